@@ -15,6 +15,8 @@ import (
 	crmapi "bronivik/bronivik_crm/internal/crmapi"
 	"bronivik/bronivik_crm/internal/db"
 	"bronivik/bronivik_crm/internal/metrics"
+	"bronivik/bronivik_crm/internal/retention"
+	"bronivik/bronivik_crm/internal/tgwebhook"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
@@ -40,8 +42,26 @@ func main() {
 		logger.Fatal().Err(err).Msg("open db error")
 	}
 	defer database.Close()
+	fields, err := cfg.Encryption.Cipher()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("field encryption keys error")
+	}
+	database.SetFieldCipher(fields)
+	if applied, err := database.Migrate(context.Background()); err != nil {
+		logger.Fatal().Err(err).Msg("migrate db error")
+	} else if applied > 0 {
+		logger.Info().Int("applied", applied).Msg("database migrations applied")
+	}
 
 	client := crmapi.NewBronivikClient(cfg.API.BaseURL, cfg.API.APIKey, cfg.API.APIExtra)
+	if cfg.API.SigningSecret != "" {
+		client.UseSigning(cfg.API.SigningSecret)
+	}
+	if t := cfg.API.TLS; t.CAFile != "" || t.CertFile != "" || t.KeyFile != "" {
+		if err := client.UseTLS(t.CAFile, t.CertFile, t.KeyFile); err != nil {
+			logger.Fatal().Err(err).Msg("api tls error")
+		}
+	}
 	var rdb *redis.Client
 	if cfg.Redis.Address != "" && cfg.API.CacheTTLSeconds > 0 {
 		rdb = redis.NewClient(&redis.Options{Addr: cfg.Redis.Address, Password: cfg.Redis.Password, DB: cfg.Redis.DB})
@@ -97,9 +117,31 @@ func main() {
 	if cfg.Backup.Enabled {
 		go startBackupLoop(ctx, database, cfg, &logger)
 	}
+	if cfg.Encryption.Enabled {
+		go startEncryptionLoop(ctx, database, cfg, &logger)
+	}
+	if cfg.Retention.Enabled {
+		policy, err := database.RetentionEngine(cfg.Retention.Policy(), cfg.Retention.Options())
+		if err != nil {
+			logger.Fatal().Err(err).Msg("retention policy error")
+		}
+		go startRetentionLoop(ctx, policy, cfg, &logger)
+	}
 
 	logger.Info().Msg("CRM bot started")
-	b.Start(ctx)
+	if cfg.Telegram.WebhookURL == "" {
+		b.Start(ctx)
+		return
+	}
+	if err := b.StartWebhook(ctx, tgwebhook.Config{
+		URL:      cfg.Telegram.WebhookURL,
+		Secret:   cfg.Telegram.WebhookSecret,
+		Listen:   cfg.Telegram.WebhookListen,
+		CertFile: cfg.Telegram.WebhookCertFile,
+		KeyFile:  cfg.Telegram.WebhookKeyFile,
+	}); err != nil {
+		logger.Fatal().Err(err).Msg("telegram webhook error")
+	}
 }
 
 func startBackupLoop(ctx context.Context, database *db.DB, cfg *config.Config, logger *zerolog.Logger) {
@@ -142,6 +184,65 @@ func startBackupLoop(ctx context.Context, database *db.DB, cfg *config.Config, l
 	}
 }
 
+// startEncryptionLoop re-encrypts client phones and names with the primary key:
+// plaintext written before encryption was enabled and values under older keys.
+func startEncryptionLoop(ctx context.Context, database *db.DB, cfg *config.Config, logger *zerolog.Logger) {
+	ticker := time.NewTicker(time.Duration(cfg.Encryption.RotationIntervalMinutes) * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		runEncryptionTask(ctx, database, cfg.Encryption.RotationBatchSize, logger)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func runEncryptionTask(ctx context.Context, database *db.DB, batchSize int, logger *zerolog.Logger) {
+	total := 0
+	for ctx.Err() == nil {
+		n, err := database.RotateFieldEncryption(ctx, batchSize)
+		total += n
+		if err != nil {
+			logger.Error().Err(err).Msg("field re-encryption failed")
+			break
+		}
+		if n == 0 {
+			break
+		}
+	}
+	if total > 0 {
+		logger.Info().Int("rows", total).Msg("fields re-encrypted")
+	}
+}
+
+// startRetentionLoop applies the personal data retention policy on schedule.
+// In dry-run mode it only logs how many rows each rule would touch.
+func startRetentionLoop(ctx context.Context, policy *retention.Engine, cfg *config.Config, logger *zerolog.Logger) {
+	ticker := time.NewTicker(time.Duration(cfg.Retention.IntervalHours) * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		report, err := policy.Run(ctx, cfg.Retention.DryRun)
+		for _, res := range report.Results {
+			if res.Rows > 0 {
+				logger.Info().Str("rule", res.Rule.String()).Time("cutoff", res.Cutoff).
+					Int64("rows", res.Rows).Bool("dry_run", report.DryRun).Msg("retention rule applied")
+			}
+		}
+		if err != nil && ctx.Err() == nil {
+			logger.Error().Err(err).Msg("retention policy failed")
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 func runBackupTask(database *db.DB, cfg *config.Config, retention time.Duration, logger *zerolog.Logger) {
 	timestamp := time.Now().Format("20060102_150405")
 	dest := filepath.Join(cfg.Backup.Path, fmt.Sprintf("bronivik_crm_%s.db", timestamp))
@@ -0,0 +1,129 @@
+// Command migrate manages versioned migrations of the bronivik_crm database.
+//
+//	migrate [-config path] [-dry-run] up
+//	migrate [-config path] [-dry-run] down [N]
+//	migrate [-config path] [-dry-run] goto VERSION
+//	migrate [-config path] status
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"bronivik/bronivik_crm/internal/config"
+	"bronivik/bronivik_crm/internal/db"
+	"bronivik/bronivik_crm/internal/migrate"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		log.Fatalf("migrate: %v", err)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	configPath := fs.String("config", os.Getenv("CRM_CONFIG_PATH"), "path to config file")
+	dryRun := fs.Bool("dry-run", false, "print SQL instead of executing it")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: migrate [-config path] [-dry-run] up | down [N] | goto VERSION | status")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("command is required")
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	database, err := db.NewDB(cfg.Database.Path)
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer database.Close()
+
+	runner, err := database.Migrator(*dryRun, out)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	var n int
+	switch cmd := fs.Arg(0); cmd {
+	case "up":
+		n, err = runner.Up(ctx)
+	case "down":
+		steps := 1
+		if fs.NArg() > 1 {
+			if steps, err = strconv.Atoi(fs.Arg(1)); err != nil || steps <= 0 {
+				return fmt.Errorf("invalid number of steps %q", fs.Arg(1))
+			}
+		}
+		n, err = runner.Down(ctx, steps)
+	case "goto":
+		if fs.NArg() < 2 {
+			return fmt.Errorf("goto requires a version")
+		}
+		version, perr := strconv.ParseInt(fs.Arg(1), 10, 64)
+		if perr != nil || version < 0 {
+			return fmt.Errorf("invalid version %q", fs.Arg(1))
+		}
+		n, err = runner.Goto(ctx, version)
+	case "status":
+		return printStatus(ctx, runner, out)
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		fmt.Fprintf(out, "-- dry run: %d migration(s) would be executed\n", n)
+		return nil
+	}
+	version, err := runner.Version(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%d migration(s) executed, schema version %d\n", n, version)
+	return nil
+}
+
+func printStatus(ctx context.Context, runner *migrate.Runner, out io.Writer) error {
+	statuses, err := runner.Status(ctx)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, st := range statuses {
+		state, appliedAt := "pending", ""
+		if st.Applied {
+			state, appliedAt = "applied", st.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		switch {
+		case st.Unknown:
+			state = "unknown (newer than binary)"
+		case st.Modified:
+			state = "modified"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", st.Version, st.Name, state, appliedAt)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return runner.Check(ctx)
+}
@@ -0,0 +1,70 @@
+// Command retention applies the personal data retention policy from the
+// retention section of the config, or reports what it would change.
+//
+//	retention [-config path] [-dry-run]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"bronivik/bronivik_crm/internal/config"
+	"bronivik/bronivik_crm/internal/db"
+	"bronivik/bronivik_crm/internal/retention"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		log.Fatalf("retention: %v", err)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("retention", flag.ContinueOnError)
+	configPath := fs.String("config", os.Getenv("CRM_CONFIG_PATH"), "path to config file")
+	dryRun := fs.Bool("dry-run", false, "report affected rows without changing data")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: retention [-config path] [-dry-run]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if len(cfg.Retention.Rules) == 0 {
+		return fmt.Errorf("no retention rules configured")
+	}
+	database, err := db.NewDB(cfg.Database.Path)
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer database.Close()
+
+	fields, err := cfg.Encryption.Cipher()
+	if err != nil {
+		return fmt.Errorf("encryption keys: %w", err)
+	}
+	database.SetFieldCipher(fields)
+	// retention_log is created by a migration, so apply pending ones first
+	if _, err := database.Migrate(context.Background()); err != nil {
+		return err
+	}
+
+	policy, err := database.RetentionEngine(cfg.Retention.Policy(), cfg.Retention.Options())
+	if err != nil {
+		return err
+	}
+	report, err := policy.Run(context.Background(), *dryRun)
+	if werr := retention.WriteReport(out, report); werr != nil && err == nil {
+		err = werr
+	}
+	return err
+}
@@ -10,8 +10,10 @@ import (
 
 	crmapi "bronivik/bronivik_crm/internal/crmapi"
 	"bronivik/bronivik_crm/internal/db"
+	"bronivik/bronivik_crm/internal/i18n"
 	"bronivik/bronivik_crm/internal/metrics"
 	"bronivik/bronivik_crm/internal/model"
+	"bronivik/bronivik_crm/internal/tgwebhook"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/google/uuid"
@@ -22,6 +24,7 @@ type telegramClient interface {
 	Send(tgbotapi.Chattable) (tgbotapi.Message, error)
 	Request(tgbotapi.Chattable) (*tgbotapi.APIResponse, error)
 	GetUpdatesChan(tgbotapi.UpdateConfig) tgbotapi.UpdatesChannel
+	MakeRequest(endpoint string, params tgbotapi.Params) (*tgbotapi.APIResponse, error)
 	SelfUser() tgbotapi.User
 }
 
@@ -41,12 +44,14 @@ func (c *realTelegramClient) GetUpdatesChan(cfg tgbotapi.UpdateConfig) tgbotapi.
 	return c.api.GetUpdatesChan(cfg)
 }
 
+func (c *realTelegramClient) MakeRequest(endpoint string, params tgbotapi.Params) (*tgbotapi.APIResponse, error) {
+	return c.api.MakeRequest(endpoint, params)
+}
+
 func (c *realTelegramClient) SelfUser() tgbotapi.User {
 	return c.api.Self
 }
 
-const itemNone = "Без аппарата"
-
 // Bot is a thin Telegram bot wrapper for CRM flow.
 type Bot struct {
 	api        *crmapi.BronivikClient
@@ -133,44 +138,70 @@ func newBot(
 	}, nil
 }
 
-// Start begins polling updates and handles commands.
-var (
-	mainMenu = tgbotapi.NewReplyKeyboard(
+func mainMenu(l *i18n.Localizer) tgbotapi.ReplyKeyboardMarkup {
+	return tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("🗓 Записаться"),
-			tgbotapi.NewKeyboardButton("📌 Мои записи"),
+			tgbotapi.NewKeyboardButton(l.T("btn_book")),
+			tgbotapi.NewKeyboardButton(l.T("btn_my_bookings")),
 		),
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("ℹ️ Помощь"),
+			tgbotapi.NewKeyboardButton(l.T("btn_help")),
 		),
 	)
+}
 
-	managerMenu = tgbotapi.NewReplyKeyboard(
+func managerMenu(l *i18n.Localizer) tgbotapi.ReplyKeyboardMarkup {
+	return tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("📥 Заявки"),
-			tgbotapi.NewKeyboardButton("➕ Создать запись"),
+			tgbotapi.NewKeyboardButton(l.T("btn_pending")),
+			tgbotapi.NewKeyboardButton(l.T("btn_manual_booking")),
 		),
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("📅 Расписание"),
-			tgbotapi.NewKeyboardButton("⚙️ Админка"),
+			tgbotapi.NewKeyboardButton(l.T("btn_schedule")),
+			tgbotapi.NewKeyboardButton(l.T("btn_admin")),
 		),
 	)
-)
+}
 
-func (b *Bot) sendMainMenu(chatID int64, userID int64) {
-	msg := tgbotapi.NewMessage(chatID, "Выберите действие:")
+func (b *Bot) sendMainMenu(ctx context.Context, chatID int64, userID int64) {
+	l := b.loc(ctx)
+	msg := tgbotapi.NewMessage(chatID, l.T("choose_action"))
 	if b.isManager(userID) {
-		msg.ReplyMarkup = managerMenu
+		msg.ReplyMarkup = managerMenu(l)
 	} else {
-		msg.ReplyMarkup = mainMenu
+		msg.ReplyMarkup = mainMenu(l)
 	}
 	_, _ = b.tg.Send(msg)
 }
 
+// Start begins polling updates and handles commands.
 func (b *Bot) Start(ctx context.Context) {
+	// getUpdates returns 409 Conflict while a webhook is set, e.g. after switching
+	// back from webhook mode. Pending updates are kept.
+	if _, err := b.tg.Request(tgbotapi.DeleteWebhookConfig{DropPendingUpdates: false}); err != nil {
+		b.logger.Error().Err(err).Msg("failed to delete telegram webhook before polling")
+	}
+
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
-	updates := b.tg.GetUpdatesChan(u)
+	b.run(ctx, b.tg.GetUpdatesChan(u))
+}
+
+// StartWebhook receives updates through the Telegram webhook instead of polling.
+func (b *Bot) StartWebhook(ctx context.Context, cfg tgwebhook.Config) error {
+	receiver, err := tgwebhook.New(cfg, b.tg, b.logger)
+	if err != nil {
+		return err
+	}
+	updates, err := receiver.Start(ctx)
+	if err != nil {
+		return err
+	}
+	b.run(ctx, updates)
+	return nil
+}
+
+func (b *Bot) run(ctx context.Context, updates tgbotapi.UpdatesChannel) {
 	b.logger.Info().Str("username", b.tg.SelfUser().UserName).Msg("CRM bot authorized")
 
 	for {
@@ -187,6 +218,10 @@ func (b *Bot) Start(ctx context.Context) {
 }
 
 func (b *Bot) handleUpdate(ctx context.Context, update *tgbotapi.Update) {
+	if from := update.SentFrom(); from != nil {
+		lang := b.userLanguage(ctx, from.ID, from.LanguageCode)
+		ctx = i18n.WithLocalizer(ctx, messages.Localizer(lang))
+	}
 	l := zerolog.Ctx(ctx)
 	if update.CallbackQuery != nil {
 		l.Debug().
@@ -211,34 +246,38 @@ func (b *Bot) handleMessage(ctx context.Context, msg *tgbotapi.Message) {
 		return
 	}
 	text := strings.TrimSpace(msg.Text)
+	l := b.loc(ctx)
 
 	// All commands take priority and interrupt any active flow
 	if strings.HasPrefix(text, "/") {
 		switch {
 		case strings.HasPrefix(text, "/start"):
 			b.state.reset(msg.From.ID)
-			b.sendMainMenu(msg.Chat.ID, msg.From.ID)
+			b.sendMainMenu(ctx, msg.Chat.ID, msg.From.ID)
+			return
+		case strings.HasPrefix(text, "/language"):
+			b.handleLanguageCommand(ctx, msg.Chat.ID)
 			return
-		case text == "🗓 Записаться":
+		case isButton(text, "btn_book"):
 			b.startBookingFlow(ctx, msg)
 			return
-		case text == "📌 Мои записи":
+		case isButton(text, "btn_my_bookings"):
 			b.handleMyBookings(ctx, msg)
 			return
-		case text == "ℹ️ Помощь" || strings.HasPrefix(text, "/help"):
-			b.reply(msg.Chat.ID, "Доступные команды: /book, /my_bookings, /help")
+		case isButton(text, "btn_help") || strings.HasPrefix(text, "/help"):
+			b.reply(msg.Chat.ID, l.T("help"))
 			return
-		case text == "📥 Заявки" && b.isManager(msg.From.ID):
+		case isButton(text, "btn_pending") && b.isManager(msg.From.ID):
 			b.handlePendingBookings(ctx, msg.Chat.ID)
 			return
-		case text == "➕ Создать запись" && b.isManager(msg.From.ID):
+		case isButton(text, "btn_manual_booking") && b.isManager(msg.From.ID):
 			b.startManualBookingFlow(ctx, msg)
 			return
-		case text == "📅 Расписание" && b.isManager(msg.From.ID):
+		case isButton(text, "btn_schedule") && b.isManager(msg.From.ID):
 			b.handleTodaySchedule(ctx, msg.Chat.ID)
 			return
-		case (text == "⚙️ Админка" || text == "/admin") && b.isManager(msg.From.ID):
-			b.sendAdminPanel(msg.Chat.ID)
+		case (isButton(text, "btn_admin") || text == "/admin") && b.isManager(msg.From.ID):
+			b.sendAdminPanel(ctx, msg.Chat.ID)
 			return
 		case strings.HasPrefix(text, "/book"):
 			b.startBookingFlow(ctx, msg)
@@ -246,18 +285,21 @@ func (b *Bot) handleMessage(ctx context.Context, msg *tgbotapi.Message) {
 		case strings.HasPrefix(text, "/my_bookings"):
 			b.handleMyBookings(ctx, msg)
 			return
+		case strings.HasPrefix(text, "/my_data"):
+			b.handleMyData(ctx, msg)
+			return
 		case strings.HasPrefix(text, "/cancel_booking"):
 			b.handleCancelBooking(ctx, msg)
 			return
 		case strings.HasPrefix(text, "/cancel"):
 			b.state.reset(msg.From.ID)
-			b.reply(msg.Chat.ID, "Операция отменена.")
-			b.sendMainMenu(msg.Chat.ID, msg.From.ID)
+			b.reply(msg.Chat.ID, l.T("operation_cancelled"))
+			b.sendMainMenu(ctx, msg.Chat.ID, msg.From.ID)
 			return
 		}
 
 		if b.isManager(msg.From.ID) {
-			if b.handleManagerCommands(msg) {
+			if b.handleManagerCommands(ctx, msg) {
 				return
 			}
 		}
@@ -270,10 +312,10 @@ func (b *Bot) handleMessage(ctx context.Context, msg *tgbotapi.Message) {
 	case stepClientName:
 		st.Draft.ClientName = text
 		st.Step = stepClientPhone
-		msg := tgbotapi.NewMessage(msg.Chat.ID, "Введите телефон клиента (в любом формате):")
+		msg := tgbotapi.NewMessage(msg.Chat.ID, l.T("enter_client_phone"))
 		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "back:name"),
+				tgbotapi.NewInlineKeyboardButtonData(l.T("btn_back"), "back:name"),
 			),
 		)
 		_, _ = b.tg.Send(msg)
@@ -281,12 +323,12 @@ func (b *Bot) handleMessage(ctx context.Context, msg *tgbotapi.Message) {
 	case stepClientPhone:
 		phone, ok := normalizeAndValidatePhone(text)
 		if !ok {
-			b.reply(msg.Chat.ID, "Некорректный телефон. Пример: +7 999 123-45-67")
+			b.reply(msg.Chat.ID, l.T("invalid_phone"))
 			return
 		}
 		st.Draft.ClientPhone = phone
 		st.Step = stepConfirm
-		b.sendConfirm(msg.Chat.ID, msg.From.ID)
+		b.sendConfirm(ctx, msg.Chat.ID, msg.From.ID)
 		return
 	}
 }
@@ -321,9 +363,13 @@ func (b *Bot) handleCallback(ctx context.Context, cq *tgbotapi.CallbackQuery) {
 	case data == "confirm":
 		b.handleConfirmCallback(ctx, chatID, userID, cq, st)
 	case data == "cancel":
-		b.handleCancelCallback(chatID, userID)
+		b.handleCancelCallback(ctx, chatID, userID)
 	case strings.HasPrefix(data, "mgr:"):
 		b.handleManagerDecision(ctx, chatID, userID, data)
+	case strings.HasPrefix(data, "set_language:"):
+		b.handleSetLanguage(ctx, cq, strings.TrimPrefix(data, "set_language:"))
+	case strings.HasPrefix(data, "erase_user"):
+		b.handleEraseUserCallback(ctx, cq, data)
 	}
 }
 
@@ -331,31 +377,36 @@ func (b *Bot) handleCabCallback(ctx context.Context, chatID int64, _userID int64
 	idStr := strings.TrimPrefix(data, "cab:")
 	cabID, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		b.reply(chatID, "Некорректный кабинет")
+		b.reply(chatID, b.loc(ctx).T("invalid_cabinet"))
 		return
 	}
 	cab, err := b.db.GetCabinet(ctx, cabID)
 	if err != nil {
-		b.reply(chatID, "Не удалось загрузить кабинет")
+		b.reply(chatID, b.loc(ctx).T("error_load_cabinet"))
 		return
 	}
 	st.Draft.CabinetID = cabID
 	st.Draft.CabinetName = cab.Name
 	st.Step = stepDate
-	b.sendCalendar(chatID)
+	b.sendCalendar(ctx, chatID)
 }
 
-func (b *Bot) handleItemCallback(_ctx context.Context, chatID int64, st *userState, data string) {
+func (b *Bot) handleItemCallback(ctx context.Context, chatID int64, st *userState, data string) {
 	name := strings.TrimPrefix(data, "item:")
 	if name == "none" {
 		name = ""
 	}
 	st.Draft.ItemName = name
 	st.Step = stepClientName
-	msg := tgbotapi.NewMessage(chatID, "Введите ФИО клиента:")
+	b.sendClientNamePrompt(ctx, chatID)
+}
+
+func (b *Bot) sendClientNamePrompt(ctx context.Context, chatID int64) {
+	l := b.loc(ctx)
+	msg := tgbotapi.NewMessage(chatID, l.T("enter_client_name"))
 	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "back:item"),
+			tgbotapi.NewInlineKeyboardButtonData(l.T("btn_back"), "back:item"),
 		),
 	)
 	_, _ = b.tg.Send(msg)
@@ -369,25 +420,19 @@ func (b *Bot) handleBack(ctx context.Context, chatID, userID int64, st *userStat
 		b.sendCabinets(ctx, chatID)
 	case "date":
 		st.Step = stepDate
-		b.sendCalendar(chatID)
+		b.sendCalendar(ctx, chatID)
 	case "time":
 		st.Step = stepTime
 		b.sendTimeSlots(ctx, chatID, userID)
 	case "duration":
 		st.Step = stepDuration
-		b.sendDurations(chatID)
+		b.sendDurations(ctx, chatID)
 	case "item":
 		st.Step = stepItem
 		b.sendItems(ctx, chatID, st.Draft.Date)
 	case "name":
 		st.Step = stepClientName
-		msg := tgbotapi.NewMessage(chatID, "Введите ФИО клиента:")
-		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
-			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "back:item"),
-			),
-		)
-		_, _ = b.tg.Send(msg)
+		b.sendClientNamePrompt(ctx, chatID)
 	default:
 		b.startBookingFlow(ctx, &tgbotapi.Message{From: &tgbotapi.User{ID: userID}, Chat: &tgbotapi.Chat{ID: chatID}})
 	}
@@ -402,21 +447,22 @@ func (b *Bot) handleDateCallback(ctx context.Context, chatID, userID int64, st *
 
 func (b *Bot) handleSlotCallback(ctx context.Context, chatID, userID int64, st *userState, data string) {
 	label := strings.TrimPrefix(data, "slot:")
+	l := b.loc(ctx)
 	if st.Draft.Date == "" {
-		b.reply(chatID, "Сначала выберите дату")
+		b.reply(chatID, l.T("choose_date_first"))
 		return
 	}
 	date, err := time.Parse("2006-01-02", st.Draft.Date)
 	if err != nil {
-		b.reply(chatID, "Некорректная дата")
+		b.reply(chatID, l.T("invalid_date"))
 		return
 	}
 	start, _, err := parseTimeLabel(date, label)
 	if err != nil {
-		b.reply(chatID, "Некорректный слот")
+		b.reply(chatID, l.T("invalid_slot"))
 		return
 	}
-	if vErr := b.validateBookingTime(start); vErr != nil {
+	if vErr := b.validateBookingTime(ctx, start); vErr != nil {
 		b.reply(chatID, vErr.Error())
 		b.sendTimeSlots(ctx, chatID, userID)
 		return
@@ -424,14 +470,15 @@ func (b *Bot) handleSlotCallback(ctx context.Context, chatID, userID int64, st *
 
 	st.Draft.StartTime = start.Format("15:04")
 	st.Step = stepDuration
-	b.sendDurations(chatID)
+	b.sendDurations(ctx, chatID)
 }
 
 func (b *Bot) handleDurationCallback(ctx context.Context, chatID int64, _userID int64, st *userState, data string) {
 	durStr := strings.TrimPrefix(data, "dur:")
+	l := b.loc(ctx)
 	dur, err := strconv.Atoi(durStr)
 	if err != nil {
-		b.reply(chatID, "Некорректная длительность")
+		b.reply(chatID, l.T("invalid_duration"))
 		return
 	}
 
@@ -443,12 +490,12 @@ func (b *Bot) handleDurationCallback(ctx context.Context, chatID int64, _userID
 	// Final availability check for the whole range
 	ok, err := b.db.CheckSlotAvailability(ctx, st.Draft.CabinetID, date, startDT, endDT)
 	if err != nil {
-		b.reply(chatID, "Не удалось проверить доступность")
+		b.reply(chatID, l.T("error_check_availability"))
 		return
 	}
 	if !ok {
-		b.reply(chatID, "Выбранный период времени занят. Выберите меньшую длительность или другое время.")
-		b.sendDurations(chatID)
+		b.reply(chatID, l.T("period_busy"))
+		b.sendDurations(ctx, chatID)
 		return
 	}
 
@@ -458,16 +505,17 @@ func (b *Bot) handleDurationCallback(ctx context.Context, chatID int64, _userID
 	b.sendItems(ctx, chatID, st.Draft.Date)
 }
 
-func (b *Bot) sendDurations(chatID int64) {
+func (b *Bot) sendDurations(ctx context.Context, chatID int64) {
+	l := b.loc(ctx)
 	durations := []int{30, 60, 90, 120, 150, 180}
 	rows := make([][]tgbotapi.InlineKeyboardButton, 0)
 	for _, d := range durations {
-		label := fmt.Sprintf("%d мин", d)
+		label := l.T("duration_minutes", d)
 		if d >= 60 {
 			if d%60 == 0 {
-				label = fmt.Sprintf("%d ч", d/60)
+				label = l.T("duration_hours", d/60)
 			} else {
-				label = fmt.Sprintf("%d ч %d мин", d/60, d%60)
+				label = l.T("duration_hours_minutes", d/60, d%60)
 			}
 		}
 		rows = append(rows, []tgbotapi.InlineKeyboardButton{
@@ -475,51 +523,52 @@ func (b *Bot) sendDurations(chatID int64) {
 		})
 	}
 	rows = append(rows, []tgbotapi.InlineKeyboardButton{
-		tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "back:time"),
+		tgbotapi.NewInlineKeyboardButtonData(l.T("btn_back"), "back:time"),
 	})
 
-	msg := tgbotapi.NewMessage(chatID, "Выберите длительность приема:")
+	msg := tgbotapi.NewMessage(chatID, l.T("choose_duration"))
 	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
 	_, _ = b.tg.Send(msg)
 }
 
 func (b *Bot) handleConfirmCallback(ctx context.Context, chatID, userID int64, cq *tgbotapi.CallbackQuery, st *userState) {
+	l := b.loc(ctx)
 	if st.Step != stepConfirm {
-		b.reply(chatID, "Сценарий устарел, начните заново: /book")
+		b.reply(chatID, l.T("flow_expired"))
 		return
 	}
 	if err := b.finalizeBooking(ctx, cq, st); err != nil {
 		if errors.Is(err, db.ErrSlotNotAvailable) {
-			b.reply(chatID, "Слот уже занят. Выберите другое время.")
+			b.reply(chatID, l.T("slot_taken"))
 			st.Step = stepTime
 			b.sendTimeSlots(ctx, chatID, userID)
 			return
 		}
 		if errors.Is(err, db.ErrItemNotAvailable) {
-			b.reply(chatID, "Аппарат недоступен на эту дату. Выберите другой аппарат или 'Без аппарата'.")
+			b.reply(chatID, l.T("item_unavailable", l.T("item_none")))
 			st.Step = stepItem
 			b.sendItems(ctx, chatID, st.Draft.Date)
 			return
 		}
 		if errors.Is(err, db.ErrSlotMisaligned) {
-			b.reply(chatID, "Слот не совпадает с расписанием. Выберите другое время.")
+			b.reply(chatID, l.T("slot_misaligned"))
 			st.Step = stepTime
 			b.sendTimeSlots(ctx, chatID, userID)
 			return
 		}
 		if errors.Is(err, errActiveLimit) {
-			b.reply(chatID, "Достигнут лимит активных бронирований. Отмените существующее или свяжитесь с менеджером.")
+			b.reply(chatID, l.T("active_limit"))
 			return
 		}
-		b.reply(chatID, "Не удалось создать бронирование")
+		b.reply(chatID, l.T("error_create_booking"))
 		return
 	}
 	b.state.reset(userID)
 }
 
-func (b *Bot) handleCancelCallback(chatID, userID int64) {
+func (b *Bot) handleCancelCallback(ctx context.Context, chatID, userID int64) {
 	b.state.reset(userID)
-	b.reply(chatID, "Ок, отменено. /book чтобы начать заново")
+	b.reply(chatID, b.loc(ctx).T("cancelled_restart"))
 }
 
 func (b *Bot) handleManagerDecision(ctx context.Context, chatID, userID int64, data string) {
@@ -535,7 +584,7 @@ func (b *Bot) handleManagerDecision(ctx context.Context, chatID, userID int64, d
 		}
 		_ = b.db.UpdateHourlyBookingStatus(ctx, bid, "approved", "")
 		metrics.IncManagerDecision("approved")
-		b.reply(chatID, fmt.Sprintf("Бронирование #%d подтверждено", bid))
+		b.reply(chatID, b.loc(ctx).T("booking_approved", bid))
 		b.notifyBookingStatus(ctx, bid, "approved")
 	case strings.HasPrefix(data, "mgr:reject:"):
 		idStr := strings.TrimPrefix(data, "mgr:reject:")
@@ -545,50 +594,58 @@ func (b *Bot) handleManagerDecision(ctx context.Context, chatID, userID int64, d
 		}
 		_ = b.db.UpdateHourlyBookingStatus(ctx, bid, "rejected", "")
 		metrics.IncManagerDecision("rejected")
-		b.reply(chatID, fmt.Sprintf("Бронирование #%d отклонено", bid))
+		b.reply(chatID, b.loc(ctx).T("booking_rejected", bid))
 		b.notifyBookingStatus(ctx, bid, "rejected")
 	}
 }
 
-func (b *Bot) validateBookingTime(start time.Time) error {
+func (b *Bot) validateBookingTime(ctx context.Context, start time.Time) error {
+	l := b.loc(ctx)
 	now := time.Now()
 	if start.Before(now.Add(b.rules.MinAdvance)) {
 		minMins := int(b.rules.MinAdvance.Minutes())
-		return fmt.Errorf("Слишком близко по времени. Минимум за %d минут до начала.", minMins)
+		return errors.New(l.T("too_soon", l.N("minutes", minMins, minMins)))
 	}
 	if start.After(now.Add(b.rules.MaxAdvance)) {
 		days := int(b.rules.MaxAdvance.Hours() / 24)
 		if days <= 0 {
 			days = 30
 		}
-		return fmt.Errorf("Слишком далеко по времени. Доступно бронирование максимум на %d дней вперед.", days)
+		return errors.New(l.T("too_far", l.N("days", days, days)))
 	}
 	return nil
 }
 
-func (b *Bot) handleManagerCommands(msg *tgbotapi.Message) bool {
+func (b *Bot) handleManagerCommands(ctx context.Context, msg *tgbotapi.Message) bool {
 	text := msg.Text
+	l := b.loc(ctx)
 	switch {
 	case strings.HasPrefix(text, "/add_cabinet"):
-		b.reply(msg.Chat.ID, "(stub) Добавить кабинет")
+		b.reply(msg.Chat.ID, l.T("stub_add_cabinet"))
 	case strings.HasPrefix(text, "/list_cabinets"):
-		b.reply(msg.Chat.ID, "(stub) Список кабинетов")
+		b.reply(msg.Chat.ID, l.T("stub_list_cabinets"))
 	case strings.HasPrefix(text, "/cabinet_schedule"):
-		b.reply(msg.Chat.ID, "(stub) Расписание кабинета")
+		b.reply(msg.Chat.ID, l.T("stub_cabinet_schedule"))
 	case strings.HasPrefix(text, "/set_schedule"):
-		b.reply(msg.Chat.ID, "(stub) Установить расписание")
+		b.reply(msg.Chat.ID, l.T("stub_set_schedule"))
 	case strings.HasPrefix(text, "/close_cabinet"):
-		b.reply(msg.Chat.ID, "(stub) Закрыть кабинет на дату")
+		b.reply(msg.Chat.ID, l.T("stub_close_cabinet"))
 	case strings.HasPrefix(text, "/pending"):
-		b.reply(msg.Chat.ID, "(stub) Ожидающие подтверждения")
+		b.reply(msg.Chat.ID, l.T("stub_pending"))
 	case strings.HasPrefix(text, "/approve"):
-		b.reply(msg.Chat.ID, "(stub) Подтвердить бронирование")
+		b.reply(msg.Chat.ID, l.T("stub_approve"))
 	case strings.HasPrefix(text, "/reject"):
-		b.reply(msg.Chat.ID, "(stub) Отклонить бронирование")
+		b.reply(msg.Chat.ID, l.T("stub_reject"))
 	case strings.HasPrefix(text, "/today_schedule"):
-		b.reply(msg.Chat.ID, "(stub) Расписание на сегодня")
+		b.reply(msg.Chat.ID, l.T("stub_today_schedule"))
 	case strings.HasPrefix(text, "/tomorrow_schedule"):
-		b.reply(msg.Chat.ID, "(stub) Расписание на завтра")
+		b.reply(msg.Chat.ID, l.T("stub_tomorrow_schedule"))
+	case strings.HasPrefix(text, "/user_data"):
+		b.handleUserData(ctx, msg)
+	case strings.HasPrefix(text, "/erase_user"):
+		b.handleEraseUser(ctx, msg)
+	case strings.HasPrefix(text, "/find_phone"):
+		b.handleFindPhone(ctx, msg)
 	default:
 		return false
 	}
@@ -599,37 +656,38 @@ func (b *Bot) handleMyBookings(ctx context.Context, msg *tgbotapi.Message) {
 	if msg == nil || msg.From == nil {
 		return
 	}
+	l := b.loc(ctx)
 	u, err := b.db.GetOrCreateUserByTelegramID(ctx, msg.From.ID, msg.From.UserName, msg.From.FirstName, msg.From.LastName, "")
 	if err != nil {
-		b.reply(msg.Chat.ID, "Не удалось загрузить пользователя")
+		b.reply(msg.Chat.ID, l.T("error_load_user"))
 		return
 	}
 
 	bookings, err := b.db.ListUserBookings(ctx, u.ID, 10, false)
 	if err != nil {
-		b.reply(msg.Chat.ID, "Не удалось получить бронирования")
+		b.reply(msg.Chat.ID, l.T("error_list_bookings"))
 		return
 	}
 	if len(bookings) == 0 {
-		b.reply(msg.Chat.ID, "У вас нет активных бронирований")
+		b.reply(msg.Chat.ID, l.T("no_active_bookings"))
 		return
 	}
 
 	var sb strings.Builder
-	sb.WriteString("Ваши бронирования:\n")
+	sb.WriteString(l.T("your_bookings") + "\n")
 	for i := range bookings {
 		bk := &bookings[i]
-		cabName := fmt.Sprintf("Кабинет #%d", bk.CabinetID)
+		cabName := l.T("cabinet_fallback", bk.CabinetID)
 		if cab, err := b.db.GetCabinet(ctx, bk.CabinetID); err == nil && cab != nil {
 			cabName = cab.Name
 		}
 		item := bk.ItemName
 		if item == "" {
-			item = itemNone
+			item = l.T("item_none")
 		}
 		line := fmt.Sprintf("#%d %s %s-%s | %s | %s | %s\n",
 			bk.ID,
-			bk.StartTime.Format("02.01"),
+			l.DayMonth(bk.StartTime),
 			bk.StartTime.Format("15:04"),
 			bk.EndTime.Format("15:04"),
 			cabName,
@@ -645,37 +703,38 @@ func (b *Bot) handleCancelBooking(ctx context.Context, msg *tgbotapi.Message) {
 	if msg == nil || msg.From == nil {
 		return
 	}
+	l := b.loc(ctx)
 	parts := strings.Fields(msg.Text)
 	if len(parts) < 2 {
-		b.reply(msg.Chat.ID, "Формат: /cancel_booking <id>")
+		b.reply(msg.Chat.ID, l.T("cancel_booking_usage"))
 		return
 	}
 	id, err := strconv.ParseInt(parts[1], 10, 64)
 	if err != nil || id <= 0 {
-		b.reply(msg.Chat.ID, "Некорректный id бронирования")
+		b.reply(msg.Chat.ID, l.T("invalid_booking_id"))
 		return
 	}
 
 	u, err := b.db.GetOrCreateUserByTelegramID(ctx, msg.From.ID, msg.From.UserName, msg.From.FirstName, msg.From.LastName, "")
 	if err != nil {
-		b.reply(msg.Chat.ID, "Не удалось загрузить пользователя")
+		b.reply(msg.Chat.ID, l.T("error_load_user"))
 		return
 	}
 
 	switch err := b.db.CancelUserBooking(ctx, id, u.ID); {
 	case err == nil:
-		b.reply(msg.Chat.ID, fmt.Sprintf("Бронирование #%d отменено", id))
+		b.reply(msg.Chat.ID, l.T("booking_cancelled", id))
 		metrics.IncBookingCanceled()
 	case errors.Is(err, db.ErrBookingNotFound):
-		b.reply(msg.Chat.ID, "Бронирование не найдено")
+		b.reply(msg.Chat.ID, l.T("booking_not_found"))
 	case errors.Is(err, db.ErrBookingForbidden):
-		b.reply(msg.Chat.ID, "Нельзя отменить чужое бронирование")
+		b.reply(msg.Chat.ID, l.T("booking_forbidden"))
 	case errors.Is(err, db.ErrBookingTooLate):
-		b.reply(msg.Chat.ID, "Нельзя отменить уже начавшееся бронирование")
+		b.reply(msg.Chat.ID, l.T("booking_too_late"))
 	case errors.Is(err, db.ErrBookingFinalized):
-		b.reply(msg.Chat.ID, "Бронирование уже завершено или отменено")
+		b.reply(msg.Chat.ID, l.T("booking_finalized"))
 	default:
-		b.reply(msg.Chat.ID, "Не удалось отменить бронирование")
+		b.reply(msg.Chat.ID, l.T("error_cancel_booking"))
 	}
 }
 
@@ -685,19 +744,20 @@ func (b *Bot) reply(chatID int64, text string) {
 }
 
 func (b *Bot) handlePendingBookings(ctx context.Context, chatID int64) {
+	l := b.loc(ctx)
 	bookings, err := b.db.ListPendingBookings(ctx)
 	if err != nil {
-		b.reply(chatID, "Ошибка получения заявок")
+		b.reply(chatID, l.T("error_pending"))
 		return
 	}
 	if len(bookings) == 0 {
-		b.reply(chatID, "Нет новых заявок")
+		b.reply(chatID, l.T("no_pending"))
 		return
 	}
 
 	for _, bk := range bookings {
-		text := b.formatBookingInfo(bk)
-		b.sendManagerDecisionMessage(chatID, bk.ID, text)
+		text := b.formatBookingInfo(l, bk)
+		b.sendManagerDecisionMessage(l, chatID, bk.ID, text)
 	}
 }
 
@@ -710,19 +770,20 @@ func (b *Bot) startManualBookingFlow(ctx context.Context, msg *tgbotapi.Message)
 }
 
 func (b *Bot) handleTodaySchedule(ctx context.Context, chatID int64) {
+	l := b.loc(ctx)
 	now := time.Now().Format("2006-01-02")
 	bookings, err := b.db.ListBookingsByDate(ctx, now)
 	if err != nil {
-		b.reply(chatID, "Ошибка получения расписания")
+		b.reply(chatID, l.T("error_schedule"))
 		return
 	}
 	if len(bookings) == 0 {
-		b.reply(chatID, "Сегодня ( "+now+" ) записей нет")
+		b.reply(chatID, l.T("schedule_empty", now))
 		return
 	}
 
 	var sb strings.Builder
-	sb.WriteString("🗓 Расписание на " + now + ":\n\n")
+	sb.WriteString(l.T("schedule_title", now) + "\n\n")
 	for _, bk := range bookings {
 		timeRange := fmt.Sprintf("%s-%s", bk.StartTime.Format("15:04"), bk.EndTime.Format("15:04"))
 		sb.WriteString(fmt.Sprintf("🔹 %s | %s | %s | %s\n", timeRange, bk.CabinetName, bk.ClientName, bk.Status))
@@ -730,55 +791,47 @@ func (b *Bot) handleTodaySchedule(ctx context.Context, chatID int64) {
 	b.reply(chatID, sb.String())
 }
 
-func (b *Bot) sendAdminPanel(chatID int64) {
-	text := "⚙️ Панель управления (Admin Panel)\n\n" +
-		"/add_cabinet - Добавить кабинет\n" +
-		"/list_cabinets - Список всех кабинетов\n" +
-		"/cabinet_schedule <id> - Просмотр расписания\n" +
-		"/close_cabinet <id> <date> - Закрыть кабинет\n"
-	b.reply(chatID, text)
+func (b *Bot) sendAdminPanel(ctx context.Context, chatID int64) {
+	b.reply(chatID, b.loc(ctx).T("admin_panel")+"\n")
 }
 
-func (b *Bot) formatBookingInfo(bk model.HourlyBooking) string {
+func (b *Bot) formatBookingInfo(l *i18n.Localizer, bk model.HourlyBooking) string {
 	item := bk.ItemName
 	if item == "" {
-		item = "Без аппарата"
-	}
-	return fmt.Sprintf(
-		"🆕 ЗАЯВКА #%d\n"+
-			"🚪 Кабинет: %s\n"+
-			"📅 Дата: %s\n"+
-			"⏱ Время: %s\n"+
-			"🛠 Аппарат: %s\n"+
-			"👤 Клиент: %s\n"+
-			"📞 Телефон: %s\n"+
-			"💬 Коммент: %s",
+		item = l.T("item_none")
+	}
+	return l.T("booking_card",
 		bk.ID, bk.CabinetName, bk.StartTime.Format("2006-01-02"),
 		fmt.Sprintf("%s-%s", bk.StartTime.Format("15:04"), bk.EndTime.Format("15:04")),
 		item, bk.ClientName, bk.ClientPhone, bk.Comment,
 	)
 }
 
-func (b *Bot) sendManagerDecisionMessage(chatID int64, bookingID int64, text string) {
+func (b *Bot) sendManagerDecisionMessage(l *i18n.Localizer, chatID int64, bookingID int64, text string) {
 	msg := tgbotapi.NewMessage(chatID, text)
-	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+	msg.ReplyMarkup = managerDecisionKeyboard(l, bookingID)
+	_, _ = b.tg.Send(msg)
+}
+
+func managerDecisionKeyboard(l *i18n.Localizer, bookingID int64) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("✅ Подтвердить", fmt.Sprintf("mgr:approve:%d", bookingID)),
-			tgbotapi.NewInlineKeyboardButtonData("❌ Отклонить", fmt.Sprintf("mgr:reject:%d", bookingID)),
+			tgbotapi.NewInlineKeyboardButtonData(l.T("btn_approve"), fmt.Sprintf("mgr:approve:%d", bookingID)),
+			tgbotapi.NewInlineKeyboardButtonData(l.T("btn_reject"), fmt.Sprintf("mgr:reject:%d", bookingID)),
 		),
 	)
-	_, _ = b.tg.Send(msg)
 }
 
 func (b *Bot) sendCabinets(ctx context.Context, chatID int64) {
+	l := b.loc(ctx)
 	cabs, err := b.db.ListActiveCabinets(ctx)
 	if err != nil {
-		b.reply(chatID, "Не удалось загрузить кабинеты")
+		b.reply(chatID, l.T("error_load_cabinets"))
 		return
 	}
 
 	if len(cabs) == 0 {
-		b.reply(chatID, "Нет доступных кабинетов")
+		b.reply(chatID, l.T("no_cabinets"))
 		return
 	}
 
@@ -789,7 +842,7 @@ func (b *Bot) sendCabinets(ctx context.Context, chatID int64) {
 		))
 	}
 
-	msg := tgbotapi.NewMessage(chatID, "Выберите кабинет:")
+	msg := tgbotapi.NewMessage(chatID, l.T("choose_cabinet"))
 	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
 	_, _ = b.tg.Send(msg)
 }
@@ -815,8 +868,9 @@ func (b *Bot) startBookingFlow(ctx context.Context, msg *tgbotapi.Message) {
 }
 
 func (b *Bot) sendItems(ctx context.Context, chatID int64, dateStr string) {
+	l := b.loc(ctx)
 	rows := [][]tgbotapi.InlineKeyboardButton{
-		{tgbotapi.NewInlineKeyboardButtonData(itemNone, "item:none")},
+		{tgbotapi.NewInlineKeyboardButtonData(l.T("item_none"), "item:none")},
 	}
 	if b.apiEnabled && b.api != nil {
 		apiCtx := ctx
@@ -833,9 +887,9 @@ func (b *Bot) sendItems(ctx context.Context, chatID int64, dateStr string) {
 				status := ""
 				if availErr == nil && avail != nil {
 					if avail.Available {
-						status = "✅ Свободен"
+						status = l.T("item_free")
 					} else {
-						status = "❌ Занят"
+						status = l.T("item_busy")
 					}
 				}
 
@@ -853,39 +907,41 @@ func (b *Bot) sendItems(ctx context.Context, chatID int64, dateStr string) {
 		}
 	}
 	rows = append(rows, []tgbotapi.InlineKeyboardButton{
-		tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "back:duration"),
+		tgbotapi.NewInlineKeyboardButtonData(l.T("btn_back"), "back:duration"),
 	})
 
-	out := tgbotapi.NewMessage(chatID, fmt.Sprintf("Выберите аппарат на %s:", dateStr))
+	out := tgbotapi.NewMessage(chatID, l.T("choose_item", dateStr))
 	if b.apiEnabled && b.api != nil && len(rows) <= 2 { // none + back
-		out.Text = "⚠️ Внешняя система недоступна, список аппаратов может быть неполным.\n\n" + out.Text
+		out.Text = l.T("items_api_unavailable") + "\n\n" + out.Text
 	}
 	out.ReplyMarkup = tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows}
 	_, _ = b.tg.Send(out)
 }
 
-func (b *Bot) sendCalendar(chatID int64) {
+func (b *Bot) sendCalendar(ctx context.Context, chatID int64) {
+	l := b.loc(ctx)
 	now := time.Now()
-	markup := GenerateCalendarKeyboard(now.Year(), int(now.Month()), nil)
-	out := tgbotapi.NewMessage(chatID, "Выберите дату:")
+	markup := GenerateCalendarKeyboard(l, now.Year(), int(now.Month()), nil)
+	out := tgbotapi.NewMessage(chatID, l.T("choose_date"))
 	out.ReplyMarkup = markup
 	_, _ = b.tg.Send(out)
 }
 
 func (b *Bot) sendTimeSlots(ctx context.Context, chatID, userID int64) {
+	l := b.loc(ctx)
 	st := b.state.get(userID)
 	if st.Draft.CabinetID == 0 || st.Draft.Date == "" {
-		b.reply(chatID, "Сначала выберите кабинет и дату: /book")
+		b.reply(chatID, l.T("choose_cabinet_and_date"))
 		return
 	}
 	date, err := time.Parse("2006-01-02", st.Draft.Date)
 	if err != nil {
-		b.reply(chatID, "Некорректная дата")
+		b.reply(chatID, l.T("invalid_date"))
 		return
 	}
 	slots, err := b.db.GetAvailableSlots(ctx, st.Draft.CabinetID, date)
 	if err != nil {
-		b.reply(chatID, "Не удалось получить слоты")
+		b.reply(chatID, l.T("error_slots"))
 		return
 	}
 
@@ -899,14 +955,14 @@ func (b *Bot) sendTimeSlots(ctx context.Context, chatID, userID int64) {
 		ui = append(ui, TimeSlot{Label: label, CallbackData: fmt.Sprintf("slot:%s", label), Available: s.Available})
 	}
 
-	header := "Выберите время:"
+	header := l.T("choose_time")
 	if !anyAvailable {
 		nextDate := date.AddDate(0, 0, 1)
 		nextSlots, _ := b.db.GetAvailableSlots(ctx, st.Draft.CabinetID, nextDate)
 
 		var sb strings.Builder
-		sb.WriteString("⚠️ Кабинет полностью занят на выбранную дату.\n\n")
-		sb.WriteString(fmt.Sprintf("Расписание на выбранную дату (%s):\n", date.Format("02.01.2006")))
+		sb.WriteString(l.T("cabinet_fully_booked") + "\n\n")
+		sb.WriteString(l.T("schedule_selected_date", l.Date(date)) + "\n")
 		for _, s := range slots {
 			status := "✅"
 			if !s.Available {
@@ -915,9 +971,9 @@ func (b *Bot) sendTimeSlots(ctx context.Context, chatID, userID int64) {
 			sb.WriteString(fmt.Sprintf("%s %s-%s\n", status, s.StartTime, s.EndTime))
 		}
 
-		sb.WriteString(fmt.Sprintf("\nРасписание на следующий день (%s):\n", nextDate.Format("02.01.2006")))
+		sb.WriteString("\n" + l.T("schedule_next_day", l.Date(nextDate)) + "\n")
 		if len(nextSlots) == 0 {
-			sb.WriteString("Нет данных или выходной.")
+			sb.WriteString(l.T("schedule_no_data"))
 		} else {
 			for _, s := range nextSlots {
 				status := "✅"
@@ -927,31 +983,32 @@ func (b *Bot) sendTimeSlots(ctx context.Context, chatID, userID int64) {
 				sb.WriteString(fmt.Sprintf("%s %s-%s\n", status, s.StartTime, s.EndTime))
 			}
 		}
-		header = sb.String() + "\nВсе равно выберите время (для записи в очередь или другое):"
+		header = sb.String() + "\n" + l.T("choose_time_anyway")
 	}
 
 	out := tgbotapi.NewMessage(chatID, header)
-	out.ReplyMarkup = GenerateTimeSlotsKeyboard(ui, st.Draft.Date)
+	out.ReplyMarkup = GenerateTimeSlotsKeyboard(l, ui, st.Draft.Date)
 	_, _ = b.tg.Send(out)
 }
 
-func (b *Bot) sendConfirm(chatID, userID int64) {
+func (b *Bot) sendConfirm(ctx context.Context, chatID, userID int64) {
+	l := b.loc(ctx)
 	st := b.state.get(userID)
 	item := st.Draft.ItemName
 	if item == "" {
-		item = itemNone
+		item = l.T("item_none")
 	}
-	text := fmt.Sprintf("Проверьте данные:\n\nКабинет: %s\nАппарат: %s\nДата: %s\nВремя: %s\nКлиент: %s\nТелефон: %s\n\nПодтвердить?",
+	text := l.T("confirm_booking",
 		st.Draft.CabinetName, item, st.Draft.Date, st.Draft.TimeLabel, st.Draft.ClientName, st.Draft.ClientPhone)
 
 	if st.APIUnreachable {
-		text = "⚠️ ВНИМАНИЕ: Внешняя система (аппараты) не отвечает. Выбранный аппарат не подтверждён автоматически — менеджер уточнит и подтвердит запись.\n\n" + text
+		text = l.T("api_unreachable_warning") + "\n\n" + text
 	}
 
 	rows := [][]tgbotapi.InlineKeyboardButton{
 		{
-			tgbotapi.NewInlineKeyboardButtonData("✅ Подтвердить", "confirm"),
-			tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "cancel"),
+			tgbotapi.NewInlineKeyboardButtonData(l.T("btn_confirm"), "confirm"),
+			tgbotapi.NewInlineKeyboardButtonData(l.T("btn_cancel"), "cancel"),
 		},
 	}
 	out := tgbotapi.NewMessage(chatID, text)
@@ -977,7 +1034,7 @@ func (b *Bot) finalizeBooking(ctx context.Context, cq *tgbotapi.CallbackQuery, s
 	if start, end, err = parseTimeLabel(date, st.Draft.TimeLabel); err != nil {
 		return err
 	}
-	if err = b.validateBookingTime(start); err != nil {
+	if err = b.validateBookingTime(ctx, start); err != nil {
 		return err
 	}
 	if b.rules.MaxActivePerUser > 0 {
@@ -1019,15 +1076,15 @@ func (b *Bot) finalizeBooking(ctx context.Context, cq *tgbotapi.CallbackQuery, s
 	}
 	metrics.IncBookingCreated(bk.Status)
 
+	l := b.loc(ctx)
 	item := bk.ItemName
 	if item == "" {
-		item = "Без аппарата"
+		item = l.T("item_none")
 	}
-	msg := fmt.Sprintf("Заявка #%d создана. Статус: %s. Кабинет: %s, %s %s, %s",
-		bk.ID, bk.Status, st.Draft.CabinetName, st.Draft.Date, st.Draft.TimeLabel, item)
+	msg := l.T("booking_created", bk.ID, bk.Status, st.Draft.CabinetName, st.Draft.Date, st.Draft.TimeLabel, item)
 	b.reply(cq.Message.Chat.ID, msg)
 	if !st.IsManual {
-		b.notifyManagersNewBooking(bk.ID, st.Draft.CabinetName, item, st.Draft.Date, st.Draft.TimeLabel, st.Draft.ClientName, st.Draft.ClientPhone)
+		b.notifyManagersNewBooking(ctx, bk.ID, st.Draft.CabinetName, bk.ItemName, st.Draft.Date, st.Draft.TimeLabel, st.Draft.ClientName, st.Draft.ClientPhone)
 	}
 	return nil
 }
@@ -1088,18 +1145,17 @@ func filterDigits(s string) string {
 	return b.String()
 }
 
-func (b *Bot) notifyManagersNewBooking(id int64, cabinet, item, date, timeLabel, clientName, clientPhone string) {
-	rows := [][]tgbotapi.InlineKeyboardButton{
-		{
-			tgbotapi.NewInlineKeyboardButtonData("✅ Approve", fmt.Sprintf("mgr:approve:%d", id)),
-			tgbotapi.NewInlineKeyboardButtonData("❌ Reject", fmt.Sprintf("mgr:reject:%d", id)),
-		},
-	}
-	text := fmt.Sprintf("Новая заявка #%d\nКабинет: %s\nАппарат: %s\nДата: %s\nВремя: %s\nКлиент: %s\nТелефон: %s",
-		id, cabinet, item, date, timeLabel, clientName, clientPhone)
+// notifyManagersNewBooking sends the request to every manager in their own
+// language; an empty item means the booking has no device.
+func (b *Bot) notifyManagersNewBooking(ctx context.Context, id int64, cabinet, item, date, timeLabel, clientName, clientPhone string) {
 	for mgrID := range b.managers {
-		msg := tgbotapi.NewMessage(mgrID, text)
-		msg.ReplyMarkup = tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows}
+		l := b.locFor(ctx, mgrID)
+		itemLabel := item
+		if itemLabel == "" {
+			itemLabel = l.T("item_none")
+		}
+		msg := tgbotapi.NewMessage(mgrID, l.T("manager_new_booking", id, cabinet, itemLabel, date, timeLabel, clientName, clientPhone))
+		msg.ReplyMarkup = managerDecisionKeyboard(l, id)
 		_, _ = b.tg.Send(msg)
 	}
 }
@@ -1114,6 +1170,6 @@ func (b *Bot) notifyBookingStatus(ctx context.Context, bookingID int64, status s
 	if err := row.Scan(&telegramID); err != nil {
 		return
 	}
-	msg := tgbotapi.NewMessage(telegramID, fmt.Sprintf("Статус заявки #%d: %s", bookingID, status))
+	msg := tgbotapi.NewMessage(telegramID, b.locFor(ctx, telegramID).T("booking_status", bookingID, status))
 	_, _ = b.tg.Send(msg)
 }
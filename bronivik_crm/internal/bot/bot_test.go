@@ -1,9 +1,13 @@
 package bot
 
 import (
+	"context"
+	"io"
 	"testing"
 	"time"
 
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -57,19 +61,63 @@ func TestValidateBookingTime(t *testing.T) {
 	now := time.Now()
 
 	t.Run("TooSoon", func(t *testing.T) {
-		err := b.validateBookingTime(now.Add(30 * time.Minute))
+		err := b.validateBookingTime(context.Background(), now.Add(30*time.Minute))
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "Слишком близко")
 	})
 
 	t.Run("TooFar", func(t *testing.T) {
-		err := b.validateBookingTime(now.Add(48 * time.Hour))
+		err := b.validateBookingTime(context.Background(), now.Add(48*time.Hour))
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "Слишком далеко")
 	})
 
 	t.Run("OK", func(t *testing.T) {
-		err := b.validateBookingTime(now.Add(2 * time.Hour))
+		err := b.validateBookingTime(context.Background(), now.Add(2*time.Hour))
 		assert.NoError(t, err)
 	})
 }
+
+// pollingTelegramClient records the Bot API calls made when polling starts.
+type pollingTelegramClient struct {
+	calls []string
+}
+
+func (c *pollingTelegramClient) Send(tgbotapi.Chattable) (tgbotapi.Message, error) {
+	return tgbotapi.Message{}, nil
+}
+
+func (c *pollingTelegramClient) Request(msg tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	if webhook, ok := msg.(tgbotapi.DeleteWebhookConfig); ok && !webhook.DropPendingUpdates {
+		c.calls = append(c.calls, "deleteWebhook")
+	}
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+func (c *pollingTelegramClient) GetUpdatesChan(tgbotapi.UpdateConfig) tgbotapi.UpdatesChannel {
+	c.calls = append(c.calls, "getUpdates")
+	return make(chan tgbotapi.Update)
+}
+
+func (c *pollingTelegramClient) MakeRequest(string, tgbotapi.Params) (*tgbotapi.APIResponse, error) {
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+func (c *pollingTelegramClient) SelfUser() tgbotapi.User {
+	return tgbotapi.User{UserName: "crm_bot"}
+}
+
+func TestStartDeletesWebhookBeforePolling(t *testing.T) {
+	tg := &pollingTelegramClient{}
+	logger := zerolog.New(io.Discard)
+	b, err := NewWithTelegramClient(tg, nil, false, nil, nil, &BookingRules{}, &logger)
+	if err != nil {
+		t.Fatalf("NewWithTelegramClient: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	b.Start(ctx)
+
+	assert.Equal(t, []string{"deleteWebhook", "getUpdates"}, tg.calls)
+}
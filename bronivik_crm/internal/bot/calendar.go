@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"time"
 
+	"bronivik/bronivik_crm/internal/i18n"
+
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
@@ -14,9 +16,10 @@ type TimeSlot struct {
 	Available    bool
 }
 
-// GenerateCalendarKeyboard builds an inline keyboard for a given month.
+// GenerateCalendarKeyboard builds an inline keyboard for a given month with
+// month and weekday names in the localizer's language.
 // availableDates keys are YYYY-MM-DD strings.
-func GenerateCalendarKeyboard(year, month int, availableDates map[string]bool) tgbotapi.InlineKeyboardMarkup {
+func GenerateCalendarKeyboard(l *i18n.Localizer, year, month int, availableDates map[string]bool) tgbotapi.InlineKeyboardMarkup {
 	firstDay := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
 	weekdayOffset := int(firstDay.Weekday())
 	if weekdayOffset == 0 {
@@ -26,21 +29,16 @@ func GenerateCalendarKeyboard(year, month int, availableDates map[string]bool) t
 
 	rows := make([][]tgbotapi.InlineKeyboardButton, 0)
 	// Month header
-	monthName := time.Month(month).String() // TODO: Russian month names
 	rows = append(rows, []tgbotapi.InlineKeyboardButton{
-		tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%s %d", monthName, year), "noop"),
+		tgbotapi.NewInlineKeyboardButtonData(l.MonthYear(firstDay), "noop"),
 	})
 
 	// Weekday header
-	rows = append(rows, []tgbotapi.InlineKeyboardButton{
-		tgbotapi.NewInlineKeyboardButtonData("Пн", "noop"),
-		tgbotapi.NewInlineKeyboardButtonData("Вт", "noop"),
-		tgbotapi.NewInlineKeyboardButtonData("Ср", "noop"),
-		tgbotapi.NewInlineKeyboardButtonData("Чт", "noop"),
-		tgbotapi.NewInlineKeyboardButtonData("Пт", "noop"),
-		tgbotapi.NewInlineKeyboardButtonData("Сб", "noop"),
-		tgbotapi.NewInlineKeyboardButtonData("Вс", "noop"),
-	})
+	weekdays := make([]tgbotapi.InlineKeyboardButton, 0, 7)
+	for i := 0; i < 7; i++ {
+		weekdays = append(weekdays, tgbotapi.NewInlineKeyboardButtonData(l.Weekday(time.Weekday((i+1)%7)), "noop"))
+	}
+	rows = append(rows, weekdays)
 
 	day := 1
 	for day <= daysInMonth {
@@ -68,14 +66,14 @@ func GenerateCalendarKeyboard(year, month int, availableDates map[string]bool) t
 
 	// Add back button
 	rows = append(rows, []tgbotapi.InlineKeyboardButton{
-		tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "back:cab"),
+		tgbotapi.NewInlineKeyboardButtonData(l.T("btn_back"), "back:cab"),
 	})
 
 	return tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows}
 }
 
 // GenerateTimeSlotsKeyboard builds an inline keyboard for time slots of a day.
-func GenerateTimeSlotsKeyboard(slots []TimeSlot, selectedDate string) tgbotapi.InlineKeyboardMarkup {
+func GenerateTimeSlotsKeyboard(l *i18n.Localizer, slots []TimeSlot, selectedDate string) tgbotapi.InlineKeyboardMarkup {
 	rows := make([][]tgbotapi.InlineKeyboardButton, 0)
 	// Group slots into rows of 3
 	var currentRow []tgbotapi.InlineKeyboardButton
@@ -100,7 +98,7 @@ func GenerateTimeSlotsKeyboard(slots []TimeSlot, selectedDate string) tgbotapi.I
 
 	// Add back button
 	rows = append(rows, []tgbotapi.InlineKeyboardButton{
-		tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "back:date"),
+		tgbotapi.NewInlineKeyboardButtonData(l.T("btn_back"), "back:date"),
 	})
 	return tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows}
 }
@@ -0,0 +1,52 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
+)
+
+// handleFindPhone lists a client's bookings by phone: /find_phone <phone>.
+// Phones are stored encrypted, so the lookup goes through the blind index.
+func (b *Bot) handleFindPhone(ctx context.Context, msg *tgbotapi.Message) {
+	l := b.loc(ctx)
+	phone := strings.TrimSpace(strings.TrimPrefix(msg.Text, "/find_phone"))
+	if phone == "" {
+		b.reply(msg.Chat.ID, l.T("find_phone_usage"))
+		return
+	}
+
+	bookings, err := b.db.ListBookingsByPhone(ctx, phone, 20)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("failed to find bookings by phone")
+		b.reply(msg.Chat.ID, l.T("find_phone_error"))
+		return
+	}
+	if len(bookings) == 0 {
+		b.reply(msg.Chat.ID, l.T("find_phone_empty"))
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(l.T("find_phone_title", phone) + "\n")
+	for i := range bookings {
+		bk := &bookings[i]
+		cabName := l.T("cabinet_fallback", bk.CabinetID)
+		if cab, err := b.db.GetCabinet(ctx, bk.CabinetID); err == nil && cab != nil {
+			cabName = cab.Name
+		}
+		sb.WriteString(fmt.Sprintf("#%d %s %s-%s | %s | %s | %s\n",
+			bk.ID,
+			l.DayMonth(bk.StartTime),
+			bk.StartTime.Format("15:04"),
+			bk.EndTime.Format("15:04"),
+			cabName,
+			bk.ClientName,
+			bk.Status,
+		))
+	}
+	b.reply(msg.Chat.ID, sb.String())
+}
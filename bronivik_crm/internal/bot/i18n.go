@@ -0,0 +1,104 @@
+package bot
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+
+	"bronivik/bronivik_crm/internal/i18n"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
+)
+
+// defaultLanguage is used when the user's language has no catalog.
+const defaultLanguage = "ru"
+
+//go:embed locales/*.yaml
+var localeFiles embed.FS
+
+// messages holds the bot message catalogs (locales/<lang>.yaml).
+var messages = mustLoadMessages()
+
+func mustLoadMessages() *i18n.Bundle {
+	locales, err := fs.Sub(localeFiles, "locales")
+	if err != nil {
+		panic(err)
+	}
+	bundle, err := i18n.Load(locales, defaultLanguage)
+	if err != nil {
+		panic(fmt.Sprintf("load bot messages: %v", err))
+	}
+	return bundle
+}
+
+// loc returns the localizer of the user whose update is being handled.
+func (b *Bot) loc(ctx context.Context) *i18n.Localizer {
+	if l := i18n.FromContext(ctx); l != nil {
+		return l
+	}
+	return messages.Localizer(defaultLanguage)
+}
+
+// locFor returns the localizer of another Telegram user, e.g. the client
+// notified about a manager decision.
+func (b *Bot) locFor(ctx context.Context, telegramID int64) *i18n.Localizer {
+	return messages.Localizer(b.userLanguage(ctx, telegramID, ""))
+}
+
+// userLanguage prefers the language chosen with /language over the language
+// of the user's Telegram client.
+func (b *Bot) userLanguage(ctx context.Context, telegramID int64, telegramLang string) string {
+	if b.db == nil {
+		return telegramLang
+	}
+	chosen, err := b.db.GetUserLanguage(ctx, telegramID)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Int64("telegram_id", telegramID).Msg("failed to load user language")
+	}
+	if chosen != "" {
+		return chosen
+	}
+	return telegramLang
+}
+
+// isButton reports whether text is the label of button key in any language:
+// the keyboard may have been sent before the user switched languages.
+func isButton(text, key string) bool {
+	return messages.Matches(key, text)
+}
+
+// handleLanguageCommand shows the interface language picker.
+func (b *Bot) handleLanguageCommand(ctx context.Context, chatID int64) {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(messages.Languages()))
+	for _, lang := range messages.Languages() {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(messages.Localizer(lang).T("language_name"), "set_language:"+lang),
+		))
+	}
+	msg := tgbotapi.NewMessage(chatID, b.loc(ctx).T("language_choose"))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	_, _ = b.tg.Send(msg)
+}
+
+// handleSetLanguage stores the chosen language and shows the menu in it.
+func (b *Bot) handleSetLanguage(ctx context.Context, cq *tgbotapi.CallbackQuery, lang string) {
+	chatID := cq.Message.Chat.ID
+	if !messages.Supported(lang) {
+		return
+	}
+	u, err := b.db.GetOrCreateUserByTelegramID(ctx, cq.From.ID, cq.From.UserName, cq.From.FirstName, cq.From.LastName, "")
+	if err == nil {
+		err = b.db.SetUserLanguage(ctx, u.ID, lang)
+	}
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Int64("user_id", cq.From.ID).Str("language", lang).Msg("failed to save user language")
+		b.reply(chatID, b.loc(ctx).T("error_save_language"))
+		return
+	}
+
+	ctx = i18n.WithLocalizer(ctx, messages.Localizer(lang))
+	b.reply(chatID, b.loc(ctx).T("language_set"))
+	b.sendMainMenu(ctx, chatID, cq.From.ID)
+}
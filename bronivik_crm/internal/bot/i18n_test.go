@@ -0,0 +1,47 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bronivik/bronivik_crm/internal/i18n"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageCatalogsComplete(t *testing.T) {
+	for _, lang := range messages.Languages() {
+		assert.Empty(t, messages.Missing(lang), "untranslated keys in %s.yaml", lang)
+	}
+	assert.ElementsMatch(t, []string{"en", "ru"}, messages.Languages())
+}
+
+func TestCalendarKeyboardLocalized(t *testing.T) {
+	ru := GenerateCalendarKeyboard(messages.Localizer("ru"), 2026, int(time.March), nil)
+	assert.Equal(t, "Март 2026", ru.InlineKeyboard[0][0].Text)
+	assert.Equal(t, "Пн", ru.InlineKeyboard[1][0].Text)
+	assert.Equal(t, "Вс", ru.InlineKeyboard[1][6].Text)
+
+	en := GenerateCalendarKeyboard(messages.Localizer("en"), 2026, int(time.March), nil)
+	assert.Equal(t, "March 2026", en.InlineKeyboard[0][0].Text)
+	assert.Equal(t, "Mon", en.InlineKeyboard[1][0].Text)
+	assert.Equal(t, "Sun", en.InlineKeyboard[1][6].Text)
+	back := en.InlineKeyboard[len(en.InlineKeyboard)-1][0]
+	assert.Equal(t, "⬅️ Back", back.Text)
+}
+
+func TestValidateBookingTimeLocalized(t *testing.T) {
+	b := &Bot{rules: &BookingRules{MinAdvance: time.Hour, MaxAdvance: 31 * 24 * time.Hour}}
+	now := time.Now()
+
+	ctx := i18n.WithLocalizer(context.Background(), messages.Localizer("en"))
+	err := b.validateBookingTime(ctx, now.Add(time.Minute))
+	require.Error(t, err)
+	assert.Equal(t, "Too soon. Book at least 60 minutes in advance.", err.Error())
+
+	err = b.validateBookingTime(context.Background(), now.Add(40*24*time.Hour))
+	require.Error(t, err)
+	assert.Equal(t, "Слишком далеко по времени. Доступно бронирование максимум на 31 день вперед.", err.Error())
+}
@@ -0,0 +1,184 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"bronivik/bronivik_crm/internal/model"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
+	"github.com/xuri/excelize/v2"
+)
+
+// handleMyData sends the user an export of everything stored about them.
+func (b *Bot) handleMyData(ctx context.Context, msg *tgbotapi.Message) {
+	b.sendUserData(ctx, msg.Chat.ID, msg.From.ID)
+}
+
+// handleUserData exports another user's data for a manager: /user_data <telegram_id>.
+func (b *Bot) handleUserData(ctx context.Context, msg *tgbotapi.Message) {
+	telegramID, ok := parseTelegramIDArg(msg.Text)
+	if !ok {
+		b.reply(msg.Chat.ID, b.loc(ctx).T("user_data_usage"))
+		return
+	}
+	b.sendUserData(ctx, msg.Chat.ID, telegramID)
+}
+
+func (b *Bot) sendUserData(ctx context.Context, chatID, telegramID int64) {
+	l := b.loc(ctx)
+	log := zerolog.Ctx(ctx)
+	export, err := b.db.ExportUserData(ctx, telegramID)
+	if err != nil {
+		log.Error().Err(err).Int64("telegram_id", telegramID).Msg("failed to export user data")
+		b.reply(chatID, l.T("user_data_error"))
+		return
+	}
+	if export.IsEmpty() {
+		b.reply(chatID, l.T("user_data_empty", telegramID))
+		return
+	}
+
+	files, err := userDataFiles(export)
+	if err != nil {
+		log.Error().Err(err).Int64("telegram_id", telegramID).Msg("failed to build user data files")
+		b.reply(chatID, l.T("user_data_error"))
+		return
+	}
+	for _, file := range files {
+		doc := tgbotapi.NewDocument(chatID, file)
+		doc.Caption = l.T("user_data_caption", telegramID)
+		if _, err := b.tg.Send(doc); err != nil {
+			log.Error().Err(err).Int64("telegram_id", telegramID).Msg("failed to send user data")
+			b.reply(chatID, l.T("user_data_error"))
+			return
+		}
+	}
+	log.Info().Int64("telegram_id", telegramID).Int64("chat_id", chatID).Msg("user data export sent")
+}
+
+// userDataFiles renders the export as JSON and as XLSX with a sheet per table.
+// The files are built in memory so personal data never lands on disk.
+func userDataFiles(export *model.UserDataExport) ([]tgbotapi.FileBytes, error) {
+	name := fmt.Sprintf("user_data_%d_%s", export.TelegramID, export.GeneratedAt.Format("2006-01-02"))
+
+	jsonData, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode json: %w", err)
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+	for _, section := range export.Sections {
+		if _, err := f.NewSheet(section.Name); err != nil {
+			return nil, fmt.Errorf("create sheet %s: %w", section.Name, err)
+		}
+		header := make([]interface{}, len(section.Columns))
+		for i, col := range section.Columns {
+			header[i] = col
+		}
+		_ = f.SetSheetRow(section.Name, "A1", &header)
+		for i, row := range section.Rows {
+			values := make([]interface{}, len(section.Columns))
+			for j, col := range section.Columns {
+				values[j] = xlsxValue(row[col])
+			}
+			cell, _ := excelize.CoordinatesToCellName(1, i+2)
+			_ = f.SetSheetRow(section.Name, cell, &values)
+		}
+	}
+	_ = f.DeleteSheet("Sheet1")
+
+	var xlsxData bytes.Buffer
+	if err := f.Write(&xlsxData); err != nil {
+		return nil, fmt.Errorf("write xlsx: %w", err)
+	}
+
+	return []tgbotapi.FileBytes{
+		{Name: name + ".json", Bytes: jsonData},
+		{Name: name + ".xlsx", Bytes: xlsxData.Bytes()},
+	}, nil
+}
+
+func xlsxValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return val.Format("2006-01-02 15:04:05")
+	default:
+		return val
+	}
+}
+
+// handleEraseUser asks the manager to confirm erasure: /erase_user <telegram_id>.
+func (b *Bot) handleEraseUser(ctx context.Context, msg *tgbotapi.Message) {
+	l := b.loc(ctx)
+	telegramID, ok := parseTelegramIDArg(msg.Text)
+	if !ok {
+		b.reply(msg.Chat.ID, l.T("erase_user_usage"))
+		return
+	}
+
+	reply := tgbotapi.NewMessage(msg.Chat.ID, l.T("erase_user_confirm", telegramID))
+	reply.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(l.T("btn_erase_user_confirm"), fmt.Sprintf("erase_user:%d", telegramID)),
+		tgbotapi.NewInlineKeyboardButtonData(l.T("btn_cancel"), "erase_user_cancel"),
+	))
+	_, _ = b.tg.Send(reply)
+}
+
+// handleEraseUserCallback erases the user's data once the manager confirms.
+func (b *Bot) handleEraseUserCallback(ctx context.Context, cq *tgbotapi.CallbackQuery, data string) {
+	if !b.isManager(cq.From.ID) {
+		return
+	}
+	l := b.loc(ctx)
+	chatID := cq.Message.Chat.ID
+
+	var text string
+	if data == "erase_user_cancel" {
+		text = l.T("erase_user_cancelled")
+	} else {
+		telegramID, err := strconv.ParseInt(strings.TrimPrefix(data, "erase_user:"), 10, 64)
+		if err != nil {
+			return
+		}
+		result, err := b.db.EraseUserData(ctx, telegramID)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			text = l.T("erase_user_not_found", telegramID)
+		case err != nil:
+			zerolog.Ctx(ctx).Error().Err(err).Int64("telegram_id", telegramID).Msg("failed to erase user data")
+			text = l.T("user_data_error")
+		default:
+			b.state.reset(telegramID)
+			zerolog.Ctx(ctx).Info().
+				Int64("telegram_id", telegramID).
+				Int64("manager_id", cq.From.ID).
+				Str("pseudonym", result.Pseudonym).
+				Int("bookings", len(result.BookingIDs)).
+				Msg("user data erased by manager")
+			text = l.T("erase_user_done", telegramID, len(result.BookingIDs), result.Pseudonym)
+		}
+	}
+
+	_, _ = b.tg.Send(tgbotapi.NewEditMessageText(chatID, cq.Message.MessageID, text))
+}
+
+func parseTelegramIDArg(text string) (int64, bool) {
+	parts := strings.Fields(text)
+	if len(parts) != 2 {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	return id, err == nil && id > 0
+}
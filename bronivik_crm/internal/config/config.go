@@ -1,17 +1,28 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 
+	"bronivik/bronivik_crm/internal/fieldcrypt"
+	"bronivik/bronivik_crm/internal/retention"
+
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
 	Telegram struct {
 		BotToken string `yaml:"bot_token"`
-		Debug    bool   `yaml:"debug"`
+		// WebhookURL switches the bot from long polling to a webhook.
+		WebhookURL      string `yaml:"webhook_url"`
+		WebhookSecret   string `yaml:"webhook_secret"`
+		WebhookListen   string `yaml:"webhook_listen"`
+		WebhookCertFile string `yaml:"webhook_cert_file"`
+		WebhookKeyFile  string `yaml:"webhook_key_file"`
+		Debug           bool   `yaml:"debug"`
 	} `yaml:"telegram"`
 
 	Database struct {
@@ -36,7 +47,14 @@ type Config struct {
 		BaseURL         string `yaml:"base_url"`
 		APIKey          string `yaml:"api_key"`
 		APIExtra        string `yaml:"api_extra"`
+		SigningSecret   string `yaml:"signing_secret"` // signs every request to bronivik_jr when set
 		CacheTTLSeconds int    `yaml:"cache_ttl_seconds"`
+		// TLS for an https base_url: ca_file verifies bronivik_jr, cert_file/key_file enable mTLS
+		TLS struct {
+			CAFile   string `yaml:"ca_file"`
+			CertFile string `yaml:"cert_file"`
+			KeyFile  string `yaml:"key_file"`
+		} `yaml:"tls"`
 	} `yaml:"api"`
 
 	Monitoring struct {
@@ -53,6 +71,10 @@ type Config struct {
 
 	Managers []int64 `yaml:"managers"`
 
+	Encryption EncryptionConfig `yaml:"encryption"`
+
+	Retention RetentionConfig `yaml:"retention"`
+
 	// CabinetsConfigPath is the path to cabinets.yaml configuration file
 	CabinetsConfigPath string `yaml:"cabinets_config_path"`
 }
@@ -83,6 +105,36 @@ func Load(path string) (*Config, error) {
 		return nil, err
 	}
 
+	if cfg.Telegram.WebhookListen == "" {
+		cfg.Telegram.WebhookListen = ":8443"
+	}
+	if cfg.Telegram.WebhookURL != "" && cfg.Telegram.WebhookSecret == "" {
+		return nil, errors.New("telegram.webhook_secret is required when webhook_url is set")
+	}
+	if (cfg.Telegram.WebhookCertFile == "") != (cfg.Telegram.WebhookKeyFile == "") {
+		return nil, errors.New("telegram.webhook_cert_file and webhook_key_file must be set together")
+	}
+
+	if cfg.Encryption.RotationIntervalMinutes <= 0 {
+		cfg.Encryption.RotationIntervalMinutes = 60
+	}
+	if cfg.Encryption.RotationBatchSize <= 0 {
+		cfg.Encryption.RotationBatchSize = 200
+	}
+	if _, err = cfg.Encryption.Cipher(); err != nil {
+		return nil, err
+	}
+
+	if cfg.Retention.IntervalHours <= 0 {
+		cfg.Retention.IntervalHours = 24
+	}
+	if cfg.Retention.BatchSize <= 0 {
+		cfg.Retention.BatchSize = 500
+	}
+	if err = cfg.Retention.validate(); err != nil {
+		return nil, err
+	}
+
 	// Set default cabinets config path
 	if cfg.CabinetsConfigPath == "" {
 		cfg.CabinetsConfigPath = "configs/cabinets.yaml"
@@ -91,6 +143,103 @@ func Load(path string) (*Config, error) {
 	return &cfg, nil
 }
 
+// EncryptionConfig enables encryption of client phones and names at rest.
+type EncryptionConfig struct {
+	Enabled      bool            `yaml:"enabled"`
+	PrimaryKeyID string          `yaml:"primary_key_id"` // key for new values
+	Keys         []EncryptionKey `yaml:"keys"`           // older keys stay for reading until re-encrypted
+	// IndexKey keys the blind index used for phone lookups; it must not change once enabled.
+	IndexKey                string `yaml:"index_key"`
+	RotationIntervalMinutes int    `yaml:"rotation_interval_minutes"`
+	RotationBatchSize       int    `yaml:"rotation_batch_size"`
+}
+
+// EncryptionKey is an AES-256 key (32 bytes, base64 or hex) with its id.
+type EncryptionKey struct {
+	ID  string `yaml:"id"`
+	Key string `yaml:"key"`
+}
+
+// Cipher builds the key set. It returns nil when encryption is disabled,
+// in which case values are stored as is.
+func (e *EncryptionConfig) Cipher() (*fieldcrypt.Cipher, error) {
+	if !e.Enabled {
+		return nil, nil
+	}
+	keys := make([]fieldcrypt.Key, 0, len(e.Keys))
+	for _, k := range e.Keys {
+		secret, err := fieldcrypt.DecodeKey(k.Key)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q: %w", k.ID, err)
+		}
+		keys = append(keys, fieldcrypt.Key{ID: k.ID, Secret: secret})
+	}
+	indexKey, err := fieldcrypt.DecodeKey(e.IndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("encryption index_key: %w", err)
+	}
+	return fieldcrypt.New(keys, e.PrimaryKeyID, indexKey)
+}
+
+// RetentionConfig is the personal data retention policy: rules clear or hash
+// fields and delete rows older than a given age.
+type RetentionConfig struct {
+	Enabled       bool            `yaml:"enabled"`
+	DryRun        bool            `yaml:"dry_run"` // only log what would be changed
+	IntervalHours int             `yaml:"interval_hours"`
+	BatchSize     int             `yaml:"batch_size"`
+	HashKey       string          `yaml:"hash_key"` // HMAC key for action: hash, required by such rules
+	Rules         []RetentionRule `yaml:"rules"`
+}
+
+// RetentionRule is one line of the policy, e.g. "clear hourly_bookings.client_phone after 180 days".
+type RetentionRule struct {
+	Table     string `yaml:"table"`
+	Column    string `yaml:"column"` // empty for action: delete
+	Action    string `yaml:"action"` // clear, hash, delete
+	AfterDays int    `yaml:"after_days"`
+}
+
+// Policy converts the rules for the retention engine.
+func (r *RetentionConfig) Policy() []retention.Rule {
+	rules := make([]retention.Rule, 0, len(r.Rules))
+	for _, rule := range r.Rules {
+		rules = append(rules, retention.Rule{
+			Table:  rule.Table,
+			Column: rule.Column,
+			Action: rule.Action,
+			After:  time.Duration(rule.AfterDays) * 24 * time.Hour,
+		})
+	}
+	return rules
+}
+
+// Options returns the retention engine settings.
+func (r *RetentionConfig) Options() retention.Options {
+	opts := retention.Options{BatchSize: r.BatchSize}
+	if r.HashKey != "" {
+		opts.HashKey = []byte(r.HashKey)
+	}
+	return opts
+}
+
+func (r *RetentionConfig) validate() error {
+	for i, rule := range r.Rules {
+		switch rule.Action {
+		case retention.ActionClear, retention.ActionHash, retention.ActionDelete:
+		default:
+			return fmt.Errorf("retention rule %d: unknown action %q", i+1, rule.Action)
+		}
+		if rule.AfterDays <= 0 {
+			return fmt.Errorf("retention rule %d: after_days must be positive", i+1)
+		}
+		if rule.Action == retention.ActionHash && r.HashKey == "" {
+			return fmt.Errorf("retention rule %d: action hash requires retention.hash_key", i+1)
+		}
+	}
+	return nil
+}
+
 func (c *Config) BookingMinAdvance() time.Duration {
 	if c.Booking.MinAdvanceMinutes <= 0 {
 		return 60 * time.Minute
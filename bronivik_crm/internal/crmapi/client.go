@@ -9,15 +9,18 @@ import (
 	"strings"
 	"time"
 
+	"bronivik/bronivik_crm/internal/reqsign"
+
 	"github.com/redis/go-redis/v9"
 )
 
 // BronivikClient is a simple HTTP client to call bronivik_jr availability APIs.
 type BronivikClient struct {
-	baseURL    string
-	apiKey     string
-	apiExtra   string
-	httpClient *http.Client
+	baseURL       string
+	apiKey        string
+	apiExtra      string
+	signingSecret string
+	httpClient    *http.Client
 
 	redis    *redis.Client
 	cacheTTL time.Duration
@@ -52,6 +55,12 @@ func (c *BronivikClient) UseRedisCache(redisClient *redis.Client, ttl time.Durat
 	c.cacheTTL = ttl
 }
 
+// UseSigning enables HMAC signing of every request with the key's signing secret,
+// so a captured request cannot be altered or replayed against bronivik_jr.
+func (c *BronivikClient) UseSigning(secret string) {
+	c.signingSecret = secret
+}
+
 // GetAvailability fetches availability for item/date (YYYY-MM-DD).
 func (c *BronivikClient) GetAvailability(ctx context.Context, itemName, date string) (resp *AvailabilityResponse, err error) {
 	endpoint := fmt.Sprintf("%s/api/v1/availability/%s?date=%s",
@@ -137,7 +146,9 @@ func (c *BronivikClient) doGet(ctx context.Context, endpoint string, out any) er
 	if err != nil {
 		return err
 	}
-	c.addHeaders(req)
+	if err := c.addHeaders(req, nil); err != nil {
+		return err
+	}
 	return c.do(req, out)
 }
 
@@ -151,7 +162,9 @@ func (c *BronivikClient) doPost(ctx context.Context, endpoint string, body, out
 		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
-	c.addHeaders(req)
+	if err := c.addHeaders(req, data); err != nil {
+		return err
+	}
 	return c.do(req, out)
 }
 
@@ -172,13 +185,25 @@ func (c *BronivikClient) do(req *http.Request, out any) error {
 	return dec.Decode(out)
 }
 
-func (c *BronivikClient) addHeaders(req *http.Request) {
+// addHeaders sets the auth headers and, if signing is enabled, signs the request with its body.
+func (c *BronivikClient) addHeaders(req *http.Request, body []byte) error {
 	if c.apiKey != "" {
 		req.Header.Set("x-api-key", c.apiKey)
 	}
 	if c.apiExtra != "" {
 		req.Header.Set("x-api-extra", c.apiExtra)
 	}
+	if c.signingSecret == "" {
+		return nil
+	}
+	h, err := reqsign.SignRequest(c.signingSecret, req.Method, req.URL.RequestURI(), body, time.Now())
+	if err != nil {
+		return err
+	}
+	req.Header.Set(reqsign.HeaderSignature, h.Signature)
+	req.Header.Set(reqsign.HeaderTimestamp, h.Timestamp)
+	req.Header.Set(reqsign.HeaderNonce, h.Nonce)
+	return nil
 }
 
 // Device represents a device from the devices API.
@@ -286,7 +311,9 @@ func (c *BronivikClient) CancelDeviceBooking(ctx context.Context, externalBookin
 	if err != nil {
 		return err
 	}
-	c.addHeaders(req)
+	if err := c.addHeaders(req, nil); err != nil {
+		return err
+	}
 	return c.do(req, nil)
 }
 
@@ -297,6 +324,10 @@ func (c *BronivikClient) HealthCheck(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	// /healthz is behind the same auth as the API
+	if err := c.addHeaders(req, nil); err != nil {
+		return err
+	}
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return err
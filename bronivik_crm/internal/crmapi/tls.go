@@ -0,0 +1,102 @@
+package crmapi
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// UseTLS configures HTTPS to bronivik_jr. caFile, when set, replaces the system
+// roots for verifying the server; certFile and keyFile add a client certificate
+// for mutual TLS. The client certificate is re-read when its files change, so a
+// renewed certificate is picked up by new connections without a restart.
+func (c *BronivikClient) UseTLS(caFile, certFile, keyFile string) error {
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		caPEM, err := os.ReadFile(caFile)
+		if err != nil {
+			return fmt.Errorf("read ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return errors.New("failed to parse ca_file PEM")
+		}
+		tlsCfg.RootCAs = pool
+	}
+
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return errors.New("tls cert_file and key_file must be set together")
+		}
+		certs, err := newClientCertLoader(certFile, keyFile)
+		if err != nil {
+			return err
+		}
+		tlsCfg.GetClientCertificate = certs.get
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsCfg
+	c.httpClient.Transport = transport
+	return nil
+}
+
+// clientCertLoader serves the client certificate from disk and reloads it when
+// the files change.
+type clientCertLoader struct {
+	certFile string
+	keyFile  string
+
+	mu    sync.Mutex
+	cert  *tls.Certificate
+	stamp string
+}
+
+func newClientCertLoader(certFile, keyFile string) (*clientCertLoader, error) {
+	l := &clientCertLoader{certFile: certFile, keyFile: keyFile}
+	stamp, err := l.filesStamp()
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load client certificate: %w", err)
+	}
+	l.cert, l.stamp = &cert, stamp
+	return l, nil
+}
+
+// get is called on every TLS handshake. A certificate that fails to load (for
+// example, half-written during renewal) is ignored until the next handshake.
+func (l *clientCertLoader) get(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stamp, err := l.filesStamp()
+	if err != nil || stamp == l.stamp {
+		return l.cert, nil
+	}
+	cert, err := tls.LoadX509KeyPair(l.certFile, l.keyFile)
+	if err != nil {
+		return l.cert, nil
+	}
+	l.cert, l.stamp = &cert, stamp
+	return l.cert, nil
+}
+
+func (l *clientCertLoader) filesStamp() (string, error) {
+	var stamp string
+	for _, path := range []string{l.certFile, l.keyFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", fmt.Errorf("stat %s: %w", path, err)
+		}
+		stamp += fmt.Sprintf("%d/%d;", info.ModTime().UnixNano(), info.Size())
+	}
+	return stamp, nil
+}
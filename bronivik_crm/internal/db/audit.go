@@ -14,6 +14,7 @@ var AuditTableNames = []string{
 	"cabinet_schedules",
 	"cabinet_schedule_overrides",
 	"hourly_bookings",
+	"retention_log",
 }
 
 // GetTableNames returns list of table names to export.
@@ -67,26 +68,39 @@ func (db *DB) GetTableData(ctx context.Context, tableName string) (data []map[st
 	}
 	defer dataRows.Close()
 
-	for dataRows.Next() {
+	data, _, err = scanRowMaps(dataRows)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, columns, nil
+}
+
+// scanRowMaps reads all result rows as column name to value maps.
+func scanRowMaps(rows *sql.Rows) (result []map[string]interface{}, columns []string, err error) {
+	columns, err = rows.Columns()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for rows.Next() {
 		values := make([]interface{}, len(columns))
 		valuePtrs := make([]interface{}, len(columns))
 		for i := range values {
 			valuePtrs[i] = &values[i]
 		}
 
-		err = dataRows.Scan(valuePtrs...)
-		if err != nil {
-			return nil, nil, err
+		if errScan := rows.Scan(valuePtrs...); errScan != nil {
+			return nil, nil, errScan
 		}
 
 		row := make(map[string]interface{})
 		for i, col := range columns {
 			row[col] = values[i]
 		}
-		data = append(data, row)
+		result = append(result, row)
 	}
 
-	return data, columns, dataRows.Err()
+	return result, columns, rows.Err()
 }
 
 // GetDB returns the underlying sql.DB.
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"bronivik/bronivik_crm/internal/crmapi"
+	"bronivik/bronivik_crm/internal/fieldcrypt"
 	"bronivik/bronivik_crm/internal/model"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -30,6 +31,7 @@ var (
 // DB wraps sql.DB for the CRM bot.
 type DB struct {
 	*sql.DB
+	fields *fieldcrypt.Cipher // encrypts client phones and names; nil disables it
 }
 
 // --- Users CRUD ---
@@ -47,10 +49,14 @@ func (db *DB) GetOrCreateUserByTelegramID(
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	u, err := getUserByTelegramIDTx(ctx, tx, telegramID)
+	u, err := getUserByTelegramIDTx(ctx, tx, telegramID, db.fields)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, err
 	}
+	sealedPhone, encErr := db.fields.Encrypt(phone)
+	if encErr != nil {
+		return nil, fmt.Errorf("encrypt phone: %w", encErr)
+	}
 
 	now := time.Now()
 	if err == sql.ErrNoRows {
@@ -59,7 +65,7 @@ func (db *DB) GetOrCreateUserByTelegramID(
 			is_manager, is_blacklisted, created_at, updated_at
 		) VALUES (?, ?, ?, ?, ?, 0, 0, ?, ?)`
 		var res sql.Result
-		res, err = tx.ExecContext(ctx, query, telegramID, username, firstName, lastName, phone, now, now)
+		res, err = tx.ExecContext(ctx, query, telegramID, username, firstName, lastName, sealedPhone, now, now)
 		if err != nil {
 			return nil, err
 		}
@@ -77,7 +83,7 @@ func (db *DB) GetOrCreateUserByTelegramID(
 		if phone != "" {
 			_, _ = tx.ExecContext(ctx, `
 					UPDATE users SET username = ?, first_name = ?, last_name = ?, phone = ?, updated_at = ? 
-					WHERE id = ?`, username, firstName, lastName, phone, now, u.ID)
+					WHERE id = ?`, username, firstName, lastName, sealedPhone, now, u.ID)
 			u.Phone = phone
 		} else {
 			_, _ = tx.ExecContext(ctx, `
@@ -96,12 +102,12 @@ func (db *DB) GetOrCreateUserByTelegramID(
 	return u, nil
 }
 
-func getUserByTelegramIDTx(ctx context.Context, tx *sql.Tx, telegramID int64) (*model.User, error) {
+func getUserByTelegramIDTx(ctx context.Context, tx *sql.Tx, telegramID int64, c *fieldcrypt.Cipher) (*model.User, error) {
 	row := tx.QueryRowContext(ctx, `
 		SELECT id, telegram_id, username, first_name, last_name, 
 		       phone, is_manager, is_blacklisted, created_at, updated_at
 		FROM users WHERE telegram_id = ? LIMIT 1`, telegramID)
-	return scanUser(row)
+	return scanUser(row, c)
 }
 
 // NewDB opens database at path and runs migrations.
@@ -116,7 +122,7 @@ func NewDB(path string) (*DB, error) {
 	if err := createTables(db); err != nil {
 		return nil, err
 	}
-	return &DB{db}, nil
+	return &DB{DB: db}, nil
 }
 
 func createTables(db *sql.DB) error {
@@ -291,7 +297,6 @@ func ensureHourlyBookingColumns(db *sql.DB) error {
 			return fmt.Errorf("add column %s: %w", c.name, err)
 		}
 	}
-
 	// Ensure schedule columns
 	if err := ensureScheduleColumns(db); err != nil {
 		return err
@@ -466,13 +471,17 @@ func (db *DB) CreateHourlyBooking(ctx context.Context, b *model.HourlyBooking) e
 	if b == nil {
 		return fmt.Errorf("booking is nil")
 	}
+	client, err := sealClient(db.fields, b.ClientName, b.ClientPhone)
+	if err != nil {
+		return err
+	}
 	now := time.Now()
 	res, err := db.ExecContext(ctx, `
 		INSERT INTO hourly_bookings (
-			user_id, cabinet_id, item_name, client_name, client_phone, 
+			user_id, cabinet_id, item_name, client_name, client_phone, client_phone_index,
 			start_time, end_time, status, comment, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		b.UserID, b.CabinetID, b.ItemName, b.ClientName, b.ClientPhone,
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		b.UserID, b.CabinetID, b.ItemName, client.name, client.phone, client.phoneIndex,
 		b.StartTime, b.EndTime, b.Status, b.Comment, now, now)
 	if err != nil {
 		return err
@@ -493,7 +502,7 @@ func (db *DB) GetHourlyBooking(ctx context.Context, id int64) (*model.HourlyBook
 		SELECT id, user_id, cabinet_id, item_name, client_name, client_phone, 
 		       start_time, end_time, status, comment, created_at, updated_at 
 		FROM hourly_bookings WHERE id = ?`, id)
-	return scanHourly(row)
+	return scanHourly(row, db.fields)
 }
 
 // ListHourlyBookingsByCabinet returns bookings for a cabinet within range.
@@ -511,7 +520,7 @@ func (db *DB) ListHourlyBookingsByCabinet(ctx context.Context, cabinetID int64,
 
 	var res []model.HourlyBooking
 	for rows.Next() {
-		bk, err := scanHourly(rows)
+		bk, err := scanHourly(rows, db.fields)
 		if err != nil {
 			return nil, err
 		}
@@ -545,7 +554,7 @@ func (db *DB) ListUserBookings(ctx context.Context, userID int64, limit int, inc
 
 	var res []model.HourlyBooking
 	for rows.Next() {
-		bk, err := scanHourly(rows)
+		bk, err := scanHourly(rows, db.fields)
 		if err != nil {
 			return nil, err
 		}
@@ -709,14 +718,18 @@ func (db *DB) CreateHourlyBookingWithChecks(ctx context.Context, booking *model.
 		}
 	}
 
+	sealed, err := sealClient(db.fields, booking.ClientName, booking.ClientPhone)
+	if err != nil {
+		return err
+	}
 	now := time.Now()
 	res, err := tx.ExecContext(ctx, `
 		INSERT INTO hourly_bookings (
-			user_id, cabinet_id, item_name, client_name, client_phone, 
+			user_id, cabinet_id, item_name, client_name, client_phone, client_phone_index,
 			start_time, end_time, status, comment, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		booking.UserID, booking.CabinetID, booking.ItemName, booking.ClientName,
-		booking.ClientPhone, booking.StartTime, booking.EndTime, booking.Status,
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		booking.UserID, booking.CabinetID, booking.ItemName, sealed.name,
+		sealed.phone, sealed.phoneIndex, booking.StartTime, booking.EndTime, booking.Status,
 		booking.Comment, now, now)
 	if err != nil {
 		return err
@@ -876,7 +889,7 @@ func combineDateTime(date time.Time, hm string) (time.Time, error) {
 	return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, date.Location()), nil
 }
 
-func scanHourly(r rowScanner) (*model.HourlyBooking, error) {
+func scanHourly(r rowScanner, c *fieldcrypt.Cipher) (*model.HourlyBooking, error) {
 	var b model.HourlyBooking
 	err := r.Scan(
 		&b.ID, &b.UserID, &b.CabinetID, &b.ItemName, &b.ClientName,
@@ -886,6 +899,9 @@ func scanHourly(r rowScanner) (*model.HourlyBooking, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := openClient(c, &b); err != nil {
+		return nil, err
+	}
 	return &b, nil
 }
 
@@ -953,7 +969,7 @@ func scanCabinet(r rowScanner) (*model.Cabinet, error) {
 	return &c, nil
 }
 
-func scanUser(r rowScanner) (*model.User, error) {
+func scanUser(r rowScanner, c *fieldcrypt.Cipher) (*model.User, error) {
 	var u model.User
 	if err := r.Scan(
 		&u.ID, &u.TelegramID, &u.Username, &u.FirstName, &u.LastName,
@@ -961,6 +977,11 @@ func scanUser(r rowScanner) (*model.User, error) {
 	); err != nil {
 		return nil, err
 	}
+	phone, err := c.Decrypt(u.Phone)
+	if err != nil {
+		return nil, fmt.Errorf("user %d: decrypt phone: %w", u.ID, err)
+	}
+	u.Phone = phone
 	return &u, nil
 }
 
@@ -1017,6 +1038,9 @@ func (db *DB) ListPendingBookings(ctx context.Context) ([]model.HourlyBooking, e
 		if err != nil {
 			return nil, err
 		}
+		if err := openClient(db.fields, &b); err != nil {
+			return nil, err
+		}
 		res = append(res, b)
 	}
 	return res, rows.Err()
@@ -1050,6 +1074,9 @@ func (db *DB) ListBookingsByDate(ctx context.Context, dateStr string) ([]model.H
 		if err != nil {
 			return nil, err
 		}
+		if err := openClient(db.fields, &b); err != nil {
+			return nil, err
+		}
 		res = append(res, b)
 	}
 	return res, rows.Err()
@@ -1,12 +1,18 @@
 package db
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
+	"errors"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"bronivik/bronivik_crm/internal/fieldcrypt"
 	"bronivik/bronivik_crm/internal/model"
+	"bronivik/bronivik_crm/internal/retention"
 )
 
 func TestGetAvailableSlots_RespectsBookings(t *testing.T) {
@@ -18,6 +24,9 @@ func TestGetAvailableSlots_RespectsBookings(t *testing.T) {
 	defer db.Close()
 
 	ctx := context.Background()
+	if _, err = db.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
 	user, err := db.GetOrCreateUserByTelegramID(ctx, 123, "u", "First", "Last", "")
 	if err != nil {
 		t.Fatalf("GetOrCreateUserByTelegramID: %v", err)
@@ -73,6 +82,9 @@ func TestCreateHourlyBookingWithChecks_BusySlot(t *testing.T) {
 	defer db.Close()
 
 	ctx := context.Background()
+	if _, err = db.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
 	user, err := db.GetOrCreateUserByTelegramID(ctx, 123, "u", "First", "Last", "")
 	if err != nil {
 		t.Fatalf("GetOrCreateUserByTelegramID: %v", err)
@@ -118,3 +130,344 @@ func TestCreateHourlyBookingWithChecks_BusySlot(t *testing.T) {
 		t.Fatalf("expected ErrSlotNotAvailable, got %v", err)
 	}
 }
+
+func TestMigrate_AppliesOnceOnTopOfBaseline(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "crm.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	applied, err := db.Migrate(ctx)
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	runner, err := db.Migrator(false, nil)
+	if err != nil {
+		t.Fatalf("Migrator: %v", err)
+	}
+	if int64(applied) != runner.Latest() {
+		t.Fatalf("expected %d applied migrations, got %d", runner.Latest(), applied)
+	}
+	if applied, err = db.Migrate(ctx); err != nil || applied != 0 {
+		t.Fatalf("second Migrate: applied=%d err=%v", applied, err)
+	}
+
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'reminders'`).Scan(&n); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("reminders table was not created")
+	}
+}
+
+func TestUserLanguage(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "crm.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+	if _, err = db.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	if lang, err := db.GetUserLanguage(ctx, 42); err != nil || lang != "" {
+		t.Fatalf("unknown user: lang=%q err=%v", lang, err)
+	}
+
+	user, err := db.GetOrCreateUserByTelegramID(ctx, 42, "u", "First", "Last", "")
+	if err != nil {
+		t.Fatalf("GetOrCreateUserByTelegramID: %v", err)
+	}
+	if err = db.UpsertUserSettings(ctx, user.ID, false, 2); err != nil {
+		t.Fatalf("UpsertUserSettings: %v", err)
+	}
+	if err = db.SetUserLanguage(ctx, user.ID, "en"); err != nil {
+		t.Fatalf("SetUserLanguage: %v", err)
+	}
+
+	if lang, err := db.GetUserLanguage(ctx, 42); err != nil || lang != "en" {
+		t.Fatalf("GetUserLanguage: lang=%q err=%v", lang, err)
+	}
+	settings, err := db.GetUserSettings(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetUserSettings: %v", err)
+	}
+	if settings.Language != "en" || settings.RemindersEnabled || settings.ReminderHoursBefore != 2 {
+		t.Fatalf("language must not reset reminder settings: %+v", settings)
+	}
+}
+
+func TestExportAndEraseUserData(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "crm.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+	if _, err = db.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	user, err := db.GetOrCreateUserByTelegramID(ctx, 500, "anna", "Anna", "Ivanova", "79991234567")
+	if err != nil {
+		t.Fatalf("GetOrCreateUserByTelegramID: %v", err)
+	}
+	if err = db.UpsertUserSettings(ctx, user.ID, true, 24); err != nil {
+		t.Fatalf("UpsertUserSettings: %v", err)
+	}
+	cab := &model.Cabinet{Name: "Cab1"}
+	if err = db.CreateCabinet(ctx, cab); err != nil {
+		t.Fatalf("CreateCabinet: %v", err)
+	}
+	bk := &model.HourlyBooking{
+		UserID: user.ID, CabinetID: cab.ID, ClientName: "Anna", ClientPhone: "79991234567",
+		StartTime: time.Date(2026, 1, 5, 10, 0, 0, 0, time.Local),
+		EndTime:   time.Date(2026, 1, 5, 11, 0, 0, 0, time.Local),
+		Status:    "approved", Comment: "first visit",
+	}
+	if err = db.CreateHourlyBooking(ctx, bk); err != nil {
+		t.Fatalf("CreateHourlyBooking: %v", err)
+	}
+	if _, err = db.ExecContext(ctx, `INSERT INTO reminders (user_id, booking_id, reminder_type, scheduled_at)
+		VALUES (?, ?, '24h_before', ?)`, user.ID, bk.ID, time.Now()); err != nil {
+		t.Fatalf("insert reminder: %v", err)
+	}
+
+	export, err := db.ExportUserData(ctx, 500)
+	if err != nil {
+		t.Fatalf("ExportUserData: %v", err)
+	}
+	for _, s := range export.Sections {
+		if len(s.Rows) != 1 {
+			t.Fatalf("section %s: expected 1 row, got %d", s.Name, len(s.Rows))
+		}
+	}
+	if name := export.Sections[0].Rows[0]["first_name"]; name != "Anna" {
+		t.Fatalf("unexpected first_name %v", name)
+	}
+
+	result, err := db.EraseUserData(ctx, 500)
+	if err != nil {
+		t.Fatalf("EraseUserData: %v", err)
+	}
+	if len(result.BookingIDs) != 1 || result.BookingIDs[0] != bk.ID {
+		t.Fatalf("unexpected booking ids %v", result.BookingIDs)
+	}
+
+	erased, err := db.GetHourlyBooking(ctx, bk.ID)
+	if err != nil {
+		t.Fatalf("GetHourlyBooking: %v", err)
+	}
+	if erased.ClientName != result.Pseudonym || erased.ClientPhone != "" || erased.Comment != "" {
+		t.Fatalf("booking still has personal data: %+v", erased)
+	}
+	if erased.Status != "approved" || erased.CabinetID != cab.ID || !erased.StartTime.Equal(bk.StartTime) {
+		t.Fatalf("booking statistics changed: %+v", erased)
+	}
+
+	if export, err = db.ExportUserData(ctx, 500); err != nil || !export.IsEmpty() {
+		t.Fatalf("export after erasure: empty=%v err=%v", export.IsEmpty(), err)
+	}
+	if _, err = db.EraseUserData(ctx, 500); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected sql.ErrNoRows, got %v", err)
+	}
+
+	// A returning user gets a fresh profile
+	again, err := db.GetOrCreateUserByTelegramID(ctx, 500, "anna", "Anna", "", "")
+	if err != nil {
+		t.Fatalf("GetOrCreateUserByTelegramID: %v", err)
+	}
+	if again.ID == user.ID {
+		t.Fatalf("erased profile was reused")
+	}
+}
+
+func TestFieldEncryption(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "crm.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+	if _, err = db.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	newCipher := func(primary string) *fieldcrypt.Cipher {
+		c, cErr := fieldcrypt.New([]fieldcrypt.Key{
+			{ID: "k1", Secret: bytes.Repeat([]byte{1}, 32)},
+			{ID: "k2", Secret: bytes.Repeat([]byte{2}, 32)},
+		}, primary, bytes.Repeat([]byte{9}, 32))
+		if cErr != nil {
+			t.Fatalf("fieldcrypt.New: %v", cErr)
+		}
+		return c
+	}
+	cab := &model.Cabinet{Name: "Cab1"}
+	if err = db.CreateCabinet(ctx, cab); err != nil {
+		t.Fatalf("CreateCabinet: %v", err)
+	}
+	user, err := db.GetOrCreateUserByTelegramID(ctx, 600, "anna", "Anna", "", "")
+	if err != nil {
+		t.Fatalf("GetOrCreateUserByTelegramID: %v", err)
+	}
+	booking := func(hour int) *model.HourlyBooking {
+		return &model.HourlyBooking{
+			UserID: user.ID, CabinetID: cab.ID, ClientName: "Анна Петрова", ClientPhone: "+7 999 123-45-67",
+			StartTime: time.Date(2026, 1, 5, hour, 0, 0, 0, time.Local),
+			EndTime:   time.Date(2026, 1, 5, hour+1, 0, 0, 0, time.Local),
+			Status:    "approved",
+		}
+	}
+
+	// Written before encryption was enabled
+	legacy := booking(10)
+	if err = db.CreateHourlyBooking(ctx, legacy); err != nil {
+		t.Fatalf("CreateHourlyBooking: %v", err)
+	}
+
+	db.SetFieldCipher(newCipher("k1"))
+	enc := booking(12)
+	if err = db.CreateHourlyBooking(ctx, enc); err != nil {
+		t.Fatalf("CreateHourlyBooking: %v", err)
+	}
+	rawPhone := func(id int64) string {
+		var phone string
+		if err := db.QueryRowContext(ctx, `SELECT client_phone FROM hourly_bookings WHERE id = ?`, id).Scan(&phone); err != nil {
+			t.Fatalf("select phone: %v", err)
+		}
+		return phone
+	}
+	if p := rawPhone(enc.ID); !strings.HasPrefix(p, "enc:v1:k1:") {
+		t.Fatalf("phone stored unencrypted: %q", p)
+	}
+	got, err := db.GetHourlyBooking(ctx, enc.ID)
+	if err != nil {
+		t.Fatalf("GetHourlyBooking: %v", err)
+	}
+	if got.ClientName != "Анна Петрова" || got.ClientPhone != "+7 999 123-45-67" {
+		t.Fatalf("unexpected decrypted client: %q %q", got.ClientName, got.ClientPhone)
+	}
+
+	// Rotate to k2: the legacy row gets encrypted and indexed too
+	db.SetFieldCipher(newCipher("k2"))
+	for {
+		n, rErr := db.RotateFieldEncryption(ctx, 1)
+		if rErr != nil {
+			t.Fatalf("RotateFieldEncryption: %v", rErr)
+		}
+		if n == 0 {
+			break
+		}
+	}
+	for _, id := range []int64{legacy.ID, enc.ID} {
+		if p := rawPhone(id); !strings.HasPrefix(p, "enc:v1:k2:") {
+			t.Fatalf("booking %d not rotated: %q", id, p)
+		}
+	}
+
+	found, err := db.ListBookingsByPhone(ctx, "89991234567", 10)
+	if err != nil {
+		t.Fatalf("ListBookingsByPhone: %v", err)
+	}
+	if len(found) != 2 || found[0].ClientPhone != "+7 999 123-45-67" {
+		t.Fatalf("unexpected lookup result: %+v", found)
+	}
+}
+
+func TestRetentionEngine(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "crm.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+	if _, err = db.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	c, err := fieldcrypt.New([]fieldcrypt.Key{{ID: "k1", Secret: bytes.Repeat([]byte{1}, 32)}}, "k1", bytes.Repeat([]byte{9}, 32))
+	if err != nil {
+		t.Fatalf("fieldcrypt.New: %v", err)
+	}
+	db.SetFieldCipher(c)
+
+	cab := &model.Cabinet{Name: "Cab1"}
+	if err = db.CreateCabinet(ctx, cab); err != nil {
+		t.Fatalf("CreateCabinet: %v", err)
+	}
+	user, err := db.GetOrCreateUserByTelegramID(ctx, 700, "anna", "Anna", "", "")
+	if err != nil {
+		t.Fatalf("GetOrCreateUserByTelegramID: %v", err)
+	}
+	start := time.Now().AddDate(-1, 0, 0)
+	old := &model.HourlyBooking{
+		UserID: user.ID, CabinetID: cab.ID, ClientName: "Анна Петрова", ClientPhone: "+7 999 123-45-67",
+		StartTime: start, EndTime: start.Add(time.Hour), Status: "approved",
+	}
+	if err = db.CreateHourlyBooking(ctx, old); err != nil {
+		t.Fatalf("CreateHourlyBooking: %v", err)
+	}
+
+	const halfYear = 180 * 24 * time.Hour
+	policy, err := db.RetentionEngine([]retention.Rule{
+		{Table: "hourly_bookings", Column: "client_phone", Action: retention.ActionClear, After: halfYear},
+		{Table: "hourly_bookings", Column: "client_name", Action: retention.ActionHash, After: halfYear},
+		{Table: "reminders", Action: retention.ActionDelete, After: 30 * 24 * time.Hour},
+	}, retention.Options{HashKey: []byte("secret")})
+	if err != nil {
+		t.Fatalf("RetentionEngine: %v", err)
+	}
+	if _, err = db.RetentionEngine([]retention.Rule{
+		{Table: "cabinets", Column: "name", Action: retention.ActionClear, After: halfYear},
+	}, retention.Options{}); err == nil {
+		t.Fatalf("expected an error for a table outside the retention catalog")
+	}
+
+	report, err := policy.Run(ctx, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if report.Total() != 2 {
+		t.Fatalf("dry run touched %d rows, want 2", report.Total())
+	}
+	got, err := db.GetHourlyBooking(ctx, old.ID)
+	if err != nil {
+		t.Fatalf("GetHourlyBooking: %v", err)
+	}
+	if got.ClientPhone != "+7 999 123-45-67" {
+		t.Fatalf("dry run changed the phone: %q", got.ClientPhone)
+	}
+
+	if _, err = policy.Run(ctx, false); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	got, err = db.GetHourlyBooking(ctx, old.ID)
+	if err != nil {
+		t.Fatalf("GetHourlyBooking: %v", err)
+	}
+	if got.ClientPhone != "" || !strings.HasPrefix(got.ClientName, retention.HashPrefix) {
+		t.Fatalf("client not anonymized: %q %q", got.ClientName, got.ClientPhone)
+	}
+	var index string
+	if err = db.QueryRowContext(ctx, `SELECT client_phone_index FROM hourly_bookings WHERE id = ?`, old.ID).Scan(&index); err != nil {
+		t.Fatalf("select index: %v", err)
+	}
+	if index != "" {
+		t.Fatalf("phone index not cleared: %q", index)
+	}
+	if found, _ := db.ListBookingsByPhone(ctx, "+7 999 123-45-67", 10); len(found) != 0 {
+		t.Fatalf("cleared phone is still searchable: %+v", found)
+	}
+
+	logged, _, err := db.GetTableData(ctx, "retention_log")
+	if err != nil {
+		t.Fatalf("GetTableData: %v", err)
+	}
+	if len(logged) != 2 {
+		t.Fatalf("retention_log has %d rows, want 2", len(logged))
+	}
+}
@@ -0,0 +1,196 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"bronivik/bronivik_crm/internal/fieldcrypt"
+	"bronivik/bronivik_crm/internal/model"
+	"bronivik/bronivik_crm/internal/retention"
+)
+
+// SetFieldCipher enables encryption of client phones and names. With nil,
+// values are stored as is and encrypted rows can no longer be read.
+func (db *DB) SetFieldCipher(c *fieldcrypt.Cipher) {
+	db.fields = c
+}
+
+// sealedClient holds a booking's client name and phone as stored in the database.
+type sealedClient struct {
+	name       string
+	phone      string
+	phoneIndex string
+}
+
+func sealClient(c *fieldcrypt.Cipher, name, phone string) (sealedClient, error) {
+	var s sealedClient
+	var err error
+	if s.name, err = c.Encrypt(name); err != nil {
+		return s, fmt.Errorf("encrypt client name: %w", err)
+	}
+	if s.phone, err = c.Encrypt(phone); err != nil {
+		return s, fmt.Errorf("encrypt client phone: %w", err)
+	}
+	s.phoneIndex = c.BlindIndex(phone)
+	return s, nil
+}
+
+// openClient decrypts the client name and phone of a booking read from the database.
+func openClient(c *fieldcrypt.Cipher, b *model.HourlyBooking) error {
+	var err error
+	if b.ClientName, err = c.Decrypt(b.ClientName); err != nil {
+		return fmt.Errorf("booking %d: decrypt client name: %w", b.ID, err)
+	}
+	if b.ClientPhone, err = c.Decrypt(b.ClientPhone); err != nil {
+		return fmt.Errorf("booking %d: decrypt client phone: %w", b.ID, err)
+	}
+	return nil
+}
+
+// ListBookingsByPhone returns the bookings of a client phone, newest first.
+// Encrypted phones are matched through the blind index, so any format of the
+// number works; without encryption the phone is compared as is.
+func (db *DB) ListBookingsByPhone(ctx context.Context, phone string, limit int) ([]model.HourlyBooking, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	column, value := "client_phone_index", db.fields.BlindIndex(phone)
+	if db.fields == nil {
+		column, value = "client_phone", phone
+	}
+	if value == "" {
+		return nil, nil
+	}
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, user_id, cabinet_id, item_name, client_name, client_phone,
+		       start_time, end_time, status, comment, created_at, updated_at
+		FROM hourly_bookings WHERE `+column+` = ?
+		ORDER BY start_time DESC LIMIT ?`, value, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []model.HourlyBooking
+	for rows.Next() {
+		bk, err := scanHourly(rows, db.fields)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *bk)
+	}
+	return res, rows.Err()
+}
+
+// fieldRotation describes a table with encrypted columns.
+type fieldRotation struct {
+	table    string
+	columns  []string
+	phoneCol string // column whose blind index is kept in indexCol
+	indexCol string
+}
+
+var fieldRotations = []fieldRotation{
+	{table: "users", columns: []string{"phone"}},
+	{table: "hourly_bookings", columns: []string{"client_name", "client_phone"},
+		phoneCol: "client_phone", indexCol: "client_phone_index"},
+}
+
+// RotateFieldEncryption re-encrypts up to limit rows per table with the
+// primary key: legacy plaintext written before encryption was enabled and
+// values under older keys. The phone blind index is refreshed on the way.
+// It returns the number of updated rows; 0 means nothing is left to rotate.
+func (db *DB) RotateFieldEncryption(ctx context.Context, limit int) (int, error) {
+	if db.fields == nil {
+		return 0, nil
+	}
+	total := 0
+	for _, r := range fieldRotations {
+		n, err := db.rotateTable(ctx, r, limit)
+		total += n
+		if err != nil {
+			return total, fmt.Errorf("rotate %s: %w", r.table, err)
+		}
+	}
+	return total, nil
+}
+
+func (db *DB) rotateTable(ctx context.Context, r fieldRotation, limit int) (int, error) {
+	selectCols := "id"
+	where := ""
+	args := make([]interface{}, 0, 2*len(r.columns)+1)
+	for _, col := range r.columns {
+		selectCols += ", COALESCE(" + col + ", '')"
+		if where != "" {
+			where += " OR "
+		}
+		// Retention hashes are not personal data and stay unencrypted
+		where += "(COALESCE(" + col + ", '') <> '' AND " + col + " NOT LIKE ? AND " + col + " NOT LIKE ?)"
+		args = append(args, db.fields.PrimaryPrefix()+"%", retention.HashPrefix+"%")
+	}
+	args = append(args, limit)
+
+	rows, err := db.QueryContext(ctx, `SELECT `+selectCols+` FROM `+r.table+` WHERE `+where+` ORDER BY id LIMIT ?`, args...)
+	if err != nil {
+		return 0, err
+	}
+	type row struct {
+		id     int64
+		values []string
+	}
+	var pending []row
+	for rows.Next() {
+		rw := row{values: make([]string, len(r.columns))}
+		dest := []interface{}{&rw.id}
+		for i := range rw.values {
+			dest = append(dest, &rw.values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		pending = append(pending, rw)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, rw := range pending {
+		set := ""
+		where := "id = ?"
+		var setArgs []interface{}
+		whereArgs := []interface{}{rw.id}
+		for i, col := range r.columns {
+			plain, err := db.fields.Decrypt(rw.values[i])
+			if err != nil {
+				return updated, fmt.Errorf("row %d: %w", rw.id, err)
+			}
+			sealed, err := db.fields.Encrypt(plain)
+			if err != nil {
+				return updated, err
+			}
+			if set != "" {
+				set += ", "
+			}
+			set += col + " = ?"
+			setArgs = append(setArgs, sealed)
+			if col == r.phoneCol {
+				set += ", " + r.indexCol + " = ?"
+				setArgs = append(setArgs, db.fields.BlindIndex(plain))
+			}
+			// Rows changed concurrently are skipped and picked up by the next pass
+			where += " AND COALESCE(" + col + ", '') = ?"
+			whereArgs = append(whereArgs, rw.values[i])
+		}
+		res, err := db.ExecContext(ctx, `UPDATE `+r.table+` SET `+set+` WHERE `+where, append(setArgs, whereArgs...)...)
+		if err != nil {
+			return updated, err
+		}
+		if n, errRows := res.RowsAffected(); errRows == nil {
+			updated += int(n)
+		}
+	}
+	return updated, nil
+}
@@ -0,0 +1,34 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"bronivik/bronivik_crm/internal/migrate"
+	"bronivik/bronivik_crm/migrations"
+)
+
+// Migrator returns the versioned migration runner for the migrations directory.
+// The baseline schema is still created by createTables; migrations run on top of it.
+func (db *DB) Migrator(dryRun bool, out io.Writer) (*migrate.Runner, error) {
+	list, err := migrate.Load(migrations.FS, migrate.DialectSQLite)
+	if err != nil {
+		return nil, err
+	}
+	return migrate.New(db.DB, list, migrate.Options{DryRun: dryRun, Out: out})
+}
+
+// Migrate applies pending migrations on startup. It fails when the database
+// schema is newer than the binary or an applied migration was edited.
+func (db *DB) Migrate(ctx context.Context) (int, error) {
+	runner, err := db.Migrator(false, nil)
+	if err != nil {
+		return 0, err
+	}
+	applied, err := runner.Up(ctx)
+	if err != nil {
+		return applied, fmt.Errorf("apply migrations: %w", err)
+	}
+	return applied, nil
+}
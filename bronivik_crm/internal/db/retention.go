@@ -0,0 +1,43 @@
+package db
+
+import (
+	"bronivik/bronivik_crm/internal/retention"
+)
+
+// retentionCatalog lists the tables and personal data columns the retention
+// policy may touch. Bookings age from their end time.
+var retentionCatalog = []retention.Table{
+	{
+		Name: "users",
+		Age:  "updated_at",
+		Columns: map[string]retention.Column{
+			"username":   {},
+			"first_name": {},
+			"last_name":  {},
+			"phone":      {},
+		},
+	},
+	{
+		Name: "hourly_bookings",
+		Age:  "end_time",
+		Columns: map[string]retention.Column{
+			"client_name":     {},
+			"client_phone":    {Derived: []string{"client_phone_index"}},
+			"comment":         {},
+			"manager_comment": {},
+		},
+		Deletable: true,
+	},
+	{
+		Name:      "reminders",
+		Age:       "COALESCE(sent_at, scheduled_at)",
+		Deletable: true,
+	},
+}
+
+// RetentionEngine builds the retention policy engine for the database. Values
+// are hashed after decryption, so SetFieldCipher must be called first.
+func (db *DB) RetentionEngine(rules []retention.Rule, opts retention.Options) (*retention.Engine, error) {
+	opts.Decrypt = db.fields.Decrypt
+	return retention.New(db, retentionCatalog, rules, opts)
+}
@@ -313,7 +313,7 @@ func (db *DB) GetActiveBookingsOnDate(ctx context.Context, cabinetID int64, date
 
 	var bookings []model.HourlyBooking
 	for rows.Next() {
-		b, err := scanHourly(rows)
+		b, err := scanHourly(rows, db.fields)
 		if err != nil {
 			return nil, err
 		}
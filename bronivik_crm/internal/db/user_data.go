@@ -0,0 +1,175 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"bronivik/bronivik_crm/internal/fieldcrypt"
+	"bronivik/bronivik_crm/internal/model"
+)
+
+// userDataQueries lists the tables holding a user's data. Every placeholder
+// receives the internal users.id of the user.
+var userDataQueries = []struct {
+	table string
+	query string
+}{
+	{"users", `SELECT * FROM users WHERE id = ?`},
+	{"user_settings", `SELECT * FROM user_settings WHERE user_id = ?`},
+	{"hourly_bookings", `SELECT * FROM hourly_bookings WHERE user_id = ? ORDER BY id`},
+	{"reminders", `SELECT * FROM reminders
+		WHERE user_id = ? OR booking_id IN (SELECT id FROM hourly_bookings WHERE user_id = ?)
+		ORDER BY id`},
+}
+
+// ExportUserData collects every row related to the Telegram user: profile,
+// settings, hourly bookings and reminders. An unknown user yields an empty export.
+func (db *DB) ExportUserData(ctx context.Context, telegramID int64) (*model.UserDataExport, error) {
+	export := &model.UserDataExport{
+		Service:     "bronivik_crm",
+		TelegramID:  telegramID,
+		GeneratedAt: time.Now(),
+	}
+
+	var userID int64
+	err := db.QueryRowContext(ctx, `SELECT id FROM users WHERE telegram_id = ?`, telegramID).Scan(&userID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("find user: %w", err)
+	}
+
+	for _, q := range userDataQueries {
+		section := model.UserDataSection{Name: q.table}
+		if err == nil {
+			args := make([]interface{}, countPlaceholders(q.query))
+			for i := range args {
+				args[i] = userID
+			}
+			if section.Rows, section.Columns, err = db.queryRowMaps(ctx, q.query, args...); err != nil {
+				return nil, fmt.Errorf("export %s: %w", q.table, err)
+			}
+		}
+		export.Sections = append(export.Sections, section)
+	}
+
+	return export, nil
+}
+
+func (db *DB) queryRowMaps(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, []string, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	data, columns, err := scanRowMaps(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Text columns may come back as []byte, which JSON would encode as base64.
+	// Encrypted phones and names are handed to the user in plaintext.
+	for _, row := range data {
+		for col, v := range row {
+			if b, ok := v.([]byte); ok {
+				v = string(b)
+				row[col] = v
+			}
+			if str, ok := v.(string); ok && fieldcrypt.IsEncrypted(str) {
+				if row[col], err = db.fields.Decrypt(str); err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+	}
+	return data, columns, nil
+}
+
+// EraseUserData removes the personal data of the Telegram user. Settings and
+// reminders are deleted. Bookings keep their cabinet, times and status for
+// statistics, but client name, phone and comments are replaced or cleared.
+// The users row stays as an anonymous stub referenced by the bookings: its
+// telegram_id becomes -users.id, so a returning user starts from scratch.
+func (db *DB) EraseUserData(ctx context.Context, telegramID int64) (*model.ErasureResult, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var userID int64
+	if err = tx.QueryRowContext(ctx, `SELECT id FROM users WHERE telegram_id = ?`, telegramID).Scan(&userID); err != nil {
+		return nil, fmt.Errorf("find user: %w", err)
+	}
+
+	result := &model.ErasureResult{
+		TelegramID:  telegramID,
+		PseudonymID: -userID,
+		Pseudonym:   model.ErasedUserPseudonym(userID),
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM hourly_bookings WHERE user_id = ? ORDER BY id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list bookings: %w", err)
+	}
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		result.BookingIDs = append(result.BookingIDs, id)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if _, err = tx.ExecContext(ctx, `
+		UPDATE hourly_bookings
+		SET client_name = ?, client_phone = '', client_phone_index = '', comment = '', manager_comment = '', updated_at = ?
+		WHERE user_id = ?`, result.Pseudonym, now, userID); err != nil {
+		return nil, fmt.Errorf("pseudonymize bookings: %w", err)
+	}
+
+	for _, query := range []string{
+		`DELETE FROM reminders WHERE user_id = ? OR booking_id IN (SELECT id FROM hourly_bookings WHERE user_id = ?)`,
+		`DELETE FROM user_settings WHERE user_id = ?`,
+	} {
+		args := make([]interface{}, countPlaceholders(query))
+		for i := range args {
+			args[i] = userID
+		}
+		res, errExec := tx.ExecContext(ctx, query, args...)
+		if errExec != nil {
+			return nil, fmt.Errorf("delete user data: %w", errExec)
+		}
+		if n, errRows := res.RowsAffected(); errRows == nil {
+			result.DeletedRows += n
+		}
+	}
+
+	if _, err = tx.ExecContext(ctx, `
+		UPDATE users
+		SET telegram_id = ?, username = '', first_name = ?, last_name = '', phone = '', updated_at = ?
+		WHERE id = ?`, result.PseudonymID, result.Pseudonym, now, userID); err != nil {
+		return nil, fmt.Errorf("pseudonymize user: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// countPlaceholders counts the "?" placeholders in a query.
+func countPlaceholders(query string) int {
+	n := 0
+	for i := 0; i < len(query); i++ {
+		if query[i] == '?' {
+			n++
+		}
+	}
+	return n
+}
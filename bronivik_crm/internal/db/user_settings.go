@@ -13,13 +13,13 @@ import (
 func (db *DB) GetUserSettings(ctx context.Context, userID int64) (*model.UserSettings, error) {
 	row := db.QueryRowContext(ctx, `
 		SELECT id, user_id, reminders_enabled, reminder_hours_before, 
-		       created_at, updated_at
+		       language, created_at, updated_at
 		FROM user_settings
 		WHERE user_id = ?`, userID)
 
 	var s model.UserSettings
 	err := row.Scan(&s.ID, &s.UserID, &s.RemindersEnabled, &s.ReminderHoursBefore,
-		&s.CreatedAt, &s.UpdatedAt)
+		&s.Language, &s.CreatedAt, &s.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// Return default settings
@@ -49,6 +49,34 @@ func (db *DB) UpsertUserSettings(ctx context.Context, userID int64, remindersEna
 	return err
 }
 
+// GetUserLanguage returns the interface language chosen by the Telegram user,
+// or an empty string if they have not chosen one.
+func (db *DB) GetUserLanguage(ctx context.Context, telegramID int64) (string, error) {
+	var lang string
+	err := db.QueryRowContext(ctx, `
+		SELECT COALESCE(us.language, '')
+		FROM users u
+		LEFT JOIN user_settings us ON us.user_id = u.id
+		WHERE u.telegram_id = ?`, telegramID).Scan(&lang)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return lang, err
+}
+
+// SetUserLanguage stores the interface language for a user ID.
+func (db *DB) SetUserLanguage(ctx context.Context, userID int64, lang string) error {
+	now := time.Now()
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO user_settings (user_id, language, created_at, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET
+			language = excluded.language,
+			updated_at = excluded.updated_at`,
+		userID, lang, now, now)
+	return err
+}
+
 // ToggleReminders toggles reminder setting for a user and returns new state.
 func (db *DB) ToggleReminders(ctx context.Context, userID int64) (bool, error) {
 	settings, err := db.GetUserSettings(ctx, userID)
@@ -107,6 +135,9 @@ func (db *DB) GetUpcomingBookingsForReminders(ctx context.Context, within time.D
 		if extID.Valid {
 			b.ExternalDeviceBookingID = extID.Int64
 		}
+		if err := openClient(db.fields, &b); err != nil {
+			return nil, err
+		}
 		bookings = append(bookings, b)
 	}
 	return bookings, rows.Err()
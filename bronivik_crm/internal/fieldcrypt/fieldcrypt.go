@@ -0,0 +1,229 @@
+// Package fieldcrypt encrypts individual database fields (phones, client names)
+// with AES-256-GCM.
+//
+// Encrypted values are stored as text "enc:v1:<key id>:<base64(nonce|ciphertext)>",
+// so a key set can hold several keys at once: new values use the primary key,
+// older ones stay readable until a re-encryption job rewrites them. Values
+// without the prefix are treated as legacy plaintext and returned unchanged,
+// which lets encryption be switched on for an existing database.
+//
+// Encrypted values cannot be compared in SQL, so BlindIndex provides a keyed
+// HMAC of the normalized value for equality lookups (e.g. bookings by phone).
+//
+// A nil *Cipher is valid and means "encryption disabled": values pass through.
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	prefix  = "enc:v1:"
+	keySize = 32
+)
+
+// ErrUnknownKey is returned when a value was encrypted with a key that is not in the key set.
+var ErrUnknownKey = errors.New("fieldcrypt: unknown key id")
+
+// Key is one entry of the key set.
+type Key struct {
+	ID     string
+	Secret []byte // 32 bytes
+}
+
+// Cipher encrypts and decrypts field values with a rotating key set.
+type Cipher struct {
+	primary  string
+	aeads    map[string]cipher.AEAD
+	indexKey []byte
+}
+
+// New builds a cipher from the key set. primaryID selects the key for new
+// values; indexKey keys the blind index and must not change once indexes exist.
+func New(keys []Key, primaryID string, indexKey []byte) (*Cipher, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("fieldcrypt: no keys")
+	}
+	if len(indexKey) < keySize {
+		return nil, fmt.Errorf("fieldcrypt: index key must be at least %d bytes", keySize)
+	}
+	c := &Cipher{primary: primaryID, aeads: make(map[string]cipher.AEAD, len(keys)), indexKey: indexKey}
+	for _, k := range keys {
+		if k.ID == "" || strings.Contains(k.ID, ":") {
+			return nil, fmt.Errorf("fieldcrypt: invalid key id %q", k.ID)
+		}
+		if _, dup := c.aeads[k.ID]; dup {
+			return nil, fmt.Errorf("fieldcrypt: duplicate key id %q", k.ID)
+		}
+		if len(k.Secret) != keySize {
+			return nil, fmt.Errorf("fieldcrypt: key %q must be %d bytes", k.ID, keySize)
+		}
+		block, err := aes.NewCipher(k.Secret)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		c.aeads[k.ID] = aead
+	}
+	if _, ok := c.aeads[primaryID]; !ok {
+		return nil, fmt.Errorf("fieldcrypt: primary key %q is not in the key set", primaryID)
+	}
+	return c, nil
+}
+
+// DecodeKey decodes a key given as base64 (standard or URL alphabet) or hex.
+func DecodeKey(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if b, err := enc.DecodeString(s); err == nil && len(b) == keySize {
+			return b, nil
+		}
+	}
+	if b, err := hex.DecodeString(s); err == nil && len(b) == keySize {
+		return b, nil
+	}
+	return nil, fmt.Errorf("fieldcrypt: key must be %d bytes in base64 or hex", keySize)
+}
+
+// IsEncrypted reports whether the value has the encrypted format.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// PrimaryKeyID returns the id of the key used for new values.
+func (c *Cipher) PrimaryKeyID() string {
+	if c == nil {
+		return ""
+	}
+	return c.primary
+}
+
+// PrimaryPrefix returns the prefix of values encrypted with the primary key.
+// Rows whose value does not start with it need re-encryption; it is meant
+// for SQL filters like "phone NOT LIKE prefix || '%'".
+func (c *Cipher) PrimaryPrefix() string {
+	if c == nil {
+		return ""
+	}
+	return prefix + c.primary + ":"
+}
+
+// Encrypt encrypts the value with the primary key. Empty values stay empty.
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	if c == nil || plaintext == "" {
+		return plaintext, nil
+	}
+	aead := c.aeads[c.primary]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("fieldcrypt: nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return c.PrimaryPrefix() + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns the plaintext of an encrypted value. Values without the
+// encrypted prefix are returned unchanged.
+func (c *Cipher) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	if c == nil {
+		return "", errors.New("fieldcrypt: encrypted value but encryption is not configured")
+	}
+	keyID, payload, ok := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	if !ok {
+		return "", errors.New("fieldcrypt: malformed value")
+	}
+	aead, ok := c.aeads[keyID]
+	if !ok {
+		return "", fmt.Errorf("%w %q", ErrUnknownKey, keyID)
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("fieldcrypt: malformed value")
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("fieldcrypt: decrypt with key %q: %w", keyID, err)
+	}
+	return string(plain), nil
+}
+
+// NeedsRotation reports whether the value should be rewritten with the
+// primary key: it is plaintext or was encrypted with an older key.
+func (c *Cipher) NeedsRotation(value string) bool {
+	return c != nil && value != "" && !strings.HasPrefix(value, c.PrimaryPrefix())
+}
+
+// Reencrypt decrypts the value with whatever key it was encrypted with and
+// encrypts it again with the primary key.
+func (c *Cipher) Reencrypt(value string) (string, error) {
+	plain, err := c.Decrypt(value)
+	if err != nil {
+		return "", err
+	}
+	return c.Encrypt(plain)
+}
+
+// BlindIndex returns a keyed hash of the normalized phone number for equality
+// lookups. It returns "" when encryption is disabled or the phone is empty.
+func (c *Cipher) BlindIndex(phone string) string {
+	normalized := NormalizePhone(phone)
+	if c == nil || normalized == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(normalized))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// NormalizePhone keeps only digits and treats the Russian trunk prefix 8 as +7,
+// so "+7 999 123-45-67" and "89991234567" have the same blind index.
+func NormalizePhone(phone string) string {
+	var sb strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			sb.WriteRune(r)
+		}
+	}
+	digits := sb.String()
+	if len(digits) == 11 && digits[0] == '8' {
+		digits = "7" + digits[1:]
+	}
+	return digits
+}
+
+// MaskPhone hides all but the last four digits, for exports that must not
+// contain decrypted phones.
+func MaskPhone(phone string) string {
+	digits := NormalizePhone(phone)
+	if digits == "" {
+		return ""
+	}
+	if len(digits) <= 4 {
+		return strings.Repeat("*", len(digits))
+	}
+	return strings.Repeat("*", len(digits)-4) + digits[len(digits)-4:]
+}
+
+// MaskName keeps the first letter of each word, e.g. "Иванов Иван" becomes "И. И.".
+func MaskName(name string) string {
+	words := strings.Fields(name)
+	for i, w := range words {
+		words[i] = string([]rune(w)[:1]) + "."
+	}
+	return strings.Join(words, " ")
+}
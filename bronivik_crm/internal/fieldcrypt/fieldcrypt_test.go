@@ -0,0 +1,143 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, keySize)
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	c, err := New([]Key{{ID: "k1", Secret: testKey(1)}}, "k1", testKey(9))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	enc, err := c.Encrypt("+79991234567")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if !strings.HasPrefix(enc, "enc:v1:k1:") || strings.Contains(enc, "9991234567") {
+		t.Fatalf("unexpected ciphertext %q", enc)
+	}
+	again, _ := c.Encrypt("+79991234567")
+	if again == enc {
+		t.Fatalf("nonce must make ciphertexts differ")
+	}
+	if plain, err := c.Decrypt(enc); err != nil || plain != "+79991234567" {
+		t.Fatalf("Decrypt: %q %v", plain, err)
+	}
+
+	// Legacy plaintext and empty values pass through
+	if plain, err := c.Decrypt("89991234567"); err != nil || plain != "89991234567" {
+		t.Fatalf("plaintext: %q %v", plain, err)
+	}
+	if enc, _ := c.Encrypt(""); enc != "" {
+		t.Fatalf("empty value must stay empty, got %q", enc)
+	}
+
+	tampered := enc[:len(enc)-2] + "AA"
+	if _, err := c.Decrypt(tampered); err == nil {
+		t.Fatalf("tampered value must not decrypt")
+	}
+}
+
+func TestRotation(t *testing.T) {
+	old, err := New([]Key{{ID: "k1", Secret: testKey(1)}}, "k1", testKey(9))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	enc, _ := old.Encrypt("Иванов Иван")
+
+	rotated, err := New([]Key{{ID: "k1", Secret: testKey(1)}, {ID: "k2", Secret: testKey(2)}}, "k2", testKey(9))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if !rotated.NeedsRotation(enc) || !rotated.NeedsRotation("plain") || rotated.NeedsRotation("") {
+		t.Fatalf("NeedsRotation mismatch")
+	}
+	re, err := rotated.Reencrypt(enc)
+	if err != nil {
+		t.Fatalf("Reencrypt: %v", err)
+	}
+	if !strings.HasPrefix(re, rotated.PrimaryPrefix()) || rotated.NeedsRotation(re) {
+		t.Fatalf("value not rotated: %q", re)
+	}
+	if plain, _ := rotated.Decrypt(re); plain != "Иванов Иван" {
+		t.Fatalf("unexpected plaintext %q", plain)
+	}
+
+	// Once the old key is removed, its values can no longer be read
+	if _, err := old.Decrypt(re); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected ErrUnknownKey, got %v", err)
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	c, _ := New([]Key{{ID: "k1", Secret: testKey(1)}}, "k1", testKey(9))
+	other, _ := New([]Key{{ID: "k1", Secret: testKey(1)}}, "k1", testKey(8))
+
+	idx := c.BlindIndex("+7 999 123-45-67")
+	if idx == "" || idx != c.BlindIndex("89991234567") {
+		t.Fatalf("equivalent phones must share the index")
+	}
+	if idx == c.BlindIndex("+79991234568") || idx == other.BlindIndex("89991234567") {
+		t.Fatalf("index must depend on the phone and the key")
+	}
+	if c.BlindIndex("") != "" {
+		t.Fatalf("empty phone must have no index")
+	}
+}
+
+func TestNilCipherPassesThrough(t *testing.T) {
+	var c *Cipher
+	if v, err := c.Encrypt("123"); err != nil || v != "123" {
+		t.Fatalf("Encrypt: %q %v", v, err)
+	}
+	if v, err := c.Decrypt("123"); err != nil || v != "123" {
+		t.Fatalf("Decrypt: %q %v", v, err)
+	}
+	if _, err := c.Decrypt("enc:v1:k1:AAAA"); err == nil {
+		t.Fatalf("encrypted value without a key set must fail")
+	}
+	if c.BlindIndex("123") != "" || c.NeedsRotation("123") {
+		t.Fatalf("nil cipher must not index or rotate")
+	}
+}
+
+func TestNewValidation(t *testing.T) {
+	if _, err := New(nil, "k1", testKey(9)); err == nil {
+		t.Fatalf("empty key set must fail")
+	}
+	if _, err := New([]Key{{ID: "k1", Secret: testKey(1)}}, "k2", testKey(9)); err == nil {
+		t.Fatalf("missing primary key must fail")
+	}
+	if _, err := New([]Key{{ID: "k1", Secret: []byte("short")}}, "k1", testKey(9)); err == nil {
+		t.Fatalf("short key must fail")
+	}
+	if _, err := New([]Key{{ID: "k1", Secret: testKey(1)}}, "k1", nil); err == nil {
+		t.Fatalf("missing index key must fail")
+	}
+}
+
+func TestDecodeKeyAndMasks(t *testing.T) {
+	if _, err := DecodeKey("AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE="); err != nil {
+		t.Fatalf("base64: %v", err)
+	}
+	if _, err := DecodeKey(strings.Repeat("ab", keySize)); err != nil {
+		t.Fatalf("hex: %v", err)
+	}
+	if _, err := DecodeKey("too-short"); err == nil {
+		t.Fatalf("short key must fail")
+	}
+	if got := MaskPhone("+7 999 123-45-67"); got != "*******4567" {
+		t.Fatalf("MaskPhone: %q", got)
+	}
+	if got := MaskName("Иванов Иван"); got != "И. И." {
+		t.Fatalf("MaskName: %q", got)
+	}
+}
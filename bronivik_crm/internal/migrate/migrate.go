@@ -0,0 +1,456 @@
+// Package migrate applies versioned SQL migrations and records them in a
+// schema_migrations table with checksums.
+//
+// The canonical source is shared/migrate; bronivik_jr and bronivik_crm keep
+// identical copies in internal/migrate because they are built as separate modules.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Dialects supported by the runner.
+const (
+	DialectSQLite   = "sqlite"
+	DialectPostgres = "postgres"
+)
+
+// DefaultTable is the name of the table with applied migrations.
+const DefaultTable = "schema_migrations"
+
+var (
+	// ErrSchemaNewer means the database has migrations this binary does not know about.
+	ErrSchemaNewer = errors.New("database schema is newer than the application")
+	// ErrChecksumMismatch means an applied migration was edited after it was applied.
+	ErrChecksumMismatch = errors.New("migration checksum mismatch")
+	// ErrNoDown means a migration cannot be rolled back.
+	ErrNoDown = errors.New("migration has no down script")
+	// ErrUnknownVersion means the target version has no migration file.
+	ErrUnknownVersion = errors.New("unknown migration version")
+)
+
+// fileRe matches NNN_name.up.sql, NNN_name.down.sql and dialect-specific
+// variants such as NNN_name.postgres.up.sql.
+var fileRe = regexp.MustCompile(`^(\d+)_([A-Za-z0-9_]+?)(?:\.(sqlite|postgres))?\.(up|down)\.sql$`)
+
+var tableRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Migration is one schema version with its up and down scripts.
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// Status describes a migration known to the binary or recorded in the database.
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	// Modified is set when the applied checksum differs from the file.
+	Modified bool
+	// Unknown is set for applied versions that have no migration file.
+	Unknown bool
+}
+
+// Options configure a Runner.
+type Options struct {
+	// Table defaults to DefaultTable.
+	Table string
+	// Dialect defaults to DialectSQLite.
+	Dialect string
+	// DryRun prints the scripts to Out instead of executing them.
+	DryRun bool
+	// Out receives dry-run output; io.Discard when nil.
+	Out io.Writer
+}
+
+// Load reads migrations from the root of fsys. A dialect-specific file takes
+// precedence over the generic one with the same version and direction.
+func Load(fsys fs.FS, dialect string) ([]Migration, error) {
+	if dialect == "" {
+		dialect = DialectSQLite
+	}
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	specific := make(map[string]bool)
+	for _, e := range entries {
+		m := fileRe.FindStringSubmatch(e.Name())
+		if e.IsDir() || m == nil {
+			continue
+		}
+		if m[3] != "" && m[3] != dialect {
+			continue
+		}
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version in %s", e.Name())
+		}
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, mig.Name, m[2])
+		}
+
+		// A dialect-specific script wins over the generic one
+		key := m[1] + "." + m[4]
+		if specific[key] && m[3] == "" {
+			continue
+		}
+		specific[key] = m[3] != ""
+
+		body, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", e.Name(), err)
+		}
+		if m[4] == "up" {
+			mig.Up = string(body)
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", mig.Version, mig.Name)
+		}
+		sum := sha256.Sum256([]byte(mig.Up))
+		mig.Checksum = hex.EncodeToString(sum[:])
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Runner applies migrations to a database.
+type Runner struct {
+	db         *sql.DB
+	migrations []Migration
+	opts       Options
+}
+
+// New creates a runner. Migrations must be sorted by version, as returned by Load.
+func New(db *sql.DB, migrations []Migration, opts Options) (*Runner, error) {
+	if opts.Table == "" {
+		opts.Table = DefaultTable
+	}
+	if !tableRe.MatchString(opts.Table) {
+		return nil, fmt.Errorf("invalid migration table name %q", opts.Table)
+	}
+	if opts.Dialect == "" {
+		opts.Dialect = DialectSQLite
+	}
+	if opts.Dialect != DialectSQLite && opts.Dialect != DialectPostgres {
+		return nil, fmt.Errorf("unknown migration dialect %q", opts.Dialect)
+	}
+	if opts.Out == nil {
+		opts.Out = io.Discard
+	}
+	return &Runner{db: db, migrations: migrations, opts: opts}, nil
+}
+
+// Latest returns the newest version known to the binary, 0 without migrations.
+func (r *Runner) Latest() int64 {
+	if len(r.migrations) == 0 {
+		return 0
+	}
+	return r.migrations[len(r.migrations)-1].Version
+}
+
+// Version returns the newest applied version, 0 when nothing is applied.
+func (r *Runner) Version(ctx context.Context) (int64, error) {
+	applied, err := r.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var version int64
+	for v := range applied {
+		if v > version {
+			version = v
+		}
+	}
+	return version, nil
+}
+
+// Status lists known migrations and applied versions without files, by version.
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	applied, err := r.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Status, 0, len(r.migrations))
+	known := make(map[int64]bool, len(r.migrations))
+	for _, m := range r.migrations {
+		known[m.Version] = true
+		st := Status{Version: m.Version, Name: m.Name}
+		if a, ok := applied[m.Version]; ok {
+			st.Applied = true
+			st.AppliedAt = a.appliedAt
+			st.Modified = a.checksum != m.Checksum
+		}
+		result = append(result, st)
+	}
+	for v, a := range applied {
+		if !known[v] {
+			result = append(result, Status{Version: v, Name: a.name, Applied: true, AppliedAt: a.appliedAt, Unknown: true})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+// Check returns ErrSchemaNewer when the database has versions newer than the
+// binary and ErrChecksumMismatch when an applied migration was edited.
+func (r *Runner) Check(ctx context.Context) error {
+	statuses, err := r.Status(ctx)
+	if err != nil {
+		return err
+	}
+	for _, st := range statuses {
+		switch {
+		case st.Unknown && st.Version > r.Latest():
+			return fmt.Errorf("%w: database is at version %d, application knows up to %d",
+				ErrSchemaNewer, st.Version, r.Latest())
+		case st.Unknown:
+			return fmt.Errorf("applied migration %d_%s has no file", st.Version, st.Name)
+		case st.Modified:
+			return fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, st.Version, st.Name)
+		}
+	}
+	return nil
+}
+
+// Up applies all pending migrations and returns how many were applied.
+func (r *Runner) Up(ctx context.Context) (int, error) {
+	return r.Goto(ctx, r.Latest())
+}
+
+// Down rolls back the given number of applied migrations, newest first.
+func (r *Runner) Down(ctx context.Context, steps int) (int, error) {
+	applied, err := r.prepare(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var down []*Migration
+	for i := len(r.migrations) - 1; i >= 0 && len(down) < steps; i-- {
+		if _, ok := applied[r.migrations[i].Version]; ok {
+			down = append(down, &r.migrations[i])
+		}
+	}
+	return r.apply(ctx, down, nil)
+}
+
+// Goto applies or rolls back migrations until version is the newest applied one.
+// Version 0 rolls back everything.
+func (r *Runner) Goto(ctx context.Context, version int64) (int, error) {
+	if version != 0 && r.find(version) == nil {
+		return 0, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+	applied, err := r.prepare(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var down, up []*Migration
+	for i := len(r.migrations) - 1; i >= 0; i-- {
+		if _, ok := applied[r.migrations[i].Version]; ok && r.migrations[i].Version > version {
+			down = append(down, &r.migrations[i])
+		}
+	}
+	for i := range r.migrations {
+		if _, ok := applied[r.migrations[i].Version]; !ok && r.migrations[i].Version <= version {
+			up = append(up, &r.migrations[i])
+		}
+	}
+	return r.apply(ctx, down, up)
+}
+
+// prepare checks the database state and creates the migrations table.
+func (r *Runner) prepare(ctx context.Context) (map[int64]appliedRow, error) {
+	if err := r.Check(ctx); err != nil {
+		return nil, err
+	}
+	applied, err := r.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return applied, r.ensureTable(ctx)
+}
+
+// apply rolls back down in order, then applies up in order.
+func (r *Runner) apply(ctx context.Context, down, up []*Migration) (int, error) {
+	for _, m := range down {
+		if m.Down == "" {
+			return 0, fmt.Errorf("%w: %d_%s", ErrNoDown, m.Version, m.Name)
+		}
+	}
+	count := 0
+	for _, m := range down {
+		done, err := r.run(ctx, m, false)
+		if err != nil {
+			return count, err
+		}
+		if done {
+			count++
+		}
+	}
+	for _, m := range up {
+		done, err := r.run(ctx, m, true)
+		if err != nil {
+			return count, err
+		}
+		if done {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// run applies or rolls back one migration in a transaction together with its
+// schema_migrations row. The row is written first, so a concurrent runner
+// waits for the lock and then skips the migration; done is false in that case.
+func (r *Runner) run(ctx context.Context, m *Migration, up bool) (done bool, err error) {
+	script, direction := m.Up, "up"
+	if !up {
+		script, direction = m.Down, "down"
+	}
+	if r.opts.DryRun {
+		fmt.Fprintf(r.opts.Out, "-- %s %d_%s\n%s\n", direction, m.Version, m.Name, script)
+		return true, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("begin migration %d: %w", m.Version, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var result sql.Result
+	if up {
+		result, err = tx.ExecContext(ctx, fmt.Sprintf(
+			`INSERT INTO %s (version, name, checksum, applied_at) VALUES (%s, %s, %s, %s)
+			 ON CONFLICT(version) DO NOTHING`,
+			r.opts.Table, r.ph(1), r.ph(2), r.ph(3), r.ph(4)),
+			m.Version, m.Name, m.Checksum, time.Now().UTC())
+	} else {
+		result, err = tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE version = %s`, r.opts.Table, r.ph(1)), m.Version)
+	}
+	if err != nil {
+		return false, fmt.Errorf("record migration %d: %w", m.Version, err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return false, fmt.Errorf("migration %d_%s %s: %w", m.Version, m.Name, direction, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit migration %d: %w", m.Version, err)
+	}
+	return true, nil
+}
+
+type appliedRow struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+func (r *Runner) applied(ctx context.Context) (map[int64]appliedRow, error) {
+	exists, err := r.tableExists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int64]appliedRow)
+	if !exists {
+		return result, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(
+		`SELECT version, name, checksum, applied_at FROM %s`, r.opts.Table))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", r.opts.Table, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var version int64
+		var row appliedRow
+		if err := rows.Scan(&version, &row.name, &row.checksum, &row.appliedAt); err != nil {
+			return nil, fmt.Errorf("read %s: %w", r.opts.Table, err)
+		}
+		result[version] = row
+	}
+	return result, rows.Err()
+}
+
+func (r *Runner) tableExists(ctx context.Context) (bool, error) {
+	query := `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`
+	if r.opts.Dialect == DialectPostgres {
+		query = `SELECT COUNT(*) FROM information_schema.tables
+		         WHERE table_schema = current_schema() AND table_name = $1`
+	}
+	var n int
+	if err := r.db.QueryRowContext(ctx, query, r.opts.Table).Scan(&n); err != nil {
+		return false, fmt.Errorf("check %s: %w", r.opts.Table, err)
+	}
+	return n > 0, nil
+}
+
+func (r *Runner) ensureTable(ctx context.Context) error {
+	if r.opts.DryRun {
+		return nil
+	}
+	appliedAt := "DATETIME"
+	if r.opts.Dialect == DialectPostgres {
+		appliedAt = "TIMESTAMPTZ"
+	}
+	_, err := r.db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		version BIGINT PRIMARY KEY,
+		name TEXT NOT NULL,
+		checksum TEXT NOT NULL,
+		applied_at %s NOT NULL
+	)`, r.opts.Table, appliedAt))
+	if err != nil {
+		return fmt.Errorf("create %s: %w", r.opts.Table, err)
+	}
+	return nil
+}
+
+func (r *Runner) find(version int64) *Migration {
+	for i := range r.migrations {
+		if r.migrations[i].Version == version {
+			return &r.migrations[i]
+		}
+	}
+	return nil
+}
+
+func (r *Runner) ph(n int) string {
+	if r.opts.Dialect == DialectPostgres {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
@@ -8,6 +8,7 @@ type UserSettings struct {
 	UserID              int64     `json:"user_id"`
 	RemindersEnabled    bool      `json:"reminders_enabled"`
 	ReminderHoursBefore int       `json:"reminder_hours_before"`
+	Language            string    `json:"language"` // chosen via /language; empty means the Telegram language
 	CreatedAt           time.Time `json:"created_at"`
 	UpdatedAt           time.Time `json:"updated_at"`
 }
@@ -0,0 +1,6 @@
+-- Rollback: Drop the client phone blind index
+-- /find_phone stops finding encrypted phones
+
+DROP INDEX IF EXISTS idx_hourly_bookings_phone_index;
+
+ALTER TABLE hourly_bookings DROP COLUMN client_phone_index;
@@ -0,0 +1,7 @@
+-- Migration: Add client phone blind index
+-- Description: Keyed hash of the normalized client phone, so /find_phone keeps
+-- working when phones are encrypted at rest. Empty until encryption is enabled.
+
+ALTER TABLE hourly_bookings ADD COLUMN client_phone_index TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_hourly_bookings_phone_index ON hourly_bookings(client_phone_index);
@@ -0,0 +1,4 @@
+-- Rollback: Drop retention_log table
+-- WARNING: This will delete the record of past retention runs
+
+DROP TABLE IF EXISTS retention_log;
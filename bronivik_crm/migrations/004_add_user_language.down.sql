@@ -0,0 +1,4 @@
+-- Rollback: Drop the chosen interface language
+-- Users get the language of their Telegram client again
+
+ALTER TABLE user_settings DROP COLUMN language;
@@ -0,0 +1,8 @@
+// Package migrations embeds the versioned SQL migrations of bronivik_crm.
+// Files are named NNN_name.up.sql / NNN_name.down.sql.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -18,6 +18,7 @@ import (
 	"bronivik/internal/logging"
 	"bronivik/internal/metrics"
 	"bronivik/internal/models"
+	"bronivik/internal/service"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
@@ -68,11 +69,37 @@ func run() error {
 		return err
 	}
 
+	if redisClient != nil {
+		grpcServer.UseRedis(redisClient)
+	}
+
+	bookingService := service.NewBookingService(db, nil, cfg.Bot.MaxBookingDays, cfg.Bot.MinBookingAdvance, &logger)
+	grpcServer.SetBookingService(bookingService)
+
 	httpServer := api.NewHTTPServer(&cfg.API, db, redisClient, sheetsService, &logger)
+	httpServer.SetBookingService(bookingService)
+	httpServer.SetWebhookService(service.NewWebhookService(db, &logger))
+	httpServer.SetAPIKeyService(service.NewAPIKeyService(db, time.Duration(cfg.API.Auth.RotationGraceHours)*time.Hour, &logger))
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	// Ключи из базы перечитываются по таймеру: изменения от бота подхватываются без перезапуска
+	apiKeys := api.NewAPIKeyStore(db, time.Duration(cfg.API.Auth.ReloadIntervalSeconds)*time.Second, &logger)
+	if err := apiKeys.Reload(ctx); err != nil {
+		logger.Error().Err(err).Msg("load api keys")
+		return err
+	}
+	go apiKeys.Start(ctx)
+	grpcServer.SetAPIKeys(apiKeys)
+	httpServer.SetAPIKeys(apiKeys)
+
+	// Потоки доступности закрываются по ctx, не задерживая остановку серверов
+	watcher := api.NewAvailabilityWatcher(db, cfg.API.Stream, &logger)
+	go watcher.Start(ctx)
+	grpcServer.SetAvailabilityWatcher(watcher)
+	httpServer.SetAvailabilityWatcher(watcher)
+
 	startMetrics(ctx, cfg, &logger)
 
 	return startServers(ctx, grpcServer, httpServer, cfg, &logger)
@@ -121,9 +148,21 @@ func loadItems(logger *zerolog.Logger) ([]models.Item, error) {
 }
 
 func initDatabase(cfg *config.Config, items []models.Item, logger *zerolog.Logger) (*database.DB, error) {
-	db, err := database.NewDB(cfg.Database.Path, logger)
+	db, err := database.Open(&cfg.Database, logger)
+	if err != nil {
+		logger.Error().Err(err).Str("driver", cfg.Database.Driver).Str("db_path", cfg.Database.Path).Msg("init database")
+		return nil, err
+	}
+	fields, err := cfg.Encryption.Cipher()
 	if err != nil {
-		logger.Error().Err(err).Str("db_path", cfg.Database.Path).Msg("init database")
+		logger.Error().Err(err).Msg("init field encryption")
+		_ = db.Close()
+		return nil, err
+	}
+	db.SetFieldCipher(fields)
+	if err := db.Migrate(context.Background(), cfg.Database.Postgres.MigrationTable); err != nil {
+		logger.Error().Err(err).Msg("apply migrations")
+		_ = db.Close()
 		return nil, err
 	}
 
@@ -171,6 +210,7 @@ func initGoogleSheets(cfg *config.Config, logger *zerolog.Logger) *google.Sheets
 		return nil
 	}
 
+	sheetsService.SetMaskPersonalData(!cfg.Encryption.DecryptsExport(config.ExportSheets))
 	logger.Info().Msg("google sheets connected")
 	return sheetsService
 }
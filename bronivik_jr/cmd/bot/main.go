@@ -3,11 +3,14 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -21,6 +24,7 @@ import (
 	"bronivik/internal/models"
 	"bronivik/internal/repository"
 	"bronivik/internal/service"
+	"bronivik/internal/tgwebhook"
 	"bronivik/internal/worker"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -62,6 +66,10 @@ func run() error {
 		return err
 	}
 
+	if sheetsService != nil {
+		sheetsService.SetMaskPersonalData(!cfg.Encryption.DecryptsExport(config.ExportSheets))
+	}
+
 	redisClient, stateService := initStateService(ctx, cfg, &logger)
 
 	// Запускаем воркер синхронизации Google Sheets
@@ -73,16 +81,67 @@ func run() error {
 	}
 
 	eventBus := events.NewEventBus()
-	subscribeBookingEvents(ctx, eventBus, db, sheetsWorker, &logger)
+	// Подписчики вызываются диспетчером событий, их изменения записываются от имени системы
+	dispatcherCtx := systemContext(ctx, "event_dispatcher")
+	subscribeBookingEvents(dispatcherCtx, eventBus, db, sheetsWorker, &logger)
 
 	// Инициализация бизнес-сервисов
-	bookingService := service.NewBookingService(db, eventBus, sheetsWorker, cfg.Bot.MaxBookingDays, cfg.Bot.MinBookingAdvance, &logger)
+	bookingService := service.NewBookingService(db, sheetsWorker, cfg.Bot.MaxBookingDays, cfg.Bot.MinBookingAdvance, &logger)
 	userService := service.NewUserService(db, cfg, &logger)
 	itemService := service.NewItemService(db, &logger)
+	waitlistService := service.NewWaitlistService(db, bookingService, time.Duration(cfg.Bot.WaitlistOfferMinutes)*time.Minute, &logger)
+	subscribeWaitlistEvents(dispatcherCtx, eventBus, db, waitlistService, &logger)
+	go waitlistService.Start(systemContext(ctx, "waitlist"))
+	webhookService := service.NewWebhookService(db, &logger)
+	apiKeyService := service.NewAPIKeyService(db, time.Duration(cfg.API.Auth.RotationGraceHours)*time.Hour, &logger)
+	startWebhookWorker(ctx, cfg, eventBus, db, &logger)
+
+	// Перешифровка данных, записанных до включения шифрования или старыми ключами
+	if cfg.Encryption.Enabled {
+		encryptionWorker := worker.NewEncryptionWorker(db,
+			time.Duration(cfg.Encryption.RotationIntervalMinutes)*time.Minute, cfg.Encryption.RotationBatchSize, &logger)
+		go encryptionWorker.Start(ctx)
+	}
+
+	// Политика хранения: очистка и хеширование персональных данных в старых записях
+	if cfg.Retention.Enabled {
+		engine, err := db.RetentionEngine(cfg.Retention.Policy(), cfg.Retention.Options())
+		if err != nil {
+			return fmt.Errorf("retention policy: %w", err)
+		}
+		retentionWorker := worker.NewRetentionWorker(engine,
+			time.Duration(cfg.Retention.IntervalHours)*time.Hour, cfg.Retention.DryRun, &logger)
+		go retentionWorker.Start(systemContext(ctx, "retention"))
+	}
+
+	// События пишутся в outbox вместе с изменением заявки (в т.ч. процессом API), бот доставляет их подписчикам
+	dispatcher := events.NewDispatcher(db, eventBus, events.DispatcherConfig{
+		PollInterval: time.Duration(cfg.Events.PollIntervalSeconds) * time.Second,
+		BatchSize:    cfg.Events.BatchSize,
+		MaxAttempts:  cfg.Events.MaxAttempts,
+		RetryDelay:   time.Duration(cfg.Events.RetryDelaySeconds) * time.Second,
+		Retention:    time.Duration(cfg.Events.RetentionDays) * 24 * time.Hour,
+	}, &logger)
+	if err := replayEvents(dispatcherCtx, dispatcher, &logger); err != nil {
+		return err
+	}
+	go dispatcher.Start(dispatcherCtx)
 	metrics := bot.NewMetrics()
 
 	if cfg.API.Enabled {
 		apiServer := api.NewHTTPServer(&cfg.API, db, redisClient, sheetsService, &logger)
+		apiServer.SetBookingService(bookingService)
+		apiServer.SetWebhookService(webhookService)
+		apiServer.SetAPIKeyService(apiKeyService)
+		apiKeys := api.NewAPIKeyStore(db, time.Duration(cfg.API.Auth.ReloadIntervalSeconds)*time.Second, &logger)
+		if err := apiKeys.Reload(ctx); err != nil {
+			return fmt.Errorf("load api keys: %w", err)
+		}
+		go apiKeys.Start(ctx)
+		apiServer.SetAPIKeys(apiKeys)
+		watcher := api.NewAvailabilityWatcher(db, cfg.API.Stream, &logger)
+		go watcher.Start(ctx)
+		apiServer.SetAvailabilityWatcher(watcher)
 		go func() {
 			if err := apiServer.Start(); err != nil {
 				logger.Error().Err(err).Msg("API server error")
@@ -94,11 +153,43 @@ func run() error {
 	}
 
 	if cfg.Backup.Enabled {
-		backupService := database.NewBackupService(cfg.Database.Path, cfg.Backup, &logger)
-		go backupService.Start(ctx)
+		// Встроенный бэкап копирует файл SQLite; PostgreSQL резервируется средствами сервера (pg_dump)
+		if cfg.Database.Driver == config.DatabaseDriverPostgres {
+			logger.Warn().Msg("Backup service is skipped for PostgreSQL; use pg_dump or server-side backups")
+		} else {
+			backupService := database.NewBackupService(cfg.Database.Path, cfg.Backup, &logger)
+			go backupService.Start(ctx)
+		}
+	}
+
+	return startBot(ctx, cfg, stateService, sheetsService, sheetsWorker, eventBus, bookingService, userService, itemService,
+		waitlistService, webhookService, apiKeyService, metrics, &logger)
+}
+
+// systemContext помечает изменения фоновой задачи name как системные.
+func systemContext(ctx context.Context, name string) context.Context {
+	return models.WithActor(ctx, models.Actor{Type: models.ActorSystem, Name: name})
+}
+
+// replayEvents повторно доставляет подписчикам события начиная с REPLAY_EVENTS_FROM,
+// включая уже обработанные (например, после сбоя Google Sheets).
+func replayEvents(ctx context.Context, dispatcher *events.Dispatcher, logger *zerolog.Logger) error {
+	fromStr := os.Getenv("REPLAY_EVENTS_FROM")
+	if fromStr == "" {
+		return nil
+	}
+	fromID, err := strconv.ParseInt(fromStr, 10, 64)
+	if err != nil || fromID <= 0 {
+		return fmt.Errorf("invalid REPLAY_EVENTS_FROM %q", fromStr)
 	}
 
-	return startBot(ctx, cfg, stateService, sheetsService, sheetsWorker, eventBus, bookingService, userService, itemService, metrics, &logger)
+	lastID, err := dispatcher.Replay(ctx, fromID, nil)
+	if err != nil {
+		logger.Error().Err(err).Int64("from_id", fromID).Int64("last_id", lastID).Msg("Ошибка повторной доставки событий")
+		return err
+	}
+	logger.Info().Int64("from_id", fromID).Int64("last_id", lastID).Msg("События доставлены повторно")
+	return nil
 }
 
 func loadConfigAndLogger() (*config.Config, []models.Item, zerolog.Logger, io.Closer, error) {
@@ -160,11 +251,22 @@ func prepareDirectories(cfg *config.Config, logger *zerolog.Logger) error {
 }
 
 func initDatabase(cfg *config.Config, items []models.Item, logger *zerolog.Logger) (*database.DB, error) {
-	db, err := database.NewDB(cfg.Database.Path, logger)
+	db, err := database.Open(&cfg.Database, logger)
 	if err != nil {
 		logger.Error().Err(err).Msg("Ошибка инициализации базы данных")
 		return nil, err
 	}
+	fields, err := cfg.Encryption.Cipher()
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("encryption keys: %w", err)
+	}
+	db.SetFieldCipher(fields)
+	if err := db.Migrate(context.Background(), cfg.Database.Postgres.MigrationTable); err != nil {
+		logger.Error().Err(err).Msg("Ошибка применения миграций")
+		_ = db.Close()
+		return nil, err
+	}
 
 	if err := db.SyncItems(context.Background(), items); err != nil {
 		logger.Error().Err(err).Msg("Ошибка синхронизации позиций")
@@ -222,6 +324,9 @@ func startBot(
 	bookingService *service.BookingService,
 	userService *service.UserService,
 	itemService *service.ItemService,
+	waitlistService *service.WaitlistService,
+	webhookService *service.WebhookService,
+	apiKeyService *service.APIKeyService,
 	metrics *bot.Metrics,
 	logger *zerolog.Logger,
 ) error {
@@ -248,10 +353,33 @@ func startBot(
 		logger.Error().Err(err).Msg("Ошибка создания бота")
 		return err
 	}
+	telegramBot.SetWaitlistService(waitlistService)
+	telegramBot.SetWebhookService(webhookService)
+	telegramBot.SetAPIKeyService(apiKeyService)
+	waitlistService.SetNotifier(telegramBot)
+	eventBus.Subscribe(events.EventBookingRescheduled, telegramBot.NotifyBookingRescheduled)
 
 	logger.Info().Msg("Бот запущен...")
 	telegramBot.StartReminders(ctx)
-	telegramBot.Start(ctx)
+	if cfg.Telegram.WebhookURL == "" {
+		telegramBot.Start(ctx)
+	} else {
+		receiver, err := tgwebhook.New(tgwebhook.Config{
+			URL:      cfg.Telegram.WebhookURL,
+			Secret:   cfg.Telegram.WebhookSecret,
+			Listen:   cfg.Telegram.WebhookListen,
+			CertFile: cfg.Telegram.WebhookCertFile,
+			KeyFile:  cfg.Telegram.WebhookKeyFile,
+		}, botAPI, logger)
+		if err != nil {
+			logger.Error().Err(err).Msg("Ошибка настройки webhook Telegram")
+			return err
+		}
+		if err := telegramBot.StartWebhook(ctx, receiver); err != nil {
+			logger.Error().Err(err).Msg("Ошибка запуска webhook Telegram")
+			return err
+		}
+	}
 
 	logger.Info().Msg("Shutdown complete.")
 	return nil
@@ -283,14 +411,9 @@ func subscribeBookingEvents(
 			return nil
 		}
 
-		booking, err := db.GetBooking(ctx, payload.BookingID)
-		if err != nil {
-			logger.Error().Err(err).Int64("booking_id", payload.BookingID).Msg("event bus: load booking")
-			return nil
-		}
-
-		if err := sheetsWorker.EnqueueTask(ctx, "upsert", booking.ID, booking, ""); err != nil {
-			logger.Error().Err(err).Int64("booking_id", booking.ID).Msg("event bus: enqueue upsert")
+		// Заявку перечитывает воркер; ошибка постановки в очередь возвращается диспетчеру для повтора
+		if err := sheetsWorker.EnqueueTask(ctx, "upsert", payload.BookingID, ""); err != nil {
+			return fmt.Errorf("enqueue upsert: %w", err)
 		}
 		return nil
 	}
@@ -315,15 +438,113 @@ func subscribeBookingEvents(
 			return nil
 		}
 
-		if err := sheetsWorker.EnqueueTask(ctx, "update_status", payload.BookingID, nil, status); err != nil {
-			logger.Error().Err(err).Int64("booking_id", payload.BookingID).Msg("event bus: enqueue status")
+		if err := sheetsWorker.EnqueueTask(ctx, "update_status", payload.BookingID, status); err != nil {
+			return fmt.Errorf("enqueue status: %w", err)
 		}
 		return nil
 	}
 
 	bus.Subscribe(events.EventBookingCreated, upsertHandler)
 	bus.Subscribe(events.EventBookingItemChange, upsertHandler)
+	bus.Subscribe(events.EventBookingRescheduled, upsertHandler)
 	bus.Subscribe(events.EventBookingConfirmed, statusHandler)
 	bus.Subscribe(events.EventBookingCanceled, statusHandler)
 	bus.Subscribe(events.EventBookingCompleted, statusHandler)
 }
+
+// startWebhookWorker подписывает отправку вебхуков на все события по заявкам.
+func startWebhookWorker(ctx context.Context, cfg *config.Config, bus *events.EventBus, db *database.DB, logger *zerolog.Logger) {
+	if !cfg.Webhooks.Enabled {
+		return
+	}
+
+	retryPolicy := worker.RetryPolicy{
+		MaxRetries:    cfg.Webhooks.MaxAttempts,
+		InitialDelay:  time.Duration(cfg.Webhooks.InitialDelaySeconds) * time.Second,
+		MaxDelay:      time.Duration(cfg.Webhooks.MaxDelaySeconds) * time.Second,
+		BackoffFactor: 2,
+	}
+	client := &http.Client{Timeout: time.Duration(cfg.Webhooks.TimeoutSeconds) * time.Second}
+	webhookWorker := worker.NewWebhookWorker(db, client, retryPolicy,
+		time.Duration(cfg.Webhooks.PollIntervalSeconds)*time.Second, logger)
+	webhookWorker.SetRetention(time.Duration(cfg.Webhooks.RetentionDays) * 24 * time.Hour)
+
+	for _, eventType := range events.BookingEventTypes {
+		bus.Subscribe(eventType, webhookWorker.HandleEvent)
+	}
+	go webhookWorker.Start(ctx)
+}
+
+func subscribeWaitlistEvents(
+	ctx context.Context,
+	bus *events.EventBus,
+	db *database.DB,
+	waitlistService *service.WaitlistService,
+	logger *zerolog.Logger,
+) {
+	if bus == nil || waitlistService == nil {
+		return
+	}
+
+	// releaseDates предлагает освободившиеся дни аппарата листу ожидания
+	releaseDates := func(itemID int64, booking *models.Booking) error {
+		for _, date := range booking.CoveredDates() {
+			if err := waitlistService.HandleSlotReleased(ctx, itemID, date); err != nil {
+				return fmt.Errorf("offer waitlist slot: %w", err)
+			}
+		}
+		return nil
+	}
+
+	// Отмена освобождает все дни заявки, в том числе многодневной
+	bus.Subscribe(events.EventBookingCanceled, func(ev *events.Event) error {
+		var payload events.BookingEventPayload
+		if err := json.Unmarshal(ev.Payload, &payload); err != nil {
+			logger.Error().Err(err).Str("event", ev.Type).Msg("event bus: decode payload")
+			return nil
+		}
+		return releaseDates(payload.ItemID, &models.Booking{Date: payload.Date, EndTime: payload.EndTime})
+	})
+
+	// После замены аппарата его дни освобождаются у прежнего аппарата
+	bus.Subscribe(events.EventBookingItemChange, func(ev *events.Event) error {
+		var payload events.BookingEventPayload
+		if err := json.Unmarshal(ev.Payload, &payload); err != nil {
+			logger.Error().Err(err).Str("event", ev.Type).Msg("event bus: decode payload")
+			return nil
+		}
+		if payload.PreviousItemName == "" {
+			return nil
+		}
+		previous, err := db.GetItemByName(ctx, payload.PreviousItemName)
+		if err != nil {
+			logger.Warn().Err(err).Str("item", payload.PreviousItemName).Msg("waitlist: previous item not found")
+			return nil
+		}
+		return releaseDates(previous.ID, &models.Booking{Date: payload.Date, EndTime: payload.EndTime})
+	})
+
+	// После переноса освобождаются прежние даты заявки
+	bus.Subscribe(events.EventBookingRescheduled, func(ev *events.Event) error {
+		var payload events.BookingEventPayload
+		if err := json.Unmarshal(ev.Payload, &payload); err != nil {
+			logger.Error().Err(err).Str("event", ev.Type).Msg("event bus: decode payload")
+			return nil
+		}
+		if payload.PreviousDate == nil {
+			return nil
+		}
+
+		current := &models.Booking{Date: payload.Date, EndTime: payload.EndTime}
+		previous := &models.Booking{Date: *payload.PreviousDate, EndTime: payload.PreviousEndTime}
+		for _, date := range previous.CoveredDates() {
+			if current.ContainsDate(date) {
+				continue
+			}
+			if err := waitlistService.HandleSlotReleased(ctx, payload.ItemID, date); err != nil {
+				return fmt.Errorf("offer waitlist slot: %w", err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,80 @@
+// Command retention применяет политику хранения персональных данных из секции
+// retention конфига или показывает, что она изменила бы.
+//
+//	retention [-config path] [-dry-run]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"bronivik/internal/config"
+	"bronivik/internal/database"
+	"bronivik/internal/models"
+	"bronivik/internal/retention"
+
+	"github.com/rs/zerolog"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		log.Fatalf("retention: %v", err)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	defaultConfig := os.Getenv("CONFIG_PATH")
+	if defaultConfig == "" {
+		defaultConfig = "configs/config.yaml"
+	}
+
+	fs := flag.NewFlagSet("retention", flag.ContinueOnError)
+	configPath := fs.String("config", defaultConfig, "path to config file")
+	dryRun := fs.Bool("dry-run", false, "report affected rows without changing data")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: retention [-config path] [-dry-run]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if len(cfg.Retention.Rules) == 0 {
+		return fmt.Errorf("no retention rules configured")
+	}
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).Level(zerolog.WarnLevel)
+	db, err := database.Open(&cfg.Database, &logger)
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer db.Close()
+
+	fields, err := cfg.Encryption.Cipher()
+	if err != nil {
+		return fmt.Errorf("encryption keys: %w", err)
+	}
+	db.SetFieldCipher(fields)
+	// retention_log создает миграция: без нее отчет некуда записать
+	if err := db.Migrate(context.Background(), cfg.Database.Postgres.MigrationTable); err != nil {
+		return err
+	}
+
+	engine, err := db.RetentionEngine(cfg.Retention.Policy(), cfg.Retention.Options())
+	if err != nil {
+		return err
+	}
+	ctx := models.WithActor(context.Background(), models.Actor{Type: models.ActorSystem, Name: "retention"})
+	report, err := engine.Run(ctx, *dryRun)
+	if werr := retention.WriteReport(out, report); werr != nil && err == nil {
+		err = werr
+	}
+	return err
+}
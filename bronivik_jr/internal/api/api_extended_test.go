@@ -35,25 +35,29 @@ func TestGRPCServer_New(t *testing.T) {
 	s.Shutdown(ctx)
 }
 
-func TestBuildTLSConfig(t *testing.T) {
+func TestNewCertReloader(t *testing.T) {
 	t.Run("EmptyPaths", func(t *testing.T) {
-		_, err := buildTLSConfig(config.APITLSConfig{Enabled: true})
+		_, err := newCertReloader(config.APITLSConfig{Enabled: true}, nil, zerolog.Nop())
 		assert.Error(t, err)
 	})
 
 	t.Run("InvalidCert", func(t *testing.T) {
-		_, err := buildTLSConfig(config.APITLSConfig{
+		_, err := newCertReloader(config.APITLSConfig{
 			Enabled:  true,
 			CertFile: "/nonexistent",
 			KeyFile:  "/nonexistent",
-		})
+		}, nil, zerolog.Nop())
 		assert.Error(t, err)
 	})
 
 	t.Run("ClientCA_Missing", func(t *testing.T) {
-		// We'd need actual certs to test further, which is complex.
-		// But we can test the check for ClientCAFile.
-		// We can't easily test LoadX509KeyPair without files.
+		_, err := newCertReloader(config.APITLSConfig{
+			Enabled:           true,
+			CertFile:          "/nonexistent",
+			KeyFile:           "/nonexistent",
+			RequireClientCert: true,
+		}, nil, zerolog.Nop())
+		assert.ErrorContains(t, err, "client_ca_file")
 	})
 }
 
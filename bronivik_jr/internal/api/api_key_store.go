@@ -0,0 +1,189 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"bronivik/internal/config"
+	"bronivik/internal/domain"
+	"bronivik/internal/models"
+
+	"github.com/rs/zerolog"
+)
+
+// APIKeyStore держит ключи из базы в памяти и перечитывает их по таймеру, поэтому
+// выпуск, ротация и отзыв ключей (в том числе ботом) подхватываются без перезапуска.
+// Время последнего использования копится в памяти и пишется в базу при перечитывании.
+type APIKeyStore struct {
+	repo     domain.APIKeyRepository
+	interval time.Duration
+	log      zerolog.Logger
+
+	mu     sync.RWMutex
+	byHash map[string]*models.APIKey
+	byCert map[string]*models.APIKey
+
+	usedMu sync.Mutex
+	used   map[int64]time.Time
+}
+
+func NewAPIKeyStore(repo domain.APIKeyRepository, interval time.Duration, logger *zerolog.Logger) *APIKeyStore {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	s := &APIKeyStore{
+		repo:     repo,
+		interval: interval,
+		byHash:   map[string]*models.APIKey{},
+		byCert:   map[string]*models.APIKey{},
+		used:     map[int64]time.Time{},
+	}
+	if logger != nil {
+		s.log = logger.With().Str("component", "api_keys").Logger()
+	}
+	return s
+}
+
+// Start перечитывает ключи до отмены ctx.
+func (s *APIKeyStore) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Последние отметки использования сохраняются и при остановке
+			if err := s.flushUsage(context.Background()); err != nil {
+				s.log.Warn().Err(err).Msg("save api key usage")
+			}
+			return
+		case <-ticker.C:
+			if err := s.Reload(ctx); err != nil && ctx.Err() == nil {
+				s.log.Error().Err(err).Msg("reload api keys")
+			}
+		}
+	}
+}
+
+// Reload сохраняет отметки использования и загружает актуальный список ключей.
+// При ошибке чтения остается предыдущий список.
+func (s *APIKeyStore) Reload(ctx context.Context) error {
+	if err := s.flushUsage(ctx); err != nil {
+		s.log.Warn().Err(err).Msg("save api key usage")
+	}
+
+	keys, err := s.repo.GetUnrevokedAPIKeys(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	byHash := make(map[string]*models.APIKey, len(keys))
+	byCert := make(map[string]*models.APIKey)
+	for _, k := range keys {
+		if !k.ActiveAt(now) {
+			continue
+		}
+		byHash[k.Hash] = k
+		// Ключи идут по возрастанию id: после ротации сертификат достается новому ключу
+		if k.ClientCertCN != "" {
+			byCert[k.ClientCertCN] = k
+		}
+	}
+
+	s.mu.Lock()
+	s.byHash = byHash
+	s.byCert = byCert
+	s.mu.Unlock()
+	return nil
+}
+
+// Invalidate сразу убирает ключ id из памяти, не дожидаясь перечитывания: отзыв или
+// смена прав ключа не должны действовать по старому списку, даже если база недоступна.
+// Если ключ остается действующим, он вернется при следующем Reload.
+func (s *APIKeyStore) Invalidate(id int64) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for hash, k := range s.byHash {
+		if k.ID == id {
+			delete(s.byHash, hash)
+		}
+	}
+	for cn, k := range s.byCert {
+		if k.ID == id {
+			delete(s.byCert, cn)
+		}
+	}
+}
+
+// Lookup ищет действующий ключ и возвращает его как клиента API.
+func (s *APIKeyStore) Lookup(apiKey string) (config.APIClientKey, bool) {
+	if s == nil || apiKey == "" {
+		return config.APIClientKey{}, false
+	}
+
+	s.mu.RLock()
+	k, ok := s.byHash[models.HashAPIKey(apiKey)]
+	s.mu.RUnlock()
+	if !ok {
+		return config.APIClientKey{}, false
+	}
+	return s.client(k, apiKey)
+}
+
+// LookupByCertCN ищет действующий ключ, за которым закреплен сертификат клиента с CN certName.
+func (s *APIKeyStore) LookupByCertCN(certName string) (config.APIClientKey, bool) {
+	if s == nil || certName == "" {
+		return config.APIClientKey{}, false
+	}
+
+	s.mu.RLock()
+	k, ok := s.byCert[certName]
+	s.mu.RUnlock()
+	if !ok {
+		return config.APIClientKey{}, false
+	}
+	return s.client(k, "")
+}
+
+// client отмечает использование ключа и возвращает его как клиента API.
+func (s *APIKeyStore) client(k *models.APIKey, apiKey string) (config.APIClientKey, bool) {
+	// Ключ с истекшим сроком отклоняется сразу, не дожидаясь перечитывания
+	now := time.Now()
+	if !k.ActiveAt(now) {
+		return config.APIClientKey{}, false
+	}
+
+	s.usedMu.Lock()
+	s.used[k.ID] = now
+	s.usedMu.Unlock()
+
+	return config.APIClientKey{
+		Key:            apiKey,
+		Name:           k.Name,
+		Permissions:    k.Permissions,
+		SigningSecret:  k.SigningSecret,
+		ClientCertCN:   k.ClientCertCN,
+		RateLimitRPS:   k.RateLimitRPS,
+		RateLimitBurst: k.RateLimitBurst,
+		DailyQuota:     k.DailyQuota,
+		Stored:         true,
+		ClientID:       k.ClientID,
+	}, true
+}
+
+func (s *APIKeyStore) flushUsage(ctx context.Context) error {
+	s.usedMu.Lock()
+	used := s.used
+	s.used = map[int64]time.Time{}
+	s.usedMu.Unlock()
+
+	if len(used) == 0 {
+		return nil
+	}
+	return s.repo.TouchAPIKeys(ctx, used)
+}
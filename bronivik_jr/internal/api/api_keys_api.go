@@ -0,0 +1,240 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"bronivik/internal/domain"
+	"bronivik/internal/metrics"
+	"bronivik/internal/models"
+)
+
+const (
+	apiKeysPathPrefix = "/api/v1/api-keys"
+	permAdminAPIKeys  = models.PermAdminAPIKeys
+)
+
+// CreateAPIKeyRequest is the request body for POST /api/v1/api-keys.
+type CreateAPIKeyRequest struct {
+	Name        string     `json:"name"`
+	Permissions []string   `json:"permissions,omitempty"` // empty grants everything except admin:* endpoints
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	// Own rate limit and daily quota; zero fields fall back to api.rate_limit.
+	models.APIKeyLimits
+}
+
+// RotateAPIKeyRequest is the optional request body for POST /api/v1/api-keys/{id}/rotate.
+type RotateAPIKeyRequest struct {
+	GraceHours int `json:"grace_hours,omitempty"` // how long the old key keeps working; config default if zero
+}
+
+// SetAPIKeyClientCertRequest is the request body for PUT /api/v1/api-keys/{id}/client-cert.
+type SetAPIKeyClientCertRequest struct {
+	// CN of the mutual TLS client certificate bound to the key; empty removes the binding.
+	ClientCertCN string `json:"client_cert_cn"`
+}
+
+// SetAPIKeys подключает ключи из базы к проверке запросов.
+func (s *HTTPServer) SetAPIKeys(keys *APIKeyStore) {
+	s.auth.keys = keys
+}
+
+// SetAPIKeyService подключает управление ключами через /api/v1/api-keys.
+func (s *HTTPServer) SetAPIKeyService(apiKeyService domain.APIKeyService) {
+	s.apiKeyService = apiKeyService
+}
+
+// SetAPIKeys подключает ключи из базы к проверке вызовов gRPC.
+func (s *GRPCServer) SetAPIKeys(keys *APIKeyStore) {
+	s.auth.SetAPIKeys(keys)
+}
+
+// handleAPIKeys routes API key management endpoints:
+//
+//	GET    /api/v1/api-keys
+//	POST   /api/v1/api-keys
+//	POST   /api/v1/api-keys/{id}/rotate
+//	PUT    /api/v1/api-keys/{id}/limits
+//	PUT    /api/v1/api-keys/{id}/client-cert
+//	DELETE /api/v1/api-keys/{id}
+func (s *HTTPServer) handleAPIKeys(w http.ResponseWriter, r *http.Request) {
+	metrics.IncHTTP("api_keys")
+	if s.apiKeyService == nil {
+		writeError(w, http.StatusServiceUnavailable, "api key service is not configured")
+		return
+	}
+
+	parts := splitPath(strings.TrimPrefix(r.URL.Path, apiKeysPathPrefix))
+	switch {
+	case len(parts) == 0:
+		switch r.Method {
+		case http.MethodGet:
+			s.listAPIKeys(w, r)
+		case http.MethodPost:
+			s.createAPIKey(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	case len(parts) <= 2:
+		id, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil || id <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid api key id")
+			return
+		}
+		switch {
+		case len(parts) == 1 && r.Method == http.MethodDelete:
+			s.revokeAPIKey(w, r, id)
+		case len(parts) == 2 && parts[1] == "rotate" && r.Method == http.MethodPost:
+			s.rotateAPIKey(w, r, id)
+		case len(parts) == 2 && parts[1] == "limits" && r.Method == http.MethodPut:
+			s.setAPIKeyLimits(w, r, id)
+		case len(parts) == 2 && parts[1] == "client-cert" && r.Method == http.MethodPut:
+			s.setAPIKeyClientCert(w, r, id)
+		case len(parts) == 2 && parts[1] != "rotate" && parts[1] != "limits" && parts[1] != "client-cert":
+			writeError(w, http.StatusNotFound, "not found")
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *HTTPServer) listAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := s.apiKeyService.ListKeys(r.Context())
+	if err != nil {
+		s.writeAPIKeyError(w, err)
+		return
+	}
+	if keys == nil {
+		keys = []*models.APIKey{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"api_keys": keys})
+}
+
+func (s *HTTPServer) createAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req CreateAPIKeyRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	key, err := s.apiKeyService.CreateKey(r.Context(), req.Name, req.Permissions, req.ExpiresAt, req.APIKeyLimits)
+	if err != nil {
+		s.writeAPIKeyError(w, err)
+		return
+	}
+	s.reloadAPIKeys(r)
+	writeJSON(w, http.StatusCreated, key)
+}
+
+func (s *HTTPServer) rotateAPIKey(w http.ResponseWriter, r *http.Request, id int64) {
+	var req RotateAPIKeyRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.GraceHours < 0 {
+		writeError(w, http.StatusBadRequest, "grace_hours must not be negative")
+		return
+	}
+
+	key, err := s.apiKeyService.RotateKey(r.Context(), id, time.Duration(req.GraceHours)*time.Hour)
+	if err != nil {
+		s.writeAPIKeyError(w, err)
+		return
+	}
+	s.reloadAPIKeys(r)
+	writeJSON(w, http.StatusCreated, key)
+}
+
+// setAPIKeyLimits заменяет лимиты ключа целиком: нулевое поле возвращает общий лимит.
+func (s *HTTPServer) setAPIKeyLimits(w http.ResponseWriter, r *http.Request, id int64) {
+	var limits models.APIKeyLimits
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&limits); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	if err := s.apiKeyService.SetKeyLimits(r.Context(), id, limits); err != nil {
+		s.writeAPIKeyError(w, err)
+		return
+	}
+	s.invalidateAPIKey(r, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setAPIKeyClientCert закрепляет за ключом сертификат клиента; пустой CN снимает привязку.
+func (s *HTTPServer) setAPIKeyClientCert(w http.ResponseWriter, r *http.Request, id int64) {
+	var req SetAPIKeyClientCertRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	if err := s.apiKeyService.SetKeyClientCert(r.Context(), id, req.ClientCertCN); err != nil {
+		s.writeAPIKeyError(w, err)
+		return
+	}
+	s.invalidateAPIKey(r, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *HTTPServer) revokeAPIKey(w http.ResponseWriter, r *http.Request, id int64) {
+	if err := s.apiKeyService.RevokeKey(r.Context(), id); err != nil {
+		s.writeAPIKeyError(w, err)
+		return
+	}
+	s.invalidateAPIKey(r, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// reloadAPIKeys сразу применяет изменение в этом процессе; остальные увидят его при перечитывании.
+func (s *HTTPServer) reloadAPIKeys(r *http.Request) {
+	if s.auth.keys == nil {
+		return
+	}
+	if err := s.auth.keys.Reload(r.Context()); err != nil {
+		s.log.Warn().Err(err).Msg("reload api keys")
+	}
+}
+
+// invalidateAPIKey убирает измененный ключ из памяти до перечитывания: если перечитать
+// список не удалось, ключ отклоняется до следующего Reload, а не работает по старым правам.
+func (s *HTTPServer) invalidateAPIKey(r *http.Request, id int64) {
+	if s.auth.keys == nil {
+		return
+	}
+	s.auth.keys.Invalidate(id)
+	s.reloadAPIKeys(r)
+}
+
+func (s *HTTPServer) writeAPIKeyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		writeError(w, http.StatusNotFound, "not found")
+	case errors.Is(err, models.ErrInvalidAPIKeyName),
+		errors.Is(err, models.ErrInvalidAPIKeyPermission),
+		errors.Is(err, models.ErrNoAPIKeyPermissions),
+		errors.Is(err, models.ErrInvalidAPIKeyExpiry),
+		errors.Is(err, models.ErrInvalidAPIKeyLimits):
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		s.log.Error().Err(err).Msg("api key API error")
+		writeError(w, http.StatusInternalServerError, "internal error")
+	}
+}
@@ -0,0 +1,202 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"bronivik/internal/config"
+	"bronivik/internal/models"
+	"bronivik/internal/service"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAPIKeysLifecycle(t *testing.T) {
+	db := newTestDB(t)
+	createTestItem(t, db, "camera", 2)
+	logger := zerolog.New(io.Discard)
+
+	cfg := config.APIConfig{
+		Enabled: true,
+		HTTP:    config.APIHTTPConfig{Enabled: true, Port: 0},
+		Auth: config.APIAuthConfig{
+			Enabled:      true,
+			HeaderAPIKey: "x-api-key",
+			HeaderExtra:  "x-api-extra",
+			APIKeys: []config.APIClientKey{
+				{Key: "admin-key", Extra: "admin-extra", Permissions: []string{permAdminAPIKeys}},
+			},
+		},
+	}
+	server := NewHTTPServer(&cfg, db, nil, nil, &logger)
+	server.SetAPIKeyService(service.NewAPIKeyService(db, time.Hour, &logger))
+	keys := NewAPIKeyStore(db, time.Minute, &logger)
+	server.SetAPIKeys(keys)
+	ts := httptest.NewServer(server.server.Handler)
+	t.Cleanup(ts.Close)
+
+	call := func(method, path, key, body string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		req.Header.Set("x-api-key", key)
+		if key == "admin-key" {
+			req.Header.Set("x-api-extra", "admin-extra")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	resp := call(http.MethodPost, apiKeysPathPrefix, "admin-key", `{"name": "crm", "permissions": ["read:everything"]}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp = call(http.MethodPost, apiKeysPathPrefix, "admin-key", `{"name": "crm"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "a database key needs permissions")
+
+	resp = call(http.MethodPost, apiKeysPathPrefix, "admin-key", `{"name": "crm", "permissions": ["read:items"]}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created models.APIKey
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	require.NotEmpty(t, created.Key)
+	assert.True(t, strings.HasPrefix(created.Key, created.Prefix))
+
+	// Ключ из базы работает сразу и без второго секрета
+	assert.Equal(t, http.StatusOK, call(http.MethodGet, "/api/v1/items", created.Key, "").StatusCode)
+	assert.Equal(t, http.StatusForbidden, call(http.MethodGet, apiKeysPathPrefix, created.Key, "").StatusCode)
+
+	resp = call(http.MethodPost, fmt.Sprintf("%s/%d/rotate", apiKeysPathPrefix, created.ID), "admin-key", `{"grace_hours": 2}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var rotated models.APIKey
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&rotated))
+	assert.Equal(t, "crm", rotated.Name)
+	assert.Equal(t, []string{models.PermReadItems}, rotated.Permissions)
+
+	// В льготный период работают оба ключа
+	assert.Equal(t, http.StatusOK, call(http.MethodGet, "/api/v1/items", created.Key, "").StatusCode)
+	assert.Equal(t, http.StatusOK, call(http.MethodGet, "/api/v1/items", rotated.Key, "").StatusCode)
+
+	resp = call(http.MethodPost, fmt.Sprintf("%s/%d/rotate", apiKeysPathPrefix, created.ID), "admin-key", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "a rotated key cannot be rotated again")
+
+	resp = call(http.MethodDelete, fmt.Sprintf("%s/%d", apiKeysPathPrefix, created.ID), "admin-key", "")
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, http.StatusUnauthorized, call(http.MethodGet, "/api/v1/items", created.Key, "").StatusCode)
+	assert.Equal(t, http.StatusOK, call(http.MethodGet, "/api/v1/items", rotated.Key, "").StatusCode)
+
+	resp = call(http.MethodGet, apiKeysPathPrefix, "admin-key", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var list struct {
+		APIKeys []*models.APIKey `json:"api_keys"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+	require.Len(t, list.APIKeys, 2)
+	assert.Empty(t, list.APIKeys[0].Key)
+	assert.NotNil(t, list.APIKeys[0].RevokedAt)
+	assert.Equal(t, rotated.ID, *list.APIKeys[0].ReplacedBy)
+
+	// Отметки использования пишутся в базу при перечитывании
+	require.NoError(t, keys.Reload(context.Background()))
+	stored, err := db.GetAPIKey(context.Background(), rotated.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, stored.LastUsedAt)
+}
+
+func TestAPIKeyStorePicksUpChanges(t *testing.T) {
+	db := newTestDB(t)
+	logger := zerolog.New(io.Discard)
+	ctx := context.Background()
+	svc := service.NewAPIKeyService(db, time.Hour, &logger)
+
+	cfg := config.APIConfig{Enabled: true, Auth: config.APIAuthConfig{Enabled: true}}
+	auth := NewAuthInterceptor(&cfg)
+	keys := NewAPIKeyStore(db, time.Minute, &logger)
+	require.NoError(t, keys.Reload(ctx))
+	auth.SetAPIKeys(keys)
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/bronivik.availability.v1.AvailabilityService/ListItems"}
+	handler := func(context.Context, any) (any, error) { return "ok", nil }
+	invoke := func(key string) error {
+		ctx := metadata.NewIncomingContext(ctx, metadata.Pairs("x-api-key", key))
+		_, err := auth.Unary()(ctx, "req", info, handler)
+		return err
+	}
+
+	// Ключ, выпущенный другим процессом, виден после перечитывания
+	key, err := svc.CreateKey(ctx, "partner", []string{models.PermReadItems}, nil, models.APIKeyLimits{})
+	require.NoError(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(invoke(key.Key)))
+	require.NoError(t, keys.Reload(ctx))
+	assert.NoError(t, invoke(key.Key))
+
+	require.NoError(t, svc.RevokeKey(ctx, key.ID))
+	require.NoError(t, keys.Reload(ctx))
+	assert.Equal(t, codes.Unauthenticated, status.Code(invoke(key.Key)))
+
+	// Истекший ключ отклоняется, даже если список еще не перечитан
+	expiring, err := svc.CreateKey(ctx, "temp", []string{models.PermReadItems}, ptrTime(time.Now().Add(time.Second)), models.APIKeyLimits{})
+	require.NoError(t, err)
+	require.NoError(t, keys.Reload(ctx))
+	_, ok := keys.Lookup(expiring.Key)
+	assert.True(t, ok)
+	keys.byHash[expiring.Hash].ExpiresAt = ptrTime(time.Now().Add(-time.Second))
+	_, ok = keys.Lookup(expiring.Key)
+	assert.False(t, ok)
+
+	// Ключу из базы без разрешений запрещено все, в отличие от статического ключа
+	require.NoError(t, db.CreateAPIKey(ctx, &models.APIKey{Name: "legacy", Prefix: "bk_legacy", Hash: models.HashAPIKey("legacy-key")}))
+	require.NoError(t, keys.Reload(ctx))
+	assert.Equal(t, codes.PermissionDenied, status.Code(invoke("legacy-key")))
+	legacy, ok := keys.Lookup("legacy-key")
+	require.True(t, ok)
+	httpAuth := NewHTTPAuth(&config.APIConfig{})
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/items", http.NoBody)
+	assert.ErrorIs(t, httpAuth.checkPermissions(legacy, req), errPermissionDenied)
+	assert.NoError(t, httpAuth.checkPermissions(config.APIClientKey{Key: "static"}, req))
+}
+
+func TestAPIKeyStoreInvalidate(t *testing.T) {
+	db := newTestDB(t)
+	logger := zerolog.New(io.Discard)
+	ctx := context.Background()
+	svc := service.NewAPIKeyService(db, time.Hour, &logger)
+
+	key, err := svc.CreateKey(ctx, "partner", []string{models.PermReadItems}, nil, models.APIKeyLimits{})
+	require.NoError(t, err)
+	require.NoError(t, svc.SetKeyClientCert(ctx, key.ID, "partner.example.com"))
+	other, err := svc.CreateKey(ctx, "other", []string{models.PermReadItems}, nil, models.APIKeyLimits{})
+	require.NoError(t, err)
+
+	keys := NewAPIKeyStore(db, time.Minute, &logger)
+	require.NoError(t, keys.Reload(ctx))
+
+	// Ключ отклоняется сразу, без перечитывания списка
+	keys.Invalidate(key.ID)
+	_, ok := keys.Lookup(key.Key)
+	assert.False(t, ok)
+	_, ok = keys.LookupByCertCN("partner.example.com")
+	assert.False(t, ok)
+	_, ok = keys.Lookup(other.Key)
+	assert.True(t, ok)
+
+	// Действующий ключ возвращается при перечитывании
+	require.NoError(t, keys.Reload(ctx))
+	_, ok = keys.Lookup(key.Key)
+	assert.True(t, ok)
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"bronivik/internal/config"
+	"bronivik/internal/models"
+	"bronivik/internal/reqsign"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
@@ -21,6 +23,9 @@ type AuthInterceptor struct {
 	cfg *config.APIConfig
 
 	clientsByAPIKey map[string]config.APIClientKey
+	clientsByCert   map[string]config.APIClientKey
+	keys            *APIKeyStore
+	signatures      *reqsign.Verifier
 	limiter         *rateLimiter
 }
 
@@ -33,27 +38,69 @@ func NewAuthInterceptor(cfg *config.APIConfig) *AuthInterceptor {
 	return &AuthInterceptor{
 		cfg:             cfg,
 		clientsByAPIKey: m,
+		clientsByCert:   clientsByCertCN(cfg.Auth.APIKeys),
+		signatures:      reqsign.NewVerifier(time.Duration(cfg.Auth.Signing.MaxSkewSeconds)*time.Second, nil),
 		limiter:         newRateLimiter(cfg),
 	}
 }
 
+// SetAPIKeys подключает ключи из базы.
+func (a *AuthInterceptor) SetAPIKeys(keys *APIKeyStore) {
+	a.keys = keys
+}
+
 func (a *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
-		if !a.cfg.Enabled {
-			return handler(ctx, req)
+		ctx, err := a.authorize(ctx, info.FullMethod, req)
+		if err != nil {
+			return nil, err
 		}
+		return handler(ctx, req)
+	}
+}
 
-		if a.cfg.Auth.Enabled {
-			if err := a.checkAuth(ctx, info.FullMethod); err != nil {
-				return nil, err
-			}
+// Stream применяет те же проверки к потоковым методам.
+func (a *AuthInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authorize(ss.Context(), info.FullMethod, nil)
+		if err != nil {
+			return err
 		}
-		if err := a.checkRateLimit(ctx); err != nil {
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authorize проверяет ключ, подпись и лимит запросов и возвращает контекст с актором API-клиента.
+// У потоковых вызовов req равен nil.
+func (a *AuthInterceptor) authorize(ctx context.Context, fullMethod string, req any) (context.Context, error) {
+	actor := models.Actor{Type: models.ActorAPI}
+	if !a.cfg.Enabled {
+		return models.WithActor(ctx, actor), nil
+	}
+
+	var client config.APIClientKey
+	if a.cfg.Auth.Enabled {
+		var err error
+		if client, err = a.checkAuth(ctx, fullMethod, req); err != nil {
 			return nil, err
 		}
-
-		return handler(ctx, req)
+		actor.Name = client.Name
 	}
+	if err := a.checkRateLimit(ctx, client); err != nil {
+		return nil, err
+	}
+
+	return models.WithActor(ctx, actor), nil
+}
+
+// contextServerStream подменяет контекст потока.
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
 }
 
 const (
@@ -61,13 +108,15 @@ const (
 	apiExtraHeaderDefault = "x-api-extra"
 	permReadAvailability  = "read:availability"
 	permReadItems         = "read:items"
+	permReadBookings      = "read:bookings"
+	permWriteBookings     = "write:bookings"
 	clientKeyUnknown      = "unknown"
 )
 
-func (a *AuthInterceptor) checkAuth(ctx context.Context, fullMethod string) error {
+func (a *AuthInterceptor) checkAuth(ctx context.Context, fullMethod string, req any) (config.APIClientKey, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		return status.Error(codes.Unauthenticated, "missing metadata")
+		return config.APIClientKey{}, status.Error(codes.Unauthenticated, "missing metadata")
 	}
 
 	apiKeyHeader := strings.ToLower(strings.TrimSpace(a.cfg.Auth.HeaderAPIKey))
@@ -82,24 +131,39 @@ func (a *AuthInterceptor) checkAuth(ctx context.Context, fullMethod string) erro
 
 	apiKey := first(md.Get(apiKeyHeader))
 	extra := first(md.Get(extraHeader))
-	if apiKey == "" || extra == "" {
-		return status.Error(codes.Unauthenticated, "missing api key headers")
-	}
+	certName := grpcClientCertName(ctx)
 
-	client, ok := a.clientsByAPIKey[apiKey]
-	if !ok {
-		return status.Error(codes.Unauthenticated, "invalid api key")
+	// Проверенный сертификат клиента, закрепленный за ключом, заменяет сам ключ
+	client, ok := clientByCert(a.clientsByCert, a.keys, certName)
+	if apiKey != "" || !ok {
+		if apiKey == "" {
+			return config.APIClientKey{}, status.Error(codes.Unauthenticated, "missing api key headers")
+		}
+		// Ключам из базы второй секрет не нужен; статические ключи из конфига проверяются вместе с extra
+		if client, ok = a.keys.Lookup(apiKey); !ok {
+			if extra == "" {
+				return config.APIClientKey{}, status.Error(codes.Unauthenticated, "missing api key headers")
+			}
+			if client, ok = a.clientsByAPIKey[apiKey]; !ok {
+				return config.APIClientKey{}, status.Error(codes.Unauthenticated, "invalid api key")
+			}
+			if subtle.ConstantTimeCompare([]byte(client.Extra), []byte(extra)) != 1 {
+				return config.APIClientKey{}, status.Error(codes.Unauthenticated, "invalid extra header")
+			}
+		}
+		if err := checkClientCert(client, certName); err != nil {
+			return config.APIClientKey{}, status.Error(codes.Unauthenticated, err.Error())
+		}
 	}
 
-	if subtle.ConstantTimeCompare([]byte(client.Extra), []byte(extra)) != 1 {
-		return status.Error(codes.Unauthenticated, "invalid extra header")
+	if err := a.checkSignature(ctx, md, fullMethod, req, client); err != nil {
+		return config.APIClientKey{}, err
 	}
-
 	if err := a.checkPermissions(client, fullMethod); err != nil {
-		return err
+		return config.APIClientKey{}, err
 	}
 
-	return nil
+	return client, nil
 }
 
 func (a *AuthInterceptor) checkPermissions(client config.APIClientKey, fullMethod string) error {
@@ -108,8 +172,9 @@ func (a *AuthInterceptor) checkPermissions(client config.APIClientKey, fullMetho
 		return nil
 	}
 
-	// If permissions list is empty, treat as allow-all.
-	if len(client.Permissions) == 0 {
+	// An empty permissions list allows everything for static keys only;
+	// database keys are denied by default.
+	if len(client.Permissions) == 0 && !client.Stored {
 		return nil
 	}
 
@@ -127,22 +192,37 @@ func requiredPermission(fullMethod string) string {
 		return permReadAvailability
 	case "/bronivik.availability.v1.AvailabilityService/GetAvailabilityBulk":
 		return permReadAvailability
+	case "/bronivik.availability.v1.AvailabilityService/WatchAvailability":
+		return permReadAvailability
 	case "/bronivik.availability.v1.AvailabilityService/ListItems":
 		return permReadItems
+	case "/bronivik.booking.v1.BookingService/GetBooking",
+		"/bronivik.booking.v1.BookingService/ListBookings":
+		return permReadBookings
+	case "/bronivik.booking.v1.BookingService/CreateBooking",
+		"/bronivik.booking.v1.BookingService/CancelBooking",
+		"/bronivik.booking.v1.BookingService/ConfirmBooking",
+		"/bronivik.booking.v1.BookingService/RescheduleBooking":
+		return permWriteBookings
 	default:
 		return ""
 	}
 }
 
-func (a *AuthInterceptor) checkRateLimit(ctx context.Context) error {
-	if a.cfg.RateLimit.RPS <= 0 {
+// checkRateLimit списывает вызов с лимитов клиента; их состояние уходит в трейлерах ratelimit-*.
+func (a *AuthInterceptor) checkRateLimit(ctx context.Context, client config.APIClientKey) error {
+	d, ok := a.limiter.allow(ctx, a.clientKey(ctx), client)
+	if !ok {
 		return nil
 	}
-
-	key := a.clientKey(ctx)
-	lim := a.limiter.getLimiter(key)
-	if !lim.Allow() {
-		return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	md := metadata.MD{}
+	for _, h := range d.headers() {
+		md.Set(h[0], h[1])
+	}
+	// Вне сервера gRPC (например, в тестах) трейлер задать нельзя, это не мешает проверке
+	_ = grpc.SetTrailer(ctx, md)
+	if err := d.err(); err != nil {
+		return status.Error(codes.ResourceExhausted, err.Error())
 	}
 	return nil
 }
@@ -157,6 +237,9 @@ func (a *AuthInterceptor) clientKey(ctx context.Context) string {
 	if apiKey != "" {
 		return apiKey
 	}
+	if certName := grpcClientCertName(ctx); certName != "" {
+		return "cert:" + certName
+	}
 
 	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
 		return p.Addr.String()
@@ -172,10 +255,7 @@ func first(vals []string) string {
 }
 
 func LoggingUnaryInterceptor(logger *zerolog.Logger) grpc.UnaryServerInterceptor {
-	base := zerolog.Nop()
-	if logger != nil {
-		base = logger.With().Str("component", "grpc").Logger()
-	}
+	base := grpcLogger(logger)
 
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 		requestID := requestIDFromMetadata(ctx)
@@ -183,30 +263,58 @@ func LoggingUnaryInterceptor(logger *zerolog.Logger) grpc.UnaryServerInterceptor
 
 		start := time.Now()
 		resp, err := handler(ctx, req)
-		dur := time.Since(start)
+		logGRPCRequest(ctx, &base, requestID, info.FullMethod, start, err)
 
-		code := codes.OK
-		if err != nil {
-			code = status.Code(err)
-		}
+		return resp, err
+	}
+}
 
-		remote := clientKeyUnknown
-		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
-			remote = p.Addr.String()
-		}
+// LoggingStreamInterceptor логирует потоковые вызовы после их завершения.
+func LoggingStreamInterceptor(logger *zerolog.Logger) grpc.StreamServerInterceptor {
+	base := grpcLogger(logger)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		requestID := requestIDFromMetadata(ctx)
+		_ = ss.SetHeader(metadata.Pairs(requestIDMetadataKey, requestID))
 
-		base.Info().
-			Str("request_id", requestID).
-			Str("method", info.FullMethod).
-			Str("remote", remote).
-			Str("code", code.String()).
-			Dur("duration", dur).
-			Msg("grpc request")
+		start := time.Now()
+		err := handler(srv, ss)
+		logGRPCRequest(ctx, &base, requestID, info.FullMethod, start, err)
 
-		return resp, err
+		return err
 	}
 }
 
+func grpcLogger(logger *zerolog.Logger) zerolog.Logger {
+	if logger == nil {
+		return zerolog.Nop()
+	}
+	return logger.With().Str("component", "grpc").Logger()
+}
+
+func logGRPCRequest(ctx context.Context, base *zerolog.Logger, requestID, method string, start time.Time, err error) {
+	dur := time.Since(start)
+
+	code := codes.OK
+	if err != nil {
+		code = status.Code(err)
+	}
+
+	remote := clientKeyUnknown
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		remote = p.Addr.String()
+	}
+
+	base.Info().
+		Str("request_id", requestID).
+		Str("method", method).
+		Str("remote", remote).
+		Str("code", code.String()).
+		Dur("duration", dur).
+		Msg("grpc request")
+}
+
 const requestIDMetadataKey = "x-request-id"
 
 func requestIDFromMetadata(ctx context.Context) string {
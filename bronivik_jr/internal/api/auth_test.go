@@ -134,6 +134,13 @@ func TestRequiredPermission(t *testing.T) {
 		{"/bronivik.availability.v1.AvailabilityService/GetAvailability", "read:availability"},
 		{"/bronivik.availability.v1.AvailabilityService/GetAvailabilityBulk", "read:availability"},
 		{"/bronivik.availability.v1.AvailabilityService/ListItems", "read:items"},
+		{"/bronivik.availability.v1.AvailabilityService/WatchAvailability", "read:availability"},
+		{"/bronivik.booking.v1.BookingService/GetBooking", "read:bookings"},
+		{"/bronivik.booking.v1.BookingService/ListBookings", "read:bookings"},
+		{"/bronivik.booking.v1.BookingService/CreateBooking", "write:bookings"},
+		{"/bronivik.booking.v1.BookingService/CancelBooking", "write:bookings"},
+		{"/bronivik.booking.v1.BookingService/ConfirmBooking", "write:bookings"},
+		{"/bronivik.booking.v1.BookingService/RescheduleBooking", "write:bookings"},
 		{"other", ""},
 	}
 	for _, tt := range tests {
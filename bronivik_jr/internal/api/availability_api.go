@@ -3,8 +3,10 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"bronivik/internal/metrics"
@@ -191,3 +193,113 @@ func (s *HTTPServer) getItemAvailabilityDates(ctx context.Context, item *models.
 	}
 	return availability
 }
+
+// AvailabilityStreamEvent is the data of an "availability" event in GET /api/v1/availability/stream.
+type AvailabilityStreamEvent struct {
+	EventID     int64     `json:"event_id"`
+	EventType   string    `json:"event_type"`
+	BookingID   int64     `json:"booking_id"`
+	ItemName    string    `json:"item_name"`
+	Date        string    `json:"date"`
+	Available   bool      `json:"available"`
+	BookedCount int64     `json:"booked_count"`
+	Total       int64     `json:"total"`
+	ChangedAt   time.Time `json:"changed_at"`
+}
+
+// SetAvailabilityWatcher включает поток изменений доступности.
+func (s *HTTPServer) SetAvailabilityWatcher(watcher *AvailabilityWatcher) {
+	s.watcher = watcher
+}
+
+// handleAvailabilityStream streams availability changes as Server-Sent Events.
+// GET /api/v1/availability/stream?items=a,b&start_date=YYYY-MM-DD&end_date=YYYY-MM-DD
+// The response ends when the server stops or the client falls behind; clients should
+// reconnect and reload availability.
+func (s *HTTPServer) handleAvailabilityStream(w http.ResponseWriter, r *http.Request) {
+	metrics.IncHTTP("availability_stream")
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.watcher == nil {
+		writeError(w, http.StatusServiceUnavailable, "availability stream is not enabled")
+		return
+	}
+
+	q := r.URL.Query()
+	filter, err := parseAvailabilityFilter(s.db, splitCSV(q.Get("items")),
+		strings.TrimSpace(q.Get("start_date")), strings.TrimSpace(q.Get("end_date")))
+	if err != nil {
+		if errors.Is(err, errUnknownItem) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	changes, cancel := s.watcher.Subscribe(filter)
+	defer cancel()
+
+	// Поток живет дольше WriteTimeout сервера
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	_ = rc.Flush()
+
+	heartbeat := time.NewTicker(s.streamHeartbeat())
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case change, ok := <-changes:
+			if !ok {
+				return
+			}
+			if err := writeAvailabilityEvent(w, &change); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func (s *HTTPServer) streamHeartbeat() time.Duration {
+	if s.cfg.Stream.HeartbeatSeconds > 0 {
+		return time.Duration(s.cfg.Stream.HeartbeatSeconds) * time.Second
+	}
+	return 15 * time.Second
+}
+
+func writeAvailabilityEvent(w http.ResponseWriter, change *AvailabilityChange) error {
+	data, err := json.Marshal(AvailabilityStreamEvent{
+		EventID:     change.EventID,
+		EventType:   change.EventType,
+		BookingID:   change.BookingID,
+		ItemName:    change.ItemName,
+		Date:        change.Date.Format("2006-01-02"),
+		Available:   change.Available,
+		BookedCount: change.BookedCount,
+		Total:       change.Total,
+		ChangedAt:   change.ChangedAt,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: availability\ndata: %s\n\n", change.EventID, data)
+	return err
+}
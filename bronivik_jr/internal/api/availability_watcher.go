@@ -0,0 +1,360 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"bronivik/internal/config"
+	"bronivik/internal/database"
+	"bronivik/internal/events"
+
+	"github.com/rs/zerolog"
+)
+
+// watchBatchSize — сколько событий читается из outbox за один запрос.
+const watchBatchSize = 100
+
+// AvailabilityChange is the new availability of an item on a date after a booking change.
+type AvailabilityChange struct {
+	EventID     int64
+	EventType   string
+	BookingID   int64
+	ItemID      int64
+	ItemName    string
+	Date        time.Time
+	Available   bool
+	BookedCount int64
+	Total       int64
+	ChangedAt   time.Time
+}
+
+// AvailabilityFilter limits a subscription to items and an inclusive date range.
+// Empty fields do not filter.
+type AvailabilityFilter struct {
+	ItemIDs []int64
+	Start   time.Time
+	End     time.Time
+}
+
+func (f *AvailabilityFilter) matches(itemID int64, date time.Time) bool {
+	if !f.Start.IsZero() && date.Before(f.Start) {
+		return false
+	}
+	if !f.End.IsZero() && date.After(f.End) {
+		return false
+	}
+	if len(f.ItemIDs) == 0 {
+		return true
+	}
+	for _, id := range f.ItemIDs {
+		if id == itemID {
+			return true
+		}
+	}
+	return false
+}
+
+type availabilitySubscription struct {
+	filter AvailabilityFilter
+	ch     chan AvailabilityChange
+}
+
+// AvailabilityWatcher tails the booking events outbox and fans out availability
+// changes to stream subscribers. It reads the events table directly, so it works
+// in every process that serves the API, independently of the event dispatcher.
+type AvailabilityWatcher struct {
+	db           *database.DB
+	pollInterval time.Duration
+	bufferSize   int
+	log          zerolog.Logger
+
+	mu     sync.Mutex
+	subs   map[*availabilitySubscription]struct{}
+	lastID int64
+	closed bool
+}
+
+// NewAvailabilityWatcher creates a watcher; Start must be called to begin polling.
+func NewAvailabilityWatcher(db *database.DB, cfg config.APIStreamConfig, logger *zerolog.Logger) *AvailabilityWatcher {
+	w := &AvailabilityWatcher{
+		db:           db,
+		pollInterval: time.Duration(cfg.PollIntervalMillis) * time.Millisecond,
+		bufferSize:   cfg.BufferSize,
+		subs:         make(map[*availabilitySubscription]struct{}),
+		lastID:       -1,
+	}
+	if w.pollInterval <= 0 {
+		w.pollInterval = time.Second
+	}
+	if w.bufferSize <= 0 {
+		w.bufferSize = 256
+	}
+	if logger != nil {
+		w.log = logger.With().Str("component", "availability_watcher").Logger()
+	}
+	return w
+}
+
+// Start polls for new events until ctx is canceled, then closes all subscriptions.
+// Only events written after the start are streamed.
+func (w *AvailabilityWatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.poll(ctx); err != nil && ctx.Err() == nil {
+			w.log.Error().Err(err).Msg("poll booking events")
+		}
+
+		select {
+		case <-ctx.Done():
+			w.closeAll()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Subscribe registers a subscriber. The channel is closed when the watcher stops,
+// when the subscriber falls behind by more than the buffer size, or after cancel.
+func (w *AvailabilityWatcher) Subscribe(filter AvailabilityFilter) (changes <-chan AvailabilityChange, cancel func()) {
+	sub := &availabilitySubscription{filter: filter, ch: make(chan AvailabilityChange, w.bufferSize)}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		close(sub.ch)
+		return sub.ch, func() {}
+	}
+	w.subs[sub] = struct{}{}
+
+	return sub.ch, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		w.removeLocked(sub)
+	}
+}
+
+func (w *AvailabilityWatcher) removeLocked(sub *availabilitySubscription) {
+	if _, ok := w.subs[sub]; ok {
+		delete(w.subs, sub)
+		close(sub.ch)
+	}
+}
+
+func (w *AvailabilityWatcher) closeAll() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for sub := range w.subs {
+		w.removeLocked(sub)
+	}
+	w.closed = true
+}
+
+func (w *AvailabilityWatcher) poll(ctx context.Context) error {
+	if w.lastID < 0 {
+		lastID, err := w.db.GetLastEventID(ctx)
+		if err != nil {
+			return err
+		}
+		w.lastID = lastID
+		return nil
+	}
+
+	for {
+		batch, err := w.db.GetEventsFrom(ctx, w.lastID+1, watchBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, ev := range batch {
+			changes, err := w.changesForEvent(ctx, ev)
+			if err != nil {
+				// Событие будет обработано повторно на следующем опросе
+				return fmt.Errorf("event %d: %w", ev.ID, err)
+			}
+			w.publish(changes)
+			w.lastID = ev.ID
+		}
+		if len(batch) < watchBatchSize {
+			return nil
+		}
+	}
+}
+
+// affectsAvailability сообщает, меняет ли событие число занятых единиц.
+// Подтверждение и завершение заявки занятость не меняют.
+func affectsAvailability(eventType string) bool {
+	switch eventType {
+	case events.EventBookingCreated, events.EventBookingCanceled,
+		events.EventBookingRescheduled, events.EventBookingItemChange:
+		return true
+	}
+	return false
+}
+
+type itemDate struct {
+	itemID int64
+	date   time.Time
+}
+
+func (w *AvailabilityWatcher) changesForEvent(ctx context.Context, ev *events.Event) ([]AvailabilityChange, error) {
+	if !affectsAvailability(ev.Type) || !w.hasSubscribers() {
+		return nil, nil
+	}
+
+	var payload events.BookingEventPayload
+	if err := json.Unmarshal(ev.Payload, &payload); err != nil {
+		w.log.Warn().Err(err).Int64("event_id", ev.ID).Msg("skip event with invalid payload")
+		return nil, nil
+	}
+
+	affected := bookingDays(payload.ItemID, payload.Date, payload.EndTime)
+	switch {
+	case payload.PreviousDate != nil:
+		affected = append(affected, bookingDays(payload.ItemID, *payload.PreviousDate, payload.PreviousEndTime)...)
+	case payload.PreviousItemName != "":
+		previous, err := w.db.GetItemByName(ctx, payload.PreviousItemName)
+		if err != nil {
+			w.log.Warn().Err(err).Str("item", payload.PreviousItemName).Msg("previous item not found")
+			break
+		}
+		affected = append(affected, bookingDays(previous.ID, payload.Date, payload.EndTime)...)
+	}
+
+	seen := make(map[itemDate]bool, len(affected))
+	var changes []AvailabilityChange
+	for _, key := range affected {
+		if seen[key] || !w.isWatched(key.itemID, key.date) {
+			continue
+		}
+		seen[key] = true
+
+		item, err := w.db.GetItemByID(ctx, key.itemID)
+		if err != nil {
+			return nil, err
+		}
+		booked, err := w.db.GetBookedCount(ctx, key.itemID, key.date)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, AvailabilityChange{
+			EventID:     ev.ID,
+			EventType:   ev.Type,
+			BookingID:   payload.BookingID,
+			ItemID:      item.ID,
+			ItemName:    item.Name,
+			Date:        key.date,
+			Available:   int64(booked) < item.TotalQuantity,
+			BookedCount: int64(booked),
+			Total:       item.TotalQuantity,
+			ChangedAt:   ev.CreatedAt,
+		})
+	}
+	return changes, nil
+}
+
+// bookingDays перечисляет дни заявки; даты приводятся к полуночи UTC, как у фильтров.
+func bookingDays(itemID int64, start time.Time, end *time.Time) []itemDate {
+	first := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	last := first
+	if end != nil {
+		last = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	var days []itemDate
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		days = append(days, itemDate{itemID: itemID, date: d})
+	}
+	return days
+}
+
+func (w *AvailabilityWatcher) hasSubscribers() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.subs) > 0
+}
+
+func (w *AvailabilityWatcher) isWatched(itemID int64, date time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for sub := range w.subs {
+		if sub.filter.matches(itemID, date) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *AvailabilityWatcher) publish(changes []AvailabilityChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for sub := range w.subs {
+		for i := range changes {
+			if !sub.filter.matches(changes[i].ItemID, changes[i].Date) {
+				continue
+			}
+			select {
+			case sub.ch <- changes[i]:
+			default:
+				// Клиент не успевает читать поток: отключаем его, чтобы он переподключился
+				// и перечитал актуальную доступность, а не пропустил изменения молча.
+				w.log.Warn().Msg("availability subscriber is too slow, closing stream")
+				w.removeLocked(sub)
+			}
+			if _, ok := w.subs[sub]; !ok {
+				break
+			}
+		}
+	}
+}
+
+// resolveItemIDs переводит названия аппаратов в ID для фильтра подписки (без учета регистра).
+func resolveItemIDs(db *database.DB, names []string) ([]int64, error) {
+	byName := make(map[string]int64)
+	for _, it := range db.GetItems() {
+		byName[strings.ToLower(strings.TrimSpace(it.Name))] = it.ID
+	}
+
+	ids := make([]int64, 0, len(names))
+	for _, name := range names {
+		id, ok := byName[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", errUnknownItem, name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+var errUnknownItem = errors.New("item not found")
+
+// parseAvailabilityFilter проверяет параметры подписки: названия аппаратов и даты YYYY-MM-DD.
+func parseAvailabilityFilter(db *database.DB, items []string, start, end string) (AvailabilityFilter, error) {
+	var filter AvailabilityFilter
+	var err error
+	if start != "" {
+		if filter.Start, err = time.Parse("2006-01-02", start); err != nil {
+			return filter, fmt.Errorf("invalid start_date format; expected YYYY-MM-DD")
+		}
+	}
+	if end != "" {
+		if filter.End, err = time.Parse("2006-01-02", end); err != nil {
+			return filter, fmt.Errorf("invalid end_date format; expected YYYY-MM-DD")
+		}
+	}
+	if !filter.Start.IsZero() && !filter.End.IsZero() && filter.Start.After(filter.End) {
+		return filter, fmt.Errorf("start_date must be before or equal to end_date")
+	}
+
+	filter.ItemIDs, err = resolveItemIDs(db, items)
+	return filter, err
+}
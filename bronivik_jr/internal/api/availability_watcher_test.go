@@ -0,0 +1,270 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	availabilityv1 "bronivik/internal/api/gen/availability/v1"
+	"bronivik/internal/config"
+	"bronivik/internal/database"
+	"bronivik/internal/events"
+	"bronivik/internal/models"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func createWatchBooking(t *testing.T, db *database.DB, item *models.Item, date time.Time, end *time.Time) *models.Booking {
+	t.Helper()
+	booking := &models.Booking{
+		ItemID: item.ID, ItemName: item.Name, Date: date, EndTime: end,
+		UserID: 1, UserName: "U1", Phone: "1", Status: models.StatusPending,
+	}
+	require.NoError(t, db.CreateBookingWithLock(context.Background(), booking))
+	return booking
+}
+
+func drainChanges(changes <-chan AvailabilityChange) []AvailabilityChange {
+	var out []AvailabilityChange
+	for {
+		select {
+		case c, ok := <-changes:
+			if !ok {
+				return out
+			}
+			out = append(out, c)
+		default:
+			return out
+		}
+	}
+}
+
+func TestAvailabilityWatcher(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	laser := createTestItem(t, db, "laser", 1)
+	camera := createTestItem(t, db, "camera", 2)
+
+	w := NewAvailabilityWatcher(db, config.APIStreamConfig{}, nil)
+	// Событие до запуска не транслируется
+	day := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	createWatchBooking(t, db, &laser, day.AddDate(0, 0, 10), nil)
+	require.NoError(t, w.poll(ctx))
+
+	changes, cancel := w.Subscribe(AvailabilityFilter{ItemIDs: []int64{laser.ID}, Start: day, End: day.AddDate(0, 0, 2)})
+
+	end := day.AddDate(0, 0, 1)
+	booking := createWatchBooking(t, db, &laser, day, &end)
+	createWatchBooking(t, db, &camera, day, nil)
+	require.NoError(t, w.poll(ctx))
+
+	got := drainChanges(changes)
+	require.Len(t, got, 2)
+	assert.Equal(t, events.EventBookingCreated, got[0].EventType)
+	assert.Equal(t, booking.ID, got[0].BookingID)
+	assert.Equal(t, day, got[0].Date)
+	assert.Equal(t, day.AddDate(0, 0, 1), got[1].Date)
+	assert.False(t, got[1].Available)
+	assert.Equal(t, int64(1), got[1].BookedCount)
+
+	// Замена аппарата освобождает даты прежнего аппарата
+	require.NoError(t, db.UpdateBookingItemWithVersion(ctx, booking.ID, 1, camera.ID, camera.Name))
+	require.NoError(t, w.poll(ctx))
+	got = drainChanges(changes)
+	require.Len(t, got, 2)
+	assert.Equal(t, events.EventBookingItemChange, got[0].EventType)
+	assert.Equal(t, laser.Name, got[0].ItemName)
+	assert.True(t, got[0].Available)
+
+	// Подтверждение не меняет занятость
+	require.NoError(t, db.UpdateBookingStatusWithVersion(ctx, booking.ID, 2, models.StatusConfirmed))
+	require.NoError(t, w.poll(ctx))
+	assert.Empty(t, drainChanges(changes))
+
+	cancel()
+	_, ok := <-changes
+	assert.False(t, ok)
+}
+
+func TestAvailabilityWatcher_ClosesSlowSubscriber(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	item := createTestItem(t, db, "laser", 3)
+
+	w := NewAvailabilityWatcher(db, config.APIStreamConfig{BufferSize: 1}, nil)
+	require.NoError(t, w.poll(ctx))
+	changes, cancel := w.Subscribe(AvailabilityFilter{})
+	defer cancel()
+
+	day := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	end := day.AddDate(0, 0, 2)
+	createWatchBooking(t, db, &item, day, &end)
+	require.NoError(t, w.poll(ctx))
+
+	got := drainChanges(changes)
+	assert.Len(t, got, 1)
+	_, ok := <-changes
+	assert.False(t, ok, "channel must be closed after overflow")
+}
+
+func TestAvailabilityStreamSSE(t *testing.T) {
+	db := newTestDB(t)
+	item := createTestItem(t, db, "laser", 1)
+	server := newTestHTTPServer(db)
+
+	watcher := NewAvailabilityWatcher(db, config.APIStreamConfig{PollIntervalMillis: 10}, nil)
+	require.NoError(t, watcher.poll(context.Background()))
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go watcher.Start(ctx)
+	server.SetAvailabilityWatcher(watcher)
+
+	ts := httptest.NewServer(server.server.Handler)
+	t.Cleanup(ts.Close)
+
+	resp, err := http.Get(ts.URL + "/api/v1/availability/stream?start_date=2025-12-31&end_date=2025-12-01")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Get(ts.URL + "/api/v1/availability/stream?items=unknown")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	reqCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(reqCtx, http.MethodGet,
+		ts.URL+"/api/v1/availability/stream?items=LASER&start_date=2025-12-01&end_date=2025-12-31", http.NoBody)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	booking := createWatchBooking(t, db, &item, time.Date(2025, 12, 5, 0, 0, 0, 0, time.UTC), nil)
+
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+		if line == "" && len(lines) > 0 {
+			break
+		}
+		if line != "" && !strings.HasPrefix(line, ":") {
+			lines = append(lines, line)
+		}
+	}
+	require.Len(t, lines, 3)
+	assert.Equal(t, "event: availability", lines[1])
+
+	var ev AvailabilityStreamEvent
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &ev))
+	assert.Equal(t, booking.ID, ev.BookingID)
+	assert.Equal(t, "2025-12-05", ev.Date)
+	assert.False(t, ev.Available)
+
+	// Остановка наблюдателя завершает поток
+	stop()
+	_, err = io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+}
+
+func TestWatchAvailabilityGRPC(t *testing.T) {
+	db := newTestDB(t)
+	item := createTestItem(t, db, "laser", 2)
+	logger := zerolog.New(io.Discard)
+	cfg := config.APIConfig{
+		Enabled: true,
+		GRPC:    config.APIGRPCConfig{Port: 0},
+		Auth: config.APIAuthConfig{
+			Enabled: true,
+			APIKeys: []config.APIClientKey{
+				{Key: "reader", Extra: "x", Permissions: []string{"read:availability"}},
+				{Key: "items", Extra: "x", Permissions: []string{"read:items"}},
+			},
+		},
+	}
+	srv, err := NewGRPCServer(&cfg, db, &logger)
+	require.NoError(t, err)
+
+	watcher := NewAvailabilityWatcher(db, config.APIStreamConfig{PollIntervalMillis: 10}, nil)
+	require.NoError(t, watcher.poll(context.Background()))
+	watchCtx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go watcher.Start(watchCtx)
+	srv.SetAvailabilityWatcher(watcher)
+
+	go func() { _ = srv.Serve() }()
+	t.Cleanup(func() { srv.Shutdown(context.Background()) })
+
+	_, port, _ := net.SplitHostPort(srv.Addr())
+	conn, err := grpc.NewClient("127.0.0.1:"+port, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := availabilityv1.NewAvailabilityServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Потоковый метод проверяется тем же AuthInterceptor
+	denied, err := client.WatchAvailability(withAPIKey(ctx, "items"), &availabilityv1.WatchAvailabilityRequest{})
+	require.NoError(t, err)
+	_, err = denied.Recv()
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	stream, err := client.WatchAvailability(withAPIKey(ctx, "reader"), &availabilityv1.WatchAvailabilityRequest{
+		Items: []string{item.Name}, StartDate: "2025-12-01", EndDate: "2025-12-31",
+	})
+	require.NoError(t, err)
+
+	// Подписка регистрируется асинхронно: повторяем создание заявки, пока не придет изменение
+	received := make(chan *availabilityv1.AvailabilityDelta, 1)
+	go func() {
+		delta, err := stream.Recv()
+		if err == nil {
+			received <- delta
+		}
+		close(received)
+	}()
+
+	var delta *availabilityv1.AvailabilityDelta
+	date := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	for delta == nil {
+		createWatchBooking(t, db, &item, date, nil)
+		select {
+		case delta = <-received:
+			require.NotNil(t, delta)
+		case <-time.After(100 * time.Millisecond):
+			date = date.AddDate(0, 0, 1)
+		}
+	}
+	assert.Equal(t, events.EventBookingCreated, delta.GetEventType())
+	assert.Equal(t, item.Name, delta.GetAvailability().GetItemName())
+	assert.Equal(t, int64(1), delta.GetAvailability().GetBookedCount())
+	assert.True(t, delta.GetAvailability().GetAvailable())
+
+	stop()
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func withAPIKey(ctx context.Context, key string) context.Context {
+	return metadata.NewOutgoingContext(ctx, metadata.Pairs("x-api-key", key, "x-api-extra", "x"))
+}
@@ -0,0 +1,313 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	bookingv1 "bronivik/internal/api/gen/booking/v1"
+	"bronivik/internal/database"
+	"bronivik/internal/domain"
+	"bronivik/internal/models"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultBookingsPageSize = 50
+	maxBookingsPageSize     = 500
+)
+
+var errInvalidPageToken = errors.New("invalid page token")
+
+// BookingGRPCService implements bookingv1.BookingServiceServer on top of the booking service.
+// Status transitions are checked with the API client rules set by AuthInterceptor.
+type BookingGRPCService struct {
+	bookingv1.UnimplementedBookingServiceServer
+	db       *database.DB
+	bookings domain.BookingService
+}
+
+func NewBookingGRPCService(db *database.DB) *BookingGRPCService {
+	return &BookingGRPCService{db: db}
+}
+
+func (s *BookingGRPCService) service() (domain.BookingService, error) {
+	if s.bookings == nil {
+		return nil, status.Error(codes.Unavailable, "booking service is not configured")
+	}
+	return s.bookings, nil
+}
+
+func (s *BookingGRPCService) CreateBooking(ctx context.Context, req *bookingv1.CreateBookingRequest) (*bookingv1.Booking, error) {
+	svc, err := s.service()
+	if err != nil {
+		return nil, err
+	}
+
+	clientName := strings.TrimSpace(req.GetClientName())
+	clientPhone := strings.TrimSpace(req.GetClientPhone())
+	if clientName == "" || clientPhone == "" {
+		return nil, status.Error(codes.InvalidArgument, "client_name and client_phone are required")
+	}
+	date, endTime, err := parseBookingDates(req.GetDate(), req.GetEndDate())
+	if err != nil {
+		return nil, err
+	}
+	item, err := s.resolveItem(ctx, req.GetItemId(), req.GetItemName())
+	if err != nil {
+		return nil, err
+	}
+
+	booking := &models.Booking{
+		UserName:     clientName,
+		UserNickname: clientName,
+		Phone:        clientPhone,
+		ItemID:       item.ID,
+		ItemName:     item.Name,
+		Date:         date,
+		EndTime:      endTime,
+		Status:       models.StatusPending,
+		Comment:      req.GetComment(),
+	}
+	if err := svc.CreateBooking(ctx, booking); err != nil {
+		return nil, bookingError(err)
+	}
+	return s.reload(ctx, svc, booking.ID)
+}
+
+func (s *BookingGRPCService) GetBooking(ctx context.Context, req *bookingv1.GetBookingRequest) (*bookingv1.Booking, error) {
+	svc, err := s.service()
+	if err != nil {
+		return nil, err
+	}
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	return s.reload(ctx, svc, req.GetId())
+}
+
+func (s *BookingGRPCService) ListBookings(ctx context.Context, req *bookingv1.ListBookingsRequest) (
+	*bookingv1.ListBookingsResponse, error) {
+	svc, err := s.service()
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := int(req.GetPageSize())
+	switch {
+	case pageSize < 0:
+		return nil, status.Error(codes.InvalidArgument, "page_size must not be negative")
+	case pageSize == 0:
+		pageSize = defaultBookingsPageSize
+	case pageSize > maxBookingsPageSize:
+		pageSize = maxBookingsPageSize
+	}
+
+	afterID, err := decodePageToken(req.GetPageToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+	}
+	for _, st := range req.GetStatuses() {
+		if !models.IsKnownStatus(st) {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown status %q", st)
+		}
+	}
+
+	filter := &models.BookingFilter{
+		ItemID:   req.GetItemId(),
+		UserID:   req.GetUserId(),
+		Statuses: req.GetStatuses(),
+		AfterID:  afterID,
+		Limit:    pageSize + 1, // лишняя запись показывает, что есть следующая страница
+	}
+	if filter.From, err = parseOptionalDate("start_date", req.GetStartDate()); err != nil {
+		return nil, err
+	}
+	if filter.To, err = parseOptionalDate("end_date", req.GetEndDate()); err != nil {
+		return nil, err
+	}
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return nil, status.Error(codes.InvalidArgument, "start_date must be before or equal to end_date")
+	}
+
+	bookings, err := svc.ListBookings(ctx, filter)
+	if err != nil {
+		return nil, bookingError(err)
+	}
+
+	resp := &bookingv1.ListBookingsResponse{}
+	if len(bookings) > pageSize {
+		bookings = bookings[:pageSize]
+		resp.NextPageToken = encodePageToken(bookings[len(bookings)-1].ID)
+	}
+	resp.Bookings = make([]*bookingv1.Booking, 0, len(bookings))
+	for _, b := range bookings {
+		resp.Bookings = append(resp.Bookings, bookingToProto(b))
+	}
+	return resp, nil
+}
+
+func (s *BookingGRPCService) CancelBooking(ctx context.Context, req *bookingv1.CancelBookingRequest) (*bookingv1.Booking, error) {
+	svc, err := s.service()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateBookingRef(req.GetId(), req.GetVersion()); err != nil {
+		return nil, err
+	}
+	ctx = withReason(ctx, req.GetReason())
+	if err := svc.RejectBooking(ctx, req.GetId(), req.GetVersion(), 0); err != nil {
+		return nil, bookingError(err)
+	}
+	return s.reload(ctx, svc, req.GetId())
+}
+
+func (s *BookingGRPCService) ConfirmBooking(ctx context.Context, req *bookingv1.ConfirmBookingRequest) (*bookingv1.Booking, error) {
+	svc, err := s.service()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateBookingRef(req.GetId(), req.GetVersion()); err != nil {
+		return nil, err
+	}
+	if err := svc.ConfirmBooking(ctx, req.GetId(), req.GetVersion(), 0); err != nil {
+		return nil, bookingError(err)
+	}
+	return s.reload(ctx, svc, req.GetId())
+}
+
+func (s *BookingGRPCService) RescheduleBooking(ctx context.Context, req *bookingv1.RescheduleBookingRequest) (
+	*bookingv1.Booking, error) {
+	svc, err := s.service()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateBookingRef(req.GetId(), req.GetVersion()); err != nil {
+		return nil, err
+	}
+	date, endTime, err := parseBookingDates(req.GetDate(), req.GetEndDate())
+	if err != nil {
+		return nil, err
+	}
+	ctx = withReason(ctx, req.GetReason())
+	if err := svc.RescheduleBooking(ctx, req.GetId(), req.GetVersion(), date, endTime, 0); err != nil {
+		return nil, bookingError(err)
+	}
+	return s.reload(ctx, svc, req.GetId())
+}
+
+// reload перечитывает заявку, чтобы вернуть клиенту актуальные статус и версию.
+func (s *BookingGRPCService) reload(ctx context.Context, svc domain.BookingService, id int64) (*bookingv1.Booking, error) {
+	booking, err := svc.GetBooking(ctx, id)
+	if err != nil {
+		return nil, bookingError(err)
+	}
+	return bookingToProto(booking), nil
+}
+
+func (s *BookingGRPCService) resolveItem(ctx context.Context, itemID int64, itemName string) (*models.Item, error) {
+	if itemID <= 0 {
+		if strings.TrimSpace(itemName) == "" {
+			return nil, status.Error(codes.InvalidArgument, "item_id or item_name is required")
+		}
+		ids, err := resolveItemIDs(s.db, []string{itemName})
+		if err != nil {
+			return nil, status.Error(codes.NotFound, "item not found")
+		}
+		itemID = ids[0]
+	}
+
+	item, err := s.db.GetItemByID(ctx, itemID)
+	if err != nil || !item.IsActive {
+		return nil, status.Error(codes.NotFound, "item not found")
+	}
+	return item, nil
+}
+
+func validateBookingRef(id, version int64) error {
+	if id <= 0 {
+		return status.Error(codes.InvalidArgument, "id is required")
+	}
+	if version <= 0 {
+		return status.Error(codes.InvalidArgument, "version is required")
+	}
+	return nil
+}
+
+func withReason(ctx context.Context, reason string) context.Context {
+	if reason = strings.TrimSpace(reason); reason != "" {
+		return models.WithChangeReason(ctx, reason)
+	}
+	return ctx
+}
+
+// parseBookingDates разбирает первый и необязательный последний день заявки.
+// Совпадающие даты означают однодневную заявку.
+func parseBookingDates(date, endDate string) (time.Time, *time.Time, error) {
+	start, err := time.Parse("2006-01-02", strings.TrimSpace(date))
+	if err != nil {
+		return time.Time{}, nil, status.Error(codes.InvalidArgument, "invalid date format; expected YYYY-MM-DD")
+	}
+	end, err := parseOptionalDate("end_date", endDate)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	if end != nil && end.Equal(start) {
+		end = nil
+	}
+	return start, end, nil
+}
+
+func parseOptionalDate(field, value string) (*time.Time, error) {
+	date, err := parseDateParam(value)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid %s format; expected YYYY-MM-DD", field)
+	}
+	return date, nil
+}
+
+// encodePageToken и decodePageToken скрывают от клиентов, что курсор — это ID последней заявки.
+func encodePageToken(lastID int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(lastID, 10)))
+}
+
+func decodePageToken(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || id < 0 {
+		return 0, errInvalidPageToken
+	}
+	return id, nil
+}
+
+func bookingToProto(b *models.Booking) *bookingv1.Booking {
+	pb := &bookingv1.Booking{
+		Id:          b.ID,
+		ItemId:      b.ItemID,
+		ItemName:    b.ItemName,
+		Date:        b.Date.Format("2006-01-02"),
+		Status:      b.Status,
+		ClientName:  b.UserName,
+		ClientPhone: b.Phone,
+		Comment:     b.Comment,
+		UserId:      b.UserID,
+		Version:     b.Version,
+		CreatedAt:   b.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   b.UpdatedAt.Format(time.RFC3339),
+	}
+	if b.IsRangeBooking() {
+		pb.EndDate = b.EndTime.Format("2006-01-02")
+	}
+	return pb
+}
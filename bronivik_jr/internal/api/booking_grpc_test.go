@@ -0,0 +1,197 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	bookingv1 "bronivik/internal/api/gen/booking/v1"
+	"bronivik/internal/config"
+	"bronivik/internal/database"
+	"bronivik/internal/models"
+	"bronivik/internal/service"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func newBookingGRPCClient(t *testing.T, db *database.DB, withService bool) bookingv1.BookingServiceClient {
+	t.Helper()
+	logger := zerolog.New(io.Discard)
+	cfg := config.APIConfig{
+		Enabled: true,
+		GRPC:    config.APIGRPCConfig{Port: 0},
+		Auth: config.APIAuthConfig{
+			Enabled: true,
+			APIKeys: []config.APIClientKey{
+				{Key: "writer", Name: "crm", Extra: "x", Permissions: []string{"read:bookings", "write:bookings"}},
+				{Key: "reader", Extra: "x", Permissions: []string{"read:bookings"}},
+			},
+		},
+	}
+	srv, err := NewGRPCServer(&cfg, db, &logger)
+	require.NoError(t, err)
+	if withService {
+		srv.SetBookingService(service.NewBookingService(db, nil, 365, 0, &logger))
+	}
+
+	go func() { _ = srv.Serve() }()
+	t.Cleanup(func() { srv.Shutdown(context.Background()) })
+
+	_, port, _ := net.SplitHostPort(srv.Addr())
+	conn, err := grpc.NewClient("127.0.0.1:"+port, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return bookingv1.NewBookingServiceClient(conn)
+}
+
+func TestBookingServiceGRPC(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	item := &models.Item{Name: "Laser", TotalQuantity: 1, IsActive: true}
+	require.NoError(t, db.CreateItem(ctx, item))
+	client := newBookingGRPCClient(t, db, true)
+
+	writer := withAPIKey(ctx, "writer")
+	reader := withAPIKey(ctx, "reader")
+	day := time.Now().AddDate(0, 0, 10)
+	date := day.Format("2006-01-02")
+	endDate := day.AddDate(0, 0, 2).Format("2006-01-02")
+
+	// Ключ без write:bookings не может создавать заявки
+	_, err := client.CreateBooking(reader, &bookingv1.CreateBookingRequest{
+		ItemName: "laser", Date: date, ClientName: "Ivan", ClientPhone: "+7900",
+	})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.CreateBooking(writer, &bookingv1.CreateBookingRequest{ItemName: "laser", Date: date})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.CreateBooking(writer, &bookingv1.CreateBookingRequest{
+		ItemName: "unknown", Date: date, ClientName: "Ivan", ClientPhone: "+7900",
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	created, err := client.CreateBooking(writer, &bookingv1.CreateBookingRequest{
+		ItemName: "laser", Date: date, EndDate: endDate, ClientName: "Ivan", ClientPhone: "+7900", Comment: "CRM",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPending, created.GetStatus())
+	assert.Equal(t, item.ID, created.GetItemId())
+	assert.Equal(t, date, created.GetDate())
+	assert.Equal(t, endDate, created.GetEndDate())
+	assert.Equal(t, int64(1), created.GetVersion())
+
+	// Единственный аппарат уже занят на эти даты
+	_, err = client.CreateBooking(writer, &bookingv1.CreateBookingRequest{
+		ItemId: item.ID, Date: endDate, ClientName: "Petr", ClientPhone: "+7901",
+	})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	got, err := client.GetBooking(reader, &bookingv1.GetBookingRequest{Id: created.GetId()})
+	require.NoError(t, err)
+	assert.Equal(t, "Ivan", got.GetClientName())
+	_, err = client.GetBooking(reader, &bookingv1.GetBookingRequest{Id: 999})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// Устаревшая версия отклоняется
+	_, err = client.ConfirmBooking(writer, &bookingv1.ConfirmBookingRequest{Id: created.GetId(), Version: 5})
+	assert.Equal(t, codes.Aborted, status.Code(err))
+	_, err = client.ConfirmBooking(writer, &bookingv1.ConfirmBookingRequest{Id: created.GetId()})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	confirmed, err := client.ConfirmBooking(writer, &bookingv1.ConfirmBookingRequest{Id: created.GetId(), Version: 1})
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusConfirmed, confirmed.GetStatus())
+	assert.Equal(t, int64(2), confirmed.GetVersion())
+
+	newDate := day.AddDate(0, 0, 5).Format("2006-01-02")
+	moved, err := client.RescheduleBooking(writer, &bookingv1.RescheduleBookingRequest{
+		Id: created.GetId(), Version: confirmed.GetVersion(), Date: newDate, Reason: "client request",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusChanged, moved.GetStatus())
+	assert.Equal(t, newDate, moved.GetDate())
+	assert.Empty(t, moved.GetEndDate())
+
+	canceled, err := client.CancelBooking(writer, &bookingv1.CancelBookingRequest{
+		Id: created.GetId(), Version: moved.GetVersion(), Reason: "no longer needed",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusCanceled, canceled.GetStatus())
+
+	// Отмененную заявку нельзя подтвердить по правилам API-клиентов
+	_, err = client.ConfirmBooking(writer, &bookingv1.ConfirmBookingRequest{Id: created.GetId(), Version: canceled.GetVersion()})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// Изменения записаны в историю от имени API-клиента
+	history, err := db.GetBookingHistory(ctx, created.GetId())
+	require.NoError(t, err)
+	require.NotEmpty(t, history)
+	last := history[len(history)-1]
+	assert.Equal(t, models.ActorAPI, last.ActorType)
+	assert.Equal(t, "crm", last.ActorName)
+	assert.Equal(t, "no longer needed", last.Reason)
+}
+
+func TestBookingServiceGRPC_ListBookings(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	laser := createTestItem(t, db, "laser", 5)
+	camera := createTestItem(t, db, "camera", 5)
+	client := newBookingGRPCClient(t, db, true)
+	reader := withAPIKey(ctx, "reader")
+
+	day := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	var laserIDs []int64
+	for i := 0; i < 3; i++ {
+		laserIDs = append(laserIDs, createWatchBooking(t, db, &laser, day.AddDate(0, 0, i), nil).ID)
+	}
+	createWatchBooking(t, db, &camera, day, nil)
+
+	var pages [][]int64
+	token := ""
+	for {
+		resp, err := client.ListBookings(reader, &bookingv1.ListBookingsRequest{
+			ItemId: laser.ID, PageSize: 2, PageToken: token,
+		})
+		require.NoError(t, err)
+		var ids []int64
+		for _, b := range resp.GetBookings() {
+			ids = append(ids, b.GetId())
+		}
+		pages = append(pages, ids)
+		if token = resp.GetNextPageToken(); token == "" {
+			break
+		}
+	}
+	assert.Equal(t, [][]int64{laserIDs[:2], laserIDs[2:]}, pages)
+
+	resp, err := client.ListBookings(reader, &bookingv1.ListBookingsRequest{
+		StartDate: "2025-12-02", EndDate: "2025-12-02", Statuses: []string{models.StatusPending},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.GetBookings(), 1)
+	assert.Equal(t, laserIDs[1], resp.GetBookings()[0].GetId())
+
+	_, err = client.ListBookings(reader, &bookingv1.ListBookingsRequest{PageToken: "not-a-token"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.ListBookings(reader, &bookingv1.ListBookingsRequest{Statuses: []string{"lost"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.ListBookings(reader, &bookingv1.ListBookingsRequest{StartDate: "2025-12-05", EndDate: "2025-12-01"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestBookingServiceGRPC_NotConfigured(t *testing.T) {
+	db := newTestDB(t)
+	client := newBookingGRPCClient(t, db, false)
+
+	_, err := client.GetBooking(withAPIKey(context.Background(), "reader"), &bookingv1.GetBookingRequest{Id: 1})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
@@ -0,0 +1,411 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"bronivik/internal/metrics"
+	"bronivik/internal/models"
+)
+
+const bookingsPathPrefix = "/api/v1/bookings"
+
+var errInvalidID = errors.New("invalid id")
+
+// BookingHistoryResponse is the response for GET /api/v1/bookings/{id}/history.
+type BookingHistoryResponse struct {
+	BookingID int64                         `json:"booking_id"`
+	History   []*models.BookingHistoryEntry `json:"history"`
+}
+
+// BookingListResponse is the response for GET /api/v1/bookings.
+type BookingListResponse struct {
+	Bookings   []*models.Booking `json:"bookings"`
+	NextCursor string            `json:"next_cursor,omitempty"` // empty on the last page
+}
+
+// CreateBookingRequest is the request body for POST /api/v1/bookings.
+type CreateBookingRequest struct {
+	ItemID            int64  `json:"item_id"`
+	ItemName          string `json:"item_name,omitempty"` // Alternative to item_id
+	Date              string `json:"date"`                // Format: YYYY-MM-DD
+	EndDate           string `json:"end_date,omitempty"`  // Format: YYYY-MM-DD, for multi-day bookings
+	UserID            int64  `json:"user_id,omitempty"`
+	ClientName        string `json:"client_name"`
+	ClientPhone       string `json:"client_phone"`
+	Comment           string `json:"comment,omitempty"`
+	ExternalBookingID string `json:"external_booking_id,omitempty"`
+	Status            string `json:"status,omitempty"` // pending (default) or confirmed
+}
+
+// UpdateBookingRequest is the request body for PATCH /api/v1/bookings/{id}.
+// Exactly one change is applied per request: status, item_id or date (with optional end_date).
+type UpdateBookingRequest struct {
+	Version int64   `json:"version"`
+	Status  string  `json:"status,omitempty"`
+	ItemID  int64   `json:"item_id,omitempty"`
+	Date    string  `json:"date,omitempty"`
+	EndDate *string `json:"end_date,omitempty"`
+}
+
+// handleBookings routes booking endpoints:
+//
+//	GET    /api/v1/bookings
+//	POST   /api/v1/bookings
+//	GET    /api/v1/bookings/{id}
+//	PATCH  /api/v1/bookings/{id}
+//	DELETE /api/v1/bookings/{id}?version=N
+//	GET    /api/v1/bookings/{id}/history
+func (s *HTTPServer) handleBookings(w http.ResponseWriter, r *http.Request) {
+	metrics.IncHTTP("bookings")
+	if s.bookingService == nil {
+		writeError(w, http.StatusServiceUnavailable, "booking service is not configured")
+		return
+	}
+	r = withChangeReason(r)
+
+	parts := splitPath(strings.TrimPrefix(r.URL.Path, bookingsPathPrefix))
+	if len(parts) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			s.listBookings(w, r)
+		case http.MethodPost:
+			s.createBooking(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+	if len(parts) > 2 || (len(parts) == 2 && parts[1] != "history") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	bookingID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || bookingID <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid booking id")
+		return
+	}
+
+	if len(parts) == 2 {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		s.getBookingHistory(w, r, bookingID)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.writeBooking(w, r, http.StatusOK, bookingID)
+	case http.MethodPatch:
+		s.updateBooking(w, r, bookingID)
+	case http.MethodDelete:
+		s.cancelBooking(w, r, bookingID)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *HTTPServer) listBookings(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := &models.BookingFilter{
+		ExternalBookingID: strings.TrimSpace(query.Get("external_booking_id")),
+		Phone:             strings.TrimSpace(query.Get("phone")),
+		Statuses:          splitCSV(query.Get("status")),
+	}
+	for _, st := range filter.Statuses {
+		if !models.IsKnownStatus(st) {
+			writeError(w, http.StatusBadRequest, "unknown status: "+st)
+			return
+		}
+	}
+
+	var err error
+	if filter.ItemID, err = parseIDParam(query.Get("item_id")); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid item_id")
+		return
+	}
+	if name := strings.TrimSpace(query.Get("item")); name != "" && filter.ItemID == 0 {
+		ids, err := resolveItemIDs(s.db, []string{name})
+		if err != nil {
+			writeError(w, http.StatusNotFound, "item not found")
+			return
+		}
+		filter.ItemID = ids[0]
+	}
+	if filter.UserID, err = parseIDParam(query.Get("user_id")); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid user_id")
+		return
+	}
+	if filter.From, err = parseDateParam(query.Get("start_date")); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid start_date format; expected YYYY-MM-DD")
+		return
+	}
+	if filter.To, err = parseDateParam(query.Get("end_date")); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid end_date format; expected YYYY-MM-DD")
+		return
+	}
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		writeError(w, http.StatusBadRequest, "start_date must be before or equal to end_date")
+		return
+	}
+	if filter.AfterID, err = decodePageToken(query.Get("cursor")); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid cursor")
+		return
+	}
+
+	limit := defaultBookingsPageSize
+	if raw := query.Get("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = min(limit, maxBookingsPageSize)
+	}
+	// Лишняя запись показывает, что есть следующая страница
+	filter.Limit = limit + 1
+
+	bookings, err := s.bookingService.ListBookings(r.Context(), filter)
+	if err != nil {
+		s.writeSeriesError(w, err)
+		return
+	}
+
+	resp := BookingListResponse{Bookings: bookings}
+	if len(bookings) > limit {
+		resp.Bookings = bookings[:limit]
+		resp.NextCursor = encodePageToken(resp.Bookings[limit-1].ID)
+	}
+	if resp.Bookings == nil {
+		resp.Bookings = []*models.Booking{}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *HTTPServer) createBooking(w http.ResponseWriter, r *http.Request) {
+	var req CreateBookingRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	if req.ClientName == "" || req.ClientPhone == "" {
+		writeError(w, http.StatusBadRequest, "client_name and client_phone are required")
+		return
+	}
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid date format; expected YYYY-MM-DD")
+		return
+	}
+	endTime, err := parseDateParam(req.EndDate)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid end_date format; expected YYYY-MM-DD")
+		return
+	}
+	if endTime != nil && endTime.Equal(date) {
+		endTime = nil
+	}
+
+	status := req.Status
+	if status == "" {
+		status = models.StatusPending
+	}
+	if status != models.StatusPending && status != models.StatusConfirmed {
+		writeError(w, http.StatusBadRequest, "status must be pending or confirmed")
+		return
+	}
+
+	var item *models.Item
+	switch {
+	case req.ItemID > 0:
+		item, err = s.db.GetItemByID(r.Context(), req.ItemID)
+	case req.ItemName != "":
+		item, err = s.db.GetItemByName(r.Context(), req.ItemName)
+	default:
+		writeError(w, http.StatusBadRequest, "item_id or item_name is required")
+		return
+	}
+	if err != nil || !item.IsActive {
+		writeError(w, http.StatusNotFound, "item not found")
+		return
+	}
+
+	booking := &models.Booking{
+		UserID:            req.UserID,
+		UserName:          req.ClientName,
+		UserNickname:      req.ClientName,
+		Phone:             req.ClientPhone,
+		ItemID:            item.ID,
+		ItemName:          item.Name,
+		Date:              date,
+		EndTime:           endTime,
+		Status:            status,
+		Comment:           req.Comment,
+		ExternalBookingID: strings.TrimSpace(req.ExternalBookingID),
+	}
+	if err := s.bookingService.CreateBooking(r.Context(), booking); err != nil {
+		s.writeSeriesError(w, err)
+		return
+	}
+
+	s.log.Info().Int64("booking_id", booking.ID).Str("item", item.Name).Msg("booking created via API")
+	s.writeBooking(w, r, http.StatusCreated, booking.ID)
+}
+
+func (s *HTTPServer) updateBooking(w http.ResponseWriter, r *http.Request, bookingID int64) {
+	var req UpdateBookingRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.Version <= 0 {
+		writeError(w, http.StatusBadRequest, "version is required")
+		return
+	}
+
+	changes := 0
+	for _, set := range []bool{req.Status != "", req.ItemID > 0, req.Date != ""} {
+		if set {
+			changes++
+		}
+	}
+	if changes != 1 {
+		writeError(w, http.StatusBadRequest, "exactly one of status, item_id or date must be set")
+		return
+	}
+	if req.EndDate != nil && req.Date == "" {
+		writeError(w, http.StatusBadRequest, "end_date requires date")
+		return
+	}
+	if req.Status != "" && (!models.IsKnownStatus(req.Status) || req.Status == models.StatusChanged) {
+		writeError(w, http.StatusBadRequest, "status must be pending, confirmed, canceled or completed")
+		return
+	}
+
+	ctx := r.Context()
+	var err error
+	switch {
+	case req.Status != "":
+		err = s.applyBookingStatus(ctx, bookingID, req.Version, req.Status)
+	case req.ItemID > 0:
+		err = s.bookingService.ChangeBookingItem(ctx, bookingID, req.Version, req.ItemID, 0)
+	default:
+		date, parseErr := time.Parse("2006-01-02", req.Date)
+		if parseErr != nil {
+			writeError(w, http.StatusBadRequest, "invalid date format; expected YYYY-MM-DD")
+			return
+		}
+		var endTime *time.Time
+		if req.EndDate != nil {
+			if endTime, parseErr = parseDateParam(*req.EndDate); parseErr != nil {
+				writeError(w, http.StatusBadRequest, "invalid end_date format; expected YYYY-MM-DD")
+				return
+			}
+			if endTime != nil && endTime.Equal(date) {
+				endTime = nil
+			}
+		}
+		err = s.bookingService.RescheduleBooking(ctx, bookingID, req.Version, date, endTime, 0)
+	}
+	if err != nil {
+		s.writeSeriesError(w, err)
+		return
+	}
+	s.writeBooking(w, r, http.StatusOK, bookingID)
+}
+
+// applyBookingStatus переводит заявку в статус; допустимость перехода проверяет сервис.
+// Статус changed выставляется только сменой аппарата или дат.
+func (s *HTTPServer) applyBookingStatus(ctx context.Context, bookingID, version int64, status string) error {
+	switch status {
+	case models.StatusConfirmed:
+		return s.bookingService.ConfirmBooking(ctx, bookingID, version, 0)
+	case models.StatusCanceled:
+		return s.bookingService.RejectBooking(ctx, bookingID, version, 0)
+	case models.StatusCompleted:
+		return s.bookingService.CompleteBooking(ctx, bookingID, version, 0)
+	case models.StatusPending:
+		return s.bookingService.ReopenBooking(ctx, bookingID, version, 0)
+	default:
+		return &models.UnknownStatusError{Status: status}
+	}
+}
+
+// cancelBooking отменяет заявку; запись не удаляется и остается в истории.
+func (s *HTTPServer) cancelBooking(w http.ResponseWriter, r *http.Request, bookingID int64) {
+	version, err := strconv.ParseInt(r.URL.Query().Get("version"), 10, 64)
+	if err != nil || version <= 0 {
+		writeError(w, http.StatusBadRequest, "version is required")
+		return
+	}
+	if err := s.bookingService.RejectBooking(r.Context(), bookingID, version, 0); err != nil {
+		s.writeSeriesError(w, err)
+		return
+	}
+	s.writeBooking(w, r, http.StatusOK, bookingID)
+}
+
+func (s *HTTPServer) writeBooking(w http.ResponseWriter, r *http.Request, statusCode int, bookingID int64) {
+	booking, err := s.bookingService.GetBooking(r.Context(), bookingID)
+	if err != nil {
+		s.writeSeriesError(w, err)
+		return
+	}
+	writeJSON(w, statusCode, booking)
+}
+
+func (s *HTTPServer) getBookingHistory(w http.ResponseWriter, r *http.Request, bookingID int64) {
+	history, err := s.bookingService.GetBookingHistory(r.Context(), bookingID)
+	if err != nil {
+		s.writeSeriesError(w, err)
+		return
+	}
+	if history == nil {
+		history = []*models.BookingHistoryEntry{}
+	}
+	writeJSON(w, http.StatusOK, BookingHistoryResponse{BookingID: bookingID, History: history})
+}
+
+// withChangeReason добавляет к контексту причину изменения из параметра ?reason=.
+func withChangeReason(r *http.Request) *http.Request {
+	reason := strings.TrimSpace(r.URL.Query().Get("reason"))
+	if reason == "" {
+		return r
+	}
+	return r.WithContext(models.WithChangeReason(r.Context(), reason))
+}
+
+func parseIDParam(raw string) (int64, error) {
+	if raw = strings.TrimSpace(raw); raw == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errInvalidID
+	}
+	return id, nil
+}
+
+func parseDateParam(raw string) (*time.Time, error) {
+	if raw = strings.TrimSpace(raw); raw == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
@@ -0,0 +1,267 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"bronivik/internal/config"
+	"bronivik/internal/models"
+	"bronivik/internal/service"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookingHistoryAPI(t *testing.T) {
+	db := newTestDB(t)
+	item := createTestItem(t, db, "camera", 1)
+
+	logger := zerolog.New(io.Discard)
+	server := newTestHTTPServer(db)
+	server.SetBookingService(service.NewBookingService(db, nil, 365, 0, &logger))
+	ts := httptest.NewServer(server.server.Handler)
+	t.Cleanup(ts.Close)
+
+	start := time.Now().AddDate(0, 0, 7)
+	body := fmt.Sprintf(`{"item_id": %d, "start_date": %q, "frequency": "weekly", "count": 1,
+		"client_name": "Client", "client_phone": "+79990000000"}`, item.ID, start.Format("2006-01-02"))
+	resp, err := http.Post(ts.URL+"/api/v1/booking-series", "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var created models.SeriesResult
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	require.Len(t, created.Bookings, 1)
+	bookingID := created.Bookings[0].ID
+
+	cancelURL := fmt.Sprintf("%s/api/v1/booking-series/%d/occurrences/%d/cancel?reason=%s",
+		ts.URL, created.Series.ID, bookingID, "client%20request")
+	cancelResp, err := http.Post(cancelURL, "application/json", nil)
+	require.NoError(t, err)
+	defer cancelResp.Body.Close()
+	require.Equal(t, http.StatusOK, cancelResp.StatusCode)
+
+	histResp, err := http.Get(fmt.Sprintf("%s/api/v1/bookings/%d/history", ts.URL, bookingID))
+	require.NoError(t, err)
+	defer histResp.Body.Close()
+	require.Equal(t, http.StatusOK, histResp.StatusCode)
+
+	var history BookingHistoryResponse
+	require.NoError(t, json.NewDecoder(histResp.Body).Decode(&history))
+	require.Len(t, history.History, 2)
+	assert.Equal(t, "", history.History[0].OldValue)
+	assert.Equal(t, models.StatusPending, history.History[0].NewValue)
+	assert.Equal(t, models.ActorAPI, history.History[0].ActorType)
+
+	canceled := history.History[1]
+	assert.Equal(t, models.HistoryFieldStatus, canceled.Field)
+	assert.Equal(t, models.StatusPending, canceled.OldValue)
+	assert.Equal(t, models.StatusCanceled, canceled.NewValue)
+	assert.Equal(t, models.ActorAPI, canceled.ActorType)
+	assert.Equal(t, "client request", canceled.Reason)
+
+	missing, err := http.Get(ts.URL + "/api/v1/bookings/9999/history")
+	require.NoError(t, err)
+	defer missing.Body.Close()
+	assert.Equal(t, http.StatusNotFound, missing.StatusCode)
+}
+
+func doJSON(t *testing.T, method, url, body string) (*http.Response, []byte) {
+	t.Helper()
+	var reader io.Reader = http.NoBody
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, data
+}
+
+func TestBookingsAPI_CRUD(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	laser := &models.Item{Name: "laser", TotalQuantity: 1, IsActive: true}
+	camera := &models.Item{Name: "camera", TotalQuantity: 1, IsActive: true}
+	require.NoError(t, db.CreateItem(ctx, laser))
+	require.NoError(t, db.CreateItem(ctx, camera))
+
+	logger := zerolog.New(io.Discard)
+	server := newTestHTTPServer(db)
+	server.SetBookingService(service.NewBookingService(db, nil, 365, 0, &logger))
+	ts := httptest.NewServer(server.server.Handler)
+	t.Cleanup(ts.Close)
+
+	day := time.Now().AddDate(0, 0, 7)
+	date := day.Format("2006-01-02")
+	endDate := day.AddDate(0, 0, 1).Format("2006-01-02")
+
+	resp, _ := doJSON(t, http.MethodPost, ts.URL+"/api/v1/bookings", `{"item_name": "laser", "date": "`+date+`"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	body := fmt.Sprintf(`{"item_name": "laser", "date": %q, "end_date": %q, "client_name": "Ivan",
+		"client_phone": "+79990000000", "external_booking_id": "crm-1"}`, date, endDate)
+	resp, data := doJSON(t, http.MethodPost, ts.URL+"/api/v1/bookings", body)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(data))
+	var created models.Booking
+	require.NoError(t, json.Unmarshal(data, &created))
+	assert.Equal(t, models.StatusPending, created.Status)
+	assert.Equal(t, "crm-1", created.ExternalBookingID)
+	assert.Equal(t, int64(1), created.Version)
+	require.NotNil(t, created.EndTime)
+
+	// Повтор внешнего ID и занятые даты — конфликт
+	resp, _ = doJSON(t, http.MethodPost, ts.URL+"/api/v1/bookings", strings.Replace(body, `"laser"`, `"camera"`, 1))
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	resp, _ = doJSON(t, http.MethodPost, ts.URL+"/api/v1/bookings", strings.Replace(body, "crm-1", "crm-2", 1))
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	bookingURL := fmt.Sprintf("%s/api/v1/bookings/%d", ts.URL, created.ID)
+	resp, data = doJSON(t, http.MethodGet, bookingURL, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var got models.Booking
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, "Ivan", got.UserName)
+
+	resp, _ = doJSON(t, http.MethodPatch, bookingURL, `{"version": 1, "status": "confirmed", "item_id": 2}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp, _ = doJSON(t, http.MethodPatch, bookingURL, `{"version": 1, "status": "changed"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp, _ = doJSON(t, http.MethodPatch, bookingURL, `{"version": 3, "status": "confirmed"}`)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	resp, data = doJSON(t, http.MethodPatch, bookingURL, `{"version": 1, "status": "confirmed"}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(data))
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, models.StatusConfirmed, got.Status)
+
+	// API-клиент не может завершить заявку
+	resp, _ = doJSON(t, http.MethodPatch, bookingURL, `{"version": 2, "status": "completed"}`)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	resp, data = doJSON(t, http.MethodPatch, bookingURL, fmt.Sprintf(`{"version": 2, "item_id": %d}`, camera.ID))
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(data))
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, camera.Name, got.ItemName)
+	assert.Equal(t, models.StatusChanged, got.Status)
+
+	newDate := day.AddDate(0, 0, 3).Format("2006-01-02")
+	resp, data = doJSON(t, http.MethodPatch, bookingURL, fmt.Sprintf(`{"version": 3, "date": %q, "end_date": ""}`, newDate))
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(data))
+	var moved models.Booking
+	require.NoError(t, json.Unmarshal(data, &moved))
+	assert.Equal(t, newDate, moved.Date.Format("2006-01-02"))
+	assert.Nil(t, moved.EndTime)
+
+	resp, _ = doJSON(t, http.MethodDelete, bookingURL, "")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp, data = doJSON(t, http.MethodDelete, bookingURL+"?version=4&reason=duplicate", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(data))
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, models.StatusCanceled, got.Status)
+
+	history, err := db.GetBookingHistory(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "duplicate", history[len(history)-1].Reason)
+
+	resp, _ = doJSON(t, http.MethodGet, ts.URL+"/api/v1/bookings/9999", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestBookingsAPI_List(t *testing.T) {
+	db := newTestDB(t)
+	laser := createTestItem(t, db, "laser", 5)
+	camera := createTestItem(t, db, "camera", 5)
+
+	logger := zerolog.New(io.Discard)
+	server := newTestHTTPServer(db)
+	server.SetBookingService(service.NewBookingService(db, nil, 365, 0, &logger))
+	ts := httptest.NewServer(server.server.Handler)
+	t.Cleanup(ts.Close)
+
+	day := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	var laserIDs []int64
+	for i := 0; i < 3; i++ {
+		laserIDs = append(laserIDs, createWatchBooking(t, db, &laser, day.AddDate(0, 0, i), nil).ID)
+	}
+	cameraBooking := createWatchBooking(t, db, &camera, day, nil)
+	require.NoError(t, db.UpdateBookingStatusWithVersion(context.Background(), cameraBooking.ID, 1, models.StatusConfirmed))
+
+	var pages [][]int64
+	next := ts.URL + "/api/v1/bookings?item=LASER&limit=2"
+	for next != "" {
+		resp, data := doJSON(t, http.MethodGet, next, "")
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(data))
+		var page BookingListResponse
+		require.NoError(t, json.Unmarshal(data, &page))
+		var ids []int64
+		for _, b := range page.Bookings {
+			ids = append(ids, b.ID)
+		}
+		pages = append(pages, ids)
+		next = ""
+		if page.NextCursor != "" {
+			next = ts.URL + "/api/v1/bookings?item=LASER&limit=2&cursor=" + page.NextCursor
+		}
+	}
+	assert.Equal(t, [][]int64{laserIDs[:2], laserIDs[2:]}, pages)
+
+	resp, data := doJSON(t, http.MethodGet, ts.URL+"/api/v1/bookings?status=confirmed&start_date=2025-12-01&end_date=2025-12-01", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var page BookingListResponse
+	require.NoError(t, json.Unmarshal(data, &page))
+	require.Len(t, page.Bookings, 1)
+	assert.Equal(t, cameraBooking.ID, page.Bookings[0].ID)
+	assert.Empty(t, page.NextCursor)
+
+	resp, data = doJSON(t, http.MethodGet, ts.URL+"/api/v1/bookings?user_id=42", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{"bookings": []}`, string(data))
+
+	for _, query := range []string{"status=lost", "cursor=bm90LWFuLWlk", "limit=0", "start_date=2025-12-05&end_date=2025-12-01", "item_id=x"} {
+		resp, _ = doJSON(t, http.MethodGet, ts.URL+"/api/v1/bookings?"+query, "")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+	}
+}
+
+func TestBookingsAPI_Permissions(t *testing.T) {
+	db := newTestDB(t)
+	cfg := config.APIConfig{
+		Enabled: true,
+		HTTP:    config.APIHTTPConfig{Enabled: true},
+		Auth: config.APIAuthConfig{
+			Enabled: true,
+			APIKeys: []config.APIClientKey{{Key: "reader", Extra: "x", Permissions: []string{"read:bookings"}}},
+		},
+	}
+	logger := zerolog.New(io.Discard)
+	server := NewHTTPServer(&cfg, db, nil, nil, &logger)
+	server.SetBookingService(service.NewBookingService(db, nil, 365, 0, &logger))
+	ts := httptest.NewServer(server.server.Handler)
+	t.Cleanup(ts.Close)
+
+	for method, want := range map[string]int{http.MethodGet: http.StatusOK, http.MethodPost: http.StatusForbidden} {
+		req, err := http.NewRequest(method, ts.URL+"/api/v1/bookings", strings.NewReader(`{}`))
+		require.NoError(t, err)
+		req.Header.Set("X-API-Key", "reader")
+		req.Header.Set("X-API-Extra", "x")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, want, resp.StatusCode, method)
+	}
+}
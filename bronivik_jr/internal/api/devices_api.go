@@ -1,10 +1,13 @@
 package api
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
+	"bronivik/internal/database"
 	"bronivik/internal/metrics"
 	"bronivik/internal/models"
 )
@@ -258,12 +261,26 @@ func (s *HTTPServer) handleCancelExternalBooking(w http.ResponseWriter, r *http.
 		return
 	}
 
+	r = withChangeReason(r)
 	err := s.db.CancelExternalBooking(r.Context(), externalID)
-	if err != nil {
+	switch {
+	case err == nil:
+	case errors.Is(err, sql.ErrNoRows):
+		writeError(w, http.StatusNotFound, "booking not found")
+		return
+	case errors.Is(err, models.ErrInvalidTransition),
+		errors.Is(err, models.ErrUnknownStatus),
+		errors.Is(err, database.ErrConcurrentModification):
+		s.log.Warn().Err(err).
+			Str("external_id", externalID).
+			Msg("external booking cannot be canceled")
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	default:
 		s.log.Error().Err(err).
 			Str("external_id", externalID).
 			Msg("failed to cancel external booking")
-		writeError(w, http.StatusNotFound, "booking not found or already canceled")
+		writeError(w, http.StatusInternalServerError, "internal error")
 		return
 	}
 
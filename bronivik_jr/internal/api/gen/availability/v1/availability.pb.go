@@ -21,10 +21,13 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// GetAvailabilityRequest is the request for a single item availability check.
 type GetAvailabilityRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ItemName      string                 `protobuf:"bytes,1,opt,name=item_name,json=itemName,proto3" json:"item_name,omitempty"`
-	Date          string                 `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"` // YYYY-MM-DD
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The unique name of the item (e.g., "Laser").
+	ItemName string `protobuf:"bytes,1,opt,name=item_name,json=itemName,proto3" json:"item_name,omitempty"`
+	// The date to check in YYYY-MM-DD format.
+	Date          string `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -73,13 +76,17 @@ func (x *GetAvailabilityRequest) GetDate() string {
 	return ""
 }
 
+// GetAvailabilityResponse contains the availability status for the requested item and date.
 type GetAvailabilityResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ItemName      string                 `protobuf:"bytes,1,opt,name=item_name,json=itemName,proto3" json:"item_name,omitempty"`
-	Date          string                 `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"` // YYYY-MM-DD
-	Available     bool                   `protobuf:"varint,3,opt,name=available,proto3" json:"available,omitempty"`
-	BookedCount   int64                  `protobuf:"varint,4,opt,name=booked_count,json=bookedCount,proto3" json:"booked_count,omitempty"`
-	Total         int64                  `protobuf:"varint,5,opt,name=total,proto3" json:"total,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ItemName string                 `protobuf:"bytes,1,opt,name=item_name,json=itemName,proto3" json:"item_name,omitempty"`
+	Date     string                 `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	// True if at least one unit is available for booking.
+	Available bool `protobuf:"varint,3,opt,name=available,proto3" json:"available,omitempty"`
+	// Number of units already booked for this date.
+	BookedCount int64 `protobuf:"varint,4,opt,name=booked_count,json=bookedCount,proto3" json:"booked_count,omitempty"`
+	// Total number of units available in the system.
+	Total         int64 `protobuf:"varint,5,opt,name=total,proto3" json:"total,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -149,10 +156,13 @@ func (x *GetAvailabilityResponse) GetTotal() int64 {
 	return 0
 }
 
+// GetAvailabilityBulkRequest is the request for multiple items and dates.
 type GetAvailabilityBulkRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Items         []string               `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
-	Dates         []string               `protobuf:"bytes,2,rep,name=dates,proto3" json:"dates,omitempty"` // YYYY-MM-DD
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// List of item names to check.
+	Items []string `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	// List of dates to check in YYYY-MM-DD format.
+	Dates         []string `protobuf:"bytes,2,rep,name=dates,proto3" json:"dates,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -201,10 +211,11 @@ func (x *GetAvailabilityBulkRequest) GetDates() []string {
 	return nil
 }
 
+// Availability represents the status of a single item on a specific date.
 type Availability struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ItemName      string                 `protobuf:"bytes,1,opt,name=item_name,json=itemName,proto3" json:"item_name,omitempty"`
-	Date          string                 `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"` // YYYY-MM-DD
+	Date          string                 `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
 	Available     bool                   `protobuf:"varint,3,opt,name=available,proto3" json:"available,omitempty"`
 	BookedCount   int64                  `protobuf:"varint,4,opt,name=booked_count,json=bookedCount,proto3" json:"booked_count,omitempty"`
 	Total         int64                  `protobuf:"varint,5,opt,name=total,proto3" json:"total,omitempty"`
@@ -277,9 +288,11 @@ func (x *Availability) GetTotal() int64 {
 	return 0
 }
 
+// GetAvailabilityBulkResponse contains the results of the bulk check.
 type GetAvailabilityBulkResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Results       []*Availability        `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// List of availability statuses for each item/date combination.
+	Results       []*Availability `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -321,6 +334,7 @@ func (x *GetAvailabilityBulkResponse) GetResults() []*Availability {
 	return nil
 }
 
+// ListItemsRequest is an empty request to list all items.
 type ListItemsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -357,11 +371,15 @@ func (*ListItemsRequest) Descriptor() ([]byte, []int) {
 	return file_availability_v1_availability_proto_rawDescGZIP(), []int{5}
 }
 
+// Item represents a piece of equipment available for booking.
 type Item struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	TotalQuantity int64                  `protobuf:"varint,3,opt,name=total_quantity,json=totalQuantity,proto3" json:"total_quantity,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unique identifier for the item.
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Display name of the item.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Total number of units available in the system.
+	TotalQuantity int64 `protobuf:"varint,3,opt,name=total_quantity,json=totalQuantity,proto3" json:"total_quantity,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -417,6 +435,7 @@ func (x *Item) GetTotalQuantity() int64 {
 	return 0
 }
 
+// ListItemsResponse contains the list of all active items.
 type ListItemsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Items         []*Item                `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
@@ -461,6 +480,150 @@ func (x *ListItemsResponse) GetItems() []*Item {
 	return nil
 }
 
+// WatchAvailabilityRequest selects the changes to stream.
+type WatchAvailabilityRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Item names to watch. Empty means all items.
+	Items []string `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	// First date of the range in YYYY-MM-DD format. Empty means no lower bound.
+	StartDate string `protobuf:"bytes,2,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	// Last date of the range in YYYY-MM-DD format (inclusive). Empty means no upper bound.
+	EndDate       string `protobuf:"bytes,3,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchAvailabilityRequest) Reset() {
+	*x = WatchAvailabilityRequest{}
+	mi := &file_availability_v1_availability_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchAvailabilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchAvailabilityRequest) ProtoMessage() {}
+
+func (x *WatchAvailabilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_availability_v1_availability_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchAvailabilityRequest.ProtoReflect.Descriptor instead.
+func (*WatchAvailabilityRequest) Descriptor() ([]byte, []int) {
+	return file_availability_v1_availability_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *WatchAvailabilityRequest) GetItems() []string {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *WatchAvailabilityRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *WatchAvailabilityRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+// AvailabilityDelta is the new availability of an item on a date after a booking change.
+type AvailabilityDelta struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID of the booking event that caused the change; increases monotonically.
+	EventId int64 `protobuf:"varint,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// Event type: booking_created, booking_canceled, booking_rescheduled or booking_item_changed.
+	EventType    string        `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	BookingId    int64         `protobuf:"varint,3,opt,name=booking_id,json=bookingId,proto3" json:"booking_id,omitempty"`
+	Availability *Availability `protobuf:"bytes,4,opt,name=availability,proto3" json:"availability,omitempty"`
+	// Time of the change in RFC 3339 format.
+	ChangedAt     string `protobuf:"bytes,5,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AvailabilityDelta) Reset() {
+	*x = AvailabilityDelta{}
+	mi := &file_availability_v1_availability_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AvailabilityDelta) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AvailabilityDelta) ProtoMessage() {}
+
+func (x *AvailabilityDelta) ProtoReflect() protoreflect.Message {
+	mi := &file_availability_v1_availability_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AvailabilityDelta.ProtoReflect.Descriptor instead.
+func (*AvailabilityDelta) Descriptor() ([]byte, []int) {
+	return file_availability_v1_availability_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AvailabilityDelta) GetEventId() int64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *AvailabilityDelta) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *AvailabilityDelta) GetBookingId() int64 {
+	if x != nil {
+		return x.BookingId
+	}
+	return 0
+}
+
+func (x *AvailabilityDelta) GetAvailability() *Availability {
+	if x != nil {
+		return x.Availability
+	}
+	return nil
+}
+
+func (x *AvailabilityDelta) GetChangedAt() string {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return ""
+}
+
 var File_availability_v1_availability_proto protoreflect.FileDescriptor
 
 const file_availability_v1_availability_proto_rawDesc = "" +
@@ -492,11 +655,26 @@ const file_availability_v1_availability_proto_rawDesc = "" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12%\n" +
 	"\x0etotal_quantity\x18\x03 \x01(\x03R\rtotalQuantity\"I\n" +
 	"\x11ListItemsResponse\x124\n" +
-	"\x05items\x18\x01 \x03(\v2\x1e.bronivik.availability.v1.ItemR\x05items2\xf8\x02\n" +
+	"\x05items\x18\x01 \x03(\v2\x1e.bronivik.availability.v1.ItemR\x05items\"j\n" +
+	"\x18WatchAvailabilityRequest\x12\x14\n" +
+	"\x05items\x18\x01 \x03(\tR\x05items\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x02 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x03 \x01(\tR\aendDate\"\xd7\x01\n" +
+	"\x11AvailabilityDelta\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\x03R\aeventId\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x02 \x01(\tR\teventType\x12\x1d\n" +
+	"\n" +
+	"booking_id\x18\x03 \x01(\x03R\tbookingId\x12J\n" +
+	"\favailability\x18\x04 \x01(\v2&.bronivik.availability.v1.AvailabilityR\favailability\x12\x1d\n" +
+	"\n" +
+	"changed_at\x18\x05 \x01(\tR\tchangedAt2\xf0\x03\n" +
 	"\x13AvailabilityService\x12v\n" +
 	"\x0fGetAvailability\x120.bronivik.availability.v1.GetAvailabilityRequest\x1a1.bronivik.availability.v1.GetAvailabilityResponse\x12\x82\x01\n" +
 	"\x13GetAvailabilityBulk\x124.bronivik.availability.v1.GetAvailabilityBulkRequest\x1a5.bronivik.availability.v1.GetAvailabilityBulkResponse\x12d\n" +
-	"\tListItems\x12*.bronivik.availability.v1.ListItemsRequest\x1a+.bronivik.availability.v1.ListItemsResponseB:Z8bronivik/internal/api/gen/availability/v1;availabilityv1b\x06proto3"
+	"\tListItems\x12*.bronivik.availability.v1.ListItemsRequest\x1a+.bronivik.availability.v1.ListItemsResponse\x12v\n" +
+	"\x11WatchAvailability\x122.bronivik.availability.v1.WatchAvailabilityRequest\x1a+.bronivik.availability.v1.AvailabilityDelta0\x01B:Z8bronivik/internal/api/gen/availability/v1;availabilityv1b\x06proto3"
 
 var (
 	file_availability_v1_availability_proto_rawDescOnce sync.Once
@@ -510,7 +688,7 @@ func file_availability_v1_availability_proto_rawDescGZIP() []byte {
 	return file_availability_v1_availability_proto_rawDescData
 }
 
-var file_availability_v1_availability_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_availability_v1_availability_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_availability_v1_availability_proto_goTypes = []any{
 	(*GetAvailabilityRequest)(nil),      // 0: bronivik.availability.v1.GetAvailabilityRequest
 	(*GetAvailabilityResponse)(nil),     // 1: bronivik.availability.v1.GetAvailabilityResponse
@@ -520,21 +698,26 @@ var file_availability_v1_availability_proto_goTypes = []any{
 	(*ListItemsRequest)(nil),            // 5: bronivik.availability.v1.ListItemsRequest
 	(*Item)(nil),                        // 6: bronivik.availability.v1.Item
 	(*ListItemsResponse)(nil),           // 7: bronivik.availability.v1.ListItemsResponse
+	(*WatchAvailabilityRequest)(nil),    // 8: bronivik.availability.v1.WatchAvailabilityRequest
+	(*AvailabilityDelta)(nil),           // 9: bronivik.availability.v1.AvailabilityDelta
 }
 var file_availability_v1_availability_proto_depIdxs = []int32{
 	3, // 0: bronivik.availability.v1.GetAvailabilityBulkResponse.results:type_name -> bronivik.availability.v1.Availability
 	6, // 1: bronivik.availability.v1.ListItemsResponse.items:type_name -> bronivik.availability.v1.Item
-	0, // 2: bronivik.availability.v1.AvailabilityService.GetAvailability:input_type -> bronivik.availability.v1.GetAvailabilityRequest
-	2, // 3: bronivik.availability.v1.AvailabilityService.GetAvailabilityBulk:input_type -> bronivik.availability.v1.GetAvailabilityBulkRequest
-	5, // 4: bronivik.availability.v1.AvailabilityService.ListItems:input_type -> bronivik.availability.v1.ListItemsRequest
-	1, // 5: bronivik.availability.v1.AvailabilityService.GetAvailability:output_type -> bronivik.availability.v1.GetAvailabilityResponse
-	4, // 6: bronivik.availability.v1.AvailabilityService.GetAvailabilityBulk:output_type -> bronivik.availability.v1.GetAvailabilityBulkResponse
-	7, // 7: bronivik.availability.v1.AvailabilityService.ListItems:output_type -> bronivik.availability.v1.ListItemsResponse
-	5, // [5:8] is the sub-list for method output_type
-	2, // [2:5] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	3, // 2: bronivik.availability.v1.AvailabilityDelta.availability:type_name -> bronivik.availability.v1.Availability
+	0, // 3: bronivik.availability.v1.AvailabilityService.GetAvailability:input_type -> bronivik.availability.v1.GetAvailabilityRequest
+	2, // 4: bronivik.availability.v1.AvailabilityService.GetAvailabilityBulk:input_type -> bronivik.availability.v1.GetAvailabilityBulkRequest
+	5, // 5: bronivik.availability.v1.AvailabilityService.ListItems:input_type -> bronivik.availability.v1.ListItemsRequest
+	8, // 6: bronivik.availability.v1.AvailabilityService.WatchAvailability:input_type -> bronivik.availability.v1.WatchAvailabilityRequest
+	1, // 7: bronivik.availability.v1.AvailabilityService.GetAvailability:output_type -> bronivik.availability.v1.GetAvailabilityResponse
+	4, // 8: bronivik.availability.v1.AvailabilityService.GetAvailabilityBulk:output_type -> bronivik.availability.v1.GetAvailabilityBulkResponse
+	7, // 9: bronivik.availability.v1.AvailabilityService.ListItems:output_type -> bronivik.availability.v1.ListItemsResponse
+	9, // 10: bronivik.availability.v1.AvailabilityService.WatchAvailability:output_type -> bronivik.availability.v1.AvailabilityDelta
+	7, // [7:11] is the sub-list for method output_type
+	3, // [3:7] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_availability_v1_availability_proto_init() }
@@ -548,7 +731,7 @@ func file_availability_v1_availability_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_availability_v1_availability_proto_rawDesc), len(file_availability_v1_availability_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -22,18 +22,30 @@ const (
 	AvailabilityService_GetAvailability_FullMethodName     = "/bronivik.availability.v1.AvailabilityService/GetAvailability"
 	AvailabilityService_GetAvailabilityBulk_FullMethodName = "/bronivik.availability.v1.AvailabilityService/GetAvailabilityBulk"
 	AvailabilityService_ListItems_FullMethodName           = "/bronivik.availability.v1.AvailabilityService/ListItems"
+	AvailabilityService_WatchAvailability_FullMethodName   = "/bronivik.availability.v1.AvailabilityService/WatchAvailability"
 )
 
 // AvailabilityServiceClient is the client API for AvailabilityService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AvailabilityService provides methods to check equipment availability
+// and retrieve item information for the booking system.
 type AvailabilityServiceClient interface {
-	// Check availability for a single item and date (YYYY-MM-DD).
+	// GetAvailability checks availability for a single item on a specific date.
+	// The date must be in YYYY-MM-DD format.
 	GetAvailability(ctx context.Context, in *GetAvailabilityRequest, opts ...grpc.CallOption) (*GetAvailabilityResponse, error)
-	// Bulk availability check.
+	// GetAvailabilityBulk performs a mass availability check for multiple items and dates.
+	// Useful for calendar views or multi-item booking scenarios.
 	GetAvailabilityBulk(ctx context.Context, in *GetAvailabilityBulkRequest, opts ...grpc.CallOption) (*GetAvailabilityBulkResponse, error)
-	// List all known items with their total quantity.
+	// ListItems returns a list of all active items (equipment) in the system
+	// along with their total quantities.
 	ListItems(ctx context.Context, in *ListItemsRequest, opts ...grpc.CallOption) (*ListItemsResponse, error)
+	// WatchAvailability streams availability changes caused by created, canceled,
+	// rescheduled or re-assigned bookings. Each message carries the new state of
+	// one item on one date. The stream does not send an initial snapshot: clients
+	// should call GetAvailabilityBulk after subscribing and after reconnecting.
+	WatchAvailability(ctx context.Context, in *WatchAvailabilityRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AvailabilityDelta], error)
 }
 
 type availabilityServiceClient struct {
@@ -74,16 +86,46 @@ func (c *availabilityServiceClient) ListItems(ctx context.Context, in *ListItems
 	return out, nil
 }
 
+func (c *availabilityServiceClient) WatchAvailability(ctx context.Context, in *WatchAvailabilityRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AvailabilityDelta], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AvailabilityService_ServiceDesc.Streams[0], AvailabilityService_WatchAvailability_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchAvailabilityRequest, AvailabilityDelta]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AvailabilityService_WatchAvailabilityClient = grpc.ServerStreamingClient[AvailabilityDelta]
+
 // AvailabilityServiceServer is the server API for AvailabilityService service.
 // All implementations must embed UnimplementedAvailabilityServiceServer
 // for forward compatibility.
+//
+// AvailabilityService provides methods to check equipment availability
+// and retrieve item information for the booking system.
 type AvailabilityServiceServer interface {
-	// Check availability for a single item and date (YYYY-MM-DD).
+	// GetAvailability checks availability for a single item on a specific date.
+	// The date must be in YYYY-MM-DD format.
 	GetAvailability(context.Context, *GetAvailabilityRequest) (*GetAvailabilityResponse, error)
-	// Bulk availability check.
+	// GetAvailabilityBulk performs a mass availability check for multiple items and dates.
+	// Useful for calendar views or multi-item booking scenarios.
 	GetAvailabilityBulk(context.Context, *GetAvailabilityBulkRequest) (*GetAvailabilityBulkResponse, error)
-	// List all known items with their total quantity.
+	// ListItems returns a list of all active items (equipment) in the system
+	// along with their total quantities.
 	ListItems(context.Context, *ListItemsRequest) (*ListItemsResponse, error)
+	// WatchAvailability streams availability changes caused by created, canceled,
+	// rescheduled or re-assigned bookings. Each message carries the new state of
+	// one item on one date. The stream does not send an initial snapshot: clients
+	// should call GetAvailabilityBulk after subscribing and after reconnecting.
+	WatchAvailability(*WatchAvailabilityRequest, grpc.ServerStreamingServer[AvailabilityDelta]) error
 	mustEmbedUnimplementedAvailabilityServiceServer()
 }
 
@@ -103,6 +145,9 @@ func (UnimplementedAvailabilityServiceServer) GetAvailabilityBulk(context.Contex
 func (UnimplementedAvailabilityServiceServer) ListItems(context.Context, *ListItemsRequest) (*ListItemsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListItems not implemented")
 }
+func (UnimplementedAvailabilityServiceServer) WatchAvailability(*WatchAvailabilityRequest, grpc.ServerStreamingServer[AvailabilityDelta]) error {
+	return status.Error(codes.Unimplemented, "method WatchAvailability not implemented")
+}
 func (UnimplementedAvailabilityServiceServer) mustEmbedUnimplementedAvailabilityServiceServer() {}
 func (UnimplementedAvailabilityServiceServer) testEmbeddedByValue()                             {}
 
@@ -178,6 +223,17 @@ func _AvailabilityService_ListItems_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AvailabilityService_WatchAvailability_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchAvailabilityRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AvailabilityServiceServer).WatchAvailability(m, &grpc.GenericServerStream[WatchAvailabilityRequest, AvailabilityDelta]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AvailabilityService_WatchAvailabilityServer = grpc.ServerStreamingServer[AvailabilityDelta]
+
 // AvailabilityService_ServiceDesc is the grpc.ServiceDesc for AvailabilityService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -198,6 +254,12 @@ var AvailabilityService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _AvailabilityService_ListItems_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchAvailability",
+			Handler:       _AvailabilityService_WatchAvailability_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "availability/v1/availability.proto",
 }
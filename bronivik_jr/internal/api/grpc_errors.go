@@ -0,0 +1,41 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+
+	"bronivik/internal/database"
+	"bronivik/internal/models"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// bookingError converts booking service errors into gRPC status errors.
+func bookingError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return status.Error(codes.NotFound, "booking not found")
+	case errors.Is(err, models.ErrInvalidTransition),
+		errors.Is(err, models.ErrUnknownStatus),
+		errors.Is(err, database.ErrSeriesCanceled):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, database.ErrConcurrentModification):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, database.ErrNotAvailable):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, database.ErrPastDate),
+		errors.Is(err, database.ErrDateTooFar),
+		errors.Is(err, database.ErrInvalidDateRange):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
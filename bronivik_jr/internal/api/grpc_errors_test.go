@@ -0,0 +1,34 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"bronivik/internal/database"
+	"bronivik/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBookingError(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{&models.TransitionError{Actor: models.ActorAPI, From: models.StatusCanceled, To: models.StatusConfirmed}, codes.FailedPrecondition},
+		{fmt.Errorf("confirm: %w", &models.UnknownStatusError{Status: "approved"}), codes.FailedPrecondition},
+		{database.ErrConcurrentModification, codes.Aborted},
+		{&database.UnavailableDatesError{}, codes.AlreadyExists},
+		{database.ErrPastDate, codes.InvalidArgument},
+		{status.Error(codes.PermissionDenied, "denied"), codes.PermissionDenied},
+		{errors.New("boom"), codes.Internal},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.code, status.Code(bookingError(tt.err)), tt.err.Error())
+	}
+	assert.NoError(t, bookingError(nil))
+}
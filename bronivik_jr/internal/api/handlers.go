@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"time"
 
@@ -17,6 +18,7 @@ type AvailabilityService struct {
 	availabilityv1.UnimplementedAvailabilityServiceServer
 	db          *database.DB
 	itemsByName map[string]*models.Item
+	watcher     *AvailabilityWatcher
 }
 
 func NewAvailabilityService(db *database.DB) *AvailabilityService {
@@ -134,3 +136,57 @@ func (s *AvailabilityService) ListItems(
 	}
 	return &availabilityv1.ListItemsResponse{Items: out}, nil
 }
+
+// WatchAvailability streams availability changes matching the request filter.
+// The stream ends with Unavailable when the server stops or the client falls behind.
+func (s *AvailabilityService) WatchAvailability(
+	req *availabilityv1.WatchAvailabilityRequest,
+	stream availabilityv1.AvailabilityService_WatchAvailabilityServer,
+) error {
+	if s.watcher == nil {
+		return status.Error(codes.Unavailable, "availability stream is not enabled")
+	}
+
+	filter, err := parseAvailabilityFilter(s.db, req.GetItems(),
+		strings.TrimSpace(req.GetStartDate()), strings.TrimSpace(req.GetEndDate()))
+	if err != nil {
+		if errors.Is(err, errUnknownItem) {
+			return status.Error(codes.NotFound, err.Error())
+		}
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	changes, cancel := s.watcher.Subscribe(filter)
+	defer cancel()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case change, ok := <-changes:
+			if !ok {
+				return status.Error(codes.Unavailable, "availability stream closed; reconnect and reload availability")
+			}
+			if err := stream.Send(availabilityDelta(&change)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func availabilityDelta(change *AvailabilityChange) *availabilityv1.AvailabilityDelta {
+	return &availabilityv1.AvailabilityDelta{
+		EventId:   change.EventID,
+		EventType: change.EventType,
+		BookingId: change.BookingID,
+		Availability: &availabilityv1.Availability{
+			ItemName:    change.ItemName,
+			Date:        change.Date.Format("2006-01-02"),
+			Available:   change.Available,
+			BookedCount: change.BookedCount,
+			Total:       change.Total,
+		},
+		ChangedAt: change.ChangedAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -13,8 +13,11 @@ import (
 
 	"bronivik/internal/config"
 	"bronivik/internal/database"
+	"bronivik/internal/domain"
 	"bronivik/internal/google"
 	"bronivik/internal/metrics"
+	"bronivik/internal/models"
+	"bronivik/internal/reqsign"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
@@ -23,13 +26,17 @@ import (
 
 // HTTPServer exposes a lightweight HTTP API alongside the gRPC service.
 type HTTPServer struct {
-	cfg           *config.APIConfig
-	db            *database.DB
-	redisClient   *redis.Client
-	sheetsService *google.SheetsService
-	server        *http.Server
-	auth          *HTTPAuth
-	log           zerolog.Logger
+	cfg            *config.APIConfig
+	db             *database.DB
+	redisClient    *redis.Client
+	sheetsService  *google.SheetsService
+	bookingService domain.BookingService
+	webhookService domain.WebhookService
+	apiKeyService  domain.APIKeyService
+	watcher        *AvailabilityWatcher
+	server         *http.Server
+	auth           *HTTPAuth
+	log            zerolog.Logger
 }
 
 func NewHTTPServer(
@@ -50,10 +57,24 @@ func NewHTTPServer(
 		srv.log = logger.With().Str("component", "http").Logger()
 	}
 	srv.auth = NewHTTPAuth(cfg)
+	if redisClient != nil {
+		// Nonce подписей и счетчики лимитов общие для всех процессов с API
+		srv.auth.signatures.SetNonceCache(NewRedisNonceCache(redisClient))
+		srv.auth.limiter.useRedis(redisClient)
+	}
 
 	apiMux.HandleFunc("/api/v1/availability/bulk", srv.handleAvailabilityBulk)
+	apiMux.HandleFunc("/api/v1/availability/stream", srv.handleAvailabilityStream)
 	apiMux.HandleFunc("/api/v1/availability/", srv.handleAvailability)
 	apiMux.HandleFunc("/api/v1/items", srv.handleItems)
+	apiMux.HandleFunc(bookingsPathPrefix, srv.handleBookings)
+	apiMux.HandleFunc(bookingsPathPrefix+"/", srv.handleBookings)
+	apiMux.HandleFunc(seriesPathPrefix, srv.handleBookingSeries)
+	apiMux.HandleFunc(seriesPathPrefix+"/", srv.handleBookingSeries)
+	apiMux.HandleFunc(webhooksPathPrefix, srv.handleWebhooks)
+	apiMux.HandleFunc(webhooksPathPrefix+"/", srv.handleWebhooks)
+	apiMux.HandleFunc(apiKeysPathPrefix, srv.handleAPIKeys)
+	apiMux.HandleFunc(apiKeysPathPrefix+"/", srv.handleAPIKeys)
 	apiMux.HandleFunc("/api/items/availability", srv.handleItemsAvailability)
 	apiMux.HandleFunc("/api/devices", srv.handleDevices)
 	apiMux.HandleFunc("/api/book-device", srv.handleBookDevice)
@@ -61,7 +82,7 @@ func NewHTTPServer(
 	apiMux.HandleFunc("/healthz", srv.handleHealthz)
 	apiMux.HandleFunc("/readyz", srv.handleReadyz)
 
-	handler := srv.loggingMiddleware(corsMiddleware(srv.auth.Wrap(apiMux)))
+	handler := srv.loggingMiddleware(corsMiddleware(srv.auth.Wrap(srv.idempotencyMiddleware(apiMux))))
 
 	srv.server = &http.Server{
 		Addr:              fmt.Sprintf(":%d", cfg.HTTP.Port),
@@ -77,8 +98,21 @@ func (s *HTTPServer) Start() error {
 	if s.server == nil {
 		return fmt.Errorf("http server is not initialized")
 	}
-	s.log.Info().Str("addr", s.server.Addr).Msg("HTTP API listening")
-	if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	if !s.cfg.HTTP.TLS.Enabled {
+		s.log.Info().Str("addr", s.server.Addr).Msg("HTTP API listening")
+		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			return err
+		}
+		return nil
+	}
+
+	certs, err := newCertReloader(s.cfg.HTTP.TLS, []string{"h2", "http/1.1"}, s.log)
+	if err != nil {
+		return fmt.Errorf("http %w", err)
+	}
+	s.server.TLSConfig = certs.TLSConfig()
+	s.log.Info().Str("addr", s.server.Addr).Bool("mtls", s.cfg.HTTP.TLS.ClientCAFile != "").Msg("HTTPS API listening")
+	if err := s.server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
 		return err
 	}
 	return nil
@@ -297,8 +331,8 @@ func (s *HTTPServer) handleItems(w http.ResponseWriter, r *http.Request) {
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET,POST,DELETE,OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, X-API-Extra")
+		w.Header().Set("Access-Control-Allow-Methods", "GET,POST,PATCH,DELETE,OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, X-API-Extra, Idempotency-Key")
 
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusNoContent)
@@ -311,9 +345,12 @@ func corsMiddleware(next http.Handler) http.Handler {
 
 // HTTPAuth provides API-key auth and per-key rate limiting for HTTP endpoints.
 type HTTPAuth struct {
-	cfg     *config.APIConfig
-	clients map[string]config.APIClientKey
-	limiter *rateLimiter
+	cfg        *config.APIConfig
+	clients    map[string]config.APIClientKey
+	byCert     map[string]config.APIClientKey
+	keys       *APIKeyStore
+	signatures *reqsign.Verifier
+	limiter    *rateLimiter
 }
 
 func NewHTTPAuth(cfg *config.APIConfig) *HTTPAuth {
@@ -322,31 +359,45 @@ func NewHTTPAuth(cfg *config.APIConfig) *HTTPAuth {
 		m[k.Key] = k
 	}
 	return &HTTPAuth{
-		cfg:     cfg,
-		clients: m,
-		limiter: newRateLimiter(cfg),
+		cfg:        cfg,
+		clients:    m,
+		byCert:     clientsByCertCN(cfg.Auth.APIKeys),
+		signatures: reqsign.NewVerifier(time.Duration(cfg.Auth.Signing.MaxSkewSeconds)*time.Second, nil),
+		limiter:    newRateLimiter(cfg),
 	}
 }
 
 func (a *HTTPAuth) Wrap(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Все изменения заявок через HTTP API выполняются от имени API-клиента
+		actor := models.Actor{Type: models.ActorAPI}
 		if !a.cfg.Enabled || !a.cfg.HTTP.Enabled {
-			next.ServeHTTP(w, r)
+			next.ServeHTTP(w, r.WithContext(models.WithActor(r.Context(), actor)))
 			return
 		}
 
+		var client config.APIClientKey
 		if a.cfg.Auth.Enabled {
-			if err := a.checkAuth(r); err != nil {
+			var err error
+			if client, err = a.checkAuth(r); err != nil {
 				statusCode := http.StatusUnauthorized
-				if err == errPermissionDenied {
+				switch err {
+				case errPermissionDenied:
 					statusCode = http.StatusForbidden
+				case errSignedBodyTooLarge:
+					statusCode = http.StatusRequestEntityTooLarge
+				case errSignatureUnavailable:
+					statusCode = http.StatusServiceUnavailable
 				}
 				writeError(w, statusCode, err.Error())
 				return
 			}
+			actor.Name = client.Name
 		}
+		ctx := context.WithValue(models.WithActor(r.Context(), actor), apiClientContextKey{}, client)
+		r = r.WithContext(ctx)
 
-		if err := a.checkRateLimit(r); err != nil {
+		if err := a.checkRateLimit(w, r, client); err != nil {
 			writeError(w, http.StatusTooManyRequests, err.Error())
 			return
 		}
@@ -357,7 +408,7 @@ func (a *HTTPAuth) Wrap(next http.Handler) http.Handler {
 
 var errPermissionDenied = fmt.Errorf("permission denied")
 
-func (a *HTTPAuth) checkAuth(r *http.Request) error {
+func (a *HTTPAuth) checkAuth(r *http.Request) (config.APIClientKey, error) {
 	apiKeyHeader := strings.TrimSpace(strings.ToLower(a.cfg.Auth.HeaderAPIKey))
 	if apiKeyHeader == "" {
 		apiKeyHeader = "x-api-key"
@@ -369,23 +420,39 @@ func (a *HTTPAuth) checkAuth(r *http.Request) error {
 
 	apiKey := strings.TrimSpace(r.Header.Get(apiKeyHeader))
 	extra := strings.TrimSpace(r.Header.Get(extraHeader))
-	if apiKey == "" || extra == "" {
-		return fmt.Errorf("missing api key headers")
-	}
+	certName := clientCertName(r.TLS)
 
-	client, ok := a.clients[apiKey]
-	if !ok {
-		return fmt.Errorf("invalid api key")
-	}
-	if subtle.ConstantTimeCompare([]byte(client.Extra), []byte(extra)) != 1 {
-		return fmt.Errorf("invalid extra header")
+	// Проверенный сертификат клиента, закрепленный за ключом, заменяет сам ключ
+	client, ok := clientByCert(a.byCert, a.keys, certName)
+	if apiKey != "" || !ok {
+		if apiKey == "" {
+			return config.APIClientKey{}, fmt.Errorf("missing api key headers")
+		}
+		// Ключам из базы второй секрет не нужен; статические ключи из конфига проверяются вместе с extra
+		if client, ok = a.keys.Lookup(apiKey); !ok {
+			if extra == "" {
+				return config.APIClientKey{}, fmt.Errorf("missing api key headers")
+			}
+			if client, ok = a.clients[apiKey]; !ok {
+				return config.APIClientKey{}, fmt.Errorf("invalid api key")
+			}
+			if subtle.ConstantTimeCompare([]byte(client.Extra), []byte(extra)) != 1 {
+				return config.APIClientKey{}, fmt.Errorf("invalid extra header")
+			}
+		}
+		if err := checkClientCert(client, certName); err != nil {
+			return config.APIClientKey{}, err
+		}
 	}
 
+	if err := a.checkSignature(r, client); err != nil {
+		return config.APIClientKey{}, err
+	}
 	if err := a.checkPermissions(client, r); err != nil {
-		return err
+		return config.APIClientKey{}, err
 	}
 
-	return nil
+	return client, nil
 }
 
 func (a *HTTPAuth) checkPermissions(client config.APIClientKey, r *http.Request) error {
@@ -393,7 +460,9 @@ func (a *HTTPAuth) checkPermissions(client config.APIClientKey, r *http.Request)
 	if required == "" {
 		return nil
 	}
-	if len(client.Permissions) == 0 {
+	// Пустой список разрешений у статического ключа допускает все, кроме административных
+	// эндпоинтов; ключу из базы без разрешений запрещено все
+	if len(client.Permissions) == 0 && !client.Stored && !strings.HasPrefix(required, "admin:") {
 		return nil
 	}
 	for _, p := range client.Permissions {
@@ -412,20 +481,31 @@ func requiredPermissionHTTP(r *http.Request) string {
 	if path == "/api/v1/items" {
 		return "read:items"
 	}
+	if strings.HasPrefix(path, bookingsPathPrefix) || strings.HasPrefix(path, "/api/v1/booking-series") {
+		if r.Method == http.MethodGet {
+			return "read:bookings"
+		}
+		return "write:bookings"
+	}
+	if strings.HasPrefix(path, webhooksPathPrefix) {
+		return permAdminWebhooks
+	}
+	if strings.HasPrefix(path, apiKeysPathPrefix) {
+		return permAdminAPIKeys
+	}
 	return ""
 }
 
-func (a *HTTPAuth) checkRateLimit(r *http.Request) error {
-	if a.cfg.RateLimit.RPS <= 0 {
+// checkRateLimit списывает запрос с лимитов клиента и отдает их состояние в заголовках RateLimit-*.
+func (a *HTTPAuth) checkRateLimit(w http.ResponseWriter, r *http.Request, client config.APIClientKey) error {
+	d, ok := a.limiter.allow(r.Context(), a.clientKey(r), client)
+	if !ok {
 		return nil
 	}
-
-	key := a.clientKey(r)
-	lim := a.limiter.getLimiter(key)
-	if !lim.Allow() {
-		return fmt.Errorf("rate limit exceeded")
+	for _, h := range d.headers() {
+		w.Header().Set(h[0], h[1])
 	}
-	return nil
+	return d.err()
 }
 
 func (a *HTTPAuth) clientKey(r *http.Request) string {
@@ -437,6 +517,9 @@ func (a *HTTPAuth) clientKey(r *http.Request) string {
 	if apiKey := strings.TrimSpace(r.Header.Get(apiKeyHeader)); apiKey != "" {
 		return apiKey
 	}
+	if certName := clientCertName(r.TLS); certName != "" {
+		return "cert:" + certName
+	}
 
 	host, _, err := net.SplitHostPort(r.RemoteAddr)
 	if err == nil && host != "" {
@@ -473,6 +556,14 @@ const requestIDHeader = "X-Request-ID"
 
 type requestIDKey struct{}
 
+// apiClientContextKey хранит в контексте запроса клиента, прошедшего проверку в HTTPAuth.
+type apiClientContextKey struct{}
+
+func apiClientFromContext(ctx context.Context) (config.APIClientKey, bool) {
+	client, ok := ctx.Value(apiClientContextKey{}).(config.APIClientKey)
+	return client, ok
+}
+
 func requestIDFromHeader(r *http.Request) string {
 	if id := strings.TrimSpace(r.Header.Get(requestIDHeader)); id != "" {
 		return id
@@ -521,3 +612,8 @@ func (r *statusRecorder) WriteHeader(status int) {
 	r.status = status
 	r.ResponseWriter.WriteHeader(status)
 }
+
+// Unwrap открывает исходный ResponseWriter для http.ResponseController (Flush, дедлайны).
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -509,6 +509,9 @@ func newTestDB(t *testing.T) *database.DB {
 		t.Fatalf("new db: %v", err)
 	}
 	t.Cleanup(func() { db.Close() })
+	if err := db.Migrate(context.Background(), ""); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
 	return db
 }
 
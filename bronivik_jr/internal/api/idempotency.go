@@ -0,0 +1,209 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"bronivik/internal/models"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	maxIdempotentBodyBytes    = 1 << 20
+	defaultIdempotencyTTL     = 24 * time.Hour
+)
+
+// idempotencyMiddleware makes POST, PATCH and DELETE requests safe to retry.
+// The first request with a key is executed and its response is stored for the
+// configured TTL; a retry with the same key and payload gets the stored response,
+// a retry with a different payload gets 409. Keys are scoped per API client.
+// Book-device requests without the header use external_booking_id as the key.
+func (s *HTTPServer) idempotencyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isMutatingMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := strings.TrimSpace(r.Header.Get(idempotencyKeyHeader))
+		fallback := key == "" && r.Method == http.MethodPost && r.URL.Path == "/api/book-device"
+		if key == "" && !fallback {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			writeError(w, http.StatusBadRequest, "Idempotency-Key is too long")
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBodyBytes+1))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "failed to read request body")
+			return
+		}
+		if len(body) > maxIdempotentBodyBytes {
+			writeError(w, http.StatusRequestEntityTooLarge, "request body is too large")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		if fallback {
+			// Некорректное тело отклонит сам обработчик
+			if key = externalBookingKey(body); key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		rec := &models.IdempotencyRecord{
+			Scope:       s.idempotencyScope(r),
+			Key:         key,
+			Fingerprint: requestFingerprint(r, body),
+			ExpiresAt:   time.Now().Add(s.idempotencyTTL()),
+		}
+		existing, err := s.db.ReserveIdempotencyKey(r.Context(), rec)
+		if err != nil {
+			s.log.Error().Err(err).Str("path", r.URL.Path).Msg("idempotency key reservation failed")
+			writeError(w, http.StatusInternalServerError, "internal error")
+			return
+		}
+		if existing != nil {
+			replayIdempotentResponse(w, rec, existing)
+			return
+		}
+
+		// Ответ сохраняется и после разрыва соединения клиентом
+		storeCtx := context.WithoutCancel(r.Context())
+		completed := false
+		defer func() {
+			// Ошибку сервера или панику клиент может повторить с тем же ключом
+			if !completed {
+				if err := s.db.ReleaseIdempotencyKey(storeCtx, rec.Scope, rec.Key); err != nil {
+					s.log.Error().Err(err).Msg("failed to release idempotency key")
+				}
+			}
+		}()
+
+		recorder := &idempotencyRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		if recorder.status >= http.StatusInternalServerError {
+			return
+		}
+		// По external_booking_id запоминается только созданная заявка: отказ из-за
+		// занятости не должен мешать повторной попытке, когда аппарат освободится
+		if fallback && recorder.status >= http.StatusMultipleChoices {
+			return
+		}
+
+		err = s.db.CompleteIdempotencyKey(storeCtx, rec.Scope, rec.Key,
+			recorder.status, recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+		if err != nil {
+			s.log.Error().Err(err).Msg("failed to store idempotent response")
+			return
+		}
+		completed = true
+	})
+}
+
+// idempotencyScope возвращает область ключей идемпотентности клиента. Для ключа из базы это
+// его клиент (api_keys.client_id), поэтому после ротации повтор новым ключом получает
+// сохраненный ответ. Статические ключи и клиенты без ключа различаются по ключу, сертификату или адресу.
+func (s *HTTPServer) idempotencyScope(r *http.Request) string {
+	if client, ok := apiClientFromContext(r.Context()); ok && client.Stored {
+		return "api_key:" + strconv.FormatInt(client.ClientID, 10)
+	}
+	return hashHex(s.auth.clientKey(r))
+}
+
+func (s *HTTPServer) idempotencyTTL() time.Duration {
+	if s.cfg.Idempotency.TTLHours > 0 {
+		return time.Duration(s.cfg.Idempotency.TTLHours) * time.Hour
+	}
+	return defaultIdempotencyTTL
+}
+
+func replayIdempotentResponse(w http.ResponseWriter, rec, existing *models.IdempotencyRecord) {
+	switch {
+	case existing.Fingerprint != rec.Fingerprint:
+		writeError(w, http.StatusConflict, "Idempotency-Key was already used with a different request")
+	case existing.InProgress():
+		writeError(w, http.StatusConflict, "request with this Idempotency-Key is still in progress")
+	default:
+		if existing.ContentType != "" {
+			w.Header().Set("Content-Type", existing.ContentType)
+		}
+		w.Header().Set(idempotencyReplayedHeader, "true")
+		w.WriteHeader(existing.StatusCode)
+		_, _ = w.Write(existing.Body)
+	}
+}
+
+func isMutatingMethod(method string) bool {
+	return method == http.MethodPost || method == http.MethodPatch || method == http.MethodDelete
+}
+
+// externalBookingKey возвращает ключ идемпотентности из external_booking_id заявки CRM.
+func externalBookingKey(body []byte) string {
+	var req struct {
+		ExternalBookingID string `json:"external_booking_id"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return ""
+	}
+	id := strings.TrimSpace(req.ExternalBookingID)
+	if id == "" || len(id) > maxIdempotencyKeyLength {
+		return ""
+	}
+	return "book-device:" + id
+}
+
+func requestFingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	for _, part := range []string{r.Method, r.URL.Path, r.URL.RawQuery} {
+		h.Write([]byte(part))
+		h.Write([]byte{'\n'})
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func hashHex(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// idempotencyRecorder передает ответ клиенту и сохраняет его копию.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *idempotencyRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *idempotencyRecorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(p)
+	return r.ResponseWriter.Write(p)
+}
+
+func (r *idempotencyRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
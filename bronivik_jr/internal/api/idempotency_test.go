@@ -0,0 +1,221 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"bronivik/internal/config"
+	"bronivik/internal/models"
+	"bronivik/internal/service"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func doIdempotent(t *testing.T, method, url, key, body string) (*http.Response, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(idempotencyKeyHeader, key)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, data
+}
+
+func TestIdempotencyMiddleware_Bookings(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	item := &models.Item{Name: "laser", TotalQuantity: 5, IsActive: true}
+	require.NoError(t, db.CreateItem(ctx, item))
+
+	logger := zerolog.New(io.Discard)
+	server := newTestHTTPServer(db)
+	server.SetBookingService(service.NewBookingService(db, nil, 365, 0, &logger))
+	ts := httptest.NewServer(server.server.Handler)
+	t.Cleanup(ts.Close)
+
+	date := time.Now().AddDate(0, 0, 10).Format("2006-01-02")
+	body := fmt.Sprintf(`{"item_id": %d, "date": %q, "client_name": "Ivan", "client_phone": "+7900"}`, item.ID, date)
+
+	first, firstBody := doIdempotent(t, http.MethodPost, ts.URL+"/api/v1/bookings", "create-1", body)
+	require.Equal(t, http.StatusCreated, first.StatusCode)
+	assert.Empty(t, first.Header.Get(idempotencyReplayedHeader))
+
+	replay, replayBody := doIdempotent(t, http.MethodPost, ts.URL+"/api/v1/bookings", "create-1", body)
+	assert.Equal(t, http.StatusCreated, replay.StatusCode)
+	assert.Equal(t, "true", replay.Header.Get(idempotencyReplayedHeader))
+	assert.Equal(t, "application/json", replay.Header.Get("Content-Type"))
+	assert.Equal(t, string(firstBody), string(replayBody))
+
+	bookings, err := db.ListBookings(ctx, &models.BookingFilter{ItemID: item.ID})
+	require.NoError(t, err)
+	assert.Len(t, bookings, 1, "replay must not create a second booking")
+
+	// Тот же ключ с другим телом — конфликт
+	other := strings.Replace(body, "Ivan", "Petr", 1)
+	conflict, _ := doIdempotent(t, http.MethodPost, ts.URL+"/api/v1/bookings", "create-1", other)
+	assert.Equal(t, http.StatusConflict, conflict.StatusCode)
+
+	// Без ключа запрос выполняется каждый раз
+	resp, _ := doIdempotent(t, http.MethodPost, ts.URL+"/api/v1/bookings", "", body)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp, _ = doIdempotent(t, http.MethodPost, ts.URL+"/api/v1/bookings", "", body)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	// Ответ с ошибкой клиента тоже повторяется
+	var created models.Booking
+	require.NoError(t, json.Unmarshal(firstBody, &created))
+	patchURL := fmt.Sprintf("%s/api/v1/bookings/%d", ts.URL, created.ID)
+	resp, _ = doIdempotent(t, http.MethodPatch, patchURL, "patch-1", `{"version": 1, "status": "confirmed"}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, _ = doIdempotent(t, http.MethodPatch, patchURL, "patch-2", `{"version": 1, "status": "canceled"}`)
+	require.Equal(t, http.StatusConflict, resp.StatusCode)
+	resp, _ = doIdempotent(t, http.MethodPatch, patchURL, "patch-2", `{"version": 1, "status": "canceled"}`)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get(idempotencyReplayedHeader))
+	resp, _ = doIdempotent(t, http.MethodPatch, patchURL, "patch-1", `{"version": 1, "status": "confirmed"}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get(idempotencyReplayedHeader))
+
+	long, _ := doIdempotent(t, http.MethodPost, ts.URL+"/api/v1/bookings", strings.Repeat("k", 300), body)
+	assert.Equal(t, http.StatusBadRequest, long.StatusCode)
+}
+
+func TestIdempotencyMiddleware_BookDeviceFallback(t *testing.T) {
+	db := newTestDB(t)
+	item := createTestItem(t, db, "laser", 1)
+	server := newTestHTTPServer(db)
+	ts := httptest.NewServer(server.server.Handler)
+	t.Cleanup(ts.Close)
+
+	body := fmt.Sprintf(`{"device_id": %d, "date": "2026-01-15", "external_booking_id": "crm-1", "client_name": "Ivan"}`, item.ID)
+	first, firstBody := doIdempotent(t, http.MethodPost, ts.URL+"/api/book-device", "", body)
+	require.Equal(t, http.StatusOK, first.StatusCode)
+
+	// Повтор без заголовка узнается по external_booking_id, хотя аппарат уже занят
+	replay, replayBody := doIdempotent(t, http.MethodPost, ts.URL+"/api/book-device", "", body)
+	assert.Equal(t, http.StatusOK, replay.StatusCode)
+	assert.Equal(t, "true", replay.Header.Get(idempotencyReplayedHeader))
+	assert.Equal(t, string(firstBody), string(replayBody))
+
+	// Другой аппарат или дата с тем же external_booking_id — конфликт
+	other := strings.Replace(body, "2026-01-15", "2026-01-16", 1)
+	conflict, _ := doIdempotent(t, http.MethodPost, ts.URL+"/api/book-device", "", other)
+	assert.Equal(t, http.StatusConflict, conflict.StatusCode)
+
+	// Отказ по занятости не запоминается
+	busy := strings.Replace(body, "crm-1", "crm-2", 1)
+	resp, _ := doIdempotent(t, http.MethodPost, ts.URL+"/api/book-device", "", busy)
+	require.Equal(t, http.StatusConflict, resp.StatusCode)
+	resp, _ = doIdempotent(t, http.MethodDelete, ts.URL+"/api/book-device/crm-1", "", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, _ = doIdempotent(t, http.MethodPost, ts.URL+"/api/book-device", "", busy)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(idempotencyReplayedHeader))
+}
+
+func TestIdempotencyMiddleware_ServerErrorNotStored(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestHTTPServer(db)
+
+	calls := 0
+	handler := server.idempotencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			writeError(w, http.StatusInternalServerError, "internal error")
+			return
+		}
+		writeJSON(w, http.StatusCreated, map[string]int{"call": calls})
+	}))
+
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings", strings.NewReader(`{}`))
+		req.Header.Set(idempotencyKeyHeader, "retry-me")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusInternalServerError, send().Code)
+	second := send()
+	assert.Equal(t, http.StatusCreated, second.Code)
+	third := send()
+	assert.Equal(t, http.StatusCreated, third.Code)
+	assert.Equal(t, second.Body.String(), third.Body.String())
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyMiddleware_ScopeSurvivesRotation(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	item := &models.Item{Name: "laser", TotalQuantity: 5, IsActive: true}
+	require.NoError(t, db.CreateItem(ctx, item))
+	logger := zerolog.New(io.Discard)
+
+	cfg := config.APIConfig{
+		Enabled: true,
+		HTTP:    config.APIHTTPConfig{Enabled: true, Port: 0},
+		Auth:    config.APIAuthConfig{Enabled: true},
+	}
+	server := NewHTTPServer(&cfg, db, nil, nil, &logger)
+	server.SetBookingService(service.NewBookingService(db, nil, 365, 0, &logger))
+	keys := NewAPIKeyStore(db, time.Minute, &logger)
+	server.SetAPIKeys(keys)
+	ts := httptest.NewServer(server.server.Handler)
+	t.Cleanup(ts.Close)
+
+	svc := service.NewAPIKeyService(db, time.Hour, &logger)
+	key, err := svc.CreateKey(ctx, "crm", []string{models.PermWriteBookings}, nil, models.APIKeyLimits{})
+	require.NoError(t, err)
+	other, err := svc.CreateKey(ctx, "partner", []string{models.PermWriteBookings}, nil, models.APIKeyLimits{})
+	require.NoError(t, err)
+	require.NoError(t, keys.Reload(ctx))
+
+	date := time.Now().AddDate(0, 0, 10).Format("2006-01-02")
+	body := fmt.Sprintf(`{"item_id": %d, "date": %q, "client_name": "Ivan", "client_phone": "+7900"}`, item.ID, date)
+	post := func(apiKey string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/v1/bookings", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("x-api-key", apiKey)
+		req.Header.Set(idempotencyKeyHeader, "create-1")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	require.Equal(t, http.StatusCreated, post(key.Key).StatusCode)
+
+	// Повтор после ротации новым ключом того же клиента получает сохраненный ответ
+	rotated, err := svc.RotateKey(ctx, key.ID, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, keys.Reload(ctx))
+	resp := post(rotated.Key)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get(idempotencyReplayedHeader))
+
+	// У другого клиента свои ключи идемпотентности
+	resp = post(other.Key)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(idempotencyReplayedHeader))
+
+	bookings, err := db.ListBookings(ctx, &models.BookingFilter{ItemID: item.ID})
+	require.NoError(t, err)
+	assert.Len(t, bookings, 2)
+}
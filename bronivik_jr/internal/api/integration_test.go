@@ -53,6 +53,9 @@ func newIntegrationDB(t *testing.T) *database.DB {
 		t.Fatalf("new db: %v", err)
 	}
 	t.Cleanup(func() { db.Close() })
+	if err := db.Migrate(context.Background(), ""); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
 	return db
 }
 
@@ -19,3 +19,17 @@ func ChainUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) grpc.Un
 		return chained(ctx, req)
 	}
 }
+
+func ChainStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			current := interceptors[i]
+			next := chained
+			chained = func(currentSrv any, currentStream grpc.ServerStream) error {
+				return current(currentSrv, currentStream, info, next)
+			}
+		}
+		return chained(srv, ss)
+	}
+}
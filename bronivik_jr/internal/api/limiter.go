@@ -1,42 +1,291 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
 	"sync"
+	"time"
 
 	"bronivik/internal/config"
+	"bronivik/internal/metrics"
 
+	"github.com/redis/go-redis/v9"
 	"golang.org/x/time/rate"
 )
 
+const (
+	defaultRateLimitBurst = 5
+	limitReasonRate       = "rate_limited"
+	limitReasonQuota      = "quota_exceeded"
+	anonymousClient       = "anonymous"
+	rateKeyPrefix         = "api_rate:"
+	quotaKeyPrefix        = "api_quota:"
+)
+
+// limitPolicy — лимиты одного клиента: скорость (токен-бакет) и квота запросов на сутки по UTC.
+type limitPolicy struct {
+	rps        float64
+	burst      int
+	dailyQuota int
+}
+
+// limitDecision — результат проверки и значения заголовков RateLimit-*. Заголовки описывают
+// тот из лимитов, до которого клиенту осталось меньше запросов.
+type limitDecision struct {
+	allowed    bool
+	reason     string
+	limit      int
+	remaining  int
+	reset      time.Duration
+	retryAfter time.Duration
+	quotaUsed  int
+}
+
+type limitStore interface {
+	take(ctx context.Context, id string, p limitPolicy, now time.Time) (limitDecision, error)
+}
+
 type rateLimiter struct {
-	limiters sync.Map
 	cfg      *config.APIConfig
+	store    limitStore
+	fallback *memoryLimitStore
 }
 
 func newRateLimiter(cfg *config.APIConfig) *rateLimiter {
-	return &rateLimiter{
-		cfg: cfg,
+	mem := newMemoryLimitStore()
+	return &rateLimiter{cfg: cfg, store: mem, fallback: mem}
+}
+
+// useRedis переносит счетчики в Redis, чтобы процессы бота и API делили одни лимиты.
+func (l *rateLimiter) useRedis(client *redis.Client) {
+	l.store = &redisLimitStore{client: client}
+}
+
+// policy возвращает лимиты клиента: собственные значения ключа или общие из api.rate_limit.
+func (l *rateLimiter) policy(client config.APIClientKey) limitPolicy {
+	p := limitPolicy{rps: l.cfg.RateLimit.RPS, burst: l.cfg.RateLimit.Burst, dailyQuota: l.cfg.RateLimit.DailyQuota}
+	if client.RateLimitRPS > 0 {
+		p.rps = client.RateLimitRPS
+	}
+	if client.RateLimitBurst > 0 {
+		p.burst = client.RateLimitBurst
+	}
+	if client.DailyQuota > 0 {
+		p.dailyQuota = client.DailyQuota
+	}
+	if p.burst <= 0 {
+		p.burst = defaultRateLimitBurst
 	}
+	return p
 }
 
-func (l *rateLimiter) getLimiter(key string) *rate.Limiter {
-	if v, ok := l.limiters.Load(key); ok {
-		if lim, ok := v.(*rate.Limiter); ok {
-			return lim
+// allow списывает запрос клиента id. ok=false — лимиты не заданы и заголовки не нужны.
+// Если Redis недоступен, лимит считается в памяти процесса.
+func (l *rateLimiter) allow(ctx context.Context, id string, client config.APIClientKey) (limitDecision, bool) {
+	p := l.policy(client)
+	if p.rps <= 0 && p.dailyQuota <= 0 {
+		return limitDecision{}, false
+	}
+
+	key := hashHex(id)
+	now := time.Now()
+	d, err := l.store.take(ctx, key, p, now)
+	if err != nil {
+		d, _ = l.fallback.take(ctx, key, p, now)
+	}
+
+	name := client.Name
+	if name == "" {
+		name = anonymousClient
+	}
+	result := "allowed"
+	if !d.allowed {
+		result = d.reason
+	}
+	metrics.IncAPIKeyRequest(name, result)
+	if p.dailyQuota > 0 {
+		metrics.SetAPIKeyQuotaUsed(name, d.quotaUsed)
+	}
+	return d, true
+}
+
+// decide собирает решение из остатка токенов и числа запросов за сутки.
+func decide(p limitPolicy, allowed bool, tokens float64, quotaUsed int, now time.Time) limitDecision {
+	d := limitDecision{allowed: allowed, quotaUsed: quotaUsed, remaining: math.MaxInt}
+
+	quotaExceeded := p.dailyQuota > 0 && quotaUsed >= p.dailyQuota && !allowed
+	if p.dailyQuota > 0 {
+		d.limit = p.dailyQuota
+		d.remaining = max(p.dailyQuota-quotaUsed, 0)
+		d.reset = untilNextDay(now)
+	}
+	if p.rps > 0 && !quotaExceeded {
+		remaining := int(math.Floor(tokens))
+		if remaining < d.remaining || !allowed {
+			d.limit = p.burst
+			d.remaining = max(remaining, 0)
+			d.reset = time.Duration((float64(p.burst) - tokens) / p.rps * float64(time.Second))
 		}
 	}
 
-	burst := l.cfg.RateLimit.Burst
-	if burst <= 0 {
-		burst = 5
+	switch {
+	case allowed:
+	case quotaExceeded:
+		d.reason = limitReasonQuota
+		d.retryAfter = untilNextDay(now)
+	default:
+		d.reason = limitReasonRate
+		d.retryAfter = time.Duration((1 - tokens) / p.rps * float64(time.Second))
+	}
+	return d
+}
+
+func untilNextDay(now time.Time) time.Duration {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC).Sub(now)
+}
+
+// seconds округляет длительность вверх до целых секунд, как требуют RateLimit-Reset и Retry-After.
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
+
+// headers возвращает пары заголовков (или трейлеров gRPC) для ответа.
+func (d limitDecision) headers() [][2]string {
+	h := [][2]string{
+		{"RateLimit-Limit", strconv.Itoa(d.limit)},
+		{"RateLimit-Remaining", strconv.Itoa(d.remaining)},
+		{"RateLimit-Reset", seconds(d.reset)},
+	}
+	if !d.allowed {
+		h = append(h, [2]string{"Retry-After", seconds(max(d.retryAfter, time.Second))})
+	}
+	return h
+}
+
+// err возвращает ошибку для отклоненного запроса.
+func (d limitDecision) err() error {
+	switch {
+	case d.allowed:
+		return nil
+	case d.reason == limitReasonQuota:
+		return errors.New("daily quota exceeded")
+	default:
+		return errors.New("rate limit exceeded")
+	}
+}
+
+// memoryLimitStore считает лимиты в памяти одного процесса.
+type memoryLimitStore struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	quotas   map[string]dailyCount
+}
+
+type dailyCount struct {
+	day  string
+	used int
+}
+
+func newMemoryLimitStore() *memoryLimitStore {
+	return &memoryLimitStore{limiters: map[string]*rate.Limiter{}, quotas: map[string]dailyCount{}}
+}
+
+func (s *memoryLimitStore) take(_ context.Context, id string, p limitPolicy, now time.Time) (limitDecision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	day := now.UTC().Format("2006-01-02")
+	count := s.quotas[id]
+	if count.day != day {
+		count = dailyCount{day: day}
+	}
+	if p.dailyQuota > 0 && count.used >= p.dailyQuota {
+		return decide(p, false, 0, count.used, now), nil
 	}
 
-	lim := rate.NewLimiter(rate.Limit(l.cfg.RateLimit.RPS), burst)
-	actual, loaded := l.limiters.LoadOrStore(key, lim)
-	if loaded {
-		if actualLim, ok := actual.(*rate.Limiter); ok {
-			return actualLim
+	tokens := float64(p.burst)
+	if p.rps > 0 {
+		lim, ok := s.limiters[id]
+		if !ok {
+			lim = rate.NewLimiter(rate.Limit(p.rps), p.burst)
+			s.limiters[id] = lim
+		}
+		// Лимиты ключа могли поменяться после перечитывания
+		if lim.Limit() != rate.Limit(p.rps) || lim.Burst() != p.burst {
+			lim.SetLimitAt(now, rate.Limit(p.rps))
+			lim.SetBurstAt(now, p.burst)
 		}
+		if !lim.AllowN(now, 1) {
+			return decide(p, false, lim.TokensAt(now), count.used, now), nil
+		}
+		tokens = lim.TokensAt(now)
+	}
+
+	count.used++
+	s.quotas[id] = count
+	return decide(p, true, tokens, count.used, now), nil
+}
+
+// redisLimitStore считает лимиты в Redis одним скриптом, чтобы проверка и списание были атомарны.
+type redisLimitStore struct {
+	client *redis.Client
+}
+
+// takeScript: KEYS[1] — токен-бакет, KEYS[2] — счетчик за сутки;
+// ARGV: rps, burst, время в мс, квота, TTL счетчика в мс.
+// Возвращает {разрешено, остаток токенов * 1000, запросов за сутки}.
+var takeScript = redis.NewScript(`
+local rps = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local quota = tonumber(ARGV[4])
+local quota_ttl = tonumber(ARGV[5])
+
+local used = 0
+if quota > 0 then
+  used = tonumber(redis.call('GET', KEYS[2]) or '0')
+  if used >= quota then
+    return {0, 0, used}
+  end
+end
+
+local tokens = burst
+if rps > 0 then
+  local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+  if state[1] then
+    local elapsed = math.max(0, now - tonumber(state[2]))
+    tokens = math.min(burst, tonumber(state[1]) + elapsed * rps / 1000)
+  end
+  if tokens < 1 then
+    return {0, math.floor(tokens * 1000), used}
+  end
+  tokens = tokens - 1
+  redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+  redis.call('PEXPIRE', KEYS[1], math.ceil(burst * 1000 / rps) + 1000)
+end
+
+if quota > 0 then
+  used = redis.call('INCR', KEYS[2])
+  if used == 1 then
+    redis.call('PEXPIRE', KEYS[2], quota_ttl)
+  end
+end
+return {1, math.floor(tokens * 1000), used}
+`)
+
+func (s *redisLimitStore) take(ctx context.Context, id string, p limitPolicy, now time.Time) (limitDecision, error) {
+	day := now.UTC().Format("2006-01-02")
+	keys := []string{rateKeyPrefix + id, quotaKeyPrefix + id + ":" + day}
+	// Счетчик живет чуть дольше суток, чтобы расхождение часов не обнулило его раньше времени
+	quotaTTL := untilNextDay(now) + time.Hour
+	res, err := takeScript.Run(ctx, s.client, keys,
+		strconv.FormatFloat(p.rps, 'f', -1, 64), p.burst, now.UnixMilli(), p.dailyQuota, quotaTTL.Milliseconds(),
+	).Int64Slice()
+	if err != nil {
+		return limitDecision{}, err
 	}
-	return lim
+	return decide(p, res[0] == 1, float64(res[1])/1000, int(res[2]), now), nil
 }
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"bronivik/internal/config"
+	"bronivik/internal/models"
+	"bronivik/internal/service"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRateLimiterPolicy(t *testing.T) {
+	cfg := config.APIConfig{RateLimit: config.APIRateLimitConfig{RPS: 10, DailyQuota: 500}}
+	l := newRateLimiter(&cfg)
+
+	assert.Equal(t, limitPolicy{rps: 10, burst: defaultRateLimitBurst, dailyQuota: 500}, l.policy(config.APIClientKey{}))
+	own := config.APIClientKey{RateLimitRPS: 0.5, RateLimitBurst: 2, DailyQuota: 20}
+	assert.Equal(t, limitPolicy{rps: 0.5, burst: 2, dailyQuota: 20}, l.policy(own))
+
+	// Без лимитов проверка не выполняется и заголовки не нужны
+	cfg.RateLimit = config.APIRateLimitConfig{}
+	_, ok := l.allow(context.Background(), "key", config.APIClientKey{})
+	assert.False(t, ok)
+}
+
+func TestRateLimiterSharedInRedis(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	cfg := config.APIConfig{}
+	client := config.APIClientKey{Name: "crm", RateLimitRPS: 1, RateLimitBurst: 2, DailyQuota: 3}
+
+	// Два процесса с общим Redis списывают из одного бакета
+	first, second := newRateLimiter(&cfg), newRateLimiter(&cfg)
+	first.useRedis(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	second.useRedis(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+
+	d, ok := first.allow(ctx, "crm-key", client)
+	require.True(t, ok)
+	assert.True(t, d.allowed)
+	assert.Equal(t, 1, d.quotaUsed)
+	d, _ = second.allow(ctx, "crm-key", client)
+	assert.True(t, d.allowed)
+	assert.Equal(t, 0, d.remaining)
+
+	d, _ = first.allow(ctx, "crm-key", client)
+	assert.False(t, d.allowed)
+	assert.Equal(t, limitReasonRate, d.reason)
+	assert.Equal(t, 2, d.limit)
+	assert.EqualError(t, d.err(), "rate limit exceeded")
+	assert.Contains(t, d.headers(), [2]string{"Retry-After", "1"})
+
+	// После пополнения бакета упираемся в суточную квоту
+	time.Sleep(1100 * time.Millisecond)
+	d, _ = second.allow(ctx, "crm-key", client)
+	assert.True(t, d.allowed)
+	assert.Equal(t, 3, d.quotaUsed)
+	time.Sleep(1100 * time.Millisecond)
+	d, _ = first.allow(ctx, "crm-key", client)
+	assert.False(t, d.allowed)
+	assert.Equal(t, limitReasonQuota, d.reason)
+	assert.Equal(t, 3, d.limit)
+	assert.Equal(t, 0, d.remaining)
+	assert.EqualError(t, d.err(), "daily quota exceeded")
+	assert.Greater(t, d.retryAfter, time.Duration(0))
+	assert.LessOrEqual(t, d.retryAfter, 24*time.Hour)
+
+	// Недоступный Redis не отключает лимиты: счет переходит в память процесса
+	mr.Close()
+	d, ok = first.allow(ctx, "other-key", config.APIClientKey{DailyQuota: 1})
+	require.True(t, ok)
+	assert.True(t, d.allowed)
+	d, _ = first.allow(ctx, "other-key", config.APIClientKey{DailyQuota: 1})
+	assert.False(t, d.allowed)
+}
+
+func TestMemoryLimitStoreResetsQuotaDaily(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryLimitStore()
+	p := limitPolicy{burst: defaultRateLimitBurst, dailyQuota: 1}
+	now := time.Date(2026, 1, 1, 23, 59, 0, 0, time.UTC)
+
+	d, _ := s.take(ctx, "key", p, now)
+	assert.True(t, d.allowed)
+	d, _ = s.take(ctx, "key", p, now)
+	assert.False(t, d.allowed)
+	assert.Equal(t, time.Minute, d.retryAfter)
+	assert.Contains(t, d.headers(), [2]string{"RateLimit-Reset", "60"})
+
+	d, _ = s.take(ctx, "key", p, now.Add(time.Minute))
+	assert.True(t, d.allowed)
+}
+
+func TestHTTPRateLimitHeadersPerKey(t *testing.T) {
+	db := newTestDB(t)
+	logger := zerolog.New(io.Discard)
+	ctx := context.Background()
+
+	cfg := config.APIConfig{
+		Enabled:   true,
+		HTTP:      config.APIHTTPConfig{Enabled: true},
+		Auth:      config.APIAuthConfig{Enabled: true},
+		RateLimit: config.APIRateLimitConfig{RPS: 100, Burst: 100},
+	}
+	server := NewHTTPServer(&cfg, db, redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()}), nil, &logger)
+	svc := service.NewAPIKeyService(db, time.Hour, &logger)
+	keys := NewAPIKeyStore(db, time.Minute, &logger)
+	server.SetAPIKeys(keys)
+	ts := httptest.NewServer(server.server.Handler)
+	t.Cleanup(ts.Close)
+
+	limited, err := svc.CreateKey(ctx, "partner", []string{models.PermReadItems}, nil, models.APIKeyLimits{DailyQuota: 2})
+	require.NoError(t, err)
+	regular, err := svc.CreateKey(ctx, "crm", []string{models.PermReadItems}, nil, models.APIKeyLimits{})
+	require.NoError(t, err)
+	require.NoError(t, keys.Reload(ctx))
+
+	get := func(key string) *http.Response {
+		req, _ := http.NewRequest(http.MethodGet, ts.URL+"/api/v1/items", http.NoBody)
+		req.Header.Set("x-api-key", key)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	resp := get(limited.Key)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get("RateLimit-Limit"))
+	assert.Equal(t, "1", resp.Header.Get("RateLimit-Remaining"))
+	assert.Empty(t, resp.Header.Get("Retry-After"))
+	get(limited.Key)
+
+	resp = get(limited.Key)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "0", resp.Header.Get("RateLimit-Remaining"))
+	retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	require.NoError(t, err)
+	assert.Positive(t, retryAfter)
+	assert.Equal(t, resp.Header.Get("RateLimit-Reset"), resp.Header.Get("Retry-After"))
+
+	// Ключ без своих лимитов живет по общему api.rate_limit
+	resp = get(regular.Key)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "100", resp.Header.Get("RateLimit-Limit"))
+
+	// Новые лимиты начинают действовать после перечитывания ключей
+	require.NoError(t, svc.SetKeyLimits(ctx, limited.ID, models.APIKeyLimits{DailyQuota: 5}))
+	require.NoError(t, keys.Reload(ctx))
+	resp = get(limited.Key)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "5", resp.Header.Get("RateLimit-Limit"))
+	assert.Equal(t, "2", resp.Header.Get("RateLimit-Remaining"))
+}
+
+func TestGRPCRateLimitTrailers(t *testing.T) {
+	cfg := config.APIConfig{
+		Enabled: true,
+		Auth: config.APIAuthConfig{
+			Enabled: true,
+			APIKeys: []config.APIClientKey{{Name: "crm", Key: "crm-key", Extra: "crm-extra", DailyQuota: 1}},
+		},
+	}
+	auth := NewAuthInterceptor(&cfg)
+	info := &grpc.UnaryServerInfo{FullMethod: "/bronivik.availability.v1.AvailabilityService/GetAvailability"}
+	handler := func(context.Context, any) (any, error) { return "ok", nil }
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", "crm-key", "x-api-extra", "crm-extra"))
+
+	_, err := auth.Unary()(ctx, nil, info, handler)
+	require.NoError(t, err)
+	_, err = auth.Unary()(ctx, nil, info, handler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "daily quota exceeded")
+}
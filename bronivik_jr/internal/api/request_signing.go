@@ -0,0 +1,189 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"bronivik/internal/config"
+	"bronivik/internal/reqsign"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// gRPC работает поверх HTTP/2 POST, путь подписи — полное имя метода
+	grpcSignMethod     = http.MethodPost
+	maxSignedBodyBytes = 1 << 20
+	nonceKeyPrefix     = "api_nonce:"
+)
+
+var (
+	errSignedBodyTooLarge   = errors.New("request body is too large")
+	errSignatureUnavailable = errors.New("request signature check unavailable")
+)
+
+// RedisNonceCache хранит nonce подписанных запросов в Redis, чтобы повтор
+// отклонялся любым экземпляром API, а не только принявшим запрос.
+type RedisNonceCache struct {
+	client *redis.Client
+}
+
+func NewRedisNonceCache(client *redis.Client) *RedisNonceCache {
+	return &RedisNonceCache{client: client}
+}
+
+func (c *RedisNonceCache) Add(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, nonceKeyPrefix+nonce, 1, ttl).Result()
+}
+
+// signatureRejection переводит ошибку проверки подписи в текст ответа клиенту.
+// Пустая строка — подпись не отклонена, а проверить ее не удалось.
+func signatureRejection(err error) string {
+	switch {
+	case errors.Is(err, reqsign.ErrMissing):
+		return "missing request signature"
+	case errors.Is(err, reqsign.ErrNoSecret):
+		return "request signing is not configured for this api key"
+	case errors.Is(err, reqsign.ErrTimestamp):
+		return "request signature expired"
+	case errors.Is(err, reqsign.ErrReplay):
+		return "request replayed"
+	case errors.Is(err, reqsign.ErrBadSignature):
+		return "invalid request signature"
+	default:
+		return ""
+	}
+}
+
+// checkSignature проверяет подпись HTTP-запроса. Подписанный запрос проверяется всегда,
+// неподписанный пропускается, если подпись не обязательна.
+func (a *HTTPAuth) checkSignature(r *http.Request, client config.APIClientKey) error {
+	h := reqsign.Headers{
+		Signature: r.Header.Get(reqsign.HeaderSignature),
+		Timestamp: r.Header.Get(reqsign.HeaderTimestamp),
+		Nonce:     r.Header.Get(reqsign.HeaderNonce),
+	}
+	if h.Empty() && !a.cfg.Auth.Signing.Required {
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSignedBodyBytes+1))
+	if err != nil {
+		return fmt.Errorf("failed to read request body")
+	}
+	if len(body) > maxSignedBodyBytes {
+		return errSignedBodyTooLarge
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	err = a.signatures.Verify(r.Context(), client.SigningSecret, r.Method, r.URL.RequestURI(), reqsign.BodyHash(body), h)
+	if err != nil {
+		if msg := signatureRejection(err); msg != "" {
+			return errors.New(msg)
+		}
+		return errSignatureUnavailable
+	}
+	return nil
+}
+
+// checkSignature проверяет подпись вызова gRPC; правила те же, что и для HTTP.
+func (a *AuthInterceptor) checkSignature(
+	ctx context.Context,
+	md metadata.MD,
+	fullMethod string,
+	req any,
+	client config.APIClientKey,
+) error {
+	h := reqsign.Headers{
+		Signature: first(md.Get(reqsign.HeaderSignature)),
+		Timestamp: first(md.Get(reqsign.HeaderTimestamp)),
+		Nonce:     first(md.Get(reqsign.HeaderNonce)),
+	}
+	if h.Empty() && !a.cfg.Auth.Signing.Required {
+		return nil
+	}
+
+	body, err := grpcSignedBody(req)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "failed to encode request for signature check")
+	}
+	if err := a.signatures.Verify(ctx, client.SigningSecret, grpcSignMethod, fullMethod, reqsign.BodyHash(body), h); err != nil {
+		if msg := signatureRejection(err); msg != "" {
+			return status.Error(codes.Unauthenticated, msg)
+		}
+		return status.Error(codes.Unavailable, errSignatureUnavailable.Error())
+	}
+	return nil
+}
+
+// grpcSignedBody — тело вызова для подписи: детерминированная сериализация сообщения.
+// Потоковые вызовы проверяются до чтения запроса, поэтому их тело пустое.
+func grpcSignedBody(req any) ([]byte, error) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return nil, nil
+	}
+	return proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+}
+
+// SigningUnaryClientInterceptor подписывает исходящие unary-вызовы gRPC секретом ключа.
+func SigningUnaryClientInterceptor(secret string) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		body, err := grpcSignedBody(req)
+		if err != nil {
+			return err
+		}
+		ctx, err = signOutgoing(ctx, secret, method, body)
+		if err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// SigningStreamClientInterceptor подписывает открытие потоков gRPC (с пустым телом).
+func SigningStreamClientInterceptor(secret string) grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		ctx, err := signOutgoing(ctx, secret, method, nil)
+		if err != nil {
+			return nil, err
+		}
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+func signOutgoing(ctx context.Context, secret, method string, body []byte) (context.Context, error) {
+	h, err := reqsign.SignRequest(secret, grpcSignMethod, method, body, time.Now())
+	if err != nil {
+		return ctx, err
+	}
+	return metadata.AppendToOutgoingContext(ctx,
+		reqsign.HeaderSignature, h.Signature,
+		reqsign.HeaderTimestamp, h.Timestamp,
+		reqsign.HeaderNonce, h.Nonce,
+	), nil
+}
@@ -0,0 +1,302 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"bronivik/internal/database"
+	"bronivik/internal/domain"
+	"bronivik/internal/metrics"
+	"bronivik/internal/models"
+)
+
+const seriesPathPrefix = "/api/v1/booking-series"
+
+// CreateSeriesRequest is the request body for creating a recurring booking series.
+type CreateSeriesRequest struct {
+	ItemID      int64  `json:"item_id"`
+	ItemName    string `json:"item_name,omitempty"` // Alternative to item_id
+	StartDate   string `json:"start_date"`          // Format: YYYY-MM-DD
+	Frequency   string `json:"frequency"`           // weekly, monthly
+	Interval    int    `json:"interval,omitempty"`
+	Until       string `json:"until,omitempty"` // Format: YYYY-MM-DD
+	Count       int    `json:"count,omitempty"`
+	UserID      int64  `json:"user_id,omitempty"`
+	ClientName  string `json:"client_name"`
+	ClientPhone string `json:"client_phone"`
+	Comment     string `json:"comment,omitempty"`
+	Status      string `json:"status,omitempty"` // pending (default) or confirmed
+}
+
+// ChangeSeriesItemRequest is the request body for moving a series or an occurrence to another item.
+type ChangeSeriesItemRequest struct {
+	ItemID int64 `json:"item_id"`
+}
+
+// SeriesDetailResponse is the response for GET /api/v1/booking-series/{id}.
+type SeriesDetailResponse struct {
+	Series   *models.BookingSeries `json:"series"`
+	Bookings []*models.Booking     `json:"bookings"`
+}
+
+// SetBookingService подключает сервис бронирований для эндпоинтов, изменяющих заявки.
+func (s *HTTPServer) SetBookingService(bookingService domain.BookingService) {
+	s.bookingService = bookingService
+}
+
+// handleBookingSeries routes series endpoints:
+//
+//	POST /api/v1/booking-series
+//	GET  /api/v1/booking-series/{id}
+//	POST /api/v1/booking-series/{id}/confirm|cancel|item
+//	POST /api/v1/booking-series/{id}/occurrences/{booking_id}/confirm|cancel|item
+func (s *HTTPServer) handleBookingSeries(w http.ResponseWriter, r *http.Request) {
+	metrics.IncHTTP("booking_series")
+	if s.bookingService == nil {
+		writeError(w, http.StatusServiceUnavailable, "booking service is not configured")
+		return
+	}
+	// Смена статусов через API проверяется по правилам API-клиентов
+	r = withChangeReason(r.WithContext(models.WithStatusActor(r.Context(), models.ActorAPI)))
+
+	parts := splitPath(strings.TrimPrefix(r.URL.Path, seriesPathPrefix))
+	if len(parts) == 0 {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		s.createSeries(w, r)
+		return
+	}
+
+	seriesID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || seriesID <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid series id")
+		return
+	}
+
+	switch {
+	case len(parts) == 1:
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		s.getSeries(w, r, seriesID)
+	case len(parts) == 2:
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		s.updateSeries(w, r, seriesID, parts[1])
+	case len(parts) == 4 && parts[1] == "occurrences":
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		bookingID, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil || bookingID <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid booking id")
+			return
+		}
+		s.updateOccurrence(w, r, seriesID, bookingID, parts[3])
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *HTTPServer) createSeries(w http.ResponseWriter, r *http.Request) {
+	var req CreateSeriesRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	if req.ClientName == "" || req.ClientPhone == "" {
+		writeError(w, http.StatusBadRequest, "client_name and client_phone are required")
+		return
+	}
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid start_date format; expected YYYY-MM-DD")
+		return
+	}
+
+	rule := models.RecurrenceRule{Frequency: req.Frequency, Interval: req.Interval, Count: req.Count}
+	if req.Until != "" {
+		until, err := time.Parse("2006-01-02", req.Until)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid until format; expected YYYY-MM-DD")
+			return
+		}
+		rule.Until = &until
+	}
+
+	status := req.Status
+	if status == "" {
+		status = models.StatusPending
+	}
+	if status != models.StatusPending && status != models.StatusConfirmed {
+		writeError(w, http.StatusBadRequest, "status must be pending or confirmed")
+		return
+	}
+
+	var item *models.Item
+	switch {
+	case req.ItemID > 0:
+		item, err = s.db.GetItemByID(r.Context(), req.ItemID)
+	case req.ItemName != "":
+		item, err = s.db.GetItemByName(r.Context(), req.ItemName)
+	default:
+		writeError(w, http.StatusBadRequest, "item_id or item_name is required")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusNotFound, "item not found")
+		return
+	}
+
+	template := &models.Booking{
+		UserID:       req.UserID,
+		UserName:     req.ClientName,
+		UserNickname: req.ClientName,
+		Phone:        req.ClientPhone,
+		ItemID:       item.ID,
+		ItemName:     item.Name,
+		Date:         startDate,
+		Status:       status,
+		Comment:      req.Comment,
+	}
+
+	result, err := s.bookingService.CreateBookingSeries(r.Context(), template, rule)
+	if err != nil {
+		if result != nil && errors.Is(err, database.ErrNotAvailable) {
+			writeJSON(w, http.StatusConflict, result)
+			return
+		}
+		s.writeSeriesError(w, err)
+		return
+	}
+
+	s.log.Info().
+		Int64("series_id", result.Series.ID).
+		Int("created", len(result.Bookings)).
+		Int("conflicts", len(result.Conflicts)).
+		Msg("booking series created via API")
+
+	writeJSON(w, http.StatusCreated, result)
+}
+
+func (s *HTTPServer) getSeries(w http.ResponseWriter, r *http.Request, seriesID int64) {
+	series, bookings, err := s.bookingService.GetBookingSeries(r.Context(), seriesID)
+	if err != nil {
+		s.writeSeriesError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, SeriesDetailResponse{Series: series, Bookings: bookings})
+}
+
+func (s *HTTPServer) updateSeries(w http.ResponseWriter, r *http.Request, seriesID int64, action string) {
+	var result *models.SeriesResult
+	var err error
+
+	switch action {
+	case "confirm":
+		result, err = s.bookingService.ConfirmSeries(r.Context(), seriesID, 0)
+	case "cancel":
+		result, err = s.bookingService.CancelSeries(r.Context(), seriesID, 0)
+	case "item":
+		var req ChangeSeriesItemRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ItemID <= 0 {
+			writeError(w, http.StatusBadRequest, "item_id is required")
+			return
+		}
+		result, err = s.bookingService.ChangeSeriesItem(r.Context(), seriesID, req.ItemID, 0)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	if err != nil {
+		s.writeSeriesError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// updateOccurrence applies an action to a single booking of the series.
+func (s *HTTPServer) updateOccurrence(w http.ResponseWriter, r *http.Request, seriesID, bookingID int64, action string) {
+	booking, err := s.bookingService.GetBooking(r.Context(), bookingID)
+	if err != nil || booking.SeriesID == nil || *booking.SeriesID != seriesID {
+		writeError(w, http.StatusNotFound, "booking not found in series")
+		return
+	}
+
+	switch action {
+	case "confirm":
+		err = s.bookingService.ConfirmBooking(r.Context(), booking.ID, booking.Version, 0)
+	case "cancel":
+		err = s.bookingService.RejectBooking(r.Context(), booking.ID, booking.Version, 0)
+	case "item":
+		var req ChangeSeriesItemRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ItemID <= 0 {
+			writeError(w, http.StatusBadRequest, "item_id is required")
+			return
+		}
+		err = s.bookingService.ChangeBookingItem(r.Context(), booking.ID, booking.Version, req.ItemID, 0)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	if err != nil {
+		s.writeSeriesError(w, err)
+		return
+	}
+
+	updated, err := s.bookingService.GetBooking(r.Context(), booking.ID)
+	if err != nil {
+		s.writeSeriesError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, updated)
+}
+
+func (s *HTTPServer) writeSeriesError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		writeError(w, http.StatusNotFound, "not found")
+	case errors.Is(err, models.ErrInvalidRecurrence),
+		errors.Is(err, models.ErrTooManyOccurrences),
+		errors.Is(err, database.ErrPastDate),
+		errors.Is(err, database.ErrDateTooFar),
+		errors.Is(err, database.ErrInvalidDateRange):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, database.ErrNotAvailable),
+		errors.Is(err, database.ErrDuplicateExternalID),
+		errors.Is(err, database.ErrConcurrentModification),
+		errors.Is(err, database.ErrSeriesCanceled),
+		errors.Is(err, models.ErrInvalidTransition),
+		errors.Is(err, models.ErrUnknownStatus):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		s.log.Error().Err(err).Msg("booking request failed")
+		writeError(w, http.StatusInternalServerError, "internal error")
+	}
+}
+
+func splitPath(path string) []string {
+	var parts []string
+	for _, p := range strings.Split(path, "/") {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
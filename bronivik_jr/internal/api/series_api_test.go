@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"bronivik/internal/models"
+	"bronivik/internal/service"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookingSeriesAPI(t *testing.T) {
+	db := newTestDB(t)
+	item := createTestItem(t, db, "camera", 1)
+
+	start := time.Now().AddDate(0, 0, 7).Truncate(24 * time.Hour)
+	insertTestBooking(t, db, &item, start.AddDate(0, 0, 7), models.StatusConfirmed)
+
+	logger := zerolog.New(io.Discard)
+	server := newTestHTTPServer(db)
+	server.SetBookingService(service.NewBookingService(db, nil, 365, 0, &logger))
+	ts := httptest.NewServer(server.server.Handler)
+	t.Cleanup(ts.Close)
+
+	body := fmt.Sprintf(`{"item_id": %d, "start_date": %q, "frequency": "weekly", "count": 3,
+		"client_name": "Client", "client_phone": "+79990000000"}`, item.ID, start.Format("2006-01-02"))
+	resp, err := http.Post(ts.URL+"/api/v1/booking-series", "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var created models.SeriesResult
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	require.NotNil(t, created.Series)
+	assert.Len(t, created.Bookings, 2)
+	require.Len(t, created.Conflicts, 1)
+	assert.Equal(t, start.AddDate(0, 0, 7).Format("2006-01-02"), created.Conflicts[0].Date.Format("2006-01-02"))
+
+	seriesURL := fmt.Sprintf("%s/api/v1/booking-series/%d", ts.URL, created.Series.ID)
+	getResp, err := http.Get(seriesURL)
+	require.NoError(t, err)
+	defer getResp.Body.Close()
+	require.Equal(t, http.StatusOK, getResp.StatusCode)
+
+	var detail SeriesDetailResponse
+	require.NoError(t, json.NewDecoder(getResp.Body).Decode(&detail))
+	assert.Len(t, detail.Bookings, 2)
+
+	// Отмена одной даты серии
+	occurrenceURL := fmt.Sprintf("%s/occurrences/%d/cancel", seriesURL, detail.Bookings[0].ID)
+	occResp, err := http.Post(occurrenceURL, "application/json", nil)
+	require.NoError(t, err)
+	defer occResp.Body.Close()
+	require.Equal(t, http.StatusOK, occResp.StatusCode)
+
+	// Отмена всей серии затрагивает только оставшуюся активную дату
+	cancelResp, err := http.Post(seriesURL+"/cancel", "application/json", nil)
+	require.NoError(t, err)
+	defer cancelResp.Body.Close()
+	require.Equal(t, http.StatusOK, cancelResp.StatusCode)
+
+	var canceled models.SeriesResult
+	require.NoError(t, json.NewDecoder(cancelResp.Body).Decode(&canceled))
+	assert.Equal(t, models.SeriesStatusCanceled, canceled.Series.Status)
+	assert.Len(t, canceled.Bookings, 1)
+
+	againResp, err := http.Post(seriesURL+"/confirm", "application/json", nil)
+	require.NoError(t, err)
+	defer againResp.Body.Close()
+	assert.Equal(t, http.StatusConflict, againResp.StatusCode)
+}
+
+func TestBookingSeriesAPIWithoutService(t *testing.T) {
+	server := newTestHTTPServer(newTestDB(t))
+	ts := httptest.NewServer(server.server.Handler)
+	t.Cleanup(ts.Close)
+
+	resp, err := http.Get(ts.URL + "/api/v1/booking-series/1")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
@@ -2,17 +2,17 @@ package api
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"fmt"
 	"net"
-	"os"
 	"time"
 
 	availabilityv1 "bronivik/internal/api/gen/availability/v1"
+	bookingv1 "bronivik/internal/api/gen/booking/v1"
 	"bronivik/internal/config"
 	"bronivik/internal/database"
+	"bronivik/internal/domain"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -22,6 +22,9 @@ import (
 type GRPCServer struct {
 	cfg      *config.APIConfig
 	db       *database.DB
+	service  *AvailabilityService
+	bookings *BookingGRPCService
+	auth     *AuthInterceptor
 	server   *grpc.Server
 	listener net.Listener
 	log      zerolog.Logger
@@ -40,13 +43,23 @@ func NewGRPCServer(cfg *config.APIConfig, db *database.DB, logger *zerolog.Logge
 		auth.Unary(),
 	)
 
-	serverOpts := []grpc.ServerOption{grpc.UnaryInterceptor(unary)}
+	stream := ChainStreamInterceptors(
+		LoggingStreamInterceptor(logger),
+		auth.Stream(),
+	)
+
+	var serverLogger zerolog.Logger
+	if logger != nil {
+		serverLogger = logger.With().Str("component", "grpc").Logger()
+	}
+
+	serverOpts := []grpc.ServerOption{grpc.UnaryInterceptor(unary), grpc.StreamInterceptor(stream)}
 	if cfg.GRPC.TLS.Enabled {
-		tlsCfg, err := buildTLSConfig(cfg.GRPC.TLS)
+		certs, err := newCertReloader(cfg.GRPC.TLS, []string{"h2"}, serverLogger)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("grpc %w", err)
 		}
-		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(certs.TLSConfig())))
 	}
 
 	grpcServer := grpc.NewServer(serverOpts...)
@@ -54,56 +67,40 @@ func NewGRPCServer(cfg *config.APIConfig, db *database.DB, logger *zerolog.Logge
 	svc := NewAvailabilityService(db)
 	availabilityv1.RegisterAvailabilityServiceServer(grpcServer, svc)
 
+	// Сервис заявок регистрируется сразу; до SetBookingService методы отвечают Unavailable
+	bookings := NewBookingGRPCService(db)
+	bookingv1.RegisterBookingServiceServer(grpcServer, bookings)
+
 	if cfg.GRPC.Reflection {
 		reflection.Register(grpcServer)
 	}
 
-	var serverLogger zerolog.Logger
-	if logger != nil {
-		serverLogger = logger.With().Str("component", "grpc").Logger()
-	}
-
 	return &GRPCServer{
 		cfg:      cfg,
 		db:       db,
+		service:  svc,
+		bookings: bookings,
+		auth:     auth,
 		server:   grpcServer,
 		listener: lis,
 		log:      serverLogger,
 	}, nil
 }
 
-func buildTLSConfig(cfg config.APITLSConfig) (*tls.Config, error) {
-	if cfg.CertFile == "" || cfg.KeyFile == "" {
-		return nil, fmt.Errorf("grpc tls enabled but cert_file/key_file not set")
-	}
-
-	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
-	if err != nil {
-		return nil, fmt.Errorf("load grpc tls keypair: %w", err)
-	}
-
-	tlsCfg := &tls.Config{
-		Certificates: []tls.Certificate{cert},
-		MinVersion:   tls.VersionTLS12,
-	}
+// SetAvailabilityWatcher включает потоковый метод WatchAvailability.
+func (s *GRPCServer) SetAvailabilityWatcher(watcher *AvailabilityWatcher) {
+	s.service.watcher = watcher
+}
 
-	if cfg.RequireClientCert {
-		if cfg.ClientCAFile == "" {
-			return nil, fmt.Errorf("grpc tls require_client_cert=true but client_ca_file not set")
-		}
-		caPEM, err := os.ReadFile(cfg.ClientCAFile)
-		if err != nil {
-			return nil, fmt.Errorf("read client_ca_file: %w", err)
-		}
-		pool := x509.NewCertPool()
-		if !pool.AppendCertsFromPEM(caPEM) {
-			return nil, fmt.Errorf("failed to parse client_ca_file PEM")
-		}
-		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
-		tlsCfg.ClientCAs = pool
-	}
+// SetBookingService подключает сервис бронирований для методов BookingService.
+func (s *GRPCServer) SetBookingService(bookingService domain.BookingService) {
+	s.bookings.bookings = bookingService
+}
 
-	return tlsCfg, nil
+// UseRedis делает nonce подписей и счетчики лимитов общими с HTTP API и другими процессами.
+func (s *GRPCServer) UseRedis(client *redis.Client) {
+	s.auth.signatures.SetNonceCache(NewRedisNonceCache(client))
+	s.auth.limiter.useRedis(client)
 }
 
 func (s *GRPCServer) Addr() string {
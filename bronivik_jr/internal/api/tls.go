@@ -0,0 +1,204 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"bronivik/internal/config"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+var errClientCertMismatch = errors.New("client certificate does not match api key")
+
+// certReloader держит TLS-конфигурацию сервера API и перечитывает сертификат, ключ и CA клиентов,
+// когда файлы меняются. Если новые файлы не читаются (например, ключ еще не дописан),
+// соединения продолжают получать прежний сертификат.
+type certReloader struct {
+	cfg        config.APITLSConfig
+	nextProtos []string
+	interval   time.Duration
+	log        zerolog.Logger
+	now        func() time.Time
+
+	mu        sync.Mutex
+	current   *tls.Config
+	stamp     string
+	checkedAt time.Time
+}
+
+func newCertReloader(cfg config.APITLSConfig, nextProtos []string, log zerolog.Logger) (*certReloader, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, fmt.Errorf("tls enabled but cert_file/key_file not set")
+	}
+	if cfg.RequireClientCert && cfg.ClientCAFile == "" {
+		return nil, fmt.Errorf("tls require_client_cert=true but client_ca_file not set")
+	}
+
+	r := &certReloader{
+		cfg:        cfg,
+		nextProtos: nextProtos,
+		interval:   time.Duration(cfg.ReloadIntervalSeconds) * time.Second,
+		log:        log,
+		now:        time.Now,
+	}
+	stamp, err := r.filesStamp()
+	if err != nil {
+		return nil, err
+	}
+	current, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	r.current, r.stamp, r.checkedAt = current, stamp, r.now()
+	return r, nil
+}
+
+// TLSConfig возвращает конфигурацию сервера: каждое новое соединение получает актуальные сертификаты.
+func (r *certReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: r.nextProtos,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.config(), nil
+		},
+	}
+}
+
+// config проверяет файлы не чаще раза в interval и перечитывает их, если они изменились.
+func (r *certReloader) config() *tls.Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if r.interval <= 0 || now.Sub(r.checkedAt) < r.interval {
+		return r.current
+	}
+	r.checkedAt = now
+
+	stamp, err := r.filesStamp()
+	if err != nil {
+		r.log.Warn().Err(err).Msg("TLS files unavailable, keeping current certificate")
+		return r.current
+	}
+	if stamp == r.stamp {
+		return r.current
+	}
+	next, err := r.load()
+	if err != nil {
+		r.log.Warn().Err(err).Msg("TLS certificate reload failed, keeping current certificate")
+		return r.current
+	}
+	r.current, r.stamp = next, stamp
+	r.log.Info().Str("cert_file", r.cfg.CertFile).Msg("TLS certificate reloaded")
+	return r.current
+}
+
+// filesStamp — время изменения и размер файлов; по нему видно, что файлы пора перечитать.
+func (r *certReloader) filesStamp() (string, error) {
+	var b strings.Builder
+	for _, path := range []string{r.cfg.CertFile, r.cfg.KeyFile, r.cfg.ClientCAFile} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", fmt.Errorf("stat %s: %w", path, err)
+		}
+		fmt.Fprintf(&b, "%d/%d;", info.ModTime().UnixNano(), info.Size())
+	}
+	return b.String(), nil
+}
+
+func (r *certReloader) load() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load tls keypair: %w", err)
+	}
+
+	tlsCfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+		NextProtos:   r.nextProtos,
+	}
+
+	if r.cfg.ClientCAFile != "" {
+		caPEM, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read client_ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("failed to parse client_ca_file PEM")
+		}
+		tlsCfg.ClientCAs = pool
+		// Без require_client_cert сертификат необязателен, но присланный проверяется
+		tlsCfg.ClientAuth = tls.VerifyClientCertIfGiven
+		if r.cfg.RequireClientCert {
+			tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+
+	return tlsCfg, nil
+}
+
+// clientsByCertCN индексирует статические ключи по CN закрепленных за ними сертификатов.
+func clientsByCertCN(keys []config.APIClientKey) map[string]config.APIClientKey {
+	m := make(map[string]config.APIClientKey)
+	for _, k := range keys {
+		if k.ClientCertCN != "" {
+			m[k.ClientCertCN] = k
+		}
+	}
+	return m
+}
+
+// clientByCert находит клиента по CN проверенного сертификата: сначала среди статических ключей
+// из конфига, затем среди ключей из базы, чтобы новые привязки работали без перезапуска.
+func clientByCert(static map[string]config.APIClientKey, keys *APIKeyStore, certName string) (config.APIClientKey, bool) {
+	if certName == "" {
+		return config.APIClientKey{}, false
+	}
+	if client, ok := static[certName]; ok {
+		return client, true
+	}
+	return keys.LookupByCertCN(certName)
+}
+
+// clientCertName возвращает CN сертификата клиента, если TLS проверил его по client_ca_file.
+func clientCertName(state *tls.ConnectionState) string {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.PeerCertificates) == 0 {
+		return ""
+	}
+	return state.PeerCertificates[0].Subject.CommonName
+}
+
+// grpcClientCertName — то же для вызова gRPC.
+func grpcClientCertName(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return ""
+	}
+	return clientCertName(&info.State)
+}
+
+// checkClientCert не пускает ключ, закрепленный за сертификатом, без этого сертификата.
+func checkClientCert(client config.APIClientKey, certName string) error {
+	if client.ClientCertCN != "" && client.ClientCertCN != certName {
+		return errClientCertMismatch
+	}
+	return nil
+}
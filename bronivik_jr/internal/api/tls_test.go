@@ -0,0 +1,321 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"bronivik/internal/config"
+	"bronivik/internal/models"
+	"bronivik/internal/service"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTestCert выпускает сертификат, подписанный parent; без parent — самоподписанный CA.
+func newTestCert(t *testing.T, cn string, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func (c *testCert) tlsCertificate(t *testing.T) tls.Certificate {
+	t.Helper()
+	pair, err := tls.X509KeyPair(c.certPEM, c.keyPEM)
+	require.NoError(t, err)
+	return pair
+}
+
+func writeTestCert(t *testing.T, dir string, c *testCert) (certFile, keyFile string) {
+	t.Helper()
+	certFile, keyFile = filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	require.NoError(t, os.WriteFile(certFile, c.certPEM, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, c.keyPEM, 0o600))
+	return certFile, keyFile
+}
+
+func TestHTTPSClientCertificates(t *testing.T) {
+	db := newTestDB(t)
+	dir := t.TempDir()
+	ca := newTestCert(t, "bronivik-ca", nil)
+	certFile, keyFile := writeTestCert(t, dir, newTestCert(t, "bronivik-api", ca))
+	caFile := filepath.Join(dir, "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, ca.certPEM, 0o600))
+
+	cfg := config.APIConfig{
+		Enabled: true,
+		HTTP: config.APIHTTPConfig{
+			Enabled: true,
+			TLS:     config.APITLSConfig{Enabled: true, CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile},
+		},
+		Auth: config.APIAuthConfig{
+			Enabled: true,
+			APIKeys: []config.APIClientKey{
+				{Key: "crm-key", Extra: "crm-extra", Name: "bronivik_crm", ClientCertCN: "crm-client"},
+				{Key: "other-key", Extra: "other-extra", Name: "other"},
+			},
+		},
+	}
+	logger := zerolog.New(io.Discard)
+	server := NewHTTPServer(&cfg, db, nil, nil, &logger)
+	certs, err := newCertReloader(cfg.HTTP.TLS, []string{"h2", "http/1.1"}, zerolog.Nop())
+	require.NoError(t, err)
+	ts := httptest.NewUnstartedServer(server.server.Handler)
+	ts.TLS = certs.TLSConfig()
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	get := func(clientCert *testCert, key, extra string) (int, error) {
+		tlsCfg := &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+		if clientCert != nil {
+			// Сертификат отправляется всегда, даже если сервер ждет другой CA
+			pair := clientCert.tlsCertificate(t)
+			tlsCfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) { return &pair, nil }
+		}
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsCfg}}
+		req, _ := http.NewRequest(http.MethodGet, ts.URL+"/api/v1/items", http.NoBody)
+		if key != "" {
+			req.Header.Set("x-api-key", key)
+			req.Header.Set("x-api-extra", extra)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		return resp.StatusCode, nil
+	}
+	status := func(clientCert *testCert, key, extra string) int {
+		code, err := get(clientCert, key, extra)
+		require.NoError(t, err)
+		return code
+	}
+
+	crmCert := newTestCert(t, "crm-client", ca)
+	strangerCert := newTestCert(t, "stranger", ca)
+
+	// Сертификат, закрепленный за ключом, заменяет ключ; без сертификата клиент работает по ключу
+	assert.Equal(t, http.StatusOK, status(crmCert, "", ""))
+	assert.Equal(t, http.StatusOK, status(crmCert, "crm-key", "crm-extra"))
+	assert.Equal(t, http.StatusOK, status(nil, "other-key", "other-extra"))
+	assert.Equal(t, http.StatusUnauthorized, status(nil, "", ""))
+	assert.Equal(t, http.StatusUnauthorized, status(strangerCert, "", ""))
+
+	// Закрепленный ключ без своего сертификата не принимается
+	assert.Equal(t, http.StatusUnauthorized, status(nil, "crm-key", "crm-extra"))
+	assert.Equal(t, http.StatusUnauthorized, status(strangerCert, "crm-key", "crm-extra"))
+
+	// Сертификат не от client_ca_file отклоняется еще при установке соединения
+	_, err = get(newTestCert(t, "crm-client", newTestCert(t, "rogue-ca", nil)), "", "")
+	assert.Error(t, err)
+}
+
+func TestClientCertificatesForStoredKeys(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	ca := newTestCert(t, "bronivik-ca", nil)
+	certFile, keyFile := writeTestCert(t, dir, newTestCert(t, "bronivik-api", ca))
+	caFile := filepath.Join(dir, "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, ca.certPEM, 0o600))
+
+	cfg := config.APIConfig{
+		Enabled: true,
+		HTTP: config.APIHTTPConfig{
+			Enabled: true,
+			TLS:     config.APITLSConfig{Enabled: true, CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile},
+		},
+		Auth: config.APIAuthConfig{Enabled: true},
+	}
+	logger := zerolog.New(io.Discard)
+	server := NewHTTPServer(&cfg, db, nil, nil, &logger)
+	svc := service.NewAPIKeyService(db, time.Hour, &logger)
+	keys := NewAPIKeyStore(db, time.Minute, &logger)
+	server.SetAPIKeys(keys)
+	certs, err := newCertReloader(cfg.HTTP.TLS, []string{"h2", "http/1.1"}, zerolog.Nop())
+	require.NoError(t, err)
+	ts := httptest.NewUnstartedServer(server.server.Handler)
+	ts.TLS = certs.TLSConfig()
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	status := func(clientCert *testCert, key string) int {
+		tlsCfg := &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+		if clientCert != nil {
+			pair := clientCert.tlsCertificate(t)
+			tlsCfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) { return &pair, nil }
+		}
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsCfg}}
+		req, _ := http.NewRequest(http.MethodGet, ts.URL+"/api/v1/items", http.NoBody)
+		if key != "" {
+			req.Header.Set("x-api-key", key)
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	partnerCert := newTestCert(t, "partner-client", ca)
+	key, err := svc.CreateKey(ctx, "partner", []string{models.PermReadItems}, nil, models.APIKeyLimits{})
+	require.NoError(t, err)
+	require.NoError(t, keys.Reload(ctx))
+	assert.Equal(t, http.StatusUnauthorized, status(partnerCert, ""))
+	assert.Equal(t, http.StatusOK, status(nil, key.Key))
+
+	// Привязка из базы начинает действовать после перечитывания ключей, без перезапуска
+	require.NoError(t, svc.SetKeyClientCert(ctx, key.ID, "partner-client"))
+	require.NoError(t, keys.Reload(ctx))
+	assert.Equal(t, http.StatusOK, status(partnerCert, ""))
+	assert.Equal(t, http.StatusOK, status(partnerCert, key.Key))
+	assert.Equal(t, http.StatusUnauthorized, status(nil, key.Key))
+	assert.Equal(t, http.StatusUnauthorized, status(newTestCert(t, "stranger", ca), key.Key))
+
+	// После ротации сертификат закреплен и за новым ключом
+	next, err := svc.RotateKey(ctx, key.ID, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, keys.Reload(ctx))
+	assert.Equal(t, http.StatusOK, status(partnerCert, next.Key))
+	assert.Equal(t, http.StatusUnauthorized, status(nil, next.Key))
+
+	// Отозванный ключ больше не пускает по сертификату
+	require.NoError(t, svc.RevokeKey(ctx, key.ID))
+	require.NoError(t, svc.RevokeKey(ctx, next.ID))
+	require.NoError(t, keys.Reload(ctx))
+	assert.Equal(t, http.StatusUnauthorized, status(partnerCert, ""))
+}
+
+func TestGRPCClientCertificateForStoredKey(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	logger := zerolog.New(io.Discard)
+	svc := service.NewAPIKeyService(db, time.Hour, &logger)
+	keys := NewAPIKeyStore(db, time.Minute, &logger)
+
+	key, err := svc.CreateKey(ctx, "partner", []string{models.PermReadItems}, nil, models.APIKeyLimits{})
+	require.NoError(t, err)
+	require.NoError(t, svc.SetKeyClientCert(ctx, key.ID, "partner-client"))
+	require.NoError(t, keys.Reload(ctx))
+
+	cfg := config.APIConfig{Enabled: true, Auth: config.APIAuthConfig{Enabled: true}}
+	auth := NewAuthInterceptor(&cfg)
+	auth.SetAPIKeys(keys)
+	info := &grpc.UnaryServerInfo{FullMethod: "/bronivik.availability.v1.AvailabilityService/ListItems"}
+	handler := func(context.Context, any) (any, error) { return "ok", nil }
+	withCert := func(cn string) context.Context {
+		cert := newTestCert(t, cn, nil).cert
+		state := tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}, PeerCertificates: []*x509.Certificate{cert}}
+		return peer.NewContext(metadata.NewIncomingContext(ctx, metadata.MD{}), &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+	}
+
+	_, err = auth.Unary()(withCert("partner-client"), nil, info, handler)
+	require.NoError(t, err)
+	_, err = auth.Unary()(withCert("stranger"), nil, info, handler)
+	assert.Equal(t, codes.Unauthenticated, grpcstatus.Code(err))
+}
+
+func TestCertReloaderPicksUpNewCertificate(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, "bronivik-ca", nil)
+	first := newTestCert(t, "bronivik-api", ca)
+	certFile, keyFile := writeTestCert(t, dir, first)
+
+	r, err := newCertReloader(config.APITLSConfig{CertFile: certFile, KeyFile: keyFile, ReloadIntervalSeconds: 60}, nil, zerolog.Nop())
+	require.NoError(t, err)
+	now := time.Now()
+	r.now = func() time.Time { return now }
+	served := func() []byte {
+		cfg, err := r.TLSConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+		require.NoError(t, err)
+		return cfg.Certificates[0].Certificate[0]
+	}
+	assert.Equal(t, first.cert.Raw, served())
+
+	second := newTestCert(t, "bronivik-api", ca)
+	writeTestCert(t, dir, second)
+	touched := now.Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, touched, touched))
+
+	// Файлы проверяются не чаще раза в reload_interval_seconds
+	assert.Equal(t, first.cert.Raw, served())
+	now = now.Add(time.Minute)
+	assert.Equal(t, second.cert.Raw, served())
+
+	// Битый ключ не ломает TLS: остается прежний сертификат
+	require.NoError(t, os.WriteFile(keyFile, []byte("broken"), 0o600))
+	now = now.Add(time.Minute)
+	assert.Equal(t, second.cert.Raw, served())
+}
+
+func TestHTTPServerStartTLSRequiresFiles(t *testing.T) {
+	logger := zerolog.New(io.Discard)
+	cfg := config.APIConfig{HTTP: config.APIHTTPConfig{Enabled: true, TLS: config.APITLSConfig{Enabled: true}}}
+	server := NewHTTPServer(&cfg, newTestDB(t), nil, nil, &logger)
+	assert.ErrorContains(t, server.Start(), "cert_file/key_file")
+}
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"bronivik/internal/domain"
+	"bronivik/internal/metrics"
+	"bronivik/internal/models"
+)
+
+const (
+	webhooksPathPrefix = "/api/v1/webhooks"
+	permAdminWebhooks  = "admin:webhooks"
+)
+
+// CreateWebhookRequest is the request body for POST /api/v1/webhooks.
+type CreateWebhookRequest struct {
+	Name       string   `json:"name,omitempty"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types,omitempty"` // empty or ["*"] subscribes to all events
+}
+
+// WebhookDeliveriesResponse is the response for GET /api/v1/webhooks/deliveries.
+type WebhookDeliveriesResponse struct {
+	Deliveries []*models.WebhookDelivery `json:"deliveries"`
+}
+
+// RedeliverResponse is the response for redelivery endpoints.
+type RedeliverResponse struct {
+	Requeued int64 `json:"requeued"`
+}
+
+// SetWebhookService подключает сервис вебхуков.
+func (s *HTTPServer) SetWebhookService(webhookService domain.WebhookService) {
+	s.webhookService = webhookService
+}
+
+// handleWebhooks routes webhook endpoints:
+//
+//	GET    /api/v1/webhooks
+//	POST   /api/v1/webhooks
+//	DELETE /api/v1/webhooks/{id}
+//	GET    /api/v1/webhooks/deliveries?status=dead&limit=50
+//	POST   /api/v1/webhooks/deliveries/redeliver
+//	POST   /api/v1/webhooks/deliveries/{id}/redeliver
+func (s *HTTPServer) handleWebhooks(w http.ResponseWriter, r *http.Request) {
+	metrics.IncHTTP("webhooks")
+	if s.webhookService == nil {
+		writeError(w, http.StatusServiceUnavailable, "webhook service is not configured")
+		return
+	}
+
+	parts := splitPath(strings.TrimPrefix(r.URL.Path, webhooksPathPrefix))
+	switch {
+	case len(parts) == 0:
+		switch r.Method {
+		case http.MethodGet:
+			s.listWebhooks(w, r)
+		case http.MethodPost:
+			s.createWebhook(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	case parts[0] == "deliveries":
+		s.handleWebhookDeliveries(w, r, parts[1:])
+	case len(parts) == 1:
+		if r.Method != http.MethodDelete {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil || id <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid webhook id")
+			return
+		}
+		if err := s.webhookService.DeactivateSubscription(r.Context(), id); err != nil {
+			s.writeWebhookError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *HTTPServer) handleWebhookDeliveries(w http.ResponseWriter, r *http.Request, parts []string) {
+	switch {
+	case len(parts) == 0:
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		deliveries, err := s.webhookService.ListDeliveries(r.Context(), r.URL.Query().Get("status"), limit)
+		if err != nil {
+			s.writeWebhookError(w, err)
+			return
+		}
+		if deliveries == nil {
+			deliveries = []*models.WebhookDelivery{}
+		}
+		writeJSON(w, http.StatusOK, WebhookDeliveriesResponse{Deliveries: deliveries})
+	case len(parts) == 1 && parts[0] == "redeliver":
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		n, err := s.webhookService.RedeliverDead(r.Context())
+		if err != nil {
+			s.writeWebhookError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, RedeliverResponse{Requeued: n})
+	case len(parts) == 2 && parts[1] == "redeliver":
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil || id <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid delivery id")
+			return
+		}
+		if err := s.webhookService.Redeliver(r.Context(), id); err != nil {
+			s.writeWebhookError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, RedeliverResponse{Requeued: 1})
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *HTTPServer) listWebhooks(w http.ResponseWriter, r *http.Request) {
+	subs, err := s.webhookService.ListSubscriptions(r.Context())
+	if err != nil {
+		s.writeWebhookError(w, err)
+		return
+	}
+	if subs == nil {
+		subs = []*models.WebhookSubscription{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"webhooks": subs})
+}
+
+func (s *HTTPServer) createWebhook(w http.ResponseWriter, r *http.Request) {
+	var req CreateWebhookRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	sub, err := s.webhookService.CreateSubscription(r.Context(), req.Name, req.URL, req.EventTypes)
+	if err != nil {
+		s.writeWebhookError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, sub)
+}
+
+func (s *HTTPServer) writeWebhookError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		writeError(w, http.StatusNotFound, "not found")
+	case errors.Is(err, models.ErrInvalidWebhookURL),
+		errors.Is(err, models.ErrInvalidWebhookEventType),
+		errors.Is(err, models.ErrInvalidWebhookStatus):
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		s.log.Error().Err(err).Msg("webhook API error")
+		writeError(w, http.StatusInternalServerError, "internal error")
+	}
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"bronivik/internal/config"
+	"bronivik/internal/events"
+	"bronivik/internal/models"
+	"bronivik/internal/service"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhooksAPI(t *testing.T) {
+	db := newTestDB(t)
+	logger := zerolog.New(io.Discard)
+	server := newTestHTTPServer(db)
+	server.SetWebhookService(service.NewWebhookService(db, &logger))
+	ts := httptest.NewServer(server.server.Handler)
+	t.Cleanup(ts.Close)
+
+	resp, err := http.Post(ts.URL+"/api/v1/webhooks", "application/json",
+		strings.NewReader(`{"url": "https://crm.example.com/hooks", "event_types": ["booking_unknown"]}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Post(ts.URL+"/api/v1/webhooks", "application/json",
+		strings.NewReader(`{"name": "crm", "url": "https://crm.example.com/hooks", "event_types": ["booking_canceled"]}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var sub models.WebhookSubscription
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&sub))
+	assert.NotEmpty(t, sub.Secret)
+	assert.Equal(t, []string{events.EventBookingCanceled}, sub.EventTypes)
+
+	// Доставка, исчерпавшая попытки
+	ctx := context.Background()
+	_, err = db.EnqueueWebhookDeliveries(ctx, &events.Event{ID: 1, Type: events.EventBookingCanceled, Payload: []byte(`{}`)})
+	require.NoError(t, err)
+	deliveries, err := db.GetWebhookDeliveries(ctx, models.WebhookDeliveryPending, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.NoError(t, db.MarkWebhookFailed(ctx, deliveries[0].ID, http.StatusBadGateway, "unexpected status 502", nil))
+
+	deadResp, err := http.Get(ts.URL + "/api/v1/webhooks/deliveries?status=dead")
+	require.NoError(t, err)
+	defer deadResp.Body.Close()
+	require.Equal(t, http.StatusOK, deadResp.StatusCode)
+	var dead WebhookDeliveriesResponse
+	require.NoError(t, json.NewDecoder(deadResp.Body).Decode(&dead))
+	require.Len(t, dead.Deliveries, 1)
+	assert.Equal(t, http.StatusBadGateway, dead.Deliveries[0].ResponseCode)
+
+	redeliverURL := fmt.Sprintf("%s/api/v1/webhooks/deliveries/%d/redeliver", ts.URL, dead.Deliveries[0].ID)
+	redeliverResp, err := http.Post(redeliverURL, "application/json", http.NoBody)
+	require.NoError(t, err)
+	redeliverResp.Body.Close()
+	assert.Equal(t, http.StatusOK, redeliverResp.StatusCode)
+
+	// Повторно отправить можно только dead-доставку
+	redeliverResp, err = http.Post(redeliverURL, "application/json", http.NoBody)
+	require.NoError(t, err)
+	redeliverResp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, redeliverResp.StatusCode)
+
+	req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/webhooks/%d", ts.URL, sub.ID), http.NoBody)
+	delResp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	delResp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, delResp.StatusCode)
+
+	listResp, err := http.Get(ts.URL + "/api/v1/webhooks")
+	require.NoError(t, err)
+	defer listResp.Body.Close()
+	var list struct {
+		Webhooks []*models.WebhookSubscription `json:"webhooks"`
+	}
+	require.NoError(t, json.NewDecoder(listResp.Body).Decode(&list))
+	require.Len(t, list.Webhooks, 1)
+	assert.False(t, list.Webhooks[0].IsActive)
+	assert.Empty(t, list.Webhooks[0].Secret)
+}
+
+func TestWebhooksRequireAdminPermission(t *testing.T) {
+	auth := NewHTTPAuth(&config.APIConfig{Auth: config.APIAuthConfig{Enabled: true}})
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/webhooks", http.NoBody)
+
+	// Ключ без списка разрешений не получает доступ к административным эндпоинтам
+	assert.ErrorIs(t, auth.checkPermissions(config.APIClientKey{Key: "k"}, req), errPermissionDenied)
+	assert.NoError(t, auth.checkPermissions(config.APIClientKey{Key: "k", Permissions: []string{permAdminWebhooks}}, req))
+
+	itemsReq := httptest.NewRequest(http.MethodGet, "/api/v1/items", http.NoBody)
+	assert.NoError(t, auth.checkPermissions(config.APIClientKey{Key: "k"}, itemsReq))
+}
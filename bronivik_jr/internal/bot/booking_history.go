@@ -0,0 +1,84 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"bronivik/internal/i18n"
+	"bronivik/internal/models"
+)
+
+// maxCardHistoryEntries ограничивает историю в карточке заявки последними записями
+const maxCardHistoryEntries = 10
+
+// formatBookingHistory форматирует историю изменений заявки для карточки менеджера
+func formatBookingHistory(l *i18n.Localizer, history []*models.BookingHistoryEntry) string {
+	var sb strings.Builder
+	sb.WriteString(l.T("history_title"))
+
+	if len(history) > maxCardHistoryEntries {
+		sb.WriteString("\n" + l.T("history_earlier", len(history)-maxCardHistoryEntries))
+		history = history[len(history)-maxCardHistoryEntries:]
+	}
+
+	for _, e := range history {
+		sb.WriteString(fmt.Sprintf("\n• %s — %s (%s)",
+			l.DateTime(e.CreatedAt), formatHistoryChange(l, e), formatHistoryActor(l, e)))
+		if e.Reason != "" {
+			sb.WriteString("\n  " + l.T("history_reason", e.Reason))
+		}
+	}
+	return sb.String()
+}
+
+func formatHistoryChange(l *i18n.Localizer, e *models.BookingHistoryEntry) string {
+	switch e.Field {
+	case models.HistoryFieldStatus:
+		if e.OldValue == "" {
+			return l.T("history_created", bookingStatusLabel(l, e.NewValue))
+		}
+		return l.T("history_status", bookingStatusLabel(l, e.OldValue), bookingStatusLabel(l, e.NewValue))
+	case models.HistoryFieldItem:
+		return l.T("history_item", e.OldValue, e.NewValue)
+	case models.HistoryFieldDates:
+		return l.T("history_dates", formatHistoryDates(l, e.OldValue), formatHistoryDates(l, e.NewValue))
+	default:
+		return fmt.Sprintf("%s: %s → %s", e.Field, e.OldValue, e.NewValue)
+	}
+}
+
+// formatHistoryDates переводит "2006-01-02..2006-01-05" в формат карточки
+func formatHistoryDates(l *i18n.Localizer, value string) string {
+	parts := strings.Split(value, "..")
+	for i, p := range parts {
+		if d, err := time.Parse("2006-01-02", p); err == nil {
+			parts[i] = l.Date(d)
+		}
+	}
+	return strings.Join(parts, " — ")
+}
+
+func formatHistoryActor(l *i18n.Localizer, e *models.BookingHistoryEntry) string {
+	switch e.ActorType {
+	case models.ActorManager:
+		if e.ActorID != 0 {
+			return l.T("history_actor_manager_id", e.ActorID)
+		}
+		return l.T("history_actor_manager")
+	case models.ActorUser:
+		return l.T("history_actor_user", e.ActorID)
+	case models.ActorAPI:
+		if e.ActorName != "" {
+			return "API: " + e.ActorName
+		}
+		return "API"
+	case models.ActorSystem:
+		if e.ActorName != "" {
+			return l.T("history_actor_system_name", e.ActorName)
+		}
+		return l.T("history_actor_system")
+	default:
+		return string(e.ActorType)
+	}
+}
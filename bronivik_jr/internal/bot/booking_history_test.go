@@ -0,0 +1,40 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"bronivik/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatBookingHistory(t *testing.T) {
+	at := time.Date(2025, 12, 1, 10, 30, 0, 0, time.UTC)
+	history := []*models.BookingHistoryEntry{
+		{Field: models.HistoryFieldStatus, NewValue: models.StatusPending, ActorType: models.ActorUser, ActorID: 456, CreatedAt: at},
+		{
+			Field: models.HistoryFieldStatus, OldValue: models.StatusPending, NewValue: models.StatusConfirmed,
+			ActorType: models.ActorManager, ActorID: 123, Reason: "по телефону", CreatedAt: at,
+		},
+		{Field: models.HistoryFieldItem, OldValue: "Item 1", NewValue: "Item 2", ActorType: models.ActorAPI, ActorName: "crm", CreatedAt: at},
+		{Field: models.HistoryFieldDates, OldValue: "2025-12-01", NewValue: "2025-12-03..2025-12-05", ActorType: models.ActorManager, CreatedAt: at},
+	}
+
+	text := formatBookingHistory(messages.Localizer("ru"), history)
+	assert.Contains(t, text, "01.12.2025 10:30 — создана: ⏳ Ожидает подтверждения (клиент 456)")
+	assert.Contains(t, text, "статус: ⏳ Ожидает подтверждения → ✅ Подтверждена (менеджер 123)\n  Причина: по телефону")
+	assert.Contains(t, text, "аппарат: Item 1 → Item 2 (API: crm)")
+	assert.Contains(t, text, "даты: 01.12.2025 → 03.12.2025 — 05.12.2025 (менеджер)")
+}
+
+func TestFormatBookingHistory_Truncates(t *testing.T) {
+	history := make([]*models.BookingHistoryEntry, 0, maxCardHistoryEntries+3)
+	for i := 0; i < maxCardHistoryEntries+3; i++ {
+		history = append(history, &models.BookingHistoryEntry{
+			Field: models.HistoryFieldStatus, OldValue: models.StatusPending, NewValue: models.StatusChanged,
+			ActorType: models.ActorManager,
+		})
+	}
+	assert.Contains(t, formatBookingHistory(messages.Localizer("ru"), history), "… ранее: 3 изм.")
+}
@@ -8,30 +8,36 @@ import (
 	"bronivik/internal/config"
 	"bronivik/internal/domain"
 	"bronivik/internal/events"
+	"bronivik/internal/i18n"
+	"bronivik/internal/models"
+	"bronivik/internal/tgwebhook"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 )
 
+// Ключи надписей кнопок в каталогах сообщений (locales/*.yaml).
 const (
-	btnCancel               = "❌ Отмена"
-	btnBack                 = "⬅️ Назад"
-	btnCreateBooking        = "📋 СОЗДАТЬ ЗАЯВКУ"
-	btnMyBookings           = "📊 Мои заявки"
-	btnManagerContacts      = "📞 Контакты менеджеров"
-	btnAvailableItems       = "💼 Ассортимент"
-	btnViewSchedule         = "📅 Посмотреть расписание"
-	btnMonthSchedule        = "📅 30 дней"
-	btnPickDate             = "🗓 Выбрать дату"
-	btnBackToItems          = "⬅️ Назад к выбору аппарата"
-	btnCreateForItem        = "📋 СОЗДАТЬ ЗАЯВКУ НА ЭТОТ АППАРАТ"
-	btnAllBookings          = "👨‍💼 Все заявки"
-	btnCreateBookingManager = "➕ Создать заявку (Менеджер)"
-	btnSyncBookings         = "🔄 Синхронизировать бронирования (Google Sheets)"
-	btnSyncSchedule         = "📅 Синхронизировать расписание (Google Sheets)"
-	btnConfirmCreate        = "✅ Подтвердить создание"
+	btnCancel               = "btn_cancel"
+	btnBack                 = "btn_back"
+	btnCreateBooking        = "btn_create_booking"
+	btnMyBookings           = "btn_my_bookings"
+	btnManagerContacts      = "btn_manager_contacts"
+	btnAvailableItems       = "btn_available_items"
+	btnViewSchedule         = "btn_view_schedule"
+	btnMonthSchedule        = "btn_month_schedule"
+	btnPickDate             = "btn_pick_date"
+	btnBackToItems          = "btn_back_to_items"
+	btnCreateForItem        = "btn_create_for_item"
+	btnAllBookings          = "btn_all_bookings"
+	btnCreateBookingManager = "btn_create_booking_manager"
+	btnSyncBookings         = "btn_sync_bookings"
+	btnSyncSchedule         = "btn_sync_schedule"
+	btnConfirmCreate        = "btn_confirm_create"
+)
 
+const (
 	statusSuccess = "✅"
 	statusPending = "⏳"
 	statusError   = "❌"
@@ -40,17 +46,20 @@ const (
 
 // Bot represents the Telegram bot instance and its dependencies.
 type Bot struct {
-	tgService      domain.TelegramService
-	config         *config.Config
-	stateService   domain.StateManager
-	sheetsService  domain.SheetsWriter
-	sheetsWorker   domain.SyncWorker
-	eventBus       domain.EventPublisher
-	bookingService domain.BookingService
-	userService    domain.UserService
-	itemService    domain.ItemService
-	metrics        *Metrics
-	logger         *zerolog.Logger
+	tgService       domain.TelegramService
+	config          *config.Config
+	stateService    domain.StateManager
+	sheetsService   domain.SheetsWriter
+	sheetsWorker    domain.SyncWorker
+	eventBus        domain.EventPublisher
+	bookingService  domain.BookingService
+	userService     domain.UserService
+	itemService     domain.ItemService
+	waitlistService domain.WaitlistService
+	webhookService  domain.WebhookService
+	apiKeyService   domain.APIKeyService
+	metrics         *Metrics
+	logger          *zerolog.Logger
 }
 
 // NewBot creates a new instance of the Telegram bot.
@@ -93,16 +102,43 @@ func NewBot(
 
 // Start begins the bot's update polling loop.
 func (b *Bot) Start(ctx context.Context) {
+	// Пока вебхук зарегистрирован, getUpdates отвечает 409 Conflict; очередь обновлений сохраняем
+	if _, err := b.tgService.Request(tgbotapi.DeleteWebhookConfig{DropPendingUpdates: false}); err != nil {
+		b.logger.Error().Err(err).Msg("Failed to delete Telegram webhook before polling")
+	}
+
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
 
-	updates := b.tgService.GetUpdatesChan(u)
+	b.run(ctx, b.tgService.GetUpdatesChan(u))
+}
+
+// StartWebhook receives updates through the Telegram webhook instead of polling.
+func (b *Bot) StartWebhook(ctx context.Context, receiver *tgwebhook.Receiver) error {
+	updates, err := receiver.Start(ctx)
+	if err != nil {
+		return err
+	}
+	b.run(ctx, updates)
+	return nil
+}
 
+// run processes updates from either source until ctx is canceled.
+func (b *Bot) run(ctx context.Context, updates tgbotapi.UpdatesChannel) {
 	b.logger.Info().Str("username", b.tgService.GetSelf().UserName).Msg("Authorized on account")
 
 	// Start metrics updater
 	go b.startMetricsUpdater(ctx)
 
+	// Обработчики работают в контексте без отмены, чтобы при остановке
+	// дообработать уже принятые обновления; по таймауту он отменяется.
+	workCtx, cancelWork := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelWork()
+
+	pool := newUpdatePool(b.config.Bot.Workers, b.config.Bot.QueueSize, b.processUpdate, b.metrics)
+	pool.start(workCtx)
+	defer b.drainUpdates(pool, cancelWork)
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -112,11 +148,25 @@ func (b *Bot) Start(ctx context.Context) {
 			if !ok {
 				return
 			}
-			b.processUpdate(ctx, &update)
+			pool.submit(ctx, update)
 		}
 	}
 }
 
+// drainUpdates waits for queued and in-flight updates before shutdown.
+func (b *Bot) drainUpdates(pool *updatePool, cancel context.CancelFunc) {
+	timeout := time.Duration(b.config.Bot.DrainTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = models.UpdateDrainTimeout * time.Second
+	}
+	if pool.drain(timeout) {
+		b.logger.Info().Msg("In-flight updates drained")
+		return
+	}
+	b.logger.Warn().Dur("timeout", timeout).Msg("Update drain timed out, canceling in-flight updates")
+	cancel()
+}
+
 // processUpdate handles a single Telegram update.
 func (b *Bot) processUpdate(ctx context.Context, update *tgbotapi.Update) {
 	start := time.Now()
@@ -146,6 +196,15 @@ func (b *Bot) processUpdate(ctx context.Context, update *tgbotapi.Update) {
 			return
 		}
 
+		// Язык сообщений: выбранный через /language или язык клиента Telegram
+		var telegramLang string
+		if update.Message != nil {
+			telegramLang = update.Message.From.LanguageCode
+		} else {
+			telegramLang = update.CallbackQuery.From.LanguageCode
+		}
+		updateCtx = i18n.WithLocalizer(updateCtx, messages.Localizer(b.userLanguage(updateCtx, userID, telegramLang)))
+
 		// Track activity
 		b.trackActivity(userID)
 
@@ -157,9 +216,9 @@ func (b *Bot) processUpdate(ctx context.Context, update *tgbotapi.Update) {
 			} else if !allowed {
 				b.logger.Warn().Int64("user_id", userID).Msg("Rate limit exceeded")
 				if update.Message != nil {
-					b.sendMessage(update.Message.Chat.ID, "⚠️ Вы отправляете сообщения слишком часто. Пожалуйста, подождите немного.")
+					b.sendMessage(update.Message.Chat.ID, b.loc(updateCtx).T("rate_limited"))
 				} else if update.CallbackQuery != nil {
-					callbackConfig := tgbotapi.NewCallback(update.CallbackQuery.ID, "⚠️ Слишком много запросов. Подождите немного.")
+					callbackConfig := tgbotapi.NewCallback(update.CallbackQuery.ID, b.loc(updateCtx).T("rate_limited_alert"))
 					callbackConfig.ShowAlert = true
 					_, _ = b.tgService.Request(callbackConfig)
 				}
@@ -167,6 +226,13 @@ func (b *Bot) processUpdate(ctx context.Context, update *tgbotapi.Update) {
 			}
 		}
 
+		// Инициатор изменений заявок для истории и правил смены статусов
+		actor := models.Actor{Type: models.ActorUser, ID: userID}
+		if b.isManager(userID) {
+			actor.Type = models.ActorManager
+		}
+		updateCtx = models.WithActor(updateCtx, actor)
+
 		if update.CallbackQuery != nil {
 			b.handleCallbackQuery(updateCtx, update)
 			return
@@ -90,7 +90,7 @@ func TestHandleManagerCallback(t *testing.T) {
 		{"Complete", "complete_1", models.StatusCompleted},
 		{"Reopen", "reopen_1", models.StatusPending},
 		{"Details", "details_1", ""},
-		{"Reschedule", "reschedule_1", models.StatusChanged},
+		{"Reschedule", "reschedule_1", ""},
 		{"Change Item", "change_item_1", ""},
 		{"Edit Comment", "edit_comment_1", ""},
 	}
@@ -195,7 +195,7 @@ func TestUtils_More(t *testing.T) {
 				},
 			},
 		}
-		b.editManagerItemsPage(&update, 0)
+		b.editManagerItemsPage(context.Background(), &update, 0)
 		// Should not panic
 	})
 }
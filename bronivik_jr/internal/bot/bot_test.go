@@ -2,7 +2,9 @@ package bot
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"sync"
@@ -231,6 +233,7 @@ type mockUserService struct {
 	saveError           error
 	updateActivityError error
 	updatePhoneError    error
+	languages           map[int64]string
 	mu                  sync.RWMutex
 }
 
@@ -266,6 +269,91 @@ func (m *mockUserService) UpdateUserPhone(ctx context.Context, telegramID int64,
 	return nil
 }
 
+func (m *mockUserService) GetUserLanguage(ctx context.Context, telegramID int64) (chosen, telegram string, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if u, ok := m.users[telegramID]; ok {
+		telegram = u.LanguageCode
+	}
+	return m.languages[telegramID], telegram, nil
+}
+
+func (m *mockUserService) SetUserLanguage(ctx context.Context, telegramID int64, lang string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.languages == nil {
+		m.languages = make(map[int64]string)
+	}
+	m.languages[telegramID] = lang
+	return nil
+}
+
+func (m *mockUserService) GetUserByTelegramID(ctx context.Context, telegramID int64) (*models.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if u, ok := m.users[telegramID]; ok {
+		return u, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *mockUserService) GiveConsent(ctx context.Context, telegramID int64, version string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u, ok := m.users[telegramID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	u.ConsentGiven = true
+	u.ConsentGivenAt = sql.NullTime{Time: time.Now(), Valid: true}
+	u.ConsentVersion = version
+	u.ConsentRevoked = false
+	u.ConsentRevokedAt = sql.NullTime{}
+	return nil
+}
+
+func (m *mockUserService) RevokeConsent(ctx context.Context, telegramID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u, ok := m.users[telegramID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	u.ConsentRevoked = true
+	u.ConsentRevokedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	u.Phone = ""
+	return nil
+}
+
+func (m *mockUserService) ExportUserData(ctx context.Context, telegramID int64) (*models.UserDataExport, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	export := &models.UserDataExport{Service: "bronivik_jr", TelegramID: telegramID, GeneratedAt: time.Now()}
+	section := models.UserDataSection{Name: "users", Columns: []string{"telegram_id", "first_name", "phone"}}
+	if u, ok := m.users[telegramID]; ok {
+		section.Rows = append(section.Rows, map[string]interface{}{
+			"telegram_id": u.TelegramID, "first_name": u.FirstName, "phone": u.Phone,
+		})
+	}
+	export.Sections = append(export.Sections, section)
+	return export, nil
+}
+
+func (m *mockUserService) EraseUserData(ctx context.Context, telegramID int64) (*models.ErasureResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.users[telegramID]; !ok {
+		return nil, sql.ErrNoRows
+	}
+	delete(m.users, telegramID)
+	return &models.ErasureResult{
+		TelegramID:  telegramID,
+		PseudonymID: -1,
+		Pseudonym:   models.ErasedUserPseudonym(1),
+		DeletedRows: 1,
+	}, nil
+}
+
 func (m *mockUserService) IsManager(userID int64) bool {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -467,6 +555,7 @@ type mockBookingService struct {
 	domain.BookingService
 	available bool
 	bookings  map[int64]*models.Booking
+	history   map[int64][]*models.BookingHistoryEntry
 	mu        sync.RWMutex
 }
 
@@ -570,16 +659,42 @@ func (m *mockBookingService) ChangeBookingItem(ctx context.Context, bookingID, v
 	return nil
 }
 
-func (m *mockBookingService) RescheduleBooking(ctx context.Context, bookingID, managerID int64) error {
+func (m *mockBookingService) RescheduleBooking(
+	ctx context.Context,
+	bookingID, version int64,
+	date time.Time,
+	endTime *time.Time,
+	managerID int64,
+) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	if b, ok := m.bookings[bookingID]; ok {
+		b.Date = date
+		b.EndTime = endTime
 		b.Status = models.StatusChanged
 		return nil
 	}
 	return nil
 }
 
+func (m *mockBookingService) GetBookingHistory(ctx context.Context, bookingID int64) ([]*models.BookingHistoryEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.history[bookingID], nil
+}
+
+func (m *mockBookingService) ListBookings(ctx context.Context, filter *models.BookingFilter) ([]*models.Booking, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var result []*models.Booking
+	for _, b := range m.bookings {
+		if b.ID > filter.AfterID {
+			result = append(result, b)
+		}
+	}
+	return result, nil
+}
+
 func (m *mockBookingService) GetBooking(ctx context.Context, id int64) (*models.Booking, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -656,6 +771,7 @@ func (m *mockBookingService) setBookings(bookings map[int64]*models.Booking) {
 
 type mockSheetsWriter struct {
 	domain.SheetsWriter
+	deletedUsers []int64
 }
 
 func (m *mockSheetsWriter) AppendBooking(ctx context.Context, booking *models.Booking) error {
@@ -691,11 +807,16 @@ func (m *mockSheetsWriter) UpdateBookingStatus(ctx context.Context, bookingID in
 	return nil
 }
 
+func (m *mockSheetsWriter) DeleteUserRows(ctx context.Context, telegramID int64) error {
+	m.deletedUsers = append(m.deletedUsers, telegramID)
+	return nil
+}
+
 type mockSyncWorker struct {
 	domain.SyncWorker
 }
 
-func (m *mockSyncWorker) EnqueueTask(ctx context.Context, taskType string, bookingID int64, booking *models.Booking, status string) error {
+func (m *mockSyncWorker) EnqueueTask(ctx context.Context, taskType string, bookingID int64, status string) error {
 	return nil
 }
 
@@ -762,6 +883,38 @@ func TestBotStart(t *testing.T) {
 	}
 }
 
+// pollingTelegramService запоминает порядок вызовов Bot API при запуске опроса.
+type pollingTelegramService struct {
+	*mockTelegramService
+	calls []string
+}
+
+func (m *pollingTelegramService) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	if webhook, ok := c.(tgbotapi.DeleteWebhookConfig); ok && !webhook.DropPendingUpdates {
+		m.calls = append(m.calls, "deleteWebhook")
+	}
+	return m.mockTelegramService.Request(c)
+}
+
+func (m *pollingTelegramService) GetUpdatesChan(config tgbotapi.UpdateConfig) tgbotapi.UpdatesChannel {
+	m.calls = append(m.calls, "getUpdates")
+	return m.mockTelegramService.GetUpdatesChan(config)
+}
+
+func TestBotStartDeletesWebhookBeforePolling(t *testing.T) {
+	tg := &pollingTelegramService{mockTelegramService: &mockTelegramService{updatesChan: make(chan tgbotapi.Update)}}
+	logger := zerolog.New(io.Discard)
+	b, err := NewBot(tg, &config.Config{}, &mockStateManager{}, &mockSheetsWriter{}, &mockSyncWorker{},
+		&mockEventPublisher{}, &mockBookingService{}, &mockUserService{}, &mockItemService{}, nil, &logger)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	b.Start(ctx)
+
+	assert.Equal(t, []string{"deleteWebhook", "getUpdates"}, tg.calls)
+}
+
 func TestHandleSelectItem(t *testing.T) {
 	tg := &mockTelegramService{updatesChan: make(chan tgbotapi.Update, 1)}
 	state := &mockStateManager{states: make(map[int64]*models.UserState)}
@@ -1083,11 +1236,22 @@ func TestBookingFlow(t *testing.T) {
 		Text: "Test User",
 	}})
 	state, _ = mocks.state.GetUserState(ctx, userID)
+	if state.CurrentStep != models.StateConsent {
+		t.Fatalf("expected state %s, got %s", models.StateConsent, state.CurrentStep)
+	}
+
+	// 5. Give consent
+	b.handleMessage(ctx, &tgbotapi.Update{Message: &tgbotapi.Message{
+		From: &tgbotapi.User{ID: userID},
+		Chat: &tgbotapi.Chat{ID: userID},
+		Text: "✅ Согласен",
+	}})
+	state, _ = mocks.state.GetUserState(ctx, userID)
 	if state.CurrentStep != models.StatePhoneNumber {
 		t.Fatalf("expected state %s, got %s", models.StatePhoneNumber, state.CurrentStep)
 	}
 
-	// 5. Enter phone
+	// 6. Enter phone
 	mocks.booking.On("CheckAvailability", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
 	b.handleMessage(ctx, &tgbotapi.Update{Message: &tgbotapi.Message{
 		From: &tgbotapi.User{ID: userID},
@@ -1109,13 +1273,13 @@ func TestBookingFlow(t *testing.T) {
 		t.Errorf("expected status %s, got %s", models.StatusPending, booking.Status)
 	}
 
-	// 6. Manager confirms
+	// 7. Manager confirms
 	b.confirmBooking(ctx, booking, 456)
 	if booking.Status != models.StatusConfirmed {
 		t.Errorf("expected status %s, got %s", models.StatusConfirmed, booking.Status)
 	}
 
-	// 7. Manager completes
+	// 8. Manager completes
 	b.completeBooking(ctx, booking, 456)
 	if booking.Status != models.StatusCompleted {
 		t.Errorf("expected status %s, got %s", models.StatusCompleted, booking.Status)
@@ -1390,14 +1554,22 @@ func TestGetErrorMessage(t *testing.T) {
 		{nil, ""},
 		{database.ErrNotAvailable, "⚠️ Извините, этот аппарат уже забронирован на выбранную дату. " +
 			"Пожалуйста, выберите другое время или аппарат."},
+		{&database.UnavailableDatesError{Dates: []time.Time{
+			time.Date(2025, 12, 3, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 4, 0, 0, 0, 0, time.UTC),
+		}}, "⚠️ Извините, этот аппарат уже забронирован на даты: 03.12.2025, 04.12.2025. " +
+			"Пожалуйста, выберите другой период или аппарат."},
 		{database.ErrPastDate, "⚠️ Нельзя создавать бронирование на прошедшую дату."},
 		{database.ErrDateTooFar, "⚠️ Вы не можете бронировать так далеко в будущем. Пожалуйста, выберите более раннюю дату."},
 		{database.ErrConcurrentModification, "⚠️ Произошла ошибка при сохранении (конфликт версий). Пожалуйста, попробуйте еще раз."},
+		{fmt.Errorf("confirm: %w", &models.TransitionError{
+			Actor: models.ActorManager, From: models.StatusCanceled, To: models.StatusConfirmed,
+		}), "⚠️ Нельзя перевести заявку из статуса «❌ Отменена» в «✅ Подтверждена»."},
+		{&models.UnknownStatusError{Status: "approved"}, "⚠️ У заявки нестандартный статус «approved». Обратитесь к администратору."},
 		{errors.New("unknown"), "❌ Произошла ошибка при обработке вашего запроса. Пожалуйста, попробуйте позже или обратитесь к менеджеру."},
 	}
 
 	for _, tt := range tests {
-		if got := b.getErrorMessage(tt.err); got != tt.expected {
+		if got := b.getErrorMessage(context.Background(), tt.err); got != tt.expected {
 			t.Errorf("getErrorMessage(%v) = %v, want %v", tt.err, got, tt.expected)
 		}
 	}
@@ -1883,9 +2055,15 @@ func TestManagerBookingActions(t *testing.T) {
 	b.handleChangeItem(ctx, &callbackUpdate)
 	assert.Equal(t, models.StatusChanged, booking.Status)
 
-	// Test rescheduleBooking
-	b.rescheduleBooking(ctx, booking, 123)
+	// Test reschedule flow
+	b.startRescheduleBooking(ctx, booking, 123, 123)
+	newDate := time.Now().AddDate(0, 0, 3).Truncate(24 * time.Hour)
+	b.handleManagerRescheduleDates(ctx, &tgbotapi.Update{Message: &tgbotapi.Message{
+		From: &tgbotapi.User{ID: 123},
+		Chat: &tgbotapi.Chat{ID: 123},
+	}}, newDate.Format("02.01.2006"), b.getUserState(ctx, 123))
 	assert.Equal(t, models.StatusChanged, booking.Status)
+	assert.Equal(t, newDate.Format("2006-01-02"), booking.Date.Format("2006-01-02"))
 
 	// Test handleCallButton
 	callbackUpdate.CallbackQuery.Data = "call_booking:1"
@@ -1952,7 +2130,7 @@ func TestManagerMoreActions(t *testing.T) {
 	}, time.Now().AddDate(0, 0, 40).Format("02.01.2006"), state)
 
 	// Test notifyManagers
-	b.notifyManagers(&models.Booking{ID: 1, ItemName: "Item 1", Date: time.Now()})
+	b.notifyManagers(context.Background(), &models.Booking{ID: 1, ItemName: "Item 1", Date: time.Now()})
 	assert.True(t, len(mocks.tg.sentMessages) > 0)
 }
 
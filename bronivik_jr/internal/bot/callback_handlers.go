@@ -2,7 +2,6 @@ package bot
 
 import (
 	"context"
-	"fmt"
 	"strconv"
 	"strings"
 
@@ -45,6 +44,15 @@ func (b *Bot) handleCallbackQuery(ctx context.Context, update *tgbotapi.Update)
 		b.clearUserState(ctx, userID)
 		b.handleMainMenu(ctx, update)
 
+	case strings.HasPrefix(data, "set_language:"):
+		b.handleSetLanguage(ctx, update, strings.TrimPrefix(data, "set_language:"))
+
+	case strings.HasPrefix(data, "privacy:"):
+		b.handlePrivacyCallback(ctx, update, strings.TrimPrefix(data, "privacy:"))
+
+	case strings.HasPrefix(data, "waitlist_"):
+		b.handleWaitlistCallback(ctx, update, data)
+
 	case strings.HasPrefix(data, "items_page:"):
 		page, _ := strconv.Atoi(strings.TrimPrefix(data, "items_page:"))
 		b.sendItemsPage(ctx, callback.Message.Chat.ID, callback.Message.MessageID, page)
@@ -94,8 +102,7 @@ func (b *Bot) handleDateSelection(ctx context.Context, update *tgbotapi.Update,
 		return
 	}
 
-	msg := tgbotapi.NewMessage(chatID,
-		fmt.Sprintf("Вы выбрали: %s\n\nВведите дату в формате ДД.ММ.ГГГГ (например, 25.12.2024):", selectedItem.Name))
+	msg := tgbotapi.NewMessage(chatID, b.loc(ctx).T("booking_selected_item", selectedItem.Name))
 
 	b.setUserState(ctx, userID, models.StateWaitingDate, map[string]interface{}{
 		"item_id": itemID,
@@ -109,7 +116,7 @@ func (b *Bot) handleDateSelection(ctx context.Context, update *tgbotapi.Update,
 func (b *Bot) handleScheduleItemSelected(ctx context.Context, update *tgbotapi.Update, itemID int64) {
 	selectedItem, err := b.itemService.GetItemByID(ctx, itemID)
 	if err != nil {
-		b.sendMessage(update.CallbackQuery.Message.Chat.ID, "Ошибка: аппарат не найден")
+		b.sendMessage(update.CallbackQuery.Message.Chat.ID, b.loc(ctx).T("error_device_not_found"))
 		return
 	}
 
@@ -118,16 +125,16 @@ func (b *Bot) handleScheduleItemSelected(ctx context.Context, update *tgbotapi.U
 		"item_id": itemID,
 	})
 
-	msg := tgbotapi.NewMessage(update.CallbackQuery.Message.Chat.ID,
-		fmt.Sprintf("Выбран аппарат: %s\n\nВыберите период или введите дату:", selectedItem.Name))
+	l := b.loc(ctx)
+	msg := tgbotapi.NewMessage(update.CallbackQuery.Message.Chat.ID, l.T("schedule_item_selected", selectedItem.Name))
 
 	keyboard := tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton(btnMonthSchedule),
-			tgbotapi.NewKeyboardButton(btnPickDate),
+			tgbotapi.NewKeyboardButton(l.T(btnMonthSchedule)),
+			tgbotapi.NewKeyboardButton(l.T(btnPickDate)),
 		),
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton(btnBackToItems),
+			tgbotapi.NewKeyboardButton(l.T(btnBackToItems)),
 		),
 	)
 	msg.ReplyMarkup = keyboard
@@ -1,32 +1,79 @@
 package bot
 
 import (
+	"context"
 	"errors"
+	"strings"
 
 	"bronivik/internal/database"
+	"bronivik/internal/models"
 )
 
-func (b *Bot) getErrorMessage(err error) string {
+func (b *Bot) getErrorMessage(ctx context.Context, err error) string {
 	if err == nil {
 		return ""
 	}
+	l := b.loc(ctx)
+
+	var datesErr *database.UnavailableDatesError
+	if errors.As(err, &datesErr) && len(datesErr.Dates) > 0 {
+		dates := make([]string, 0, len(datesErr.Dates))
+		for _, d := range datesErr.Dates {
+			dates = append(dates, l.Date(d))
+		}
+		return l.T("error_unavailable_dates", strings.Join(dates, ", "))
+	}
 
 	if errors.Is(err, database.ErrNotAvailable) {
-		return "⚠️ Извините, этот аппарат уже забронирован на выбранную дату. Пожалуйста, выберите другое время или аппарат."
+		return l.T("error_not_available")
 	}
 
 	if errors.Is(err, database.ErrPastDate) {
-		return "⚠️ Нельзя создавать бронирование на прошедшую дату."
+		return l.T("error_past_date")
 	}
 
 	if errors.Is(err, database.ErrDateTooFar) {
-		return "⚠️ Вы не можете бронировать так далеко в будущем. Пожалуйста, выберите более раннюю дату."
+		return l.T("error_date_too_far")
+	}
+
+	if errors.Is(err, database.ErrInvalidDateRange) {
+		return l.T("error_invalid_date_range")
+	}
+
+	var trErr *models.TransitionError
+	if errors.As(err, &trErr) {
+		return l.T("error_transition", bookingStatusLabel(l, trErr.From), bookingStatusLabel(l, trErr.To))
+	}
+
+	var statusErr *models.UnknownStatusError
+	if errors.As(err, &statusErr) {
+		return l.T("error_unknown_status", statusErr.Status)
 	}
 
 	if errors.Is(err, database.ErrConcurrentModification) {
-		return "⚠️ Произошла ошибка при сохранении (конфликт версий). Пожалуйста, попробуйте еще раз."
+		return l.T("error_concurrent_modification")
+	}
+
+	if errors.Is(err, database.ErrWaitlistOfferExpired) {
+		return l.T("error_waitlist_offer_expired")
+	}
+
+	if errors.Is(err, database.ErrAlreadyInWaitlist) {
+		return l.T("error_already_in_waitlist")
+	}
+
+	if errors.Is(err, models.ErrInvalidRecurrence) {
+		return l.T("error_invalid_recurrence")
+	}
+
+	if errors.Is(err, models.ErrTooManyOccurrences) {
+		return l.T("error_too_many_occurrences", models.MaxSeriesOccurrences)
+	}
+
+	if errors.Is(err, database.ErrSeriesCanceled) {
+		return l.T("error_series_canceled")
 	}
 
 	// Default error message
-	return "❌ Произошла ошибка при обработке вашего запроса. Пожалуйста, попробуйте позже или обратитесь к менеджеру."
+	return l.T("error_generic")
 }
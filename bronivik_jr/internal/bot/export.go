@@ -7,11 +7,24 @@ import (
 	"path/filepath"
 	"time"
 
+	"bronivik/internal/config"
+	"bronivik/internal/fieldcrypt"
+	"bronivik/internal/i18n"
 	"bronivik/internal/models"
 
 	"github.com/xuri/excelize/v2"
 )
 
+// exportPerson возвращает имя и телефон клиента для Excel-выгрузки. При
+// шифровании полей они расшифровываются, только если это разрешено в
+// encryption.decrypt_exports, иначе маскируются.
+func (b *Bot) exportPerson(name, phone string) (string, string) {
+	if b.config.Encryption.DecryptsExport(config.ExportExcel) {
+		return name, phone
+	}
+	return fieldcrypt.MaskName(name), fieldcrypt.MaskPhone(phone)
+}
+
 // exportToExcel создает Excel файл с данными о бронированиях
 func (b *Bot) exportToExcel(ctx context.Context, startDate, endDate time.Time) (string, error) {
 	// Создаем папку для экспорта, если не существует
@@ -34,8 +47,9 @@ func (b *Bot) exportToExcel(ctx context.Context, startDate, endDate time.Time) (
 	f := excelize.NewFile()
 	defer f.Close()
 
-	// Создаем лист с данными
-	sheetName := "Бронирования"
+	// Создаем лист с данными на языке менеджера
+	l := b.loc(ctx)
+	sheetName := l.T("export_sheet_bookings")
 	index, err := f.NewSheet(sheetName)
 	if err != nil {
 		return "", fmt.Errorf("error creating sheet: %v", err)
@@ -43,17 +57,16 @@ func (b *Bot) exportToExcel(ctx context.Context, startDate, endDate time.Time) (
 	f.SetActiveSheet(index)
 
 	// Устанавливаем заголовок периода
-	_ = f.SetCellValue(sheetName, "A1", fmt.Sprintf("Период: %s - %s",
-		startDate.Format("02.01.2006"), endDate.Format("02.01.2006")))
+	_ = f.SetCellValue(sheetName, "A1", l.T("export_period", l.Date(startDate), l.Date(endDate)))
 
 	// Заголовки - даты
-	dateHeaders := b.writeDateHeaders(f, sheetName, startDate, endDate)
+	dateHeaders := b.writeDateHeaders(l, f, sheetName, startDate, endDate)
 
 	// Названия аппаратов
 	b.writeItemHeaders(f, sheetName, items)
 
 	// Заполняем данные по бронированиям
-	b.writeBookingData(ctx, f, sheetName, dailyBookings, items, dateHeaders)
+	b.writeBookingData(ctx, l, f, sheetName, dailyBookings, items, dateHeaders)
 
 	// Настраиваем ширину колонок
 	_ = f.SetColWidth(sheetName, "A", "A", 25)
@@ -89,15 +102,16 @@ func (b *Bot) exportToExcel(ctx context.Context, startDate, endDate time.Time) (
 	return filePath, nil
 }
 
-func (b *Bot) writeDateHeaders(f *excelize.File, sheetName string, startDate, endDate time.Time) map[string]int {
+func (b *Bot) writeDateHeaders(
+	l *i18n.Localizer, f *excelize.File, sheetName string, startDate, endDate time.Time,
+) map[string]int {
 	col := 2
 	currentDate := startDate
 	dateHeaders := make(map[string]int)
 
 	for !currentDate.After(endDate) {
 		cell, _ := excelize.CoordinatesToCellName(col, 2)
-		dateStr := currentDate.Format("02.01")
-		_ = f.SetCellValue(sheetName, cell, dateStr)
+		_ = f.SetCellValue(sheetName, cell, l.DayMonth(currentDate))
 		dateHeaders[currentDate.Format("2006-01-02")] = col
 
 		style, _ := f.NewStyle(&excelize.Style{
@@ -130,7 +144,7 @@ func (b *Bot) writeItemHeaders(f *excelize.File, sheetName string, items []*mode
 }
 
 func (b *Bot) writeBookingData(
-	ctx context.Context, f *excelize.File, sheetName string,
+	ctx context.Context, l *i18n.Localizer, f *excelize.File, sheetName string,
 	dailyBookings map[string][]*models.Booking,
 	items []*models.Item,
 	dateHeaders map[string]int,
@@ -161,14 +175,15 @@ func (b *Bot) writeBookingData(
 			if len(itemBookings) > 0 {
 				for _, booking := range itemBookings {
 					status := b.getBookingStatusIcon(booking.Status)
-					cellValue += fmt.Sprintf("%s %s (%s)\n", status, booking.UserName, booking.Phone)
+					name, phone := b.exportPerson(booking.UserName, booking.Phone)
+					cellValue += fmt.Sprintf("%s %s (%s)\n", status, name, phone)
 					if booking.Comment != "" {
 						cellValue += fmt.Sprintf("   💬 %s\n", booking.Comment)
 					}
 				}
-				cellValue += fmt.Sprintf("\nЗанято: %d/%d", bookedCount, item.TotalQuantity)
+				cellValue += "\n" + l.T("export_booked", bookedCount, item.TotalQuantity)
 			} else {
-				cellValue = fmt.Sprintf("Свободно\n\nДоступно: %d/%d", item.TotalQuantity, item.TotalQuantity)
+				cellValue = l.T("export_free", item.TotalQuantity, item.TotalQuantity)
 			}
 
 			_ = f.SetCellValue(sheetName, cell, cellValue)
@@ -294,7 +309,7 @@ func getLastColumn(colCount int) string {
 }
 
 // exportUsersToExcel создает Excel файл с данными пользователей
-func (b *Bot) exportUsersToExcel(_ context.Context, users []*models.User) (string, error) {
+func (b *Bot) exportUsersToExcel(ctx context.Context, users []*models.User) (string, error) {
 	// Создаем папку для экспорта, если не существует
 	if err := os.MkdirAll(b.config.Exports.Path, 0o755); err != nil {
 		return "", fmt.Errorf("error creating export directory: %v", err)
@@ -303,8 +318,10 @@ func (b *Bot) exportUsersToExcel(_ context.Context, users []*models.User) (strin
 	// Создаем новый Excel файл
 	f := excelize.NewFile()
 
-	// Создаем лист с пользователями
-	index, err := f.NewSheet("Пользователи")
+	// Создаем лист с пользователями на языке менеджера
+	l := b.loc(ctx)
+	sheetName := l.T("export_sheet_users")
+	index, err := f.NewSheet(sheetName)
 	if err != nil {
 		return "", fmt.Errorf("error creating sheet: %v", err)
 	}
@@ -312,43 +329,45 @@ func (b *Bot) exportUsersToExcel(_ context.Context, users []*models.User) (strin
 
 	// Заголовки
 	headers := []string{
-		"ID", "Telegram ID", "Username", "Имя", "Фамилия", "Телефон",
-		"Менеджер", "Черный список", "Язык", "Последняя активность", "Дата регистрации",
+		"ID", "Telegram ID", "Username", l.T("export_col_first_name"), l.T("export_col_last_name"),
+		l.T("export_col_phone"), l.T("export_col_manager"), l.T("export_col_blacklisted"),
+		l.T("export_col_language"), l.T("export_col_last_activity"), l.T("export_col_registered"),
 	}
 	for i, header := range headers {
 		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
-		_ = f.SetCellValue("Пользователи", cell, header)
-		// f.SetCellStyle("Пользователи", cell, cell, f.SetCellStyle("Пользователи", cell, "bold")
+		_ = f.SetCellValue(sheetName, cell, header)
+		// f.SetCellStyle(sheetName, cell, cell, f.SetCellStyle(sheetName, cell, "bold")
 	}
 
 	// Данные пользователей
 	for i, user := range users {
 		row := i + 2
-		_ = f.SetCellValue("Пользователи", fmt.Sprintf("A%d", row), user.ID)
-		_ = f.SetCellValue("Пользователи", fmt.Sprintf("B%d", row), user.TelegramID)
-		_ = f.SetCellValue("Пользователи", fmt.Sprintf("C%d", row), user.Username)
-		_ = f.SetCellValue("Пользователи", fmt.Sprintf("D%d", row), user.FirstName)
-		_ = f.SetCellValue("Пользователи", fmt.Sprintf("E%d", row), user.LastName)
-		_ = f.SetCellValue("Пользователи", fmt.Sprintf("F%d", row), user.Phone)
-		_ = f.SetCellValue("Пользователи", fmt.Sprintf("G%d", row), boolToYesNo(user.IsManager))
-		_ = f.SetCellValue("Пользователи", fmt.Sprintf("H%d", row), boolToYesNo(user.IsBlacklisted))
-		_ = f.SetCellValue("Пользователи", fmt.Sprintf("I%d", row), user.LanguageCode)
-		_ = f.SetCellValue("Пользователи", fmt.Sprintf("J%d", row), user.LastActivity.Format("02.01.2006 15:04"))
-		_ = f.SetCellValue("Пользователи", fmt.Sprintf("K%d", row), user.CreatedAt.Format("02.01.2006 15:04"))
+		_ = f.SetCellValue(sheetName, fmt.Sprintf("A%d", row), user.ID)
+		_ = f.SetCellValue(sheetName, fmt.Sprintf("B%d", row), user.TelegramID)
+		_ = f.SetCellValue(sheetName, fmt.Sprintf("C%d", row), user.Username)
+		_ = f.SetCellValue(sheetName, fmt.Sprintf("D%d", row), user.FirstName)
+		_ = f.SetCellValue(sheetName, fmt.Sprintf("E%d", row), user.LastName)
+		_, phone := b.exportPerson("", user.Phone)
+		_ = f.SetCellValue(sheetName, fmt.Sprintf("F%d", row), phone)
+		_ = f.SetCellValue(sheetName, fmt.Sprintf("G%d", row), yesNo(l, user.IsManager))
+		_ = f.SetCellValue(sheetName, fmt.Sprintf("H%d", row), yesNo(l, user.IsBlacklisted))
+		_ = f.SetCellValue(sheetName, fmt.Sprintf("I%d", row), user.LanguageCode)
+		_ = f.SetCellValue(sheetName, fmt.Sprintf("J%d", row), l.DateTime(user.LastActivity))
+		_ = f.SetCellValue(sheetName, fmt.Sprintf("K%d", row), l.DateTime(user.CreatedAt))
 	}
 
 	// Настраиваем ширину колонок
-	_ = f.SetColWidth("Пользователи", "A", "A", 10)
-	_ = f.SetColWidth("Пользователи", "B", "B", 15)
-	_ = f.SetColWidth("Пользователи", "C", "C", 20)
-	_ = f.SetColWidth("Пользователи", "D", "D", 15)
-	_ = f.SetColWidth("Пользователи", "E", "E", 15)
-	_ = f.SetColWidth("Пользователи", "F", "F", 15)
-	_ = f.SetColWidth("Пользователи", "G", "G", 10)
-	_ = f.SetColWidth("Пользователи", "H", "H", 12)
-	_ = f.SetColWidth("Пользователи", "I", "I", 10)
-	_ = f.SetColWidth("Пользователи", "J", "J", 20)
-	_ = f.SetColWidth("Пользователи", "K", "K", 20)
+	_ = f.SetColWidth(sheetName, "A", "A", 10)
+	_ = f.SetColWidth(sheetName, "B", "B", 15)
+	_ = f.SetColWidth(sheetName, "C", "C", 20)
+	_ = f.SetColWidth(sheetName, "D", "D", 15)
+	_ = f.SetColWidth(sheetName, "E", "E", 15)
+	_ = f.SetColWidth(sheetName, "F", "F", 15)
+	_ = f.SetColWidth(sheetName, "G", "G", 10)
+	_ = f.SetColWidth(sheetName, "H", "H", 12)
+	_ = f.SetColWidth(sheetName, "I", "I", 10)
+	_ = f.SetColWidth(sheetName, "J", "J", 20)
+	_ = f.SetColWidth(sheetName, "K", "K", 20)
 
 	// Удаляем стандартный лист
 	_ = f.DeleteSheet("Sheet1")
@@ -365,10 +384,10 @@ func (b *Bot) exportUsersToExcel(_ context.Context, users []*models.User) (strin
 	return filePath, nil
 }
 
-// boolToYesNo преобразует bool в "Да"/"Нет"
-func boolToYesNo(b bool) string {
-	if b {
-		return "Да"
+// yesNo преобразует bool в "Да"/"Нет" на языке менеджера
+func yesNo(l *i18n.Localizer, v bool) string {
+	if v {
+		return l.T("export_yes")
 	}
-	return "Нет"
+	return l.T("export_no")
 }
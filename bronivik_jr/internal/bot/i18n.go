@@ -0,0 +1,107 @@
+package bot
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+
+	"bronivik/internal/i18n"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// defaultLanguage используется, если язык пользователя не поддерживается.
+const defaultLanguage = "ru"
+
+//go:embed locales/*.yaml
+var localeFiles embed.FS
+
+// messages содержит каталоги сообщений бота (locales/<язык>.yaml).
+var messages = mustLoadMessages()
+
+func mustLoadMessages() *i18n.Bundle {
+	locales, err := fs.Sub(localeFiles, "locales")
+	if err != nil {
+		panic(err)
+	}
+	bundle, err := i18n.Load(locales, defaultLanguage)
+	if err != nil {
+		panic(fmt.Sprintf("load bot messages: %v", err))
+	}
+	return bundle
+}
+
+// loc возвращает локализатор пользователя, чье обновление обрабатывается.
+func (b *Bot) loc(ctx context.Context) *i18n.Localizer {
+	if l := i18n.FromContext(ctx); l != nil {
+		return l
+	}
+	return messages.Localizer(defaultLanguage)
+}
+
+// locFor возвращает локализатор другого пользователя, например клиента,
+// которому менеджер подтвердил заявку.
+func (b *Bot) locFor(ctx context.Context, userID int64) *i18n.Localizer {
+	return messages.Localizer(b.userLanguage(ctx, userID, ""))
+}
+
+// userLanguage выбирает язык: настройка /language, затем язык клиента Telegram
+// из обновления, затем сохраненный при /start.
+func (b *Bot) userLanguage(ctx context.Context, userID int64, telegramLang string) string {
+	if b.userService == nil {
+		return telegramLang
+	}
+	chosen, stored, err := b.userService.GetUserLanguage(ctx, userID)
+	if err != nil {
+		b.logger.Error().Err(err).Int64("user_id", userID).Msg("Failed to load user language")
+	}
+	switch {
+	case chosen != "":
+		return chosen
+	case telegramLang != "":
+		return telegramLang
+	default:
+		return stored
+	}
+}
+
+// isButton сообщает, что text — надпись кнопки key на любом из языков:
+// клавиатура могла быть отправлена до смены языка.
+func isButton(text, key string) bool {
+	return messages.Matches(key, text)
+}
+
+// handleLanguageCommand показывает выбор языка интерфейса.
+func (b *Bot) handleLanguageCommand(ctx context.Context, update *tgbotapi.Update) {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(messages.Languages()))
+	for _, lang := range messages.Languages() {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(messages.Localizer(lang).T("language_name"), "set_language:"+lang),
+		))
+	}
+
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, b.loc(ctx).T("language_choose"))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	if _, err := b.tgService.Send(msg); err != nil {
+		b.logger.Error().Err(err).Int64("chat_id", update.Message.Chat.ID).Msg("Failed to send language menu")
+	}
+}
+
+// handleSetLanguage сохраняет выбранный язык и показывает меню уже на нем.
+func (b *Bot) handleSetLanguage(ctx context.Context, update *tgbotapi.Update, lang string) {
+	callback := update.CallbackQuery
+	if !messages.Supported(lang) {
+		return
+	}
+	if err := b.userService.SetUserLanguage(ctx, callback.From.ID, lang); err != nil {
+		b.logger.Error().Err(err).Int64("user_id", callback.From.ID).Str("language", lang).Msg("Failed to save user language")
+		b.sendMessage(callback.Message.Chat.ID, b.loc(ctx).T("error_generic"))
+		return
+	}
+
+	l := messages.Localizer(lang)
+	ctx = i18n.WithLocalizer(ctx, l)
+	b.sendMessage(callback.Message.Chat.ID, l.T("language_set"))
+	b.handleMainMenu(ctx, update)
+}
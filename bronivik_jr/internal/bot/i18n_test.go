@@ -0,0 +1,119 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bronivik/internal/i18n"
+	"bronivik/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestMessageCatalogsComplete(t *testing.T) {
+	for _, lang := range messages.Languages() {
+		assert.Empty(t, messages.Missing(lang), "untranslated keys in %s.yaml", lang)
+	}
+	assert.ElementsMatch(t, []string{"en", "ru"}, messages.Languages())
+}
+
+func TestLanguageCommandSwitchesLanguage(t *testing.T) {
+	b, mocks := setupTestBot()
+	ctx := context.Background()
+	userID := int64(555)
+	user := &tgbotapi.User{ID: userID}
+	chat := &tgbotapi.Chat{ID: userID}
+
+	b.processUpdate(ctx, &tgbotapi.Update{Message: &tgbotapi.Message{From: user, Chat: chat, Text: "/language"}})
+	require.NotEmpty(t, mocks.tg.sentMessages)
+	menu, ok := mocks.tg.sentMessages[len(mocks.tg.sentMessages)-1].(tgbotapi.MessageConfig)
+	require.True(t, ok)
+	assert.Contains(t, menu.Text, "Выберите язык")
+	keyboard, ok := menu.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+	require.True(t, ok)
+	var callbacks []string
+	for _, row := range keyboard.InlineKeyboard {
+		callbacks = append(callbacks, *row[0].CallbackData)
+	}
+	assert.Equal(t, []string{"set_language:en", "set_language:ru"}, callbacks)
+
+	b.processUpdate(ctx, &tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    user,
+		Message: &tgbotapi.Message{Chat: chat, MessageID: 1},
+		Data:    "set_language:en",
+	}})
+	assert.Equal(t, "en", mocks.user.languages[userID])
+	assert.Contains(t, sentTexts(mocks), "✅ Interface language: English.")
+
+	// Кнопки старой русской клавиатуры продолжают работать, ответ уже на английском
+	b.processUpdate(ctx, &tgbotapi.Update{Message: &tgbotapi.Message{From: user, Chat: chat, Text: "📊 Мои заявки"}})
+	assert.Contains(t, lastSentText(mocks), "You have no bookings yet")
+	b.processUpdate(ctx, &tgbotapi.Update{Message: &tgbotapi.Message{From: user, Chat: chat, Text: "📊 My bookings"}})
+	assert.Contains(t, lastSentText(mocks), "You have no bookings yet")
+}
+
+func TestTelegramLanguageIsDefault(t *testing.T) {
+	b, mocks := setupTestBot()
+	ctx := context.Background()
+	user := &tgbotapi.User{ID: 556, LanguageCode: "en-GB"}
+
+	b.processUpdate(ctx, &tgbotapi.Update{Message: &tgbotapi.Message{
+		From: user, Chat: &tgbotapi.Chat{ID: user.ID}, Text: "📊 My bookings",
+	}})
+	assert.Contains(t, lastSentText(mocks), "You have no bookings yet")
+
+	// Выбор через /language важнее языка клиента Telegram
+	require.NoError(t, mocks.user.SetUserLanguage(ctx, user.ID, "ru"))
+	b.processUpdate(ctx, &tgbotapi.Update{Message: &tgbotapi.Message{
+		From: user, Chat: &tgbotapi.Chat{ID: user.ID}, Text: "📊 My bookings",
+	}})
+	assert.Contains(t, lastSentText(mocks), "У вас пока нет заявок")
+}
+
+func TestNotificationUsesRecipientLanguage(t *testing.T) {
+	b, mocks := setupTestBot()
+	ctx := context.Background()
+	clientID := int64(777)
+	require.NoError(t, mocks.user.SaveUser(ctx, &models.User{TelegramID: clientID, LanguageCode: "en"}))
+
+	// Менеджер работает по-русски, клиент получает уведомление на своем языке
+	assert.Equal(t, "ru", b.loc(ctx).Lang())
+	assert.Equal(t, "en", b.locFor(ctx, clientID).Lang())
+	assert.Equal(t, "ru", b.locFor(ctx, 123).Lang())
+}
+
+func TestExportUsesManagerLanguage(t *testing.T) {
+	b, _ := setupTestBot()
+	b.config.Exports.Path = t.TempDir()
+	ctx := i18n.WithLocalizer(context.Background(), messages.Localizer("en"))
+
+	users := []*models.User{{ID: 1, TelegramID: 123, IsManager: true, CreatedAt: time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC)}}
+	path, err := b.exportUsersToExcel(ctx, users)
+	require.NoError(t, err)
+
+	f, err := excelize.OpenFile(path)
+	require.NoError(t, err)
+	defer f.Close()
+	assert.Equal(t, []string{"Users"}, f.GetSheetList())
+	header, _ := f.GetCellValue("Users", "D1")
+	assert.Equal(t, "First name", header)
+	manager, _ := f.GetCellValue("Users", "G2")
+	assert.Equal(t, "Yes", manager)
+	registered, _ := f.GetCellValue("Users", "K2")
+	assert.Equal(t, "Mar 5, 2026 10:00", registered)
+}
+
+func sentTexts(mocks *botMocks) []string {
+	var texts []string
+	for _, c := range mocks.tg.sentMessages {
+		if msg, ok := c.(tgbotapi.MessageConfig); ok {
+			texts = append(texts, msg.Text)
+		}
+	}
+	return texts
+}
@@ -2,7 +2,6 @@ package bot
 
 import (
 	"context"
-	"fmt"
 	"strconv"
 	"strings"
 
@@ -42,11 +41,11 @@ func (b *Bot) handleManagerCommand(ctx context.Context, update *tgbotapi.Update)
 // handleManagerBasicCommands обрабатывает основные команды менеджера
 func (b *Bot) handleManagerBasicCommands(ctx context.Context, update *tgbotapi.Update, text string, userID int64) bool {
 	switch {
-	case text == btnAllBookings || text == "/get_all":
+	case isButton(text, btnAllBookings) || text == "/get_all":
 		b.showManagerBookings(ctx, update)
 		return true
 
-	case text == btnCreateBookingManager:
+	case isButton(text, btnCreateBookingManager):
 		b.startManagerBooking(ctx, update)
 		return true
 
@@ -63,15 +62,55 @@ func (b *Bot) handleManagerBasicCommands(ctx context.Context, update *tgbotapi.U
 		}
 		return true
 
-	case text == btnSyncBookings:
-		b.sendMessage(update.Message.Chat.ID, "⏳ Запускаю фоновую синхронизацию бронирований...")
+	case isButton(text, btnSyncBookings):
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("sync_bookings_started"))
 		go b.SyncBookingsToSheets(ctx)
 		return true
 
-	case text == btnSyncSchedule:
-		b.sendMessage(update.Message.Chat.ID, "⏳ Запускаю фоновую синхронизацию расписания...")
+	case isButton(text, btnSyncSchedule):
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("sync_schedule_started"))
 		go b.SyncScheduleToSheets(ctx)
 		return true
+
+	case text == "/webhooks":
+		b.handleWebhooksCommand(ctx, update)
+		return true
+
+	case strings.HasPrefix(text, "/redeliver"):
+		b.handleRedeliverCommand(ctx, update)
+		return true
+
+	case text == "/api_keys":
+		b.handleAPIKeysCommand(ctx, update)
+		return true
+
+	case strings.HasPrefix(text, "/api_key_create"):
+		b.handleAPIKeyCreateCommand(ctx, update)
+		return true
+
+	case strings.HasPrefix(text, "/api_key_rotate"):
+		b.handleAPIKeyRotateCommand(ctx, update)
+		return true
+
+	case strings.HasPrefix(text, "/api_key_revoke"):
+		b.handleAPIKeyRevokeCommand(ctx, update)
+		return true
+
+	case strings.HasPrefix(text, "/api_key_limits"):
+		b.handleAPIKeyLimitsCommand(ctx, update)
+		return true
+
+	case strings.HasPrefix(text, "/api_key_cert"):
+		b.handleAPIKeyCertCommand(ctx, update)
+		return true
+
+	case strings.HasPrefix(text, "/user_data"):
+		b.handleUserDataCommand(ctx, update)
+		return true
+
+	case strings.HasPrefix(text, "/erase_user"):
+		b.handleEraseUserCommand(ctx, update)
+		return true
 	}
 	return false
 }
@@ -122,16 +161,29 @@ func (b *Bot) handleManagerStateCommands(ctx context.Context, update *tgbotapi.U
 	case models.StateManagerWaitingEndDate:
 		b.handleManagerEndDate(ctx, update, text, state)
 		return true
+	case models.StateManagerWaitingSeriesStart:
+		b.handleManagerSeriesStart(ctx, update, text, state)
+		return true
+	case models.StateManagerWaitingSeriesEnd:
+		b.handleManagerSeriesEnd(ctx, update, text, state)
+		return true
 	case models.StateManagerWaitingComment:
 		b.handleManagerComment(ctx, update, text, state)
 		return true
+	case models.StateManagerWaitingReschedule:
+		b.handleManagerRescheduleDates(ctx, update, text, state)
+		return true
 	case models.StateManagerConfirmBooking:
-		if text == btnConfirmCreate {
-			b.createManagerBookings(ctx, update, state)
+		if isButton(text, btnConfirmCreate) {
+			if state.GetString("date_type") == typeSeries {
+				b.createManagerSeries(ctx, update, state)
+			} else {
+				b.createManagerBookings(ctx, update, state)
+			}
 			return true
-		} else if text == btnCancel {
+		} else if isButton(text, btnCancel) {
 			b.clearUserState(ctx, update.Message.From.ID)
-			b.sendMessage(update.Message.Chat.ID, "❌ Создание заявки отменено")
+			b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("manager_booking_create_canceled"))
 			b.handleMainMenu(ctx, update)
 			return true
 		}
@@ -168,7 +220,7 @@ func (b *Bot) handleManagerPaginationAndSelection(ctx context.Context, update *t
 	switch {
 	case strings.HasPrefix(data, "manager_items_page:"):
 		page, _ := strconv.Atoi(strings.TrimPrefix(data, "manager_items_page:"))
-		b.editManagerItemsPage(update, page)
+		b.editManagerItemsPage(ctx, update, page)
 		return true
 
 	case strings.HasPrefix(data, "manager_bookings_page:"):
@@ -199,6 +251,8 @@ func (b *Bot) handleManagerMiscCallbacks(ctx context.Context, update *tgbotapi.U
 	case data == "manager_date_range":
 		b.handleManagerDateType(ctx, update, "range")
 		return true
+	case strings.HasPrefix(data, "manager_series"), strings.HasPrefix(data, "series_"):
+		return b.handleSeriesCallback(ctx, update, data)
 	case strings.HasPrefix(data, "change_to_"):
 		b.handleChangeItem(ctx, update)
 		return true
@@ -208,6 +262,9 @@ func (b *Bot) handleManagerMiscCallbacks(ctx context.Context, update *tgbotapi.U
 	case data == "export_users":
 		b.handleExportUsers(ctx, update)
 		return true
+	case strings.HasPrefix(data, "erase_user"):
+		b.handleEraseUserCallback(ctx, update, data)
+		return true
 	}
 	return false
 }
@@ -247,7 +304,7 @@ func (b *Bot) handleManagerBookingActions(ctx context.Context, update *tgbotapi.
 	case "reject_":
 		b.rejectBooking(ctx, booking, callback.Message.Chat.ID)
 	case "reschedule_":
-		b.rescheduleBooking(ctx, booking, callback.Message.Chat.ID)
+		b.startRescheduleBooking(ctx, booking, callback.From.ID, callback.Message.Chat.ID)
 	case "change_item_":
 		b.startChangeItem(ctx, booking, callback.Message.Chat.ID)
 	case "reopen_":
@@ -258,7 +315,7 @@ func (b *Bot) handleManagerBookingActions(ctx context.Context, update *tgbotapi.
 
 	if action != "change_item_" {
 		editMsg := tgbotapi.NewEditMessageText(callback.Message.Chat.ID, callback.Message.MessageID,
-			fmt.Sprintf("✅ Заявка #%d обработана\nДействие: %s", bookingID, action))
+			b.loc(ctx).T("manager_booking_processed", bookingID, action))
 		if _, err := b.tgService.Send(editMsg); err != nil {
 			b.logger.Error().Err(err).Msg("Failed to send edit message in handleManagerBookingActions")
 		}
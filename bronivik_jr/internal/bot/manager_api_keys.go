@@ -0,0 +1,275 @@
+package bot
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"bronivik/internal/domain"
+	"bronivik/internal/i18n"
+	"bronivik/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// SetAPIKeyService подключает управление ключами API. Без него команды недоступны.
+func (b *Bot) SetAPIKeyService(apiKeyService domain.APIKeyService) {
+	b.apiKeyService = apiKeyService
+}
+
+// handleAPIKeysCommand показывает ключи API из базы.
+func (b *Bot) handleAPIKeysCommand(ctx context.Context, update *tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+	if b.apiKeyService == nil {
+		b.sendMessage(chatID, b.loc(ctx).T("api_keys_disabled"))
+		return
+	}
+
+	keys, err := b.apiKeyService.ListKeys(ctx)
+	if err != nil {
+		b.sendMessage(chatID, b.loc(ctx).T("api_keys_error", err))
+		return
+	}
+	b.sendMessage(chatID, formatAPIKeys(b.loc(ctx), keys, time.Now()))
+}
+
+func formatAPIKeys(l *i18n.Localizer, keys []*models.APIKey, now time.Time) string {
+	var sb strings.Builder
+	sb.WriteString(l.T("api_keys_title") + "\n")
+	if len(keys) == 0 {
+		sb.WriteString(l.T("api_keys_empty") + "\n")
+	}
+	for _, k := range keys {
+		mark := "✅"
+		var details []string
+		switch {
+		case k.RevokedAt != nil:
+			mark = "⛔"
+			details = append(details, l.T("api_keys_revoked_at", l.DateTime(*k.RevokedAt)))
+		case !k.ActiveAt(now):
+			mark = "⌛"
+			details = append(details, l.T("api_keys_expired", l.DateTime(*k.ExpiresAt)))
+		case k.ExpiresAt != nil:
+			if k.ReplacedBy != nil {
+				mark = "⏳"
+			}
+			details = append(details, l.T("api_keys_expires", l.DateTime(*k.ExpiresAt)))
+		}
+		if k.ReplacedBy != nil {
+			details = append(details, l.T("api_keys_replaced", *k.ReplacedBy))
+		}
+		if k.RateLimitRPS > 0 {
+			details = append(details, l.T("api_keys_rate", strconv.FormatFloat(k.RateLimitRPS, 'f', -1, 64)))
+		}
+		if k.RateLimitBurst > 0 {
+			details = append(details, l.T("api_keys_burst", k.RateLimitBurst))
+		}
+		if k.DailyQuota > 0 {
+			details = append(details, l.T("api_keys_quota", k.DailyQuota))
+		}
+		if k.ClientCertCN != "" {
+			details = append(details, l.T("api_keys_cert", k.ClientCertCN))
+		}
+		if k.LastUsedAt != nil {
+			details = append(details, l.T("api_keys_last_used", l.DateTime(*k.LastUsedAt)))
+		} else {
+			details = append(details, l.T("api_keys_never_used"))
+		}
+
+		perms := l.T("api_keys_all_permissions")
+		if len(k.Permissions) > 0 {
+			perms = strings.Join(k.Permissions, ", ")
+		}
+		sb.WriteString(fmt.Sprintf("%s #%d %s (%s…) — %s\n   %s\n", mark, k.ID, k.Name, k.Prefix, perms, strings.Join(details, ", ")))
+	}
+	sb.WriteString("\n" + l.T("api_keys_hint"))
+	return sb.String()
+}
+
+// handleAPIKeyCreateCommand выпускает ключ: /api_key_create <имя> <разрешения> [дней].
+// Аргументы после имени различаются по виду: число — срок действия, иначе список разрешений.
+func (b *Bot) handleAPIKeyCreateCommand(ctx context.Context, update *tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+	l := b.loc(ctx)
+	if b.apiKeyService == nil {
+		b.sendMessage(chatID, l.T("api_keys_disabled"))
+		return
+	}
+
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) < 2 || len(parts) > 4 {
+		b.sendMessage(chatID, l.T("api_keys_usage_create"))
+		return
+	}
+	var permissions []string
+	var expiresAt *time.Time
+	for _, arg := range parts[2:] {
+		if days, err := strconv.Atoi(arg); err == nil {
+			if days <= 0 || expiresAt != nil {
+				b.sendMessage(chatID, l.T("api_keys_usage_create"))
+				return
+			}
+			until := time.Now().AddDate(0, 0, days)
+			expiresAt = &until
+			continue
+		}
+		permissions = append(permissions, strings.Split(arg, ",")...)
+	}
+
+	key, err := b.apiKeyService.CreateKey(ctx, parts[1], permissions, expiresAt, models.APIKeyLimits{})
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidAPIKeyPermission) || errors.Is(err, models.ErrNoAPIKeyPermissions) {
+			b.sendMessage(chatID, l.T("api_keys_invalid_permission", strings.Join(models.APIKeyPermissions, ", ")))
+			return
+		}
+		b.sendMessage(chatID, l.T("api_keys_error", err))
+		return
+	}
+	b.sendMessage(chatID, l.T("api_keys_created", key.ID, key.Name, key.Key, key.SigningSecret))
+}
+
+// handleAPIKeyRotateCommand заменяет ключ: /api_key_rotate <номер> [часов].
+func (b *Bot) handleAPIKeyRotateCommand(ctx context.Context, update *tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+	l := b.loc(ctx)
+	if b.apiKeyService == nil {
+		b.sendMessage(chatID, l.T("api_keys_disabled"))
+		return
+	}
+
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) < 2 || len(parts) > 3 {
+		b.sendMessage(chatID, l.T("api_keys_usage_rotate"))
+		return
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(parts[1], "#"), 10, 64)
+	if err != nil || id <= 0 {
+		b.sendMessage(chatID, l.T("api_keys_usage_rotate"))
+		return
+	}
+	graceHours := b.config.API.Auth.RotationGraceHours
+	if len(parts) == 3 {
+		if graceHours, err = strconv.Atoi(parts[2]); err != nil || graceHours <= 0 {
+			b.sendMessage(chatID, l.T("api_keys_usage_rotate"))
+			return
+		}
+	}
+
+	key, err := b.apiKeyService.RotateKey(ctx, id, time.Duration(graceHours)*time.Hour)
+	if err != nil {
+		b.sendAPIKeyError(ctx, chatID, id, err)
+		return
+	}
+	b.sendMessage(chatID, l.T("api_keys_rotated", id, key.ID, graceHours, key.Key, key.SigningSecret))
+}
+
+// handleAPIKeyRevokeCommand сразу отключает ключ: /api_key_revoke <номер>.
+func (b *Bot) handleAPIKeyRevokeCommand(ctx context.Context, update *tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+	l := b.loc(ctx)
+	if b.apiKeyService == nil {
+		b.sendMessage(chatID, l.T("api_keys_disabled"))
+		return
+	}
+
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) != 2 {
+		b.sendMessage(chatID, l.T("api_keys_usage_revoke"))
+		return
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(parts[1], "#"), 10, 64)
+	if err != nil || id <= 0 {
+		b.sendMessage(chatID, l.T("api_keys_usage_revoke"))
+		return
+	}
+
+	if err := b.apiKeyService.RevokeKey(ctx, id); err != nil {
+		b.sendAPIKeyError(ctx, chatID, id, err)
+		return
+	}
+	b.sendMessage(chatID, l.T("api_keys_revoked", id))
+}
+
+// handleAPIKeyLimitsCommand задает лимиты ключа: /api_key_limits <номер> <запросов/с> <пачка> <в сутки>.
+func (b *Bot) handleAPIKeyLimitsCommand(ctx context.Context, update *tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+	l := b.loc(ctx)
+	if b.apiKeyService == nil {
+		b.sendMessage(chatID, l.T("api_keys_disabled"))
+		return
+	}
+
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) != 5 {
+		b.sendMessage(chatID, l.T("api_keys_usage_limits"))
+		return
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(parts[1], "#"), 10, 64)
+	if err != nil || id <= 0 {
+		b.sendMessage(chatID, l.T("api_keys_usage_limits"))
+		return
+	}
+	var limits models.APIKeyLimits
+	var errRPS, errBurst, errQuota error
+	limits.RateLimitRPS, errRPS = strconv.ParseFloat(strings.Replace(parts[2], ",", ".", 1), 64)
+	limits.RateLimitBurst, errBurst = strconv.Atoi(parts[3])
+	limits.DailyQuota, errQuota = strconv.Atoi(parts[4])
+	if errRPS != nil || errBurst != nil || errQuota != nil || limits.Validate() != nil {
+		b.sendMessage(chatID, l.T("api_keys_usage_limits"))
+		return
+	}
+
+	if err := b.apiKeyService.SetKeyLimits(ctx, id, limits); err != nil {
+		b.sendAPIKeyError(ctx, chatID, id, err)
+		return
+	}
+	b.sendMessage(chatID, l.T("api_keys_limits_set", id))
+}
+
+// handleAPIKeyCertCommand закрепляет сертификат клиента: /api_key_cert <номер> <CN сертификата|->.
+// Прочерк снимает привязку.
+func (b *Bot) handleAPIKeyCertCommand(ctx context.Context, update *tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+	l := b.loc(ctx)
+	if b.apiKeyService == nil {
+		b.sendMessage(chatID, l.T("api_keys_disabled"))
+		return
+	}
+
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) != 3 {
+		b.sendMessage(chatID, l.T("api_keys_usage_cert"))
+		return
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(parts[1], "#"), 10, 64)
+	if err != nil || id <= 0 {
+		b.sendMessage(chatID, l.T("api_keys_usage_cert"))
+		return
+	}
+	cn := parts[2]
+	if cn == "-" {
+		cn = ""
+	}
+
+	if err := b.apiKeyService.SetKeyClientCert(ctx, id, cn); err != nil {
+		b.sendAPIKeyError(ctx, chatID, id, err)
+		return
+	}
+	if cn == "" {
+		b.sendMessage(chatID, l.T("api_keys_cert_removed", id))
+		return
+	}
+	b.sendMessage(chatID, l.T("api_keys_cert_set", id, cn))
+}
+
+func (b *Bot) sendAPIKeyError(ctx context.Context, chatID, id int64, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		b.sendMessage(chatID, b.loc(ctx).T("api_keys_not_found", id))
+		return
+	}
+	b.sendMessage(chatID, b.loc(ctx).T("api_keys_error", err))
+}
@@ -0,0 +1,35 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"bronivik/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatAPIKeys(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	graceUntil := now.Add(24 * time.Hour)
+	revokedAt := now.Add(-time.Hour)
+	usedAt := now.Add(-10 * time.Minute)
+	replacedBy := int64(2)
+	keys := []*models.APIKey{
+		{ID: 1, Name: "crm", Prefix: "bk_1a2b3c4d", Permissions: []string{"read:items"}, ExpiresAt: &graceUntil, ReplacedBy: &replacedBy},
+		{ID: 2, Name: "crm", Prefix: "bk_5e6f7a8b", Permissions: []string{"read:items"}, LastUsedAt: &usedAt,
+			ClientCertCN: "crm-client", APIKeyLimits: models.APIKeyLimits{RateLimitRPS: 0.5, DailyQuota: 1000}},
+		{ID: 3, Name: "old", Prefix: "bk_9c0d1e2f", RevokedAt: &revokedAt},
+	}
+
+	ru := messages.Localizer("ru")
+	text := formatAPIKeys(ru, keys, now)
+	assert.Contains(t, text, "⏳ #1 crm (bk_1a2b3c4d…) — read:items")
+	assert.Contains(t, text, "заменен ключом #2")
+	assert.Contains(t, text, "✅ #2 crm (bk_5e6f7a8b…) — read:items\n   до 0.5 запр./с, 1000 запр. в сутки, сертификат CN=crm-client, использован")
+	assert.Contains(t, text, "⛔ #3 old (bk_9c0d1e2f…) — все, кроме admin:*")
+	assert.Contains(t, text, "/api_key_rotate <номер>")
+	assert.Contains(t, text, "/api_key_cert <номер>")
+
+	assert.Contains(t, formatAPIKeys(ru, nil, now), "Ключей в базе нет")
+}
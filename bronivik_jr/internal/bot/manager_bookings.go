@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"bronivik/internal/database"
+	"bronivik/internal/i18n"
 	"bronivik/internal/models"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -20,8 +21,7 @@ func (b *Bot) startManagerBooking(ctx context.Context, update *tgbotapi.Update)
 		return
 	}
 
-	msg := tgbotapi.NewMessage(update.Message.Chat.ID,
-		"📋 Создание заявки от имени клиента\n\nВведите Имя клиента:")
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, b.loc(ctx).T("manager_enter_client_name"))
 
 	b.setUserState(ctx, update.Message.From.ID, models.StateManagerWaitingClientName, map[string]interface{}{
 		"is_manager_booking": true,
@@ -36,7 +36,7 @@ func (b *Bot) handleManagerClientName(ctx context.Context, update *tgbotapi.Upda
 	state.TempData["client_name"] = b.sanitizeInput(text)
 	b.setUserState(ctx, update.Message.From.ID, models.StateManagerWaitingClientPhone, state.TempData)
 
-	msg := tgbotapi.NewMessage(update.Message.Chat.ID, "📱 Введите телефон клиента:")
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, b.loc(ctx).T("manager_enter_client_phone"))
 	if _, err := b.tgService.Send(msg); err != nil {
 		b.logger.Error().Err(err).Msg("Failed to send message in handleManagerClientName")
 	}
@@ -47,7 +47,7 @@ func (b *Bot) handleManagerClientPhone(ctx context.Context, update *tgbotapi.Upd
 	// Нормализуем телефон
 	normalizedPhone := b.normalizePhone(text)
 	if normalizedPhone == "" {
-		b.sendMessage(update.Message.Chat.ID, "Неверный формат номера телефона. Пожалуйста, введите номер в формате +7XXXXXXXXXX или 8XXXXXXXXXX")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("phone_invalid"))
 		return
 	}
 
@@ -65,7 +65,7 @@ func (b *Bot) sendManagerItemsPage(ctx context.Context, chatID int64, messageID,
 		ChatID:       chatID,
 		MessageID:    messageID,
 		Page:         page,
-		Title:        "🏢 *Выберите аппарат:*",
+		Title:        b.loc(ctx).T("manager_items_title"),
 		ItemPrefix:   "manager_select_item:",
 		PagePrefix:   "manager_items_page:",
 		BackCallback: "",
@@ -88,13 +88,13 @@ func (b *Bot) handleManagerItemSelection(ctx context.Context, update *tgbotapi.U
 	selectedItem, ok := b.getItemByID(itemID)
 
 	if !ok {
-		b.sendMessage(callback.Message.Chat.ID, "Аппарат не найден")
+		b.sendMessage(callback.Message.Chat.ID, b.loc(ctx).T("manager_item_not_found"))
 		return
 	}
 
 	state := b.getUserState(ctx, callback.From.ID)
 	if state == nil {
-		b.sendMessage(callback.Message.Chat.ID, "Сессия устарела. Начните заново.")
+		b.sendMessage(callback.Message.Chat.ID, b.loc(ctx).T("session_expired"))
 		return
 	}
 
@@ -102,12 +102,16 @@ func (b *Bot) handleManagerItemSelection(ctx context.Context, update *tgbotapi.U
 	b.setUserState(ctx, callback.From.ID, models.StateManagerWaitingDateType, state.TempData)
 
 	// Спрашиваем тип даты (одна дата или интервал)
-	msg := tgbotapi.NewMessage(callback.Message.Chat.ID, "📅 Выберите тип бронирования:")
+	l := b.loc(ctx)
+	msg := tgbotapi.NewMessage(callback.Message.Chat.ID, l.T("manager_choose_date_type"))
 
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📅 Одна дата", "manager_single_date"),
-			tgbotapi.NewInlineKeyboardButtonData("📆 Интервал дат", "manager_date_range"),
+			tgbotapi.NewInlineKeyboardButtonData(l.T("btn_single_date"), "manager_single_date"),
+			tgbotapi.NewInlineKeyboardButtonData(l.T("btn_date_range"), "manager_date_range"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(l.T("btn_series"), "manager_series"),
 		),
 	)
 	msg.ReplyMarkup = &keyboard
@@ -128,26 +132,39 @@ func (b *Bot) handleManagerDateType(ctx context.Context, update *tgbotapi.Update
 		return
 	}
 
-	if dateType == typeSingle {
+	switch dateType {
+	case typeSingle:
 		state.TempData["date_type"] = typeSingle
 		b.setUserState(ctx, callback.From.ID, models.StateManagerWaitingSingleDate, state.TempData)
 
 		editMsg := tgbotapi.NewEditMessageText(
 			callback.Message.Chat.ID,
 			callback.Message.MessageID,
-			"📅 Введите дату бронирования в формате ДД.ММ.ГГГГ (например, 25.12.2024):",
+			b.loc(ctx).T("manager_enter_single_date"),
+		)
+		if _, err := b.tgService.Send(editMsg); err != nil {
+			b.logger.Error().Err(err).Msg("Failed to send edit message in handleManagerDateType")
+		}
+	case typeSeries:
+		state.TempData["date_type"] = typeSeries
+		b.setUserState(ctx, callback.From.ID, models.StateManagerWaitingSeriesStart, state.TempData)
+
+		editMsg := tgbotapi.NewEditMessageText(
+			callback.Message.Chat.ID,
+			callback.Message.MessageID,
+			b.loc(ctx).T("manager_enter_series_start"),
 		)
 		if _, err := b.tgService.Send(editMsg); err != nil {
 			b.logger.Error().Err(err).Msg("Failed to send edit message in handleManagerDateType")
 		}
-	} else {
+	default:
 		state.TempData["date_type"] = "range"
 		b.setUserState(ctx, callback.From.ID, models.StateManagerWaitingStartDate, state.TempData)
 
 		editMsg := tgbotapi.NewEditMessageText(
 			callback.Message.Chat.ID,
 			callback.Message.MessageID,
-			"📅 Введите начальную дату интервала в формате ДД.ММ.ГГГГ (например, 25.12.2024):",
+			b.loc(ctx).T("manager_enter_range_start"),
 		)
 		if _, err := b.tgService.Send(editMsg); err != nil {
 			b.logger.Error().Err(err).Msg("Failed to send edit message in handleManagerDateType")
@@ -163,48 +180,47 @@ func (b *Bot) handleManagerDateType(ctx context.Context, update *tgbotapi.Update
 func (b *Bot) handleManagerSingleDate(ctx context.Context, update *tgbotapi.Update, dateStr string, state *models.UserState) {
 	date, err := time.Parse("02.01.2006", dateStr)
 	if err != nil {
-		b.sendMessage(update.Message.Chat.ID, "Неверный формат даты. Используйте ДД.ММ.ГГГГ (например, 25.12.2024)")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("date_invalid_format"))
 		return
 	}
 
 	// Валидация даты через сервис
 	if err := b.bookingService.ValidateBookingDate(date); err != nil {
-		b.sendMessage(update.Message.Chat.ID, b.getErrorMessage(err))
+		b.sendMessage(update.Message.Chat.ID, b.getErrorMessage(ctx, err))
 		return
 	}
 
 	state.TempData["dates"] = []time.Time{date}
 	b.setUserState(ctx, update.Message.From.ID, models.StateManagerWaitingComment, state.TempData)
 
-	b.sendMessage(update.Message.Chat.ID, "💬 Введите комментарий к заявке "+
-		"(например: 'Техническое обслуживание', 'Обучение персонала' или любой другой текст):")
+	b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("manager_enter_comment"))
 }
 
 // handleManagerStartDate обработка ввода начальной даты интервала
 func (b *Bot) handleManagerStartDate(ctx context.Context, update *tgbotapi.Update, dateStr string, state *models.UserState) {
 	startDate, err := time.Parse("02.01.2006", dateStr)
 	if err != nil {
-		b.sendMessage(update.Message.Chat.ID, "Неверный формат даты. Используйте ДД.ММ.ГГГГ (например, 25.12.2024)")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("date_invalid_format"))
 		return
 	}
 
 	// Валидация даты через сервис
 	if err := b.bookingService.ValidateBookingDate(startDate); err != nil {
-		b.sendMessage(update.Message.Chat.ID, b.getErrorMessage(err))
+		b.sendMessage(update.Message.Chat.ID, b.getErrorMessage(ctx, err))
 		return
 	}
 
 	state.TempData["start_date"] = startDate
 	b.setUserState(ctx, update.Message.From.ID, models.StateManagerWaitingEndDate, state.TempData)
 
-	b.sendMessage(update.Message.Chat.ID, "📅 Введите конечную дату интервала в формате ДД.ММ.ГГГГ:")
+	b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("manager_enter_range_end"))
 }
 
 // handleManagerEndDate обработка ввода конечной даты интервала
 func (b *Bot) handleManagerEndDate(ctx context.Context, update *tgbotapi.Update, dateStr string, state *models.UserState) {
 	endDate, err := time.Parse("02.01.2006", dateStr)
 	if err != nil {
-		b.sendMessage(update.Message.Chat.ID, "Неверный формат даты. Используйте ДД.ММ.ГГГГ (например, 25.12.2024)")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("date_invalid_format"))
 		return
 	}
 
@@ -212,19 +228,19 @@ func (b *Bot) handleManagerEndDate(ctx context.Context, update *tgbotapi.Update,
 
 	// Проверяем, что конечная дата не раньше начальной
 	if endDate.Before(startDate) {
-		b.sendMessage(update.Message.Chat.ID, "Конечная дата не может быть раньше начальной.")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("manager_end_before_start"))
 		return
 	}
 
 	// Валидация даты через сервис
 	if err := b.bookingService.ValidateBookingDate(endDate); err != nil {
-		b.sendMessage(update.Message.Chat.ID, b.getErrorMessage(err))
+		b.sendMessage(update.Message.Chat.ID, b.getErrorMessage(ctx, err))
 		return
 	}
 
 	// Ограничиваем интервал (например, максимум 31 день за раз)
 	if endDate.Sub(startDate).Hours() > 24*31 {
-		b.sendMessage(update.Message.Chat.ID, "Максимальный интервал бронирования - 31 день.")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("manager_range_too_long"))
 		return
 	}
 
@@ -237,7 +253,7 @@ func (b *Bot) handleManagerEndDate(ctx context.Context, update *tgbotapi.Update,
 	state.TempData["dates"] = dates
 	b.setUserState(ctx, update.Message.From.ID, models.StateManagerWaitingComment, state.TempData)
 
-	b.sendMessage(update.Message.Chat.ID, fmt.Sprintf("💬 Введите комментарий к заявке (будет применен ко всем %d дням):", len(dates)))
+	b.sendMessage(update.Message.Chat.ID, b.loc(ctx).N("manager_enter_range_comment", len(dates)))
 }
 
 // handleManagerComment обработка ввода комментария
@@ -250,7 +266,7 @@ func (b *Bot) handleManagerComment(ctx context.Context, update *tgbotapi.Update,
 }
 
 // showManagerBookingConfirmation показывает подтверждение заявки менеджером
-func (b *Bot) showManagerBookingConfirmation(_ context.Context, update *tgbotapi.Update, state *models.UserState) {
+func (b *Bot) showManagerBookingConfirmation(ctx context.Context, update *tgbotapi.Update, state *models.UserState) {
 	clientName := state.TempData["client_name"].(string)
 	clientPhone := state.TempData["client_phone"].(string)
 	itemID := state.GetInt64("item_id")
@@ -259,29 +275,37 @@ func (b *Bot) showManagerBookingConfirmation(_ context.Context, update *tgbotapi
 	comment := state.TempData["comment"].(string)
 	dateType := state.TempData["date_type"].(string)
 
+	l := b.loc(ctx)
 	var message strings.Builder
-	message.WriteString("📋 *Подтверждение заявки:*\n\n")
-	message.WriteString(fmt.Sprintf("👤 *Клиент:* %s\n", clientName))
-	message.WriteString(fmt.Sprintf("📱 *Телефон:* %s\n", clientPhone))
-	message.WriteString(fmt.Sprintf("🏢 *Аппарат:* %s\n", selectedItem.Name))
-
-	if dateType == typeSingle {
-		message.WriteString(fmt.Sprintf("📅 *Дата:* %s\n", dates[0].Format("02.01.2006")))
-	} else {
-		message.WriteString(fmt.Sprintf("📅 *Интервал:* %s - %s (%d дней)\n",
-			dates[0].Format("02.01.2006"),
-			dates[len(dates)-1].Format("02.01.2006"),
-			len(dates)))
+	message.WriteString(l.T("manager_confirm_title") + "\n\n")
+	message.WriteString(l.T("manager_confirm_client", clientName) + "\n")
+	message.WriteString(l.T("manager_confirm_phone", clientPhone) + "\n")
+	message.WriteString(l.T("manager_confirm_item", selectedItem.Name) + "\n")
+
+	switch dateType {
+	case typeSingle:
+		message.WriteString(l.T("manager_confirm_date", l.Date(dates[0])) + "\n")
+	case typeSeries:
+		message.WriteString(l.N("manager_confirm_series", len(dates),
+			describeRecurrence(l, state.GetString("series_frequency"), int(state.GetInt64("series_interval"))),
+			len(dates),
+			l.Date(dates[0]),
+			l.Date(dates[len(dates)-1])) + "\n")
+	default:
+		message.WriteString(l.N("manager_confirm_range", len(dates),
+			l.Date(dates[0]),
+			l.Date(dates[len(dates)-1]),
+			len(dates)) + "\n")
 	}
 
-	message.WriteString(fmt.Sprintf("💬 *Комментарий:* %s\n\n", comment))
+	message.WriteString(l.T("manager_confirm_comment", comment) + "\n\n")
 
 	msg := tgbotapi.NewMessage(update.Message.Chat.ID, message.String())
 
 	keyboard := tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("✅ Подтвердить создание"),
-			tgbotapi.NewKeyboardButton("❌ Отмена"),
+			tgbotapi.NewKeyboardButton(l.T(btnConfirmCreate)),
+			tgbotapi.NewKeyboardButton(l.T(btnCancel)),
 		),
 	)
 	msg.ReplyMarkup = keyboard
@@ -301,6 +325,7 @@ func (b *Bot) createManagerBookings(ctx context.Context, update *tgbotapi.Update
 	dates := state.GetDates("dates")
 	comment := state.TempData["comment"].(string)
 
+	l := b.loc(ctx)
 	createdBookings := make([]*models.Booking, 0, len(dates))
 	failedDates := make([]string, 0)
 
@@ -310,12 +335,12 @@ func (b *Bot) createManagerBookings(ctx context.Context, update *tgbotapi.Update
 		available, err := b.bookingService.CheckAvailability(ctx, selectedItem.ID, date)
 		if err != nil {
 			b.logger.Error().Err(err).Int64("item_id", selectedItem.ID).Time("date", date).Msg("Error checking availability")
-			failedDates = append(failedDates, date.Format("02.01.2006"))
+			failedDates = append(failedDates, l.Date(date))
 			continue
 		}
 
 		if !available {
-			failedDates = append(failedDates, date.Format("02.01.2006"))
+			failedDates = append(failedDates, l.Date(date))
 			continue
 		}
 
@@ -338,7 +363,7 @@ func (b *Bot) createManagerBookings(ctx context.Context, update *tgbotapi.Update
 		err = b.bookingService.CreateBooking(ctx, booking)
 		if err != nil {
 			b.logger.Error().Err(err).Interface("booking", booking).Msg("Error creating manager booking")
-			failedDates = append(failedDates, fmt.Sprintf("%s (%s)", date.Format("02.01.2006"), b.getErrorMessage(err)))
+			failedDates = append(failedDates, fmt.Sprintf("%s (%s)", l.Date(date), b.getErrorMessage(ctx, err)))
 		} else {
 			createdBookings = append(createdBookings, booking)
 			// Track metrics
@@ -351,20 +376,20 @@ func (b *Bot) createManagerBookings(ctx context.Context, update *tgbotapi.Update
 
 	// Формируем отчет
 	var message strings.Builder
-	message.WriteString("📊 *Результат создания заявок:*\n\n")
+	message.WriteString(l.T("manager_result_title") + "\n\n")
 
 	if len(createdBookings) > 0 {
-		message.WriteString(fmt.Sprintf("✅ *Успешно создано:* %d заявок\n", len(createdBookings)))
+		message.WriteString(l.N("manager_result_created", len(createdBookings)) + "\n")
 		for _, booking := range createdBookings {
-			message.WriteString(fmt.Sprintf("   • %s (№%d)\n", booking.Date.Format("02.01.2006"), booking.ID))
+			message.WriteString(fmt.Sprintf("   • %s (№%d)\n", l.Date(booking.Date), booking.ID))
 		}
 		message.WriteString("\n")
 	}
 
 	if len(failedDates) > 0 {
-		message.WriteString(fmt.Sprintf("❌ *Не удалось создать:* %d заявок\n", len(failedDates)))
+		message.WriteString(l.N("manager_result_failed", len(failedDates)) + "\n")
 		for _, date := range failedDates {
-			message.WriteString(fmt.Sprintf("   • %s (недоступно)\n", date))
+			message.WriteString("   • " + l.T("manager_result_failed_date", date) + "\n")
 		}
 	}
 
@@ -400,12 +425,12 @@ func (b *Bot) sendManagerBookingsPage(ctx context.Context, chatID int64, message
 	bookings, err := b.bookingService.GetBookingsByDateRange(ctx, startDate, endDate)
 	if err != nil {
 		b.logger.Error().Err(err).Time("start_date", startDate).Time("end_date", endDate).Msg("Error getting bookings")
-		b.sendMessage(chatID, "Ошибка при получении заявок")
+		b.sendMessage(chatID, b.loc(ctx).T("error_get_bookings"))
 		return
 	}
 
 	if len(bookings) == 0 {
-		b.sendMessage(chatID, "Заявок не найдено")
+		b.sendMessage(chatID, b.loc(ctx).T("manager_no_bookings"))
 		return
 	}
 
@@ -414,7 +439,7 @@ func (b *Bot) sendManagerBookingsPage(ctx context.Context, chatID int64, message
 		ChatID:       chatID,
 		MessageID:    messageID,
 		Page:         page,
-		Title:        "📊 *Все заявки на квартал вперед:*",
+		Title:        b.loc(ctx).T("manager_bookings_title"),
 		ItemPrefix:   "show_booking:",
 		PagePrefix:   "manager_bookings_page:",
 		BackCallback: "back_to_main",
@@ -431,7 +456,7 @@ func (b *Bot) showManagerBookingDetail(ctx context.Context, update *tgbotapi.Upd
 
 	booking, err := b.bookingService.GetBooking(ctx, bookingID)
 	if err != nil {
-		b.sendMessage(update.Message.Chat.ID, "Заявка не найдена")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("manager_booking_not_found"))
 		return
 	}
 
@@ -440,13 +465,12 @@ func (b *Bot) showManagerBookingDetail(ctx context.Context, update *tgbotapi.Upd
 
 // startChangeItem начало изменения аппарата в заявке
 func (b *Bot) startChangeItem(ctx context.Context, booking *models.Booking, managerChatID int64) {
-	msg := tgbotapi.NewMessage(managerChatID,
-		"Выберите новый аппарат для заявки #"+strconv.FormatInt(booking.ID, 10)+":")
+	msg := tgbotapi.NewMessage(managerChatID, b.loc(ctx).T("manager_choose_new_item", booking.ID))
 
 	items, err := b.itemService.GetActiveItems(ctx)
 	if err != nil {
 		b.logger.Error().Err(err).Msg("Error getting active items")
-		b.sendMessage(managerChatID, "Ошибка при получении списка аппаратов")
+		b.sendMessage(managerChatID, b.loc(ctx).T("error_get_items"))
 		return
 	}
 
@@ -485,7 +509,7 @@ func (b *Bot) handleChangeItem(ctx context.Context, update *tgbotapi.Update) {
 	selectedItem, ok := b.getItemByID(itemID)
 
 	if !ok {
-		b.sendMessage(callback.Message.Chat.ID, "Аппарат не найден")
+		b.sendMessage(callback.Message.Chat.ID, b.loc(ctx).T("manager_item_not_found"))
 		return
 	}
 
@@ -493,7 +517,7 @@ func (b *Bot) handleChangeItem(ctx context.Context, update *tgbotapi.Update) {
 	booking, err := b.bookingService.GetBooking(ctx, bookingID)
 	if err != nil {
 		b.logger.Error().Err(err).Int64("booking_id", bookingID).Msg("Error getting booking")
-		b.sendMessage(callback.Message.Chat.ID, "Ошибка при получении заявки")
+		b.sendMessage(callback.Message.Chat.ID, b.loc(ctx).T("manager_error_get_booking"))
 		return
 	}
 
@@ -501,7 +525,7 @@ func (b *Bot) handleChangeItem(ctx context.Context, update *tgbotapi.Update) {
 	err = b.bookingService.ChangeBookingItem(ctx, bookingID, booking.Version, selectedItem.ID, callback.From.ID)
 	if err != nil {
 		b.logger.Error().Err(err).Int64("booking_id", bookingID).Msg("Error changing booking item")
-		b.sendMessage(callback.Message.Chat.ID, "Ошибка при изменении аппарата: "+b.getErrorMessage(err))
+		b.sendMessage(callback.Message.Chat.ID, b.loc(ctx).T("manager_error_change_item", b.getErrorMessage(ctx, err)))
 		return
 	}
 
@@ -515,12 +539,12 @@ func (b *Bot) handleChangeItem(ctx context.Context, update *tgbotapi.Update) {
 
 	// Уведомляем пользователя
 	userMsg := tgbotapi.NewMessage(booking.UserID,
-		fmt.Sprintf("🔄 В вашей заявке #%d изменен аппарат на: %s", bookingID, selectedItem.Name))
+		b.locFor(ctx, booking.UserID).T("user_booking_item_changed", bookingID, selectedItem.Name))
 	if _, errSend := b.tgService.Send(userMsg); errSend != nil {
 		b.logger.Error().Err(errSend).Msg("Failed to send user notification in handleChangeItem")
 	}
 
-	b.sendMessage(callback.Message.Chat.ID, "✅ Аппарат успешно изменен")
+	b.sendMessage(callback.Message.Chat.ID, b.loc(ctx).T("manager_item_changed"))
 
 	// ВМЕСТО ВЫЗОВА showManagerBookingDetail, который требует Message, используем sendManagerBookingDetail
 	updatedBooking, err := b.bookingService.GetBooking(ctx, bookingID)
@@ -533,65 +557,78 @@ func (b *Bot) handleChangeItem(ctx context.Context, update *tgbotapi.Update) {
 	b.sendManagerBookingDetail(ctx, callback.Message.Chat.ID, updatedBooking)
 }
 
+// bookingStatusLabel возвращает название статуса заявки
+func bookingStatusLabel(l *i18n.Localizer, status string) string {
+	if !models.IsKnownStatus(status) {
+		return status
+	}
+	return l.T("status_" + status)
+}
+
 // sendManagerBookingDetail отправляет детали заявки в указанный чат (без использования update)
-func (b *Bot) sendManagerBookingDetail(_ context.Context, chatID int64, booking *models.Booking) {
-	statusText := map[string]string{
-		models.StatusPending:   "⏳ Ожидает подтверждения",
-		models.StatusConfirmed: "✅ Подтверждена",
-		models.StatusCanceled:  "❌ Отменена",
-		models.StatusChanged:   "🔄 Изменена",
-		models.StatusCompleted: "🏁 Завершена",
-	}
-
-	message := fmt.Sprintf(`📋 Заявка #%d
-
-👤 Клиент: %s
-📱 Телефон: %s
-🏢 Позиция: %s
-📅 Дата: %s
-📊 Статус: %s
-� Комментарий: %s
-🕐 Создана: %s
-✏️ Обновлена: %s`,
+func (b *Bot) sendManagerBookingDetail(ctx context.Context, chatID int64, booking *models.Booking) {
+	l := b.loc(ctx)
+	statusText := bookingStatusLabel(l, booking.Status)
+	if !models.IsKnownStatus(booking.Status) {
+		statusText = l.T("status_unknown", booking.Status)
+	}
+
+	message := l.T("manager_booking_card",
 		booking.ID,
 		booking.UserName,
 		booking.Phone,
 		booking.ItemName,
-		booking.Date.Format("02.01.2006"),
-		statusText[booking.Status],
+		formatBookingDates(l, booking),
+		statusText,
 		booking.Comment,
-		booking.CreatedAt.Format("02.01.2006 15:04"),
-		booking.UpdatedAt.Format("02.01.2006 15:04"),
+		l.DateTime(booking.CreatedAt),
+		l.DateTime(booking.UpdatedAt),
 	)
 
+	if booking.SeriesID != nil {
+		message += "\n" + l.T("manager_booking_series", *booking.SeriesID)
+	}
+
+	if history, err := b.bookingService.GetBookingHistory(ctx, booking.ID); err != nil {
+		b.logger.Error().Err(err).Int64("booking_id", booking.ID).Msg("Failed to load booking history")
+	} else if len(history) > 0 {
+		message += "\n\n" + formatBookingHistory(l, history)
+	}
+
 	msg := tgbotapi.NewMessage(chatID, message)
 
 	// Создаем инлайн-клавиатуру для управления заявкой
-	rows := make([][]tgbotapi.InlineKeyboardButton, 0, 4)
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, 5)
 
 	if booking.Status == models.StatusPending || booking.Status == models.StatusChanged {
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("✅ Подтвердить", fmt.Sprintf("confirm_%d", booking.ID)),
-			tgbotapi.NewInlineKeyboardButtonData("❌ Отклонить", fmt.Sprintf("reject_%d", booking.ID)),
+			tgbotapi.NewInlineKeyboardButtonData(l.T("btn_confirm"), fmt.Sprintf("confirm_%d", booking.ID)),
+			tgbotapi.NewInlineKeyboardButtonData(l.T("btn_reject"), fmt.Sprintf("reject_%d", booking.ID)),
 		))
 	}
 
 	if booking.Status == models.StatusConfirmed {
 		rows = append(rows,
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("🔄 Вернуть в работу", fmt.Sprintf("reopen_%d", booking.ID)),
-				tgbotapi.NewInlineKeyboardButtonData("🏁 Завершить", fmt.Sprintf("complete_%d", booking.ID)),
+				tgbotapi.NewInlineKeyboardButtonData(l.T("btn_reopen"), fmt.Sprintf("reopen_%d", booking.ID)),
+				tgbotapi.NewInlineKeyboardButtonData(l.T("btn_complete"), fmt.Sprintf("complete_%d", booking.ID)),
 			),
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("✏️ Изменить аппарат", fmt.Sprintf("change_item_%d", booking.ID)),
-				tgbotapi.NewInlineKeyboardButtonData("🔄 Предложить выбрать другую дату", fmt.Sprintf("reschedule_%d", booking.ID)),
+				tgbotapi.NewInlineKeyboardButtonData(l.T("btn_change_item"), fmt.Sprintf("change_item_%d", booking.ID)),
+				tgbotapi.NewInlineKeyboardButtonData(l.T("btn_reschedule"), fmt.Sprintf("reschedule_%d", booking.ID)),
 			),
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("📞 Позвонить", fmt.Sprintf("call_booking:%d", booking.ID)),
+				tgbotapi.NewInlineKeyboardButtonData(l.T("btn_call"), fmt.Sprintf("call_booking:%d", booking.ID)),
 			),
 		)
 	}
 
+	if booking.SeriesID != nil {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(l.T("btn_whole_series"), fmt.Sprintf("series_show:%d", *booking.SeriesID)),
+		))
+	}
+
 	if len(rows) > 0 {
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
 		msg.ReplyMarkup = &keyboard
@@ -607,29 +644,29 @@ func (b *Bot) updateBookingStatus(ctx context.Context, booking *models.Booking,
 	var err error
 	var userMsgText, managerMsgText string
 	var logMsg string
+	l, userLoc := b.loc(ctx), b.locFor(ctx, booking.UserID)
 
 	switch action {
 	case "reopen":
 		logMsg = "Manager reopened booking"
 		err = b.bookingService.ReopenBooking(ctx, booking.ID, booking.Version, managerChatID)
-		userMsgText = fmt.Sprintf("🔄 Ваша заявка #%d возвращена в работу. Ожидайте подтверждения.", booking.ID)
-		managerMsgText = "✅ Заявка возвращена в работу"
+		userMsgText = userLoc.T("user_booking_reopened", booking.ID)
+		managerMsgText = l.T("manager_booking_reopened")
 	case "complete":
 		logMsg = "Manager completed booking"
 		err = b.bookingService.CompleteBooking(ctx, booking.ID, booking.Version, managerChatID)
-		userMsgText = fmt.Sprintf("🏁 Ваша заявка #%d завершена. Спасибо за использование наших услуг!", booking.ID)
-		managerMsgText = "✅ Заявка завершена"
+		userMsgText = userLoc.T("user_booking_completed", booking.ID)
+		managerMsgText = l.T("manager_booking_completed")
 	case "confirm":
 		logMsg = "Manager confirmed booking"
 		err = b.bookingService.ConfirmBooking(ctx, booking.ID, booking.Version, managerChatID)
-		userMsgText = fmt.Sprintf("✅ Ваша заявка на %s %s подтверждена!",
-			booking.ItemName, booking.Date.Format("02.01.2006"))
-		managerMsgText = "✅ Бронирование подтверждено"
+		userMsgText = userLoc.T("user_booking_confirmed", booking.ItemName, userLoc.Date(booking.Date))
+		managerMsgText = l.T("manager_booking_confirmed")
 	case "reject":
 		logMsg = "Manager rejected booking"
 		err = b.bookingService.RejectBooking(ctx, booking.ID, booking.Version, managerChatID)
-		userMsgText = "❌ К сожалению, ваша заявка была отклонена менеджером."
-		managerMsgText = "❌ Бронирование отменено"
+		userMsgText = userLoc.T("user_booking_rejected")
+		managerMsgText = l.T("manager_booking_rejected")
 	default:
 		return
 	}
@@ -643,10 +680,11 @@ func (b *Bot) updateBookingStatus(ctx context.Context, booking *models.Booking,
 
 	if err != nil {
 		if errors.Is(err, database.ErrConcurrentModification) {
-			b.sendMessage(managerChatID, "Заявка уже изменена. Обновите данные и попробуйте снова.")
+			b.sendMessage(managerChatID, l.T("manager_booking_changed_concurrently"))
 			return
 		}
 		b.logger.Error().Err(err).Int64("booking_id", booking.ID).Msg("Error updating booking status")
+		b.sendMessage(managerChatID, b.getErrorMessage(ctx, err))
 		return
 	}
 
@@ -681,74 +719,29 @@ func (b *Bot) rejectBooking(ctx context.Context, booking *models.Booking, manage
 	b.updateBookingStatus(ctx, booking, managerChatID, "reject")
 }
 
-// rescheduleBooking предложение выбрать другую дату
-func (b *Bot) rescheduleBooking(ctx context.Context, booking *models.Booking, managerChatID int64) {
-	b.logger.Info().
-		Int64("booking_id", booking.ID).
-		Int64("manager_id", managerChatID).
-		Int64("client_id", booking.UserID).
-		Str("item_name", booking.ItemName).
-		Msg("Manager proposed reschedule")
-
-	// Отправляем пользователю сообщение с предложением выбрать другую дату
-	userMsg := tgbotapi.NewMessage(booking.UserID,
-		fmt.Sprintf("🔄 Менеджер предложил выбрать другую дату для %s. Пожалуйста, создайте новую заявку.",
-			booking.ItemName))
-
-	keyboard := tgbotapi.NewReplyKeyboard(
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton(btnCreateBooking),
-		),
-	)
-	userMsg.ReplyMarkup = keyboard
-
-	if _, err := b.tgService.Send(userMsg); err != nil {
-		b.logger.Error().Err(err).Msg("Failed to send user msg in rescheduleBooking")
-	}
-
-	// Обновляем статус текущей заявки через сервис
-	err := b.bookingService.RescheduleBooking(ctx, booking.ID, managerChatID)
-	if err != nil {
-		b.logger.Error().Err(err).Int64("booking_id", booking.ID).Msg("Error updating booking status")
-	}
-
-	managerMsg := tgbotapi.NewMessage(managerChatID, "🔄 Пользователю предложено выбрать другую дату")
-	if _, err := b.tgService.Send(managerMsg); err != nil {
-		b.logger.Error().Err(err).Msg("Failed to send manager msg in rescheduleBooking")
-	}
-}
-
 // notifyManagers уведомление менеджеров о новой заявке
-func (b *Bot) notifyManagers(booking *models.Booking) {
-	message := fmt.Sprintf(`🆕 Новая заявка на бронирование:
-
-🏢 Позиция: %s
-📅 Дата: %s
-👤 Клиент: %s
-📱 Телефон: %s
-💬 Комментарий: %s
-🆔 ID заявки: %d`,
-		booking.ItemName,
-		booking.Date.Format("02.01.2006"),
-		booking.UserName,
-		booking.Phone,
-		booking.Comment,
-		booking.ID)
-
+func (b *Bot) notifyManagers(ctx context.Context, booking *models.Booking) {
 	for _, managerID := range b.config.Managers {
-		msg := tgbotapi.NewMessage(managerID, message)
+		l := b.locFor(ctx, managerID)
+		msg := tgbotapi.NewMessage(managerID, l.T("manager_new_booking",
+			booking.ItemName,
+			l.Date(booking.Date),
+			booking.UserName,
+			booking.Phone,
+			booking.Comment,
+			booking.ID))
 
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("✅ Подтвердить", fmt.Sprintf("confirm_%d", booking.ID)),
-				tgbotapi.NewInlineKeyboardButtonData("❌ Отклонить", fmt.Sprintf("reject_%d", booking.ID)),
+				tgbotapi.NewInlineKeyboardButtonData(l.T("btn_confirm"), fmt.Sprintf("confirm_%d", booking.ID)),
+				tgbotapi.NewInlineKeyboardButtonData(l.T("btn_reject"), fmt.Sprintf("reject_%d", booking.ID)),
 			),
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("✏️ Изменить аппарат", fmt.Sprintf("change_item_%d", booking.ID)),
-				tgbotapi.NewInlineKeyboardButtonData("🔄 Предложить другую дату", fmt.Sprintf("reschedule_%d", booking.ID)),
+				tgbotapi.NewInlineKeyboardButtonData(l.T("btn_change_item"), fmt.Sprintf("change_item_%d", booking.ID)),
+				tgbotapi.NewInlineKeyboardButtonData(l.T("btn_reschedule"), fmt.Sprintf("reschedule_%d", booking.ID)),
 			),
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("📞 Позвонить", fmt.Sprintf("call_booking:%d", booking.ID)),
+				tgbotapi.NewInlineKeyboardButtonData(l.T("btn_call"), fmt.Sprintf("call_booking:%d", booking.ID)),
 			),
 		)
 		msg.ReplyMarkup = &keyboard
@@ -770,25 +763,26 @@ func (b *Bot) handleCallButton(ctx context.Context, update *tgbotapi.Update) {
 	data := strings.TrimPrefix(callback.Data, "call_booking:")
 
 	// Парсим ID заявки
+	l := b.loc(ctx)
 	bookingID, err := strconv.ParseInt(data, 10, 64)
 	if err != nil {
-		b.sendMessage(callback.Message.Chat.ID, "❌ Ошибка: неверный формат данных заявки")
+		b.sendMessage(callback.Message.Chat.ID, l.T("call_error_bad_data"))
 		// Подтверждаем callback даже при ошибке
-		_, _ = b.tgService.Send(tgbotapi.NewCallback(callback.ID, "❌ Ошибка"))
+		_, _ = b.tgService.Send(tgbotapi.NewCallback(callback.ID, l.T("call_error")))
 		return
 	}
 
 	// Получаем заявку из базы данных
 	booking, err := b.bookingService.GetBooking(ctx, bookingID)
 	if err != nil {
-		b.sendMessage(callback.Message.Chat.ID, "❌ Заявка не найдена")
-		_, _ = b.tgService.Send(tgbotapi.NewCallback(callback.ID, "❌ Заявка не найдена"))
+		b.sendMessage(callback.Message.Chat.ID, l.T("call_booking_not_found"))
+		_, _ = b.tgService.Send(tgbotapi.NewCallback(callback.ID, l.T("call_booking_not_found")))
 		return
 	}
 
 	if booking.Phone == "" {
-		b.sendMessage(callback.Message.Chat.ID, "❌ Номер телефона не указан в заявке")
-		_, _ = b.tgService.Send(tgbotapi.NewCallback(callback.ID, "❌ Номер не указан"))
+		b.sendMessage(callback.Message.Chat.ID, l.T("call_no_phone"))
+		_, _ = b.tgService.Send(tgbotapi.NewCallback(callback.ID, l.T("call_no_phone_short")))
 		return
 	}
 
@@ -796,14 +790,14 @@ func (b *Bot) handleCallButton(ctx context.Context, update *tgbotapi.Update) {
 	formattedPhone := b.formatPhoneForDisplay(booking.Phone)
 
 	// Создаем информативное сообщение
-	message := "📞 *Информация для связи*\n\n"
-	message += fmt.Sprintf("👤 *Клиент:* %s\n", booking.UserName)
-	message += fmt.Sprintf("📱 *Телефон:* `%s`\n", formattedPhone)
-	message += fmt.Sprintf("🏢 *Аппарат:* %s\n", booking.ItemName)
-	message += fmt.Sprintf("📅 *Дата:* %s\n", booking.Date.Format("02.01.2006"))
+	message := l.T("call_title") + "\n\n"
+	message += l.T("manager_confirm_client", booking.UserName) + "\n"
+	message += l.T("call_phone", formattedPhone) + "\n"
+	message += l.T("manager_confirm_item", booking.ItemName) + "\n"
+	message += l.T("manager_confirm_date", l.Date(booking.Date)) + "\n"
 
 	if booking.Comment != "" {
-		message += fmt.Sprintf("💬 *Комментарий:* %s\n", booking.Comment)
+		message += l.T("manager_confirm_comment", booking.Comment) + "\n"
 	}
 
 	msg := tgbotapi.NewMessage(callback.Message.Chat.ID, message)
@@ -816,7 +810,7 @@ func (b *Bot) handleCallButton(ctx context.Context, update *tgbotapi.Update) {
 			tgbotapi.NewInlineKeyboardButtonURL("✉️ Telegram", fmt.Sprintf("https://t.me/%s", strings.TrimPrefix(booking.Phone, "+"))),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад к заявке", fmt.Sprintf("show_booking:%d", booking.ID)),
+			tgbotapi.NewInlineKeyboardButtonData(l.T("btn_back_to_booking"), fmt.Sprintf("show_booking:%d", booking.ID)),
 		),
 	)
 	msg.ReplyMarkup = &keyboard
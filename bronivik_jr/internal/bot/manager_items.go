@@ -2,7 +2,6 @@ package bot
 
 import (
 	"context"
-	"fmt"
 	"strconv"
 	"strings"
 
@@ -14,73 +13,73 @@ import (
 func (b *Bot) handleAddItemCommand(ctx context.Context, update *tgbotapi.Update) {
 	parts := strings.Fields(update.Message.Text)
 	if len(parts) < 3 {
-		b.sendMessage(update.Message.Chat.ID, "Использование: /add_item <название> <количество>")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("items_usage_add"))
 		return
 	}
 
 	qty, err := strconv.ParseInt(parts[len(parts)-1], 10, 64)
 	if err != nil || qty <= 0 {
-		b.sendMessage(update.Message.Chat.ID, "Количество должно быть положительным числом")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("items_invalid_quantity"))
 		return
 	}
 
 	name := b.sanitizeInput(strings.Join(parts[1:len(parts)-1], " "))
 	item := &models.Item{Name: name, TotalQuantity: qty}
 	if err := b.itemService.CreateItem(ctx, item); err != nil {
-		b.sendMessage(update.Message.Chat.ID, fmt.Sprintf("Не удалось создать аппарат: %v", err))
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("items_error_create", err))
 		return
 	}
 
 	b.sendMessage(update.Message.Chat.ID,
-		fmt.Sprintf("✅ Аппарат '%s' добавлен (кол-во: %d, порядок: %d)",
-			item.Name, item.TotalQuantity, item.SortOrder))
+		b.loc(ctx).T("items_added", item.Name, item.TotalQuantity, item.SortOrder))
 }
 
 func (b *Bot) handleEditItemCommand(ctx context.Context, update *tgbotapi.Update) {
 	parts := strings.Fields(update.Message.Text)
 	if len(parts) < 3 {
-		b.sendMessage(update.Message.Chat.ID, "Использование: /edit_item <название> <новое_количество>")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("items_usage_edit"))
 		return
 	}
 
 	qty, err := strconv.ParseInt(parts[len(parts)-1], 10, 64)
 	if err != nil || qty <= 0 {
-		b.sendMessage(update.Message.Chat.ID, "Количество должно быть положительным числом")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("items_invalid_quantity"))
 		return
 	}
 
 	name := b.sanitizeInput(strings.Join(parts[1:len(parts)-1], " "))
 	current, err := b.itemService.GetItemByName(ctx, name)
 	if err != nil {
-		b.sendMessage(update.Message.Chat.ID, fmt.Sprintf("Аппарат '%s' не найден", name))
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("items_not_found", name))
 		return
 	}
 
 	current.TotalQuantity = qty
 	if err := b.itemService.UpdateItem(ctx, current); err != nil {
-		b.sendMessage(update.Message.Chat.ID, fmt.Sprintf("Не удалось обновить аппарат: %v", err))
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("items_error_update", err))
 		return
 	}
 
-	b.sendMessage(update.Message.Chat.ID, fmt.Sprintf("✅ Аппарат '%s' обновлён (кол-во: %d)", current.Name, current.TotalQuantity))
+	b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("items_updated", current.Name, current.TotalQuantity))
 }
 
 func (b *Bot) handleListItemsCommand(ctx context.Context, update *tgbotapi.Update) {
 	items, err := b.itemService.GetActiveItems(ctx)
 	if err != nil {
-		b.sendMessage(update.Message.Chat.ID, fmt.Sprintf("Ошибка загрузки списка: %v", err))
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("items_error_list", err))
 		return
 	}
 
 	if len(items) == 0 {
-		b.sendMessage(update.Message.Chat.ID, "Активные аппараты отсутствуют")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("items_none_active"))
 		return
 	}
 
+	l := b.loc(ctx)
 	var sb strings.Builder
-	sb.WriteString("📋 Список активных аппаратов:\n")
+	sb.WriteString(l.T("items_list_title") + "\n")
 	for _, it := range items {
-		sb.WriteString(fmt.Sprintf("• %s — qty: %d, order: %d\n", it.Name, it.TotalQuantity, it.SortOrder))
+		sb.WriteString(l.T("items_list_line", it.Name, it.TotalQuantity, it.SortOrder) + "\n")
 	}
 
 	b.sendMessage(update.Message.Chat.ID, sb.String())
@@ -89,64 +88,64 @@ func (b *Bot) handleListItemsCommand(ctx context.Context, update *tgbotapi.Updat
 func (b *Bot) handleDisableItemCommand(ctx context.Context, update *tgbotapi.Update) {
 	parts := strings.Fields(update.Message.Text)
 	if len(parts) < 2 {
-		b.sendMessage(update.Message.Chat.ID, "Использование: /disable_item <название>")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("items_usage_disable"))
 		return
 	}
 
 	name := b.sanitizeInput(strings.Join(parts[1:], " "))
 	item, err := b.itemService.GetItemByName(ctx, name)
 	if err != nil {
-		b.sendMessage(update.Message.Chat.ID, fmt.Sprintf("Аппарат '%s' не найден", name))
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("items_not_found", name))
 		return
 	}
 
 	if err := b.itemService.DeactivateItem(ctx, item.ID); err != nil {
-		b.sendMessage(update.Message.Chat.ID, fmt.Sprintf("Не удалось отключить аппарат: %v", err))
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("items_error_disable", err))
 		return
 	}
 
-	b.sendMessage(update.Message.Chat.ID, fmt.Sprintf("🛑 Аппарат '%s' деактивирован", item.Name))
+	b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("items_disabled", item.Name))
 }
 
 func (b *Bot) handleSetItemOrderCommand(ctx context.Context, update *tgbotapi.Update) {
 	parts := strings.Fields(update.Message.Text)
 	if len(parts) < 3 {
-		b.sendMessage(update.Message.Chat.ID, "Использование: /set_item_order <название> <порядок>")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("items_usage_order"))
 		return
 	}
 
 	order, err := strconv.ParseInt(parts[len(parts)-1], 10, 64)
 	if err != nil || order < 1 {
-		b.sendMessage(update.Message.Chat.ID, "Порядок должен быть положительным числом")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("items_invalid_order"))
 		return
 	}
 
 	name := b.sanitizeInput(strings.Join(parts[1:len(parts)-1], " "))
 	item, err := b.itemService.GetItemByName(ctx, name)
 	if err != nil {
-		b.sendMessage(update.Message.Chat.ID, fmt.Sprintf("Аппарат '%s' не найден", name))
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("items_not_found", name))
 		return
 	}
 
 	if err := b.itemService.ReorderItem(ctx, item.ID, order); err != nil {
-		b.sendMessage(update.Message.Chat.ID, fmt.Sprintf("Не удалось изменить порядок: %v", err))
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("items_error_order", err))
 		return
 	}
 
-	b.sendMessage(update.Message.Chat.ID, fmt.Sprintf("↕️ Порядок '%s' установлен на %d", item.Name, order))
+	b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("items_order_set", item.Name, order))
 }
 
 func (b *Bot) handleMoveItemCommand(ctx context.Context, update *tgbotapi.Update, delta int64) {
 	parts := strings.Fields(update.Message.Text)
 	if len(parts) < 2 {
-		b.sendMessage(update.Message.Chat.ID, "Использование: /move_item_up|/move_item_down <название>")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("items_usage_move"))
 		return
 	}
 
 	name := strings.Join(parts[1:], " ")
 	item, err := b.itemService.GetItemByName(ctx, name)
 	if err != nil {
-		b.sendMessage(update.Message.Chat.ID, fmt.Sprintf("Аппарат '%s' не найден", name))
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("items_not_found", name))
 		return
 	}
 
@@ -156,21 +155,22 @@ func (b *Bot) handleMoveItemCommand(ctx context.Context, update *tgbotapi.Update
 	}
 
 	if err := b.itemService.ReorderItem(ctx, item.ID, newOrder); err != nil {
-		b.sendMessage(update.Message.Chat.ID, fmt.Sprintf("Не удалось изменить порядок: %v", err))
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("items_error_order", err))
 		return
 	}
 
-	direction := "вверх"
+	l := b.loc(ctx)
+	direction := l.T("items_direction_up")
 	if delta > 0 {
-		direction = "вниз"
+		direction = l.T("items_direction_down")
 	}
-	b.sendMessage(update.Message.Chat.ID, fmt.Sprintf("↕️ Аппарат '%s' перемещён %s (новый порядок: %d)", item.Name, direction, newOrder))
+	b.sendMessage(update.Message.Chat.ID, l.T("items_moved", item.Name, direction, newOrder))
 }
 
 // editManagerItemsPage редактирует страницу с аппаратами для менеджера
-func (b *Bot) editManagerItemsPage(update *tgbotapi.Update, page int) {
+func (b *Bot) editManagerItemsPage(ctx context.Context, update *tgbotapi.Update, page int) {
 	callback := update.CallbackQuery
-	b.sendManagerItemsPage(context.Background(), callback.Message.Chat.ID, callback.Message.MessageID, page)
+	b.sendManagerItemsPage(ctx, callback.Message.Chat.ID, callback.Message.MessageID, page)
 	if _, err := b.tgService.Send(tgbotapi.NewCallback(callback.ID, "")); err != nil {
 		b.logger.Error().Err(err).Msg("Failed to send callback in editManagerItemsPage")
 	}
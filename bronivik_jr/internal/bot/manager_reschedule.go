@@ -0,0 +1,153 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"bronivik/internal/events"
+	"bronivik/internal/i18n"
+	"bronivik/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// maxRescheduleDays ограничивает длину нового периода, как и при создании заявки менеджером
+const maxRescheduleDays = 31
+
+// startRescheduleBooking запрашивает у менеджера новую дату или период для заявки
+func (b *Bot) startRescheduleBooking(ctx context.Context, booking *models.Booking, managerID, chatID int64) {
+	b.setUserState(ctx, managerID, models.StateManagerWaitingReschedule, map[string]interface{}{
+		"booking_id": booking.ID,
+	})
+
+	l := b.loc(ctx)
+	msg := tgbotapi.NewMessage(chatID, l.T("reschedule_prompt", booking.ID, booking.ItemName, formatBookingDates(l, booking)))
+	msg.ReplyMarkup = tgbotapi.NewReplyKeyboard(
+		tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(l.T(btnCancel))),
+	)
+
+	if _, err := b.tgService.Send(msg); err != nil {
+		b.logger.Error().Err(err).Msg("Failed to send message in startRescheduleBooking")
+	}
+}
+
+// handleManagerRescheduleDates обработка ввода новых дат заявки
+func (b *Bot) handleManagerRescheduleDates(ctx context.Context, update *tgbotapi.Update, text string, state *models.UserState) {
+	chatID := update.Message.Chat.ID
+	managerID := update.Message.From.ID
+	l := b.loc(ctx)
+
+	if isButton(text, btnCancel) {
+		b.clearUserState(ctx, managerID)
+		b.sendMessage(chatID, l.T("reschedule_canceled"))
+		b.handleMainMenu(ctx, update)
+		return
+	}
+
+	date, endTime, err := parseRescheduleDates(text)
+	if err != nil {
+		b.sendMessage(chatID, l.T("reschedule_invalid_format"))
+		return
+	}
+	if endTime != nil && endTime.Sub(date).Hours() > 24*maxRescheduleDays {
+		b.sendMessage(chatID, l.N("reschedule_range_too_long", maxRescheduleDays))
+		return
+	}
+
+	bookingID := state.GetInt64("booking_id")
+	booking, err := b.bookingService.GetBooking(ctx, bookingID)
+	if err != nil || booking == nil {
+		b.clearUserState(ctx, managerID)
+		b.sendMessage(chatID, l.T("manager_booking_not_found"))
+		return
+	}
+	original := *booking
+	previousDates := formatBookingDates(l, &original)
+
+	err = b.bookingService.RescheduleBooking(ctx, booking.ID, booking.Version, date, endTime, managerID)
+	if err != nil {
+		b.logger.Error().Err(err).Int64("booking_id", booking.ID).Msg("Error rescheduling booking")
+		// Состояние сохраняем, чтобы менеджер мог ввести другие даты
+		b.sendMessage(chatID, b.getErrorMessage(ctx, err)+"\n\n"+l.T("reschedule_retry", l.T(btnCancel)))
+		return
+	}
+	b.clearUserState(ctx, managerID)
+
+	updated, err := b.bookingService.GetBooking(ctx, booking.ID)
+	if err != nil || updated == nil {
+		updated = booking
+		updated.Date = date
+		updated.EndTime = endTime
+	}
+	newDates := formatBookingDates(l, updated)
+
+	b.logger.Info().
+		Int64("booking_id", booking.ID).
+		Int64("manager_id", managerID).
+		Int64("client_id", booking.UserID).
+		Str("old_dates", previousDates).
+		Str("new_dates", newDates).
+		Msg("Manager rescheduled booking")
+
+	// Клиента уведомляет подписчик booking_rescheduled (NotifyBookingRescheduled)
+	b.sendMessage(chatID, l.T("reschedule_done", previousDates, newDates))
+	b.sendManagerBookingDetail(ctx, chatID, updated)
+}
+
+// NotifyBookingRescheduled сообщает клиенту прежние и новые даты заявки; подписывается
+// на booking_rescheduled, поэтому срабатывает и при переносе через REST и gRPC.
+// Ошибка отправки не возвращается диспетчеру, чтобы повтор не задел других подписчиков.
+func (b *Bot) NotifyBookingRescheduled(ev *events.Event) error {
+	var payload events.BookingEventPayload
+	if err := json.Unmarshal(ev.Payload, &payload); err != nil {
+		b.logger.Error().Err(err).Str("event", ev.Type).Msg("event bus: decode payload")
+		return nil
+	}
+	// Клиент, перенесший заявку сам, и удаленные пользователи не уведомляются
+	if payload.PreviousDate == nil || payload.UserID <= 0 || payload.UserID == payload.ChangedByID {
+		return nil
+	}
+
+	ctx := context.Background()
+	// Даты клиенту форматируем на его языке
+	l := b.locFor(ctx, payload.UserID)
+	previous := &models.Booking{Date: *payload.PreviousDate, EndTime: payload.PreviousEndTime}
+	current := &models.Booking{Date: payload.Date, EndTime: payload.EndTime}
+	msg := tgbotapi.NewMessage(payload.UserID, l.T("user_booking_rescheduled",
+		payload.BookingID, payload.ItemName, formatBookingDates(l, previous), formatBookingDates(l, current)))
+	if _, err := b.tgService.Send(msg); err != nil {
+		b.logger.Error().Err(err).Int64("user_id", payload.UserID).Msg("Failed to notify user about reschedule")
+	}
+	return nil
+}
+
+// parseRescheduleDates разбирает "ДД.ММ.ГГГГ" или "ДД.ММ.ГГГГ-ДД.ММ.ГГГГ"
+func parseRescheduleDates(text string) (time.Time, *time.Time, error) {
+	parts := strings.SplitN(text, "-", 2)
+	date, err := time.Parse("02.01.2006", strings.TrimSpace(parts[0]))
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	if len(parts) == 1 {
+		return date, nil, nil
+	}
+
+	endTime, err := time.Parse("02.01.2006", strings.TrimSpace(parts[1]))
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	if endTime.Equal(date) {
+		return date, nil, nil
+	}
+	return date, &endTime, nil
+}
+
+// formatBookingDates возвращает дату заявки или период для диапазонных бронирований
+func formatBookingDates(l *i18n.Localizer, booking *models.Booking) string {
+	if booking.IsRangeBooking() {
+		return l.Date(booking.Date) + " — " + l.Date(*booking.EndTime)
+	}
+	return l.Date(booking.Date)
+}
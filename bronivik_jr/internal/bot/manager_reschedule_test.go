@@ -0,0 +1,109 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"bronivik/internal/events"
+	"bronivik/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRescheduleDates(t *testing.T) {
+	date, end, err := parseRescheduleDates("25.12.2025")
+	require.NoError(t, err)
+	assert.Equal(t, "2025-12-25", date.Format("2006-01-02"))
+	assert.Nil(t, end)
+
+	date, end, err = parseRescheduleDates("25.12.2025 - 27.12.2025")
+	require.NoError(t, err)
+	assert.Equal(t, "2025-12-25", date.Format("2006-01-02"))
+	require.NotNil(t, end)
+	assert.Equal(t, "2025-12-27", end.Format("2006-01-02"))
+
+	_, end, err = parseRescheduleDates("25.12.2025-25.12.2025")
+	require.NoError(t, err)
+	assert.Nil(t, end)
+
+	_, _, err = parseRescheduleDates("2025-12-25")
+	assert.Error(t, err)
+}
+
+func TestManagerReschedule_LeavesNotificationToEvent(t *testing.T) {
+	b, mocks := setupTestBot()
+	ctx := context.Background()
+	managerID := int64(123)
+	clientID := int64(456)
+
+	oldDate := time.Now().AddDate(0, 0, 2).Truncate(24 * time.Hour)
+	booking := &models.Booking{ID: 1, UserID: clientID, ItemName: "Item 1", Date: oldDate, Status: models.StatusConfirmed}
+	mocks.booking.setBookings(map[int64]*models.Booking{1: booking})
+
+	b.startRescheduleBooking(ctx, booking, managerID, managerID)
+	state := b.getUserState(ctx, managerID)
+	require.NotNil(t, state)
+	assert.Equal(t, models.StateManagerWaitingReschedule, state.CurrentStep)
+
+	newStart := oldDate.AddDate(0, 0, 7)
+	newEnd := newStart.AddDate(0, 0, 2)
+	update := tgbotapi.Update{Message: &tgbotapi.Message{
+		From: &tgbotapi.User{ID: managerID},
+		Chat: &tgbotapi.Chat{ID: managerID},
+	}}
+	input := newStart.Format("02.01.2006") + "-" + newEnd.Format("02.01.2006")
+	require.True(t, b.handleManagerStateCommands(ctx, &update, input, state))
+
+	require.NotNil(t, booking.EndTime)
+	assert.Equal(t, newEnd.Format("2006-01-02"), booking.EndTime.Format("2006-01-02"))
+
+	// Клиенту пишет подписчик booking_rescheduled, а не обработчик менеджера
+	for _, c := range mocks.tg.sentMessages {
+		if msg, ok := c.(tgbotapi.MessageConfig); ok {
+			assert.NotEqual(t, clientID, msg.ChatID)
+		}
+	}
+}
+
+func TestNotifyBookingRescheduled(t *testing.T) {
+	b, mocks := setupTestBot()
+	managerID := int64(123)
+	clientID := int64(456)
+
+	oldDate := time.Now().AddDate(0, 0, 2).Truncate(24 * time.Hour)
+	newStart := oldDate.AddDate(0, 0, 7)
+	newEnd := newStart.AddDate(0, 0, 2)
+	event := func(changedByID int64) *events.Event {
+		payload, err := json.Marshal(events.BookingEventPayload{
+			BookingID: 1, UserID: clientID, ItemName: "Item 1", Date: newStart, EndTime: &newEnd,
+			ChangedBy: string(models.ActorAPI), ChangedByID: changedByID, PreviousDate: &oldDate,
+		})
+		require.NoError(t, err)
+		return &events.Event{ID: 1, Type: events.EventBookingRescheduled, Payload: payload}
+	}
+	clientMessages := func() []string {
+		var texts []string
+		for _, c := range mocks.tg.sentMessages {
+			if msg, ok := c.(tgbotapi.MessageConfig); ok && msg.ChatID == clientID {
+				texts = append(texts, msg.Text)
+			}
+		}
+		return texts
+	}
+
+	// Перенос через API или менеджером: клиент получает прежние и новые даты
+	require.NoError(t, b.NotifyBookingRescheduled(event(0)))
+	require.NoError(t, b.NotifyBookingRescheduled(event(managerID)))
+	texts := clientMessages()
+	require.Len(t, texts, 2)
+	assert.Contains(t, texts[0], "Было: "+oldDate.Format("02.01.2006"))
+	assert.Contains(t, texts[0], "Стало: "+newStart.Format("02.01.2006")+" — "+newEnd.Format("02.01.2006"))
+
+	// Клиент, перенесший заявку сам, уведомление не получает
+	require.NoError(t, b.NotifyBookingRescheduled(event(clientID)))
+	assert.Len(t, clientMessages(), 2)
+}
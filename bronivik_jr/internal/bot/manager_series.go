@@ -0,0 +1,425 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"bronivik/internal/database"
+	"bronivik/internal/i18n"
+	"bronivik/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	typeSeries = "series"
+
+	// seriesDetailMaxDates ограничивает число дат в карточке серии
+	seriesDetailMaxDates = 15
+)
+
+// handleSeriesCallback обрабатывает callback-запросы, связанные с сериями бронирований
+func (b *Bot) handleSeriesCallback(ctx context.Context, update *tgbotapi.Update, data string) bool {
+	callback := update.CallbackQuery
+	chatID := callback.Message.Chat.ID
+
+	switch {
+	case data == "manager_series":
+		b.handleManagerDateType(ctx, update, typeSeries)
+
+	case strings.HasPrefix(data, "manager_series_freq:"):
+		b.handleManagerSeriesFrequency(ctx, update, strings.TrimPrefix(data, "manager_series_freq:"))
+
+	case strings.HasPrefix(data, "series_show:"):
+		if seriesID, ok := parseSeriesID(data, "series_show:"); ok {
+			b.sendManagerSeriesDetail(ctx, chatID, seriesID)
+		}
+
+	case strings.HasPrefix(data, "series_confirm:"):
+		if seriesID, ok := parseSeriesID(data, "series_confirm:"); ok {
+			result, err := b.bookingService.ConfirmSeries(ctx, seriesID, callback.From.ID)
+			b.reportSeriesAction(ctx, chatID, seriesID, "confirm", result, err)
+		}
+
+	case strings.HasPrefix(data, "series_cancel:"):
+		if seriesID, ok := parseSeriesID(data, "series_cancel:"); ok {
+			result, err := b.bookingService.CancelSeries(ctx, seriesID, callback.From.ID)
+			b.reportSeriesAction(ctx, chatID, seriesID, "cancel", result, err)
+		}
+
+	case strings.HasPrefix(data, "series_change_item:"):
+		if seriesID, ok := parseSeriesID(data, "series_change_item:"); ok {
+			b.startChangeSeriesItem(ctx, chatID, seriesID)
+		}
+
+	case strings.HasPrefix(data, "series_change_to:"):
+		var seriesID, itemID int64
+		if _, err := fmt.Sscanf(data, "series_change_to:%d:%d", &seriesID, &itemID); err != nil {
+			return true
+		}
+		result, err := b.bookingService.ChangeSeriesItem(ctx, seriesID, itemID, callback.From.ID)
+		b.reportSeriesAction(ctx, chatID, seriesID, "change_item", result, err)
+
+	default:
+		return false
+	}
+	return true
+}
+
+func parseSeriesID(data, prefix string) (int64, bool) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(data, prefix), 10, 64)
+	return id, err == nil
+}
+
+// handleManagerSeriesStart обработка ввода даты первого бронирования серии
+func (b *Bot) handleManagerSeriesStart(ctx context.Context, update *tgbotapi.Update, dateStr string, state *models.UserState) {
+	startDate, err := time.Parse("02.01.2006", dateStr)
+	if err != nil {
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("date_invalid_format"))
+		return
+	}
+
+	if err := b.bookingService.ValidateBookingDate(startDate); err != nil {
+		b.sendMessage(update.Message.Chat.ID, b.getErrorMessage(ctx, err))
+		return
+	}
+
+	state.TempData["start_date"] = startDate
+	b.setUserState(ctx, update.Message.From.ID, models.StateManagerWaitingSeriesRule, state.TempData)
+
+	l := b.loc(ctx)
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, l.T("series_choose_frequency"))
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(l.T("btn_series_weekly"), "manager_series_freq:weekly:1"),
+			tgbotapi.NewInlineKeyboardButtonData(l.T("btn_series_biweekly"), "manager_series_freq:weekly:2"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(l.T("btn_series_monthly"), "manager_series_freq:monthly:1"),
+		),
+	)
+	msg.ReplyMarkup = &keyboard
+
+	if _, err := b.tgService.Send(msg); err != nil {
+		b.logger.Error().Err(err).Msg("Failed to send message in handleManagerSeriesStart")
+	}
+}
+
+// handleManagerSeriesFrequency обработка выбора частоты повторения
+func (b *Bot) handleManagerSeriesFrequency(ctx context.Context, update *tgbotapi.Update, value string) {
+	callback := update.CallbackQuery
+	state := b.getUserState(ctx, callback.From.ID)
+	if state == nil || state.CurrentStep != models.StateManagerWaitingSeriesRule {
+		b.sendMessage(callback.Message.Chat.ID, b.loc(ctx).T("session_expired"))
+		return
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) != 2 {
+		return
+	}
+	interval, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return
+	}
+
+	state.TempData["series_frequency"] = parts[0]
+	state.TempData["series_interval"] = interval
+	b.setUserState(ctx, callback.From.ID, models.StateManagerWaitingSeriesEnd, state.TempData)
+
+	editMsg := tgbotapi.NewEditMessageText(
+		callback.Message.Chat.ID,
+		callback.Message.MessageID,
+		b.loc(ctx).T("series_enter_end", describeRecurrence(b.loc(ctx), parts[0], interval)),
+	)
+	if _, err := b.tgService.Send(editMsg); err != nil {
+		b.logger.Error().Err(err).Msg("Failed to send edit message in handleManagerSeriesFrequency")
+	}
+}
+
+// handleManagerSeriesEnd обработка ввода количества повторений или даты окончания
+func (b *Bot) handleManagerSeriesEnd(ctx context.Context, update *tgbotapi.Update, text string, state *models.UserState) {
+	rule := models.RecurrenceRule{
+		Frequency: state.GetString("series_frequency"),
+		Interval:  int(state.GetInt64("series_interval")),
+	}
+
+	text = strings.TrimSpace(text)
+	if count, err := strconv.Atoi(text); err == nil {
+		rule.Count = count
+	} else if until, err := time.Parse("02.01.2006", text); err == nil {
+		rule.Until = &until
+	} else {
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("series_invalid_end"))
+		return
+	}
+
+	dates, err := rule.Occurrences(state.GetTime("start_date"))
+	if err != nil {
+		b.sendMessage(update.Message.Chat.ID, b.getErrorMessage(ctx, err))
+		return
+	}
+
+	delete(state.TempData, "series_count")
+	delete(state.TempData, "series_until")
+	if rule.Count > 0 {
+		state.TempData["series_count"] = rule.Count
+	} else {
+		state.TempData["series_until"] = *rule.Until
+	}
+	state.TempData["dates"] = dates
+	b.setUserState(ctx, update.Message.From.ID, models.StateManagerWaitingComment, state.TempData)
+
+	b.sendMessage(update.Message.Chat.ID, b.loc(ctx).N("series_enter_comment", len(dates)))
+}
+
+// seriesRuleFromState восстанавливает правило повторения из состояния
+func seriesRuleFromState(state *models.UserState) models.RecurrenceRule {
+	rule := models.RecurrenceRule{
+		Frequency: state.GetString("series_frequency"),
+		Interval:  int(state.GetInt64("series_interval")),
+		Count:     int(state.GetInt64("series_count")),
+	}
+	if until := state.GetTime("series_until"); !until.IsZero() {
+		rule.Until = &until
+	}
+	return rule
+}
+
+// createManagerSeries создает серию подтвержденных бронирований от имени менеджера
+func (b *Bot) createManagerSeries(ctx context.Context, update *tgbotapi.Update, state *models.UserState) {
+	itemID := state.GetInt64("item_id")
+	selectedItem, _ := b.getItemByID(itemID)
+
+	template := &models.Booking{
+		UserID:       update.Message.From.ID, // ID менеджера
+		UserName:     state.GetString("client_name"),
+		UserNickname: state.GetString("client_name"),
+		Phone:        state.GetString("client_phone"),
+		ItemID:       selectedItem.ID,
+		ItemName:     selectedItem.Name,
+		Date:         state.GetTime("start_date"),
+		Status:       models.StatusConfirmed,
+		Comment:      state.GetString("comment"),
+	}
+
+	l := b.loc(ctx)
+	result, err := b.bookingService.CreateBookingSeries(ctx, template, seriesRuleFromState(state))
+	if err != nil && result == nil {
+		b.logger.Error().Err(err).Int64("item_id", itemID).Msg("Error creating booking series")
+		b.sendMessage(update.Message.Chat.ID, l.T("series_error_create", b.getErrorMessage(ctx, err)))
+		b.clearUserState(ctx, update.Message.From.ID)
+		b.handleMainMenu(ctx, update)
+		return
+	}
+
+	var message strings.Builder
+	message.WriteString(l.T("series_result_title") + "\n\n")
+	if result.Series.ID != 0 {
+		message.WriteString(l.T("series_result_series", result.Series.ID,
+			describeRecurrence(l, result.Series.Frequency, result.Series.Interval)) + "\n\n")
+	}
+
+	if len(result.Bookings) > 0 {
+		message.WriteString(l.N("manager_result_created", len(result.Bookings)) + "\n")
+		for _, booking := range result.Bookings {
+			message.WriteString(fmt.Sprintf("   • %s (№%d)\n", l.Date(booking.Date), booking.ID))
+		}
+		message.WriteString("\n")
+		if b.metrics != nil {
+			b.metrics.BookingsCreated.WithLabelValues(selectedItem.Name).Add(float64(len(result.Bookings)))
+		}
+	}
+	writeSeriesConflicts(l, &message, l.T("series_not_created"), result.Conflicts)
+
+	b.sendMessage(update.Message.Chat.ID, message.String())
+	b.clearUserState(ctx, update.Message.From.ID)
+	b.handleMainMenu(ctx, update)
+}
+
+// sendManagerSeriesDetail отправляет карточку серии с действиями над всей серией
+func (b *Bot) sendManagerSeriesDetail(ctx context.Context, chatID, seriesID int64) {
+	series, bookings, err := b.bookingService.GetBookingSeries(ctx, seriesID)
+	if err != nil {
+		b.logger.Error().Err(err).Int64("series_id", seriesID).Msg("Error getting booking series")
+		b.sendMessage(chatID, b.loc(ctx).T("series_not_found"))
+		return
+	}
+
+	l := b.loc(ctx)
+	var message strings.Builder
+	message.WriteString(l.T("series_card",
+		series.ID,
+		series.UserName,
+		series.Phone,
+		series.ItemName,
+		describeRecurrence(l, series.Frequency, series.Interval),
+		l.T("series_status_"+series.Status),
+		len(bookings)) + "\n")
+
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, 4)
+	today := time.Now().Truncate(24 * time.Hour)
+	var dateButtons []tgbotapi.InlineKeyboardButton
+	for i, booking := range bookings {
+		if i == seriesDetailMaxDates {
+			message.WriteString("   " + l.T("series_more_dates", len(bookings)-i) + "\n")
+			break
+		}
+		message.WriteString(fmt.Sprintf("   • %s — %s (№%d)\n",
+			l.Date(booking.Date), booking.Status, booking.ID))
+		if !booking.Date.Before(today) {
+			dateButtons = append(dateButtons, tgbotapi.NewInlineKeyboardButtonData(
+				l.DayMonth(booking.Date), fmt.Sprintf("show_booking:%d", booking.ID)))
+		}
+	}
+
+	// Кнопки для перехода к отдельным датам, по 3 в ряд
+	for i := 0; i < len(dateButtons); i += 3 {
+		end := min(i+3, len(dateButtons))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(dateButtons[i:end]...))
+	}
+
+	if series.Status == models.SeriesStatusActive {
+		rows = append(rows,
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(l.T("btn_series_confirm"), fmt.Sprintf("series_confirm:%d", series.ID)),
+				tgbotapi.NewInlineKeyboardButtonData(l.T("btn_series_cancel"), fmt.Sprintf("series_cancel:%d", series.ID)),
+			),
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(l.T("btn_series_change_item"), fmt.Sprintf("series_change_item:%d", series.ID)),
+			),
+		)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, message.String())
+	if len(rows) > 0 {
+		keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+		msg.ReplyMarkup = &keyboard
+	}
+	if _, err := b.tgService.Send(msg); err != nil {
+		b.logger.Error().Err(err).Msg("Failed to send message in sendManagerSeriesDetail")
+	}
+}
+
+// startChangeSeriesItem предлагает выбрать новый аппарат для всей серии
+func (b *Bot) startChangeSeriesItem(ctx context.Context, chatID, seriesID int64) {
+	items, err := b.itemService.GetActiveItems(ctx)
+	if err != nil {
+		b.logger.Error().Err(err).Msg("Error getting active items")
+		b.sendMessage(chatID, b.loc(ctx).T("error_get_items"))
+		return
+	}
+
+	keyboardRows := make([][]tgbotapi.InlineKeyboardButton, 0, len(items))
+	for _, item := range items {
+		keyboardRows = append(keyboardRows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(item.Name, fmt.Sprintf("series_change_to:%d:%d", seriesID, item.ID)),
+		))
+	}
+
+	msg := tgbotapi.NewMessage(chatID, b.loc(ctx).T("series_choose_new_item", seriesID))
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(keyboardRows...)
+	msg.ReplyMarkup = &keyboard
+
+	if _, err := b.tgService.Send(msg); err != nil {
+		b.logger.Error().Err(err).Msg("Failed to send message in startChangeSeriesItem")
+	}
+}
+
+// reportSeriesAction отправляет менеджеру отчет по датам и уведомляет клиента
+func (b *Bot) reportSeriesAction(
+	ctx context.Context,
+	chatID, seriesID int64,
+	action string,
+	result *models.SeriesResult,
+	err error,
+) {
+	l := b.loc(ctx)
+	if err != nil {
+		b.logger.Error().Err(err).Int64("series_id", seriesID).Str("action", action).Msg("Error updating booking series")
+		b.sendMessage(chatID, l.T("series_error_update", b.getErrorMessage(ctx, err)))
+		return
+	}
+
+	userLoc := b.locFor(ctx, result.Series.UserID)
+	var title, userTitle string
+	switch action {
+	case "confirm":
+		title = l.T("series_confirmed")
+		userTitle = userLoc.T("user_series_confirmed", result.Series.ItemName)
+	case "cancel":
+		title = l.T("series_canceled")
+		userTitle = userLoc.T("user_series_canceled", result.Series.ItemName)
+	case "change_item":
+		title = l.T("series_item_changed", result.Series.ItemName)
+		userTitle = userLoc.T("user_series_item_changed", result.Series.ItemName)
+	}
+
+	b.logger.Info().
+		Int64("series_id", seriesID).
+		Int64("manager_id", chatID).
+		Str("action", action).
+		Int("updated", len(result.Bookings)).
+		Int("conflicts", len(result.Conflicts)).
+		Msg("Manager updated booking series")
+
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("%s (#%d)\n\n", title, seriesID))
+	message.WriteString(l.T("series_updated_dates", len(result.Bookings)) + "\n")
+	writeSeriesConflicts(l, &message, l.T("series_not_updated"), result.Conflicts)
+	b.sendMessage(chatID, message.String())
+
+	if len(result.Bookings) > 0 && result.Series.UserID != chatID {
+		var userMsg strings.Builder
+		userMsg.WriteString(userTitle + "\n")
+		for _, booking := range result.Bookings {
+			userMsg.WriteString(fmt.Sprintf("   • %s\n", userLoc.Date(booking.Date)))
+		}
+		b.sendMessage(result.Series.UserID, userMsg.String())
+	}
+
+	b.sendManagerSeriesDetail(ctx, chatID, seriesID)
+}
+
+func writeSeriesConflicts(l *i18n.Localizer, message *strings.Builder, title string, conflicts []models.SeriesConflict) {
+	if len(conflicts) == 0 {
+		return
+	}
+	message.WriteString(fmt.Sprintf("%s %d\n", title, len(conflicts)))
+	for _, c := range conflicts {
+		message.WriteString(fmt.Sprintf("   • %s (%s)\n", l.Date(c.Date), seriesConflictReason(l, c.Reason)))
+	}
+}
+
+// seriesConflictReason переводит причину конфликта в короткий текст для менеджера
+func seriesConflictReason(l *i18n.Localizer, reason string) string {
+	switch reason {
+	case database.ErrNotAvailable.Error():
+		return l.T("series_conflict_not_available")
+	case database.ErrPastDate.Error():
+		return l.T("series_conflict_past_date")
+	case database.ErrDateTooFar.Error():
+		return l.T("series_conflict_too_far")
+	case database.ErrConcurrentModification.Error():
+		return l.T("series_conflict_version")
+	default:
+		return reason
+	}
+}
+
+// describeRecurrence возвращает человекочитаемое описание правила повторения
+func describeRecurrence(l *i18n.Localizer, frequency string, interval int) string {
+	if interval <= 1 {
+		if frequency == models.RecurrenceMonthly {
+			return l.T("recurrence_monthly")
+		}
+		return l.T("recurrence_weekly")
+	}
+	if frequency == models.RecurrenceMonthly {
+		return l.T("recurrence_every_months", interval)
+	}
+	return l.T("recurrence_every_weeks", interval)
+}
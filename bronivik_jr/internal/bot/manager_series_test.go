@@ -0,0 +1,183 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"bronivik/internal/database"
+	"bronivik/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func (m *mockBookingService) CreateBookingSeries(
+	ctx context.Context,
+	template *models.Booking,
+	rule models.RecurrenceRule,
+) (*models.SeriesResult, error) {
+	args := m.Called(ctx, template, rule)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SeriesResult), args.Error(1)
+}
+
+func (m *mockBookingService) GetBookingSeries(ctx context.Context, seriesID int64) (*models.BookingSeries, []*models.Booking, error) {
+	args := m.Called(ctx, seriesID)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*models.BookingSeries), args.Get(1).([]*models.Booking), args.Error(2)
+}
+
+func (m *mockBookingService) CancelSeries(ctx context.Context, seriesID, managerID int64) (*models.SeriesResult, error) {
+	args := m.Called(ctx, seriesID, managerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SeriesResult), args.Error(1)
+}
+
+func lastSentText(mocks *botMocks) string {
+	for i := len(mocks.tg.sentMessages) - 1; i >= 0; i-- {
+		if msg, ok := mocks.tg.sentMessages[i].(tgbotapi.MessageConfig); ok {
+			return msg.Text
+		}
+	}
+	return ""
+}
+
+func TestManagerBookingFlow_Series(t *testing.T) {
+	b, mocks := setupTestBot()
+	ctx := context.Background()
+	managerID := int64(123)
+
+	b.setUserState(ctx, managerID, models.StateManagerWaitingDateType, map[string]interface{}{
+		"item_id":      int64(1),
+		"client_name":  "John Doe",
+		"client_phone": "79991234567",
+	})
+
+	update := tgbotapi.Update{
+		Message: &tgbotapi.Message{
+			From: &tgbotapi.User{ID: managerID},
+			Chat: &tgbotapi.Chat{ID: managerID},
+		},
+		CallbackQuery: &tgbotapi.CallbackQuery{
+			From: &tgbotapi.User{ID: managerID},
+			Message: &tgbotapi.Message{
+				Chat:      &tgbotapi.Chat{ID: managerID},
+				MessageID: 456,
+			},
+		},
+	}
+
+	// 1. Выбор серии
+	require.True(t, b.handleManagerMiscCallbacks(ctx, &update, "manager_series"))
+	state := b.getUserState(ctx, managerID)
+	assert.Equal(t, models.StateManagerWaitingSeriesStart, state.CurrentStep)
+
+	// 2. Дата начала
+	start := time.Now().AddDate(0, 0, 7).Truncate(24 * time.Hour)
+	b.handleManagerSeriesStart(ctx, &update, start.Format("02.01.2006"), state)
+	state = b.getUserState(ctx, managerID)
+	assert.Equal(t, models.StateManagerWaitingSeriesRule, state.CurrentStep)
+
+	// 3. Частота
+	require.True(t, b.handleManagerMiscCallbacks(ctx, &update, "manager_series_freq:weekly:2"))
+	state = b.getUserState(ctx, managerID)
+	assert.Equal(t, models.StateManagerWaitingSeriesEnd, state.CurrentStep)
+
+	// 4. Количество повторений
+	b.handleManagerSeriesEnd(ctx, &update, "3", state)
+	state = b.getUserState(ctx, managerID)
+	assert.Equal(t, models.StateManagerWaitingComment, state.CurrentStep)
+	assert.Len(t, state.GetDates("dates"), 3)
+
+	// 5. Комментарий и создание
+	b.handleManagerComment(ctx, &update, "Series comment", state)
+	state = b.getUserState(ctx, managerID)
+	assert.Equal(t, models.StateManagerConfirmBooking, state.CurrentStep)
+
+	series := &models.BookingSeries{ID: 9, Frequency: models.RecurrenceWeekly, Interval: 2}
+	mocks.booking.On("CreateBookingSeries", mock.Anything, mock.MatchedBy(func(tpl *models.Booking) bool {
+		return tpl.Status == models.StatusConfirmed && tpl.Comment == "Series comment" && tpl.Date.Equal(start)
+	}), models.RecurrenceRule{Frequency: models.RecurrenceWeekly, Interval: 2, Count: 3}).Return(&models.SeriesResult{
+		Series:    series,
+		Bookings:  []*models.Booking{{ID: 1, Date: start}, {ID: 2, Date: start.AddDate(0, 0, 28)}},
+		Conflicts: []models.SeriesConflict{{Date: start.AddDate(0, 0, 14), Reason: database.ErrNotAvailable.Error()}},
+	}, nil).Once()
+
+	update.Message.Text = messages.Localizer("ru").T(btnConfirmCreate)
+	require.True(t, b.handleManagerStateCommands(ctx, &update, update.Message.Text, state))
+	mocks.booking.AssertExpectations(t)
+
+	var report string
+	for _, c := range mocks.tg.sentMessages {
+		if msg, ok := c.(tgbotapi.MessageConfig); ok && strings.Contains(msg.Text, "Серия #9") {
+			report = msg.Text
+		}
+	}
+	require.NotEmpty(t, report)
+	assert.Contains(t, report, start.AddDate(0, 0, 14).Format("02.01.2006")+" (занято)")
+}
+
+func TestManagerSeries_InvalidEnd(t *testing.T) {
+	b, mocks := setupTestBot()
+	ctx := context.Background()
+	managerID := int64(123)
+
+	b.setUserState(ctx, managerID, models.StateManagerWaitingSeriesEnd, map[string]interface{}{
+		"start_date":       time.Now().AddDate(0, 0, 7),
+		"series_frequency": models.RecurrenceWeekly,
+		"series_interval":  1,
+	})
+	state := b.getUserState(ctx, managerID)
+	update := tgbotapi.Update{Message: &tgbotapi.Message{
+		From: &tgbotapi.User{ID: managerID},
+		Chat: &tgbotapi.Chat{ID: managerID},
+	}}
+
+	b.handleManagerSeriesEnd(ctx, &update, "500", state)
+	state = b.getUserState(ctx, managerID)
+	assert.Equal(t, models.StateManagerWaitingSeriesEnd, state.CurrentStep)
+	assert.Contains(t, lastSentText(mocks), "Слишком много дат")
+}
+
+func TestManagerSeries_Cancel(t *testing.T) {
+	b, mocks := setupTestBot()
+	ctx := context.Background()
+	managerID := int64(123)
+	clientID := int64(777)
+
+	date := time.Now().AddDate(0, 0, 7).Truncate(24 * time.Hour)
+	series := &models.BookingSeries{ID: 4, UserID: clientID, ItemName: "Item 1", Status: models.SeriesStatusCanceled}
+	mocks.booking.On("CancelSeries", mock.Anything, int64(4), managerID).Return(&models.SeriesResult{
+		Series:   series,
+		Bookings: []*models.Booking{{ID: 10, Date: date}},
+	}, nil).Once()
+	mocks.booking.On("GetBookingSeries", mock.Anything, int64(4)).
+		Return(series, []*models.Booking{{ID: 10, Date: date, Status: models.StatusCanceled}}, nil).Once()
+
+	update := tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: managerID},
+		Message: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: managerID}, MessageID: 1},
+		Data:    "series_cancel:4",
+	}}
+	require.True(t, b.handleManagerCallback(ctx, &update))
+	mocks.booking.AssertExpectations(t)
+
+	var notifiedClient bool
+	for _, c := range mocks.tg.sentMessages {
+		if msg, ok := c.(tgbotapi.MessageConfig); ok && msg.ChatID == clientID {
+			notifiedClient = strings.Contains(msg.Text, date.Format("02.01.2006"))
+		}
+	}
+	assert.True(t, notifiedClient)
+}
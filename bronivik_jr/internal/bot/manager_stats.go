@@ -23,7 +23,7 @@ func (b *Bot) getUserStats(ctx context.Context, update *tgbotapi.Update) {
 	allUsers, err := b.userService.GetAllUsers(ctx)
 	if err != nil {
 		b.logger.Error().Err(err).Msg("Error getting all users")
-		b.sendMessage(update.Message.Chat.ID, "Ошибка при получении данных")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("stats_error_get_data"))
 		return
 	}
 
@@ -38,17 +38,14 @@ func (b *Bot) getUserStats(ctx context.Context, update *tgbotapi.Update) {
 	}
 
 	// Формируем сообщение со статистикой
+	l := b.loc(ctx)
 	var message strings.Builder
-	message.WriteString("📊 *Статистика*\n\n")
+	message.WriteString(l.T("stats_title") + "\n\n")
 
 	// Пользователи
-	message.WriteString("👥 *Пользователи*\n")
-	message.WriteString(fmt.Sprintf("Всего: *%d*\n", len(allUsers)))
-	message.WriteString(fmt.Sprintf("Активных (30д): *%d*\n", len(activeUsers)))
-	message.WriteString(fmt.Sprintf("Менеджеров: *%d*\n", len(managers)))
-	message.WriteString(fmt.Sprintf("В черном списке: *%d*\n\n", blacklistedCount))
+	message.WriteString(l.T("stats_users", len(allUsers), len(activeUsers), len(managers), blacklistedCount) + "\n\n")
 
-	message.WriteString("Последние пользователи:\n")
+	message.WriteString(l.T("stats_recent_users") + "\n")
 	count := 5
 	if len(allUsers) < count {
 		count = len(allUsers)
@@ -66,7 +63,7 @@ func (b *Bot) getUserStats(ctx context.Context, update *tgbotapi.Update) {
 			emoji,
 			user.FirstName,
 			user.LastName,
-			user.LastActivity.Format("02.01.2006")))
+			l.Date(user.LastActivity)))
 	}
 	message.WriteString("\n")
 
@@ -78,12 +75,12 @@ func (b *Bot) getUserStats(ctx context.Context, update *tgbotapi.Update) {
 		start time.Time
 		end   time.Time
 	}{
-		{"Сегодня", today, today},
-		{"7 дней", today.AddDate(0, 0, -6), today},
-		{"30 дней", today.AddDate(0, 0, -29), today},
+		{l.T("stats_period_today"), today, today},
+		{l.N("stats_period_days", 7), today.AddDate(0, 0, -6), today},
+		{l.N("stats_period_days", 30), today.AddDate(0, 0, -29), today},
 	}
 
-	message.WriteString("📅 *Бронирования*\n")
+	message.WriteString(l.T("stats_bookings") + "\n")
 	for _, p := range periods {
 		summary := b.bookingSummary(ctx, p.start, p.end)
 		message.WriteString(fmt.Sprintf("%s: %s\n", p.label, summary))
@@ -94,7 +91,7 @@ func (b *Bot) getUserStats(ctx context.Context, update *tgbotapi.Update) {
 
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📤 Экспорт пользователей", "export_users"),
+			tgbotapi.NewInlineKeyboardButtonData(l.T("btn_export_users"), "export_users"),
 		),
 	)
 	msg.ReplyMarkup = &keyboard
@@ -106,14 +103,15 @@ func (b *Bot) getUserStats(ctx context.Context, update *tgbotapi.Update) {
 
 // bookingSummary агрегирует заявки за период в компактный блок: всего, статусы, топ-товары.
 func (b *Bot) bookingSummary(ctx context.Context, startDate, endDate time.Time) string {
+	l := b.loc(ctx)
 	bookings, err := b.bookingService.GetBookingsByDateRange(ctx, startDate, endDate)
 	if err != nil {
 		b.logger.Error().Err(err).Msg("bookingSummary error")
-		return "ошибка"
+		return l.T("stats_summary_error")
 	}
 
 	if len(bookings) == 0 {
-		return "нет данных"
+		return l.T("stats_summary_empty")
 	}
 
 	statusCount := map[string]int{}
@@ -154,7 +152,7 @@ func (b *Bot) bookingSummary(ctx context.Context, startDate, endDate time.Time)
 		itemParts = append(itemParts, fmt.Sprintf("%s:%d", it.name, it.count))
 	}
 
-	return fmt.Sprintf("всего %d | статусы [%s] | топ [%s]",
+	return l.T("stats_summary",
 		len(bookings),
 		strings.Join(statusParts, ", "),
 		strings.Join(itemParts, ", "),
@@ -171,14 +169,14 @@ func (b *Bot) handleExportUsers(ctx context.Context, update *tgbotapi.Update) {
 	users, err := b.userService.GetAllUsers(ctx)
 	if err != nil {
 		b.logger.Error().Err(err).Msg("Error getting users for export")
-		b.sendMessage(callback.Message.Chat.ID, "Ошибка при получении данных пользователей")
+		b.sendMessage(callback.Message.Chat.ID, b.loc(ctx).T("stats_error_get_users"))
 		return
 	}
 
 	filePath, err := b.exportUsersToExcel(ctx, users)
 	if err != nil {
 		b.logger.Error().Err(err).Msg("Error exporting users to Excel")
-		b.sendMessage(callback.Message.Chat.ID, "Ошибка при создании файла экспорта")
+		b.sendMessage(callback.Message.Chat.ID, b.loc(ctx).T("export_error_create"))
 		return
 	}
 
@@ -186,7 +184,7 @@ func (b *Bot) handleExportUsers(ctx context.Context, update *tgbotapi.Update) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		b.logger.Error().Err(err).Str("file_path", filePath).Msg("Error opening file")
-		b.sendMessage(callback.Message.Chat.ID, "Ошибка при открытии файла")
+		b.sendMessage(callback.Message.Chat.ID, b.loc(ctx).T("export_error_open"))
 		return
 	}
 	defer file.Close()
@@ -197,14 +195,14 @@ func (b *Bot) handleExportUsers(ctx context.Context, update *tgbotapi.Update) {
 	}
 
 	doc := tgbotapi.NewDocument(callback.Message.Chat.ID, fileReader)
-	doc.Caption = "📊 Экспорт данных пользователей"
+	doc.Caption = b.loc(ctx).T("export_users_caption")
 
 	_, err = b.tgService.Send(doc)
 	if err != nil {
 		b.logger.Error().Err(err).Msg("Error sending document")
-		b.sendMessage(callback.Message.Chat.ID, "Ошибка при отправке файла")
+		b.sendMessage(callback.Message.Chat.ID, b.loc(ctx).T("export_error_send"))
 		return
 	}
 
-	b.sendMessage(callback.Message.Chat.ID, "✅ Файл с пользователями успешно отправлен")
+	b.sendMessage(callback.Message.Chat.ID, b.loc(ctx).T("export_users_sent"))
 }
@@ -0,0 +1,116 @@
+package bot
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"bronivik/internal/domain"
+	"bronivik/internal/i18n"
+	"bronivik/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const webhookDeadLetterLimit = 10
+
+// SetWebhookService подключает управление вебхуками. Без него команды недоступны.
+func (b *Bot) SetWebhookService(webhookService domain.WebhookService) {
+	b.webhookService = webhookService
+}
+
+// handleWebhooksCommand показывает подписки и последние недоставленные события.
+func (b *Bot) handleWebhooksCommand(ctx context.Context, update *tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+	if b.webhookService == nil {
+		b.sendMessage(chatID, b.loc(ctx).T("webhooks_disabled"))
+		return
+	}
+
+	subs, err := b.webhookService.ListSubscriptions(ctx)
+	if err != nil {
+		b.sendMessage(chatID, b.loc(ctx).T("webhooks_error_subscriptions", err))
+		return
+	}
+	dead, err := b.webhookService.ListDeliveries(ctx, models.WebhookDeliveryDead, webhookDeadLetterLimit)
+	if err != nil {
+		b.sendMessage(chatID, b.loc(ctx).T("webhooks_error_deliveries", err))
+		return
+	}
+
+	b.sendMessage(chatID, formatWebhooks(b.loc(ctx), subs, dead))
+}
+
+func formatWebhooks(l *i18n.Localizer, subs []*models.WebhookSubscription, dead []*models.WebhookDelivery) string {
+	var sb strings.Builder
+	sb.WriteString(l.T("webhooks_title") + "\n")
+	if len(subs) == 0 {
+		sb.WriteString(l.T("webhooks_no_subscriptions") + "\n")
+	}
+	for _, s := range subs {
+		mark := "✅"
+		if !s.IsActive {
+			mark = "⏸"
+		}
+		sb.WriteString(fmt.Sprintf("%s #%d %s — %s\n   %s\n", mark, s.ID, s.Name, s.URL, strings.Join(s.EventTypes, ", ")))
+	}
+
+	if len(dead) == 0 {
+		sb.WriteString("\n" + l.T("webhooks_no_dead"))
+		return sb.String()
+	}
+
+	sb.WriteString("\n" + l.T("webhooks_dead_title") + "\n")
+	for _, d := range dead {
+		sb.WriteString(l.T("webhooks_dead_line", d.ID, d.SubscriptionID, d.EventType, d.EventID, d.Attempts))
+		if d.LastError != "" {
+			sb.WriteString(": " + d.LastError)
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n" + l.T("webhooks_redeliver_hint"))
+	return sb.String()
+}
+
+// handleRedeliverCommand возвращает недоставленные события в очередь отправки.
+func (b *Bot) handleRedeliverCommand(ctx context.Context, update *tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+	if b.webhookService == nil {
+		b.sendMessage(chatID, b.loc(ctx).T("webhooks_disabled"))
+		return
+	}
+
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) != 2 {
+		b.sendMessage(chatID, b.loc(ctx).T("webhooks_usage_redeliver"))
+		return
+	}
+
+	if parts[1] == "all" {
+		n, err := b.webhookService.RedeliverDead(ctx)
+		if err != nil {
+			b.sendMessage(chatID, b.loc(ctx).T("webhooks_error_redeliver", err))
+			return
+		}
+		b.sendMessage(chatID, b.loc(ctx).T("webhooks_redelivered_all", n))
+		return
+	}
+
+	id, err := strconv.ParseInt(strings.TrimPrefix(parts[1], "#"), 10, 64)
+	if err != nil || id <= 0 {
+		b.sendMessage(chatID, b.loc(ctx).T("webhooks_invalid_id"))
+		return
+	}
+	if err := b.webhookService.Redeliver(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			b.sendMessage(chatID, b.loc(ctx).T("webhooks_dead_not_found", id))
+			return
+		}
+		b.sendMessage(chatID, b.loc(ctx).T("webhooks_error_redeliver", err))
+		return
+	}
+	b.sendMessage(chatID, b.loc(ctx).T("webhooks_redelivered", id))
+}
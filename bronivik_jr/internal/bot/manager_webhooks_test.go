@@ -0,0 +1,30 @@
+package bot
+
+import (
+	"testing"
+
+	"bronivik/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatWebhooks(t *testing.T) {
+	subs := []*models.WebhookSubscription{
+		{ID: 1, Name: "crm", URL: "https://crm.example.com/hooks", EventTypes: []string{"booking_created", "booking_canceled"}, IsActive: true},
+		{ID: 2, Name: "old", URL: "https://old.example.com", EventTypes: []string{"booking_created"}},
+	}
+
+	ru := messages.Localizer("ru")
+	text := formatWebhooks(ru, subs, nil)
+	assert.Contains(t, text, "✅ #1 crm — https://crm.example.com/hooks")
+	assert.Contains(t, text, "booking_created, booking_canceled")
+	assert.Contains(t, text, "⏸ #2 old")
+	assert.Contains(t, text, "Недоставленных событий нет")
+
+	dead := []*models.WebhookDelivery{
+		{ID: 5, SubscriptionID: 1, EventID: 42, EventType: "booking_canceled", Attempts: 8, LastError: "unexpected status 502"},
+	}
+	text = formatWebhooks(ru, subs, dead)
+	assert.Contains(t, text, "#5 подписка #1, booking_canceled (событие 42), попыток: 8: unexpected status 502")
+	assert.Contains(t, text, "/redeliver <номер>")
+}
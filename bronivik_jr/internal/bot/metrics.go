@@ -15,6 +15,10 @@ type Metrics struct {
 	ErrorsTotal          prometheus.Counter
 	UsersTotal           prometheus.Gauge
 	UpdateProcessingTime prometheus.Histogram
+	UpdateQueueLength    prometheus.Gauge
+	UpdateQueueWait      prometheus.Histogram
+	UpdateQueueFull      prometheus.Counter
+	UpdatesInFlight      prometheus.Gauge
 	BookingsCreated      *prometheus.CounterVec
 	BookingDuration      *prometheus.HistogramVec
 }
@@ -58,6 +62,35 @@ func NewMetrics() *Metrics {
 			Buckets:   prometheus.DefBuckets,
 		}),
 
+		UpdateQueueLength: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: "bronivik_jr",
+			Subsystem: "bot",
+			Name:      "update_queue_length",
+			Help:      "Number of updates waiting for a worker",
+		}),
+
+		UpdateQueueWait: promauto.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "bronivik_jr",
+			Subsystem: "bot",
+			Name:      "update_queue_wait_seconds",
+			Help:      "Time between receiving an update and starting to process it",
+			Buckets:   prometheus.DefBuckets,
+		}),
+
+		UpdateQueueFull: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: "bronivik_jr",
+			Subsystem: "bot",
+			Name:      "update_queue_full_total",
+			Help:      "Number of times receiving updates blocked on a full worker queue",
+		}),
+
+		UpdatesInFlight: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: "bronivik_jr",
+			Subsystem: "bot",
+			Name:      "updates_in_flight",
+			Help:      "Number of updates being processed",
+		}),
+
 		BookingsCreated: promauto.NewCounterVec(prometheus.CounterOpts{
 			Namespace: "bronivik_jr",
 			Subsystem: "bot",
@@ -49,21 +49,22 @@ func (b *Bot) renderPaginatedList(
 	}
 
 	content, keyboard := renderer(startIdx, endIdx)
+	l := b.loc(params.Ctx)
 
 	var message strings.Builder
 	message.WriteString(fmt.Sprintf("%s\n\n", params.Title))
 	if totalPages > 1 {
-		message.WriteString(fmt.Sprintf("Страница %d из %d\n\n", params.Page+1, totalPages))
+		message.WriteString(l.T("page_of", params.Page+1, totalPages) + "\n\n")
 	}
 	message.WriteString(content)
 
 	// Добавляем навигационные кнопки
 	navButtons := make([]tgbotapi.InlineKeyboardButton, 0, 2)
 	if params.Page > 0 {
-		navButtons = append(navButtons, tgbotapi.NewInlineKeyboardButtonData(btnBack, fmt.Sprintf("%s%d", params.PagePrefix, params.Page-1)))
+		navButtons = append(navButtons, tgbotapi.NewInlineKeyboardButtonData(l.T(btnBack), fmt.Sprintf("%s%d", params.PagePrefix, params.Page-1)))
 	}
 	if endIdx < totalCount {
-		navButtons = append(navButtons, tgbotapi.NewInlineKeyboardButtonData("Вперед ➡️", fmt.Sprintf("%s%d", params.PagePrefix, params.Page+1)))
+		navButtons = append(navButtons, tgbotapi.NewInlineKeyboardButtonData(l.T("btn_next_page"), fmt.Sprintf("%s%d", params.PagePrefix, params.Page+1)))
 	}
 	if len(navButtons) > 0 {
 		keyboard = append(keyboard, navButtons)
@@ -71,7 +72,7 @@ func (b *Bot) renderPaginatedList(
 
 	if params.BackCallback != "" {
 		keyboard = append(keyboard, []tgbotapi.InlineKeyboardButton{
-			tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад в меню", params.BackCallback),
+			tgbotapi.NewInlineKeyboardButtonData(l.T("btn_back_to_menu"), params.BackCallback),
 		})
 	}
 
@@ -103,10 +104,11 @@ func (b *Bot) renderPaginatedItems(params *PaginationParams) {
 	items, err := b.itemService.GetActiveItems(params.Ctx)
 	if err != nil {
 		b.logger.Error().Err(err).Msg("Error getting active items for pagination")
-		b.sendMessage(params.ChatID, "Ошибка при получении списка аппаратов")
+		b.sendMessage(params.ChatID, b.loc(params.Ctx).T("error_get_items"))
 		return
 	}
 
+	l := b.loc(params.Ctx)
 	b.renderPaginatedList(params, len(items), b.config.Bot.PaginationSize,
 		func(startIdx, endIdx int) (string, [][]tgbotapi.InlineKeyboardButton) {
 			var content strings.Builder
@@ -119,7 +121,7 @@ func (b *Bot) renderPaginatedItems(params *PaginationParams) {
 					content.WriteString(fmt.Sprintf("   📝 %s\n", item.Description))
 				}
 				if params.ShowCapacity {
-					content.WriteString(fmt.Sprintf("   👥 Всего: %d\n", item.TotalQuantity))
+					content.WriteString("   " + l.T("items_total", item.TotalQuantity) + "\n")
 				}
 				content.WriteString("\n")
 
@@ -140,6 +142,7 @@ func (b *Bot) renderPaginatedBookings(params *PaginationParams, bookings []*mode
 	if pageSize > 5 {
 		pageSize = 5 // Keep bookings list shorter
 	}
+	l := b.loc(params.Ctx)
 	b.renderPaginatedList(params, len(bookings), pageSize, func(startIdx, endIdx int) (string, [][]tgbotapi.InlineKeyboardButton) {
 		var content strings.Builder
 		currentBookings := bookings[startIdx:endIdx]
@@ -158,14 +161,14 @@ func (b *Bot) renderPaginatedBookings(params *PaginationParams, bookings []*mode
 				statusEmoji = "🏁"
 			}
 
-			content.WriteString(fmt.Sprintf("%s *Заявка #%d*\n", statusEmoji, booking.ID))
+			content.WriteString(fmt.Sprintf("%s *%s*\n", statusEmoji, l.T("my_bookings_booking", booking.ID)))
 			content.WriteString(fmt.Sprintf("   👤 %s\n", booking.UserName))
 			content.WriteString(fmt.Sprintf("   🏢 %s\n", booking.ItemName))
-			content.WriteString(fmt.Sprintf("   📅 %s\n", booking.Date.Format("02.01.2006")))
+			content.WriteString(fmt.Sprintf("   📅 %s\n", l.Date(booking.Date)))
 			content.WriteString(fmt.Sprintf("   🔗 /manager_booking_%d\n\n", booking.ID))
 
 			btn := tgbotapi.NewInlineKeyboardButtonData(
-				fmt.Sprintf("#%d: %s (%s)", booking.ID, booking.UserName, booking.Date.Format("02.01")),
+				fmt.Sprintf("#%d: %s (%s)", booking.ID, booking.UserName, l.DayMonth(booking.Date)),
 				fmt.Sprintf("%s%d", params.ItemPrefix, booking.ID),
 			)
 			keyboard = append(keyboard, []tgbotapi.InlineKeyboardButton{btn})
@@ -0,0 +1,176 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	"bronivik/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	btnConsentAccept  = "btn_consent_accept"
+	btnConsentDecline = "btn_consent_decline"
+)
+
+// getConsentUser возвращает пользователя с данными о согласии; nil, если его еще нет в базе.
+func (b *Bot) getConsentUser(ctx context.Context, userID int64) *models.User {
+	u, err := b.userService.GetUserByTelegramID(ctx, userID)
+	if err != nil {
+		return nil
+	}
+	return u
+}
+
+// consentRevoked сообщает, что пользователь отозвал согласие: новые заявки ему недоступны.
+func (b *Bot) consentRevoked(ctx context.Context, userID int64) bool {
+	u := b.getConsentUser(ctx, userID)
+	return u != nil && u.ConsentRevoked
+}
+
+// handleNameEntered после ввода имени запрашивает согласие, если его еще нет, и затем телефон.
+func (b *Bot) handleNameEntered(ctx context.Context, update *tgbotapi.Update, state *models.UserState) {
+	userID := update.Message.From.ID
+	u := b.getConsentUser(ctx, userID)
+	switch {
+	case u != nil && u.ConsentRevoked:
+		b.clearUserState(ctx, userID)
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("consent_blocked"))
+		b.handleMainMenu(ctx, update)
+	case u == nil || !u.HasConsent(models.PrivacyPolicyVersion):
+		b.setUserState(ctx, userID, models.StateConsent, state.TempData)
+		b.requestConsent(ctx, update)
+	default:
+		b.setUserState(ctx, userID, models.StatePhoneNumber, state.TempData)
+		b.handlePhoneRequest(ctx, update)
+	}
+}
+
+// requestConsent показывает текст политики и кнопки согласия перед вводом телефона.
+func (b *Bot) requestConsent(ctx context.Context, update *tgbotapi.Update) {
+	l := b.loc(ctx)
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID,
+		l.T("privacy_policy", models.PrivacyPolicyVersion)+"\n\n"+l.T("consent_request"))
+	msg.ReplyMarkup = tgbotapi.NewReplyKeyboard(
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton(l.T(btnConsentAccept)),
+			tgbotapi.NewKeyboardButton(l.T(btnConsentDecline)),
+		),
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton(l.T(btnCancel)),
+			tgbotapi.NewKeyboardButton(l.T(btnBack)),
+		),
+	)
+	if _, err := b.tgService.Send(msg); err != nil {
+		b.logger.Error().Err(err).Int64("user_id", update.Message.From.ID).Msg("Failed to send consent request")
+	}
+}
+
+// handleConsentAnswer обрабатывает ответ на запрос согласия в сценарии бронирования.
+func (b *Bot) handleConsentAnswer(ctx context.Context, update *tgbotapi.Update, text string, state *models.UserState) {
+	userID := update.Message.From.ID
+	chatID := update.Message.Chat.ID
+	l := b.loc(ctx)
+
+	switch {
+	case isButton(text, btnConsentAccept):
+		if !b.giveConsent(ctx, update.Message.From) {
+			b.sendMessage(chatID, l.T("error_generic"))
+			return
+		}
+		b.sendMessage(chatID, l.T("consent_given"))
+		b.setUserState(ctx, userID, models.StatePhoneNumber, state.TempData)
+		b.handlePhoneRequest(ctx, update)
+
+	case isButton(text, btnConsentDecline):
+		b.clearUserState(ctx, userID)
+		b.sendMessage(chatID, l.T("consent_declined"))
+		b.handleMainMenu(ctx, update)
+
+	default:
+		b.sendMessage(chatID, l.T("consent_choose", l.T(btnConsentAccept), l.T(btnConsentDecline)))
+	}
+}
+
+// giveConsent сохраняет согласие; пользователь создается, если еще не нажимал /start.
+func (b *Bot) giveConsent(ctx context.Context, from *tgbotapi.User) bool {
+	if b.getConsentUser(ctx, from.ID) == nil {
+		user := &models.User{
+			TelegramID:   from.ID,
+			Username:     from.UserName,
+			FirstName:    from.FirstName,
+			LastName:     from.LastName,
+			LanguageCode: from.LanguageCode,
+			LastActivity: time.Now(),
+		}
+		if err := b.userService.SaveUser(ctx, user); err != nil {
+			b.logger.Error().Err(err).Int64("user_id", from.ID).Msg("Error saving user before consent")
+			return false
+		}
+	}
+	if err := b.userService.GiveConsent(ctx, from.ID, models.PrivacyPolicyVersion); err != nil {
+		b.logger.Error().Err(err).Int64("user_id", from.ID).Msg("Error saving consent")
+		return false
+	}
+	return true
+}
+
+// handlePrivacyCommand показывает политику, текущий статус согласия и кнопку дать/отозвать.
+func (b *Bot) handlePrivacyCommand(ctx context.Context, update *tgbotapi.Update) {
+	l := b.loc(ctx)
+	u := b.getConsentUser(ctx, update.Message.From.ID)
+
+	var status string
+	button := tgbotapi.NewInlineKeyboardButtonData(l.T("btn_privacy_accept"), "privacy:accept")
+	switch {
+	case u != nil && u.ConsentRevoked:
+		status = l.T("privacy_status_revoked", l.DateTime(u.ConsentRevokedAt.Time))
+	case u != nil && u.HasConsent(models.PrivacyPolicyVersion):
+		status = l.T("privacy_status_given", l.DateTime(u.ConsentGivenAt.Time), u.ConsentVersion)
+		button = tgbotapi.NewInlineKeyboardButtonData(l.T("btn_privacy_revoke"), "privacy:revoke")
+	case u != nil && u.ConsentGiven:
+		status = l.T("privacy_status_outdated")
+	default:
+		status = l.T("privacy_status_none")
+	}
+
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID,
+		l.T("privacy_policy", models.PrivacyPolicyVersion)+"\n\n"+status+"\n\n"+l.T("privacy_my_data_hint"))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(button))
+	if _, err := b.tgService.Send(msg); err != nil {
+		b.logger.Error().Err(err).Int64("chat_id", update.Message.Chat.ID).Msg("Failed to send privacy info")
+	}
+}
+
+// handlePrivacyCallback дает или отзывает согласие по кнопке из /privacy.
+func (b *Bot) handlePrivacyCallback(ctx context.Context, update *tgbotapi.Update, action string) {
+	callback := update.CallbackQuery
+	chatID := callback.Message.Chat.ID
+	l := b.loc(ctx)
+
+	var text string
+	switch action {
+	case "accept":
+		if !b.giveConsent(ctx, callback.From) {
+			text = l.T("error_generic")
+			break
+		}
+		text = l.T("consent_given")
+	case "revoke":
+		if err := b.userService.RevokeConsent(ctx, callback.From.ID); err != nil {
+			b.logger.Error().Err(err).Int64("user_id", callback.From.ID).Msg("Error revoking consent")
+			text = l.T("error_generic")
+			break
+		}
+		// Незавершенный сценарий бронирования больше не должен дойти до телефона
+		b.clearUserState(ctx, callback.From.ID)
+		text = l.T("consent_revoked")
+	default:
+		return
+	}
+
+	if _, err := b.tgService.EditMessage(chatID, callback.Message.MessageID, text, nil); err != nil {
+		b.logger.Error().Err(err).Msg("Failed to edit privacy message")
+	}
+}
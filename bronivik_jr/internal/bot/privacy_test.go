@@ -0,0 +1,107 @@
+package bot
+
+import (
+	"context"
+	"testing"
+
+	"bronivik/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func textMessage(userID int64, text string) *tgbotapi.Update {
+	return &tgbotapi.Update{Message: &tgbotapi.Message{
+		From: &tgbotapi.User{ID: userID},
+		Chat: &tgbotapi.Chat{ID: userID},
+		Text: text,
+	}}
+}
+
+func TestConsent_DeclineStopsBooking(t *testing.T) {
+	b, mocks := setupTestBot()
+	ctx := context.Background()
+	userID := int64(321)
+	b.setUserState(ctx, userID, models.StateEnterName, map[string]interface{}{"item_id": int64(1)})
+
+	b.handleMessage(ctx, textMessage(userID, "Test User"))
+	state, _ := mocks.state.GetUserState(ctx, userID)
+	require.Equal(t, models.StateConsent, state.CurrentStep)
+	assert.Contains(t, sentTexts(mocks), messages.Localizer("ru").T("privacy_policy", models.PrivacyPolicyVersion)+
+		"\n\n"+messages.Localizer("ru").T("consent_request"))
+
+	// Произвольный текст не считается ответом
+	b.handleMessage(ctx, textMessage(userID, "89991234567"))
+	state, _ = mocks.state.GetUserState(ctx, userID)
+	assert.Equal(t, models.StateConsent, state.CurrentStep)
+
+	b.handleMessage(ctx, textMessage(userID, "❌ Не согласен"))
+	state, _ = mocks.state.GetUserState(ctx, userID)
+	assert.Equal(t, models.StateMainMenu, state.CurrentStep)
+	assert.Contains(t, sentTexts(mocks), messages.Localizer("ru").T("consent_declined"))
+	_, err := mocks.user.GetUserByTelegramID(ctx, userID)
+	assert.Error(t, err, "decline must not store the user's consent")
+}
+
+func TestConsent_SkippedWhenAlreadyGiven(t *testing.T) {
+	b, mocks := setupTestBot()
+	ctx := context.Background()
+	userID := int64(322)
+	require.NoError(t, mocks.user.SaveUser(ctx, &models.User{TelegramID: userID}))
+	require.NoError(t, mocks.user.GiveConsent(ctx, userID, models.PrivacyPolicyVersion))
+
+	b.setUserState(ctx, userID, models.StateEnterName, map[string]interface{}{"item_id": int64(1)})
+	b.handleMessage(ctx, textMessage(userID, "Test User"))
+	state, _ := mocks.state.GetUserState(ctx, userID)
+	assert.Equal(t, models.StatePhoneNumber, state.CurrentStep)
+
+	// После обновления политики согласие спрашивается снова
+	mocks.user.users[userID].ConsentVersion = "2020-01"
+	b.setUserState(ctx, userID, models.StateEnterName, map[string]interface{}{"item_id": int64(1)})
+	b.handleMessage(ctx, textMessage(userID, "Test User"))
+	state, _ = mocks.state.GetUserState(ctx, userID)
+	assert.Equal(t, models.StateConsent, state.CurrentStep)
+}
+
+func TestPrivacy_RevokeBlocksNewBookings(t *testing.T) {
+	b, mocks := setupTestBot()
+	ctx := context.Background()
+	userID := int64(323)
+	require.NoError(t, mocks.user.SaveUser(ctx, &models.User{TelegramID: userID, Phone: "+79991234567"}))
+	require.NoError(t, mocks.user.GiveConsent(ctx, userID, models.PrivacyPolicyVersion))
+
+	b.handleMessage(ctx, textMessage(userID, "/privacy"))
+	msg, ok := mocks.tg.sentMessages[len(mocks.tg.sentMessages)-1].(tgbotapi.MessageConfig)
+	require.True(t, ok)
+	keyboard, ok := msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+	require.True(t, ok)
+	assert.Equal(t, "privacy:revoke", *keyboard.InlineKeyboard[0][0].CallbackData)
+
+	b.handleCallbackQuery(ctx, &tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: userID},
+		Message: &tgbotapi.Message{MessageID: 1, Chat: &tgbotapi.Chat{ID: userID}},
+		Data:    "privacy:revoke",
+	}})
+	u := mocks.user.users[userID]
+	assert.True(t, u.ConsentRevoked)
+	assert.Empty(t, u.Phone)
+
+	// Новая заявка недоступна, пока согласие не дано снова
+	b.handleSelectItem(ctx, textMessage(userID, ""))
+	assert.Equal(t, messages.Localizer("ru").T("consent_blocked"), lastSentText(mocks))
+	state, _ := mocks.state.GetUserState(ctx, userID)
+	assert.Nil(t, state)
+
+	b.handleCallbackQuery(ctx, &tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: userID},
+		Message: &tgbotapi.Message{MessageID: 1, Chat: &tgbotapi.Chat{ID: userID}},
+		Data:    "privacy:accept",
+	}})
+	assert.True(t, u.HasConsent(models.PrivacyPolicyVersion))
+	b.handleSelectItem(ctx, textMessage(userID, ""))
+	state, _ = mocks.state.GetUserState(ctx, userID)
+	assert.Equal(t, models.StateSelectItem, state.CurrentStep)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"bronivik/internal/i18n"
 	"bronivik/internal/models"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -69,7 +70,7 @@ func (b *Bot) sendTomorrowReminders(ctx context.Context) {
 			continue
 		}
 
-		msgText := formatReminderMessage(booking)
+		msgText := formatReminderMessage(b.locFor(ctx, user.TelegramID), booking)
 		msg := tgbotapi.NewMessage(user.TelegramID, msgText)
 		if _, err := b.tgService.Send(msg); err != nil {
 			b.logger.Error().Err(err).Int64("telegram_id", user.TelegramID).Msg("reminder: send error")
@@ -86,9 +87,8 @@ func shouldRemindStatus(status string) bool {
 	}
 }
 
-func formatReminderMessage(b *models.Booking) string {
-	date := b.Date.Format("02.01.2006")
-	return "Напоминание: завтра у вас бронь " + b.ItemName + " на " + date + ". Статус: " + b.Status
+func formatReminderMessage(l *i18n.Localizer, b *models.Booking) string {
+	return l.T("reminder", b.ItemName, l.Date(b.Date), b.Status)
 }
 
 func timeUntilNextHour(hour int) time.Duration {
@@ -91,7 +91,7 @@ func TestFormatReminderMessage(t *testing.T) {
 		Status:   models.StatusConfirmed,
 	}
 
-	msg := formatReminderMessage(booking)
+	msg := formatReminderMessage(messages.Localizer("ru"), booking)
 
 	// Check message contains key info
 	if msg == "" {
@@ -0,0 +1,140 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// queuedUpdate хранит время приема, чтобы измерять ожидание в очереди.
+type queuedUpdate struct {
+	update     tgbotapi.Update
+	receivedAt time.Time
+}
+
+// updatePool обрабатывает обновления параллельно. Обновления одного пользователя
+// всегда попадают в очередь одного воркера, поэтому его сценарий (состояние
+// диалога) выполняется строго по порядку и не гоняется сам с собой.
+type updatePool struct {
+	queues  []chan queuedUpdate
+	handle  func(context.Context, *tgbotapi.Update)
+	metrics *Metrics
+	wg      sync.WaitGroup
+}
+
+func newUpdatePool(workers, queueSize int, handle func(context.Context, *tgbotapi.Update), metrics *Metrics) *updatePool {
+	if workers <= 0 {
+		workers = 1
+	}
+	if queueSize <= 0 {
+		queueSize = 1
+	}
+	p := &updatePool{
+		queues:  make([]chan queuedUpdate, workers),
+		handle:  handle,
+		metrics: metrics,
+	}
+	for i := range p.queues {
+		p.queues[i] = make(chan queuedUpdate, queueSize)
+	}
+	return p
+}
+
+// start запускает воркеры. Обработчики получают ctx, поэтому для дообработки
+// при остановке он не должен отменяться вместе с приемом обновлений.
+func (p *updatePool) start(ctx context.Context) {
+	for _, queue := range p.queues {
+		p.wg.Add(1)
+		go p.work(ctx, queue)
+	}
+}
+
+func (p *updatePool) work(ctx context.Context, queue <-chan queuedUpdate) {
+	defer p.wg.Done()
+	for item := range queue {
+		if p.metrics != nil {
+			p.metrics.UpdateQueueLength.Dec()
+			p.metrics.UpdateQueueWait.Observe(time.Since(item.receivedAt).Seconds())
+			p.metrics.UpdatesInFlight.Inc()
+		}
+		p.handle(ctx, &item.update)
+		if p.metrics != nil {
+			p.metrics.UpdatesInFlight.Dec()
+		}
+	}
+}
+
+// submit ставит обновление в очередь воркера. Если очередь заполнена, ждет
+// освобождения места: прием новых обновлений приостанавливается (backpressure).
+// Возвращает false, если ctx отменен раньше.
+func (p *updatePool) submit(ctx context.Context, update tgbotapi.Update) bool {
+	item := queuedUpdate{update: update, receivedAt: time.Now()}
+	queue := p.queues[p.shard(updateKey(&update))]
+	if p.metrics != nil {
+		p.metrics.UpdateQueueLength.Inc()
+	}
+
+	select {
+	case queue <- item:
+		return true
+	default:
+	}
+
+	if p.metrics != nil {
+		p.metrics.UpdateQueueFull.Inc()
+	}
+	select {
+	case queue <- item:
+		return true
+	case <-ctx.Done():
+		if p.metrics != nil {
+			p.metrics.UpdateQueueLength.Dec()
+		}
+		return false
+	}
+}
+
+// drain закрывает очереди и ждет, пока воркеры обработают принятые обновления.
+// Возвращает false, если за timeout они не успели.
+func (p *updatePool) drain(timeout time.Duration) bool {
+	for _, queue := range p.queues {
+		close(queue)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+func (p *updatePool) shard(key int64) int {
+	return int(uint64(key) % uint64(len(p.queues)))
+}
+
+// updateKey возвращает ключ упорядочивания: пользователя, а если его нет, чат.
+func updateKey(update *tgbotapi.Update) int64 {
+	switch {
+	case update.Message != nil && update.Message.From != nil:
+		return update.Message.From.ID
+	case update.Message != nil && update.Message.Chat != nil:
+		return update.Message.Chat.ID
+	case update.CallbackQuery != nil && update.CallbackQuery.From != nil:
+		return update.CallbackQuery.From.ID
+	}
+	if chat := update.FromChat(); chat != nil {
+		return chat.ID
+	}
+	return 0
+}
@@ -0,0 +1,89 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func userUpdate(updateID int, userID int64) tgbotapi.Update {
+	return tgbotapi.Update{
+		UpdateID: updateID,
+		Message: &tgbotapi.Message{
+			From: &tgbotapi.User{ID: userID},
+			Chat: &tgbotapi.Chat{ID: userID},
+		},
+	}
+}
+
+func TestUpdatePoolKeepsPerUserOrder(t *testing.T) {
+	var mu sync.Mutex
+	seen := make(map[int64][]int)
+	pool := newUpdatePool(4, 10, func(_ context.Context, u *tgbotapi.Update) {
+		// Разная длительность обработки перемешала бы порядок без шардирования
+		time.Sleep(time.Duration(u.UpdateID%3) * time.Millisecond)
+		mu.Lock()
+		seen[u.Message.From.ID] = append(seen[u.Message.From.ID], u.UpdateID)
+		mu.Unlock()
+	}, nil)
+	ctx := context.Background()
+	pool.start(ctx)
+
+	for i := 0; i < 60; i++ {
+		require.True(t, pool.submit(ctx, userUpdate(i, int64(i%5+1))))
+	}
+	require.True(t, pool.drain(5*time.Second))
+
+	for userID, ids := range seen {
+		assert.Len(t, ids, 12, "user %d", userID)
+		assert.IsIncreasing(t, ids, "user %d", userID)
+	}
+}
+
+func TestUpdatePoolProcessesUsersInParallel(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan int64, 2)
+	pool := newUpdatePool(2, 1, func(_ context.Context, u *tgbotapi.Update) {
+		started <- u.Message.From.ID
+		<-release
+	}, nil)
+	ctx := context.Background()
+	pool.start(ctx)
+
+	// Пользователи 1 и 2 попадают в разные воркеры: медленный первый не держит второго
+	pool.submit(ctx, userUpdate(1, 1))
+	pool.submit(ctx, userUpdate(2, 2))
+	for i := 0; i < 2; i++ {
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatal("updates were not processed in parallel")
+		}
+	}
+	close(release)
+	assert.True(t, pool.drain(time.Second))
+}
+
+func TestUpdatePoolBackpressureAndDrainTimeout(t *testing.T) {
+	release := make(chan struct{})
+	pool := newUpdatePool(1, 1, func(context.Context, *tgbotapi.Update) { <-release }, nil)
+	pool.start(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.True(t, pool.submit(ctx, userUpdate(1, 1)))
+	// Первое обновление может еще лежать в очереди, поэтому отправляем до блокировки
+	submitted := 1
+	for pool.submit(ctx, userUpdate(submitted+1, 1)) {
+		submitted++
+		require.Less(t, submitted, 5, "queue must block when full")
+	}
+
+	assert.False(t, pool.drain(20*time.Millisecond))
+	close(release)
+}
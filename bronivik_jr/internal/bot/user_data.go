@@ -0,0 +1,212 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"bronivik/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/xuri/excelize/v2"
+)
+
+// handleMyDataCommand отправляет пользователю выгрузку всех его данных (JSON и XLSX).
+func (b *Bot) handleMyDataCommand(ctx context.Context, update *tgbotapi.Update) {
+	b.sendUserData(ctx, update.Message.Chat.ID, update.Message.From.ID)
+}
+
+// handleUserDataCommand — выгрузка данных пользователя по запросу менеджера: /user_data <telegram_id>.
+func (b *Bot) handleUserDataCommand(ctx context.Context, update *tgbotapi.Update) {
+	telegramID, ok := parseTelegramIDArg(update.Message.Text)
+	if !ok {
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("user_data_usage"))
+		return
+	}
+	b.sendUserData(ctx, update.Message.Chat.ID, telegramID)
+}
+
+func (b *Bot) sendUserData(ctx context.Context, chatID, telegramID int64) {
+	l := b.loc(ctx)
+	export, err := b.userService.ExportUserData(ctx, telegramID)
+	if err != nil {
+		b.logger.Error().Err(err).Int64("user_id", telegramID).Msg("Error exporting user data")
+		b.sendMessage(chatID, l.T("user_data_error"))
+		return
+	}
+	if export.IsEmpty() {
+		b.sendMessage(chatID, l.T("user_data_empty", telegramID))
+		return
+	}
+
+	files, err := userDataFiles(export)
+	if err != nil {
+		b.logger.Error().Err(err).Int64("user_id", telegramID).Msg("Error building user data files")
+		b.sendMessage(chatID, l.T("user_data_error"))
+		return
+	}
+
+	for _, file := range files {
+		doc := tgbotapi.NewDocument(chatID, file)
+		doc.Caption = l.T("user_data_caption", telegramID)
+		if _, err := b.tgService.Send(doc); err != nil {
+			b.logger.Error().Err(err).Int64("user_id", telegramID).Msg("Error sending user data")
+			b.sendMessage(chatID, l.T("export_error_send"))
+			return
+		}
+	}
+	b.logger.Info().Int64("user_id", telegramID).Int64("chat_id", chatID).Msg("User data export sent")
+}
+
+// userDataFiles строит машиночитаемую выгрузку: JSON и XLSX с листом на каждую таблицу.
+// Файлы не сохраняются на диск, чтобы персональные данные не оставались в каталоге экспорта.
+func userDataFiles(export *models.UserDataExport) ([]tgbotapi.FileBytes, error) {
+	name := fmt.Sprintf("user_data_%d_%s", export.TelegramID, export.GeneratedAt.Format("2006-01-02"))
+
+	jsonData, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode json: %w", err)
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+	for _, section := range export.Sections {
+		if _, err := f.NewSheet(section.Name); err != nil {
+			return nil, fmt.Errorf("create sheet %s: %w", section.Name, err)
+		}
+		header := make([]interface{}, len(section.Columns))
+		for i, col := range section.Columns {
+			header[i] = col
+		}
+		_ = f.SetSheetRow(section.Name, "A1", &header)
+		for i, row := range section.Rows {
+			values := make([]interface{}, len(section.Columns))
+			for j, col := range section.Columns {
+				values[j] = xlsxValue(row[col])
+			}
+			cell, _ := excelize.CoordinatesToCellName(1, i+2)
+			_ = f.SetSheetRow(section.Name, cell, &values)
+		}
+	}
+	_ = f.DeleteSheet("Sheet1")
+
+	var xlsxData bytes.Buffer
+	if err := f.Write(&xlsxData); err != nil {
+		return nil, fmt.Errorf("write xlsx: %w", err)
+	}
+
+	return []tgbotapi.FileBytes{
+		{Name: name + ".json", Bytes: jsonData},
+		{Name: name + ".xlsx", Bytes: xlsxData.Bytes()},
+	}, nil
+}
+
+func xlsxValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return val.Format("2006-01-02 15:04:05")
+	default:
+		return val
+	}
+}
+
+// handleEraseUserCommand просит менеджера подтвердить удаление данных: /erase_user <telegram_id>.
+func (b *Bot) handleEraseUserCommand(ctx context.Context, update *tgbotapi.Update) {
+	l := b.loc(ctx)
+	chatID := update.Message.Chat.ID
+	telegramID, ok := parseTelegramIDArg(update.Message.Text)
+	if !ok {
+		b.sendMessage(chatID, l.T("erase_user_usage"))
+		return
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(l.T("btn_erase_user_confirm"), fmt.Sprintf("erase_user:%d", telegramID)),
+		tgbotapi.NewInlineKeyboardButtonData(l.T(btnCancel), "erase_user_cancel"),
+	))
+	if _, err := b.tgService.SendWithInlineKeyboard(chatID, l.T("erase_user_confirm", telegramID), keyboard); err != nil {
+		b.logger.Error().Err(err).Int64("chat_id", chatID).Msg("Failed to send erase confirmation")
+	}
+}
+
+// handleEraseUserCallback удаляет данные пользователя после подтверждения менеджером.
+func (b *Bot) handleEraseUserCallback(ctx context.Context, update *tgbotapi.Update, data string) {
+	callback := update.CallbackQuery
+	chatID := callback.Message.Chat.ID
+	l := b.loc(ctx)
+
+	if data == "erase_user_cancel" {
+		if _, err := b.tgService.EditMessage(chatID, callback.Message.MessageID, l.T("erase_user_cancelled"), nil); err != nil {
+			b.logger.Error().Err(err).Msg("Failed to edit erase message")
+		}
+		return
+	}
+
+	telegramID, err := strconv.ParseInt(strings.TrimPrefix(data, "erase_user:"), 10, 64)
+	if err != nil {
+		return
+	}
+
+	var text string
+	result, err := b.userService.EraseUserData(ctx, telegramID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		text = l.T("erase_user_not_found", telegramID)
+	case err != nil:
+		b.logger.Error().Err(err).Int64("user_id", telegramID).Msg("Error erasing user data")
+		text = l.T("user_data_error")
+	default:
+		b.clearUserState(ctx, telegramID)
+		b.eraseUserFromSheets(ctx, result)
+		b.logger.Info().
+			Int64("user_id", telegramID).
+			Int64("manager_id", callback.From.ID).
+			Msg("User data erased by manager")
+		text = l.T("erase_user_done", telegramID, len(result.BookingIDs), result.Pseudonym)
+	}
+
+	if _, err := b.tgService.EditMessage(chatID, callback.Message.MessageID, text, nil); err != nil {
+		b.logger.Error().Err(err).Msg("Failed to edit erase message")
+	}
+}
+
+// eraseUserFromSheets очищает строку пользователя на листе Users и перезаписывает
+// его заявки в Google Sheets обезличенными данными.
+func (b *Bot) eraseUserFromSheets(ctx context.Context, result *models.ErasureResult) {
+	if b.sheetsService != nil {
+		if err := b.sheetsService.DeleteUserRows(ctx, result.TelegramID); err != nil {
+			b.logger.Error().Err(err).Int64("user_id", result.TelegramID).Msg("Failed to erase user from Google Sheets")
+		}
+	}
+	if b.sheetsWorker == nil {
+		return
+	}
+
+	for _, id := range result.BookingIDs {
+		if err := b.sheetsWorker.EnqueueTask(ctx, "upsert", id, ""); err != nil {
+			b.logger.Error().Err(err).Int64("booking_id", id).Msg("Failed to enqueue erased booking sync")
+		}
+	}
+	if len(result.BookingIDs) > 0 {
+		if err := b.sheetsWorker.EnqueueSyncSchedule(ctx, time.Time{}, time.Time{}); err != nil {
+			b.logger.Error().Err(err).Msg("Failed to enqueue schedule sync")
+		}
+	}
+}
+
+func parseTelegramIDArg(text string) (int64, bool) {
+	parts := strings.Fields(text)
+	if len(parts) != 2 {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	return id, err == nil && id > 0
+}
@@ -0,0 +1,91 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"bronivik/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sentDocuments(mocks *botMocks) []tgbotapi.DocumentConfig {
+	var docs []tgbotapi.DocumentConfig
+	for _, c := range mocks.tg.sentMessages {
+		if doc, ok := c.(tgbotapi.DocumentConfig); ok {
+			docs = append(docs, doc)
+		}
+	}
+	return docs
+}
+
+func TestMyData_SendsJSONAndXLSX(t *testing.T) {
+	b, mocks := setupTestBot()
+	ctx := context.Background()
+	userID := int64(410)
+	require.NoError(t, mocks.user.SaveUser(ctx, &models.User{TelegramID: userID, FirstName: "Anna", Phone: "79991234567"}))
+
+	b.handleMessage(ctx, textMessage(userID, "/my_data"))
+
+	docs := sentDocuments(mocks)
+	require.Len(t, docs, 2)
+	jsonFile, ok := docs[0].File.(tgbotapi.FileBytes)
+	require.True(t, ok)
+	assert.True(t, strings.HasSuffix(jsonFile.Name, ".json"))
+	var export models.UserDataExport
+	require.NoError(t, json.Unmarshal(jsonFile.Bytes, &export))
+	assert.Equal(t, userID, export.TelegramID)
+	assert.Equal(t, "Anna", export.Sections[0].Rows[0]["first_name"])
+
+	xlsxFile, ok := docs[1].File.(tgbotapi.FileBytes)
+	require.True(t, ok)
+	assert.True(t, strings.HasSuffix(xlsxFile.Name, ".xlsx"))
+	assert.NotEmpty(t, xlsxFile.Bytes)
+}
+
+func TestUserData_ManagerOnlyAndEmpty(t *testing.T) {
+	b, mocks := setupTestBot()
+	ctx := context.Background()
+
+	// Обычный пользователь не может выгружать чужие данные
+	b.handleMessage(ctx, textMessage(411, "/user_data 123"))
+	assert.Empty(t, sentDocuments(mocks))
+
+	b.handleMessage(ctx, textMessage(123, "/user_data"))
+	assert.Equal(t, messages.Localizer("ru").T("user_data_usage"), lastSentText(mocks))
+
+	b.handleMessage(ctx, textMessage(123, "/user_data 999"))
+	assert.Equal(t, messages.Localizer("ru").T("user_data_empty", int64(999)), lastSentText(mocks))
+	assert.Empty(t, sentDocuments(mocks))
+}
+
+func TestEraseUser_ConfirmErasesAndCleansSheets(t *testing.T) {
+	b, mocks := setupTestBot()
+	ctx := context.Background()
+	managerID := int64(123)
+	userID := int64(412)
+	require.NoError(t, mocks.user.SaveUser(ctx, &models.User{TelegramID: userID, FirstName: "Anna"}))
+
+	b.handleMessage(ctx, textMessage(managerID, "/erase_user 412"))
+	msg, ok := mocks.tg.sentMessages[len(mocks.tg.sentMessages)-1].(tgbotapi.MessageConfig)
+	require.True(t, ok)
+	assert.Equal(t, messages.Localizer("ru").T("erase_user_confirm", userID), msg.Text)
+	keyboard, ok := msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+	require.True(t, ok)
+	assert.Equal(t, "erase_user:412", *keyboard.InlineKeyboard[0][0].CallbackData)
+
+	b.handleCallbackQuery(ctx, &tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: managerID},
+		Message: &tgbotapi.Message{MessageID: 1, Chat: &tgbotapi.Chat{ID: managerID}},
+		Data:    "erase_user:412",
+	}})
+
+	_, err := mocks.user.GetUserByTelegramID(ctx, userID)
+	assert.Error(t, err)
+	assert.Equal(t, []int64{userID}, mocks.sheets.deletedUsers)
+}
@@ -40,7 +40,7 @@ func (b *Bot) handleMessage(ctx context.Context, update *tgbotapi.Update) {
 	state := b.getUserState(ctx, userID)
 
 	// Обработка общих кнопок "Назад" и "Отмена"
-	if text == btnCancel || text == btnBack {
+	if isButton(text, btnCancel) || isButton(text, btnBack) {
 		b.handleCustomInput(ctx, update, state)
 		return
 	}
@@ -78,11 +78,23 @@ func (b *Bot) handleBasicCommands(ctx context.Context, update *tgbotapi.Update,
 		b.handleStartWithUserTracking(ctx, update)
 		return true
 
-	case text == btnManagerContacts:
+	case text == "/language":
+		b.handleLanguageCommand(ctx, update)
+		return true
+
+	case text == "/privacy":
+		b.handlePrivacyCommand(ctx, update)
+		return true
+
+	case text == "/my_data":
+		b.handleMyDataCommand(ctx, update)
+		return true
+
+	case isButton(text, btnManagerContacts):
 		b.showManagerContacts(ctx, update)
 		return true
 
-	case text == btnMyBookings:
+	case isButton(text, btnMyBookings):
 		b.showUserBookings(ctx, update)
 		return true
 	}
@@ -91,15 +103,15 @@ func (b *Bot) handleBasicCommands(ctx context.Context, update *tgbotapi.Update,
 
 func (b *Bot) handleNavigationCommands(ctx context.Context, update *tgbotapi.Update, text string) bool {
 	switch {
-	case text == btnAvailableItems:
+	case isButton(text, btnAvailableItems):
 		b.showAvailableItems(ctx, update)
 		return true
 
-	case text == btnViewSchedule:
+	case isButton(text, btnViewSchedule):
 		b.handleViewSchedule(ctx, update)
 		return true
 
-	case text == btnBackToItems:
+	case isButton(text, btnBackToItems):
 		b.handleViewSchedule(ctx, update)
 		return true
 	}
@@ -108,29 +120,29 @@ func (b *Bot) handleNavigationCommands(ctx context.Context, update *tgbotapi.Upd
 
 func (b *Bot) handleBookingProcessCommands(ctx context.Context, update *tgbotapi.Update, state *models.UserState, text string) bool {
 	switch {
-	case text == btnCreateBooking:
+	case isButton(text, btnCreateBooking):
 		b.handleSelectItem(ctx, update)
 		return true
 
-	case text == btnMonthSchedule:
+	case isButton(text, btnMonthSchedule):
 		if state != nil && state.TempData["item_id"] != nil {
 			b.showMonthScheduleForItem(ctx, update)
 		} else {
-			b.sendMessage(update.Message.Chat.ID, "Сначала выберите аппарат для просмотра расписания")
+			b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("schedule_select_item_first"))
 			b.handleViewSchedule(ctx, update)
 		}
 		return true
 
-	case text == btnPickDate:
+	case isButton(text, btnPickDate):
 		if state != nil && state.TempData["item_id"] != nil {
 			b.requestSpecificDate(ctx, update)
 		} else {
-			b.sendMessage(update.Message.Chat.ID, "Сначала выберите аппарат для просмотра расписания")
+			b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("schedule_select_item_first"))
 			b.handleViewSchedule(ctx, update)
 		}
 		return true
 
-	case text == btnCreateForItem:
+	case isButton(text, btnCreateForItem):
 		if state != nil && state.TempData["item_id"] != nil {
 			itemID := state.GetInt64("item_id")
 			b.handleDateSelection(ctx, update, itemID)
@@ -143,13 +155,14 @@ func (b *Bot) handleBookingProcessCommands(ctx context.Context, update *tgbotapi
 
 // handleUserStateSteps обрабатывает ввод пользователя в зависимости от текущего шага
 func (b *Bot) handleUserStateSteps(ctx context.Context, update *tgbotapi.Update, text string, state *models.UserState) bool {
-	userID := update.Message.From.ID
-
 	switch state.CurrentStep {
 	case models.StateEnterName:
 		state.TempData["user_name"] = b.sanitizeInput(text)
-		b.setUserState(ctx, userID, models.StatePhoneNumber, state.TempData)
-		b.handlePhoneRequest(ctx, update)
+		b.handleNameEntered(ctx, update, state)
+		return true
+
+	case models.StateConsent:
+		b.handleConsentAnswer(ctx, update, text, state)
 		return true
 
 	case models.StatePhoneNumber:
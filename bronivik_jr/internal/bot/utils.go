@@ -85,8 +85,8 @@ func (b *Bot) handleMainMenu(ctx context.Context, update *tgbotapi.Update) {
 
 	b.updateUserActivity(userID)
 
-	msg := tgbotapi.NewMessage(chatID,
-		"Добро пожаловать! Выберите действие:")
+	l := b.loc(ctx)
+	msg := tgbotapi.NewMessage(chatID, l.T("menu_welcome"))
 
 	rows := make([][]tgbotapi.KeyboardButton, 0, 5)
 
@@ -94,15 +94,15 @@ func (b *Bot) handleMainMenu(ctx context.Context, update *tgbotapi.Update) {
 	if !b.isManager(userID) {
 		rows = append(rows,
 			tgbotapi.NewKeyboardButtonRow(
-				tgbotapi.NewKeyboardButton(btnCreateBooking),
+				tgbotapi.NewKeyboardButton(l.T(btnCreateBooking)),
 			),
 			tgbotapi.NewKeyboardButtonRow(
-				tgbotapi.NewKeyboardButton(btnViewSchedule),
-				tgbotapi.NewKeyboardButton(btnAvailableItems),
+				tgbotapi.NewKeyboardButton(l.T(btnViewSchedule)),
+				tgbotapi.NewKeyboardButton(l.T(btnAvailableItems)),
 			),
 			tgbotapi.NewKeyboardButtonRow(
-				tgbotapi.NewKeyboardButton(btnMyBookings),
-				tgbotapi.NewKeyboardButton(btnManagerContacts),
+				tgbotapi.NewKeyboardButton(l.T(btnMyBookings)),
+				tgbotapi.NewKeyboardButton(l.T(btnManagerContacts)),
 			),
 		)
 	}
@@ -111,14 +111,14 @@ func (b *Bot) handleMainMenu(ctx context.Context, update *tgbotapi.Update) {
 	if b.isManager(userID) {
 		rows = append(rows,
 			tgbotapi.NewKeyboardButtonRow(
-				tgbotapi.NewKeyboardButton(btnAllBookings),
+				tgbotapi.NewKeyboardButton(l.T(btnAllBookings)),
 			),
 			tgbotapi.NewKeyboardButtonRow(
-				tgbotapi.NewKeyboardButton(btnCreateBookingManager),
+				tgbotapi.NewKeyboardButton(l.T(btnCreateBookingManager)),
 			),
 			tgbotapi.NewKeyboardButtonRow(
-				tgbotapi.NewKeyboardButton(btnSyncBookings),
-				tgbotapi.NewKeyboardButton(btnSyncSchedule),
+				tgbotapi.NewKeyboardButton(l.T(btnSyncBookings)),
+				tgbotapi.NewKeyboardButton(l.T(btnSyncSchedule)),
 			),
 		)
 	}
@@ -132,14 +132,15 @@ func (b *Bot) handleMainMenu(ctx context.Context, update *tgbotapi.Update) {
 }
 
 // showManagerContacts показывает контакты менеджеров
-func (b *Bot) showManagerContacts(_ context.Context, update *tgbotapi.Update) {
+func (b *Bot) showManagerContacts(ctx context.Context, update *tgbotapi.Update) {
 	contacts := b.config.ManagersContacts
+	l := b.loc(ctx)
 	var message strings.Builder
-	message.WriteString("📞 Контакты менеджера:\n\n")
+	message.WriteString(l.T("contacts_title") + "\n\n")
 	for _, contact := range contacts {
 		message.WriteString(fmt.Sprintf("🔹 %s\n", contact))
 	}
-	message.WriteString("\nПо любым интересующим Вас вопросам, дадим ответ.")
+	message.WriteString("\n" + l.T("contacts_footer"))
 
 	msg := tgbotapi.NewMessage(update.Message.Chat.ID, message.String())
 	if _, err := b.tgService.Send(msg); err != nil {
@@ -152,12 +153,13 @@ func (b *Bot) showUserBookings(ctx context.Context, update *tgbotapi.Update) {
 	bookings, err := b.userService.GetUserBookings(ctx, update.Message.From.ID)
 	if err != nil {
 		b.logger.Error().Err(err).Int64("user_id", update.Message.From.ID).Msg("Error getting user bookings")
-		b.sendMessage(update.Message.Chat.ID, "Ошибка при получении заявок")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("error_get_bookings"))
 		return
 	}
 
+	l := b.loc(ctx)
 	var message strings.Builder
-	message.WriteString("📊 Ваши заявки (за последние 2 недели и предстоящие):\n\n")
+	message.WriteString(l.T("my_bookings_title") + "\n\n")
 
 	for _, booking := range bookings {
 		statusEmoji := statusPending
@@ -172,14 +174,14 @@ func (b *Bot) showUserBookings(ctx context.Context, update *tgbotapi.Update) {
 			statusEmoji = "🏁"
 		}
 
-		message.WriteString(fmt.Sprintf("%s Заявка #%d\n", statusEmoji, booking.ID))
+		message.WriteString(fmt.Sprintf("%s %s\n", statusEmoji, l.T("my_bookings_booking", booking.ID)))
 		message.WriteString(fmt.Sprintf("   🏢 %s\n", booking.ItemName))
-		message.WriteString(fmt.Sprintf("   📅 %s\n", booking.Date.Format("02.01.2006")))
-		message.WriteString(fmt.Sprintf("   📊 Статус: %s\n\n", booking.Status))
+		message.WriteString(fmt.Sprintf("   📅 %s\n", l.Date(booking.Date)))
+		message.WriteString(fmt.Sprintf("   📊 %s\n\n", l.T("my_bookings_status", booking.Status)))
 	}
 
 	if len(bookings) == 0 {
-		message.WriteString("У вас пока нет заявок")
+		message.WriteString(l.T("my_bookings_empty"))
 	}
 
 	b.sendMessage(update.Message.Chat.ID, message.String())
@@ -189,16 +191,16 @@ func (b *Bot) showUserBookings(ctx context.Context, update *tgbotapi.Update) {
 func (b *Bot) handleNameRequest(ctx context.Context, update *tgbotapi.Update) {
 	b.debugState(ctx, update.Message.From.ID, "handleNameRequest START")
 
-	msg := tgbotapi.NewMessage(update.Message.Chat.ID,
-		"Пожалуйста, введите ваше ФИО для заявки:")
+	l := b.loc(ctx)
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, l.T("booking_enter_name"))
 
 	keyboard := tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton(btnManagerContacts),
-			tgbotapi.NewKeyboardButton(btnCancel),
+			tgbotapi.NewKeyboardButton(l.T(btnManagerContacts)),
+			tgbotapi.NewKeyboardButton(l.T(btnCancel)),
 		),
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton(btnBack),
+			tgbotapi.NewKeyboardButton(l.T(btnBack)),
 		),
 	)
 	msg.ReplyMarkup = keyboard
@@ -215,21 +217,19 @@ func (b *Bot) handleNameRequest(ctx context.Context, update *tgbotapi.Update) {
 
 // Обновляем handlePhoneRequest - добавляем контакты
 func (b *Bot) handlePhoneRequest(ctx context.Context, update *tgbotapi.Update) {
-	msg := tgbotapi.NewMessage(update.Message.Chat.ID,
-		"Пожалуйста, предоставьте ваш номер телефона для связи:\n"+
-			"Вы можете предоставить разрешение на использование номера из контакта телеграмм\n"+
-			"Либо введите номер телефона для связи")
+	l := b.loc(ctx)
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, l.T("booking_enter_phone"))
 
 	keyboard := tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButtonContact("📱 Отправить номер телефона из вашего контакта в телеграмм"),
+			tgbotapi.NewKeyboardButtonContact(l.T("btn_share_phone")),
 		),
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton(btnManagerContacts),
-			tgbotapi.NewKeyboardButton(btnCancel),
+			tgbotapi.NewKeyboardButton(l.T(btnManagerContacts)),
+			tgbotapi.NewKeyboardButton(l.T(btnCancel)),
 		),
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton(btnBack),
+			tgbotapi.NewKeyboardButton(l.T(btnBack)),
 		),
 	)
 	msg.ReplyMarkup = keyboard
@@ -246,7 +246,7 @@ func (b *Bot) handlePhoneRequest(ctx context.Context, update *tgbotapi.Update) {
 func (b *Bot) finalizeBooking(ctx context.Context, update *tgbotapi.Update) {
 	state := b.getUserState(ctx, update.Message.From.ID)
 	if state == nil {
-		b.sendMessage(update.Message.Chat.ID, "Сессия устарела. Начните заново.")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("session_expired"))
 		b.handleMainMenu(ctx, update)
 		return
 	}
@@ -265,7 +265,7 @@ func (b *Bot) finalizeBooking(ctx context.Context, update *tgbotapi.Update) {
 	selectedItem, ok := b.getItemByID(itemID)
 
 	if !ok {
-		b.sendMessage(update.Message.Chat.ID, "Ошибка: выбранная позиция не найдена.")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("error_item_not_found"))
 		b.handleMainMenu(ctx, update)
 		return
 	}
@@ -288,7 +288,12 @@ func (b *Bot) finalizeBooking(ctx context.Context, update *tgbotapi.Update) {
 	err := b.bookingService.CreateBooking(ctx, &booking)
 	if err != nil {
 		b.logger.Error().Err(err).Int64("user_id", update.Message.From.ID).Msg("Error creating booking")
-		b.sendMessage(update.Message.Chat.ID, b.getErrorMessage(err))
+		if errors.Is(err, database.ErrNotAvailable) && b.waitlistService != nil {
+			state.TempData["user_name"] = userName
+			b.offerWaitlist(ctx, update, state)
+			return
+		}
+		b.sendMessage(update.Message.Chat.ID, b.getErrorMessage(ctx, err))
 		if errors.Is(err, database.ErrNotAvailable) || errors.Is(err, database.ErrPastDate) {
 			b.handleMainMenu(ctx, update)
 		}
@@ -302,10 +307,10 @@ func (b *Bot) finalizeBooking(ctx context.Context, update *tgbotapi.Update) {
 	}
 
 	// Уведомляем менеджеров
-	b.notifyManagers(&booking)
+	b.notifyManagers(ctx, &booking)
 
 	msg := tgbotapi.NewMessage(update.Message.Chat.ID,
-		fmt.Sprintf("⏳ Ваша заявка #%d на позицию %s успешно создана. \nОжидайте подтверждения.", booking.ID, booking.ItemName))
+		b.loc(ctx).T("booking_created", booking.ID, booking.ItemName))
 
 	// Очищаем состояние
 	b.clearUserState(ctx, update.Message.From.ID)
@@ -348,7 +353,7 @@ func (b *Bot) sendScheduleItemsPage(ctx context.Context, chatID int64, messageID
 		ChatID:       chatID,
 		MessageID:    messageID,
 		Page:         page,
-		Title:        "🏢 *Выберите аппарат для просмотра расписания:*",
+		Title:        b.loc(ctx).T("schedule_items_title"),
 		ItemPrefix:   "schedule_select_item:",
 		PagePrefix:   "schedule_items_page:",
 		BackCallback: "back_to_main_from_schedule",
@@ -383,6 +388,13 @@ func (b *Bot) handleSelectItem(ctx context.Context, update *tgbotapi.Update) {
 		return
 	}
 
+	// Пока согласие на обработку данных отозвано, новые заявки недоступны
+	if b.consentRevoked(ctx, userID) {
+		b.clearUserState(ctx, userID)
+		b.sendMessage(chatID, b.loc(ctx).T("consent_blocked"))
+		return
+	}
+
 	// Обновляем активность пользователя
 	b.updateUserActivity(userID)
 
@@ -402,7 +414,7 @@ func (b *Bot) sendItemsPage(ctx context.Context, chatID int64, messageID, page i
 		ChatID:       chatID,
 		MessageID:    messageID,
 		Page:         page,
-		Title:        "🏢 *Доступные аппараты*",
+		Title:        b.loc(ctx).T("items_title"),
 		ItemPrefix:   "select_item:",
 		PagePrefix:   "items_page:",
 		BackCallback: "back_to_main",
@@ -415,11 +427,12 @@ func (b *Bot) showAvailableItems(ctx context.Context, update *tgbotapi.Update) {
 	items, err := b.itemService.GetActiveItems(ctx)
 	if err != nil {
 		b.logger.Error().Err(err).Msg("Error getting active items")
-		b.sendMessage(update.Message.Chat.ID, "Ошибка при получении списка аппаратов")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("error_get_items"))
 		return
 	}
+	l := b.loc(ctx)
 	var message strings.Builder
-	message.WriteString("🏢 Доступные позиции:\n\n")
+	message.WriteString(l.T("items_available_title") + "\n\n")
 
 	for _, item := range items {
 		message.WriteString(fmt.Sprintf("🔹 %s\n", item.Name))
@@ -430,7 +443,7 @@ func (b *Bot) showAvailableItems(ctx context.Context, update *tgbotapi.Update) {
 	keyboard := make([][]tgbotapi.InlineKeyboardButton, 0, 1)
 
 	keyboard = append(keyboard, []tgbotapi.InlineKeyboardButton{
-		tgbotapi.NewInlineKeyboardButtonData(btnCreateBooking, "start_the_order"),
+		tgbotapi.NewInlineKeyboardButtonData(l.T(btnCreateBooking), "start_the_order"),
 	})
 
 	markup := tgbotapi.NewInlineKeyboardMarkup(keyboard...)
@@ -447,14 +460,14 @@ func (b *Bot) showAvailableItems(ctx context.Context, update *tgbotapi.Update) {
 func (b *Bot) showMonthScheduleForItem(ctx context.Context, update *tgbotapi.Update) {
 	state := b.getUserState(ctx, update.Message.From.ID)
 	if state == nil || state.TempData["item_id"] == nil {
-		b.sendMessage(update.Message.Chat.ID, "Ошибка: аппарат не выбран")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("error_item_not_selected"))
 		return
 	}
 
 	itemID := state.GetInt64("item_id")
 	selectedItem, ok := b.getItemByID(itemID)
 	if !ok {
-		b.sendMessage(update.Message.Chat.ID, "Ошибка: аппарат не найден")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("error_device_not_found"))
 		return
 	}
 	startDate := time.Now()
@@ -462,33 +475,34 @@ func (b *Bot) showMonthScheduleForItem(ctx context.Context, update *tgbotapi.Upd
 	availability, err := b.bookingService.GetAvailability(ctx, selectedItem.ID, startDate, 30)
 	if err != nil {
 		b.logger.Error().Err(err).Int64("item_id", selectedItem.ID).Msg("Error getting availability")
-		b.sendMessage(update.Message.Chat.ID, "Ошибка при получении расписания")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("error_get_schedule"))
 		return
 	}
 
+	l := b.loc(ctx)
 	var message strings.Builder
-	message.WriteString(fmt.Sprintf("📅 *Расписание %s*\n", selectedItem.Name))
-	message.WriteString("На ближайшие 30 дней:\n\n")
+	message.WriteString(l.T("schedule_title", selectedItem.Name) + "\n")
+	message.WriteString(l.N("schedule_next_days", 30) + "\n\n")
 
 	message.WriteString("```\n")
-	message.WriteString("Дата     Статус\n")
+	message.WriteString(l.T("schedule_table_header") + "\n")
 	message.WriteString("───────  ──────────\n")
 
 	for _, avail := range availability {
-		status := "✅ Свободно"
+		status := l.T("schedule_free")
 		if avail.Available == 0 {
-			status = "❌ Занято  "
+			status = l.T("schedule_busy")
 		}
 
 		message.WriteString(fmt.Sprintf("%s   %s\n",
-			avail.Date.Format("02.01"), status))
+			l.DayMonth(avail.Date), status))
 	}
 	message.WriteString("```")
 
 	keyboard := make([][]tgbotapi.InlineKeyboardButton, 0, 1)
 
 	keyboard = append(keyboard, []tgbotapi.InlineKeyboardButton{
-		tgbotapi.NewInlineKeyboardButtonData(btnCreateForItem, "start_the_order_item"),
+		tgbotapi.NewInlineKeyboardButtonData(l.T(btnCreateForItem), "start_the_order_item"),
 	})
 
 	markup := tgbotapi.NewInlineKeyboardMarkup(keyboard...)
@@ -505,21 +519,20 @@ func (b *Bot) showMonthScheduleForItem(ctx context.Context, update *tgbotapi.Upd
 func (b *Bot) handleSpecificDateInput(ctx context.Context, update *tgbotapi.Update, dateStr string) {
 	state := b.getUserState(ctx, update.Message.From.ID)
 	if state == nil || state.TempData["item_id"] == nil {
-		b.sendMessage(update.Message.Chat.ID, "Ошибка: аппарат не выбран")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("error_item_not_selected"))
 		return
 	}
 
 	itemID := state.GetInt64("item_id")
 	selectedItem, ok := b.getItemByID(itemID)
 	if !ok {
-		b.sendMessage(update.Message.Chat.ID, "Ошибка: аппарат не найден")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("error_device_not_found"))
 		return
 	}
 
 	date, err := time.Parse("02.01.2006", dateStr)
 	if err != nil {
-		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
-			"Неверный формат даты. Используйте ДД.ММ.ГГГГ (например, 25.12.2024)")
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, b.loc(ctx).T("date_invalid_format"))
 		if _, errSend := b.tgService.Send(msg); errSend != nil {
 			b.logger.Error().Err(errSend).Msg("Failed to send invalid date format msg in handleSpecificDateInput")
 		}
@@ -529,19 +542,20 @@ func (b *Bot) handleSpecificDateInput(ctx context.Context, update *tgbotapi.Upda
 	available, err := b.bookingService.CheckAvailability(ctx, selectedItem.ID, date)
 	if err != nil {
 		b.logger.Error().Err(err).Int64("item_id", selectedItem.ID).Time("date", date).Msg("Error checking availability")
-		b.sendMessage(update.Message.Chat.ID, "Ошибка при проверке доступности")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("error_check_availability"))
 		return
 	}
 
-	status := "✅ Доступно"
+	l := b.loc(ctx)
+	status := l.T("availability_yes")
 	if !available {
-		status = "❌ Недоступно"
+		status = l.T("availability_no")
 	}
 
 	booked, _ := b.bookingService.GetBookedCount(ctx, selectedItem.ID, date)
-	message := fmt.Sprintf("📅 Доступность *%s* на %s:\n\n%s\n\nЗабронировано: %d/%d",
+	message := l.T("availability_on_date",
 		selectedItem.Name,
-		date.Format("02.01.2006"),
+		l.Date(date),
 		status,
 		booked,
 		selectedItem.TotalQuantity)
@@ -555,8 +569,7 @@ func (b *Bot) handleSpecificDateInput(ctx context.Context, update *tgbotapi.Upda
 
 // requestSpecificDate запрашивает у пользователя конкретную дату
 func (b *Bot) requestSpecificDate(ctx context.Context, update *tgbotapi.Update) {
-	msg := tgbotapi.NewMessage(update.Message.Chat.ID,
-		"Введите дату в формате ДД.ММ.ГГГГ (например, 25.12.2025):")
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, b.loc(ctx).T("date_request"))
 
 	b.setUserState(ctx, update.Message.From.ID, models.StateWaitingSpecificDate, nil)
 	if _, err := b.tgService.Send(msg); err != nil {
@@ -567,7 +580,7 @@ func (b *Bot) requestSpecificDate(ctx context.Context, update *tgbotapi.Update)
 // handleCustomInput ...
 func (b *Bot) handleCustomInput(ctx context.Context, update *tgbotapi.Update, state *models.UserState) {
 	if state == nil {
-		b.sendMessage(update.Message.Chat.ID, "Неизвестная команда. Используйте меню.")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("unknown_command"))
 		b.handleMainMenu(ctx, update)
 		return
 	}
@@ -576,19 +589,19 @@ func (b *Bot) handleCustomInput(ctx context.Context, update *tgbotapi.Update, st
 	userID := update.Message.From.ID
 
 	// Обработка общих кнопок "Назад" и "Отмена"
-	if text == btnCancel {
+	if isButton(text, btnCancel) {
 		b.clearUserState(ctx, userID)
-		b.sendMessage(update.Message.Chat.ID, "❌ Действие отменено")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("action_canceled"))
 		b.handleMainMenu(ctx, update)
 		return
 	}
 
-	if text == btnBack {
+	if isButton(text, btnBack) {
 		switch state.CurrentStep {
 		case models.StateEnterName:
 			b.handleDateSelection(ctx, update, state.GetInt64("item_id"))
 			return
-		case models.StatePhoneNumber:
+		case models.StateConsent, models.StatePhoneNumber:
 			b.handleNameRequest(ctx, update)
 			return
 		case models.StateWaitingDate:
@@ -597,7 +610,7 @@ func (b *Bot) handleCustomInput(ctx context.Context, update *tgbotapi.Update, st
 		}
 	}
 
-	b.sendMessage(update.Message.Chat.ID, "Неизвестная команда. Используйте меню.")
+	b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("unknown_command"))
 	b.handleMainMenu(ctx, update)
 }
 
@@ -626,8 +639,7 @@ func (b *Bot) handleDateInput(ctx context.Context, update *tgbotapi.Update, date
 
 	date, err := time.Parse("02.01.2006", dateStr)
 	if err != nil {
-		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
-			"Неверный формат даты. Используйте ДД.ММ.ГГГГ (например, 25.12.2024)")
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, b.loc(ctx).T("date_invalid_format"))
 		if _, errSend := b.tgService.Send(msg); errSend != nil {
 			b.logger.Error().Err(errSend).Msg("Failed to send invalid date format msg in handleDateInput")
 		}
@@ -636,7 +648,7 @@ func (b *Bot) handleDateInput(ctx context.Context, update *tgbotapi.Update, date
 
 	// Валидация даты через сервис
 	if errVal := b.bookingService.ValidateBookingDate(date); errVal != nil {
-		b.sendMessage(update.Message.Chat.ID, b.getErrorMessage(errVal))
+		b.sendMessage(update.Message.Chat.ID, b.getErrorMessage(ctx, errVal))
 		return
 	}
 
@@ -644,7 +656,7 @@ func (b *Bot) handleDateInput(ctx context.Context, update *tgbotapi.Update, date
 	item, ok := b.getItemByID(itemID)
 
 	if !ok {
-		b.sendMessage(update.Message.Chat.ID, "Ошибка: не найден выбранный элемент. Начните заново.")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("error_item_missing_restart"))
 		b.handleMainMenu(ctx, update)
 		return
 	}
@@ -653,8 +665,7 @@ func (b *Bot) handleDateInput(ctx context.Context, update *tgbotapi.Update, date
 	available, err := b.bookingService.CheckAvailability(ctx, item.ID, date)
 	if err != nil {
 		b.logger.Error().Err(err).Int64("item_id", item.ID).Time("date", date).Msg("Error checking availability")
-		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
-			"Произошла ошибка при проверке доступности. Попробуйте позже.")
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, b.loc(ctx).T("error_availability_retry"))
 		if _, errSend := b.tgService.Send(msg); errSend != nil {
 			b.logger.Error().Err(errSend).Msg("Failed to send error msg in handleDateInput")
 		}
@@ -662,8 +673,7 @@ func (b *Bot) handleDateInput(ctx context.Context, update *tgbotapi.Update, date
 	}
 
 	if !available {
-		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
-			"К сожалению, на выбранную дату позиция недоступна. Выберите другую дату.")
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, b.loc(ctx).T("date_not_available"))
 		if _, errSend := b.tgService.Send(msg); errSend != nil {
 			b.logger.Error().Err(errSend).Msg("Failed to send not available msg in handleDateInput")
 		}
@@ -685,15 +695,14 @@ func (b *Bot) handleDateInput(ctx context.Context, update *tgbotapi.Update, date
 func (b *Bot) restoreStateOrRestart(ctx context.Context, update *tgbotapi.Update, requiredFields ...string) bool {
 	state := b.getUserState(ctx, update.Message.From.ID)
 	if state == nil {
-		b.sendMessage(update.Message.Chat.ID, "Сессия устарела. Начните заново.")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("session_expired"))
 		b.handleMainMenu(ctx, update)
 		return false
 	}
 
 	for _, field := range requiredFields {
 		if _, exists := state.TempData[field]; !exists {
-			b.sendMessage(update.Message.Chat.ID,
-				fmt.Sprintf("Ошибка: отсутствуют данные (%s). Начните заново.", field))
+			b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("error_missing_data", field))
 			b.handleMainMenu(ctx, update)
 			return false
 		}
@@ -730,7 +739,7 @@ func (b *Bot) handlePhoneReceived(ctx context.Context, update *tgbotapi.Update,
 	// Проверяем и нормализуем номер телефона
 	normalizedPhone := b.normalizePhone(phone)
 	if normalizedPhone == "" {
-		b.sendMessage(update.Message.Chat.ID, "Неверный формат номера телефона. Пожалуйста, введите номер в формате +7XXXXXXXXXX или 8XXXXXXXXXX")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("phone_invalid"))
 		return
 	}
 
@@ -742,7 +751,7 @@ func (b *Bot) handlePhoneReceived(ctx context.Context, update *tgbotapi.Update,
 	selectedItem, ok := b.getItemByID(itemID)
 
 	if !ok {
-		b.sendMessage(update.Message.Chat.ID, "Ошибка: выбранная позиция не найдена. Начните заново.")
+		b.sendMessage(update.Message.Chat.ID, b.loc(ctx).T("error_item_not_found_restart"))
 		b.handleMainMenu(ctx, update)
 		return
 	}
@@ -758,9 +767,12 @@ func (b *Bot) handlePhoneReceived(ctx context.Context, update *tgbotapi.Update,
 
 	// Проверяем доступность еще раз
 	available, err := b.bookingService.CheckAvailability(ctx, selectedItem.ID, date)
+	if err == nil && !available && b.waitlistService != nil {
+		b.offerWaitlist(ctx, update, state)
+		return
+	}
 	if err != nil || !available {
-		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
-			"К сожалению, выбранная позиция больше не доступна на эту дату. Пожалуйста, начните заново.")
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, b.loc(ctx).T("item_no_longer_available"))
 		if _, errSend := b.tgService.Send(msg); errSend != nil {
 			b.logger.Error().Err(errSend).Msg("Failed to send not available msg in handlePhoneReceived")
 		}
@@ -773,15 +785,11 @@ func (b *Bot) handlePhoneReceived(ctx context.Context, update *tgbotapi.Update,
 		name = ""
 	}
 
+	l := b.loc(ctx)
 	msg := tgbotapi.NewMessage(update.Message.Chat.ID,
-		fmt.Sprintf(`📋 Подтверждение заявки:
-
-🏢 Позиция: %s
-📅 Дата: %s
-👤 Имя: %s
-📱 Телефон: %s`,
+		l.T("booking_confirmation",
 			selectedItem.Name,
-			date.Format("02.01.2006"),
+			l.Date(date),
 			name,
 			normalizedPhone))
 
@@ -0,0 +1,200 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"bronivik/internal/database"
+	"bronivik/internal/domain"
+	"bronivik/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	btnJoinWaitlist    = "btn_join_waitlist"
+	btnAcceptWaitlist  = "btn_accept_waitlist"
+	btnDeclineWaitlist = "btn_decline_waitlist"
+)
+
+// SetWaitlistService подключает лист ожидания. Без него бот работает как раньше.
+func (b *Bot) SetWaitlistService(waitlistService domain.WaitlistService) {
+	b.waitlistService = waitlistService
+}
+
+// offerWaitlist предлагает встать в лист ожидания, сохраняя данные заявки в состоянии.
+func (b *Bot) offerWaitlist(ctx context.Context, update *tgbotapi.Update, state *models.UserState) {
+	userID := update.Message.From.ID
+	chatID := update.Message.Chat.ID
+
+	b.setUserState(ctx, userID, models.StateWaitlistOffer, state.TempData)
+
+	l := b.loc(ctx)
+	msg := tgbotapi.NewMessage(chatID, l.T("waitlist_offer_join"))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(l.T(btnJoinWaitlist), "waitlist_join"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(l.T(btnBack), "back_to_main"),
+		),
+	)
+	if _, err := b.tgService.Send(msg); err != nil {
+		b.logger.Error().Err(err).Int64("user_id", userID).Msg("Failed to send waitlist offer")
+	}
+}
+
+// handleWaitlistCallback обрабатывает кнопки листа ожидания.
+func (b *Bot) handleWaitlistCallback(ctx context.Context, update *tgbotapi.Update, data string) bool {
+	if b.waitlistService == nil {
+		return false
+	}
+
+	switch {
+	case data == "waitlist_join":
+		b.handleWaitlistJoin(ctx, update)
+	case strings.HasPrefix(data, "waitlist_accept:"):
+		entryID, err := strconv.ParseInt(strings.TrimPrefix(data, "waitlist_accept:"), 10, 64)
+		if err != nil {
+			return true
+		}
+		b.handleWaitlistAccept(ctx, update, entryID)
+	case strings.HasPrefix(data, "waitlist_decline:"):
+		entryID, err := strconv.ParseInt(strings.TrimPrefix(data, "waitlist_decline:"), 10, 64)
+		if err != nil {
+			return true
+		}
+		b.handleWaitlistDecline(ctx, update, entryID)
+	default:
+		return false
+	}
+	return true
+}
+
+func (b *Bot) handleWaitlistJoin(ctx context.Context, update *tgbotapi.Update) {
+	callback := update.CallbackQuery
+	userID := callback.From.ID
+	chatID := callback.Message.Chat.ID
+
+	state := b.getUserState(ctx, userID)
+	if state == nil || state.CurrentStep != models.StateWaitlistOffer || state.TempData["item_id"] == nil {
+		b.sendMessage(chatID, b.loc(ctx).T("session_expired"))
+		b.handleMainMenu(ctx, update)
+		return
+	}
+
+	item, ok := b.getItemByID(state.GetInt64("item_id"))
+	if !ok {
+		b.sendMessage(chatID, b.loc(ctx).T("error_item_not_found"))
+		b.handleMainMenu(ctx, update)
+		return
+	}
+
+	entry := &models.WaitlistEntry{
+		UserID:       userID,
+		ChatID:       chatID,
+		UserName:     state.GetString("user_name"),
+		UserNickname: strings.TrimSpace(callback.From.FirstName + " " + callback.From.LastName),
+		Phone:        state.GetString("phone"),
+		ItemID:       item.ID,
+		ItemName:     item.Name,
+		Date:         state.GetTime("date"),
+	}
+	if entry.UserName == "" {
+		entry.UserName = entry.UserNickname
+	}
+
+	err := b.waitlistService.JoinWaitlist(ctx, entry)
+	switch {
+	case errors.Is(err, database.ErrAlreadyInWaitlist):
+		b.sendMessage(chatID, b.loc(ctx).T("error_already_in_waitlist"))
+	case err != nil:
+		b.logger.Error().Err(err).Int64("user_id", userID).Msg("Error joining waitlist")
+		b.sendMessage(chatID, b.getErrorMessage(ctx, err))
+	default:
+		l := b.loc(ctx)
+		b.sendMessage(chatID, l.T("waitlist_joined", entry.ItemName, l.Date(entry.Date)))
+	}
+
+	b.clearUserState(ctx, userID)
+	b.handleMainMenu(ctx, update)
+}
+
+func (b *Bot) handleWaitlistAccept(ctx context.Context, update *tgbotapi.Update, entryID int64) {
+	callback := update.CallbackQuery
+	chatID := callback.Message.Chat.ID
+
+	if b.consentRevoked(ctx, callback.From.ID) {
+		if _, err := b.tgService.EditMessage(chatID, callback.Message.MessageID, b.loc(ctx).T("consent_blocked"), nil); err != nil {
+			b.logger.Error().Err(err).Msg("Failed to edit waitlist offer message")
+		}
+		return
+	}
+
+	booking, err := b.waitlistService.AcceptOffer(ctx, entryID, callback.From.ID)
+	if err != nil {
+		b.logger.Error().Err(err).Int64("waitlist_id", entryID).Msg("Error accepting waitlist offer")
+		text := b.getErrorMessage(ctx, err)
+		if errors.Is(err, database.ErrNotAvailable) {
+			text = b.loc(ctx).T("waitlist_slot_taken")
+		}
+		if _, errEdit := b.tgService.EditMessage(chatID, callback.Message.MessageID, text, nil); errEdit != nil {
+			b.logger.Error().Err(errEdit).Msg("Failed to edit waitlist offer message")
+		}
+		return
+	}
+
+	if b.metrics != nil {
+		b.metrics.BookingsCreated.WithLabelValues(booking.ItemName).Inc()
+	}
+	b.notifyManagers(ctx, booking)
+
+	l := b.loc(ctx)
+	text := l.T("waitlist_booking_created", booking.ID, booking.ItemName, l.Date(booking.Date))
+	if _, err := b.tgService.EditMessage(chatID, callback.Message.MessageID, text, nil); err != nil {
+		b.logger.Error().Err(err).Msg("Failed to edit waitlist offer message")
+	}
+}
+
+func (b *Bot) handleWaitlistDecline(ctx context.Context, update *tgbotapi.Update, entryID int64) {
+	callback := update.CallbackQuery
+	chatID := callback.Message.Chat.ID
+
+	text := b.loc(ctx).T("waitlist_declined")
+	if err := b.waitlistService.DeclineOffer(ctx, entryID, callback.From.ID); err != nil {
+		b.logger.Error().Err(err).Int64("waitlist_id", entryID).Msg("Error declining waitlist offer")
+		text = b.getErrorMessage(ctx, err)
+	}
+	if _, err := b.tgService.EditMessage(chatID, callback.Message.MessageID, text, nil); err != nil {
+		b.logger.Error().Err(err).Msg("Failed to edit waitlist offer message")
+	}
+}
+
+// NotifyWaitlistOffer отправляет пользователю предложение забронировать освободившееся место.
+func (b *Bot) NotifyWaitlistOffer(ctx context.Context, entry *models.WaitlistEntry) error {
+	l := b.locFor(ctx, entry.UserID)
+	text := l.T("waitlist_offer", entry.ItemName, l.Date(entry.Date))
+	if entry.OfferExpiresAt != nil {
+		text += "\n\n" + l.T("waitlist_offer_deadline", l.DateTime(*entry.OfferExpiresAt))
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(l.T(btnAcceptWaitlist), fmt.Sprintf("waitlist_accept:%d", entry.ID)),
+			tgbotapi.NewInlineKeyboardButtonData(l.T(btnDeclineWaitlist), fmt.Sprintf("waitlist_decline:%d", entry.ID)),
+		),
+	)
+
+	_, err := b.tgService.SendWithInlineKeyboard(entry.ChatID, text, keyboard)
+	return err
+}
+
+// NotifyWaitlistOfferExpired сообщает, что срок предложения истек.
+func (b *Bot) NotifyWaitlistOfferExpired(ctx context.Context, entry *models.WaitlistEntry) error {
+	l := b.locFor(ctx, entry.UserID)
+	_, err := b.tgService.SendMessage(entry.ChatID, l.T("waitlist_offer_expired", entry.ItemName, l.Date(entry.Date)))
+	return err
+}
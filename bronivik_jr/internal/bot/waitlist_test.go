@@ -0,0 +1,132 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bronivik/internal/database"
+	"bronivik/internal/domain"
+	"bronivik/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWaitlistService struct {
+	mock.Mock
+	domain.WaitlistService
+}
+
+func (m *mockWaitlistService) JoinWaitlist(ctx context.Context, entry *models.WaitlistEntry) error {
+	return m.Called(ctx, entry).Error(0)
+}
+
+func (m *mockWaitlistService) AcceptOffer(ctx context.Context, entryID, userID int64) (*models.Booking, error) {
+	args := m.Called(ctx, entryID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Booking), args.Error(1)
+}
+
+func (m *mockWaitlistService) DeclineOffer(ctx context.Context, entryID, userID int64) error {
+	return m.Called(ctx, entryID, userID).Error(0)
+}
+
+func waitlistCallback(userID int64, data string) *tgbotapi.Update {
+	return &tgbotapi.Update{
+		CallbackQuery: &tgbotapi.CallbackQuery{
+			ID:   "cb",
+			From: &tgbotapi.User{ID: userID, FirstName: "Ivan"},
+			Message: &tgbotapi.Message{
+				MessageID: 1,
+				Chat:      &tgbotapi.Chat{ID: userID},
+			},
+			Data: data,
+		},
+	}
+}
+
+func TestWaitlist_OfferedWhenNotAvailable(t *testing.T) {
+	b, mocks := setupTestBot()
+	waitlist := &mockWaitlistService{}
+	b.SetWaitlistService(waitlist)
+	mocks.booking.available = false
+	ctx := context.Background()
+	date := time.Now().AddDate(0, 0, 5).Truncate(24 * time.Hour)
+
+	_ = mocks.state.SetUserState(ctx, 456, models.StatePhoneNumber, map[string]interface{}{
+		"item_id":   int64(1),
+		"date":      date,
+		"user_name": "Test User",
+	})
+
+	b.handleMessage(ctx, &tgbotapi.Update{
+		Message: &tgbotapi.Message{
+			From: &tgbotapi.User{ID: 456},
+			Chat: &tgbotapi.Chat{ID: 456},
+			Text: "89991234567",
+		},
+	})
+
+	state := mocks.state.getStates()[456]
+	require.NotNil(t, state)
+	assert.Equal(t, models.StateWaitlistOffer, state.CurrentStep)
+
+	waitlist.On("JoinWaitlist", mock.Anything, mock.MatchedBy(func(e *models.WaitlistEntry) bool {
+		return e.UserID == 456 && e.ChatID == 456 && e.ItemID == 1 &&
+			e.UserName == "Test User" && e.Phone == "79991234567" && e.Date.Equal(date)
+	})).Return(nil).Once()
+
+	b.handleCallbackQuery(ctx, waitlistCallback(456, "waitlist_join"))
+
+	waitlist.AssertExpectations(t)
+	state = mocks.state.getStates()[456]
+	require.NotNil(t, state)
+	assert.Equal(t, models.StateMainMenu, state.CurrentStep)
+}
+
+func TestWaitlist_AcceptAndDecline(t *testing.T) {
+	b, mocks := setupTestBot()
+	waitlist := &mockWaitlistService{}
+	b.SetWaitlistService(waitlist)
+	ctx := context.Background()
+
+	booking := &models.Booking{ID: 77, UserID: 456, ItemID: 1, ItemName: "Item 1", Date: time.Now().AddDate(0, 0, 2)}
+	waitlist.On("AcceptOffer", mock.Anything, int64(5), int64(456)).Return(booking, nil).Once()
+	b.handleCallbackQuery(ctx, waitlistCallback(456, "waitlist_accept:5"))
+
+	// Менеджеры получают уведомление о новой заявке
+	assert.NotEmpty(t, mocks.tg.getSentMessages())
+
+	waitlist.On("DeclineOffer", mock.Anything, int64(6), int64(456)).Return(database.ErrWaitlistOfferExpired).Once()
+	b.handleCallbackQuery(ctx, waitlistCallback(456, "waitlist_decline:6"))
+
+	waitlist.AssertExpectations(t)
+}
+
+func TestNotifyWaitlistOffer(t *testing.T) {
+	b, mocks := setupTestBot()
+	expiresAt := time.Date(2030, 1, 2, 15, 4, 0, 0, time.UTC)
+	entry := &models.WaitlistEntry{
+		ID: 9, ChatID: 456, ItemName: "Item 1",
+		Date: time.Date(2030, 1, 3, 0, 0, 0, 0, time.UTC), OfferExpiresAt: &expiresAt,
+	}
+
+	require.NoError(t, b.NotifyWaitlistOffer(context.Background(), entry))
+
+	sent := mocks.tg.getSentMessages()
+	require.Len(t, sent, 1)
+	msg, ok := sent[0].(tgbotapi.MessageConfig)
+	require.True(t, ok)
+	assert.Contains(t, msg.Text, "03.01.2030")
+	assert.Contains(t, msg.Text, "02.01.2030 15:04")
+
+	keyboard, ok := msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+	require.True(t, ok)
+	assert.Equal(t, "waitlist_accept:9", *keyboard.InlineKeyboard[0][0].CallbackData)
+	assert.Equal(t, "waitlist_decline:9", *keyboard.InlineKeyboard[0][1].CallbackData)
+}
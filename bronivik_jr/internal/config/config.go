@@ -3,9 +3,14 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"regexp"
+	"time"
 
+	"bronivik/internal/fieldcrypt"
 	"bronivik/internal/models"
+	"bronivik/internal/retention"
 
 	"github.com/joho/godotenv"
 	"gopkg.in/yaml.v3"
@@ -27,28 +32,177 @@ type Config struct {
 	Exports          ExportConfig     `yaml:"exports"`
 	Google           GoogleConfig     `yaml:"google"`
 	Bot              BotConfig        `yaml:"bot"`
+	Events           EventsConfig     `yaml:"events"`
+	Webhooks         WebhooksConfig   `yaml:"webhooks"`
+	Encryption       EncryptionConfig `yaml:"encryption"`
+	Retention        RetentionConfig  `yaml:"retention"`
+}
+
+// EncryptionConfig включает шифрование телефонов и имен клиентов в базе.
+type EncryptionConfig struct {
+	Enabled      bool            `yaml:"enabled"`
+	PrimaryKeyID string          `yaml:"primary_key_id"` // ключ для новых значений
+	Keys         []EncryptionKey `yaml:"keys"`           // старые ключи остаются для чтения до перешифрования
+	// IndexKey — ключ blind index для поиска заявок по телефону; после включения не меняется
+	IndexKey                string   `yaml:"index_key"`
+	RotationIntervalMinutes int      `yaml:"rotation_interval_minutes"` // как часто искать значения под старыми ключами
+	RotationBatchSize       int      `yaml:"rotation_batch_size"`
+	DecryptExports          []string `yaml:"decrypt_exports"` // excel, sheets; остальные выгрузки получают маску
+}
+
+// EncryptionKey — ключ AES-256 (32 байта в base64 или hex) с идентификатором.
+type EncryptionKey struct {
+	ID  string `yaml:"id"`
+	Key string `yaml:"key"`
+}
+
+// Выгрузки, в которых можно расшифровать персональные данные.
+const (
+	ExportExcel  = "excel"
+	ExportSheets = "sheets"
+)
+
+// Cipher собирает набор ключей. При выключенном шифровании возвращает nil:
+// значения пишутся и читаются как есть.
+func (e *EncryptionConfig) Cipher() (*fieldcrypt.Cipher, error) {
+	if !e.Enabled {
+		return nil, nil
+	}
+	keys := make([]fieldcrypt.Key, 0, len(e.Keys))
+	for _, k := range e.Keys {
+		secret, err := fieldcrypt.DecodeKey(k.Key)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q: %w", k.ID, err)
+		}
+		keys = append(keys, fieldcrypt.Key{ID: k.ID, Secret: secret})
+	}
+	indexKey, err := fieldcrypt.DecodeKey(e.IndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("encryption index_key: %w", err)
+	}
+	return fieldcrypt.New(keys, e.PrimaryKeyID, indexKey)
+}
+
+// DecryptsExport сообщает, показывать ли в выгрузке расшифрованные данные.
+// Без шифрования выгрузки не меняются.
+func (e *EncryptionConfig) DecryptsExport(export string) bool {
+	if !e.Enabled {
+		return true
+	}
+	for _, name := range e.DecryptExports {
+		if name == export {
+			return true
+		}
+	}
+	return false
+}
+
+// RetentionConfig задает политику хранения персональных данных: правила
+// очищают или хешируют поля и удаляют строки старше заданного возраста.
+type RetentionConfig struct {
+	Enabled       bool            `yaml:"enabled"`
+	DryRun        bool            `yaml:"dry_run"` // только записать в лог, что было бы изменено
+	IntervalHours int             `yaml:"interval_hours"`
+	BatchSize     int             `yaml:"batch_size"`
+	HashKey       string          `yaml:"hash_key"` // ключ HMAC для action: hash, обязателен при таких правилах
+	Rules         []RetentionRule `yaml:"rules"`
+}
+
+// RetentionRule — правило политики, например «очистить bookings.phone через 180 дней».
+type RetentionRule struct {
+	Table     string `yaml:"table"`
+	Column    string `yaml:"column"` // пусто для action: delete
+	Action    string `yaml:"action"` // clear, hash, delete
+	AfterDays int    `yaml:"after_days"`
+}
+
+// Policy переводит правила в формат движка retention.
+func (r *RetentionConfig) Policy() []retention.Rule {
+	rules := make([]retention.Rule, 0, len(r.Rules))
+	for _, rule := range r.Rules {
+		rules = append(rules, retention.Rule{
+			Table:  rule.Table,
+			Column: rule.Column,
+			Action: rule.Action,
+			After:  time.Duration(rule.AfterDays) * 24 * time.Hour,
+		})
+	}
+	return rules
+}
+
+// Options возвращает настройки движка retention.
+func (r *RetentionConfig) Options() retention.Options {
+	opts := retention.Options{BatchSize: r.BatchSize}
+	if r.HashKey != "" {
+		opts.HashKey = []byte(r.HashKey)
+	}
+	return opts
+}
+
+func (r *RetentionConfig) validate() error {
+	for i, rule := range r.Rules {
+		switch rule.Action {
+		case retention.ActionClear, retention.ActionHash, retention.ActionDelete:
+		default:
+			return fmt.Errorf("retention rule %d: unknown action %q", i+1, rule.Action)
+		}
+		if rule.AfterDays <= 0 {
+			return fmt.Errorf("retention rule %d: after_days must be positive", i+1)
+		}
+		if rule.Action == retention.ActionHash && r.HashKey == "" {
+			return fmt.Errorf("retention rule %d: action hash requires retention.hash_key", i+1)
+		}
+	}
+	return nil
+}
+
+// EventsConfig управляет доставкой событий из outbox-таблицы events.
+type EventsConfig struct {
+	PollIntervalSeconds int `yaml:"poll_interval_seconds"`
+	BatchSize           int `yaml:"batch_size"`
+	MaxAttempts         int `yaml:"max_attempts"`
+	RetryDelaySeconds   int `yaml:"retry_delay_seconds"`
+	RetentionDays       int `yaml:"retention_days"` // 0 — не удалять доставленные события
+}
+
+// WebhooksConfig управляет отправкой исходящих вебхуков подписчикам.
+type WebhooksConfig struct {
+	Enabled             bool `yaml:"enabled"`
+	PollIntervalSeconds int  `yaml:"poll_interval_seconds"`
+	TimeoutSeconds      int  `yaml:"timeout_seconds"`
+	MaxAttempts         int  `yaml:"max_attempts"` // после исчерпания доставка попадает в dead letter
+	InitialDelaySeconds int  `yaml:"initial_delay_seconds"`
+	MaxDelaySeconds     int  `yaml:"max_delay_seconds"`
+	RetentionDays       int  `yaml:"retention_days"` // 0 — не удалять доставленные вебхуки
 }
 
 type BotConfig struct {
-	ReminderTime      string `yaml:"reminder_time"`
-	PaginationSize    int    `yaml:"pagination_size"`
-	MaxBookingDays    int    `yaml:"max_booking_days"`
-	MinBookingAdvance int    `yaml:"min_booking_advance"`
-	RateLimitMessages int    `yaml:"rate_limit_messages"`
-	RateLimitWindow   int    `yaml:"rate_limit_window"`
+	ReminderTime         string `yaml:"reminder_time"`
+	PaginationSize       int    `yaml:"pagination_size"`
+	MaxBookingDays       int    `yaml:"max_booking_days"`
+	MinBookingAdvance    int    `yaml:"min_booking_advance"`
+	RateLimitMessages    int    `yaml:"rate_limit_messages"`
+	RateLimitWindow      int    `yaml:"rate_limit_window"`
+	WaitlistOfferMinutes int    `yaml:"waitlist_offer_minutes"`
+	Workers              int    `yaml:"workers"`
+	QueueSize            int    `yaml:"queue_size"`
+	DrainTimeout         int    `yaml:"drain_timeout"`
 }
 
 type APIConfig struct {
-	Enabled   bool               `yaml:"enabled"`
-	HTTP      APIHTTPConfig      `yaml:"http"`
-	GRPC      APIGRPCConfig      `yaml:"grpc"`
-	Auth      APIAuthConfig      `yaml:"auth"`
-	RateLimit APIRateLimitConfig `yaml:"rate_limit"`
+	Enabled     bool                 `yaml:"enabled"`
+	HTTP        APIHTTPConfig        `yaml:"http"`
+	GRPC        APIGRPCConfig        `yaml:"grpc"`
+	Auth        APIAuthConfig        `yaml:"auth"`
+	RateLimit   APIRateLimitConfig   `yaml:"rate_limit"`
+	Stream      APIStreamConfig      `yaml:"stream"`
+	Idempotency APIIdempotencyConfig `yaml:"idempotency"`
 }
 
 type APIHTTPConfig struct {
-	Enabled bool `yaml:"enabled"`
-	Port    int  `yaml:"port"`
+	Enabled bool         `yaml:"enabled"`
+	Port    int          `yaml:"port"`
+	TLS     APITLSConfig `yaml:"tls"`
 }
 
 type APIGRPCConfig struct {
@@ -57,31 +211,73 @@ type APIGRPCConfig struct {
 	TLS        APITLSConfig `yaml:"tls"`
 }
 
+// APITLSConfig настраивает TLS сервера API. С client_ca_file сертификат клиента проверяется,
+// если клиент его прислал, а с require_client_cert — обязателен. Файлы перечитываются
+// раз в reload_interval_seconds, если изменились, поэтому обновление сертификата не требует перезапуска.
 type APITLSConfig struct {
-	Enabled           bool   `yaml:"enabled"`
-	CertFile          string `yaml:"cert_file"`
-	KeyFile           string `yaml:"key_file"`
-	ClientCAFile      string `yaml:"client_ca_file"`
-	RequireClientCert bool   `yaml:"require_client_cert"`
+	Enabled               bool   `yaml:"enabled"`
+	CertFile              string `yaml:"cert_file"`
+	KeyFile               string `yaml:"key_file"`
+	ClientCAFile          string `yaml:"client_ca_file"`
+	RequireClientCert     bool   `yaml:"require_client_cert"`
+	ReloadIntervalSeconds int    `yaml:"reload_interval_seconds"`
 }
 
 type APIAuthConfig struct {
-	Enabled      bool           `yaml:"enabled"`
-	HeaderAPIKey string         `yaml:"header_api_key"`
-	HeaderExtra  string         `yaml:"header_extra"`
-	APIKeys      []APIClientKey `yaml:"api_keys"`
+	Enabled      bool   `yaml:"enabled"`
+	HeaderAPIKey string `yaml:"header_api_key"`
+	HeaderExtra  string `yaml:"header_extra"`
+	// Статические ключи из конфига; ключи из базы (api_keys) проверяются первыми и не требуют extra
+	APIKeys               []APIClientKey   `yaml:"api_keys"`
+	ReloadIntervalSeconds int              `yaml:"reload_interval_seconds"` // как часто перечитывать ключи из базы
+	RotationGraceHours    int              `yaml:"rotation_grace_hours"`    // сколько старый ключ работает после ротации
+	Signing               APISigningConfig `yaml:"signing"`
+}
+
+// APISigningConfig настраивает HMAC-подпись запросов (x-signature, x-signature-timestamp, x-signature-nonce).
+// Подписанный запрос проверяется всегда; без required неподписанные запросы тоже принимаются.
+type APISigningConfig struct {
+	Required       bool `yaml:"required"`
+	MaxSkewSeconds int  `yaml:"max_skew_seconds"` // допустимое расхождение часов клиента и сервера
 }
 
 type APIClientKey struct {
-	Key         string   `yaml:"key"`
-	Extra       string   `yaml:"extra"`
-	Name        string   `yaml:"name"`
-	Permissions []string `yaml:"permissions"`
+	Key           string   `yaml:"key"`
+	Extra         string   `yaml:"extra"`
+	Name          string   `yaml:"name"`
+	Permissions   []string `yaml:"permissions"`
+	SigningSecret string   `yaml:"signing_secret"` // секрет HMAC-подписи; пусто — ключ не может подписывать запросы
+	// CN сертификата клиента (mTLS), закрепленного за ключом: с этим сертификатом клиент
+	// проходит без x-api-key, а сам ключ без него не принимается
+	ClientCertCN string `yaml:"client_cert_cn"`
+	// Собственные лимиты ключа; 0 — общий лимит из api.rate_limit
+	RateLimitRPS   float64 `yaml:"rate_limit_rps"`
+	RateLimitBurst int     `yaml:"rate_limit_burst"`
+	DailyQuota     int     `yaml:"daily_quota"`
+	// Stored — ключ из таблицы api_keys: без разрешений ему запрещено все
+	Stored bool `yaml:"-"`
+	// ClientID — клиент ключа из базы (api_keys.client_id), общий для всех его ротаций
+	ClientID int64 `yaml:"-"`
 }
 
+// APIRateLimitConfig — лимиты по умолчанию для каждого клиента API. Счетчики хранятся в Redis,
+// если он настроен, и тогда общие для процессов бота и API.
 type APIRateLimitConfig struct {
-	RPS   float64 `yaml:"rps"`
-	Burst int     `yaml:"burst"`
+	RPS        float64 `yaml:"rps"`
+	Burst      int     `yaml:"burst"`
+	DailyQuota int     `yaml:"daily_quota"` // запросов в сутки (UTC); 0 — без квоты
+}
+
+// APIStreamConfig настраивает потоки изменений доступности (gRPC WatchAvailability и SSE).
+type APIStreamConfig struct {
+	PollIntervalMillis int `yaml:"poll_interval_ms"`  // как часто проверять новые события
+	HeartbeatSeconds   int `yaml:"heartbeat_seconds"` // период keep-alive комментариев SSE
+	BufferSize         int `yaml:"buffer_size"`       // очередь подписчика; при переполнении поток закрывается
+}
+
+// APIIdempotencyConfig настраивает повтор изменяющих HTTP-запросов с заголовком Idempotency-Key.
+type APIIdempotencyConfig struct {
+	TTLHours int `yaml:"ttl_hours"` // сколько хранится ответ на запрос с ключом
 }
 
 type ExportConfig struct {
@@ -94,14 +290,30 @@ type AppConfig struct {
 	Version     string `yaml:"version"`
 }
 
+// TelegramConfig задает подключение к Bot API. Если указан WebhookURL, бот
+// получает обновления через webhook, иначе через long polling.
 type TelegramConfig struct {
-	BotToken   string `yaml:"bot_token"`
-	WebhookURL string `yaml:"webhook_url"`
-	Debug      bool   `yaml:"debug"`
+	BotToken        string `yaml:"bot_token"`
+	WebhookURL      string `yaml:"webhook_url"`
+	WebhookSecret   string `yaml:"webhook_secret"`
+	WebhookListen   string `yaml:"webhook_listen"`
+	WebhookCertFile string `yaml:"webhook_cert_file"`
+	WebhookKeyFile  string `yaml:"webhook_key_file"`
+	Debug           bool   `yaml:"debug"`
 }
 
+// webhookSecretPattern — допустимые символы secret_token по документации Bot API.
+var webhookSecretPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
+// Бэкенды базы данных.
+const (
+	DatabaseDriverSQLite   = "sqlite"
+	DatabaseDriverPostgres = "postgres"
+)
+
 type DatabaseConfig struct {
-	Path     string         `yaml:"path"`
+	Driver   string         `yaml:"driver"` // sqlite (по умолчанию) или postgres
+	Path     string         `yaml:"path"`   // файл SQLite
 	Postgres PostgresConfig `yaml:"postgres"`
 }
 
@@ -183,14 +395,55 @@ func (c *Config) Validate() error {
 	if c.Telegram.BotToken == "" || c.Telegram.BotToken == "YOUR_BOT_TOKEN_HERE" {
 		return errors.New("telegram bot token is required")
 	}
+	if err := c.Telegram.validateWebhook(); err != nil {
+		return err
+	}
 
-	if c.Database.Path == "" {
-		return errors.New("database path is required")
+	switch c.Database.Driver {
+	case "", DatabaseDriverSQLite:
+		if c.Database.Path == "" {
+			return errors.New("database path is required")
+		}
+	case DatabaseDriverPostgres:
+		if c.Database.Postgres.Host == "" || c.Database.Postgres.DBName == "" {
+			return errors.New("database postgres host and dbname are required")
+		}
+	default:
+		return fmt.Errorf("unknown database driver: %s", c.Database.Driver)
+	}
+
+	if _, err := c.Encryption.Cipher(); err != nil {
+		return err
+	}
+	for _, export := range c.Encryption.DecryptExports {
+		if export != ExportExcel && export != ExportSheets {
+			return fmt.Errorf("unknown encryption decrypt_exports entry: %s", export)
+		}
+	}
+	if err := c.Retention.validate(); err != nil {
+		return err
 	}
 
 	return ValidateItems(c.Items)
 }
 
+func (t *TelegramConfig) validateWebhook() error {
+	if t.WebhookURL == "" {
+		return nil
+	}
+	u, err := url.Parse(t.WebhookURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return errors.New("telegram webhook_url must be an absolute https url")
+	}
+	if !webhookSecretPattern.MatchString(t.WebhookSecret) {
+		return errors.New("telegram webhook_secret is required: 1-256 characters A-Z, a-z, 0-9, _ or -")
+	}
+	if (t.WebhookCertFile == "") != (t.WebhookKeyFile == "") {
+		return errors.New("telegram webhook_cert_file and webhook_key_file must be set together")
+	}
+	return nil
+}
+
 func ValidateItems(items []models.Item) error {
 	// Check for duplicate item IDs
 	itemIDs := make(map[int64]bool)
@@ -207,6 +460,22 @@ func ValidateItems(items []models.Item) error {
 }
 
 func (c *Config) applyDefaults() {
+	if c.Telegram.WebhookListen == "" {
+		c.Telegram.WebhookListen = ":8443"
+	}
+	if c.Database.Driver == "" {
+		c.Database.Driver = DatabaseDriverSQLite
+	}
+	if c.Database.Postgres.Port == 0 {
+		c.Database.Postgres.Port = 5432
+	}
+	if c.Database.Postgres.MaxConnections == 0 {
+		c.Database.Postgres.MaxConnections = 10
+	}
+	// Таблица версий миграций используется для обоих бэкендов
+	if c.Database.Postgres.MigrationTable == "" {
+		c.Database.Postgres.MigrationTable = "schema_migrations"
+	}
 	if c.API.GRPC.Port == 0 {
 		c.API.GRPC.Port = 8081
 	}
@@ -229,6 +498,32 @@ func (c *Config) applyDefaults() {
 	if c.API.Auth.HeaderExtra == "" {
 		c.API.Auth.HeaderExtra = "x-api-extra"
 	}
+	if c.API.Auth.ReloadIntervalSeconds == 0 {
+		c.API.Auth.ReloadIntervalSeconds = 30
+	}
+	if c.API.Auth.RotationGraceHours == 0 {
+		c.API.Auth.RotationGraceHours = 24
+	}
+	if c.API.Auth.Signing.MaxSkewSeconds == 0 {
+		c.API.Auth.Signing.MaxSkewSeconds = 300
+	}
+	for _, t := range []*APITLSConfig{&c.API.HTTP.TLS, &c.API.GRPC.TLS} {
+		if t.ReloadIntervalSeconds == 0 {
+			t.ReloadIntervalSeconds = 60
+		}
+	}
+	if c.API.Stream.PollIntervalMillis == 0 {
+		c.API.Stream.PollIntervalMillis = 1000
+	}
+	if c.API.Stream.HeartbeatSeconds == 0 {
+		c.API.Stream.HeartbeatSeconds = 15
+	}
+	if c.API.Stream.BufferSize == 0 {
+		c.API.Stream.BufferSize = 256
+	}
+	if c.API.Idempotency.TTLHours == 0 {
+		c.API.Idempotency.TTLHours = 24
+	}
 
 	// Bot defaults
 	if c.Bot.ReminderTime == "" {
@@ -246,4 +541,63 @@ func (c *Config) applyDefaults() {
 	if c.Bot.RateLimitWindow == 0 {
 		c.Bot.RateLimitWindow = models.RateLimitWindow
 	}
+	if c.Bot.WaitlistOfferMinutes == 0 {
+		c.Bot.WaitlistOfferMinutes = models.WaitlistOfferTTL / 60
+	}
+	if c.Bot.Workers <= 0 {
+		c.Bot.Workers = models.UpdateWorkers
+	}
+	if c.Bot.QueueSize <= 0 {
+		c.Bot.QueueSize = models.UpdateQueueSize
+	}
+	if c.Bot.DrainTimeout <= 0 {
+		c.Bot.DrainTimeout = models.UpdateDrainTimeout
+	}
+
+	// Events defaults
+	if c.Events.PollIntervalSeconds == 0 {
+		c.Events.PollIntervalSeconds = 2
+	}
+	if c.Events.BatchSize == 0 {
+		c.Events.BatchSize = 100
+	}
+	if c.Events.MaxAttempts == 0 {
+		c.Events.MaxAttempts = 10
+	}
+	if c.Events.RetryDelaySeconds == 0 {
+		c.Events.RetryDelaySeconds = 5
+	}
+
+	// Webhooks defaults
+	if c.Webhooks.PollIntervalSeconds == 0 {
+		c.Webhooks.PollIntervalSeconds = 2
+	}
+	if c.Webhooks.TimeoutSeconds == 0 {
+		c.Webhooks.TimeoutSeconds = 10
+	}
+	if c.Webhooks.MaxAttempts == 0 {
+		c.Webhooks.MaxAttempts = 8
+	}
+	if c.Webhooks.InitialDelaySeconds == 0 {
+		c.Webhooks.InitialDelaySeconds = 10
+	}
+	if c.Webhooks.MaxDelaySeconds == 0 {
+		c.Webhooks.MaxDelaySeconds = 3600
+	}
+
+	// Encryption defaults
+	if c.Encryption.RotationIntervalMinutes == 0 {
+		c.Encryption.RotationIntervalMinutes = 60
+	}
+	if c.Encryption.RotationBatchSize == 0 {
+		c.Encryption.RotationBatchSize = 200
+	}
+
+	// Retention defaults
+	if c.Retention.IntervalHours == 0 {
+		c.Retention.IntervalHours = 24
+	}
+	if c.Retention.BatchSize == 0 {
+		c.Retention.BatchSize = 500
+	}
 }
@@ -47,6 +47,9 @@ items:
 	}
 }
 
+// testEncryptionKey — 32 байта в base64.
+const testEncryptionKey = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+
 func TestValidateConfig(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -70,6 +73,178 @@ func TestValidateConfig(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "postgres without path",
+			cfg: Config{
+				Telegram: TelegramConfig{BotToken: "token"},
+				Database: DatabaseConfig{
+					Driver:   DatabaseDriverPostgres,
+					Postgres: PostgresConfig{Host: "localhost", DBName: "bronivik"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "postgres without dbname",
+			cfg: Config{
+				Telegram: TelegramConfig{BotToken: "token"},
+				Database: DatabaseConfig{Driver: DatabaseDriverPostgres, Postgres: PostgresConfig{Host: "localhost"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown driver",
+			cfg: Config{
+				Telegram: TelegramConfig{BotToken: "token"},
+				Database: DatabaseConfig{Driver: "mysql", Path: "path"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "webhook with secret",
+			cfg: Config{
+				Telegram: TelegramConfig{
+					BotToken:      "token",
+					WebhookURL:    "https://bot.example.com/telegram",
+					WebhookSecret: "s3cret_token-1",
+				},
+				Database: DatabaseConfig{Path: "path"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "webhook without secret",
+			cfg: Config{
+				Telegram: TelegramConfig{BotToken: "token", WebhookURL: "https://bot.example.com/telegram"},
+				Database: DatabaseConfig{Path: "path"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "webhook over http",
+			cfg: Config{
+				Telegram: TelegramConfig{
+					BotToken:      "token",
+					WebhookURL:    "http://bot.example.com/telegram",
+					WebhookSecret: "secret",
+				},
+				Database: DatabaseConfig{Path: "path"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "webhook cert without key",
+			cfg: Config{
+				Telegram: TelegramConfig{
+					BotToken:        "token",
+					WebhookURL:      "https://bot.example.com/telegram",
+					WebhookSecret:   "secret",
+					WebhookCertFile: "cert.pem",
+				},
+				Database: DatabaseConfig{Path: "path"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "encryption with keys",
+			cfg: Config{
+				Telegram: TelegramConfig{BotToken: "token"},
+				Database: DatabaseConfig{Path: "path"},
+				Encryption: EncryptionConfig{
+					Enabled:        true,
+					PrimaryKeyID:   "2025-01",
+					Keys:           []EncryptionKey{{ID: "2025-01", Key: testEncryptionKey}},
+					IndexKey:       testEncryptionKey,
+					DecryptExports: []string{ExportSheets},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "encryption primary key missing",
+			cfg: Config{
+				Telegram: TelegramConfig{BotToken: "token"},
+				Database: DatabaseConfig{Path: "path"},
+				Encryption: EncryptionConfig{
+					Enabled:      true,
+					PrimaryKeyID: "2025-02",
+					Keys:         []EncryptionKey{{ID: "2025-01", Key: testEncryptionKey}},
+					IndexKey:     testEncryptionKey,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "encryption unknown export",
+			cfg: Config{
+				Telegram: TelegramConfig{BotToken: "token"},
+				Database: DatabaseConfig{Path: "path"},
+				Encryption: EncryptionConfig{
+					Enabled:        true,
+					PrimaryKeyID:   "2025-01",
+					Keys:           []EncryptionKey{{ID: "2025-01", Key: testEncryptionKey}},
+					IndexKey:       testEncryptionKey,
+					DecryptExports: []string{"pdf"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "retention rules",
+			cfg: Config{
+				Telegram: TelegramConfig{BotToken: "token"},
+				Database: DatabaseConfig{Path: "path"},
+				Retention: RetentionConfig{Rules: []RetentionRule{
+					{Table: "bookings", Column: "phone", Action: "clear", AfterDays: 180},
+					{Table: "reminders", Action: "delete", AfterDays: 30},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "retention unknown action",
+			cfg: Config{
+				Telegram: TelegramConfig{BotToken: "token"},
+				Database: DatabaseConfig{Path: "path"},
+				Retention: RetentionConfig{Rules: []RetentionRule{
+					{Table: "bookings", Column: "phone", Action: "null", AfterDays: 180},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "retention hash without key",
+			cfg: Config{
+				Telegram: TelegramConfig{BotToken: "token"},
+				Database: DatabaseConfig{Path: "path"},
+				Retention: RetentionConfig{Rules: []RetentionRule{
+					{Table: "bookings", Column: "user_name", Action: "hash", AfterDays: 180},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "retention hash with key",
+			cfg: Config{
+				Telegram: TelegramConfig{BotToken: "token"},
+				Database: DatabaseConfig{Path: "path"},
+				Retention: RetentionConfig{HashKey: "secret", Rules: []RetentionRule{
+					{Table: "bookings", Column: "user_name", Action: "hash", AfterDays: 180},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "retention without age",
+			cfg: Config{
+				Telegram: TelegramConfig{BotToken: "token"},
+				Database: DatabaseConfig{Path: "path"},
+				Retention: RetentionConfig{Rules: []RetentionRule{
+					{Table: "reminders", Action: "delete"},
+				}},
+			},
+			wantErr: true,
+		},
 		{
 			name: "duplicate item id",
 			cfg: Config{
@@ -111,6 +286,27 @@ func TestApplyDefaults(t *testing.T) {
 	if cfg.Bot.RateLimitMessages != models.RateLimitMessages {
 		t.Errorf("expected default rate limit messages %d, got %d", models.RateLimitMessages, cfg.Bot.RateLimitMessages)
 	}
+	if cfg.Bot.Workers != models.UpdateWorkers || cfg.Bot.QueueSize != models.UpdateQueueSize {
+		t.Errorf("expected default update pool %d/%d, got %d/%d",
+			models.UpdateWorkers, models.UpdateQueueSize, cfg.Bot.Workers, cfg.Bot.QueueSize)
+	}
+	if cfg.Events.MaxAttempts != 10 {
+		t.Errorf("expected default event max attempts 10, got %d", cfg.Events.MaxAttempts)
+	}
+	if cfg.Retention.IntervalHours != 24 || cfg.Retention.BatchSize != 500 {
+		t.Errorf("expected default retention schedule 24h/500, got %dh/%d", cfg.Retention.IntervalHours, cfg.Retention.BatchSize)
+	}
+	if cfg.API.Auth.ReloadIntervalSeconds != 30 || cfg.API.Auth.RotationGraceHours != 24 {
+		t.Errorf("expected default api key reload 30s and grace 24h, got %ds/%dh",
+			cfg.API.Auth.ReloadIntervalSeconds, cfg.API.Auth.RotationGraceHours)
+	}
+	if cfg.API.Auth.Signing.MaxSkewSeconds != 300 {
+		t.Errorf("expected default signature clock skew 300s, got %ds", cfg.API.Auth.Signing.MaxSkewSeconds)
+	}
+	if cfg.API.HTTP.TLS.ReloadIntervalSeconds != 60 || cfg.API.GRPC.TLS.ReloadIntervalSeconds != 60 {
+		t.Errorf("expected default tls reload 60s, got %ds/%ds",
+			cfg.API.HTTP.TLS.ReloadIntervalSeconds, cfg.API.GRPC.TLS.ReloadIntervalSeconds)
+	}
 }
 
 func TestValidateItems(t *testing.T) {
@@ -55,8 +55,12 @@ func (db *DB) GetBlockedUser(ctx context.Context, userID int64) (*BlockedUser, e
 // BlockUser adds a user to the blocklist.
 func (db *DB) BlockUser(ctx context.Context, userID int64, reason string, blockedBy int64) error {
 	_, err := db.ExecContext(ctx,
-		`INSERT OR REPLACE INTO blocked_users (user_id, blocked_at, reason, blocked_by)
-		VALUES (?, ?, ?, ?)`,
+		`INSERT INTO blocked_users (user_id, blocked_at, reason, blocked_by)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET
+			blocked_at = excluded.blocked_at,
+			reason = excluded.reason,
+			blocked_by = excluded.blocked_by`,
 		userID, time.Now(), reason, blockedBy,
 	)
 	return err
@@ -124,8 +128,13 @@ func (db *DB) GetManager(ctx context.Context, userID int64) (*Manager, error) {
 // AddManager adds a new manager.
 func (db *DB) AddManager(ctx context.Context, userID, chatID int64, name string, addedBy int64) error {
 	_, err := db.ExecContext(ctx,
-		`INSERT OR REPLACE INTO managers (user_id, chat_id, name, added_at, added_by)
-		VALUES (?, ?, ?, ?, ?)`,
+		`INSERT INTO managers (user_id, chat_id, name, added_at, added_by)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET
+			chat_id = excluded.chat_id,
+			name = excluded.name,
+			added_at = excluded.added_at,
+			added_by = excluded.added_by`,
 		userID, chatID, name, time.Now(), addedBy,
 	)
 	return err
@@ -0,0 +1,228 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"bronivik/internal/fieldcrypt"
+	"bronivik/internal/models"
+)
+
+const apiKeyColumns = `id, name, key_prefix, key_hash, signing_secret, permissions, expires_at, last_used_at, revoked_at, replaced_by,
+	rate_limit_rps, rate_limit_burst, daily_quota, client_cert_cn, COALESCE(client_id, id), created_at, updated_at`
+
+// CreateAPIKey сохраняет ключ; key.Hash и key.Prefix должны быть заполнены.
+func (db *DB) CreateAPIKey(ctx context.Context, key *models.APIKey) error {
+	return createAPIKey(ctx, db, db.dialect, db.fields, key)
+}
+
+func createAPIKey(ctx context.Context, q querier, d dialect, c *fieldcrypt.Cipher, key *models.APIKey) error {
+	secret, err := c.Encrypt(key.SigningSecret)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt signing secret: %w", err)
+	}
+	// У первого ключа клиента client_id пустой: клиентом считается сам ключ
+	var clientID interface{}
+	if key.ClientID != 0 {
+		clientID = key.ClientID
+	}
+	now := time.Now()
+	id, err := d.insertID(ctx, q, `
+		INSERT INTO api_keys (name, key_prefix, key_hash, signing_secret, permissions, expires_at,
+			rate_limit_rps, rate_limit_burst, daily_quota, client_cert_cn, client_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		key.Name, key.Prefix, key.Hash, secret, strings.Join(key.Permissions, ","), key.ExpiresAt,
+		key.RateLimitRPS, key.RateLimitBurst, key.DailyQuota, key.ClientCertCN, clientID, now, now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+	key.ID = id
+	if key.ClientID == 0 {
+		key.ClientID = id
+	}
+	key.CreatedAt = now
+	key.UpdatedAt = now
+	return nil
+}
+
+// GetAPIKeys возвращает все ключи, включая отозванные и истекшие.
+func (db *DB) GetAPIKeys(ctx context.Context) ([]*models.APIKey, error) {
+	return db.queryAPIKeys(ctx, `ORDER BY id ASC`)
+}
+
+// GetUnrevokedAPIKeys возвращает ключи, которые не отозваны; срок действия проверяет вызывающий.
+func (db *DB) GetUnrevokedAPIKeys(ctx context.Context) ([]*models.APIKey, error) {
+	return db.queryAPIKeys(ctx, `WHERE revoked_at IS NULL ORDER BY id ASC`)
+}
+
+// GetAPIKey возвращает ключ по id или sql.ErrNoRows.
+func (db *DB) GetAPIKey(ctx context.Context, id int64) (*models.APIKey, error) {
+	keys, err := db.queryAPIKeys(ctx, `WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return keys[0], nil
+}
+
+func (db *DB) queryAPIKeys(ctx context.Context, where string, args ...interface{}) ([]*models.APIKey, error) {
+	rows, err := db.QueryContext(ctx, `SELECT `+apiKeyColumns+` FROM api_keys `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []*models.APIKey
+	for rows.Next() {
+		k := &models.APIKey{}
+		var permissions string
+		var expiresAt, lastUsedAt, revokedAt sql.NullTime
+		var replacedBy sql.NullInt64
+		if err := rows.Scan(&k.ID, &k.Name, &k.Prefix, &k.Hash, &k.SigningSecret, &permissions, &expiresAt, &lastUsedAt, &revokedAt,
+			&replacedBy, &k.RateLimitRPS, &k.RateLimitBurst, &k.DailyQuota, &k.ClientCertCN, &k.ClientID, &k.CreatedAt, &k.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		if k.SigningSecret, err = db.fields.Decrypt(k.SigningSecret); err != nil {
+			return nil, fmt.Errorf("failed to decrypt signing secret of api key %d: %w", k.ID, err)
+		}
+		k.Permissions = []string{}
+		if permissions != "" {
+			k.Permissions = strings.Split(permissions, ",")
+		}
+		if expiresAt.Valid {
+			k.ExpiresAt = &expiresAt.Time
+		}
+		if lastUsedAt.Valid {
+			k.LastUsedAt = &lastUsedAt.Time
+		}
+		if revokedAt.Valid {
+			k.RevokedAt = &revokedAt.Time
+		}
+		if replacedBy.Valid {
+			k.ReplacedBy = &replacedBy.Int64
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// RotateAPIKey создает next с именем, разрешениями, лимитами, сертификатом, клиентом и сроком жизни ключа id, а старый ключ
+// оставляет рабочим до graceUntil. Для отозванного, истекшего или уже замененного ключа возвращает sql.ErrNoRows.
+func (db *DB) RotateAPIKey(ctx context.Context, id int64, next *models.APIKey, graceUntil time.Time) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var permissions string
+	var createdAt time.Time
+	var expiresAt, revokedAt sql.NullTime
+	var replacedBy sql.NullInt64
+	err = tx.QueryRowContext(ctx, `
+		SELECT name, permissions, expires_at, revoked_at, replaced_by, rate_limit_rps, rate_limit_burst, daily_quota,
+			client_cert_cn, COALESCE(client_id, id), created_at
+		FROM api_keys WHERE id = ?`, id).
+		Scan(&next.Name, &permissions, &expiresAt, &revokedAt, &replacedBy,
+			&next.RateLimitRPS, &next.RateLimitBurst, &next.DailyQuota, &next.ClientCertCN, &next.ClientID, &createdAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return err
+		}
+		return fmt.Errorf("failed to get api key: %w", err)
+	}
+	now := time.Now()
+	if revokedAt.Valid || replacedBy.Valid || (expiresAt.Valid && !now.Before(expiresAt.Time)) {
+		return sql.ErrNoRows
+	}
+	next.Permissions = []string{}
+	if permissions != "" {
+		next.Permissions = strings.Split(permissions, ",")
+	}
+	next.ExpiresAt = nil
+	if expiresAt.Valid {
+		until := now.Add(expiresAt.Time.Sub(createdAt))
+		next.ExpiresAt = &until
+	}
+
+	if err := createAPIKey(ctx, tx, db.dialect, db.fields, next); err != nil {
+		return err
+	}
+
+	// Старый ключ не продлевается, если он истекает раньше конца льготного периода
+	if expiresAt.Valid && expiresAt.Time.Before(graceUntil) {
+		graceUntil = expiresAt.Time
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE api_keys SET expires_at = ?, replaced_by = ?, updated_at = ? WHERE id = ?`,
+		graceUntil, next.ID, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to expire rotated api key: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// UpdateAPIKeyLimits меняет лимиты ключа; для неизвестного или отозванного ключа возвращает sql.ErrNoRows.
+func (db *DB) UpdateAPIKeyLimits(ctx context.Context, id int64, limits models.APIKeyLimits) error {
+	result, err := db.ExecContext(ctx, `
+		UPDATE api_keys SET rate_limit_rps = ?, rate_limit_burst = ?, daily_quota = ?, updated_at = ?
+		WHERE id = ? AND revoked_at IS NULL`,
+		limits.RateLimitRPS, limits.RateLimitBurst, limits.DailyQuota, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update api key limits: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// UpdateAPIKeyClientCert закрепляет за ключом сертификат клиента с CN cn (пустой cn снимает привязку);
+// для неизвестного или отозванного ключа возвращает sql.ErrNoRows.
+func (db *DB) UpdateAPIKeyClientCert(ctx context.Context, id int64, cn string) error {
+	result, err := db.ExecContext(ctx, `
+		UPDATE api_keys SET client_cert_cn = ?, updated_at = ? WHERE id = ? AND revoked_at IS NULL`,
+		cn, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update api key client certificate: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// RevokeAPIKey сразу отключает ключ; для неизвестного или уже отозванного ключа возвращает sql.ErrNoRows.
+func (db *DB) RevokeAPIKey(ctx context.Context, id int64) error {
+	now := time.Now()
+	result, err := db.ExecContext(ctx, `UPDATE api_keys SET revoked_at = ?, updated_at = ? WHERE id = ? AND revoked_at IS NULL`,
+		now, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// TouchAPIKeys записывает время последнего использования ключей.
+func (db *DB) TouchAPIKeys(ctx context.Context, lastUsed map[int64]time.Time) error {
+	for id, at := range lastUsed {
+		if _, err := db.ExecContext(ctx, `UPDATE api_keys SET last_used_at = ? WHERE id = ?`, at, id); err != nil {
+			return fmt.Errorf("failed to update api key usage: %w", err)
+		}
+	}
+	return nil
+}
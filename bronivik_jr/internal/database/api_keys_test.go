@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"bronivik/internal/fieldcrypt"
+	"bronivik/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeys(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	db.SetFieldCipher(testFieldCipher(t, "k1"))
+	ctx := context.Background()
+
+	expiresAt := time.Now().Add(30 * 24 * time.Hour)
+	key := &models.APIKey{
+		Name: "crm", Prefix: "bk_1234", Hash: models.HashAPIKey("bk_1234secret"), SigningSecret: "sign-secret",
+		Permissions: []string{models.PermReadItems, models.PermReadBookings}, ExpiresAt: &expiresAt,
+		APIKeyLimits: models.APIKeyLimits{RateLimitRPS: 2.5, RateLimitBurst: 10, DailyQuota: 1000},
+	}
+	require.NoError(t, db.CreateAPIKey(ctx, key))
+
+	stored, err := db.GetAPIKey(ctx, key.ID)
+	require.NoError(t, err)
+	assert.Equal(t, key.Hash, stored.Hash)
+	assert.Equal(t, "sign-secret", stored.SigningSecret)
+
+	// Секрет подписи лежит в базе зашифрованным
+	var raw string
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT signing_secret FROM api_keys WHERE id = ?`, key.ID).Scan(&raw))
+	assert.True(t, fieldcrypt.IsEncrypted(raw))
+	assert.Equal(t, key.Permissions, stored.Permissions)
+	assert.WithinDuration(t, expiresAt, *stored.ExpiresAt, time.Second)
+	assert.Nil(t, stored.LastUsedAt)
+	assert.Equal(t, key.APIKeyLimits, stored.APIKeyLimits)
+
+	limits := models.APIKeyLimits{RateLimitRPS: 0.5, DailyQuota: 100}
+	require.NoError(t, db.UpdateAPIKeyLimits(ctx, key.ID, limits))
+	stored, err = db.GetAPIKey(ctx, key.ID)
+	require.NoError(t, err)
+	assert.Equal(t, limits, stored.APIKeyLimits)
+	assert.ErrorIs(t, db.UpdateAPIKeyLimits(ctx, 999, limits), sql.ErrNoRows)
+
+	require.NoError(t, db.UpdateAPIKeyClientCert(ctx, key.ID, "crm-client"))
+	stored, err = db.GetAPIKey(ctx, key.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "crm-client", stored.ClientCertCN)
+	assert.ErrorIs(t, db.UpdateAPIKeyClientCert(ctx, 999, "crm-client"), sql.ErrNoRows)
+
+	// Ротация: новый ключ наследует имя, разрешения, лимиты, сертификат и срок жизни, старый работает до конца льготного периода
+	graceUntil := time.Now().Add(time.Hour)
+	next := &models.APIKey{Prefix: "bk_5678", Hash: models.HashAPIKey("bk_5678secret")}
+	require.NoError(t, db.RotateAPIKey(ctx, key.ID, next, graceUntil))
+	assert.Equal(t, "crm", next.Name)
+	assert.Equal(t, key.Permissions, next.Permissions)
+	assert.Equal(t, limits, next.APIKeyLimits)
+	assert.Equal(t, "crm-client", next.ClientCertCN)
+	assert.Equal(t, key.ID, key.ClientID)
+	assert.Equal(t, key.ID, next.ClientID, "rotation keeps the client")
+	require.NotNil(t, next.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(30*24*time.Hour), *next.ExpiresAt, time.Minute)
+
+	old, err := db.GetAPIKey(ctx, key.ID)
+	require.NoError(t, err)
+	assert.Equal(t, key.ID, old.ClientID)
+	stored, err = db.GetAPIKey(ctx, next.ID)
+	require.NoError(t, err)
+	assert.Equal(t, key.ID, stored.ClientID)
+	assert.WithinDuration(t, graceUntil, *old.ExpiresAt, time.Second)
+	assert.Equal(t, next.ID, *old.ReplacedBy)
+	assert.True(t, old.ActiveAt(time.Now()))
+	assert.False(t, old.ActiveAt(graceUntil.Add(time.Second)))
+
+	err = db.RotateAPIKey(ctx, key.ID, &models.APIKey{Prefix: "bk_9", Hash: models.HashAPIKey("bk_9")}, graceUntil)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	usedAt := time.Now()
+	require.NoError(t, db.TouchAPIKeys(ctx, map[int64]time.Time{next.ID: usedAt}))
+	require.NoError(t, db.RevokeAPIKey(ctx, key.ID))
+	assert.ErrorIs(t, db.RevokeAPIKey(ctx, key.ID), sql.ErrNoRows)
+	assert.ErrorIs(t, db.UpdateAPIKeyLimits(ctx, key.ID, limits), sql.ErrNoRows)
+	assert.ErrorIs(t, db.UpdateAPIKeyClientCert(ctx, key.ID, ""), sql.ErrNoRows)
+
+	active, err := db.GetUnrevokedAPIKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	assert.Equal(t, next.ID, active[0].ID)
+	assert.WithinDuration(t, usedAt, *active[0].LastUsedAt, time.Second)
+
+	all, err := db.GetAPIKeys(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+}
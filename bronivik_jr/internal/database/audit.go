@@ -13,6 +13,11 @@ var AuditTableNames = []string{
 	"items",
 	"bookings",
 	"sync_queue",
+	"booking_series",
+	"booking_history",
+	"waitlist",
+	"consent_log",
+	"retention_log",
 }
 
 // GetTableNames returns list of table names to export.
@@ -34,44 +39,38 @@ func (db *DB) GetTableData(ctx context.Context, tableName string) (result []map[
 		return nil, nil, fmt.Errorf("invalid table name: %s", tableName)
 	}
 
-	// Get column names
-	rows, err := db.QueryContext(ctx, fmt.Sprintf("PRAGMA table_info(%s)", tableName))
+	// Get data; имена колонок берем из результата запроса, а не из PRAGMA, чтобы работало и в PostgreSQL
+	dataRows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s", tableName))
 	if err != nil {
 		return nil, nil, err
 	}
+	defer dataRows.Close()
 
-	for rows.Next() {
-		var cid int
-		var name, typeName string
-		var notNull, pk int
-		var dfltValue sql.NullString
-		if errScan := rows.Scan(&cid, &name, &typeName, &notNull, &dfltValue, &pk); errScan != nil {
-			rows.Close()
-			return nil, nil, errScan
-		}
-		columns = append(columns, name)
+	result, columns, err = scanRowMaps(dataRows)
+	if err != nil {
+		return nil, nil, err
 	}
-	rows.Close()
-
 	if len(columns) == 0 {
 		return nil, nil, fmt.Errorf("table %s has no columns", tableName)
 	}
+	return result, columns, nil
+}
 
-	// Get data
-	dataRows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s", tableName))
+// scanRowMaps читает все строки результата как map "колонка → значение".
+func scanRowMaps(rows *sql.Rows) (result []map[string]interface{}, columns []string, err error) {
+	columns, err = rows.Columns()
 	if err != nil {
 		return nil, nil, err
 	}
-	defer dataRows.Close()
 
-	for dataRows.Next() {
+	for rows.Next() {
 		values := make([]interface{}, len(columns))
 		valuePtrs := make([]interface{}, len(columns))
 		for i := range values {
 			valuePtrs[i] = &values[i]
 		}
 
-		if errScan := dataRows.Scan(valuePtrs...); errScan != nil {
+		if errScan := rows.Scan(valuePtrs...); errScan != nil {
 			return nil, nil, errScan
 		}
 
@@ -82,7 +81,7 @@ func (db *DB) GetTableData(ctx context.Context, tableName string) (result []map[
 		result = append(result, row)
 	}
 
-	return result, columns, dataRows.Err()
+	return result, columns, rows.Err()
 }
 
 // GetDB returns the underlying sql.DB.
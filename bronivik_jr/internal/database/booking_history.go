@@ -0,0 +1,162 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"bronivik/internal/events"
+	"bronivik/internal/models"
+)
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// querier — DB или Tx, в которых пишутся история и события заявки.
+type querier interface {
+	execer
+	queryRower
+}
+
+// bookingSnapshot — значения полей заявки, изменения которых пишутся в историю.
+type bookingSnapshot struct {
+	status string
+	item   string
+	dates  string
+}
+
+func getBookingSnapshot(ctx context.Context, q queryRower, id int64) (*bookingSnapshot, error) {
+	var snap bookingSnapshot
+	var date string
+	var endTime sql.NullString
+	err := q.QueryRowContext(ctx,
+		`SELECT status, item_name, CAST(date(date) AS TEXT), CAST(date(end_time) AS TEXT) FROM bookings WHERE id = ?`, id,
+	).Scan(&snap.status, &snap.item, &date, &endTime)
+	if err != nil {
+		return nil, err
+	}
+	snap.dates = date
+	if endTime.Valid && endTime.String != date {
+		snap.dates += ".." + endTime.String
+	}
+	return &snap, nil
+}
+
+// insertBookingHistory записывает одно изменение заявки; инициатор и причина берутся из контекста.
+func insertBookingHistory(ctx context.Context, ex execer, bookingID int64, field, oldValue, newValue string) error {
+	actor := models.ActorFromContext(ctx)
+	_, err := ex.ExecContext(ctx, `
+		INSERT INTO booking_history (
+			booking_id, field, old_value, new_value, actor_type, actor_id, actor_name, reason, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		bookingID, field, oldValue, newValue, string(actor.Type), actor.ID, actor.Name,
+		models.ChangeReasonFromContext(ctx), time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save booking history: %w", err)
+	}
+	return nil
+}
+
+// recordBookingCreated записывает создание заявки как переход статуса из пустого значения
+// и событие booking_created.
+func recordBookingCreated(ctx context.Context, q querier, booking *models.Booking) error {
+	if err := insertBookingHistory(ctx, q, booking.ID, models.HistoryFieldStatus, "", booking.Status); err != nil {
+		return err
+	}
+	return insertBookingEvent(ctx, q, events.EventBookingCreated, booking.ID, nil)
+}
+
+// recordBookingChanges записывает поля, значения которых отличаются в before и after,
+// и соответствующее изменению событие.
+func recordBookingChanges(ctx context.Context, q querier, bookingID int64, before, after *bookingSnapshot) error {
+	changes := []struct{ field, oldValue, newValue string }{
+		{models.HistoryFieldStatus, before.status, after.status},
+		{models.HistoryFieldItem, before.item, after.item},
+		{models.HistoryFieldDates, before.dates, after.dates},
+	}
+	for _, c := range changes {
+		if c.oldValue == c.newValue {
+			continue
+		}
+		if err := insertBookingHistory(ctx, q, bookingID, c.field, c.oldValue, c.newValue); err != nil {
+			return err
+		}
+	}
+
+	eventType := bookingEventType(before, after)
+	if eventType == "" {
+		return nil
+	}
+	return insertBookingEvent(ctx, q, eventType, bookingID, before)
+}
+
+// updateBookingWithHistory выполняет UPDATE заявки и записывает изменения в историю
+// в одной транзакции. apply описывает новые значения полей. Возвращает число
+// обновленных строк: 0, если заявки нет или версия не совпала.
+func (db *DB) updateBookingWithHistory(
+	ctx context.Context,
+	id int64,
+	apply func(*bookingSnapshot),
+	query string,
+	args ...interface{},
+) (int64, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	before, err := getBookingSnapshot(ctx, tx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get booking: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil || rows == 0 {
+		return 0, err
+	}
+
+	after := *before
+	apply(&after)
+	if err := recordBookingChanges(ctx, tx, id, before, &after); err != nil {
+		return 0, err
+	}
+	return rows, tx.Commit()
+}
+
+// GetBookingHistory возвращает историю изменений заявки, от старых к новым.
+func (db *DB) GetBookingHistory(ctx context.Context, bookingID int64) ([]*models.BookingHistoryEntry, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, booking_id, field, old_value, new_value, actor_type, actor_id, actor_name, reason, created_at
+		FROM booking_history WHERE booking_id = ? ORDER BY id ASC`, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []*models.BookingHistoryEntry
+	for rows.Next() {
+		e := &models.BookingHistoryEntry{}
+		var actorType string
+		if err := rows.Scan(&e.ID, &e.BookingID, &e.Field, &e.OldValue, &e.NewValue,
+			&actorType, &e.ActorID, &e.ActorName, &e.Reason, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan booking history: %w", err)
+		}
+		e.ActorType = models.StatusActor(actorType)
+		history = append(history, e)
+	}
+	return history, rows.Err()
+}
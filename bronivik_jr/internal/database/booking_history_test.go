@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bronivik/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookingHistory(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	item1 := &models.Item{Name: "Item 1", TotalQuantity: 1, IsActive: true}
+	item2 := &models.Item{Name: "Item 2", TotalQuantity: 1, IsActive: true}
+	require.NoError(t, db.CreateItem(ctx, item1))
+	require.NoError(t, db.CreateItem(ctx, item2))
+
+	userCtx := models.WithActor(ctx, models.Actor{Type: models.ActorUser, ID: 10})
+	booking := &models.Booking{
+		ItemID: item1.ID, ItemName: item1.Name, Date: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+		UserID: 10, UserName: "U1", Phone: "1", Status: models.StatusPending,
+	}
+	require.NoError(t, db.CreateBookingWithLock(userCtx, booking))
+
+	managerCtx := models.WithChangeReason(
+		models.WithActor(ctx, models.Actor{Type: models.ActorManager, ID: 20}), "подтверждено по телефону")
+	require.NoError(t, db.UpdateBookingStatusWithVersion(managerCtx, booking.ID, 1, models.StatusConfirmed))
+
+	apiCtx := models.WithActor(ctx, models.Actor{Type: models.ActorAPI, Name: "crm"})
+	require.NoError(t, db.UpdateBookingItemAndStatusWithVersion(apiCtx, booking.ID, 2, item2.ID, item2.Name, models.StatusChanged))
+
+	// Устаревшая версия не попадает в историю
+	assert.ErrorIs(t, db.UpdateBookingStatusWithVersion(managerCtx, booking.ID, 1, models.StatusCanceled), ErrConcurrentModification)
+
+	history, err := db.GetBookingHistory(ctx, booking.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 4)
+
+	assert.Equal(t, models.HistoryFieldStatus, history[0].Field)
+	assert.Equal(t, "", history[0].OldValue)
+	assert.Equal(t, models.StatusPending, history[0].NewValue)
+	assert.Equal(t, models.ActorUser, history[0].ActorType)
+	assert.Equal(t, int64(10), history[0].ActorID)
+
+	assert.Equal(t, models.StatusConfirmed, history[1].NewValue)
+	assert.Equal(t, models.ActorManager, history[1].ActorType)
+	assert.Equal(t, int64(20), history[1].ActorID)
+	assert.Equal(t, "подтверждено по телефону", history[1].Reason)
+
+	assert.Equal(t, models.HistoryFieldStatus, history[2].Field)
+	assert.Equal(t, models.StatusChanged, history[2].NewValue)
+	assert.Equal(t, models.HistoryFieldItem, history[3].Field)
+	assert.Equal(t, item1.Name, history[3].OldValue)
+	assert.Equal(t, item2.Name, history[3].NewValue)
+	assert.Equal(t, models.ActorAPI, history[3].ActorType)
+	assert.Equal(t, "crm", history[3].ActorName)
+}
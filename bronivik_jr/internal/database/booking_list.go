@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"bronivik/internal/models"
+)
+
+// ListBookings возвращает заявки по фильтру в порядке возрастания ID.
+// Постраничный вывод строится на курсоре AfterID, а не на OFFSET,
+// чтобы новые заявки не сдвигали уже выданные страницы.
+func (db *DB) ListBookings(ctx context.Context, filter *models.BookingFilter) ([]*models.Booking, error) {
+	query := `SELECT id, user_id, user_name, user_nickname, phone, item_id,
+	                 item_name, CAST(date(date) AS TEXT), CAST(date(end_time) AS TEXT), status, COALESCE(comment, ''), created_at,
+	                 updated_at, version, series_id, COALESCE(external_booking_id, '')
+	          FROM bookings WHERE id > ?`
+	args := []interface{}{filter.AfterID}
+
+	if filter.ItemID > 0 {
+		query += ` AND item_id = ?`
+		args = append(args, filter.ItemID)
+	}
+	if filter.UserID > 0 {
+		query += ` AND user_id = ?`
+		args = append(args, filter.UserID)
+	}
+	if filter.ExternalBookingID != "" {
+		query += ` AND external_booking_id = ?`
+		args = append(args, filter.ExternalBookingID)
+	}
+	if filter.Phone != "" {
+		// Зашифрованные телефоны ищутся по blind index, открытые — как есть
+		if db.fields != nil {
+			query += ` AND phone_index = ?`
+			args = append(args, db.fields.BlindIndex(filter.Phone))
+		} else {
+			query += ` AND phone = ?`
+			args = append(args, filter.Phone)
+		}
+	}
+	if len(filter.Statuses) > 0 {
+		query += ` AND status IN (?` + strings.Repeat(", ?", len(filter.Statuses)-1) + `)`
+		for _, s := range filter.Statuses {
+			args = append(args, s)
+		}
+	}
+	// Заявка-диапазон попадает в выборку, если пересекается с периодом хотя бы одним днем
+	if filter.From != nil {
+		query += ` AND date(COALESCE(end_time, date)) >= ?`
+		args = append(args, filter.From.Format("2006-01-02"))
+	}
+	if filter.To != nil {
+		query += ` AND date(date) <= ?`
+		args = append(args, filter.To.Format("2006-01-02"))
+	}
+	query += ` ORDER BY id ASC`
+	if filter.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bookings: %w", err)
+	}
+	defer rows.Close()
+
+	var bookings []*models.Booking
+	for rows.Next() {
+		b := &models.Booking{}
+		var dateStr string
+		var endStr sql.NullString
+		var seriesID sql.NullInt64
+		err := rows.Scan(
+			&b.ID, &b.UserID, &b.UserName, &b.UserNickname, &b.Phone,
+			&b.ItemID, &b.ItemName, &dateStr, &endStr, &b.Status, &b.Comment,
+			&b.CreatedAt, &b.UpdatedAt, &b.Version, &seriesID, &b.ExternalBookingID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan booking: %w", err)
+		}
+		if seriesID.Valid {
+			b.SeriesID = &seriesID.Int64
+		}
+		if b.Date, err = time.Parse("2006-01-02", dateStr); err != nil {
+			return nil, fmt.Errorf("failed to parse booking date %s: %w", dateStr, err)
+		}
+		if b.EndTime, err = parseEndTime(endStr); err != nil {
+			return nil, err
+		}
+		if err := openPerson(db.fields, &b.UserName, &b.Phone); err != nil {
+			return nil, fmt.Errorf("booking %d: %w", b.ID, err)
+		}
+		bookings = append(bookings, b)
+	}
+	return bookings, rows.Err()
+}
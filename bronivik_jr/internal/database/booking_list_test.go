@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bronivik/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListBookings(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	laser := &models.Item{Name: "Laser", TotalQuantity: 5, IsActive: true}
+	camera := &models.Item{Name: "Camera", TotalQuantity: 5, IsActive: true}
+	require.NoError(t, db.CreateItem(ctx, laser))
+	require.NoError(t, db.CreateItem(ctx, camera))
+
+	day := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	rangeEnd := day.AddDate(0, 0, 4)
+	create := func(item *models.Item, userID int64, date time.Time, end *time.Time, status string) *models.Booking {
+		b := &models.Booking{
+			ItemID: item.ID, ItemName: item.Name, Date: date, EndTime: end,
+			UserID: userID, UserName: "U", Phone: "1", Status: status,
+		}
+		require.NoError(t, db.CreateBookingWithLock(ctx, b))
+		return b
+	}
+	b1 := create(laser, 1, day, &rangeEnd, models.StatusPending)
+	b2 := create(camera, 2, day.AddDate(0, 0, 2), nil, models.StatusConfirmed)
+	b3 := create(laser, 1, day.AddDate(0, 0, 10), nil, models.StatusCanceled)
+
+	ids := func(bookings []*models.Booking) []int64 {
+		out := make([]int64, 0, len(bookings))
+		for _, b := range bookings {
+			out = append(out, b.ID)
+		}
+		return out
+	}
+
+	all, err := db.ListBookings(ctx, &models.BookingFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{b1.ID, b2.ID, b3.ID}, ids(all))
+	require.NotNil(t, all[0].EndTime)
+	assert.Equal(t, rangeEnd, *all[0].EndTime)
+	assert.Equal(t, int64(1), all[0].Version)
+
+	got, err := db.ListBookings(ctx, &models.BookingFilter{ItemID: laser.ID, UserID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{b1.ID, b3.ID}, ids(got))
+
+	got, err = db.ListBookings(ctx, &models.BookingFilter{Statuses: []string{models.StatusPending, models.StatusConfirmed}})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{b1.ID, b2.ID}, ids(got))
+
+	// Диапазон 03.12–04.12 пересекается с многодневной заявкой b1
+	from, to := day.AddDate(0, 0, 3), day.AddDate(0, 0, 3)
+	got, err = db.ListBookings(ctx, &models.BookingFilter{From: &from, To: &to})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{b1.ID}, ids(got))
+
+	// Курсор и размер страницы
+	got, err = db.ListBookings(ctx, &models.BookingFilter{Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{b1.ID}, ids(got))
+	got, err = db.ListBookings(ctx, &models.BookingFilter{AfterID: b1.ID, Limit: 5})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{b2.ID, b3.ID}, ids(got))
+
+	// Внешний ID сохраняется при создании, повторное использование запрещено
+	external := &models.Booking{
+		ItemID: camera.ID, ItemName: camera.Name, Date: day.AddDate(0, 0, 20),
+		UserName: "CRM", Phone: "2", Status: models.StatusConfirmed, ExternalBookingID: "crm-42",
+	}
+	require.NoError(t, db.CreateBookingWithLock(ctx, external))
+	got, err = db.ListBookings(ctx, &models.BookingFilter{ExternalBookingID: "crm-42"})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{external.ID}, ids(got))
+	assert.Equal(t, "crm-42", got[0].ExternalBookingID)
+
+	duplicate := *external
+	duplicate.Date = day.AddDate(0, 0, 21)
+	assert.ErrorIs(t, db.CreateBookingWithLock(ctx, &duplicate), ErrDuplicateExternalID)
+}
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"bronivik/internal/models"
+)
+
+// UpdateBookingDatesWithVersion переносит заявку на новую дату или период.
+// Доступность всех новых дней проверяется в той же транзакции, прежние даты
+// и статус сохраняются в booking_history.
+func (db *DB) UpdateBookingDatesWithVersion(
+	ctx context.Context,
+	id, fromVersion int64,
+	date time.Time,
+	endTime *time.Time,
+	changedBy int64,
+) error {
+	moved := &models.Booking{ID: id, Date: date, EndTime: endTime}
+	if endTime != nil && endTime.Before(date) {
+		return ErrInvalidDateRange
+	}
+
+	if actor := models.ActorFromContext(ctx); actor.ID == 0 {
+		actor.ID = changedBy
+		ctx = models.WithActor(ctx, actor)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var itemID, version int64
+	err = tx.QueryRowContext(ctx,
+		`SELECT item_id, version FROM bookings WHERE id = ?`, id,
+	).Scan(&itemID, &version)
+	if err != nil {
+		return fmt.Errorf("failed to get booking: %w", err)
+	}
+	if version != fromVersion {
+		return ErrConcurrentModification
+	}
+	if err := tx.lockItem(ctx, itemID); err != nil {
+		return fmt.Errorf("failed to lock item: %w", err)
+	}
+
+	db.mu.RLock()
+	item, ok := db.itemsCache[itemID]
+	db.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("item not found in cache: %d", itemID)
+	}
+
+	busy, err := unavailableDates(ctx, tx, itemID, int(item.TotalQuantity), moved.CoveredDates(), id)
+	if err != nil {
+		return fmt.Errorf("failed to check availability in tx: %w", err)
+	}
+	if len(busy) > 0 {
+		return &UnavailableDatesError{Dates: busy}
+	}
+
+	before, err := getBookingSnapshot(ctx, tx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get booking: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE bookings SET date = ?, end_time = ?, status = ?, version = version + 1, updated_at = ?
+		 WHERE id = ? AND version = ?`,
+		date.Format("2006-01-02"), formatEndTime(moved), models.StatusChanged, time.Now(), id, fromVersion,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update booking dates: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrConcurrentModification
+	}
+
+	after := *before
+	after.status = models.StatusChanged
+	after.dates = models.HistoryDates(date, endTime)
+	if err := recordBookingChanges(ctx, tx, id, before, &after); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
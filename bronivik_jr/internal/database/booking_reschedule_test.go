@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bronivik/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateBookingDatesWithVersion(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	item := &models.Item{Name: "Item 1", TotalQuantity: 1, IsActive: true}
+	require.NoError(t, db.CreateItem(ctx, item))
+
+	start := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	booking := &models.Booking{
+		ItemID: item.ID, ItemName: item.Name, Date: start,
+		UserID: 1, UserName: "U1", Phone: "1", Status: models.StatusConfirmed,
+	}
+	require.NoError(t, db.CreateBookingWithLock(ctx, booking))
+
+	// Чужая заявка на 05.12
+	require.NoError(t, db.CreateBookingWithLock(ctx, &models.Booking{
+		ItemID: item.ID, ItemName: item.Name, Date: start.AddDate(0, 0, 4),
+		UserID: 2, UserName: "U2", Phone: "2", Status: models.StatusConfirmed,
+	}))
+
+	// Перенос на период с занятым днем
+	end := start.AddDate(0, 0, 5)
+	err := db.UpdateBookingDatesWithVersion(ctx, booking.ID, 1, start.AddDate(0, 0, 2), &end, 100)
+	var datesErr *UnavailableDatesError
+	require.ErrorAs(t, err, &datesErr)
+	assert.Equal(t, []time.Time{start.AddDate(0, 0, 4)}, datesErr.Dates)
+
+	// Перенос на период, включающий исходную дату самой заявки
+	end = start.AddDate(0, 0, 2)
+	require.NoError(t, db.UpdateBookingDatesWithVersion(ctx, booking.ID, 1, start, &end, 100))
+
+	stored, err := db.GetBooking(ctx, booking.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stored.Version)
+	assert.Equal(t, models.StatusChanged, stored.Status)
+	require.NotNil(t, stored.EndTime)
+	assert.Equal(t, "2025-12-03", stored.EndTime.Format("2006-01-02"))
+
+	// Устаревшая версия
+	err = db.UpdateBookingDatesWithVersion(ctx, booking.ID, 1, start.AddDate(0, 0, 10), nil, 100)
+	assert.ErrorIs(t, err, ErrConcurrentModification)
+
+	require.NoError(t, db.UpdateBookingDatesWithVersion(ctx, booking.ID, 2, start.AddDate(0, 0, 10), nil, 101))
+
+	history, err := db.GetBookingHistory(ctx, booking.ID)
+	require.NoError(t, err)
+	var dates []*models.BookingHistoryEntry
+	for _, e := range history {
+		if e.Field == models.HistoryFieldDates {
+			dates = append(dates, e)
+		}
+	}
+	require.Len(t, dates, 2)
+	assert.Equal(t, "2025-12-01", dates[0].OldValue)
+	assert.Equal(t, "2025-12-01..2025-12-03", dates[0].NewValue)
+	assert.Equal(t, int64(100), dates[0].ActorID)
+	assert.Equal(t, "2025-12-01..2025-12-03", dates[1].OldValue)
+	assert.Equal(t, "2025-12-11", dates[1].NewValue)
+	assert.Equal(t, int64(101), dates[1].ActorID)
+}
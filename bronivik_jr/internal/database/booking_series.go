@@ -0,0 +1,237 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"bronivik/internal/fieldcrypt"
+	"bronivik/internal/models"
+)
+
+const seriesColumns = `id, user_id, user_name, user_nickname, phone, item_id, item_name,
+	frequency, "interval", CAST(date(start_date) AS TEXT), CAST(date(until_date) AS TEXT), occurrences, status, comment,
+	created_at, updated_at`
+
+func scanBookingSeries(row rowScanner, c *fieldcrypt.Cipher) (*models.BookingSeries, error) {
+	var series models.BookingSeries
+	var startStr string
+	var untilStr, nickname, comment sql.NullString
+
+	err := row.Scan(
+		&series.ID, &series.UserID, &series.UserName, &nickname, &series.Phone,
+		&series.ItemID, &series.ItemName, &series.Frequency, &series.Interval,
+		&startStr, &untilStr, &series.Count, &series.Status, &comment,
+		&series.CreatedAt, &series.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	series.StartDate, err = time.Parse("2006-01-02", startStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse series start date %s: %w", startStr, err)
+	}
+	if untilStr.Valid {
+		until, err := time.Parse("2006-01-02", untilStr.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse series until date %s: %w", untilStr.String, err)
+		}
+		series.UntilDate = &until
+	}
+	series.UserNickname = nickname.String
+	series.Comment = comment.String
+	if err := openPerson(c, &series.UserName, &series.Phone); err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+// CreateBookingSeriesWithLock создает серию и все ее бронирования в одной транзакции.
+// Доступность проверяется для каждой даты отдельно: занятые даты пропускаются
+// и возвращаются в списке конфликтов. Если не удалось создать ни одного
+// бронирования, транзакция откатывается и возвращается ErrNotAvailable.
+func (db *DB) CreateBookingSeriesWithLock(
+	ctx context.Context,
+	series *models.BookingSeries,
+	bookings []*models.Booking,
+) ([]*models.Booking, []models.SeriesConflict, error) {
+	db.mu.RLock()
+	item, ok := db.itemsCache[series.ItemID]
+	db.mu.RUnlock()
+	if !ok {
+		return nil, nil, fmt.Errorf("item not found in cache: %d", series.ItemID)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := tx.lockItem(ctx, series.ItemID); err != nil {
+		return nil, nil, fmt.Errorf("failed to lock item: %w", err)
+	}
+
+	now := time.Now()
+	var until interface{}
+	if series.UntilDate != nil {
+		until = series.UntilDate.Format("2006-01-02")
+	}
+	if series.Status == "" {
+		series.Status = models.SeriesStatusActive
+	}
+
+	person, err := sealPerson(db.fields, series.UserName, series.Phone)
+	if err != nil {
+		return nil, nil, err
+	}
+	seriesID, err := tx.insertID(ctx, `INSERT INTO booking_series (
+			user_id, user_name, user_nickname, phone, item_id, item_name,
+			frequency, "interval", start_date, until_date, occurrences, status, comment,
+			created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		series.UserID, person.name, series.UserNickname, person.phone,
+		series.ItemID, series.ItemName, series.Frequency, series.Interval,
+		series.StartDate.Format("2006-01-02"), until, series.Count, series.Status, series.Comment,
+		now, now,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to insert booking series: %w", err)
+	}
+
+	var created []*models.Booking
+	var conflicts []models.SeriesConflict
+	for _, booking := range bookings {
+		bookedCount, err := takenCountOn(ctx, tx, series.ItemID, booking.Date, 0)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to check availability in tx: %w", err)
+		}
+		if bookedCount >= int(item.TotalQuantity) {
+			conflicts = append(conflicts, models.SeriesConflict{Date: booking.Date, Reason: ErrNotAvailable.Error()})
+			continue
+		}
+
+		bookingPerson, err := sealPerson(db.fields, booking.UserName, booking.Phone)
+		if err != nil {
+			return nil, nil, err
+		}
+		id, err := tx.insertID(ctx, `INSERT INTO bookings (
+				user_id, user_name, user_nickname, phone, phone_index, item_id, item_name,
+				date, status, comment, created_at, updated_at, version, series_id
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			booking.UserID, bookingPerson.name, booking.UserNickname, bookingPerson.phone, bookingPerson.phoneIndex,
+			series.ItemID, series.ItemName, booking.Date.Format("2006-01-02"),
+			booking.Status, booking.Comment, now, now, 1, seriesID,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to insert series booking: %w", err)
+		}
+
+		booking.ID = id
+		booking.ItemID = series.ItemID
+		booking.ItemName = series.ItemName
+		booking.CreatedAt = now
+		booking.UpdatedAt = now
+		booking.Version = 1
+		booking.SeriesID = &seriesID
+		if err := recordBookingCreated(ctx, tx, booking); err != nil {
+			return nil, nil, err
+		}
+		created = append(created, booking)
+	}
+
+	if len(created) == 0 {
+		return nil, conflicts, ErrNotAvailable
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit booking series: %w", err)
+	}
+
+	series.ID = seriesID
+	series.CreatedAt = now
+	series.UpdatedAt = now
+	return created, conflicts, nil
+}
+
+// GetBookingSeries возвращает серию по ID.
+func (db *DB) GetBookingSeries(ctx context.Context, id int64) (*models.BookingSeries, error) {
+	row := db.QueryRowContext(ctx, `SELECT `+seriesColumns+` FROM booking_series WHERE id = ?`, id)
+	series, err := scanBookingSeries(row, db.fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking series: %w", err)
+	}
+	return series, nil
+}
+
+// GetSeriesBookings возвращает все бронирования серии в порядке дат.
+func (db *DB) GetSeriesBookings(ctx context.Context, seriesID int64) ([]*models.Booking, error) {
+	query := `SELECT id, user_id, user_name, user_nickname, phone, item_id,
+	                 item_name, CAST(date(date) AS TEXT), status, comment, created_at,
+					 updated_at, version
+              FROM bookings WHERE series_id = ? ORDER BY date ASC, id ASC`
+	rows, err := db.QueryContext(ctx, query, seriesID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get series bookings: %w", err)
+	}
+	defer rows.Close()
+
+	var bookings []*models.Booking
+	for rows.Next() {
+		b := &models.Booking{}
+		var dateStr string
+		err := rows.Scan(
+			&b.ID, &b.UserID, &b.UserName, &b.UserNickname, &b.Phone,
+			&b.ItemID, &b.ItemName, &dateStr, &b.Status, &b.Comment,
+			&b.CreatedAt, &b.UpdatedAt, &b.Version,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan series booking: %w", err)
+		}
+		b.Date, err = time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse booking date %s: %w", dateStr, err)
+		}
+		id := seriesID
+		b.SeriesID = &id
+		bookings = append(bookings, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := openBookings(db.fields, bookings); err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// UpdateBookingSeriesStatus меняет статус серии (active/canceled).
+func (db *DB) UpdateBookingSeriesStatus(ctx context.Context, id int64, status string) error {
+	result, err := db.ExecContext(ctx,
+		`UPDATE booking_series SET status = ?, updated_at = ? WHERE id = ?`,
+		status, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update series status: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// UpdateBookingSeriesItem меняет аппарат серии; бронирования обновляются отдельно.
+func (db *DB) UpdateBookingSeriesItem(ctx context.Context, id, itemID int64, itemName string) error {
+	result, err := db.ExecContext(ctx,
+		`UPDATE booking_series SET item_id = ?, item_name = ?, updated_at = ? WHERE id = ?`,
+		itemID, itemName, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update series item: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
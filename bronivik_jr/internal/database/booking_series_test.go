@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bronivik/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBookingSeriesWithLock(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	item := &models.Item{Name: "Series Item", TotalQuantity: 1, IsActive: true}
+	require.NoError(t, db.CreateItem(ctx, item))
+
+	start := time.Now().AddDate(0, 0, 7).Truncate(24 * time.Hour)
+	dates := []time.Time{start, start.AddDate(0, 0, 7), start.AddDate(0, 0, 14)}
+
+	// Вторая дата уже занята
+	require.NoError(t, db.CreateBooking(ctx, &models.Booking{
+		UserID: 99, UserName: "Other", Phone: "000", ItemID: item.ID, ItemName: item.Name,
+		Date: dates[1], Status: models.StatusConfirmed,
+	}))
+
+	series := &models.BookingSeries{
+		UserID: 1, UserName: "Client", Phone: "123", ItemID: item.ID, ItemName: item.Name,
+		Frequency: models.RecurrenceWeekly, Interval: 1, StartDate: start, Count: 3,
+	}
+	bookings := make([]*models.Booking, 0, len(dates))
+	for _, d := range dates {
+		bookings = append(bookings, &models.Booking{
+			UserID: 1, UserName: "Client", Phone: "123", Date: d, Status: models.StatusPending,
+		})
+	}
+
+	created, conflicts, err := db.CreateBookingSeriesWithLock(ctx, series, bookings)
+	require.NoError(t, err)
+	require.Len(t, created, 2)
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, dates[1], conflicts[0].Date)
+	assert.NotZero(t, series.ID)
+	assert.Equal(t, models.SeriesStatusActive, series.Status)
+
+	stored, err := db.GetBooking(ctx, created[0].ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.SeriesID)
+	assert.Equal(t, series.ID, *stored.SeriesID)
+
+	got, err := db.GetBookingSeries(ctx, series.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RecurrenceWeekly, got.Frequency)
+	assert.Equal(t, 3, got.Count)
+	assert.Equal(t, start.Format("2006-01-02"), got.StartDate.Format("2006-01-02"))
+	assert.Nil(t, got.UntilDate)
+
+	seriesBookings, err := db.GetSeriesBookings(ctx, series.ID)
+	require.NoError(t, err)
+	require.Len(t, seriesBookings, 2)
+	assert.Equal(t, dates[0].Format("2006-01-02"), seriesBookings[0].Date.Format("2006-01-02"))
+	assert.Equal(t, dates[2].Format("2006-01-02"), seriesBookings[1].Date.Format("2006-01-02"))
+
+	require.NoError(t, db.UpdateBookingSeriesStatus(ctx, series.ID, models.SeriesStatusCanceled))
+	require.NoError(t, db.UpdateBookingSeriesItem(ctx, series.ID, item.ID, "Renamed"))
+	got, err = db.GetBookingSeries(ctx, series.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.SeriesStatusCanceled, got.Status)
+	assert.Equal(t, "Renamed", got.ItemName)
+}
+
+func TestCreateBookingSeriesWithLock_AllConflicts(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	item := &models.Item{Name: "Busy Item", TotalQuantity: 1, IsActive: true}
+	require.NoError(t, db.CreateItem(ctx, item))
+
+	date := time.Now().AddDate(0, 0, 3).Truncate(24 * time.Hour)
+	require.NoError(t, db.CreateBooking(ctx, &models.Booking{
+		UserID: 99, UserName: "Other", Phone: "000", ItemID: item.ID, ItemName: item.Name,
+		Date: date, Status: models.StatusConfirmed,
+	}))
+
+	series := &models.BookingSeries{
+		UserID: 1, UserName: "Client", Phone: "123", ItemID: item.ID, ItemName: item.Name,
+		Frequency: models.RecurrenceWeekly, StartDate: date, Count: 1,
+	}
+	_, conflicts, err := db.CreateBookingSeriesWithLock(ctx, series, []*models.Booking{{
+		UserID: 1, UserName: "Client", Phone: "123", Date: date, Status: models.StatusPending,
+	}})
+	assert.ErrorIs(t, err, ErrNotAvailable)
+	assert.Len(t, conflicts, 1)
+
+	// Серия не должна сохраниться при откате
+	_, err = db.GetBookingSeries(ctx, 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"bronivik/internal/models"
+)
+
+// GetUnknownStatusCounts возвращает количество заявок по статусам,
+// которых нет в таблице переходов (например, оставшихся после миграции).
+func (db *DB) GetUnknownStatusCounts(ctx context.Context) (map[string]int, error) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(models.BookingStatuses)), ",")
+	args := make([]interface{}, 0, len(models.BookingStatuses))
+	for _, status := range models.BookingStatuses {
+		args = append(args, status)
+	}
+
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(
+		"SELECT status, COUNT(*) FROM bookings WHERE status NOT IN (%s) GROUP BY status", placeholders), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+	return counts, rows.Err()
+}
+
+// reportUnknownStatuses пишет в лог предупреждение о заявках с нестандартными статусами.
+// Такие заявки не меняются автоматически: любые переходы для них отклоняются.
+func (db *DB) reportUnknownStatuses(ctx context.Context) {
+	counts, err := db.GetUnknownStatusCounts(ctx)
+	if err != nil {
+		db.logger.Error().Err(err).Msg("Failed to check booking statuses")
+		return
+	}
+	for status, count := range counts {
+		db.logger.Warn().
+			Str("status", status).
+			Int("count", count).
+			Msg("Bookings with unknown status found; status transitions for them are blocked")
+	}
+}
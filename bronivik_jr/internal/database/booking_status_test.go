@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"bronivik/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUnknownStatusCounts(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	item := &models.Item{Name: "Item 1", TotalQuantity: 5, IsActive: true}
+	require.NoError(t, db.CreateItem(ctx, item))
+
+	date := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	for _, status := range []string{models.StatusConfirmed, "approved", "approved", "rejected"} {
+		require.NoError(t, db.CreateBooking(ctx, &models.Booking{
+			ItemID: item.ID, ItemName: item.Name, Date: date,
+			UserID: 1, UserName: "U1", Phone: "1", Status: status,
+		}))
+	}
+
+	counts, err := db.GetUnknownStatusCounts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"approved": 2, "rejected": 1}, counts)
+}
+
+func TestCancelExternalBooking_Transitions(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	item := &models.Item{Name: "Item 1", TotalQuantity: 1, IsActive: true}
+	require.NoError(t, db.CreateItem(ctx, item))
+
+	date := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	_, err := db.CreateExternalBooking(ctx, item.ID, item.Name, date, "crm-1", "Client", "")
+	require.NoError(t, err)
+
+	stored, err := db.GetExternalBooking(ctx, "crm-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusConfirmed, stored.Status)
+
+	require.NoError(t, db.CancelExternalBooking(ctx, "crm-1"))
+	assert.ErrorIs(t, db.CancelExternalBooking(ctx, "crm-1"), models.ErrInvalidTransition)
+	assert.ErrorIs(t, db.CancelExternalBooking(ctx, "crm-unknown"), sql.ErrNoRows)
+
+	// Отмененная заявка освобождает аппарат
+	_, err = db.CreateExternalBooking(ctx, item.ID, item.Name, date, "crm-2", "Client", "")
+	require.NoError(t, err)
+	_, err = db.CreateExternalBooking(ctx, item.ID, item.Name, date, "crm-3", "Client", "")
+	assert.ErrorIs(t, err, ErrNotAvailable)
+}
@@ -2,14 +2,16 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
+	"bronivik/internal/fieldcrypt"
 	"bronivik/internal/models"
 )
 
 func (db *DB) CheckAvailability(ctx context.Context, itemID int64, date time.Time) (bool, error) {
-	bookedCount, err := db.GetBookedCount(ctx, itemID, date)
+	takenCount, err := takenCountOn(ctx, db, itemID, date, 0)
 	if err != nil {
 		return false, fmt.Errorf("failed to check availability: %w", err)
 	}
@@ -21,33 +23,134 @@ func (db *DB) CheckAvailability(ctx context.Context, itemID int64, date time.Tim
 		return false, fmt.Errorf("item not found in cache: %d", itemID)
 	}
 
-	return bookedCount < int(item.TotalQuantity), nil
+	return takenCount < int(item.TotalQuantity), nil
 }
 
 func (db *DB) GetBookedCount(ctx context.Context, itemID int64, date time.Time) (int, error) {
-	query := `SELECT COUNT(*) FROM bookings WHERE item_id = ? AND date = ? AND status NOT IN (?, ?)`
-	var count int
-	err := db.QueryRowContext(ctx, query, itemID, date.Format("2006-01-02"), models.StatusCanceled, "rejected").Scan(&count)
+	count, err := bookedCountOn(ctx, db, itemID, date, 0)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get booked count: %w", err)
 	}
 	return count, nil
 }
 
+// queryRower позволяет выполнять одни и те же проверки как на DB, так и внутри Tx.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	fieldCipher() *fieldcrypt.Cipher
+}
+
+// bookedCountOn считает активные бронирования, покрывающие день date,
+// включая бронирования-диапазоны, начавшиеся раньше. excludeID исключает
+// саму переносимую заявку (0 — не исключать ничего).
+func bookedCountOn(ctx context.Context, q queryRower, itemID int64, date time.Time, excludeID int64) (int, error) {
+	query := `SELECT COUNT(*) FROM bookings
+	          WHERE item_id = ? AND date(date) <= ? AND date(COALESCE(end_time, date)) >= ?
+	            AND status NOT IN (?, ?) AND id != ?`
+	day := date.Format("2006-01-02")
+	var count int
+	err := q.QueryRowContext(ctx, query, itemID, day, day, models.StatusCanceled, "rejected", excludeID).Scan(&count)
+	return count, err
+}
+
+// heldOffersOn считает действующие предложения листа ожидания на день date:
+// предложенная единица держится за пользователем до принятия или истечения срока.
+func heldOffersOn(ctx context.Context, q queryRower, itemID int64, date time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM waitlist
+	          WHERE item_id = ? AND date = ? AND status = ? AND offer_expires_at > ?`
+	var count int
+	err := q.QueryRowContext(ctx, query, itemID, date.Format("2006-01-02"),
+		models.WaitlistStatusOffered, time.Now()).Scan(&count)
+	return count, err
+}
+
+// takenCountOn считает занятые на день date единицы: бронирования и
+// действующие предложения листа ожидания.
+func takenCountOn(ctx context.Context, q queryRower, itemID int64, date time.Time, excludeID int64) (int, error) {
+	booked, err := bookedCountOn(ctx, q, itemID, date, excludeID)
+	if err != nil {
+		return 0, err
+	}
+	held, err := heldOffersOn(ctx, q, itemID, date)
+	if err != nil {
+		return 0, err
+	}
+	return booked + held, nil
+}
+
+// unavailableDates возвращает дни из dates, на которые не осталось свободных единиц.
+func unavailableDates(
+	ctx context.Context,
+	q queryRower,
+	itemID int64,
+	total int,
+	dates []time.Time,
+	excludeID int64,
+) ([]time.Time, error) {
+	var busy []time.Time
+	for _, d := range dates {
+		count, err := takenCountOn(ctx, q, itemID, d, excludeID)
+		if err != nil {
+			return nil, err
+		}
+		if count >= total {
+			busy = append(busy, d)
+		}
+	}
+	return busy, nil
+}
+
+// formatEndTime приводит end_time к формату хранения; NULL для однодневных бронирований.
+func formatEndTime(booking *models.Booking) interface{} {
+	if !booking.IsRangeBooking() {
+		return nil
+	}
+	return booking.EndTime.Format("2006-01-02")
+}
+
+// parseEndTime разбирает date(end_time), NULL означает однодневное бронирование.
+// В PostgreSQL date() возвращает DATE, поэтому запросы приводят его к TEXT,
+// чтобы обе базы отдавали строку YYYY-MM-DD.
+func parseEndTime(endStr sql.NullString) (*time.Time, error) {
+	if !endStr.Valid || endStr.String == "" {
+		return nil, nil
+	}
+	end, err := time.Parse("2006-01-02", endStr.String)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse booking end date %s: %w", endStr.String, err)
+	}
+	return &end, nil
+}
+
 func (db *DB) CreateBooking(ctx context.Context, booking *models.Booking) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	person, err := sealPerson(db.fields, booking.UserName, booking.Phone)
+	if err != nil {
+		return err
+	}
+
 	query := `INSERT INTO bookings (
-				user_id, user_name, user_nickname, phone, item_id, item_name, 
-				date, status, comment, created_at, updated_at, version
-			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+				user_id, user_name, user_nickname, phone, phone_index, item_id, item_name, 
+				date, end_time, status, comment, created_at, updated_at, version
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	now := time.Now()
-	result, err := db.ExecContext(ctx, query,
+	id, err := tx.insertID(ctx, query,
 		booking.UserID,
-		booking.UserName,
+		person.name,
 		booking.UserNickname,
-		booking.Phone,
+		person.phone,
+		person.phoneIndex,
 		booking.ItemID,
 		booking.ItemName,
 		booking.Date.Format("2006-01-02"),
+		formatEndTime(booking),
 		booking.Status,
 		booking.Comment,
 		now,
@@ -57,17 +160,15 @@ func (db *DB) CreateBooking(ctx context.Context, booking *models.Booking) error
 	if err != nil {
 		return fmt.Errorf("failed to create booking: %w", err)
 	}
-
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get last insert id: %w", err)
-	}
 	booking.ID = id
 	booking.CreatedAt = now
 	booking.UpdatedAt = now
 	booking.Version = 1
 
-	return nil
+	if err := recordBookingCreated(ctx, tx, booking); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (db *DB) CreateBookingWithLock(ctx context.Context, booking *models.Booking) error {
@@ -79,13 +180,8 @@ func (db *DB) CreateBookingWithLock(ctx context.Context, booking *models.Booking
 		_ = tx.Rollback()
 	}()
 
-	// 1. Check availability inside transaction
-	var bookedCount int
-	queryCount := `SELECT COUNT(*) FROM bookings WHERE item_id = ? AND date = ? AND status NOT IN (?, ?)`
-	err = tx.QueryRowContext(ctx, queryCount, booking.ItemID,
-		booking.Date.Format("2006-01-02"), models.StatusCanceled, "rejected").Scan(&bookedCount)
-	if err != nil {
-		return fmt.Errorf("failed to check availability in tx: %w", err)
+	if booking.EndTime != nil && booking.EndTime.Before(booking.Date) {
+		return ErrInvalidDateRange
 	}
 
 	db.mu.RLock()
@@ -95,26 +191,65 @@ func (db *DB) CreateBookingWithLock(ctx context.Context, booking *models.Booking
 		return fmt.Errorf("item not found in cache: %d", booking.ItemID)
 	}
 
-	if bookedCount >= int(item.TotalQuantity) {
-		return ErrNotAvailable
+	// Параллельные бронирования одного аппарата проверяют занятость по очереди
+	if err := tx.lockItem(ctx, booking.ItemID); err != nil {
+		return fmt.Errorf("failed to lock item: %w", err)
+	}
+
+	// 1. Check availability for every covered day inside transaction
+	busy, err := unavailableDates(ctx, tx, booking.ItemID, int(item.TotalQuantity), booking.CoveredDates(), 0)
+	if err != nil {
+		return fmt.Errorf("failed to check availability in tx: %w", err)
+	}
+	if len(busy) > 0 {
+		return &UnavailableDatesError{Dates: busy}
+	}
+
+	var externalID interface{}
+	if booking.ExternalBookingID != "" {
+		var existing int64
+		err = tx.QueryRowContext(ctx, `SELECT id FROM bookings WHERE external_booking_id = ?`,
+			booking.ExternalBookingID).Scan(&existing)
+		if err == nil {
+			return ErrDuplicateExternalID
+		}
+		if err != sql.ErrNoRows {
+			return fmt.Errorf("failed to check external booking id: %w", err)
+		}
+		externalID = booking.ExternalBookingID
 	}
 
 	// 2. Create booking
+	if err := insertBooking(ctx, tx, booking, externalID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertBooking сохраняет проверенную заявку внутри транзакции и пишет событие booking_created.
+func insertBooking(ctx context.Context, tx *Tx, booking *models.Booking, externalID interface{}) error {
+	person, err := sealPerson(tx.fields, booking.UserName, booking.Phone)
+	if err != nil {
+		return err
+	}
 	queryInsert := `INSERT INTO bookings (
-				user_id, user_name, user_nickname, phone, item_id, item_name, 
-				date, status, comment, created_at, updated_at, version
-			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+				user_id, user_name, user_nickname, phone, phone_index, item_id, item_name, 
+				date, end_time, status, comment, external_booking_id, created_at, updated_at, version
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	now := time.Now()
-	result, err := tx.ExecContext(ctx, queryInsert,
+	id, err := tx.insertID(ctx, queryInsert,
 		booking.UserID,
-		booking.UserName,
+		person.name,
 		booking.UserNickname,
-		booking.Phone,
+		person.phone,
+		person.phoneIndex,
 		booking.ItemID,
 		booking.ItemName,
 		booking.Date.Format("2006-01-02"),
+		formatEndTime(booking),
 		booking.Status,
 		booking.Comment,
+		externalID,
 		now,
 		now,
 		1,
@@ -122,17 +257,12 @@ func (db *DB) CreateBookingWithLock(ctx context.Context, booking *models.Booking
 	if err != nil {
 		return fmt.Errorf("failed to insert booking in tx: %w", err)
 	}
-
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get last insert id in tx: %w", err)
-	}
 	booking.ID = id
 	booking.CreatedAt = now
 	booking.UpdatedAt = now
 	booking.Version = 1
 
-	return tx.Commit()
+	return recordBookingCreated(ctx, tx, booking)
 }
 
 func (db *DB) UpdateBookingComment(ctx context.Context, bookingID int64, comment string) error {
@@ -142,41 +272,57 @@ func (db *DB) UpdateBookingComment(ctx context.Context, bookingID int64, comment
 }
 
 func (db *DB) GetBooking(ctx context.Context, id int64) (*models.Booking, error) {
+	return getBooking(ctx, db, id)
+}
+
+func getBooking(ctx context.Context, q queryRower, id int64) (*models.Booking, error) {
 	var booking models.Booking
 	var dateStr string
+	var endStr sql.NullString
+	var seriesID sql.NullInt64
 	query := `SELECT id, user_id, user_name, user_nickname, phone, item_id, 
-	                 item_name, date(date), status, comment, created_at, 
-					 updated_at, version 
+	                 item_name, CAST(date(date) AS TEXT), CAST(date(end_time) AS TEXT), status, COALESCE(comment, ''), created_at, 
+					 updated_at, version, series_id, COALESCE(external_booking_id, '') 
               FROM bookings WHERE id = ?`
-	err := db.QueryRowContext(ctx, query, id).Scan(
+	err := q.QueryRowContext(ctx, query, id).Scan(
 		&booking.ID, &booking.UserID, &booking.UserName, &booking.UserNickname, &booking.Phone,
-		&booking.ItemID, &booking.ItemName, &dateStr, &booking.Status, &booking.Comment,
-		&booking.CreatedAt, &booking.UpdatedAt, &booking.Version,
+		&booking.ItemID, &booking.ItemName, &dateStr, &endStr, &booking.Status, &booking.Comment,
+		&booking.CreatedAt, &booking.UpdatedAt, &booking.Version, &seriesID, &booking.ExternalBookingID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get booking: %w", err)
 	}
+	if seriesID.Valid {
+		booking.SeriesID = &seriesID.Int64
+	}
 
 	booking.Date, err = time.Parse("2006-01-02", dateStr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse booking date %s: %w", dateStr, err)
 	}
+	if booking.EndTime, err = parseEndTime(endStr); err != nil {
+		return nil, err
+	}
+	if err = openPerson(q.fieldCipher(), &booking.UserName, &booking.Phone); err != nil {
+		return nil, fmt.Errorf("booking %d: %w", booking.ID, err)
+	}
 	return &booking, nil
 }
 
 func (db *DB) UpdateBookingStatus(ctx context.Context, id int64, status string) error {
 	query := `UPDATE bookings SET status = ?, updated_at = ? WHERE id = ?`
-	_, err := db.ExecContext(ctx, query, status, time.Now(), id)
+	_, err := db.updateBookingWithHistory(ctx, id, func(s *bookingSnapshot) { s.status = status },
+		query, status, time.Now(), id)
 	return err
 }
 
 func (db *DB) UpdateBookingStatusWithVersion(ctx context.Context, id, fromVersion int64, status string) error {
 	query := `UPDATE bookings SET status = ?, version = version + 1, updated_at = ? WHERE id = ? AND version = ?`
-	result, err := db.ExecContext(ctx, query, status, time.Now(), id, fromVersion)
+	rows, err := db.updateBookingWithHistory(ctx, id, func(s *bookingSnapshot) { s.status = status },
+		query, status, time.Now(), id, fromVersion)
 	if err != nil {
 		return fmt.Errorf("failed to update booking status: %w", err)
 	}
-	rows, _ := result.RowsAffected()
 	if rows == 0 {
 		return ErrConcurrentModification
 	}
@@ -184,10 +330,11 @@ func (db *DB) UpdateBookingStatusWithVersion(ctx context.Context, id, fromVersio
 }
 
 func (db *DB) GetBookingsByDateRange(ctx context.Context, startDate, endDate time.Time) ([]*models.Booking, error) {
+	// Бронирование-диапазон попадает в выборку, если пересекается с периодом хотя бы одним днем
 	query := `SELECT id, user_id, user_name, user_nickname, phone, item_id, 
-	                 item_name, date(date), status, comment, created_at, 
+	                 item_name, CAST(date(date) AS TEXT), CAST(date(end_time) AS TEXT), status, COALESCE(comment, ''), created_at, 
 					 updated_at, version 
-              FROM bookings WHERE date(date) >= ? AND date(date) <= ? ORDER BY date ASC`
+              FROM bookings WHERE date(COALESCE(end_time, date)) >= ? AND date(date) <= ? ORDER BY date ASC`
 	rows, err := db.QueryContext(ctx, query, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get bookings by date range: %w", err)
@@ -198,28 +345,33 @@ func (db *DB) GetBookingsByDateRange(ctx context.Context, startDate, endDate tim
 	for rows.Next() {
 		b := &models.Booking{}
 		var dateStr string
+		var endStr sql.NullString
 		err := rows.Scan(
 			&b.ID, &b.UserID, &b.UserName, &b.UserNickname, &b.Phone,
-			&b.ItemID, &b.ItemName, &dateStr, &b.Status, &b.Comment,
+			&b.ItemID, &b.ItemName, &dateStr, &endStr, &b.Status, &b.Comment,
 			&b.CreatedAt, &b.UpdatedAt, &b.Version,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan booking: %w", err)
 		}
 		b.Date, _ = time.Parse("2006-01-02", dateStr)
+		b.EndTime, _ = parseEndTime(endStr)
 		bookings = append(bookings, b)
 	}
+	if err := openBookings(db.fields, bookings); err != nil {
+		return nil, err
+	}
 	return bookings, nil
 }
 
 func (db *DB) GetAvailabilityForPeriod(ctx context.Context, itemID int64, startDate time.Time, days int) ([]*models.Availability, error) {
 	endDate := startDate.AddDate(0, 0, days-1)
 
-	// Используем date() для нормализации даты в SQLite
-	query := `SELECT date(date) as d, COUNT(*) as booked_count 
+	// Используем date() для нормализации даты; диапазоны раскладываем по дням ниже
+	query := `SELECT CAST(date(date) AS TEXT), CAST(date(end_time) AS TEXT)
               FROM bookings 
-              WHERE item_id = ? AND date BETWEEN ? AND ? AND status NOT IN (?, ?)
-              GROUP BY d`
+              WHERE item_id = ? AND date(COALESCE(end_time, date)) >= ? AND date(date) <= ?
+                AND status NOT IN (?, ?)`
 
 	rows, err := db.QueryContext(ctx, query, itemID,
 		startDate.Format("2006-01-02"), endDate.Format("2006-01-02"),
@@ -231,12 +383,44 @@ func (db *DB) GetAvailabilityForPeriod(ctx context.Context, itemID int64, startD
 
 	bookedCounts := make(map[string]int)
 	for rows.Next() {
+		var dateStr string
+		var endStr sql.NullString
+		if err := rows.Scan(&dateStr, &endStr); err != nil {
+			return nil, err
+		}
+		b := &models.Booking{}
+		if b.Date, err = time.Parse("2006-01-02", dateStr); err != nil {
+			return nil, fmt.Errorf("failed to parse booking date %s: %w", dateStr, err)
+		}
+		if b.EndTime, err = parseEndTime(endStr); err != nil {
+			return nil, err
+		}
+		for _, d := range b.CoveredDates() {
+			bookedCounts[d.Format("2006-01-02")]++
+		}
+	}
+
+	// Предложенные из листа ожидания единицы недоступны остальным
+	heldRows, err := db.QueryContext(ctx, `
+		SELECT CAST(date(date) AS TEXT), COUNT(*) FROM waitlist
+		WHERE item_id = ? AND date >= ? AND date <= ? AND status = ? AND offer_expires_at > ?
+		GROUP BY date`, itemID, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"),
+		models.WaitlistStatusOffered, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get held waitlist offers: %w", err)
+	}
+	defer heldRows.Close()
+	held := make(map[string]int)
+	for heldRows.Next() {
 		var dateStr string
 		var count int
-		if err := rows.Scan(&dateStr, &count); err != nil {
+		if err := heldRows.Scan(&dateStr, &count); err != nil {
 			return nil, err
 		}
-		bookedCounts[dateStr] = count
+		held[dateStr] = count
+	}
+	if err := heldRows.Err(); err != nil {
+		return nil, err
 	}
 
 	db.mu.RLock()
@@ -249,7 +433,7 @@ func (db *DB) GetAvailabilityForPeriod(ctx context.Context, itemID int64, startD
 		dateStr := date.Format("2006-01-02")
 		booked := bookedCounts[dateStr]
 
-		available := int(item.TotalQuantity) - booked
+		available := int(item.TotalQuantity) - booked - held[dateStr]
 		if available < 0 {
 			available = 0
 		}
@@ -266,43 +450,106 @@ func (db *DB) GetAvailabilityForPeriod(ctx context.Context, itemID int64, startD
 
 func (db *DB) UpdateBookingItem(ctx context.Context, id, itemID int64, itemName string) error {
 	query := `UPDATE bookings SET item_id = ?, item_name = ?, updated_at = ? WHERE id = ?`
-	_, err := db.ExecContext(ctx, query, itemID, itemName, time.Now(), id)
+	_, err := db.updateBookingWithHistory(ctx, id, func(s *bookingSnapshot) { s.item = itemName },
+		query, itemID, itemName, time.Now(), id)
 	return err
 }
 
 func (db *DB) UpdateBookingItemWithVersion(ctx context.Context, id, fromVersion, itemID int64, itemName string) error {
 	query := `UPDATE bookings SET item_id = ?, item_name = ?, version = version + 1, updated_at = ? WHERE id = ? AND version = ?`
-	result, err := db.ExecContext(ctx, query, itemID, itemName, time.Now(), id, fromVersion)
+	rows, err := db.updateBookingWithHistory(ctx, id, func(s *bookingSnapshot) { s.item = itemName },
+		query, itemID, itemName, time.Now(), id, fromVersion)
 	if err != nil {
 		return fmt.Errorf("failed to update booking item: %w", err)
 	}
-	rows, _ := result.RowsAffected()
 	if rows == 0 {
 		return ErrConcurrentModification
 	}
 	return nil
 }
 
+// UpdateBookingItemAndStatusWithVersion переводит заявку на другой аппарат.
+// Доступность нового аппарата на все дни заявки проверяется в той же транзакции
+// под блокировкой аппарата, как при создании бронирования.
 func (db *DB) UpdateBookingItemAndStatusWithVersion(ctx context.Context, id, fromVersion, itemID int64, itemName, status string) error {
-	query := `UPDATE bookings SET item_id = ?, item_name = ?, status = ?, version = version + 1, updated_at = ? WHERE id = ? AND version = ?`
-	result, err := db.ExecContext(ctx, query, itemID, itemName, status, time.Now(), id, fromVersion)
+	db.mu.RLock()
+	item, ok := db.itemsCache[itemID]
+	db.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("item not found in cache: %d", itemID)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	current := &models.Booking{ID: id}
+	var dateStr string
+	var endStr sql.NullString
+	var version int64
+	err = tx.QueryRowContext(ctx,
+		`SELECT CAST(date(date) AS TEXT), CAST(date(end_time) AS TEXT), version FROM bookings WHERE id = ?`, id,
+	).Scan(&dateStr, &endStr, &version)
+	if err != nil {
+		return fmt.Errorf("failed to get booking: %w", err)
+	}
+	if version != fromVersion {
+		return ErrConcurrentModification
+	}
+	if current.Date, err = time.Parse("2006-01-02", dateStr); err != nil {
+		return fmt.Errorf("failed to parse booking date %s: %w", dateStr, err)
+	}
+	if current.EndTime, err = parseEndTime(endStr); err != nil {
+		return err
+	}
+
+	if err := tx.lockItem(ctx, itemID); err != nil {
+		return fmt.Errorf("failed to lock item: %w", err)
+	}
+	busy, err := unavailableDates(ctx, tx, itemID, int(item.TotalQuantity), current.CoveredDates(), id)
+	if err != nil {
+		return fmt.Errorf("failed to check availability in tx: %w", err)
+	}
+	if len(busy) > 0 {
+		return &UnavailableDatesError{Dates: busy}
+	}
+
+	before, err := getBookingSnapshot(ctx, tx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get booking: %w", err)
+	}
+	result, err := tx.ExecContext(ctx,
+		`UPDATE bookings SET item_id = ?, item_name = ?, status = ?, version = version + 1, updated_at = ?
+		 WHERE id = ? AND version = ?`,
+		itemID, itemName, status, time.Now(), id, fromVersion,
+	)
 	if err != nil {
 		return fmt.Errorf("failed to update booking item and status: %w", err)
 	}
-	rows, _ := result.RowsAffected()
-	if rows == 0 {
+	if rows, _ := result.RowsAffected(); rows == 0 {
 		return ErrConcurrentModification
 	}
-	return nil
+
+	after := *before
+	after.item = itemName
+	after.status = status
+	if err := recordBookingChanges(ctx, tx, id, before, &after); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (db *DB) GetUserBookings(ctx context.Context, userID int64) ([]*models.Booking, error) {
 	// Get bookings for the last 2 weeks and future ones
 	twoWeeksAgo := time.Now().AddDate(0, 0, -14).Format("2006-01-02")
 	query := `SELECT id, user_id, user_name, user_nickname, phone, item_id, 
-	                 item_name, date(date), status, comment, created_at, 
+	                 item_name, CAST(date(date) AS TEXT), CAST(date(end_time) AS TEXT), status, COALESCE(comment, ''), created_at, 
 					 updated_at, version 
-              FROM bookings WHERE user_id = ? AND date >= ? ORDER BY date DESC`
+              FROM bookings WHERE user_id = ? AND date(COALESCE(end_time, date)) >= ? ORDER BY date DESC`
 	rows, err := db.QueryContext(ctx, query, userID, twoWeeksAgo)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user bookings: %w", err)
@@ -313,9 +560,10 @@ func (db *DB) GetUserBookings(ctx context.Context, userID int64) ([]*models.Book
 	for rows.Next() {
 		b := &models.Booking{}
 		var dateStr string
+		var endStr sql.NullString
 		err := rows.Scan(
 			&b.ID, &b.UserID, &b.UserName, &b.UserNickname, &b.Phone,
-			&b.ItemID, &b.ItemName, &dateStr, &b.Status, &b.Comment,
+			&b.ItemID, &b.ItemName, &dateStr, &endStr, &b.Status, &b.Comment,
 			&b.CreatedAt, &b.UpdatedAt, &b.Version,
 		)
 		if err != nil {
@@ -325,23 +573,15 @@ func (db *DB) GetUserBookings(ctx context.Context, userID int64) ([]*models.Book
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse booking date %s: %w", dateStr, err)
 		}
+		if b.EndTime, err = parseEndTime(endStr); err != nil {
+			return nil, err
+		}
 		bookings = append(bookings, b)
 	}
-	return bookings, nil
-}
-
-func (db *DB) GetBookingWithAvailability(ctx context.Context, bookingID, newItemID int64) (*models.Booking, bool, error) {
-	booking, err := db.GetBooking(ctx, bookingID)
-	if err != nil {
-		return nil, false, err
-	}
-
-	available, err := db.CheckAvailability(ctx, newItemID, booking.Date)
-	if err != nil {
-		return nil, false, err
+	if err := openBookings(db.fields, bookings); err != nil {
+		return nil, err
 	}
-
-	return booking, available, nil
+	return bookings, nil
 }
 
 func (db *DB) GetDailyBookings(ctx context.Context, startDate, endDate time.Time) (map[string][]*models.Booking, error) {
@@ -350,10 +590,18 @@ func (db *DB) GetDailyBookings(ctx context.Context, startDate, endDate time.Time
 		return nil, err
 	}
 
+	// Бронирование-диапазон попадает в каждый покрытый день внутри периода
+	from := startDate.Format("2006-01-02")
+	to := endDate.Format("2006-01-02")
 	daily := make(map[string][]*models.Booking)
 	for _, b := range bookings {
-		dateKey := b.Date.Format("2006-01-02")
-		daily[dateKey] = append(daily[dateKey], b)
+		for _, d := range b.CoveredDates() {
+			dateKey := d.Format("2006-01-02")
+			if dateKey < from || dateKey > to {
+				continue
+			}
+			daily[dateKey] = append(daily[dateKey], b)
+		}
 	}
 	return daily, nil
 }
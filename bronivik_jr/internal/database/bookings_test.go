@@ -17,6 +17,8 @@ func setupTestDB(t *testing.T) *DB {
 	logger := zerolog.New(os.Stdout)
 	db, err := NewDB(":memory:", &logger)
 	require.NoError(t, err)
+	// Часть схемы создают версионные миграции
+	require.NoError(t, db.Migrate(context.Background(), ""))
 	return db
 }
 
@@ -194,18 +196,22 @@ func TestUpdateBookingItem(t *testing.T) {
 	defer db.Close()
 
 	ctx := context.Background()
+	item1 := &models.Item{Name: "Item 1", TotalQuantity: 1, IsActive: true}
+	item2 := &models.Item{Name: "Item 2", TotalQuantity: 1, IsActive: true}
+	require.NoError(t, db.CreateItem(ctx, item1))
+	require.NoError(t, db.CreateItem(ctx, item2))
 
 	booking := &models.Booking{
-		ItemID: 1, ItemName: "Item 1", Date: time.Now(), UserID: 1, UserName: "User 1", Phone: "123", Status: models.StatusPending,
+		ItemID: item1.ID, ItemName: item1.Name, Date: time.Now(), UserID: 1, UserName: "User 1", Phone: "123", Status: models.StatusPending,
 	}
 	err := db.CreateBooking(ctx, booking)
 	require.NoError(t, err)
 
-	err = db.UpdateBookingItemAndStatusWithVersion(ctx, booking.ID, booking.Version, 2, "Item 2", models.StatusChanged)
+	err = db.UpdateBookingItemAndStatusWithVersion(ctx, booking.ID, booking.Version, item2.ID, item2.Name, models.StatusChanged)
 	require.NoError(t, err)
 
 	updated, _ := db.GetBooking(ctx, booking.ID)
-	assert.Equal(t, int64(2), updated.ItemID)
+	assert.Equal(t, item2.ID, updated.ItemID)
 	assert.Equal(t, "Item 2", updated.ItemName)
 	assert.Equal(t, models.StatusChanged, updated.Status)
 }
@@ -261,43 +267,45 @@ func TestBookingUpdateExtras(t *testing.T) {
 	})
 }
 
-func TestGetBookingWithAvailability(t *testing.T) {
+func TestUpdateBookingItemChecksAvailability(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
 
 	ctx := context.Background()
-
 	item1 := &models.Item{Name: "Item 1", TotalQuantity: 1, IsActive: true}
-	err := db.CreateItem(ctx, item1)
-	require.NoError(t, err)
+	item2 := &models.Item{Name: "Item 2", TotalQuantity: 1, IsActive: true}
+	require.NoError(t, db.CreateItem(ctx, item1))
+	require.NoError(t, db.CreateItem(ctx, item2))
 
-	date := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	start := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 2)
 	booking := &models.Booking{
-		ItemID:   item1.ID,
-		ItemName: item1.Name,
-		Date:     date,
-		UserID:   1,
-		UserName: "U1",
-		Phone:    "1",
-		Status:   models.StatusConfirmed,
+		ItemID: item1.ID, ItemName: item1.Name, Date: start, EndTime: &end,
+		UserID: 1, UserName: "U1", Phone: "1", Status: models.StatusConfirmed,
 	}
-	err = db.CreateBooking(ctx, booking)
-	require.NoError(t, err)
+	require.NoError(t, db.CreateBookingWithLock(ctx, booking))
 
-	// Check another item at same date
-	item2 := &models.Item{Name: "Item 2", TotalQuantity: 1, IsActive: true}
-	err = db.CreateItem(ctx, item2)
-	require.NoError(t, err)
+	// Второй аппарат занят на последний день заявки
+	busyDay := end
+	other := &models.Booking{
+		ItemID: item2.ID, ItemName: item2.Name, Date: busyDay,
+		UserID: 2, UserName: "U2", Phone: "2", Status: models.StatusConfirmed,
+	}
+	require.NoError(t, db.CreateBookingWithLock(ctx, other))
 
-	b, available, err := db.GetBookingWithAvailability(ctx, booking.ID, item2.ID)
-	require.NoError(t, err)
-	assert.Equal(t, booking.ID, b.ID)
-	assert.True(t, available)
+	err := db.UpdateBookingItemAndStatusWithVersion(ctx, booking.ID, booking.Version, item2.ID, item2.Name, models.StatusChanged)
+	var unavailable *UnavailableDatesError
+	require.ErrorAs(t, err, &unavailable)
+	assert.ErrorIs(t, err, ErrNotAvailable)
+	require.Len(t, unavailable.Dates, 1)
+	assert.Equal(t, busyDay.Format("2006-01-02"), unavailable.Dates[0].Format("2006-01-02"))
 
-	// Check same item (should be unavailable since it's fully booked by itself)
-	_, available, err = db.GetBookingWithAvailability(ctx, booking.ID, item1.ID)
+	stored, err := db.GetBooking(ctx, booking.ID)
 	require.NoError(t, err)
-	assert.False(t, available)
+	assert.Equal(t, item1.ID, stored.ItemID)
+
+	// Собственная заявка не мешает остаться на том же аппарате
+	require.NoError(t, db.UpdateBookingItemAndStatusWithVersion(ctx, booking.ID, booking.Version, item1.ID, item1.Name, models.StatusChanged))
 }
 
 func TestCreateBookingWithLock_Failure(t *testing.T) {
@@ -324,3 +332,77 @@ func TestCreateBookingWithLock_Failure(t *testing.T) {
 	err = db.CreateBookingWithLock(ctx, b2)
 	assert.ErrorIs(t, err, ErrNotAvailable)
 }
+
+func TestRangeBookingAvailability(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	item := &models.Item{Name: "Range Item", TotalQuantity: 1, IsActive: true}
+	require.NoError(t, db.CreateItem(ctx, item))
+
+	start := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 3)
+	rangeBooking := &models.Booking{
+		ItemID: item.ID, ItemName: item.Name, Date: start, EndTime: &end,
+		UserID: 1, UserName: "U1", Phone: "1", Status: models.StatusConfirmed,
+	}
+	require.NoError(t, db.CreateBookingWithLock(ctx, rangeBooking))
+
+	stored, err := db.GetBooking(ctx, rangeBooking.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.EndTime)
+	assert.Equal(t, "2025-12-04", stored.EndTime.Format("2006-01-02"))
+
+	// Однодневное бронирование внутри диапазона
+	available, err := db.CheckAvailability(ctx, item.ID, start.AddDate(0, 0, 2))
+	require.NoError(t, err)
+	assert.False(t, available)
+
+	// Пересекающийся диапазон называет только занятые даты
+	otherStart := start.AddDate(0, 0, 3)
+	otherEnd := start.AddDate(0, 0, 5)
+	err = db.CreateBookingWithLock(ctx, &models.Booking{
+		ItemID: item.ID, ItemName: item.Name, Date: otherStart, EndTime: &otherEnd,
+		UserID: 2, UserName: "U2", Phone: "2", Status: models.StatusConfirmed,
+	})
+	require.ErrorIs(t, err, ErrNotAvailable)
+	var datesErr *UnavailableDatesError
+	require.ErrorAs(t, err, &datesErr)
+	require.Len(t, datesErr.Dates, 1)
+	assert.Equal(t, otherStart, datesErr.Dates[0])
+	assert.Contains(t, err.Error(), "2025-12-04")
+
+	availability, err := db.GetAvailabilityForPeriod(ctx, item.ID, start.AddDate(0, 0, -1), 6)
+	require.NoError(t, err)
+	booked := make([]int64, 0, len(availability))
+	for _, a := range availability {
+		booked = append(booked, a.Booked)
+	}
+	assert.Equal(t, []int64{0, 1, 1, 1, 1, 0}, booked)
+
+	// Период, начинающийся в середине диапазона
+	daily, err := db.GetDailyBookings(ctx, start.AddDate(0, 0, 2), start.AddDate(0, 0, 6))
+	require.NoError(t, err)
+	assert.Len(t, daily["2025-12-03"], 1)
+	assert.Len(t, daily["2025-12-04"], 1)
+	assert.Empty(t, daily["2025-12-05"])
+	assert.NotContains(t, daily, "2025-12-01")
+}
+
+func TestCreateBookingWithLock_InvalidRange(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	item := &models.Item{Name: "Item 1", TotalQuantity: 1, IsActive: true}
+	require.NoError(t, db.CreateItem(ctx, item))
+
+	start := time.Date(2025, 12, 5, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, -1)
+	err := db.CreateBookingWithLock(ctx, &models.Booking{
+		ItemID: item.ID, ItemName: item.Name, Date: start, EndTime: &end,
+		UserID: 1, UserName: "U1", Phone: "1", Status: models.StatusConfirmed,
+	})
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+}
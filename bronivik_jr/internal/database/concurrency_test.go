@@ -21,6 +21,7 @@ func TestConcurrentBooking(t *testing.T) {
 	defer db.Close()
 
 	ctx := context.Background()
+	assert.NoError(t, db.Migrate(ctx, ""))
 
 	// Create an item with quantity 1
 	item := &models.Item{
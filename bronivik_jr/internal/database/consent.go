@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"bronivik/internal/models"
+)
+
+// GiveConsent отмечает согласие пользователя с политикой версии version
+// и записывает событие в consent_log.
+func (db *DB) GiveConsent(ctx context.Context, telegramID int64, version string) error {
+	now := time.Now()
+	return db.updateConsent(ctx, telegramID, models.ConsentActionGiven, version, `
+		UPDATE users SET consent_given = ?, consent_given_at = ?, consent_version = ?,
+		                 consent_revoked = ?, consent_revoked_at = NULL, updated_at = ?
+		WHERE telegram_id = ?`,
+		true, now, version, false, now, telegramID)
+}
+
+// RevokeConsent отзывает согласие и удаляет телефон из профиля пользователя.
+// Телефоны в уже созданных заявках не трогаются.
+func (db *DB) RevokeConsent(ctx context.Context, telegramID int64) error {
+	now := time.Now()
+	return db.updateConsent(ctx, telegramID, models.ConsentActionRevoked, "", `
+		UPDATE users SET consent_revoked = ?, consent_revoked_at = ?, phone = '', updated_at = ?
+		WHERE telegram_id = ?`,
+		true, now, now, telegramID)
+}
+
+func (db *DB) updateConsent(ctx context.Context, telegramID int64, action, version, query string, args ...interface{}) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update consent: %w", err)
+	}
+	if n, errRows := res.RowsAffected(); errRows == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO consent_log (user_id, action, policy_version, created_at)
+		VALUES (?, ?, ?, ?)`, telegramID, action, version, time.Now()); err != nil {
+		return fmt.Errorf("failed to log consent: %w", err)
+	}
+	return tx.Commit()
+}
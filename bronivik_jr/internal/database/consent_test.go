@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"bronivik/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsent(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	require.NoError(t, db.CreateOrUpdateUser(ctx, &models.User{
+		TelegramID: 700, FirstName: "Anna", Phone: "79991234567", LastActivity: time.Now(),
+	}))
+
+	u, err := db.GetUserByTelegramID(ctx, 700)
+	require.NoError(t, err)
+	assert.False(t, u.HasConsent(models.PrivacyPolicyVersion))
+
+	require.NoError(t, db.GiveConsent(ctx, 700, models.PrivacyPolicyVersion))
+	u, err = db.GetUserByTelegramID(ctx, 700)
+	require.NoError(t, err)
+	assert.True(t, u.HasConsent(models.PrivacyPolicyVersion))
+	assert.False(t, u.HasConsent("next"), "новая версия политики требует нового согласия")
+	assert.True(t, u.ConsentGivenAt.Valid)
+
+	require.NoError(t, db.RevokeConsent(ctx, 700))
+	u, err = db.GetUserByTelegramID(ctx, 700)
+	require.NoError(t, err)
+	assert.True(t, u.ConsentRevoked)
+	assert.True(t, u.ConsentRevokedAt.Valid)
+	assert.False(t, u.HasConsent(models.PrivacyPolicyVersion))
+	assert.Empty(t, u.Phone)
+
+	// Повторное согласие снимает отзыв
+	require.NoError(t, db.GiveConsent(ctx, 700, models.PrivacyPolicyVersion))
+	u, err = db.GetUserByTelegramID(ctx, 700)
+	require.NoError(t, err)
+	assert.True(t, u.HasConsent(models.PrivacyPolicyVersion))
+	assert.False(t, u.ConsentRevokedAt.Valid)
+
+	assert.ErrorIs(t, db.GiveConsent(ctx, 701, models.PrivacyPolicyVersion), sql.ErrNoRows)
+
+	// События согласия попадают в аудит-выгрузку
+	rows, _, err := db.GetTableData(ctx, "consent_log")
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	var actions []string
+	for _, row := range rows {
+		actions = append(actions, row["action"].(string))
+	}
+	assert.Equal(t, []string{models.ConsentActionGiven, models.ConsentActionRevoked, models.ConsentActionGiven}, actions)
+}
@@ -11,6 +11,8 @@ import (
 	"sync"
 	"time"
 
+	"bronivik/internal/config"
+	"bronivik/internal/fieldcrypt"
 	"bronivik/internal/models"
 
 	_ "github.com/mattn/go-sqlite3" // sqlite3 driver
@@ -20,10 +22,12 @@ import (
 // DB represents the database connection and its cache.
 type DB struct {
 	*sql.DB
+	dialect    dialect
 	itemsCache map[int64]models.Item
 	cacheTime  time.Time
 	mu         sync.RWMutex
 	logger     *zerolog.Logger
+	fields     *fieldcrypt.Cipher // шифрование телефонов и имен клиентов; nil — выключено
 }
 
 var (
@@ -31,8 +35,42 @@ var (
 	ErrNotAvailable           = errors.New("not available")
 	ErrPastDate               = errors.New("cannot book in the past")
 	ErrDateTooFar             = errors.New("date is too far in the future")
+	ErrAlreadyInWaitlist      = errors.New("already in waitlist")
+	ErrWaitlistOfferExpired   = errors.New("waitlist offer expired")
+	ErrSeriesCanceled         = errors.New("booking series is canceled")
+	ErrInvalidDateRange       = errors.New("end date is before start date")
+	ErrDuplicateExternalID    = errors.New("external booking id already exists")
 )
 
+// UnavailableDatesError сообщает, какие именно дни бронирования заняты.
+type UnavailableDatesError struct {
+	Dates []time.Time
+}
+
+func (e *UnavailableDatesError) Error() string {
+	dates := make([]string, 0, len(e.Dates))
+	for _, d := range e.Dates {
+		dates = append(dates, d.Format("2006-01-02"))
+	}
+	return fmt.Sprintf("%s: %s", ErrNotAvailable, strings.Join(dates, ", "))
+}
+
+func (e *UnavailableDatesError) Unwrap() error {
+	return ErrNotAvailable
+}
+
+// Open подключается к базе, выбранной в конфигурации: SQLite по умолчанию или PostgreSQL.
+func Open(cfg *config.DatabaseConfig, logger *zerolog.Logger) (*DB, error) {
+	switch cfg.Driver {
+	case "", config.DatabaseDriverSQLite:
+		return NewDB(cfg.Path, logger)
+	case config.DatabaseDriverPostgres:
+		return NewPostgresDB(&cfg.Postgres, logger)
+	default:
+		return nil, fmt.Errorf("unknown database driver: %s", cfg.Driver)
+	}
+}
+
 // NewDB initializes a new database connection and creates tables if they don't exist.
 func NewDB(path string, logger *zerolog.Logger) (*DB, error) {
 	// Создаем директорию для БД, если её нет
@@ -53,19 +91,32 @@ func NewDB(path string, logger *zerolog.Logger) (*DB, error) {
 	db.SetMaxIdleConns(5)
 	db.SetConnMaxLifetime(time.Hour)
 
+	instance, err := initDB(db, dialectSQLite, logger)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info().Str("path", path).Msg("Database initialized")
+	return instance, nil
+}
+
+// initDB проверяет соединение, создает таблицы и загружает кеш аппаратов.
+func initDB(db *sql.DB, d dialect, logger *zerolog.Logger) (*DB, error) {
 	// Проверяем соединение
 	if err := db.Ping(); err != nil {
+		_ = db.Close()
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
 	instance := &DB{
 		DB:         db,
+		dialect:    d,
 		itemsCache: make(map[int64]models.Item),
 		logger:     logger,
 	}
 
 	// Создаем таблицы
 	if err := instance.createTables(); err != nil {
+		_ = db.Close()
 		return nil, fmt.Errorf("failed to create tables: %v", err)
 	}
 
@@ -75,11 +126,20 @@ func NewDB(path string, logger *zerolog.Logger) (*DB, error) {
 		// We don't return error here to allow the app to start even if items are missing
 	}
 
-	logger.Info().Str("path", path).Msg("Database initialized")
+	instance.reportUnknownStatuses(context.Background())
 	return instance, nil
 }
 
+// Driver возвращает бэкенд базы: sqlite или postgres.
+func (db *DB) Driver() string {
+	return db.dialect.String()
+}
+
 func (db *DB) createTables() error {
+	if db.dialect == dialectPostgres {
+		return db.createPostgresTables()
+	}
+
 	queries := []string{
 		// Таблица предметов (аппаратов)
 		`CREATE TABLE IF NOT EXISTS items (
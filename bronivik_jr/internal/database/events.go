@@ -0,0 +1,180 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"bronivik/internal/events"
+	"bronivik/internal/models"
+)
+
+// bookingEventType определяет событие по изменившимся полям заявки.
+// Перенос и замена аппарата сами меняют статус на changed, поэтому дают одно событие.
+// Возврат в pending и ручная установка changed событий не порождают.
+func bookingEventType(before, after *bookingSnapshot) string {
+	switch {
+	case before.dates != after.dates:
+		return events.EventBookingRescheduled
+	case before.item != after.item:
+		return events.EventBookingItemChange
+	case before.status == after.status:
+		return ""
+	}
+
+	switch after.status {
+	case models.StatusConfirmed:
+		return events.EventBookingConfirmed
+	case models.StatusCanceled:
+		return events.EventBookingCanceled
+	case models.StatusCompleted:
+		return events.EventBookingCompleted
+	}
+	return ""
+}
+
+// insertBookingEvent пишет событие в outbox. Полезная нагрузка строится по текущему
+// состоянию заявки внутри той же транзакции; previous задает прежние даты для переноса
+// и прежний аппарат для замены.
+func insertBookingEvent(ctx context.Context, q querier, eventType string, bookingID int64, previous *bookingSnapshot) error {
+	booking, err := getBooking(ctx, q, bookingID)
+	if err != nil {
+		return err
+	}
+
+	actor := models.ActorFromContext(ctx)
+	payload := events.BookingEventPayload{
+		BookingID:   booking.ID,
+		UserID:      booking.UserID,
+		ItemID:      booking.ItemID,
+		ItemName:    booking.ItemName,
+		Status:      booking.Status,
+		Date:        booking.Date,
+		EndTime:     booking.EndTime,
+		Comment:     booking.Comment,
+		ChangedBy:   string(actor.Type),
+		ChangedByID: actor.ID,
+	}
+	if previous != nil {
+		switch eventType {
+		case events.EventBookingRescheduled:
+			if payload.PreviousDate, payload.PreviousEndTime, err = parseHistoryDates(previous.dates); err != nil {
+				return err
+			}
+		case events.EventBookingItemChange:
+			payload.PreviousItemName = previous.item
+		}
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event payload: %w", err)
+	}
+
+	now := time.Now()
+	_, err = q.ExecContext(ctx, `
+		INSERT INTO events (type, booking_id, payload, next_attempt_at, created_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		eventType, bookingID, string(data), now, now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save event: %w", err)
+	}
+	return nil
+}
+
+// parseHistoryDates разбирает значение поля dates из снимка заявки ("2006-01-02" или "a..b").
+func parseHistoryDates(value string) (*time.Time, *time.Time, error) {
+	startStr, endStr, isRange := strings.Cut(value, "..")
+	start, err := time.Parse("2006-01-02", startStr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse booking dates %q: %w", value, err)
+	}
+	if !isRange {
+		return &start, nil, nil
+	}
+	end, err := time.Parse("2006-01-02", endStr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse booking dates %q: %w", value, err)
+	}
+	return &start, &end, nil
+}
+
+const eventColumns = `id, type, payload, attempts, processed_at IS NOT NULL, created_at`
+
+func (db *DB) queryEvents(ctx context.Context, query string, args ...interface{}) ([]*events.Event, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*events.Event
+	for rows.Next() {
+		ev := &events.Event{}
+		var payload string
+		if err := rows.Scan(&ev.ID, &ev.Type, &payload, &ev.Attempts, &ev.Processed, &ev.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		ev.Payload = []byte(payload)
+		result = append(result, ev)
+	}
+	return result, rows.Err()
+}
+
+// GetPendingEvents возвращает необработанные события, срок доставки которых наступил.
+// События, исчерпавшие попытки, имеют пустой next_attempt_at и не выбираются.
+func (db *DB) GetPendingEvents(ctx context.Context, now time.Time, limit int) ([]*events.Event, error) {
+	return db.queryEvents(ctx, `SELECT `+eventColumns+` FROM events
+		WHERE processed_at IS NULL AND next_attempt_at IS NOT NULL AND next_attempt_at <= ?
+		ORDER BY id ASC LIMIT ?`, now, limit)
+}
+
+// GetEventsFrom возвращает события начиная с fromID независимо от их состояния (для replay).
+func (db *DB) GetEventsFrom(ctx context.Context, fromID int64, limit int) ([]*events.Event, error) {
+	return db.queryEvents(ctx, `SELECT `+eventColumns+` FROM events
+		WHERE id >= ? ORDER BY id ASC LIMIT ?`, fromID, limit)
+}
+
+// GetLastEventID возвращает ID последнего записанного события или 0, если событий нет.
+func (db *DB) GetLastEventID(ctx context.Context) (int64, error) {
+	var id int64
+	if err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(id), 0) FROM events`).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to get last event id: %w", err)
+	}
+	return id, nil
+}
+
+func (db *DB) MarkEventProcessed(ctx context.Context, id int64) error {
+	_, err := db.ExecContext(ctx, `UPDATE events SET processed_at = ?, last_error = '' WHERE id = ?`, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to mark event processed: %w", err)
+	}
+	return nil
+}
+
+// MarkEventFailed увеличивает счетчик попыток; нулевой nextAttemptAt снимает событие с автоматической доставки.
+func (db *DB) MarkEventFailed(ctx context.Context, id int64, lastErr string, nextAttemptAt time.Time) error {
+	var next interface{}
+	if !nextAttemptAt.IsZero() {
+		next = nextAttemptAt
+	}
+	_, err := db.ExecContext(ctx, `
+		UPDATE events SET attempts = attempts + 1, last_error = ?, next_attempt_at = ? WHERE id = ?`,
+		lastErr, next, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark event failed: %w", err)
+	}
+	return nil
+}
+
+// DeleteProcessedEvents удаляет доставленные события старше before.
+func (db *DB) DeleteProcessedEvents(ctx context.Context, before time.Time) (int64, error) {
+	result, err := db.ExecContext(ctx, `DELETE FROM events WHERE processed_at IS NOT NULL AND processed_at < ?`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete processed events: %w", err)
+	}
+	return result.RowsAffected()
+}
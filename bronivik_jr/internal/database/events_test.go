@@ -0,0 +1,109 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"bronivik/internal/events"
+	"bronivik/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookingEventsOutbox(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := models.WithActor(context.Background(), models.Actor{Type: models.ActorManager, ID: 100})
+	item := &models.Item{Name: "Item 1", TotalQuantity: 1, IsActive: true}
+	require.NoError(t, db.CreateItem(ctx, item))
+
+	start := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	booking := &models.Booking{
+		ItemID: item.ID, ItemName: item.Name, Date: start,
+		UserID: 1, UserName: "U1", Phone: "1", Status: models.StatusPending,
+	}
+	require.NoError(t, db.CreateBookingWithLock(ctx, booking))
+	require.NoError(t, db.UpdateBookingStatusWithVersion(ctx, booking.ID, 1, models.StatusConfirmed))
+	require.NoError(t, db.UpdateBookingStatusWithVersion(ctx, booking.ID, 2, models.StatusPending))
+	end := start.AddDate(0, 0, 2)
+	require.NoError(t, db.UpdateBookingDatesWithVersion(ctx, booking.ID, 3, start.AddDate(0, 0, 1), &end, 100))
+
+	// Неудачное изменение не оставляет события
+	assert.ErrorIs(t, db.UpdateBookingStatusWithVersion(ctx, booking.ID, 1, models.StatusCanceled), ErrConcurrentModification)
+
+	pending, err := db.GetPendingEvents(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	var types []string
+	for _, ev := range pending {
+		types = append(types, ev.Type)
+	}
+	// Возврат в pending события не порождает
+	assert.Equal(t, []string{events.EventBookingCreated, events.EventBookingConfirmed, events.EventBookingRescheduled}, types)
+
+	var payload events.BookingEventPayload
+	require.NoError(t, json.Unmarshal(pending[2].Payload, &payload))
+	assert.Equal(t, booking.ID, payload.BookingID)
+	assert.Equal(t, models.StatusChanged, payload.Status)
+	assert.Equal(t, "manager", payload.ChangedBy)
+	assert.Equal(t, int64(100), payload.ChangedByID)
+	require.NotNil(t, payload.PreviousDate)
+	assert.True(t, payload.PreviousDate.Equal(start))
+	assert.Nil(t, payload.PreviousEndTime)
+	require.NotNil(t, payload.EndTime)
+	assert.True(t, payload.EndTime.Equal(end))
+
+	// Ошибка доставки откладывает событие, успешная — исключает его из выборки
+	require.NoError(t, db.MarkEventProcessed(ctx, pending[0].ID))
+	require.NoError(t, db.MarkEventFailed(ctx, pending[1].ID, "sheets unavailable", time.Now().Add(time.Minute)))
+	require.NoError(t, db.MarkEventFailed(ctx, pending[2].ID, "sheets unavailable", time.Time{}))
+
+	pending, err = db.GetPendingEvents(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+	pending, err = db.GetPendingEvents(ctx, time.Now().Add(2*time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, 1, pending[0].Attempts)
+
+	// Replay видит все события, включая обработанные
+	all, err := db.GetEventsFrom(ctx, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.True(t, all[0].Processed)
+
+	deleted, err := db.DeleteProcessedEvents(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+}
+
+func TestExternalBookingEvents(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := models.WithActor(context.Background(), models.Actor{Type: models.ActorAPI, Name: "crm"})
+	item := &models.Item{Name: "Item 1", TotalQuantity: 1, IsActive: true}
+	require.NoError(t, db.CreateItem(ctx, item))
+
+	date := time.Now().AddDate(0, 0, 3)
+	id, err := db.CreateExternalBooking(ctx, item.ID, item.Name, date, "ext-1", "Client", "+7900")
+	require.NoError(t, err)
+	require.NoError(t, db.CancelExternalBooking(ctx, "ext-1"))
+
+	all, err := db.GetEventsFrom(ctx, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, events.EventBookingCanceled, all[1].Type)
+
+	var payload events.BookingEventPayload
+	require.NoError(t, json.Unmarshal(all[1].Payload, &payload))
+	assert.Equal(t, id, payload.BookingID)
+	assert.Equal(t, "api", payload.ChangedBy)
+
+	lastID, err := db.GetLastEventID(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, all[1].ID, lastID)
+}
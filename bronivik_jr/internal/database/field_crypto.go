@@ -0,0 +1,182 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"bronivik/internal/fieldcrypt"
+	"bronivik/internal/models"
+	"bronivik/internal/retention"
+)
+
+// SetFieldCipher включает шифрование телефонов и имен клиентов. nil — значения
+// пишутся как есть; уже зашифрованные строки без ключей прочитать нельзя.
+func (db *DB) SetFieldCipher(c *fieldcrypt.Cipher) {
+	db.fields = c
+}
+
+func (db *DB) fieldCipher() *fieldcrypt.Cipher {
+	return db.fields
+}
+
+func (tx *Tx) fieldCipher() *fieldcrypt.Cipher {
+	return tx.fields
+}
+
+// sealedPerson — имя и телефон клиента в том виде, в котором они хранятся в базе.
+type sealedPerson struct {
+	name       string
+	phone      string
+	phoneIndex string
+}
+
+func sealPerson(c *fieldcrypt.Cipher, name, phone string) (sealedPerson, error) {
+	var p sealedPerson
+	var err error
+	if p.name, err = c.Encrypt(name); err != nil {
+		return p, fmt.Errorf("failed to encrypt name: %w", err)
+	}
+	if p.phone, err = c.Encrypt(phone); err != nil {
+		return p, fmt.Errorf("failed to encrypt phone: %w", err)
+	}
+	p.phoneIndex = c.BlindIndex(phone)
+	return p, nil
+}
+
+// openPerson расшифровывает прочитанные из базы имя и телефон на месте.
+func openPerson(c *fieldcrypt.Cipher, name, phone *string) error {
+	var err error
+	if *name, err = c.Decrypt(*name); err != nil {
+		return fmt.Errorf("failed to decrypt name: %w", err)
+	}
+	if *phone, err = c.Decrypt(*phone); err != nil {
+		return fmt.Errorf("failed to decrypt phone: %w", err)
+	}
+	return nil
+}
+
+func openBookings(c *fieldcrypt.Cipher, bookings []*models.Booking) error {
+	for _, b := range bookings {
+		if err := openPerson(c, &b.UserName, &b.Phone); err != nil {
+			return fmt.Errorf("booking %d: %w", b.ID, err)
+		}
+	}
+	return nil
+}
+
+// fieldRotation описывает таблицу с зашифрованными полями для перешифрования.
+type fieldRotation struct {
+	table    string
+	columns  []string
+	indexCol string // колонка blind index телефона; пусто — индекса нет
+}
+
+// Ответы в idempotency_keys тоже шифруются, но не перешифровываются: они удаляются
+// через api.idempotency.ttl_hours, а старый ключ шифрования держат хотя бы столько же.
+var fieldRotations = []fieldRotation{
+	{table: "users", columns: []string{"phone"}},
+	{table: "bookings", columns: []string{"user_name", "phone"}, indexCol: "phone_index"},
+	{table: "booking_series", columns: []string{"user_name", "phone"}},
+	{table: "waitlist", columns: []string{"user_name", "phone"}},
+	{table: "api_keys", columns: []string{"signing_secret"}},
+}
+
+// RotateFieldEncryption перешифровывает основным ключом до limit строк каждой
+// таблицы: открытые значения (записанные до включения шифрования) и значения
+// под старыми ключами. Вместе с телефоном заполняется blind index. Возвращает
+// число обновленных строк; 0 означает, что перешифровывать больше нечего.
+func (db *DB) RotateFieldEncryption(ctx context.Context, limit int) (int, error) {
+	if db.fields == nil {
+		return 0, nil
+	}
+	total := 0
+	for _, r := range fieldRotations {
+		n, err := db.rotateTable(ctx, r, limit)
+		total += n
+		if err != nil {
+			return total, fmt.Errorf("rotate %s: %w", r.table, err)
+		}
+	}
+	return total, nil
+}
+
+func (db *DB) rotateTable(ctx context.Context, r fieldRotation, limit int) (int, error) {
+	pattern := db.fields.PrimaryPrefix() + "%"
+	selectCols := "id"
+	where := ""
+	args := make([]interface{}, 0, 2*len(r.columns)+1)
+	for _, col := range r.columns {
+		selectCols += ", COALESCE(" + col + ", '')"
+		if where != "" {
+			where += " OR "
+		}
+		// Хеши политики хранения не персональные данные, их не шифруем
+		where += "(COALESCE(" + col + ", '') <> '' AND " + col + " NOT LIKE ? AND " + col + " NOT LIKE ?)"
+		args = append(args, pattern, retention.HashPrefix+"%")
+	}
+	args = append(args, limit)
+
+	rows, err := db.QueryContext(ctx, `SELECT `+selectCols+` FROM `+r.table+` WHERE `+where+` ORDER BY id LIMIT ?`, args...)
+	if err != nil {
+		return 0, err
+	}
+	type row struct {
+		id     int64
+		values []string
+	}
+	var pending []row
+	for rows.Next() {
+		rw := row{values: make([]string, len(r.columns))}
+		dest := []interface{}{&rw.id}
+		for i := range rw.values {
+			dest = append(dest, &rw.values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		pending = append(pending, rw)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, rw := range pending {
+		set := ""
+		var setArgs, whereArgs []interface{}
+		where := "id = ?"
+		whereArgs = append(whereArgs, rw.id)
+		for i, col := range r.columns {
+			plain, err := db.fields.Decrypt(rw.values[i])
+			if err != nil {
+				return updated, fmt.Errorf("row %d: %w", rw.id, err)
+			}
+			sealed, err := db.fields.Encrypt(plain)
+			if err != nil {
+				return updated, err
+			}
+			if set != "" {
+				set += ", "
+			}
+			set += col + " = ?"
+			setArgs = append(setArgs, sealed)
+			// Строку, измененную параллельно, пропускаем: ее перешифрует следующий проход
+			where += " AND COALESCE(" + col + ", '') = ?"
+			whereArgs = append(whereArgs, rw.values[i])
+			if col == "phone" && r.indexCol != "" {
+				set += ", " + r.indexCol + " = ?"
+				setArgs = append(setArgs, db.fields.BlindIndex(plain))
+			}
+		}
+		res, err := db.ExecContext(ctx, `UPDATE `+r.table+` SET `+set+` WHERE `+where, append(setArgs, whereArgs...)...)
+		if err != nil {
+			return updated, err
+		}
+		if n, errRows := res.RowsAffected(); errRows == nil {
+			updated += int(n)
+		}
+	}
+	return updated, nil
+}
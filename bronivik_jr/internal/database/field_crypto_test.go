@@ -0,0 +1,139 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"bronivik/internal/fieldcrypt"
+	"bronivik/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFieldCipher(t *testing.T, primary string) *fieldcrypt.Cipher {
+	t.Helper()
+	keys := []fieldcrypt.Key{
+		{ID: "k1", Secret: bytes.Repeat([]byte{1}, 32)},
+		{ID: "k2", Secret: bytes.Repeat([]byte{2}, 32)},
+	}
+	c, err := fieldcrypt.New(keys, primary, bytes.Repeat([]byte{9}, 32))
+	require.NoError(t, err)
+	return c
+}
+
+func rawBookingPerson(t *testing.T, db *DB, id int64) (name, phone, index string) {
+	t.Helper()
+	err := db.QueryRow(`SELECT user_name, phone, phone_index FROM bookings WHERE id = ?`, id).Scan(&name, &phone, &index)
+	require.NoError(t, err)
+	return name, phone, index
+}
+
+func TestFieldEncryption(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	db.SetFieldCipher(testFieldCipher(t, "k1"))
+
+	item := &models.Item{Name: "Item 1", TotalQuantity: 2, IsActive: true}
+	require.NoError(t, db.CreateItem(ctx, item))
+	require.NoError(t, db.CreateOrUpdateUser(ctx, &models.User{
+		TelegramID: 900, FirstName: "Anna", Phone: "+7 999 123-45-67", LastActivity: time.Now(),
+	}))
+
+	booking := &models.Booking{
+		ItemID: item.ID, ItemName: item.Name, Date: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+		UserID: 900, UserName: "Анна Петрова", Phone: "+7 999 123-45-67", Status: models.StatusPending,
+	}
+	require.NoError(t, db.CreateBooking(ctx, booking))
+
+	// В базе лежит шифротекст, наружу отдается открытый текст
+	name, phone, index := rawBookingPerson(t, db, booking.ID)
+	assert.True(t, strings.HasPrefix(name, "enc:v1:k1:"))
+	assert.True(t, strings.HasPrefix(phone, "enc:v1:k1:"))
+	assert.NotEmpty(t, index)
+
+	got, err := db.GetBooking(ctx, booking.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Анна Петрова", got.UserName)
+	assert.Equal(t, "+7 999 123-45-67", got.Phone)
+
+	user, err := db.GetUserByTelegramID(ctx, 900)
+	require.NoError(t, err)
+	assert.Equal(t, "+7 999 123-45-67", user.Phone)
+
+	// Поиск по телефону не зависит от формата номера
+	found, err := db.ListBookings(ctx, &models.BookingFilter{Phone: "89991234567"})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, booking.ID, found[0].ID)
+	assert.Equal(t, "Анна Петрова", found[0].UserName)
+
+	found, err = db.ListBookings(ctx, &models.BookingFilter{Phone: "89990000000"})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	// Без ключей зашифрованные значения не читаются
+	db.SetFieldCipher(nil)
+	_, err = db.GetBooking(ctx, booking.ID)
+	assert.Error(t, err)
+}
+
+func TestRotateFieldEncryption(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	item := &models.Item{Name: "Item 1", TotalQuantity: 5, IsActive: true}
+	require.NoError(t, db.CreateItem(ctx, item))
+
+	// Заявка записана до включения шифрования
+	legacy := &models.Booking{
+		ItemID: item.ID, ItemName: item.Name, Date: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+		UserID: 1, UserName: "Иван", Phone: "79990000001", Status: models.StatusPending,
+	}
+	require.NoError(t, db.CreateBooking(ctx, legacy))
+
+	// Заявка под ключом, который выводится из ротации
+	db.SetFieldCipher(testFieldCipher(t, "k1"))
+	old := &models.Booking{
+		ItemID: item.ID, ItemName: item.Name, Date: time.Date(2025, 12, 2, 0, 0, 0, 0, time.UTC),
+		UserID: 2, UserName: "Петр", Phone: "79990000002", Status: models.StatusPending,
+	}
+	require.NoError(t, db.CreateBooking(ctx, old))
+	before, err := db.GetBooking(ctx, old.ID)
+	require.NoError(t, err)
+
+	db.SetFieldCipher(testFieldCipher(t, "k2"))
+	rotated, err := db.RotateFieldEncryption(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rotated, "limit ограничивает пачку в каждой таблице")
+
+	for {
+		n, err := db.RotateFieldEncryption(ctx, 10)
+		require.NoError(t, err)
+		if n == 0 {
+			break
+		}
+	}
+
+	for _, id := range []int64{legacy.ID, old.ID} {
+		name, phone, index := rawBookingPerson(t, db, id)
+		assert.True(t, strings.HasPrefix(name, "enc:v1:k2:"), name)
+		assert.True(t, strings.HasPrefix(phone, "enc:v1:k2:"), phone)
+		assert.NotEmpty(t, index)
+	}
+
+	after, err := db.GetBooking(ctx, old.ID)
+	require.NoError(t, err)
+	assert.Equal(t, before.UserName, after.UserName)
+	assert.Equal(t, before.Version, after.Version, "перешифрование не меняет версию заявки")
+
+	found, err := db.ListBookings(ctx, &models.BookingFilter{Phone: "+7 999 000-00-01"})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, legacy.ID, found[0].ID)
+}
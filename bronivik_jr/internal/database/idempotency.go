@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"bronivik/internal/models"
+)
+
+// ReserveIdempotencyKey занимает ключ под выполняемый запрос. Если ключ уже занят
+// и не истек, запись не меняется и возвращается существующая запись.
+// Истекшие ключи удаляются в той же транзакции.
+func (db *DB) ReserveIdempotencyKey(ctx context.Context, rec *models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	now := time.Now()
+	if _, err := tx.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= ?`, now); err != nil {
+		return nil, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO idempotency_keys (scope, key, fingerprint, status_code, content_type, created_at, expires_at)
+		VALUES (?, ?, ?, 0, '', ?, ?)
+		ON CONFLICT(scope, key) DO NOTHING`,
+		rec.Scope, rec.Key, rec.Fingerprint, now, rec.ExpiresAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	var existing *models.IdempotencyRecord
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		existing = &models.IdempotencyRecord{Scope: rec.Scope, Key: rec.Key}
+		err = tx.QueryRowContext(ctx, `
+			SELECT fingerprint, status_code, content_type, body, created_at, expires_at
+			FROM idempotency_keys WHERE scope = ? AND key = ?`, rec.Scope, rec.Key,
+		).Scan(&existing.Fingerprint, &existing.StatusCode, &existing.ContentType, &existing.Body,
+			&existing.CreatedAt, &existing.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+		}
+		if len(existing.Body) > 0 {
+			body, err := db.fields.Decrypt(string(existing.Body))
+			if err != nil {
+				return nil, fmt.Errorf("failed to decrypt idempotency response: %w", err)
+			}
+			existing.Body = []byte(body)
+		}
+	} else {
+		rec.CreatedAt = now
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return existing, nil
+}
+
+// CompleteIdempotencyKey сохраняет ответ на запрос, занявший ключ. Ответ содержит
+// заявку с именем и телефоном клиента, поэтому шифруется вместе с ними.
+func (db *DB) CompleteIdempotencyKey(ctx context.Context, scope, key string, statusCode int, contentType string, body []byte) error {
+	sealed, err := db.fields.Encrypt(string(body))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt idempotency response: %w", err)
+	}
+	_, err = db.ExecContext(ctx, `
+		UPDATE idempotency_keys SET status_code = ?, content_type = ?, body = ?
+		WHERE scope = ? AND key = ?`,
+		statusCode, contentType, []byte(sealed), scope, key,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey освобождает ключ, если запрос не удалось выполнить: клиент может повторить его.
+func (db *DB) ReleaseIdempotencyKey(ctx context.Context, scope, key string) error {
+	if _, err := db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE scope = ? AND key = ?`, scope, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
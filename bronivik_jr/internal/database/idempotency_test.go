@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bronivik/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyKeys(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	rec := &models.IdempotencyRecord{Scope: "crm", Key: "k1", Fingerprint: "f1", ExpiresAt: time.Now().Add(time.Hour)}
+	existing, err := db.ReserveIdempotencyKey(ctx, rec)
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+
+	// Пока запрос выполняется, ключ занят
+	existing, err = db.ReserveIdempotencyKey(ctx, &models.IdempotencyRecord{Scope: "crm", Key: "k1", Fingerprint: "f2",
+		ExpiresAt: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.True(t, existing.InProgress())
+	assert.Equal(t, "f1", existing.Fingerprint)
+
+	// Ключи разных клиентов не пересекаются
+	existing, err = db.ReserveIdempotencyKey(ctx, &models.IdempotencyRecord{Scope: "other", Key: "k1", Fingerprint: "f1",
+		ExpiresAt: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+
+	require.NoError(t, db.CompleteIdempotencyKey(ctx, "crm", "k1", 201, "application/json", []byte(`{"id":1}`)))
+	existing, err = db.ReserveIdempotencyKey(ctx, rec)
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, 201, existing.StatusCode)
+	assert.Equal(t, "application/json", existing.ContentType)
+	assert.Equal(t, `{"id":1}`, string(existing.Body))
+
+	// Освобожденный ключ можно занять снова
+	require.NoError(t, db.ReleaseIdempotencyKey(ctx, "crm", "k1"))
+	existing, err = db.ReserveIdempotencyKey(ctx, rec)
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+
+	// Истекший ключ удаляется при следующем резервировании
+	expired := &models.IdempotencyRecord{Scope: "crm", Key: "old", Fingerprint: "f1", ExpiresAt: time.Now().Add(-time.Minute)}
+	_, err = db.ReserveIdempotencyKey(ctx, expired)
+	require.NoError(t, err)
+	existing, err = db.ReserveIdempotencyKey(ctx, &models.IdempotencyRecord{Scope: "crm", Key: "old", Fingerprint: "f3",
+		ExpiresAt: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+}
+
+func TestIdempotencyResponseEncrypted(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	db.SetFieldCipher(testFieldCipher(t, "k1"))
+
+	rec := &models.IdempotencyRecord{Scope: "crm", Key: "k1", Fingerprint: "f1", ExpiresAt: time.Now().Add(time.Hour)}
+	_, err := db.ReserveIdempotencyKey(ctx, rec)
+	require.NoError(t, err)
+	body := `{"id":1,"user_name":"Анна","phone":"+100"}`
+	require.NoError(t, db.CompleteIdempotencyKey(ctx, "crm", "k1", 201, "application/json", []byte(body)))
+
+	var raw []byte
+	require.NoError(t, db.QueryRow(`SELECT body FROM idempotency_keys WHERE scope = 'crm' AND key = 'k1'`).Scan(&raw))
+	assert.NotContains(t, string(raw), "+100")
+
+	existing, err := db.ReserveIdempotencyKey(ctx, rec)
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, body, string(existing.Body))
+}
@@ -100,7 +100,7 @@ func (db *DB) CreateItem(ctx context.Context, item *models.Item) error {
               is_active, permanent_reserved, cabinet_id, created_at, updated_at)
 	              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	now := time.Now()
-	result, err := db.ExecContext(ctx, query,
+	id, err := db.insertID(ctx, query,
 		item.Name,
 		item.Description,
 		item.TotalQuantity,
@@ -114,11 +114,6 @@ func (db *DB) CreateItem(ctx context.Context, item *models.Item) error {
 	if err != nil {
 		return fmt.Errorf("failed to create item: %w", err)
 	}
-
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get last insert id: %w", err)
-	}
 	item.ID = id
 	item.CreatedAt = now
 	item.UpdatedAt = now
@@ -261,7 +256,7 @@ func (db *DB) UpdateItem(ctx context.Context, item *models.Item) error {
 }
 
 func (db *DB) DeactivateItem(ctx context.Context, id int64) error {
-	query := `UPDATE items SET is_active = 0, updated_at = ? WHERE id = ?`
+	query := `UPDATE items SET is_active = FALSE, updated_at = ? WHERE id = ?`
 	now := time.Now()
 	_, err := db.ExecContext(ctx, query, now, id)
 	if err != nil {
@@ -25,6 +25,10 @@ func (db *DB) CreateExternalBooking(
 	}
 	defer func() { _ = tx.Rollback() }()
 
+	if err := tx.lockItem(ctx, itemID); err != nil {
+		return 0, fmt.Errorf("lock item: %w", err)
+	}
+
 	// Check if external booking ID already exists
 	var existingID int64
 	err = tx.QueryRowContext(ctx,
@@ -39,18 +43,13 @@ func (db *DB) CreateExternalBooking(
 	}
 
 	// Check availability
-	var bookedCount int64
-	err = tx.QueryRowContext(ctx, `
-		SELECT COUNT(*) FROM bookings 
-		WHERE item_id = ? AND date(date) = date(?) AND status = 'approved'`,
-		itemID, date,
-	).Scan(&bookedCount)
+	bookedCount, err := takenCountOn(ctx, tx, itemID, date, 0)
 	if err != nil {
 		return 0, fmt.Errorf("check availability: %w", err)
 	}
 
 	// Get item quantity
-	var totalQty int64
+	var totalQty int
 	err = tx.QueryRowContext(ctx,
 		"SELECT total_quantity FROM items WHERE id = ?",
 		itemID,
@@ -64,20 +63,25 @@ func (db *DB) CreateExternalBooking(
 	}
 
 	// Create booking
+	person, err := sealPerson(db.fields, clientName, clientPhone)
+	if err != nil {
+		return 0, err
+	}
 	now := time.Now()
-	result, err := tx.ExecContext(ctx, `
+	bookingID, err := tx.insertID(ctx, `
 		INSERT INTO bookings (
-			user_id, user_name, user_nickname, phone, item_id, item_name,
+			user_id, user_name, user_nickname, phone, phone_index, item_id, item_name,
 			date, status, external_booking_id, created_at, updated_at, version
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		0, // API booking has no telegram user
-		clientName,
+		person.name,
 		"",
-		clientPhone,
+		person.phone,
+		person.phoneIndex,
 		itemID,
 		itemName,
 		date,
-		"approved", // Auto-approve external bookings
+		models.StatusConfirmed, // Auto-approve external bookings
 		externalBookingID,
 		now,
 		now,
@@ -87,9 +91,8 @@ func (db *DB) CreateExternalBooking(
 		return 0, fmt.Errorf("insert booking: %w", err)
 	}
 
-	bookingID, err := result.LastInsertId()
-	if err != nil {
-		return 0, fmt.Errorf("get last id: %w", err)
+	if err := recordBookingCreated(ctx, tx, &models.Booking{ID: bookingID, Status: models.StatusConfirmed}); err != nil {
+		return 0, err
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -100,27 +103,51 @@ func (db *DB) CreateExternalBooking(
 }
 
 // CancelExternalBooking cancels a booking by external ID.
+// The transition is checked against the API client rules.
 func (db *DB) CancelExternalBooking(ctx context.Context, externalBookingID string) error {
-	result, err := db.ExecContext(ctx, `
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var id, version int64
+	var status string
+	err = tx.QueryRowContext(ctx,
+		"SELECT id, status, version FROM bookings WHERE external_booking_id = ?",
+		externalBookingID,
+	).Scan(&id, &status, &version)
+	if err != nil {
+		return err
+	}
+
+	if err = models.CheckStatusTransition(models.ActorAPI, status, models.StatusCanceled); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `
 		UPDATE bookings 
-		SET status = 'canceled', updated_at = ?
-		WHERE external_booking_id = ? AND status != 'canceled'`,
-		time.Now(), externalBookingID,
+		SET status = ?, updated_at = ?, version = version + 1
+		WHERE id = ? AND version = ?`,
+		models.StatusCanceled, time.Now(), id, version,
 	)
 	if err != nil {
 		return err
 	}
-
-	rowsAffected, err := result.RowsAffected()
+	rows, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if rows == 0 {
+		return ErrConcurrentModification
+	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("booking not found or already canceled")
+	before, after := &bookingSnapshot{status: status}, &bookingSnapshot{status: models.StatusCanceled}
+	if err := recordBookingChanges(ctx, tx, id, before, after); err != nil {
+		return err
 	}
 
-	return nil
+	return tx.Commit()
 }
 
 // GetExternalBooking returns booking by external ID.
@@ -128,7 +155,7 @@ func (db *DB) GetExternalBooking(ctx context.Context, externalBookingID string)
 	var b models.Booking
 	err := db.QueryRowContext(ctx, `
 		SELECT id, user_id, user_name, user_nickname, phone, item_id, item_name,
-		       date, status, comment, reminder_sent, external_booking_id,
+		       date, status, COALESCE(comment, ''), reminder_sent, external_booking_id,
 		       created_at, updated_at, version
 		FROM bookings WHERE external_booking_id = ?`,
 		externalBookingID,
@@ -140,6 +167,9 @@ func (db *DB) GetExternalBooking(ctx context.Context, externalBookingID string)
 	if err != nil {
 		return nil, err
 	}
+	if err := openPerson(db.fields, &b.UserName, &b.Phone); err != nil {
+		return nil, err
+	}
 	return &b, nil
 }
 
@@ -172,7 +202,7 @@ func (db *DB) ListPermanentReservedItems(ctx context.Context) ([]models.Item, er
 	rows, err := db.QueryContext(ctx, `
 		SELECT id, name, description, total_quantity, sort_order,
 		       is_active, permanent_reserved, created_at, updated_at
-		FROM items WHERE permanent_reserved = 1 AND is_active = 1
+		FROM items WHERE permanent_reserved = TRUE AND is_active = TRUE
 		ORDER BY sort_order, id`)
 	if err != nil {
 		return nil, err
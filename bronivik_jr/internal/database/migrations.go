@@ -0,0 +1,46 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"bronivik/internal/migrate"
+	"bronivik/migrations"
+)
+
+// Migrator возвращает исполнитель версионных миграций из каталога migrations.
+// createTables создает только исходную схему (аппараты, пользователи, заявки, очередь
+// синхронизации и доступ); все остальное описано миграциями, чтобы up, down, goto и
+// status управляли им.
+func (db *DB) Migrator(table string, dryRun bool, out io.Writer) (*migrate.Runner, error) {
+	list, err := migrate.Load(migrations.FS, db.dialect.String())
+	if err != nil {
+		return nil, err
+	}
+	return migrate.New(db.DB, list, migrate.Options{
+		Table:   table,
+		Dialect: db.dialect.String(),
+		DryRun:  dryRun,
+		Out:     out,
+	})
+}
+
+// Migrate применяет недостающие миграции при старте. Если схема базы новее,
+// чем знает бинарник, или примененная миграция была изменена, возвращается ошибка.
+func (db *DB) Migrate(ctx context.Context, table string) error {
+	runner, err := db.Migrator(table, false, nil)
+	if err != nil {
+		return err
+	}
+	applied, err := runner.Up(ctx)
+	if err != nil {
+		return fmt.Errorf("apply migrations: %w", err)
+	}
+	version, err := runner.Version(ctx)
+	if err != nil {
+		return err
+	}
+	db.logger.Info().Int("applied", applied).Int64("version", version).Msg("Database migrations are up to date")
+	return nil
+}
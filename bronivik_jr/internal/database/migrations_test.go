@@ -0,0 +1,143 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"bronivik/internal/migrate"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	logger := zerolog.New(io.Discard)
+	db, err := NewDB(filepath.Join(t.TempDir(), "migrate.db"), &logger)
+	require.NoError(t, err)
+	defer db.Close()
+	ctx := context.Background()
+
+	// Миграции применяются поверх базовой схемы и не конфликтуют с ней
+	require.NoError(t, db.Migrate(ctx, "schema_migrations"))
+	require.NoError(t, db.Migrate(ctx, "schema_migrations"))
+
+	runner, err := db.Migrator("schema_migrations", false, nil)
+	require.NoError(t, err)
+	version, err := runner.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, runner.Latest(), version)
+
+	var reminders int
+	require.NoError(t, db.QueryRow(
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'reminders'`).Scan(&reminders))
+	assert.Equal(t, 1, reminders)
+
+	var out bytes.Buffer
+	dry, err := db.Migrator("schema_migrations", true, &out)
+	require.NoError(t, err)
+	n, err := dry.Goto(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int(version), n)
+	assert.Contains(t, out.String(), "DROP TABLE IF EXISTS reminders")
+
+	// Откат и повторное применение миграций поверх базовой схемы
+	_, err = runner.Goto(ctx, 2)
+	require.NoError(t, err)
+	var consentLog int
+	require.NoError(t, db.QueryRow(
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'consent_log'`).Scan(&consentLog))
+	assert.Zero(t, consentLog)
+	require.NoError(t, db.Migrate(ctx, "schema_migrations"))
+	require.NoError(t, db.QueryRow(
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'consent_log'`).Scan(&consentLog))
+	assert.Equal(t, 1, consentLog)
+
+	// Все таблицы сверх исходной схемы принадлежат миграциям: откат до нуля их удаляет
+	tables := []string{"booking_series", "booking_history", "events", "webhook_subscriptions",
+		"webhook_deliveries", "idempotency_keys", "waitlist", "api_keys"}
+	countTables := func() int {
+		var n int
+		for _, table := range tables {
+			var c int
+			require.NoError(t, db.QueryRow(
+				`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&c))
+			n += c
+		}
+		return n
+	}
+	_, err = runner.Goto(ctx, 0)
+	require.NoError(t, err)
+	assert.Zero(t, countTables())
+	_, err = db.Exec(`SELECT end_time FROM bookings`)
+	assert.Error(t, err)
+	require.NoError(t, db.Migrate(ctx, "schema_migrations"))
+	assert.Equal(t, len(tables), countTables())
+
+	// Запись о версии, которой нет в бинарнике, блокирует запуск
+	_, err = db.Exec(`INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (999, 'future', '', ?)`,
+		"2030-01-01 00:00:00")
+	require.NoError(t, err)
+	assert.ErrorIs(t, db.Migrate(ctx, "schema_migrations"), migrate.ErrSchemaNewer)
+}
+
+func TestMigrateStripsQueuedClientNames(t *testing.T) {
+	logger := zerolog.New(io.Discard)
+	db, err := NewDB(filepath.Join(t.TempDir(), "migrate.db"), &logger)
+	require.NoError(t, err)
+	defer db.Close()
+	ctx := context.Background()
+
+	runner, err := db.Migrator("schema_migrations", false, nil)
+	require.NoError(t, err)
+	_, err = runner.Goto(ctx, 14)
+	require.NoError(t, err)
+
+	// Строки, записанные до 015: заявка целиком в задаче и имя клиента в событии
+	_, err = db.Exec(`INSERT INTO sync_queue (task_type, booking_id, payload) VALUES
+		('upsert', 7, '{"booking_id":7,"booking":{"id":7,"user_name":"Анна","phone":"+100"}}'),
+		('update_status', 7, '{"booking_id":7,"status":"confirmed"}')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO events (type, booking_id, payload)
+		VALUES ('booking_created', 7, '{"booking_id":7,"user_id":1,"user_name":"Анна","item_name":"Item"}')`)
+	require.NoError(t, err)
+	require.NoError(t, db.Migrate(ctx, "schema_migrations"))
+
+	var upsert, status, event string
+	require.NoError(t, db.QueryRow(`SELECT payload FROM sync_queue WHERE task_type = 'upsert'`).Scan(&upsert))
+	require.NoError(t, db.QueryRow(`SELECT payload FROM sync_queue WHERE task_type = 'update_status'`).Scan(&status))
+	require.NoError(t, db.QueryRow(`SELECT payload FROM events`).Scan(&event))
+	assert.JSONEq(t, `{"booking_id":7}`, upsert)
+	assert.JSONEq(t, `{"booking_id":7,"status":"confirmed"}`, status)
+	assert.JSONEq(t, `{"booking_id":7,"user_id":1,"item_name":"Item"}`, event)
+}
+
+func TestMigrateLinksRotatedAPIKeysToClient(t *testing.T) {
+	logger := zerolog.New(io.Discard)
+	db, err := NewDB(filepath.Join(t.TempDir(), "migrate.db"), &logger)
+	require.NoError(t, err)
+	defer db.Close()
+	ctx := context.Background()
+
+	runner, err := db.Migrator("schema_migrations", false, nil)
+	require.NoError(t, err)
+	_, err = runner.Goto(ctx, 15)
+	require.NoError(t, err)
+
+	// Ключ 1 дважды ротирован (1 -> 2 -> 3), ключ 4 не ротировался
+	_, err = db.Exec(`INSERT INTO api_keys (id, name, key_prefix, key_hash, replaced_by) VALUES
+		(1, 'crm', 'bk_1', 'h1', 2), (2, 'crm', 'bk_2', 'h2', 3), (3, 'crm', 'bk_3', 'h3', NULL),
+		(4, 'partner', 'bk_4', 'h4', NULL)`)
+	require.NoError(t, err)
+	require.NoError(t, db.Migrate(ctx, "schema_migrations"))
+
+	keys, err := db.GetAPIKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 4)
+	for i, want := range []int64{1, 1, 1, 4} {
+		assert.Equal(t, want, keys[i].ClientID, "key %d", keys[i].ID)
+	}
+}
@@ -0,0 +1,175 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"bronivik/internal/config"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // pgx driver
+	"github.com/rs/zerolog"
+)
+
+// NewPostgresDB подключается к PostgreSQL и создает таблицы, если их нет.
+func NewPostgresDB(cfg *config.PostgresConfig, logger *zerolog.Logger) (*DB, error) {
+	db, err := sql.Open("pgx", postgresDSN(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	maxConns := cfg.MaxConnections
+	if maxConns <= 0 {
+		maxConns = 10
+	}
+	db.SetMaxOpenConns(maxConns)
+	db.SetMaxIdleConns(maxConns / 2)
+	db.SetConnMaxLifetime(time.Hour)
+
+	instance, err := initDB(db, dialectPostgres, logger)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info().Str("host", cfg.Host).Str("dbname", cfg.DBName).Msg("Database initialized")
+	return instance, nil
+}
+
+// postgresDSN собирает строку подключения. Сессия работает в UTC, чтобы date()
+// от TIMESTAMPTZ давал тот же день, что и сохраненная дата бронирования.
+func postgresDSN(cfg *config.PostgresConfig) string {
+	port := cfg.Port
+	if port == 0 {
+		port = 5432
+	}
+	query := url.Values{}
+	if cfg.SSLMode != "" {
+		query.Set("sslmode", cfg.SSLMode)
+	}
+	query.Set("timezone", "UTC")
+
+	u := url.URL{
+		Scheme:   "postgres",
+		Host:     net.JoinHostPort(cfg.Host, strconv.Itoa(port)),
+		Path:     "/" + cfg.DBName,
+		RawQuery: query.Encode(),
+	}
+	if cfg.User != "" {
+		u.User = url.UserPassword(cfg.User, cfg.Password)
+	}
+	return u.String()
+}
+
+// createPostgresTables создает ту же исходную схему, что и для SQLite. Внешние ключи на
+// users не объявляются: SQLite их не проверяет, а заявки из API пишутся с user_id = 0.
+func (db *DB) createPostgresTables() error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS items (
+			id BIGSERIAL PRIMARY KEY,
+			name TEXT UNIQUE NOT NULL,
+			description TEXT,
+			total_quantity BIGINT NOT NULL DEFAULT 1,
+			cabinet_id BIGINT,
+			sort_order BIGINT NOT NULL DEFAULT 0,
+			is_active BOOLEAN NOT NULL DEFAULT TRUE,
+			permanent_reserved BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at TIMESTAMPTZ DEFAULT now(),
+			updated_at TIMESTAMPTZ DEFAULT now()
+		)`,
+		`CREATE TABLE IF NOT EXISTS users (
+			id BIGSERIAL PRIMARY KEY,
+			telegram_id BIGINT UNIQUE NOT NULL,
+			username TEXT,
+			first_name TEXT NOT NULL,
+			last_name TEXT,
+			phone TEXT,
+			is_manager BOOLEAN NOT NULL DEFAULT FALSE,
+			is_blacklisted BOOLEAN NOT NULL DEFAULT FALSE,
+			language_code TEXT,
+			last_activity TIMESTAMPTZ NOT NULL,
+			created_at TIMESTAMPTZ DEFAULT now(),
+			updated_at TIMESTAMPTZ DEFAULT now()
+		)`,
+		`CREATE TABLE IF NOT EXISTS user_settings (
+			id BIGSERIAL PRIMARY KEY,
+			user_id BIGINT NOT NULL UNIQUE,
+			reminders_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			reminder_hours_before BIGINT NOT NULL DEFAULT 24,
+			created_at TIMESTAMPTZ DEFAULT now(),
+			updated_at TIMESTAMPTZ DEFAULT now()
+		)`,
+		`CREATE TABLE IF NOT EXISTS bookings (
+			id BIGSERIAL PRIMARY KEY,
+			user_id BIGINT NOT NULL,
+			user_name TEXT NOT NULL,
+			user_nickname TEXT,
+			phone TEXT NOT NULL,
+			item_id BIGINT NOT NULL REFERENCES items(id),
+			item_name TEXT NOT NULL,
+			date TIMESTAMPTZ NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			comment TEXT,
+			reminder_sent BOOLEAN NOT NULL DEFAULT FALSE,
+			external_booking_id TEXT,
+			created_at TIMESTAMPTZ DEFAULT now(),
+			updated_at TIMESTAMPTZ DEFAULT now(),
+			version BIGINT NOT NULL DEFAULT 1
+		)`,
+
+		`CREATE INDEX IF NOT EXISTS idx_users_telegram_id ON users(telegram_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_users_is_manager ON users(is_manager)`,
+		`CREATE INDEX IF NOT EXISTS idx_users_is_blacklisted ON users(is_blacklisted)`,
+		`CREATE INDEX IF NOT EXISTS idx_items_sort ON items(sort_order, id)`,
+		`CREATE INDEX IF NOT EXISTS idx_items_cabinet ON items(cabinet_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_items_active ON items(is_active)`,
+		`CREATE INDEX IF NOT EXISTS idx_items_permanent ON items(permanent_reserved)`,
+		`CREATE INDEX IF NOT EXISTS idx_user_settings_user_id ON user_settings(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_bookings_item_date_status ON bookings(item_id, date, status)`,
+		`CREATE INDEX IF NOT EXISTS idx_bookings_date ON bookings(date)`,
+		`CREATE INDEX IF NOT EXISTS idx_bookings_status ON bookings(status)`,
+		`CREATE INDEX IF NOT EXISTS idx_bookings_item_id ON bookings(item_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_bookings_user_id ON bookings(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_bookings_reminder ON bookings(reminder_sent, date)`,
+		`CREATE INDEX IF NOT EXISTS idx_bookings_external ON bookings(external_booking_id)`,
+
+		`CREATE TABLE IF NOT EXISTS sync_queue (
+			id BIGSERIAL PRIMARY KEY,
+			task_type TEXT NOT NULL,
+			booking_id BIGINT NOT NULL,
+			payload TEXT,
+			status TEXT DEFAULT 'pending',
+			retry_count BIGINT DEFAULT 0,
+			last_error TEXT,
+			created_at TIMESTAMPTZ DEFAULT now(),
+			processed_at TIMESTAMPTZ,
+			next_retry_at TIMESTAMPTZ
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_sync_queue_status ON sync_queue(status)`,
+		`CREATE INDEX IF NOT EXISTS idx_sync_queue_next_retry ON sync_queue(next_retry_at)`,
+
+		`CREATE TABLE IF NOT EXISTS blocked_users (
+			user_id BIGINT PRIMARY KEY,
+			blocked_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			reason TEXT,
+			blocked_by BIGINT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS managers (
+			user_id BIGINT PRIMARY KEY,
+			chat_id BIGINT NOT NULL,
+			name TEXT NOT NULL,
+			added_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			added_by BIGINT NOT NULL DEFAULT 0
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_blocked_users_blocked_at ON blocked_users(blocked_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_managers_chat_id ON managers(chat_id)`,
+	}
+
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("error executing query %s: %v", query, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"bronivik/internal/retention"
+)
+
+// retentionCatalog — таблицы и поля с персональными данными, которые может
+// трогать политика хранения. Возраст заявок считается от даты окончания.
+var retentionCatalog = []retention.Table{
+	{
+		Name: "users",
+		Age:  "last_activity",
+		Columns: map[string]retention.Column{
+			"username":   {},
+			"first_name": {},
+			"last_name":  {},
+			"phone":      {},
+		},
+	},
+	{
+		Name: "bookings",
+		Age:  "COALESCE(end_time, date)",
+		Columns: map[string]retention.Column{
+			"user_name":     {},
+			"user_nickname": {},
+			"phone":         {Derived: []string{"phone_index"}},
+			"comment":       {},
+		},
+		Deletable: true,
+	},
+	{
+		Name: "booking_series",
+		Age:  "COALESCE(until_date, updated_at)",
+		Columns: map[string]retention.Column{
+			"user_name":     {},
+			"user_nickname": {},
+			"phone":         {},
+			"comment":       {},
+		},
+	},
+	{
+		Name:      "booking_history",
+		Age:       "created_at",
+		Columns:   map[string]retention.Column{"actor_name": {}},
+		Deletable: true,
+	},
+	{
+		Name: "waitlist",
+		Age:  "date",
+		Columns: map[string]retention.Column{
+			"user_name":     {},
+			"user_nickname": {},
+			"phone":         {},
+		},
+		Deletable: true,
+	},
+	{
+		Name:      "reminders",
+		Age:       "COALESCE(sent_at, scheduled_at)",
+		Deletable: true,
+	},
+	// Копии заявок в очередях; удаляются только обработанные строки, иначе
+	// событие или задача синхронизации потеряются
+	{
+		Name:      "events",
+		Age:       "created_at",
+		Where:     "processed_at IS NOT NULL",
+		Deletable: true,
+	},
+	{
+		Name:      "webhook_deliveries",
+		Age:       "created_at",
+		Where:     "status <> 'pending'",
+		Deletable: true,
+	},
+	{
+		Name:      "sync_queue",
+		Age:       "created_at",
+		Where:     "status IN ('completed', 'failed')",
+		Deletable: true,
+	},
+}
+
+// RetentionEngine собирает движок политики хранения. Хешируются расшифрованные
+// значения, поэтому SetFieldCipher вызывается до него.
+func (db *DB) RetentionEngine(rules []retention.Rule, opts retention.Options) (*retention.Engine, error) {
+	opts.Decrypt = db.fields.Decrypt
+	return retention.New(db, retentionCatalog, rules, opts)
+}
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"bronivik/internal/models"
+	"bronivik/internal/retention"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetentionEngine(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	db.SetFieldCipher(testFieldCipher(t, "k1"))
+
+	item := &models.Item{Name: "Item 1", TotalQuantity: 2, IsActive: true}
+	require.NoError(t, db.CreateItem(ctx, item))
+
+	old := &models.Booking{
+		ItemID: item.ID, ItemName: item.Name, Date: time.Now().AddDate(-1, 0, 0),
+		UserID: 1, UserName: "Анна Петрова", Phone: "+7 999 123-45-67", Status: models.StatusCompleted,
+	}
+	require.NoError(t, db.CreateBooking(ctx, old))
+	recent := &models.Booking{
+		ItemID: item.ID, ItemName: item.Name, Date: time.Now().AddDate(0, 0, 1),
+		UserID: 2, UserName: "Иван Сидоров", Phone: "79990000002", Status: models.StatusPending,
+	}
+	require.NoError(t, db.CreateBooking(ctx, recent))
+
+	const halfYear = 180 * 24 * time.Hour
+	engine, err := db.RetentionEngine([]retention.Rule{
+		{Table: "bookings", Column: "phone", Action: retention.ActionClear, After: halfYear},
+		{Table: "bookings", Column: "user_name", Action: retention.ActionHash, After: halfYear},
+	}, retention.Options{HashKey: []byte("secret")})
+	require.NoError(t, err)
+
+	// Пробный прогон только считает строки
+	report, err := engine.Run(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), report.Total())
+	got, err := db.GetBooking(ctx, old.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "+7 999 123-45-67", got.Phone)
+
+	report, err = engine.Run(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), report.Total())
+
+	name, phone, index := rawBookingPerson(t, db, old.ID)
+	assert.True(t, strings.HasPrefix(name, retention.HashPrefix), name)
+	assert.Empty(t, phone)
+	assert.Empty(t, index)
+
+	got, err = db.GetBooking(ctx, recent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Иван Сидоров", got.UserName)
+	assert.Equal(t, "79990000002", got.Phone)
+
+	// Что сделано, попадает в аудит-выгрузку
+	rows, _, err := db.GetTableData(ctx, "retention_log")
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "bookings", rows[0]["table_name"])
+	assert.Equal(t, "phone", rows[0]["column_name"])
+
+	// Хеши не перешифровываются при ротации ключей
+	db.SetFieldCipher(testFieldCipher(t, "k2"))
+	_, err = db.RotateFieldEncryption(ctx, 10)
+	require.NoError(t, err)
+	rotatedName, _, _ := rawBookingPerson(t, db, old.ID)
+	assert.Equal(t, name, rotatedName)
+}
+
+func TestRetentionKeepsPendingQueueRows(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	item := &models.Item{Name: "Item 1", TotalQuantity: 2, IsActive: true}
+	require.NoError(t, db.CreateItem(ctx, item))
+	for i := 0; i < 2; i++ {
+		require.NoError(t, db.CreateBooking(ctx, &models.Booking{
+			ItemID: item.ID, ItemName: item.Name, Date: time.Now(),
+			UserID: int64(i + 1), UserName: "Анна", Phone: "+100", Status: models.StatusPending,
+		}))
+	}
+	pending, err := db.GetPendingEvents(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	require.NoError(t, db.MarkEventProcessed(ctx, pending[0].ID))
+
+	// Через год обработанное событие удаляется, необработанное остается
+	engine, err := db.RetentionEngine([]retention.Rule{
+		{Table: "events", Action: retention.ActionDelete, After: 30 * 24 * time.Hour},
+	}, retention.Options{Now: func() time.Time { return time.Now().AddDate(1, 0, 0) }})
+	require.NoError(t, err)
+	report, err := engine.Run(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), report.Total())
+
+	left, err := db.GetPendingEvents(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	require.Len(t, left, 1)
+	assert.Equal(t, pending[1].ID, left[0].ID)
+}
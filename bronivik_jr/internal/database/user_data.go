@@ -0,0 +1,292 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"bronivik/internal/fieldcrypt"
+	"bronivik/internal/models"
+)
+
+// userDataQueries перечисляет таблицы с данными пользователя. Каждый запрос
+// получает telegram_id во всех плейсхолдерах.
+var userDataQueries = []struct {
+	table string
+	query string
+}{
+	{"users", `SELECT * FROM users WHERE telegram_id = ?`},
+	{"user_settings", `SELECT * FROM user_settings WHERE user_id = ?`},
+	{"bookings", `SELECT * FROM bookings WHERE user_id = ? ORDER BY id`},
+	{"booking_series", `SELECT * FROM booking_series WHERE user_id = ? ORDER BY id`},
+	{"booking_history", `SELECT * FROM booking_history
+		WHERE booking_id IN (SELECT id FROM bookings WHERE user_id = ?)
+		   OR (actor_type = 'user' AND actor_id = ?)
+		ORDER BY id`},
+	{"reminders", `SELECT * FROM reminders WHERE user_id = ? ORDER BY id`},
+	{"waitlist", `SELECT * FROM waitlist WHERE user_id = ? ORDER BY id`},
+	{"consent_log", `SELECT * FROM consent_log WHERE user_id = ? ORDER BY id`},
+	{"events", `SELECT * FROM events WHERE booking_id IN (SELECT id FROM bookings WHERE user_id = ?) ORDER BY id`},
+	{"webhook_deliveries", `SELECT * FROM webhook_deliveries
+		WHERE event_id IN (SELECT id FROM events WHERE booking_id IN (SELECT id FROM bookings WHERE user_id = ?))
+		ORDER BY id`},
+	{"sync_queue", `SELECT * FROM sync_queue WHERE booking_id IN (SELECT id FROM bookings WHERE user_id = ?) ORDER BY id`},
+}
+
+// userPayloadQueries выбирают JSON-копии заявок пользователя: события outbox, доставки
+// вебхуков и задачи синхронизации с Sheets. Запрос получает telegram_id.
+var userPayloadQueries = []struct {
+	table string
+	query string
+}{
+	{"events", `SELECT id, payload FROM events WHERE booking_id IN (SELECT id FROM bookings WHERE user_id = ?)`},
+	{"webhook_deliveries", `SELECT id, payload FROM webhook_deliveries
+		WHERE event_id IN (SELECT id FROM events WHERE booking_id IN (SELECT id FROM bookings WHERE user_id = ?))`},
+	{"sync_queue", `SELECT id, payload FROM sync_queue WHERE booking_id IN (SELECT id FROM bookings WHERE user_id = ?)`},
+}
+
+// ExportUserData собирает все строки, относящиеся к пользователю: профиль,
+// настройки, заявки, напоминания, лист ожидания и записи аудита.
+func (db *DB) ExportUserData(ctx context.Context, telegramID int64) (*models.UserDataExport, error) {
+	export := &models.UserDataExport{
+		Service:     "bronivik_jr",
+		TelegramID:  telegramID,
+		GeneratedAt: time.Now(),
+	}
+
+	for _, q := range userDataQueries {
+		args := make([]interface{}, countPlaceholders(q.query))
+		for i := range args {
+			args[i] = telegramID
+		}
+
+		rows, err := db.QueryContext(ctx, q.query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("export %s: %w", q.table, err)
+		}
+		data, columns, err := scanRowMaps(rows)
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("export %s: %w", q.table, err)
+		}
+
+		// Текстовые колонки драйвер может вернуть как []byte — в JSON они стали бы base64.
+		// Зашифрованные телефоны и имена пользователь получает в открытом виде.
+		for _, row := range data {
+			for col, v := range row {
+				if b, ok := v.([]byte); ok {
+					v = string(b)
+					row[col] = v
+				}
+				if str, ok := v.(string); ok && fieldcrypt.IsEncrypted(str) {
+					if row[col], err = db.fields.Decrypt(str); err != nil {
+						return nil, fmt.Errorf("export %s: %w", q.table, err)
+					}
+				}
+			}
+		}
+		export.Sections = append(export.Sections, models.UserDataSection{Name: q.table, Columns: columns, Rows: data})
+	}
+
+	return export, nil
+}
+
+// EraseUserData удаляет персональные данные пользователя. Профиль, настройки,
+// напоминания и записи листа ожидания удаляются; в заявках, сериях и истории
+// имя, телефон и комментарий стираются, а telegram_id заменяется псевдонимом
+// (-users.id), чтобы статистика по аппаратам, датам и статусам не менялась.
+// Те же поля стираются в JSON событий, доставок вебхуков и задач синхронизации;
+// уже доставленные вебхуки удаляются.
+func (db *DB) EraseUserData(ctx context.Context, telegramID int64) (*models.ErasureResult, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var userRowID int64
+	err = tx.QueryRowContext(ctx, `SELECT id FROM users WHERE telegram_id = ?`, telegramID).Scan(&userRowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	result := &models.ErasureResult{
+		TelegramID:  telegramID,
+		PseudonymID: -userRowID,
+		Pseudonym:   models.ErasedUserPseudonym(userRowID),
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM bookings WHERE user_id = ? ORDER BY id`, telegramID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bookings: %w", err)
+	}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		result.BookingIDs = append(result.BookingIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// JSON-копии заявок обрабатываются до псевдонимизации, пока заявки находятся по telegram_id
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM webhook_deliveries WHERE status = ?
+		  AND event_id IN (SELECT id FROM events WHERE booking_id IN (SELECT id FROM bookings WHERE user_id = ?))`,
+		models.WebhookDeliveryDelivered, telegramID); err != nil {
+		return nil, fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+	for _, q := range userPayloadQueries {
+		if err := scrubUserPayloads(ctx, tx, q.table, q.query, telegramID, result); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	pseudonymize := []struct {
+		query string
+		args  []interface{}
+	}{
+		{`UPDATE bookings SET user_id = ?, user_name = ?, user_nickname = '', phone = '', phone_index = '', comment = '',
+			updated_at = ?, version = version + 1
+		  WHERE user_id = ?`,
+			[]interface{}{result.PseudonymID, result.Pseudonym, now, telegramID}},
+		{`UPDATE booking_series SET user_id = ?, user_name = ?, user_nickname = '', phone = '', comment = '',
+			updated_at = ?
+		  WHERE user_id = ?`,
+			[]interface{}{result.PseudonymID, result.Pseudonym, now, telegramID}},
+		{`UPDATE booking_history SET actor_id = ?, actor_name = ? WHERE actor_type = ? AND actor_id = ?`,
+			[]interface{}{result.PseudonymID, result.Pseudonym, string(models.ActorUser), telegramID}},
+		{`UPDATE consent_log SET user_id = ? WHERE user_id = ?`,
+			[]interface{}{result.PseudonymID, telegramID}},
+	}
+	for _, q := range pseudonymize {
+		if _, err := tx.ExecContext(ctx, q.query, q.args...); err != nil {
+			return nil, fmt.Errorf("failed to pseudonymize user data: %w", err)
+		}
+	}
+
+	for _, query := range []string{
+		`DELETE FROM waitlist WHERE user_id = ?`,
+		`DELETE FROM reminders WHERE user_id = ?`,
+		`DELETE FROM user_settings WHERE user_id = ?`,
+		`DELETE FROM users WHERE telegram_id = ?`,
+	} {
+		res, err := tx.ExecContext(ctx, query, telegramID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete user data: %w", err)
+		}
+		if n, errRows := res.RowsAffected(); errRows == nil {
+			result.DeletedRows += n
+		}
+	}
+
+	// Факт удаления остается в журнале согласий под псевдонимом
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO consent_log (user_id, action, policy_version, created_at)
+		VALUES (?, ?, '', ?)`, result.PseudonymID, models.ConsentActionErased, now); err != nil {
+		return nil, fmt.Errorf("failed to log erasure: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// scrubUserPayloads переписывает колонку payload выбранных строк таблицы без персональных данных.
+func scrubUserPayloads(ctx context.Context, tx *Tx, table, query string, telegramID int64, result *models.ErasureResult) error {
+	rows, err := tx.QueryContext(ctx, query, telegramID)
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", table, err)
+	}
+	scrubbed := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var payload sql.NullString
+		if err := rows.Scan(&id, &payload); err != nil {
+			rows.Close()
+			return err
+		}
+		if !payload.Valid || payload.String == "" {
+			continue
+		}
+		if scrubbed[id], err = scrubPayload(payload.String, telegramID, result); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scrub %s %d: %w", table, id, err)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, payload := range scrubbed {
+		if _, err := tx.ExecContext(ctx, `UPDATE `+table+` SET payload = ? WHERE id = ?`, payload, id); err != nil {
+			return fmt.Errorf("failed to update %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// scrubPayload заменяет в JSON имя и telegram_id пользователя псевдонимом и стирает
+// телефон, ник и комментарий на любом уровне вложенности (заявка в задаче Sheets вложена).
+func scrubPayload(payload string, telegramID int64, result *models.ErasureResult) (string, error) {
+	dec := json.NewDecoder(strings.NewReader(payload))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return "", err
+	}
+
+	user := json.Number(fmt.Sprint(telegramID))
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			for key, val := range node {
+				switch key {
+				case "user_id", "changed_by_id":
+					if val == user {
+						node[key] = result.PseudonymID
+					}
+				case "user_name":
+					node[key] = result.Pseudonym
+				case "user_nickname", "phone", "comment":
+					node[key] = ""
+				default:
+					walk(val)
+				}
+			}
+		case []interface{}:
+			for _, item := range node {
+				walk(item)
+			}
+		}
+	}
+	walk(v)
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// countPlaceholders считает плейсхолдеры "?" в запросе.
+func countPlaceholders(query string) int {
+	n := 0
+	for i := 0; i < len(query); i++ {
+		if query[i] == '?' {
+			n++
+		}
+	}
+	return n
+}
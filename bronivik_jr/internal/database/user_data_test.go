@@ -0,0 +1,160 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"bronivik/internal/events"
+	"bronivik/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportAndEraseUserData(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	require.NoError(t, db.Migrate(ctx, ""))
+
+	item := &models.Item{Name: "Item 1", TotalQuantity: 1, IsActive: true}
+	require.NoError(t, db.CreateItem(ctx, item))
+	require.NoError(t, db.CreateOrUpdateUser(ctx, &models.User{
+		TelegramID: 800, FirstName: "Anna", Phone: "79991234567", LastActivity: time.Now(),
+	}))
+	require.NoError(t, db.CreateOrUpdateUser(ctx, &models.User{
+		TelegramID: 801, FirstName: "Boris", LastActivity: time.Now(),
+	}))
+	require.NoError(t, db.GiveConsent(ctx, 800, models.PrivacyPolicyVersion))
+	require.NoError(t, db.UpsertUserSettings(ctx, 800, true, 24))
+
+	userCtx := models.WithActor(ctx, models.Actor{Type: models.ActorUser, ID: 800})
+	booking := &models.Booking{
+		ItemID: item.ID, ItemName: item.Name, Date: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+		UserID: 800, UserName: "Anna", Phone: "79991234567", Comment: "позвонить вечером", Status: models.StatusPending,
+	}
+	require.NoError(t, db.CreateBookingWithLock(userCtx, booking))
+	other := &models.Booking{
+		ItemID: item.ID, ItemName: item.Name, Date: time.Date(2025, 12, 2, 0, 0, 0, 0, time.UTC),
+		UserID: 801, UserName: "Boris", Phone: "1", Status: models.StatusPending,
+	}
+	require.NoError(t, db.CreateBooking(ctx, other))
+
+	// Копии заявки в событиях, вебхуках и задачах синхронизации; одна доставка уже отправлена
+	require.NoError(t, db.CreateWebhookSubscription(ctx, &models.WebhookSubscription{
+		Name: "crm", URL: "https://crm.example/hook", EventTypes: events.BookingEventTypes, Secret: "s",
+	}))
+	pending, err := db.GetPendingEvents(ctx, time.Now().Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.NotEmpty(t, pending)
+	for _, ev := range pending {
+		_, err := db.EnqueueWebhookDeliveries(ctx, ev)
+		require.NoError(t, err)
+	}
+	deliveries, err := db.GetWebhookDeliveries(ctx, models.WebhookDeliveryPending, 10)
+	require.NoError(t, err)
+	require.NoError(t, db.MarkWebhookDelivered(ctx, deliveries[len(deliveries)-1].ID, 200))
+	taskPayload, err := json.Marshal(map[string]interface{}{"booking_id": booking.ID, "booking": booking})
+	require.NoError(t, err)
+	require.NoError(t, db.CreateSyncTask(ctx, &models.SyncTask{
+		TaskType: "upsert", BookingID: booking.ID, Payload: string(taskPayload), Status: "pending",
+	}))
+
+	export, err := db.ExportUserData(ctx, 800)
+	require.NoError(t, err)
+	sections := make(map[string]models.UserDataSection)
+	for _, s := range export.Sections {
+		sections[s.Name] = s
+	}
+	require.Len(t, sections["users"].Rows, 1)
+	assert.Equal(t, "Anna", sections["users"].Rows[0]["first_name"])
+	assert.Len(t, sections["user_settings"].Rows, 1)
+	require.Len(t, sections["bookings"].Rows, 1, "чужие заявки не попадают в выгрузку")
+	assert.Len(t, sections["booking_history"].Rows, 1)
+	assert.Len(t, sections["consent_log"].Rows, 1)
+	assert.Contains(t, sections, "reminders")
+	assert.NotEmpty(t, sections["events"].Rows)
+	assert.NotEmpty(t, sections["webhook_deliveries"].Rows)
+	assert.Len(t, sections["sync_queue"].Rows, 1)
+	_, err = json.Marshal(export)
+	require.NoError(t, err)
+
+	result, err := db.EraseUserData(ctx, 800)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{booking.ID}, result.BookingIDs)
+	assert.Less(t, result.PseudonymID, int64(0))
+
+	_, err = db.GetUserByTelegramID(ctx, 800)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	// Заявка остается для статистики, но без персональных данных
+	erased, err := db.GetBooking(ctx, booking.ID)
+	require.NoError(t, err)
+	assert.Equal(t, result.PseudonymID, erased.UserID)
+	assert.Equal(t, result.Pseudonym, erased.UserName)
+	assert.Empty(t, erased.Phone)
+	assert.Empty(t, erased.Comment)
+	assert.Equal(t, item.ID, erased.ItemID)
+	assert.Equal(t, models.StatusPending, erased.Status)
+
+	history, err := db.GetBookingHistory(ctx, booking.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, result.PseudonymID, history[0].ActorID)
+
+	kept, err := db.GetBooking(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Boris", kept.UserName)
+
+	// Ни в одной таблице не осталось имени, телефона и комментария
+	dump := dumpAllTables(t, db)
+	for _, value := range []string{"Anna", "79991234567", "позвонить вечером"} {
+		assert.NotContains(t, dump, value)
+	}
+	assert.Contains(t, dump, "Boris")
+
+	export, err = db.ExportUserData(ctx, 800)
+	require.NoError(t, err)
+	assert.True(t, export.IsEmpty())
+
+	_, err = db.EraseUserData(ctx, 800)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+// dumpAllTables возвращает содержимое всех таблиц базы одной строкой.
+func dumpAllTables(t *testing.T, db *DB) string {
+	t.Helper()
+	ctx := context.Background()
+	rows, err := db.QueryContext(ctx, `SELECT name FROM sqlite_master WHERE type = 'table'`)
+	require.NoError(t, err)
+	var tables []string
+	for rows.Next() {
+		var name string
+		require.NoError(t, rows.Scan(&name))
+		tables = append(tables, name)
+	}
+	require.NoError(t, rows.Close())
+
+	var b strings.Builder
+	for _, table := range tables {
+		rows, err := db.QueryContext(ctx, `SELECT * FROM `+table)
+		require.NoError(t, err)
+		data, _, err := scanRowMaps(rows)
+		rows.Close()
+		require.NoError(t, err)
+		for _, row := range data {
+			for column, value := range row {
+				if raw, ok := value.([]byte); ok {
+					value = string(raw)
+				}
+				fmt.Fprintf(&b, "%s.%s=%v\n", table, column, value)
+			}
+		}
+	}
+	return b.String()
+}
@@ -13,14 +13,14 @@ import (
 func (db *DB) GetUserSettings(ctx context.Context, telegramID int64) (*models.UserSettings, error) {
 	row := db.QueryRowContext(ctx, `
 		SELECT us.id, us.user_id, us.reminders_enabled, us.reminder_hours_before, 
-		       us.created_at, us.updated_at
+		       us.language, us.created_at, us.updated_at
 		FROM user_settings us
 		JOIN users u ON u.telegram_id = us.user_id
 		WHERE u.telegram_id = ?`, telegramID)
 
 	var s models.UserSettings
 	err := row.Scan(&s.ID, &s.UserID, &s.RemindersEnabled, &s.ReminderHoursBefore,
-		&s.CreatedAt, &s.UpdatedAt)
+		&s.Language, &s.CreatedAt, &s.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// Return default settings
@@ -51,6 +51,33 @@ func (db *DB) UpsertUserSettings(ctx context.Context, telegramID int64, reminder
 	return err
 }
 
+// GetUserLanguage returns the language chosen with /language and the
+// language_code reported by Telegram. Either may be empty.
+func (db *DB) GetUserLanguage(ctx context.Context, telegramID int64) (chosen, telegram string, err error) {
+	err = db.QueryRowContext(ctx, `
+		SELECT COALESCE(us.language, ''), COALESCE(u.language_code, '')
+		FROM users u
+		LEFT JOIN user_settings us ON us.user_id = u.telegram_id
+		WHERE u.telegram_id = ?`, telegramID).Scan(&chosen, &telegram)
+	if err == sql.ErrNoRows {
+		return "", "", nil
+	}
+	return chosen, telegram, err
+}
+
+// SetUserLanguage stores the interface language of a user.
+func (db *DB) SetUserLanguage(ctx context.Context, telegramID int64, lang string) error {
+	now := time.Now()
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO user_settings (user_id, language, created_at, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET
+			language = excluded.language,
+			updated_at = excluded.updated_at`,
+		telegramID, lang, now, now)
+	return err
+}
+
 // ToggleReminders toggles reminder setting for a user and returns new state.
 func (db *DB) ToggleReminders(ctx context.Context, telegramID int64) (bool, error) {
 	settings, err := db.GetUserSettings(ctx, telegramID)
@@ -79,7 +106,7 @@ func (db *DB) GetUpcomingBookingsForReminders(ctx context.Context, within time.D
 		FROM bookings
 		WHERE date BETWEEN ? AND ?
 		  AND status IN ('pending', 'confirmed')
-		  AND reminder_sent = 0
+		  AND reminder_sent = FALSE
 		ORDER BY date ASC`, now, until)
 	if err != nil {
 		return nil, err
@@ -99,6 +126,9 @@ func (db *DB) GetUpcomingBookingsForReminders(ctx context.Context, within time.D
 		if extID.Valid {
 			b.ExternalBookingID = extID.String
 		}
+		if err := openPerson(db.fields, &b.UserName, &b.Phone); err != nil {
+			return nil, err
+		}
 		bookings = append(bookings, b)
 	}
 	return bookings, rows.Err()
@@ -107,7 +137,7 @@ func (db *DB) GetUpcomingBookingsForReminders(ctx context.Context, within time.D
 // MarkBookingReminderSent marks a booking as having had its reminder sent.
 func (db *DB) MarkBookingReminderSent(ctx context.Context, bookingID int64) error {
 	_, err := db.ExecContext(ctx, `
-		UPDATE bookings SET reminder_sent = 1, updated_at = ?
+		UPDATE bookings SET reminder_sent = TRUE, updated_at = ?
 		WHERE id = ?`, time.Now(), bookingID)
 	return err
 }
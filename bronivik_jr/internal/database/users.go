@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"bronivik/internal/fieldcrypt"
 	"bronivik/internal/models"
 )
 
@@ -25,13 +26,17 @@ func (db *DB) CreateOrUpdateUser(ctx context.Context, user *models.User) error {
 	if lastActivity.IsZero() {
 		lastActivity = time.Now()
 	}
+	phone, err := db.fields.Encrypt(user.Phone)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt phone: %w", err)
+	}
 	now := time.Now()
-	_, err := db.ExecContext(ctx, query,
+	_, err = db.ExecContext(ctx, query,
 		user.TelegramID,
 		user.Username,
 		user.FirstName,
 		user.LastName,
-		user.Phone,
+		phone,
 		user.IsManager,
 		user.IsBlacklisted,
 		user.LanguageCode,
@@ -45,37 +50,43 @@ func (db *DB) CreateOrUpdateUser(ctx context.Context, user *models.User) error {
 	return nil
 }
 
-func (db *DB) GetUserByTelegramID(ctx context.Context, telegramID int64) (*models.User, error) {
-	query := `SELECT id, telegram_id, username, first_name, last_name, 
-	                 phone, is_manager, is_blacklisted, language_code, 
-					 last_activity, created_at, updated_at 
-              FROM users WHERE telegram_id = ?`
-	return db.queryUser(ctx, query, telegramID)
-}
+// userColumns — колонки users в порядке scanUser.
+const userColumns = `id, telegram_id, username, first_name, last_name,
+	phone, is_manager, is_blacklisted, language_code,
+	last_activity, created_at, updated_at,
+	consent_given, consent_given_at, consent_revoked, consent_revoked_at, consent_version`
 
-func (db *DB) GetUserByID(ctx context.Context, id int64) (*models.User, error) {
-	query := `SELECT id, telegram_id, username, first_name, last_name, 
-	                 phone, is_manager, is_blacklisted, language_code, 
-					 last_activity, created_at, updated_at 
-              FROM users WHERE id = ?`
-	return db.queryUser(ctx, query, id)
-}
-
-func (db *DB) queryUser(ctx context.Context, query string, args ...interface{}) (*models.User, error) {
-	var user models.User
-	err := db.QueryRowContext(ctx, query, args...).Scan(
-		&user.ID, &user.TelegramID, &user.Username, &user.FirstName, &user.LastName, &user.Phone,
-		&user.IsManager, &user.IsBlacklisted, &user.LanguageCode, &user.LastActivity, &user.CreatedAt, &user.UpdatedAt,
+func scanUser(row rowScanner, c *fieldcrypt.Cipher) (*models.User, error) {
+	u := &models.User{}
+	err := row.Scan(
+		&u.ID, &u.TelegramID, &u.Username, &u.FirstName, &u.LastName, &u.Phone,
+		&u.IsManager, &u.IsBlacklisted, &u.LanguageCode, &u.LastActivity, &u.CreatedAt, &u.UpdatedAt,
+		&u.ConsentGiven, &u.ConsentGivenAt, &u.ConsentRevoked, &u.ConsentRevokedAt, &u.ConsentVersion,
 	)
 	if err != nil {
 		return nil, err
 	}
-	return &user, nil
+	if u.Phone, err = c.Decrypt(u.Phone); err != nil {
+		return nil, fmt.Errorf("user %d: failed to decrypt phone: %w", u.ID, err)
+	}
+	return u, nil
+}
+
+func (db *DB) GetUserByTelegramID(ctx context.Context, telegramID int64) (*models.User, error) {
+	return scanUser(db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE telegram_id = ?`, telegramID), db.fields)
+}
+
+func (db *DB) GetUserByID(ctx context.Context, id int64) (*models.User, error) {
+	return scanUser(db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE id = ?`, id), db.fields)
 }
 
 func (db *DB) UpdateUserPhone(ctx context.Context, telegramID int64, phone string) error {
+	sealed, err := db.fields.Encrypt(phone)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt phone: %w", err)
+	}
 	query := `UPDATE users SET phone = ?, updated_at = ? WHERE telegram_id = ?`
-	_, err := db.ExecContext(ctx, query, phone, time.Now(), telegramID)
+	_, err = db.ExecContext(ctx, query, sealed, time.Now(), telegramID)
 	return err
 }
 
@@ -87,80 +98,44 @@ func (db *DB) UpdateUserActivity(ctx context.Context, telegramID int64) error {
 }
 
 func (db *DB) GetAllUsers(ctx context.Context) ([]*models.User, error) {
-	query := `SELECT id, telegram_id, username, first_name, last_name, 
-		phone, is_manager, is_blacklisted, language_code, 
-		last_activity, created_at, updated_at
-              FROM users ORDER BY last_activity DESC`
-	rows, err := db.QueryContext(ctx, query)
+	users, err := db.queryUsers(ctx, `SELECT `+userColumns+` FROM users ORDER BY last_activity DESC`)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all users: %w", err)
 	}
-	defer rows.Close()
-
-	var users []*models.User
-	for rows.Next() {
-		u := &models.User{}
-		err := rows.Scan(
-			&u.ID, &u.TelegramID, &u.Username, &u.FirstName, &u.LastName, &u.Phone,
-			&u.IsManager, &u.IsBlacklisted, &u.LanguageCode, &u.LastActivity, &u.CreatedAt, &u.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan user: %w", err)
-		}
-		users = append(users, u)
-	}
 	return users, nil
 }
 
 func (db *DB) GetUsersByManagerStatus(ctx context.Context, isManager bool) ([]*models.User, error) {
-	query := `SELECT id, telegram_id, username, first_name, last_name, 
-		phone, is_manager, is_blacklisted, language_code, 
-		last_activity, created_at, updated_at
-              FROM users WHERE is_manager = ? ORDER BY last_activity DESC`
-	rows, err := db.QueryContext(ctx, query, isManager)
+	users, err := db.queryUsers(ctx, `SELECT `+userColumns+` FROM users WHERE is_manager = ? ORDER BY last_activity DESC`, isManager)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get users by manager status: %w", err)
 	}
-	defer rows.Close()
-
-	var users []*models.User
-	for rows.Next() {
-		u := &models.User{}
-		err := rows.Scan(
-			&u.ID, &u.TelegramID, &u.Username, &u.FirstName, &u.LastName, &u.Phone,
-			&u.IsManager, &u.IsBlacklisted, &u.LanguageCode, &u.LastActivity, &u.CreatedAt, &u.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan user: %w", err)
-		}
-		users = append(users, u)
-	}
 	return users, nil
 }
 
 func (db *DB) GetActiveUsers(ctx context.Context, days int) ([]*models.User, error) {
 	since := time.Now().AddDate(0, 0, -days)
-	query := `SELECT id, telegram_id, username, first_name, last_name, 
-		phone, is_manager, is_blacklisted, language_code, 
-		last_activity, created_at, updated_at
-              FROM users WHERE last_activity >= ? ORDER BY last_activity DESC`
-	rows, err := db.QueryContext(ctx, query, since)
+	users, err := db.queryUsers(ctx, `SELECT `+userColumns+` FROM users WHERE last_activity >= ? ORDER BY last_activity DESC`, since)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get active users: %w", err)
 	}
+	return users, nil
+}
+
+func (db *DB) queryUsers(ctx context.Context, query string, args ...interface{}) ([]*models.User, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
 	defer rows.Close()
 
 	var users []*models.User
 	for rows.Next() {
-		u := &models.User{}
-		err := rows.Scan(
-			&u.ID, &u.TelegramID, &u.Username, &u.FirstName, &u.LastName, &u.Phone,
-			&u.IsManager, &u.IsBlacklisted, &u.LanguageCode, &u.LastActivity, &u.CreatedAt, &u.UpdatedAt,
-		)
+		u, err := scanUser(rows, db.fields)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
 		}
 		users = append(users, u)
 	}
-	return users, nil
+	return users, rows.Err()
 }
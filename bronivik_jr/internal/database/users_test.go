@@ -159,3 +159,31 @@ func TestGetActiveUsers(t *testing.T) {
 	assert.Len(t, active, 1)
 	assert.Equal(t, int64(555), active[0].TelegramID)
 }
+
+func TestUserLanguage(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	require.NoError(t, db.CreateOrUpdateUser(ctx, &models.User{
+		TelegramID: 777, FirstName: "Lang", LanguageCode: "en", LastActivity: time.Now(),
+	}))
+
+	chosen, telegram, err := db.GetUserLanguage(ctx, 777)
+	require.NoError(t, err)
+	assert.Empty(t, chosen)
+	assert.Equal(t, "en", telegram)
+
+	require.NoError(t, db.SetUserLanguage(ctx, 777, "en"))
+	require.NoError(t, db.UpsertUserSettings(ctx, 777, false, 12))
+	require.NoError(t, db.SetUserLanguage(ctx, 777, "ru"))
+
+	settings, err := db.GetUserSettings(ctx, 777)
+	require.NoError(t, err)
+	assert.Equal(t, "ru", settings.Language)
+	assert.False(t, settings.RemindersEnabled, "смена языка не сбрасывает другие настройки")
+
+	chosen, _, err = db.GetUserLanguage(ctx, 777)
+	require.NoError(t, err)
+	assert.Equal(t, "ru", chosen)
+}
@@ -0,0 +1,295 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"bronivik/internal/fieldcrypt"
+	"bronivik/internal/models"
+)
+
+const waitlistColumns = `id, user_id, chat_id, user_name, user_nickname, phone, item_id, item_name,
+	CAST(date(date) AS TEXT), status, offer_expires_at, booking_id, created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWaitlistEntry(row rowScanner, c *fieldcrypt.Cipher) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	var dateStr string
+	var nickname sql.NullString
+	var expiresAt sql.NullTime
+	var bookingID sql.NullInt64
+
+	err := row.Scan(
+		&entry.ID, &entry.UserID, &entry.ChatID, &entry.UserName, &nickname, &entry.Phone,
+		&entry.ItemID, &entry.ItemName, &dateStr, &entry.Status, &expiresAt, &bookingID,
+		&entry.CreatedAt, &entry.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.Date, err = time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse waitlist date %s: %w", dateStr, err)
+	}
+	entry.UserNickname = nickname.String
+	if expiresAt.Valid {
+		t := expiresAt.Time
+		entry.OfferExpiresAt = &t
+	}
+	if bookingID.Valid {
+		id := bookingID.Int64
+		entry.BookingID = &id
+	}
+	if err := openPerson(c, &entry.UserName, &entry.Phone); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// AddWaitlistEntry ставит пользователя в лист ожидания на позицию и дату.
+// Повторная запись при уже активной (waiting/offered) возвращает ErrAlreadyInWaitlist.
+func (db *DB) AddWaitlistEntry(ctx context.Context, entry *models.WaitlistEntry) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var existing int
+	queryExisting := `SELECT COUNT(*) FROM waitlist WHERE user_id = ? AND item_id = ? AND date = ? AND status IN (?, ?)`
+	err = tx.QueryRowContext(ctx, queryExisting, entry.UserID, entry.ItemID, entry.Date.Format("2006-01-02"),
+		models.WaitlistStatusWaiting, models.WaitlistStatusOffered).Scan(&existing)
+	if err != nil {
+		return fmt.Errorf("failed to check waitlist: %w", err)
+	}
+	if existing > 0 {
+		return ErrAlreadyInWaitlist
+	}
+
+	person, err := sealPerson(db.fields, entry.UserName, entry.Phone)
+	if err != nil {
+		return err
+	}
+	queryInsert := `INSERT INTO waitlist (
+				user_id, chat_id, user_name, user_nickname, phone, item_id, item_name,
+				date, status, created_at, updated_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	now := time.Now()
+	id, err := tx.insertID(ctx, queryInsert,
+		entry.UserID,
+		entry.ChatID,
+		person.name,
+		entry.UserNickname,
+		person.phone,
+		entry.ItemID,
+		entry.ItemName,
+		entry.Date.Format("2006-01-02"),
+		models.WaitlistStatusWaiting,
+		now,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create waitlist entry: %w", err)
+	}
+	entry.ID = id
+	entry.Status = models.WaitlistStatusWaiting
+	entry.CreatedAt = now
+	entry.UpdatedAt = now
+
+	return tx.Commit()
+}
+
+// GetWaitlistEntry возвращает запись листа ожидания по ID.
+func (db *DB) GetWaitlistEntry(ctx context.Context, id int64) (*models.WaitlistEntry, error) {
+	query := `SELECT ` + waitlistColumns + ` FROM waitlist WHERE id = ?`
+	entry, err := scanWaitlistEntry(db.QueryRowContext(ctx, query, id), db.fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get waitlist entry: %w", err)
+	}
+	return entry, nil
+}
+
+// GetNextWaitlistEntry возвращает первую ожидающую запись в очереди или nil, если очередь пуста.
+func (db *DB) GetNextWaitlistEntry(ctx context.Context, itemID int64, date time.Time) (*models.WaitlistEntry, error) {
+	query := `SELECT ` + waitlistColumns + ` FROM waitlist
+              WHERE item_id = ? AND date = ? AND status = ?
+              ORDER BY created_at ASC, id ASC LIMIT 1`
+	entry, err := scanWaitlistEntry(db.QueryRowContext(ctx, query, itemID, date.Format("2006-01-02"), models.WaitlistStatusWaiting), db.fields)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next waitlist entry: %w", err)
+	}
+	return entry, nil
+}
+
+// MarkWaitlistOffered переводит ожидающую запись в статус предложения со сроком принятия.
+// Предложение держит единицу до принятия или истечения срока; если свободной
+// единицы на дату уже нет, возвращается ErrNotAvailable.
+func (db *DB) MarkWaitlistOffered(ctx context.Context, id int64, expiresAt time.Time) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var itemID int64
+	var dateStr string
+	err = tx.QueryRowContext(ctx, `SELECT item_id, CAST(date(date) AS TEXT) FROM waitlist WHERE id = ? AND status = ?`,
+		id, models.WaitlistStatusWaiting).Scan(&itemID, &dateStr)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrConcurrentModification
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get waitlist entry: %w", err)
+	}
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return fmt.Errorf("failed to parse waitlist date %s: %w", dateStr, err)
+	}
+
+	db.mu.RLock()
+	item, ok := db.itemsCache[itemID]
+	db.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("item not found in cache: %d", itemID)
+	}
+	if err := tx.lockItem(ctx, itemID); err != nil {
+		return fmt.Errorf("failed to lock item: %w", err)
+	}
+	busy, err := unavailableDates(ctx, tx, itemID, int(item.TotalQuantity), []time.Time{date}, 0)
+	if err != nil {
+		return fmt.Errorf("failed to check availability in tx: %w", err)
+	}
+	if len(busy) > 0 {
+		return ErrNotAvailable
+	}
+
+	query := `UPDATE waitlist SET status = ?, offer_expires_at = ?, updated_at = ? WHERE id = ? AND status = ?`
+	result, err := tx.ExecContext(ctx, query, models.WaitlistStatusOffered, expiresAt, time.Now(), id, models.WaitlistStatusWaiting)
+	if err != nil {
+		return fmt.Errorf("failed to mark waitlist entry offered: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrConcurrentModification
+	}
+	return tx.Commit()
+}
+
+// UpdateWaitlistStatus меняет статус записи, если она находится в ожидаемом статусе.
+func (db *DB) UpdateWaitlistStatus(ctx context.Context, id int64, fromStatus, toStatus string) error {
+	query := `UPDATE waitlist SET status = ?, offer_expires_at = NULL, updated_at = ? WHERE id = ? AND status = ?`
+	result, err := db.ExecContext(ctx, query, toStatus, time.Now(), id, fromStatus)
+	if err != nil {
+		return fmt.Errorf("failed to update waitlist status: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrConcurrentModification
+	}
+	return nil
+}
+
+// CreateWaitlistBooking принимает предложение entryID и создаёт по нему заявку
+// в одной транзакции: удерживаемая предложением единица переходит в бронирование.
+// Истёкшее или уже закрытое предложение возвращает ErrWaitlistOfferExpired.
+func (db *DB) CreateWaitlistBooking(ctx context.Context, entryID int64, booking *models.Booking) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if booking.EndTime != nil && booking.EndTime.Before(booking.Date) {
+		return ErrInvalidDateRange
+	}
+
+	db.mu.RLock()
+	item, ok := db.itemsCache[booking.ItemID]
+	db.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("item not found in cache: %d", booking.ItemID)
+	}
+	if err := tx.lockItem(ctx, booking.ItemID); err != nil {
+		return fmt.Errorf("failed to lock item: %w", err)
+	}
+
+	// Снимаем удержание: дальше единица проверяется как обычное бронирование
+	now := time.Now()
+	queryAccept := `UPDATE waitlist SET status = ?, offer_expires_at = NULL, updated_at = ?
+	                WHERE id = ? AND status = ? AND offer_expires_at > ?`
+	result, err := tx.ExecContext(ctx, queryAccept, models.WaitlistStatusAccepted, now,
+		entryID, models.WaitlistStatusOffered, now)
+	if err != nil {
+		return fmt.Errorf("failed to accept waitlist offer: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrWaitlistOfferExpired
+	}
+
+	busy, err := unavailableDates(ctx, tx, booking.ItemID, int(item.TotalQuantity), booking.CoveredDates(), 0)
+	if err != nil {
+		return fmt.Errorf("failed to check availability in tx: %w", err)
+	}
+	if len(busy) > 0 {
+		return &UnavailableDatesError{Dates: busy}
+	}
+
+	if err := insertBooking(ctx, tx, booking, nil); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE waitlist SET booking_id = ? WHERE id = ?`, booking.ID, entryID); err != nil {
+		return fmt.Errorf("failed to link waitlist booking: %w", err)
+	}
+	return tx.Commit()
+}
+
+// GetExpiredWaitlistOffers возвращает предложения, срок принятия которых истёк к моменту now.
+func (db *DB) GetExpiredWaitlistOffers(ctx context.Context, now time.Time) ([]*models.WaitlistEntry, error) {
+	query := `SELECT ` + waitlistColumns + ` FROM waitlist
+              WHERE status = ? AND offer_expires_at IS NOT NULL AND offer_expires_at <= ?
+              ORDER BY offer_expires_at ASC`
+	rows, err := db.QueryContext(ctx, query, models.WaitlistStatusOffered, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired waitlist offers: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.WaitlistEntry
+	for rows.Next() {
+		entry, err := scanWaitlistEntry(rows, db.fields)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan waitlist entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// ExpirePastWaitlistEntries закрывает записи на даты раньше before, которые больше не могут быть предложены.
+func (db *DB) ExpirePastWaitlistEntries(ctx context.Context, before time.Time) (int64, error) {
+	query := `UPDATE waitlist SET status = ?, offer_expires_at = NULL, updated_at = ?
+              WHERE date < ? AND status IN (?, ?)`
+	result, err := db.ExecContext(ctx, query, models.WaitlistStatusExpired, time.Now(), before.Format("2006-01-02"),
+		models.WaitlistStatusWaiting, models.WaitlistStatusOffered)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire past waitlist entries: %w", err)
+	}
+	return result.RowsAffected()
+}
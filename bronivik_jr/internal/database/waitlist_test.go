@@ -0,0 +1,183 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bronivik/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupWaitlistItem создаёт аппарат на две единицы, на который встают в очередь тесты.
+func setupWaitlistItem(t *testing.T, db *DB) {
+	t.Helper()
+	item := &models.Item{Name: "Item", TotalQuantity: 2, IsActive: true}
+	require.NoError(t, db.CreateItem(context.Background(), item))
+	require.Equal(t, int64(1), item.ID)
+}
+
+func TestWaitlistQueue(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	setupWaitlistItem(t, db)
+
+	ctx := context.Background()
+	date := time.Now().AddDate(0, 0, 3).Truncate(24 * time.Hour)
+
+	first := &models.WaitlistEntry{
+		UserID: 1, ChatID: 1, UserName: "User 1", Phone: "123",
+		ItemID: 1, ItemName: "Item", Date: date,
+	}
+	second := &models.WaitlistEntry{
+		UserID: 2, ChatID: 2, UserName: "User 2", Phone: "456",
+		ItemID: 1, ItemName: "Item", Date: date,
+	}
+	require.NoError(t, db.AddWaitlistEntry(ctx, first))
+	require.NoError(t, db.AddWaitlistEntry(ctx, second))
+	assert.Equal(t, models.WaitlistStatusWaiting, first.Status)
+
+	// Повторная запись того же пользователя запрещена
+	dup := *first
+	assert.ErrorIs(t, db.AddWaitlistEntry(ctx, &dup), ErrAlreadyInWaitlist)
+
+	next, err := db.GetNextWaitlistEntry(ctx, 1, date)
+	require.NoError(t, err)
+	require.NotNil(t, next)
+	assert.Equal(t, first.ID, next.ID)
+	assert.Equal(t, date.Format("2006-01-02"), next.Date.Format("2006-01-02"))
+
+	expiresAt := time.Now().Add(-time.Minute)
+	require.NoError(t, db.MarkWaitlistOffered(ctx, first.ID, expiresAt))
+	assert.ErrorIs(t, db.MarkWaitlistOffered(ctx, first.ID, expiresAt), ErrConcurrentModification)
+
+	// Истёкшее предложение больше не держит единицу
+	held, err := heldOffersOn(ctx, db, 1, date)
+	require.NoError(t, err)
+	assert.Equal(t, 0, held)
+
+	next, err = db.GetNextWaitlistEntry(ctx, 1, date)
+	require.NoError(t, err)
+	require.NotNil(t, next)
+	assert.Equal(t, second.ID, next.ID)
+
+	expired, err := db.GetExpiredWaitlistOffers(ctx, time.Now())
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	assert.Equal(t, first.ID, expired[0].ID)
+	require.NotNil(t, expired[0].OfferExpiresAt)
+
+	require.NoError(t, db.UpdateWaitlistStatus(ctx, first.ID, models.WaitlistStatusOffered, models.WaitlistStatusExpired))
+
+	// После истечения пользователь может снова встать в очередь
+	again := *first
+	require.NoError(t, db.AddWaitlistEntry(ctx, &again))
+}
+
+func TestCreateWaitlistBooking(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	setupWaitlistItem(t, db)
+
+	ctx := context.Background()
+	date := time.Now().AddDate(0, 0, 1).Truncate(24 * time.Hour)
+
+	entry := &models.WaitlistEntry{
+		UserID: 1, ChatID: 1, UserName: "User", Phone: "123",
+		ItemID: 1, ItemName: "Item", Date: date,
+	}
+	require.NoError(t, db.AddWaitlistEntry(ctx, entry))
+	newBooking := func() *models.Booking {
+		return &models.Booking{
+			ItemID: 1, ItemName: "Item", Date: date, UserID: 1, UserName: "User", Phone: "123", Status: models.StatusPending,
+		}
+	}
+
+	// Нельзя принять предложение, которое не было сделано
+	assert.ErrorIs(t, db.CreateWaitlistBooking(ctx, entry.ID, newBooking()), ErrWaitlistOfferExpired)
+
+	require.NoError(t, db.MarkWaitlistOffered(ctx, entry.ID, time.Now().Add(time.Hour)))
+	booking := newBooking()
+	require.NoError(t, db.CreateWaitlistBooking(ctx, entry.ID, booking))
+	require.NotZero(t, booking.ID)
+
+	got, err := db.GetWaitlistEntry(ctx, entry.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.WaitlistStatusAccepted, got.Status)
+	require.NotNil(t, got.BookingID)
+	assert.Equal(t, booking.ID, *got.BookingID)
+	assert.Nil(t, got.OfferExpiresAt)
+
+	stored, err := db.GetBooking(ctx, booking.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "User", stored.UserName)
+
+	// Повторное принятие не создаёт вторую заявку
+	assert.ErrorIs(t, db.CreateWaitlistBooking(ctx, entry.ID, newBooking()), ErrWaitlistOfferExpired)
+}
+
+func TestWaitlistOfferHoldsUnit(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	setupWaitlistItem(t, db)
+
+	ctx := context.Background()
+	date := time.Now().AddDate(0, 0, 1).Truncate(24 * time.Hour)
+	newBooking := func(userID int64) *models.Booking {
+		return &models.Booking{
+			ItemID: 1, ItemName: "Item", Date: date, UserID: userID, UserName: "User", Phone: "123", Status: models.StatusPending,
+		}
+	}
+
+	// Из двух единиц одна забронирована, вторую держит предложение
+	require.NoError(t, db.CreateBookingWithLock(ctx, newBooking(1)))
+	offered := &models.WaitlistEntry{UserID: 2, ChatID: 2, UserName: "User 2", Phone: "456", ItemID: 1, ItemName: "Item", Date: date}
+	waiting := &models.WaitlistEntry{UserID: 3, ChatID: 3, UserName: "User 3", Phone: "789", ItemID: 1, ItemName: "Item", Date: date}
+	require.NoError(t, db.AddWaitlistEntry(ctx, offered))
+	require.NoError(t, db.AddWaitlistEntry(ctx, waiting))
+	require.NoError(t, db.MarkWaitlistOffered(ctx, offered.ID, time.Now().Add(time.Hour)))
+
+	available, err := db.CheckAvailability(ctx, 1, date)
+	require.NoError(t, err)
+	assert.False(t, available)
+
+	availability, err := db.GetAvailabilityForPeriod(ctx, 1, date, 1)
+	require.NoError(t, err)
+	require.Len(t, availability, 1)
+	assert.Equal(t, int64(0), availability[0].Available)
+
+	assert.ErrorIs(t, db.CreateBookingWithLock(ctx, newBooking(4)), ErrNotAvailable)
+	assert.ErrorIs(t, db.MarkWaitlistOffered(ctx, waiting.ID, time.Now().Add(time.Hour)), ErrNotAvailable)
+
+	// Удержанная единица достаётся тому, кому её предложили
+	require.NoError(t, db.CreateWaitlistBooking(ctx, offered.ID, newBooking(2)))
+}
+
+func TestExpirePastWaitlistEntries(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	today := time.Now().Truncate(24 * time.Hour)
+
+	past := &models.WaitlistEntry{
+		UserID: 1, ChatID: 1, UserName: "User", Phone: "123",
+		ItemID: 1, ItemName: "Item", Date: today.AddDate(0, 0, -1),
+	}
+	future := &models.WaitlistEntry{
+		UserID: 1, ChatID: 1, UserName: "User", Phone: "123",
+		ItemID: 1, ItemName: "Item", Date: today.AddDate(0, 0, 1),
+	}
+	require.NoError(t, db.AddWaitlistEntry(ctx, past))
+	require.NoError(t, db.AddWaitlistEntry(ctx, future))
+
+	n, err := db.ExpirePastWaitlistEntries(ctx, today)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	got, err := db.GetWaitlistEntry(ctx, future.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.WaitlistStatusWaiting, got.Status)
+}
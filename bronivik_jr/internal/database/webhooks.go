@@ -0,0 +1,233 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"bronivik/internal/events"
+	"bronivik/internal/models"
+)
+
+func (db *DB) CreateWebhookSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	now := time.Now()
+	id, err := db.insertID(ctx, `
+		INSERT INTO webhook_subscriptions (name, url, event_types, secret, is_active, created_at, updated_at)
+		VALUES (?, ?, ?, ?, TRUE, ?, ?)`,
+		sub.Name, sub.URL, strings.Join(sub.EventTypes, ","), sub.Secret, now, now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	sub.ID = id
+	sub.IsActive = true
+	sub.CreatedAt = now
+	sub.UpdatedAt = now
+	return nil
+}
+
+// GetWebhookSubscriptions возвращает подписки без секретов; activeOnly оставляет только активные.
+func (db *DB) GetWebhookSubscriptions(ctx context.Context, activeOnly bool) ([]*models.WebhookSubscription, error) {
+	query := `SELECT id, name, url, event_types, is_active, created_at, updated_at FROM webhook_subscriptions`
+	if activeOnly {
+		query += ` WHERE is_active = TRUE`
+	}
+	rows, err := db.QueryContext(ctx, query+` ORDER BY id ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []*models.WebhookSubscription
+	for rows.Next() {
+		sub := &models.WebhookSubscription{}
+		var eventTypes string
+		if err := rows.Scan(&sub.ID, &sub.Name, &sub.URL, &eventTypes, &sub.IsActive, &sub.CreatedAt, &sub.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook subscription: %w", err)
+		}
+		sub.EventTypes = strings.Split(eventTypes, ",")
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+// DeactivateWebhookSubscription отключает подписку; ее неотправленные доставки переходят в dead.
+func (db *DB) DeactivateWebhookSubscription(ctx context.Context, id int64) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx,
+		`UPDATE webhook_subscriptions SET is_active = FALSE, updated_at = ? WHERE id = ? AND is_active = TRUE`, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to deactivate webhook subscription: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE webhook_deliveries SET status = ?, last_error = ?, next_attempt_at = NULL, updated_at = ?
+		WHERE subscription_id = ? AND status = ?`,
+		models.WebhookDeliveryDead, "subscription disabled", now, id, models.WebhookDeliveryPending)
+	if err != nil {
+		return fmt.Errorf("failed to cancel webhook deliveries: %w", err)
+	}
+	return tx.Commit()
+}
+
+// EnqueueWebhookDeliveries создает доставки события для всех подходящих активных подписок.
+// Повторная обработка того же события (ретрай диспетчера, replay) дублей не создает.
+func (db *DB) EnqueueWebhookDeliveries(ctx context.Context, event *events.Event) (int, error) {
+	subs, err := db.GetWebhookSubscriptions(ctx, true)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	created := 0
+	for _, sub := range subs {
+		if !sub.Matches(event.Type) {
+			continue
+		}
+		result, err := db.ExecContext(ctx, `
+			INSERT INTO webhook_deliveries (
+				subscription_id, event_id, event_type, payload, status, next_attempt_at, created_at, updated_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(subscription_id, event_id) DO NOTHING`,
+			sub.ID, event.ID, event.Type, string(event.Payload), models.WebhookDeliveryPending, now, now, now,
+		)
+		if err != nil {
+			return created, fmt.Errorf("failed to enqueue webhook delivery: %w", err)
+		}
+		if rows, _ := result.RowsAffected(); rows > 0 {
+			created++
+		}
+	}
+	return created, nil
+}
+
+const webhookDeliveryColumns = `d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+	d.last_error, d.response_code, d.next_attempt_at, d.delivered_at, d.created_at, d.updated_at, s.url, s.secret`
+
+func (db *DB) queryWebhookDeliveries(ctx context.Context, where string, args ...interface{}) ([]*models.WebhookDelivery, error) {
+	rows, err := db.QueryContext(ctx, `SELECT `+webhookDeliveryColumns+`
+		FROM webhook_deliveries d JOIN webhook_subscriptions s ON s.id = d.subscription_id `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*models.WebhookDelivery
+	for rows.Next() {
+		d := &models.WebhookDelivery{}
+		var nextAttemptAt, deliveredAt sql.NullTime
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+			&d.LastError, &d.ResponseCode, &nextAttemptAt, &deliveredAt, &d.CreatedAt, &d.UpdatedAt, &d.URL, &d.Secret); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		if nextAttemptAt.Valid {
+			d.NextAttemptAt = &nextAttemptAt.Time
+		}
+		if deliveredAt.Valid {
+			d.DeliveredAt = &deliveredAt.Time
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// GetDueWebhookDeliveries возвращает доставки, время отправки которых наступило.
+func (db *DB) GetDueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]*models.WebhookDelivery, error) {
+	return db.queryWebhookDeliveries(ctx, `
+		WHERE d.status = ? AND d.next_attempt_at <= ? AND s.is_active = TRUE
+		ORDER BY d.next_attempt_at ASC, d.id ASC LIMIT ?`,
+		models.WebhookDeliveryPending, now, limit)
+}
+
+// GetWebhookDeliveries возвращает журнал доставок, новые сверху; пустой status — все статусы.
+func (db *DB) GetWebhookDeliveries(ctx context.Context, status string, limit int) ([]*models.WebhookDelivery, error) {
+	if status == "" {
+		return db.queryWebhookDeliveries(ctx, `ORDER BY d.id DESC LIMIT ?`, limit)
+	}
+	return db.queryWebhookDeliveries(ctx, `WHERE d.status = ? ORDER BY d.id DESC LIMIT ?`, status, limit)
+}
+
+func (db *DB) MarkWebhookDelivered(ctx context.Context, id int64, responseCode int) error {
+	now := time.Now()
+	_, err := db.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = attempts + 1, response_code = ?, last_error = '',
+		    next_attempt_at = NULL, delivered_at = ?, updated_at = ?
+		WHERE id = ?`,
+		models.WebhookDeliveryDelivered, responseCode, now, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark webhook delivered: %w", err)
+	}
+	return nil
+}
+
+// MarkWebhookFailed записывает неудачную попытку; nil nextAttemptAt переводит доставку в dead.
+func (db *DB) MarkWebhookFailed(ctx context.Context, id int64, responseCode int, lastErr string, nextAttemptAt *time.Time) error {
+	status := models.WebhookDeliveryPending
+	if nextAttemptAt == nil {
+		status = models.WebhookDeliveryDead
+	}
+	_, err := db.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = attempts + 1, response_code = ?, last_error = ?, next_attempt_at = ?, updated_at = ?
+		WHERE id = ?`,
+		status, responseCode, lastErr, nextAttemptAt, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to mark webhook failed: %w", err)
+	}
+	return nil
+}
+
+// RedeliverWebhook возвращает доставку из dead в очередь со сбросом счетчика попыток.
+// Для несуществующей доставки, другого статуса или отключенной подписки возвращает sql.ErrNoRows.
+func (db *DB) RedeliverWebhook(ctx context.Context, id int64) error {
+	now := time.Now()
+	result, err := db.ExecContext(ctx, `
+		UPDATE webhook_deliveries SET status = ?, attempts = 0, next_attempt_at = ?, updated_at = ?
+		WHERE id = ? AND status = ?
+		  AND subscription_id IN (SELECT id FROM webhook_subscriptions WHERE is_active = TRUE)`,
+		models.WebhookDeliveryPending, now, now, id, models.WebhookDeliveryDead)
+	if err != nil {
+		return fmt.Errorf("failed to redeliver webhook: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteDeliveredWebhooks удаляет доставленные вебхуки старше before: в payload хранится копия заявки.
+func (db *DB) DeleteDeliveredWebhooks(ctx context.Context, before time.Time) (int64, error) {
+	result, err := db.ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE status = ? AND delivered_at < ?`,
+		models.WebhookDeliveryDelivered, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete delivered webhooks: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// RedeliverDeadWebhooks возвращает в очередь все dead-доставки активных подписок.
+func (db *DB) RedeliverDeadWebhooks(ctx context.Context) (int64, error) {
+	now := time.Now()
+	result, err := db.ExecContext(ctx, `
+		UPDATE webhook_deliveries SET status = ?, attempts = 0, next_attempt_at = ?, updated_at = ?
+		WHERE status = ? AND subscription_id IN (SELECT id FROM webhook_subscriptions WHERE is_active = TRUE)`,
+		models.WebhookDeliveryPending, now, now, models.WebhookDeliveryDead)
+	if err != nil {
+		return 0, fmt.Errorf("failed to redeliver webhooks: %w", err)
+	}
+	return result.RowsAffected()
+}
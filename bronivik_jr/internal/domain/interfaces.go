@@ -13,9 +13,11 @@ type Repository interface {
 	GetBooking(ctx context.Context, id int64) (*models.Booking, error)
 	CreateBooking(ctx context.Context, booking *models.Booking) error
 	CreateBookingWithLock(ctx context.Context, booking *models.Booking) error
+	CreateWaitlistBooking(ctx context.Context, entryID int64, booking *models.Booking) error
 	UpdateBookingStatus(ctx context.Context, id int64, status string) error
 	UpdateBookingStatusWithVersion(ctx context.Context, id int64, version int64, status string) error
 	GetBookingsByDateRange(ctx context.Context, start, end time.Time) ([]*models.Booking, error)
+	ListBookings(ctx context.Context, filter *models.BookingFilter) ([]*models.Booking, error)
 	CheckAvailability(ctx context.Context, itemID int64, date time.Time) (bool, error)
 	GetAvailabilityForPeriod(ctx context.Context, itemID int64, startDate time.Time, days int) ([]*models.Availability, error)
 	GetActiveItems(ctx context.Context) ([]*models.Item, error)
@@ -31,14 +33,30 @@ type Repository interface {
 	CreateOrUpdateUser(ctx context.Context, user *models.User) error
 	UpdateUserActivity(ctx context.Context, telegramID int64) error
 	UpdateUserPhone(ctx context.Context, telegramID int64, phone string) error
+	GetUserLanguage(ctx context.Context, telegramID int64) (chosen, telegram string, err error)
+	SetUserLanguage(ctx context.Context, telegramID int64, lang string) error
+	GiveConsent(ctx context.Context, telegramID int64, version string) error
+	RevokeConsent(ctx context.Context, telegramID int64) error
+	ExportUserData(ctx context.Context, telegramID int64) (*models.UserDataExport, error)
+	EraseUserData(ctx context.Context, telegramID int64) (*models.ErasureResult, error)
 	GetDailyBookings(ctx context.Context, start, end time.Time) (map[string][]*models.Booking, error)
 	GetBookedCount(ctx context.Context, itemID int64, date time.Time) (int, error)
-	GetBookingWithAvailability(ctx context.Context, id int64, newItemID int64) (*models.Booking, bool, error)
 	UpdateBookingItemAndStatusWithVersion(ctx context.Context, id int64, version int64, itemID int64, itemName string, status string) error
+	UpdateBookingDatesWithVersion(ctx context.Context, id int64, version int64, date time.Time, endTime *time.Time, changedBy int64) error
+	GetBookingHistory(ctx context.Context, bookingID int64) ([]*models.BookingHistoryEntry, error)
 	SetItems(items []*models.Item)
 	GetActiveUsers(ctx context.Context, days int) ([]*models.User, error)
 	GetUsersByManagerStatus(ctx context.Context, isManager bool) ([]*models.User, error)
 	GetUserBookings(ctx context.Context, userID int64) ([]*models.Booking, error)
+	CreateBookingSeriesWithLock(
+		ctx context.Context,
+		series *models.BookingSeries,
+		bookings []*models.Booking,
+	) ([]*models.Booking, []models.SeriesConflict, error)
+	GetBookingSeries(ctx context.Context, id int64) (*models.BookingSeries, error)
+	GetSeriesBookings(ctx context.Context, seriesID int64) ([]*models.Booking, error)
+	UpdateBookingSeriesStatus(ctx context.Context, id int64, status string) error
+	UpdateBookingSeriesItem(ctx context.Context, id int64, itemID int64, itemName string) error
 }
 
 type StateRepository interface {
@@ -69,6 +87,7 @@ type TelegramSender interface {
 
 type SheetsWriter interface {
 	UpdateUsersSheet(ctx context.Context, users []*models.User) error
+	DeleteUserRows(ctx context.Context, telegramID int64) error
 	UpdateBookingsSheet(ctx context.Context, bookings []*models.Booking) error
 	ReplaceBookingsSheet(ctx context.Context, bookings []*models.Booking) error
 	AppendBooking(ctx context.Context, booking *models.Booking) error
@@ -83,7 +102,7 @@ type SheetsWriter interface {
 }
 
 type SyncWorker interface {
-	EnqueueTask(ctx context.Context, taskType string, bookingID int64, booking *models.Booking, status string) error
+	EnqueueTask(ctx context.Context, taskType string, bookingID int64, status string) error
 	EnqueueSyncSchedule(ctx context.Context, startDate, endDate time.Time) error
 }
 
@@ -104,18 +123,26 @@ type TelegramService interface {
 type BookingService interface {
 	ValidateBookingDate(date time.Time) error
 	CreateBooking(ctx context.Context, booking *models.Booking) error
+	CreateWaitlistBooking(ctx context.Context, entryID int64, booking *models.Booking) error
 	ConfirmBooking(ctx context.Context, bookingID int64, version int64, managerID int64) error
 	RejectBooking(ctx context.Context, bookingID int64, version int64, managerID int64) error
 	CompleteBooking(ctx context.Context, bookingID int64, version int64, managerID int64) error
 	ReopenBooking(ctx context.Context, bookingID int64, version int64, managerID int64) error
 	ChangeBookingItem(ctx context.Context, bookingID int64, version int64, newItemID int64, managerID int64) error
-	RescheduleBooking(ctx context.Context, bookingID int64, managerID int64) error
+	RescheduleBooking(ctx context.Context, bookingID int64, version int64, date time.Time, endTime *time.Time, managerID int64) error
+	GetBookingHistory(ctx context.Context, bookingID int64) ([]*models.BookingHistoryEntry, error)
 	GetAvailability(ctx context.Context, itemID int64, startDate time.Time, days int) ([]*models.Availability, error)
 	CheckAvailability(ctx context.Context, itemID int64, date time.Time) (bool, error)
 	GetBookedCount(ctx context.Context, itemID int64, date time.Time) (int, error)
 	GetBookingsByDateRange(ctx context.Context, start, end time.Time) ([]*models.Booking, error)
+	ListBookings(ctx context.Context, filter *models.BookingFilter) ([]*models.Booking, error)
 	GetBooking(ctx context.Context, id int64) (*models.Booking, error)
 	GetDailyBookings(ctx context.Context, start, end time.Time) (map[string][]*models.Booking, error)
+	CreateBookingSeries(ctx context.Context, template *models.Booking, rule models.RecurrenceRule) (*models.SeriesResult, error)
+	GetBookingSeries(ctx context.Context, seriesID int64) (*models.BookingSeries, []*models.Booking, error)
+	ConfirmSeries(ctx context.Context, seriesID int64, managerID int64) (*models.SeriesResult, error)
+	CancelSeries(ctx context.Context, seriesID int64, managerID int64) (*models.SeriesResult, error)
+	ChangeSeriesItem(ctx context.Context, seriesID int64, newItemID int64, managerID int64) (*models.SeriesResult, error)
 }
 
 type UserService interface {
@@ -129,6 +156,13 @@ type UserService interface {
 	GetManagers(ctx context.Context) ([]*models.User, error)
 	GetUserBookings(ctx context.Context, userID int64) ([]*models.Booking, error)
 	GetUserByID(ctx context.Context, id int64) (*models.User, error)
+	GetUserByTelegramID(ctx context.Context, telegramID int64) (*models.User, error)
+	GetUserLanguage(ctx context.Context, telegramID int64) (chosen, telegram string, err error)
+	SetUserLanguage(ctx context.Context, telegramID int64, lang string) error
+	GiveConsent(ctx context.Context, telegramID int64, version string) error
+	RevokeConsent(ctx context.Context, telegramID int64) error
+	ExportUserData(ctx context.Context, telegramID int64) (*models.UserDataExport, error)
+	EraseUserData(ctx context.Context, telegramID int64) (*models.ErasureResult, error)
 }
 
 type ItemService interface {
@@ -140,3 +174,70 @@ type ItemService interface {
 	DeactivateItem(ctx context.Context, id int64) error
 	ReorderItem(ctx context.Context, id int64, newOrder int64) error
 }
+
+type WaitlistRepository interface {
+	AddWaitlistEntry(ctx context.Context, entry *models.WaitlistEntry) error
+	GetWaitlistEntry(ctx context.Context, id int64) (*models.WaitlistEntry, error)
+	GetNextWaitlistEntry(ctx context.Context, itemID int64, date time.Time) (*models.WaitlistEntry, error)
+	MarkWaitlistOffered(ctx context.Context, id int64, expiresAt time.Time) error
+	UpdateWaitlistStatus(ctx context.Context, id int64, fromStatus, toStatus string) error
+	GetExpiredWaitlistOffers(ctx context.Context, now time.Time) ([]*models.WaitlistEntry, error)
+	ExpirePastWaitlistEntries(ctx context.Context, before time.Time) (int64, error)
+}
+
+// WaitlistNotifier доставляет пользователю предложения из листа ожидания.
+type WaitlistNotifier interface {
+	NotifyWaitlistOffer(ctx context.Context, entry *models.WaitlistEntry) error
+	NotifyWaitlistOfferExpired(ctx context.Context, entry *models.WaitlistEntry) error
+}
+
+type WaitlistService interface {
+	JoinWaitlist(ctx context.Context, entry *models.WaitlistEntry) error
+	HandleSlotReleased(ctx context.Context, itemID int64, date time.Time) error
+	AcceptOffer(ctx context.Context, entryID int64, userID int64) (*models.Booking, error)
+	DeclineOffer(ctx context.Context, entryID int64, userID int64) error
+}
+
+type WebhookRepository interface {
+	CreateWebhookSubscription(ctx context.Context, sub *models.WebhookSubscription) error
+	GetWebhookSubscriptions(ctx context.Context, activeOnly bool) ([]*models.WebhookSubscription, error)
+	DeactivateWebhookSubscription(ctx context.Context, id int64) error
+	GetWebhookDeliveries(ctx context.Context, status string, limit int) ([]*models.WebhookDelivery, error)
+	RedeliverWebhook(ctx context.Context, id int64) error
+	RedeliverDeadWebhooks(ctx context.Context) (int64, error)
+}
+
+type WebhookService interface {
+	CreateSubscription(ctx context.Context, name, url string, eventTypes []string) (*models.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error)
+	DeactivateSubscription(ctx context.Context, id int64) error
+	ListDeliveries(ctx context.Context, status string, limit int) ([]*models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, deliveryID int64) error
+	RedeliverDead(ctx context.Context) (int64, error)
+}
+
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *models.APIKey) error
+	GetAPIKeys(ctx context.Context) ([]*models.APIKey, error)
+	GetUnrevokedAPIKeys(ctx context.Context) ([]*models.APIKey, error)
+	RotateAPIKey(ctx context.Context, id int64, next *models.APIKey, graceUntil time.Time) error
+	UpdateAPIKeyLimits(ctx context.Context, id int64, limits models.APIKeyLimits) error
+	UpdateAPIKeyClientCert(ctx context.Context, id int64, cn string) error
+	RevokeAPIKey(ctx context.Context, id int64) error
+	TouchAPIKeys(ctx context.Context, lastUsed map[int64]time.Time) error
+}
+
+type APIKeyService interface {
+	CreateKey(
+		ctx context.Context,
+		name string,
+		permissions []string,
+		expiresAt *time.Time,
+		limits models.APIKeyLimits,
+	) (*models.APIKey, error)
+	ListKeys(ctx context.Context) ([]*models.APIKey, error)
+	RotateKey(ctx context.Context, id int64, grace time.Duration) (*models.APIKey, error)
+	SetKeyLimits(ctx context.Context, id int64, limits models.APIKeyLimits) error
+	SetKeyClientCert(ctx context.Context, id int64, cn string) error
+	RevokeKey(ctx context.Context, id int64) error
+}
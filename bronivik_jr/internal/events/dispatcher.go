@@ -0,0 +1,188 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Store persists events written by the transactional outbox.
+type Store interface {
+	// GetPendingEvents returns unprocessed events that are due for delivery, oldest first.
+	GetPendingEvents(ctx context.Context, now time.Time, limit int) ([]*Event, error)
+	// GetEventsFrom returns events with ID >= fromID regardless of their state, oldest first.
+	GetEventsFrom(ctx context.Context, fromID int64, limit int) ([]*Event, error)
+	MarkEventProcessed(ctx context.Context, id int64) error
+	MarkEventFailed(ctx context.Context, id int64, lastErr string, nextAttemptAt time.Time) error
+	DeleteProcessedEvents(ctx context.Context, before time.Time) (int64, error)
+}
+
+// DispatcherConfig controls polling and retries of the outbox dispatcher.
+type DispatcherConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int           // after this many failures the event stays in the store for replay
+	RetryDelay   time.Duration // first retry delay, doubled on every attempt
+	MaxDelay     time.Duration
+	Retention    time.Duration // processed events older than this are deleted; 0 keeps them
+}
+
+func (c *DispatcherConfig) applyDefaults() {
+	if c.PollInterval <= 0 {
+		c.PollInterval = 2 * time.Second
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 100
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 10
+	}
+	if c.RetryDelay <= 0 {
+		c.RetryDelay = 5 * time.Second
+	}
+	if c.MaxDelay <= 0 {
+		c.MaxDelay = time.Hour
+	}
+}
+
+// Dispatcher delivers stored events to the bus subscribers with retries.
+// Delivery is at-least-once: a failed event is dispatched again to all
+// subscribers of its type, so handlers must be idempotent.
+type Dispatcher struct {
+	store  Store
+	bus    *EventBus
+	cfg    DispatcherConfig
+	logger zerolog.Logger
+}
+
+// NewDispatcher creates a dispatcher for the store and bus.
+func NewDispatcher(store Store, bus *EventBus, cfg DispatcherConfig, logger *zerolog.Logger) *Dispatcher {
+	cfg.applyDefaults()
+	l := zerolog.Nop()
+	if logger != nil {
+		l = logger.With().Str("component", "event_dispatcher").Logger()
+	}
+	return &Dispatcher{store: store, bus: bus, cfg: cfg, logger: l}
+}
+
+// Start polls the store until ctx is canceled.
+func (d *Dispatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	lastCleanup := time.Time{}
+	for {
+		if _, err := d.DispatchPending(ctx); err != nil && ctx.Err() == nil {
+			d.logger.Error().Err(err).Msg("dispatch pending events")
+		}
+
+		if d.cfg.Retention > 0 && time.Since(lastCleanup) > time.Hour {
+			lastCleanup = time.Now()
+			if n, err := d.store.DeleteProcessedEvents(ctx, time.Now().Add(-d.cfg.Retention)); err != nil {
+				d.logger.Error().Err(err).Msg("delete processed events")
+			} else if n > 0 {
+				d.logger.Info().Int64("deleted", n).Msg("processed events cleaned up")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchPending delivers one batch of due events and returns how many were processed.
+func (d *Dispatcher) DispatchPending(ctx context.Context) (int, error) {
+	pending, err := d.store.GetPendingEvents(ctx, time.Now(), d.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for _, ev := range pending {
+		if ctx.Err() != nil {
+			return processed, ctx.Err()
+		}
+
+		if err := d.bus.Dispatch(ev); err != nil {
+			d.fail(ctx, ev, err)
+			continue
+		}
+		if err := d.store.MarkEventProcessed(ctx, ev.ID); err != nil {
+			return processed, err
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+func (d *Dispatcher) fail(ctx context.Context, ev *Event, handlerErr error) {
+	attempt := ev.Attempts + 1
+	next := time.Now().Add(d.retryDelay(attempt))
+	l := d.logger.Warn()
+	if attempt >= d.cfg.MaxAttempts {
+		// The event is no longer picked up automatically; it can be delivered via Replay
+		next = time.Time{}
+		l = d.logger.Error()
+	}
+	l.Err(handlerErr).Int64("event_id", ev.ID).Str("event_type", ev.Type).Int("attempt", attempt).
+		Msg("event handler failed")
+
+	if err := d.store.MarkEventFailed(ctx, ev.ID, handlerErr.Error(), next); err != nil {
+		d.logger.Error().Err(err).Int64("event_id", ev.ID).Msg("mark event failed")
+	}
+}
+
+func (d *Dispatcher) retryDelay(attempt int) time.Duration {
+	delay := d.cfg.RetryDelay
+	for i := 1; i < attempt && delay < d.cfg.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > d.cfg.MaxDelay {
+		delay = d.cfg.MaxDelay
+	}
+	return delay
+}
+
+// Replay delivers all events with ID >= fromID, including already processed ones.
+// With a nil handler events go to the bus subscribers and successfully delivered
+// events are marked processed; otherwise only handler receives them, which lets
+// a new consumer catch up without affecting existing ones. Returns the last
+// delivered event ID.
+func (d *Dispatcher) Replay(ctx context.Context, fromID int64, handler EventHandler) (int64, error) {
+	lastID := fromID - 1
+	for {
+		batch, err := d.store.GetEventsFrom(ctx, lastID+1, d.cfg.BatchSize)
+		if err != nil {
+			return lastID, err
+		}
+		if len(batch) == 0 {
+			return lastID, nil
+		}
+
+		for _, ev := range batch {
+			if ctx.Err() != nil {
+				return lastID, ctx.Err()
+			}
+
+			if handler != nil {
+				if err := handler(ev); err != nil {
+					return lastID, err
+				}
+			} else {
+				if err := d.bus.Dispatch(ev); err != nil {
+					return lastID, err
+				}
+				if !ev.Processed {
+					if err := d.store.MarkEventProcessed(ctx, ev.ID); err != nil {
+						return lastID, err
+					}
+				}
+			}
+			lastID = ev.ID
+		}
+	}
+}
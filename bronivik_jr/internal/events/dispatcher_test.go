@@ -0,0 +1,141 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type memoryStore struct {
+	events []*Event
+	failed map[int64]time.Time
+}
+
+func newMemoryStore(types ...string) *memoryStore {
+	s := &memoryStore{failed: make(map[int64]time.Time)}
+	for i, t := range types {
+		s.events = append(s.events, &Event{ID: int64(i + 1), Type: t})
+	}
+	return s
+}
+
+func (s *memoryStore) GetPendingEvents(_ context.Context, now time.Time, limit int) ([]*Event, error) {
+	var result []*Event
+	for _, ev := range s.events {
+		next, failed := s.failed[ev.ID]
+		if ev.Processed || (failed && (next.IsZero() || next.After(now))) {
+			continue
+		}
+		result = append(result, ev)
+		if len(result) == limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (s *memoryStore) GetEventsFrom(_ context.Context, fromID int64, limit int) ([]*Event, error) {
+	var result []*Event
+	for _, ev := range s.events {
+		if ev.ID >= fromID && len(result) < limit {
+			result = append(result, ev)
+		}
+	}
+	return result, nil
+}
+
+func (s *memoryStore) MarkEventProcessed(_ context.Context, id int64) error {
+	s.events[id-1].Processed = true
+	delete(s.failed, id)
+	return nil
+}
+
+func (s *memoryStore) MarkEventFailed(_ context.Context, id int64, _ string, next time.Time) error {
+	s.events[id-1].Attempts++
+	s.failed[id] = next
+	return nil
+}
+
+func (s *memoryStore) DeleteProcessedEvents(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestDispatcherRetriesFailedHandlers(t *testing.T) {
+	store := newMemoryStore("ok", "flaky")
+	bus := NewEventBus()
+	bus.Subscribe("ok", func(_ *Event) error { return nil })
+	failures := 2
+	bus.Subscribe("flaky", func(_ *Event) error {
+		if failures > 0 {
+			failures--
+			return errors.New("temporary")
+		}
+		return nil
+	})
+
+	d := NewDispatcher(store, bus, DispatcherConfig{MaxAttempts: 3, RetryDelay: time.Nanosecond}, nil)
+	ctx := context.Background()
+
+	n, err := d.DispatchPending(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("expected 1 processed event, got %d (%v)", n, err)
+	}
+	if store.events[1].Attempts != 1 || store.events[1].Processed {
+		t.Fatalf("expected failed attempt to be recorded, got %+v", store.events[1])
+	}
+
+	time.Sleep(time.Millisecond)
+	_, _ = d.DispatchPending(ctx)
+	time.Sleep(time.Millisecond)
+	n, err = d.DispatchPending(ctx)
+	if err != nil || n != 1 || !store.events[1].Processed {
+		t.Fatalf("expected flaky event to be delivered on third attempt, got %d (%v)", n, err)
+	}
+}
+
+func TestDispatcherStopsAfterMaxAttempts(t *testing.T) {
+	store := newMemoryStore("broken")
+	bus := NewEventBus()
+	bus.Subscribe("broken", func(_ *Event) error { return errors.New("permanent") })
+
+	d := NewDispatcher(store, bus, DispatcherConfig{MaxAttempts: 1}, nil)
+	_, _ = d.DispatchPending(context.Background())
+
+	if next, ok := store.failed[1]; !ok || !next.IsZero() {
+		t.Fatalf("expected event to be excluded from automatic delivery, got %v", next)
+	}
+	pending, _ := store.GetPendingEvents(context.Background(), time.Now().Add(time.Hour), 10)
+	if len(pending) != 0 {
+		t.Errorf("expected no pending events, got %d", len(pending))
+	}
+}
+
+func TestDispatcherReplay(t *testing.T) {
+	store := newMemoryStore("a", "b", "a")
+	store.events[0].Processed = true
+	bus := NewEventBus()
+	var delivered []int64
+	bus.Subscribe("a", func(ev *Event) error { delivered = append(delivered, ev.ID); return nil })
+
+	d := NewDispatcher(store, bus, DispatcherConfig{BatchSize: 2}, nil)
+
+	// A new consumer receives events only through its own handler
+	var seen []int64
+	lastID, err := d.Replay(context.Background(), 2, func(ev *Event) error { seen = append(seen, ev.ID); return nil })
+	if err != nil || lastID != 3 {
+		t.Fatalf("unexpected replay result: %d (%v)", lastID, err)
+	}
+	if len(seen) != 2 || len(delivered) != 0 || store.events[2].Processed {
+		t.Fatalf("expected only custom handler to receive events, got %v / %v", seen, delivered)
+	}
+
+	// Without a handler events go to bus subscribers, including processed ones
+	lastID, err = d.Replay(context.Background(), 1, nil)
+	if err != nil || lastID != 3 {
+		t.Fatalf("unexpected replay result: %d (%v)", lastID, err)
+	}
+	if len(delivered) != 2 || !store.events[1].Processed || !store.events[2].Processed {
+		t.Errorf("expected events 1 and 3 to be redelivered, got %v", delivered)
+	}
+}
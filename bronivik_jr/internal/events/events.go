@@ -2,39 +2,61 @@ package events
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"sync"
 	"time"
 )
 
 const (
-	EventBookingCreated    = "booking_created"
-	EventBookingConfirmed  = "booking_confirmed"
-	EventBookingCanceled   = "booking_canceled"
-	EventBookingCompleted  = "booking_completed"
-	EventBookingItemChange = "booking_item_changed"
+	EventBookingCreated     = "booking_created"
+	EventBookingConfirmed   = "booking_confirmed"
+	EventBookingCanceled    = "booking_canceled"
+	EventBookingCompleted   = "booking_completed"
+	EventBookingItemChange  = "booking_item_changed"
+	EventBookingRescheduled = "booking_rescheduled"
 )
 
+// BookingEventTypes lists all booking lifecycle event types.
+var BookingEventTypes = []string{
+	EventBookingCreated,
+	EventBookingConfirmed,
+	EventBookingCanceled,
+	EventBookingCompleted,
+	EventBookingItemChange,
+	EventBookingRescheduled,
+}
+
 // BookingEventPayload describes the minimal booking snapshot for event consumers.
+// It is stored in the outbox and sent to webhooks as is, so it carries no client
+// name or phone; consumers look the booking up by ID.
 type BookingEventPayload struct {
-	BookingID   int64     `json:"booking_id"`
-	UserID      int64     `json:"user_id"`
-	UserName    string    `json:"user_name"`
-	ItemID      int64     `json:"item_id"`
-	ItemName    string    `json:"item_name"`
-	Status      string    `json:"status"`
-	Date        time.Time `json:"date"`
-	Comment     string    `json:"comment,omitempty"`
-	ChangedBy   string    `json:"changed_by,omitempty"`
-	ChangedByID int64     `json:"changed_by_id,omitempty"`
+	BookingID   int64      `json:"booking_id"`
+	UserID      int64      `json:"user_id"`
+	ItemID      int64      `json:"item_id"`
+	ItemName    string     `json:"item_name"`
+	Status      string     `json:"status"`
+	Date        time.Time  `json:"date"`
+	EndTime     *time.Time `json:"end_time,omitempty"`
+	Comment     string     `json:"comment,omitempty"`
+	ChangedBy   string     `json:"changed_by,omitempty"`
+	ChangedByID int64      `json:"changed_by_id,omitempty"`
+	// Previous dates, set only for booking_rescheduled events.
+	PreviousDate    *time.Time `json:"previous_date,omitempty"`
+	PreviousEndTime *time.Time `json:"previous_end_time,omitempty"`
+	// Previous item, set only for booking_item_changed events.
+	PreviousItemName string `json:"previous_item_name,omitempty"`
 }
 
 // Event represents a lightweight domain event.
+// ID and Processed are set for events loaded from the outbox store.
 type Event struct {
 	ID        int64
 	Type      string
 	Payload   []byte
 	CreatedAt time.Time
 	Processed bool
+	Attempts  int
 }
 
 // EventHandler reacts to an event.
@@ -58,8 +80,14 @@ func (b *EventBus) Subscribe(eventType string, handler EventHandler) {
 	b.subscribers[eventType] = append(b.subscribers[eventType], handler)
 }
 
-// Publish notifies subscribers of the event type.
+// Publish notifies subscribers of the event type, ignoring handler errors.
 func (b *EventBus) Publish(event *Event) {
+	_ = b.Dispatch(event)
+}
+
+// Dispatch runs every subscriber of the event type and returns their joined errors.
+// All handlers are called even if some of them fail.
+func (b *EventBus) Dispatch(event *Event) error {
 	b.mu.RLock()
 	handlers := append([]EventHandler(nil), b.subscribers[event.Type]...)
 	b.mu.RUnlock()
@@ -68,10 +96,14 @@ func (b *EventBus) Publish(event *Event) {
 		event.CreatedAt = time.Now()
 	}
 
-	for _, handler := range handlers {
+	var errs []error
+	for i, handler := range handlers {
 		// Handlers run synchronously; caller decides concurrency model.
-		_ = handler(event)
+		if err := handler(event); err != nil {
+			errs = append(errs, fmt.Errorf("%s handler %d: %w", event.Type, i, err))
+		}
 	}
+	return errors.Join(errs...)
 }
 
 // PublishJSON serializes the payload and publishes an event.
@@ -2,6 +2,8 @@ package events
 
 import (
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -91,3 +93,19 @@ func TestNewJSONEvent(t *testing.T) {
 		t.Errorf("expected BookingID 123, got %d", decoded.BookingID)
 	}
 }
+
+func TestEventBusDispatchJoinsErrors(t *testing.T) {
+	bus := NewEventBus()
+	var calls int
+
+	bus.Subscribe("event", func(_ *Event) error { calls++; return errors.New("first") })
+	bus.Subscribe("event", func(_ *Event) error { calls++; return nil })
+
+	err := bus.Dispatch(&Event{Type: "event"})
+	if err == nil || !strings.Contains(err.Error(), "first") {
+		t.Errorf("expected handler error, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected all handlers to be called, got %d", calls)
+	}
+}
@@ -55,6 +55,36 @@ func TestSheetsService_UpdateUsersSheet(t *testing.T) {
 	}
 }
 
+func TestSheetsService_DeleteUserRows(t *testing.T) {
+	ctx := context.Background()
+	mux, server, s := setupMockServer(ctx)
+	defer server.Close()
+	mux.HandleFunc("/v4/spreadsheets/users_tid/values/Users!B:B", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(sheets.ValueRange{Values: [][]interface{}{{"Telegram ID"}, {"100"}, {"200"}, {}, {"100"}}})
+	})
+	var cleared []string
+	mux.HandleFunc("/v4/spreadsheets/users_tid/values/", func(w http.ResponseWriter, r *http.Request) {
+		cleared = append(cleared, r.URL.Path)
+		_ = json.NewEncoder(w).Encode(sheets.ClearValuesResponse{})
+	})
+
+	if err := s.DeleteUserRows(ctx, 100); err != nil {
+		t.Fatalf("DeleteUserRows failed: %v", err)
+	}
+	want := []string{
+		"/v4/spreadsheets/users_tid/values/Users!A2:K2:clear",
+		"/v4/spreadsheets/users_tid/values/Users!A5:K5:clear",
+	}
+	if len(cleared) != len(want) {
+		t.Fatalf("cleared %v, want %v", cleared, want)
+	}
+	for i := range want {
+		if cleared[i] != want[i] {
+			t.Errorf("cleared[%d] = %s, want %s", i, cleared[i], want[i])
+		}
+	}
+}
+
 func TestSheetsService_WarmUpCache(t *testing.T) {
 	ctx := context.Background()
 	mux, server, s := setupMockServer(ctx)
@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"bronivik/internal/fieldcrypt"
 	"bronivik/internal/models"
 
 	"golang.org/x/oauth2/google"
@@ -23,6 +24,21 @@ type SheetsService struct {
 	rowCache        map[int64]int
 	cacheMu         sync.RWMutex
 	lastRefresh     time.Time
+	maskPersonal    bool
+}
+
+// SetMaskPersonalData включает маскирование телефонов и имен клиентов в таблицах:
+// при шифровании полей в Sheets попадают только последние цифры и инициалы.
+func (s *SheetsService) SetMaskPersonalData(mask bool) {
+	s.maskPersonal = mask
+}
+
+// person возвращает имя и телефон клиента в том виде, в котором их можно писать в таблицу.
+func (s *SheetsService) person(name, phone string) (string, string) {
+	if !s.maskPersonal {
+		return name, phone
+	}
+	return fieldcrypt.MaskName(name), fieldcrypt.MaskPhone(phone)
 }
 
 func NewSimpleSheetsService(credentialsFile, usersSheetID, bookingsSheetID string) (*SheetsService, error) {
@@ -120,13 +136,14 @@ func (s *SheetsService) UpdateUsersSheet(ctx context.Context, users []*models.Us
 
 	// Данные пользователей
 	for _, user := range users {
+		_, phone := s.person("", user.Phone)
 		row := []interface{}{
 			user.ID,
 			user.TelegramID,
 			user.Username,
 			user.FirstName,
 			user.LastName,
-			user.Phone,
+			phone,
 			user.IsManager,
 			user.IsBlacklisted,
 			user.LanguageCode,
@@ -151,6 +168,39 @@ func (s *SheetsService) UpdateUsersSheet(ctx context.Context, users []*models.Us
 	return err
 }
 
+// DeleteUserRows очищает строки листа Users с указанным Telegram ID (колонка B).
+// Используется при удалении персональных данных пользователя.
+func (s *SheetsService) DeleteUserRows(ctx context.Context, telegramID int64) error {
+	resp, err := s.service.Spreadsheets.Values.Get(s.usersSheetID, "Users!B:B").Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	for i, row := range resp.Values {
+		if len(row) == 0 {
+			continue
+		}
+		var id int64
+		switch v := row[0].(type) {
+		case float64:
+			id = int64(v)
+		case string:
+			_, _ = fmt.Sscanf(v, "%d", &id)
+		}
+		if id != telegramID {
+			continue
+		}
+
+		rangeData := fmt.Sprintf("Users!A%d:K%d", i+1, i+1)
+		if _, err := s.service.Spreadsheets.Values.Clear(s.usersSheetID, rangeData, &sheets.ClearValuesRequest{}).
+			Context(ctx).
+			Do(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // WarmUpCache populates the row index cache by reading the entire ID column.
 func (s *SheetsService) WarmUpCache(ctx context.Context) error {
 	resp, err := s.service.Spreadsheets.Values.Get(s.bookingsSheetID, "Bookings!A:A").Context(ctx).Do()
@@ -183,18 +233,7 @@ func (s *SheetsService) WarmUpCache(ctx context.Context) error {
 
 // AppendBooking добавляет новое бронирование
 func (s *SheetsService) AppendBooking(ctx context.Context, booking *models.Booking) error {
-	row := []interface{}{
-		booking.ID,
-		booking.UserID,
-		booking.ItemID,
-		booking.Date.Format("2006-01-02"),
-		booking.Status,
-		booking.UserName,
-		booking.Phone,
-		booking.ItemName,
-		booking.CreatedAt.Format("2006-01-02 15:04:05"),
-		booking.UpdatedAt.Format("2006-01-02 15:04:05"),
-	}
+	row := s.bookingRowValues(booking)
 
 	rangeData := "Bookings!A:A"
 	valueRange := &sheets.ValueRange{
@@ -234,7 +273,7 @@ func (s *SheetsService) UpsertBooking(ctx context.Context, booking *models.Booki
 
 	rangeData := fmt.Sprintf("Bookings!A%d:J%d", rowIdx, rowIdx)
 	valueRange := &sheets.ValueRange{
-		Values: [][]interface{}{bookingRowValues(booking)},
+		Values: [][]interface{}{s.bookingRowValues(booking)},
 	}
 
 	_, err = s.service.Spreadsheets.Values.Update(s.bookingsSheetID, rangeData, valueRange).
@@ -361,15 +400,16 @@ func (s *SheetsService) ClearCache() {
 	s.rowCache = make(map[int64]int)
 }
 
-func bookingRowValues(booking *models.Booking) []interface{} {
+func (s *SheetsService) bookingRowValues(booking *models.Booking) []interface{} {
+	name, phone := s.person(booking.UserName, booking.Phone)
 	return []interface{}{
 		booking.ID,
 		booking.UserID,
 		booking.ItemID,
 		booking.Date.Format("2006-01-02"),
 		booking.Status,
-		booking.UserName,
-		booking.Phone,
+		name,
+		phone,
 		booking.ItemName,
 		booking.CreatedAt.Format("2006-01-02 15:04:05"),
 		booking.UpdatedAt.Format("2006-01-02 15:04:05"),
@@ -389,19 +429,7 @@ func (s *SheetsService) UpdateBookingsSheet(ctx context.Context, bookings []*mod
 
 	// Данные бронирований
 	for _, booking := range bookings {
-		row := []interface{}{
-			booking.ID,
-			booking.UserID,
-			booking.ItemID,
-			booking.Date.Format("2006-01-02"),
-			booking.Status,
-			booking.UserName,
-			booking.Phone,
-			booking.ItemName,
-			booking.CreatedAt.Format("2006-01-02 15:04:05"),
-			booking.UpdatedAt.Format("2006-01-02 15:04:05"),
-		}
-		values = append(values, row)
+		values = append(values, s.bookingRowValues(booking))
 	}
 
 	// Полностью очищаем и перезаписываем лист
@@ -635,7 +663,8 @@ func (s *SheetsService) formatScheduleCell(item *models.Item, itemBookings []*mo
 			statusIcon = "❌"
 		}
 
-		cellValue += fmt.Sprintf("[№%d] %s %s (%s)\n", b.ID, statusIcon, b.UserName, b.Phone)
+		name, phone := s.person(b.UserName, b.Phone)
+		cellValue += fmt.Sprintf("[№%d] %s %s (%s)\n", b.ID, statusIcon, name, phone)
 		if b.Comment != "" {
 			cellValue += fmt.Sprintf("   💬 %s\n", b.Comment)
 		}
@@ -794,11 +823,12 @@ func (s *SheetsService) ReplaceBookingsSheet(ctx context.Context, bookings []*mo
 	// Подготавливаем данные для записи
 	values := make([][]interface{}, 0, len(bookings))
 	for _, booking := range bookings {
+		name, phone := s.person(booking.UserName, booking.Phone)
 		row := []interface{}{
 			booking.ID,
 			booking.UserID,
-			booking.UserName,
-			booking.Phone,
+			name,
+			phone,
 			booking.ItemName,
 			booking.Date.Format("02.01.2006"),
 			booking.Status,
@@ -49,7 +49,7 @@ func TestBookingRowValues(t *testing.T) {
 		UpdatedAt: updatedAt,
 	}
 
-	values := bookingRowValues(booking)
+	values := (&SheetsService{}).bookingRowValues(booking)
 
 	expected := []interface{}{
 		int64(123),
@@ -75,6 +75,16 @@ func TestBookingRowValues(t *testing.T) {
 	}
 }
 
+func TestBookingRowValuesMasked(t *testing.T) {
+	s := &SheetsService{}
+	s.SetMaskPersonalData(true)
+
+	values := s.bookingRowValues(&models.Booking{UserName: "Test User", Phone: "+7 999 123-45-67"})
+	if values[5] != "T. U." || values[6] != "*******4567" {
+		t.Errorf("Expected masked name and phone, got %v and %v", values[5], values[6])
+	}
+}
+
 func TestCacheOperations(t *testing.T) {
 	s := &SheetsService{
 		rowCache: make(map[int64]int),
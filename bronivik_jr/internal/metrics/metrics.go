@@ -17,12 +17,30 @@ var (
 		},
 		[]string{"endpoint"},
 	)
+
+	apiKeyRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "bronivik_jr",
+			Name:      "api_key_requests_total",
+			Help:      "API requests by client key name and rate limit result (allowed, rate_limited, quota_exceeded).",
+		},
+		[]string{"key", "result"},
+	)
+
+	apiKeyQuotaUsed = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "bronivik_jr",
+			Name:      "api_key_daily_quota_used",
+			Help:      "Requests counted against the daily quota of a client key (UTC day).",
+		},
+		[]string{"key"},
+	)
 )
 
 // Register registers Prometheus metrics. Safe to call multiple times.
 func Register() {
 	once.Do(func() {
-		prometheus.MustRegister(httpRequests)
+		prometheus.MustRegister(httpRequests, apiKeyRequests, apiKeyQuotaUsed)
 	})
 }
 
@@ -30,3 +48,13 @@ func Register() {
 func IncHTTP(endpoint string) {
 	httpRequests.WithLabelValues(endpoint).Inc()
 }
+
+// IncAPIKeyRequest counts a request of an API client by rate limit result.
+func IncAPIKeyRequest(key, result string) {
+	apiKeyRequests.WithLabelValues(key, result).Inc()
+}
+
+// SetAPIKeyQuotaUsed records how much of its daily quota an API client has used.
+func SetAPIKeyQuotaUsed(key string, used int) {
+	apiKeyQuotaUsed.WithLabelValues(key).Set(float64(used))
+}
@@ -14,5 +14,7 @@ func TestMetrics(t *testing.T) {
 	// IncHTTP should not panic
 	assert.NotPanics(t, func() {
 		IncHTTP("test_endpoint")
+		IncAPIKeyRequest("crm", "allowed")
+		SetAPIKeyQuotaUsed("crm", 3)
 	})
 }
@@ -0,0 +1,154 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"testing"
+	"testing/fstest"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"001_create_notes.up.sql":                {Data: []byte(`CREATE TABLE notes (id INTEGER PRIMARY KEY, body TEXT);`)},
+		"001_create_notes.down.sql":              {Data: []byte(`DROP TABLE notes;`)},
+		"002_add_author.up.sql":                  {Data: []byte(`ALTER TABLE notes ADD COLUMN author TEXT;`)},
+		"002_add_author.postgres.up.sql":         {Data: []byte(`ALTER TABLE notes ADD COLUMN IF NOT EXISTS author TEXT;`)},
+		"002_add_author.down.sql":                {Data: []byte(`ALTER TABLE notes DROP COLUMN author;`)},
+		"003_index_author.up.sql":                {Data: []byte(`CREATE INDEX idx_notes_author ON notes(author);`)},
+		"003_index_author.down.sql":              {Data: []byte(`DROP INDEX idx_notes_author;`)},
+		"README.md":                              {Data: []byte(`not a migration`)},
+		"004_ignored_for_sqlite.postgres.up.sql": {Data: []byte(`SELECT 1;`)},
+	}
+}
+
+func newTestRunner(t *testing.T, db *sql.DB, fsys fstest.MapFS, opts Options) *Runner {
+	t.Helper()
+	migrations, err := Load(fsys, opts.Dialect)
+	require.NoError(t, err)
+	r, err := New(db, migrations, opts)
+	require.NoError(t, err)
+	return r
+}
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestLoad(t *testing.T) {
+	migrations, err := Load(testFS(), DialectSQLite)
+	require.NoError(t, err)
+	require.Len(t, migrations, 3)
+	assert.Equal(t, int64(2), migrations[1].Version)
+	assert.Equal(t, "add_author", migrations[1].Name)
+	assert.Contains(t, migrations[1].Up, "ADD COLUMN author")
+	assert.NotEmpty(t, migrations[1].Checksum)
+
+	pg, err := Load(testFS(), DialectPostgres)
+	require.NoError(t, err)
+	require.Len(t, pg, 4)
+	assert.Contains(t, pg[1].Up, "IF NOT EXISTS")
+	assert.NotEqual(t, migrations[1].Checksum, pg[1].Checksum)
+
+	_, err = Load(fstest.MapFS{"001_x.down.sql": {Data: []byte(`SELECT 1;`)}}, DialectSQLite)
+	assert.Error(t, err)
+}
+
+func TestRunnerUpDownGoto(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	r := newTestRunner(t, db, testFS(), Options{})
+
+	n, err := r.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	version, err := r.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), version)
+
+	// Повторный запуск ничего не применяет
+	n, err = r.Up(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	n, err = r.Down(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	version, err = r.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), version)
+
+	n, err = r.Goto(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	_, err = db.Exec(`INSERT INTO notes (body, author) VALUES ('x', 'y')`)
+	require.NoError(t, err)
+
+	_, err = r.Goto(ctx, 42)
+	assert.ErrorIs(t, err, ErrUnknownVersion)
+
+	n, err = r.Goto(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	statuses, err := r.Status(ctx)
+	require.NoError(t, err)
+	for _, st := range statuses {
+		assert.False(t, st.Applied, st.Version)
+	}
+}
+
+func TestRunnerDryRun(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	var out bytes.Buffer
+	r := newTestRunner(t, db, testFS(), Options{DryRun: true, Out: &out})
+
+	n, err := r.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Contains(t, out.String(), "-- up 1_create_notes")
+	assert.Contains(t, out.String(), "CREATE TABLE notes")
+
+	var tables int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table'`).Scan(&tables))
+	assert.Zero(t, tables, "dry run must not change the database")
+}
+
+func TestRunnerRefusesNewerAndModifiedSchema(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	_, err := newTestRunner(t, db, testFS(), Options{Table: "migrations_log"}).Up(ctx)
+	require.NoError(t, err)
+
+	// Старый бинарник знает только первую миграцию
+	old := testFS()
+	delete(old, "002_add_author.up.sql")
+	delete(old, "002_add_author.postgres.up.sql")
+	delete(old, "002_add_author.down.sql")
+	delete(old, "003_index_author.up.sql")
+	delete(old, "003_index_author.down.sql")
+	r := newTestRunner(t, db, old, Options{Table: "migrations_log"})
+	assert.ErrorIs(t, r.Check(ctx), ErrSchemaNewer)
+	_, err = r.Up(ctx)
+	assert.ErrorIs(t, err, ErrSchemaNewer)
+
+	edited := testFS()
+	edited["001_create_notes.up.sql"] = &fstest.MapFile{Data: []byte(`CREATE TABLE notes (id INTEGER PRIMARY KEY);`)}
+	r = newTestRunner(t, db, edited, Options{Table: "migrations_log"})
+	assert.ErrorIs(t, r.Check(ctx), ErrChecksumMismatch)
+	statuses, err := r.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, statuses[0].Modified)
+
+	_, err = New(db, nil, Options{Table: "bad name"})
+	assert.Error(t, err)
+}
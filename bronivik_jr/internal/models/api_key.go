@@ -0,0 +1,84 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// Разрешения ключей API. Ключ без разрешений получает все, кроме административных.
+const (
+	PermReadAvailability = "read:availability"
+	PermReadItems        = "read:items"
+	PermReadBookings     = "read:bookings"
+	PermWriteBookings    = "write:bookings"
+	PermAdminWebhooks    = "admin:webhooks"
+	PermAdminAPIKeys     = "admin:api_keys"
+)
+
+// APIKeyPermissions — все разрешения, которые можно выдать ключу.
+var APIKeyPermissions = []string{
+	PermReadAvailability,
+	PermReadItems,
+	PermReadBookings,
+	PermWriteBookings,
+	PermAdminWebhooks,
+	PermAdminAPIKeys,
+}
+
+var (
+	ErrInvalidAPIKeyName       = errors.New("api key name is required")
+	ErrInvalidAPIKeyPermission = errors.New("unknown api key permission")
+	ErrNoAPIKeyPermissions     = errors.New("api key needs at least one permission")
+	ErrInvalidAPIKeyExpiry     = errors.New("api key expiry must be in the future")
+	ErrInvalidAPIKeyLimits     = errors.New("api key limits must not be negative")
+)
+
+// APIKeyLimits — собственные лимиты ключа. Нулевое поле означает общий лимит из api.rate_limit.
+type APIKeyLimits struct {
+	RateLimitRPS   float64 `json:"rate_limit_rps,omitempty"`
+	RateLimitBurst int     `json:"rate_limit_burst,omitempty"`
+	DailyQuota     int     `json:"daily_quota,omitempty"` // запросов в сутки (UTC)
+}
+
+// Validate проверяет, что лимиты не отрицательные.
+func (l APIKeyLimits) Validate() error {
+	if l.RateLimitRPS < 0 || l.RateLimitBurst < 0 || l.DailyQuota < 0 {
+		return ErrInvalidAPIKeyLimits
+	}
+	return nil
+}
+
+// APIKey — ключ внешнего клиента API. В базе лежит только SHA-256 ключа,
+// сам ключ и секрет подписи отдаются один раз: при создании или ротации.
+type APIKey struct {
+	ID            int64      `json:"id"`
+	Name          string     `json:"name"`
+	Prefix        string     `json:"prefix"` // начало ключа, чтобы узнать его в списке
+	Key           string     `json:"key,omitempty"`
+	Hash          string     `json:"-"`
+	SigningSecret string     `json:"signing_secret,omitempty"` // секрет HMAC-подписи запросов; в базе зашифрован
+	Permissions   []string   `json:"permissions"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt    *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	ReplacedBy    *int64     `json:"replaced_by,omitempty"`    // новый ключ после ротации; старый работает до expires_at
+	ClientCertCN  string     `json:"client_cert_cn,omitempty"` // CN сертификата клиента mTLS, закрепленного за ключом
+	ClientID      int64      `json:"client_id"`                // id первого ключа в цепочке ротаций; не меняется при ротации
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	APIKeyLimits
+}
+
+// ActiveAt сообщает, принимается ли ключ в момент now.
+func (k *APIKey) ActiveAt(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// HashAPIKey возвращает SHA-256 ключа, по которому он ищется в базе.
+// Ключи случайные и длинные, поэтому соль и медленный хеш не нужны.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
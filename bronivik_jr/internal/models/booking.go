@@ -17,6 +17,7 @@ type Booking struct {
 	Comment           string     `json:"comment"`
 	ReminderSent      bool       `json:"reminder_sent"`
 	ExternalBookingID string     `json:"external_booking_id,omitempty"` // ID from CRM bot
+	SeriesID          *int64     `json:"series_id,omitempty"`           // recurring series, nil for one-off bookings
 	CreatedAt         time.Time  `json:"created_at"`
 	UpdatedAt         time.Time  `json:"updated_at"`
 	Version           int64      `json:"version"`
@@ -65,3 +66,26 @@ func (b *Booking) ContainsDate(date time.Time) bool {
 
 	return !dateOnly.Before(startOnly) && !dateOnly.After(endOnly)
 }
+
+// CoveredDates returns every calendar day covered by the booking, both ends inclusive.
+// The first element is Date itself, the following ones keep its time of day.
+func (b *Booking) CoveredDates() []time.Time {
+	dates := []time.Time{b.Date}
+	for d := b.Date.AddDate(0, 0, 1); b.ContainsDate(d); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d)
+	}
+	return dates
+}
+
+// BookingFilter selects bookings for paginated listing. Zero values do not filter.
+type BookingFilter struct {
+	ItemID            int64
+	UserID            int64
+	ExternalBookingID string
+	Phone             string // client phone in any format; matched via the blind index when encryption is on
+	Statuses          []string
+	From              *time.Time // bookings ending on or after From
+	To                *time.Time // bookings starting on or before To
+	AfterID           int64      // cursor: only bookings with a greater ID
+	Limit             int
+}
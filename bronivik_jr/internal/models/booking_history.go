@@ -0,0 +1,49 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// Поля заявки, изменения которых попадают в историю.
+const (
+	HistoryFieldStatus = "status"
+	HistoryFieldItem   = "item"
+	HistoryFieldDates  = "dates" // "2006-01-02" или "2006-01-02..2006-01-05" для периода
+)
+
+// BookingHistoryEntry — одно изменение заявки.
+type BookingHistoryEntry struct {
+	ID        int64       `json:"id"`
+	BookingID int64       `json:"booking_id"`
+	Field     string      `json:"field"`
+	OldValue  string      `json:"old_value"` // пусто при создании заявки
+	NewValue  string      `json:"new_value"`
+	ActorType StatusActor `json:"actor_type"`
+	ActorID   int64       `json:"actor_id,omitempty"`
+	ActorName string      `json:"actor_name,omitempty"`
+	Reason    string      `json:"reason,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// HistoryDates форматирует даты заявки для поля HistoryFieldDates.
+func HistoryDates(date time.Time, endTime *time.Time) string {
+	value := date.Format("2006-01-02")
+	if endTime != nil && !endTime.Equal(date) {
+		value += ".." + endTime.Format("2006-01-02")
+	}
+	return value
+}
+
+type changeReasonKey struct{}
+
+// WithChangeReason добавляет к контексту причину изменения заявки.
+func WithChangeReason(ctx context.Context, reason string) context.Context {
+	return context.WithValue(ctx, changeReasonKey{}, reason)
+}
+
+// ChangeReasonFromContext возвращает причину изменения, если она задана.
+func ChangeReasonFromContext(ctx context.Context) string {
+	reason, _ := ctx.Value(changeReasonKey{}).(string)
+	return reason
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidRecurrence  = errors.New("invalid recurrence rule")
+	ErrTooManyOccurrences = errors.New("too many occurrences in series")
+)
+
+// RecurrenceRule describes how often a booking series repeats and when it stops.
+// Exactly one of Until or Count must be set.
+type RecurrenceRule struct {
+	Frequency string     `json:"frequency"` // weekly, monthly
+	Interval  int        `json:"interval"`  // every N weeks/months, defaults to 1
+	Until     *time.Time `json:"until,omitempty"`
+	Count     int        `json:"count,omitempty"`
+}
+
+// Validate checks that the rule is well-formed.
+func (r RecurrenceRule) Validate() error {
+	if r.Frequency != RecurrenceWeekly && r.Frequency != RecurrenceMonthly {
+		return ErrInvalidRecurrence
+	}
+	if r.Interval < 0 || r.Count < 0 {
+		return ErrInvalidRecurrence
+	}
+	if (r.Until == nil) == (r.Count == 0) {
+		return ErrInvalidRecurrence
+	}
+	if r.Count > MaxSeriesOccurrences {
+		return ErrTooManyOccurrences
+	}
+	return nil
+}
+
+// Occurrences returns the dates of the series starting at start.
+// Monthly series skip months that do not have the start day (e.g. the 31st).
+func (r RecurrenceRule) Occurrences(start time.Time) ([]time.Time, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	interval := r.Interval
+	if interval == 0 {
+		interval = 1
+	}
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+
+	var dates []time.Time
+	for i := 0; ; i++ {
+		date := start.AddDate(0, 0, 7*interval*i)
+		if r.Frequency == RecurrenceMonthly {
+			date = start.AddDate(0, interval*i, 0)
+		}
+
+		if r.Until != nil && date.After(*r.Until) {
+			break
+		}
+		// AddDate нормализует 31.04 в 01.05 — такие месяцы пропускаем
+		if r.Frequency == RecurrenceMonthly && date.Day() != start.Day() {
+			continue
+		}
+		if len(dates) == MaxSeriesOccurrences {
+			return nil, ErrTooManyOccurrences
+		}
+		dates = append(dates, date)
+		if r.Count > 0 && len(dates) == r.Count {
+			break
+		}
+	}
+	if len(dates) == 0 {
+		return nil, ErrInvalidRecurrence
+	}
+	return dates, nil
+}
+
+// BookingSeries groups recurring bookings of one item for one client.
+type BookingSeries struct {
+	ID           int64      `json:"id"`
+	UserID       int64      `json:"user_id"`
+	UserName     string     `json:"user_name"`
+	UserNickname string     `json:"user_nickname"`
+	Phone        string     `json:"phone"`
+	ItemID       int64      `json:"item_id"`
+	ItemName     string     `json:"item_name"`
+	Frequency    string     `json:"frequency"`
+	Interval     int        `json:"interval"`
+	StartDate    time.Time  `json:"start_date"`
+	UntilDate    *time.Time `json:"until_date,omitempty"`
+	Count        int        `json:"count,omitempty"`
+	Status       string     `json:"status"` // active, canceled
+	Comment      string     `json:"comment"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Rule returns the recurrence rule of the series.
+func (s *BookingSeries) Rule() RecurrenceRule {
+	return RecurrenceRule{Frequency: s.Frequency, Interval: s.Interval, Until: s.UntilDate, Count: s.Count}
+}
+
+// SeriesConflict describes an occurrence that could not be created or updated.
+type SeriesConflict struct {
+	Date      time.Time `json:"date"`
+	BookingID int64     `json:"booking_id,omitempty"`
+	Reason    string    `json:"reason"`
+}
+
+// SeriesResult is the per-date report of a series operation.
+type SeriesResult struct {
+	Series    *BookingSeries   `json:"series"`
+	Bookings  []*Booking       `json:"bookings"`
+	Conflicts []SeriesConflict `json:"conflicts"`
+}
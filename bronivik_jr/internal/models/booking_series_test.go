@@ -0,0 +1,78 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecurrenceRule_Validate(t *testing.T) {
+	until := day(20)
+	tests := []struct {
+		name    string
+		rule    RecurrenceRule
+		wantErr error
+	}{
+		{"weekly count", RecurrenceRule{Frequency: RecurrenceWeekly, Count: 4}, nil},
+		{"monthly until", RecurrenceRule{Frequency: RecurrenceMonthly, Until: &until}, nil},
+		{"unknown frequency", RecurrenceRule{Frequency: "daily", Count: 4}, ErrInvalidRecurrence},
+		{"no end", RecurrenceRule{Frequency: RecurrenceWeekly}, ErrInvalidRecurrence},
+		{"both ends", RecurrenceRule{Frequency: RecurrenceWeekly, Count: 2, Until: &until}, ErrInvalidRecurrence},
+		{"negative interval", RecurrenceRule{Frequency: RecurrenceWeekly, Interval: -1, Count: 2}, ErrInvalidRecurrence},
+		{"too many", RecurrenceRule{Frequency: RecurrenceWeekly, Count: MaxSeriesOccurrences + 1}, ErrTooManyOccurrences},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecurrenceRule_Occurrences(t *testing.T) {
+	t.Run("weekly by count", func(t *testing.T) {
+		rule := RecurrenceRule{Frequency: RecurrenceWeekly, Count: 3}
+		dates, err := rule.Occurrences(day(5))
+		require.NoError(t, err)
+		assert.Equal(t, []time.Time{day(5), day(12), day(19)}, dates)
+	})
+
+	t.Run("biweekly until inclusive", func(t *testing.T) {
+		until := day(19)
+		rule := RecurrenceRule{Frequency: RecurrenceWeekly, Interval: 2, Until: &until}
+		dates, err := rule.Occurrences(day(5))
+		require.NoError(t, err)
+		assert.Equal(t, []time.Time{day(5), day(19)}, dates)
+	})
+
+	t.Run("monthly skips short months", func(t *testing.T) {
+		rule := RecurrenceRule{Frequency: RecurrenceMonthly, Count: 3}
+		dates, err := rule.Occurrences(day(31))
+		require.NoError(t, err)
+		require.Len(t, dates, 3)
+		assert.Equal(t, time.January, dates[0].Month())
+		assert.Equal(t, time.March, dates[1].Month())
+		assert.Equal(t, time.May, dates[2].Month())
+	})
+
+	t.Run("until before start", func(t *testing.T) {
+		until := day(1)
+		rule := RecurrenceRule{Frequency: RecurrenceWeekly, Until: &until}
+		_, err := rule.Occurrences(day(5))
+		assert.ErrorIs(t, err, ErrInvalidRecurrence)
+	})
+
+	t.Run("until too far", func(t *testing.T) {
+		until := day(1).AddDate(5, 0, 0)
+		rule := RecurrenceRule{Frequency: RecurrenceWeekly, Until: &until}
+		_, err := rule.Occurrences(day(1))
+		assert.ErrorIs(t, err, ErrTooManyOccurrences)
+	})
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// StatusActor определяет, по чьим правилам меняется статус заявки.
+type StatusActor string
+
+const (
+	ActorManager StatusActor = "manager" // менеджер в Telegram-боте
+	ActorAPI     StatusActor = "api"     // внешний клиент HTTP/gRPC API
+	ActorUser    StatusActor = "user"    // клиент в Telegram-боте
+	ActorSystem  StatusActor = "system"  // фоновые задачи и миграции
+)
+
+// Actor описывает инициатора изменения заявки.
+type Actor struct {
+	Type StatusActor
+	ID   int64  // Telegram ID пользователя или менеджера
+	Name string // имя API-клиента или задачи
+}
+
+type actorKey struct{}
+
+// WithActor сохраняет инициатора изменений в контексте.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext возвращает инициатора изменений; по умолчанию — система.
+// Правила менеджера действуют только при явно заданном ActorManager.
+func ActorFromContext(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(actorKey{}).(Actor); ok {
+		return actor
+	}
+	return Actor{Type: ActorSystem}
+}
+
+// WithStatusActor задает правила переходов статусов для вызовов сервиса.
+func WithStatusActor(ctx context.Context, actor StatusActor) context.Context {
+	current := ActorFromContext(ctx)
+	current.Type = actor
+	return WithActor(ctx, current)
+}
+
+// StatusActorFromContext возвращает актора, заданного через WithStatusActor.
+func StatusActorFromContext(ctx context.Context) StatusActor {
+	return ActorFromContext(ctx).Type
+}
+
+var (
+	ErrInvalidTransition = errors.New("invalid booking status transition")
+	ErrUnknownStatus     = errors.New("unknown booking status")
+)
+
+// TransitionError описывает запрещенный переход статуса.
+type TransitionError struct {
+	Actor StatusActor
+	From  string
+	To    string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: %s -> %s (%s)", ErrInvalidTransition, e.From, e.To, e.Actor)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+// UnknownStatusError возвращается для заявок с нестандартным статусом (например, после миграции).
+type UnknownStatusError struct {
+	Status string
+}
+
+func (e *UnknownStatusError) Error() string {
+	return fmt.Sprintf("%s: %q", ErrUnknownStatus, e.Status)
+}
+
+func (e *UnknownStatusError) Unwrap() error {
+	return ErrUnknownStatus
+}
+
+// BookingStatuses перечисляет все допустимые статусы заявки.
+var BookingStatuses = []string{StatusPending, StatusConfirmed, StatusChanged, StatusCanceled, StatusCompleted}
+
+// managerTransitions — переходы, доступные менеджеру в боте.
+// canceled и completed — конечные статусы.
+var managerTransitions = map[string][]string{
+	StatusPending:   {StatusConfirmed, StatusCanceled, StatusChanged},
+	StatusChanged:   {StatusConfirmed, StatusCanceled, StatusChanged, StatusPending},
+	StatusConfirmed: {StatusPending, StatusCompleted, StatusCanceled, StatusChanged},
+	StatusCanceled:  {},
+	StatusCompleted: {},
+}
+
+// apiTransitions — переходы, доступные API-клиентам, пользователям и фоновым задачам:
+// без завершения и возврата в работу.
+var apiTransitions = map[string][]string{
+	StatusPending:   {StatusConfirmed, StatusCanceled, StatusChanged},
+	StatusChanged:   {StatusConfirmed, StatusCanceled, StatusChanged},
+	StatusConfirmed: {StatusCanceled, StatusChanged},
+	StatusCanceled:  {},
+	StatusCompleted: {},
+}
+
+// IsKnownStatus сообщает, входит ли статус в таблицу переходов.
+func IsKnownStatus(status string) bool {
+	_, ok := managerTransitions[status]
+	return ok
+}
+
+// AllowedTransitions возвращает статусы, в которые actor может перевести заявку из from.
+func AllowedTransitions(actor StatusActor, from string) []string {
+	if actor == ActorManager {
+		return managerTransitions[from]
+	}
+	return apiTransitions[from]
+}
+
+// CheckStatusTransition проверяет переход from -> to по правилам actor.
+func CheckStatusTransition(actor StatusActor, from, to string) error {
+	if !IsKnownStatus(from) {
+		return &UnknownStatusError{Status: from}
+	}
+	if !IsKnownStatus(to) {
+		return &UnknownStatusError{Status: to}
+	}
+	for _, allowed := range AllowedTransitions(actor, from) {
+		if allowed == to {
+			return nil
+		}
+	}
+	return &TransitionError{Actor: actor, From: from, To: to}
+}
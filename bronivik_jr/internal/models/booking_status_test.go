@@ -0,0 +1,62 @@
+package models
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckStatusTransition(t *testing.T) {
+	tests := []struct {
+		name    string
+		actor   StatusActor
+		from    string
+		to      string
+		wantErr error
+	}{
+		{"manager confirms pending", ActorManager, StatusPending, StatusConfirmed, nil},
+		{"manager reopens confirmed", ActorManager, StatusConfirmed, StatusPending, nil},
+		{"manager completes confirmed", ActorManager, StatusConfirmed, StatusCompleted, nil},
+		{"manager completes canceled", ActorManager, StatusCanceled, StatusCompleted, ErrInvalidTransition},
+		{"manager reopens completed", ActorManager, StatusCompleted, StatusPending, ErrInvalidTransition},
+		{"manager completes pending", ActorManager, StatusPending, StatusCompleted, ErrInvalidTransition},
+		{"api cancels confirmed", ActorAPI, StatusConfirmed, StatusCanceled, nil},
+		{"api reopens confirmed", ActorAPI, StatusConfirmed, StatusPending, ErrInvalidTransition},
+		{"api completes confirmed", ActorAPI, StatusConfirmed, StatusCompleted, ErrInvalidTransition},
+		{"api cancels completed", ActorAPI, StatusCompleted, StatusCanceled, ErrInvalidTransition},
+		{"system cancels pending", ActorSystem, StatusPending, StatusCanceled, nil},
+		{"system reopens confirmed", ActorSystem, StatusConfirmed, StatusPending, ErrInvalidTransition},
+		{"legacy status", ActorManager, "approved", StatusCanceled, ErrUnknownStatus},
+		{"unknown target", ActorManager, StatusPending, "rescheduled", ErrUnknownStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckStatusTransition(tt.actor, tt.from, tt.to)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTransitionError_Message(t *testing.T) {
+	err := CheckStatusTransition(ActorAPI, StatusCanceled, StatusConfirmed)
+	var trErr *TransitionError
+	assert.ErrorAs(t, err, &trErr)
+	assert.Equal(t, StatusCanceled, trErr.From)
+	assert.Equal(t, StatusConfirmed, trErr.To)
+	assert.Equal(t, ActorAPI, trErr.Actor)
+}
+
+func TestActorFromContextDefaultsToSystem(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, Actor{Type: ActorSystem}, ActorFromContext(ctx))
+
+	ctx = WithActor(ctx, Actor{Type: ActorManager, ID: 42})
+	assert.Equal(t, ActorManager, StatusActorFromContext(ctx))
+	assert.Equal(t, int64(42), ActorFromContext(WithStatusActor(ctx, ActorAPI)).ID)
+}
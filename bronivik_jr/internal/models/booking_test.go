@@ -346,3 +346,33 @@ func TestBooking_ContainsDate(t *testing.T) {
 		})
 	}
 }
+
+func TestBooking_CoveredDates(t *testing.T) {
+	tests := []struct {
+		name    string
+		booking Booking
+		want    []time.Time
+	}{
+		{
+			name:    "single day booking",
+			booking: Booking{Date: day(15)},
+			want:    []time.Time{day(15)},
+		},
+		{
+			name:    "range booking",
+			booking: Booking{Date: day(15), EndTime: dayPtr(17)},
+			want:    []time.Time{day(15), day(16), day(17)},
+		},
+		{
+			name:    "end before start",
+			booking: Booking{Date: day(15), EndTime: dayPtr(14)},
+			want:    []time.Time{day(15)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.booking.CoveredDates())
+		})
+	}
+}
@@ -8,6 +8,35 @@ const (
 	StatusCompleted = "completed"
 )
 
+// Правила повторения и статусы серий бронирований
+const (
+	RecurrenceWeekly  = "weekly"
+	RecurrenceMonthly = "monthly"
+
+	SeriesStatusActive   = "active"
+	SeriesStatusCanceled = "canceled"
+)
+
+// Статусы записи в листе ожидания
+const (
+	WaitlistStatusWaiting  = "waiting"
+	WaitlistStatusOffered  = "offered"
+	WaitlistStatusAccepted = "accepted"
+	WaitlistStatusDeclined = "declined"
+	WaitlistStatusExpired  = "expired"
+)
+
+// Согласие на обработку персональных данных
+const (
+	// PrivacyPolicyVersion версия текста политики (privacy_policy в каталогах сообщений).
+	// После изменения текста версию нужно поднять — пользователи дадут согласие заново.
+	PrivacyPolicyVersion = "2026-10"
+
+	ConsentActionGiven   = "given"
+	ConsentActionRevoked = "revoked"
+	ConsentActionErased  = "erased"
+)
+
 const (
 	ParseModeMarkdown = "Markdown"
 	ParseModeHTML     = "HTML"
@@ -20,10 +49,12 @@ const (
 	StateViewSchedule        = "view_schedule"
 	StatePersonalData        = "personal_data"
 	StateEnterName           = "enter_name"
+	StateConsent             = "consent"
 	StatePhoneNumber         = "phone_number"
 	StateConfirmation        = "confirmation"
 	StateWaitingDate         = "waiting_date"
 	StateWaitingSpecificDate = "waiting_specific_date"
+	StateWaitlistOffer       = "waitlist_offer"
 
 	// Manager States
 	StateManagerWaitingClientName    = "manager_waiting_client_name"
@@ -33,7 +64,11 @@ const (
 	StateManagerWaitingSingleDate    = "manager_waiting_single_date"
 	StateManagerWaitingStartDate     = "manager_waiting_start_date"
 	StateManagerWaitingEndDate       = "manager_waiting_end_date"
+	StateManagerWaitingSeriesStart   = "manager_waiting_series_start"
+	StateManagerWaitingSeriesRule    = "manager_waiting_series_rule"
+	StateManagerWaitingSeriesEnd     = "manager_waiting_series_end"
 	StateManagerWaitingComment       = "manager_waiting_comment"
+	StateManagerWaitingReschedule    = "manager_waiting_reschedule"
 	StateManagerConfirmBooking       = "manager_confirm_booking"
 )
 
@@ -68,4 +103,22 @@ const (
 
 	// SheetsCacheTTL время жизни кэша строк Google Sheets
 	SheetsCacheTTL = 60 * 60 // 1 час в секундах
+
+	// MaxSeriesOccurrences максимальное количество повторений в серии
+	MaxSeriesOccurrences = 104 // 2 года еженедельно
+
+	// WaitlistOfferTTL время на принятие предложения из листа ожидания
+	WaitlistOfferTTL = 2 * 60 * 60 // 2 часа в секундах
+
+	// WaitlistCheckInterval период проверки просроченных предложений
+	WaitlistCheckInterval = 60 // 1 минута в секундах
+
+	// UpdateWorkers число воркеров, параллельно обрабатывающих обновления Telegram
+	UpdateWorkers = 8
+
+	// UpdateQueueSize размер очереди обновлений каждого воркера
+	UpdateQueueSize = 100
+
+	// UpdateDrainTimeout время на дообработку принятых обновлений при остановке
+	UpdateDrainTimeout = 30 // в секундах
 )
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// IdempotencyRecord — сохраненный ответ на изменяющий запрос API с ключом идемпотентности.
+// Повтор запроса с тем же ключом получает этот ответ вместо повторного выполнения.
+type IdempotencyRecord struct {
+	Scope       string // хеш клиента API: ключи разных клиентов не пересекаются
+	Key         string
+	Fingerprint string // хеш метода, пути и тела запроса
+	StatusCode  int    // 0 — запрос еще выполняется
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// InProgress сообщает, что первый запрос с этим ключом еще не получил ответ.
+func (r *IdempotencyRecord) InProgress() bool {
+	return r.StatusCode == 0
+}
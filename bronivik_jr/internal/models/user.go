@@ -22,4 +22,10 @@ type User struct {
 	ConsentGivenAt   sql.NullTime // Когда было дано согласие
 	ConsentRevoked   bool         `gorm:"default:false"` // Отозвано ли согласие
 	ConsentRevokedAt sql.NullTime // Когда было отозвано
+	ConsentVersion   string       `gorm:"size:32"` // Версия политики, с которой согласился пользователь
+}
+
+// HasConsent сообщает, что пользователь согласился с политикой версии version и не отозвал согласие.
+func (u *User) HasConsent(version string) bool {
+	return u.ConsentGiven && !u.ConsentRevoked && u.ConsentVersion == version
 }
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// UserDataExport holds everything the service stores about one Telegram user.
+type UserDataExport struct {
+	Service     string            `json:"service"`
+	TelegramID  int64             `json:"telegram_id"`
+	GeneratedAt time.Time         `json:"generated_at"`
+	Sections    []UserDataSection `json:"sections"`
+}
+
+// UserDataSection contains the rows of one table that belong to the user.
+type UserDataSection struct {
+	Name    string                   `json:"name"`
+	Columns []string                 `json:"columns"`
+	Rows    []map[string]interface{} `json:"rows"`
+}
+
+// IsEmpty reports whether no data about the user was found.
+func (e *UserDataExport) IsEmpty() bool {
+	for _, s := range e.Sections {
+		if len(s.Rows) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ErasureResult describes what erasing a user's personal data changed.
+type ErasureResult struct {
+	TelegramID  int64   `json:"telegram_id"`
+	PseudonymID int64   `json:"pseudonym_id"`
+	Pseudonym   string  `json:"pseudonym"`
+	BookingIDs  []int64 `json:"booking_ids"`
+	DeletedRows int64   `json:"deleted_rows"`
+}
+
+// ErasedUserPseudonym returns the name that replaces the user's name in kept bookings.
+func ErasedUserPseudonym(userRowID int64) string {
+	return fmt.Sprintf("deleted-%d", userRowID)
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// WaitlistEntry represents a user waiting for a fully booked item on a given date.
+type WaitlistEntry struct {
+	ID             int64      `json:"id"`
+	UserID         int64      `json:"user_id"`
+	ChatID         int64      `json:"chat_id"`
+	UserName       string     `json:"user_name"`
+	UserNickname   string     `json:"user_nickname"`
+	Phone          string     `json:"phone"`
+	ItemID         int64      `json:"item_id"`
+	ItemName       string     `json:"item_name"`
+	Date           time.Time  `json:"date"`
+	Status         string     `json:"status"`
+	OfferExpiresAt *time.Time `json:"offer_expires_at,omitempty"`
+	BookingID      *int64     `json:"booking_id,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// IsOfferExpired reports whether the offer made to the entry has run out at the given moment.
+func (w *WaitlistEntry) IsOfferExpired(now time.Time) bool {
+	return w.Status == WaitlistStatusOffered && w.OfferExpiresAt != nil && now.After(*w.OfferExpiresAt)
+}
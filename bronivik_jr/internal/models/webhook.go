@@ -0,0 +1,62 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Статусы доставки вебхука.
+const (
+	WebhookDeliveryPending   = "pending"   // ожидает отправки или повтора
+	WebhookDeliveryDelivered = "delivered" // получатель ответил 2xx
+	WebhookDeliveryDead      = "dead"      // попытки исчерпаны, доступна повторная отправка вручную
+)
+
+var (
+	ErrInvalidWebhookURL       = errors.New("webhook url must be an absolute http(s) url")
+	ErrInvalidWebhookEventType = errors.New("unknown webhook event type")
+	ErrInvalidWebhookStatus    = errors.New("unknown webhook delivery status")
+)
+
+// WebhookSubscription — подписка внешней системы на события по заявкам.
+type WebhookSubscription struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Secret     string    `json:"secret,omitempty"` // отдается только при создании
+	IsActive   bool      `json:"is_active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Matches сообщает, подписана ли подписка на eventType.
+func (s *WebhookSubscription) Matches(eventType string) bool {
+	for _, t := range s.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery — одна попытка доставки события подписчику (журнал доставок).
+type WebhookDelivery struct {
+	ID             int64      `json:"id"`
+	SubscriptionID int64      `json:"subscription_id"`
+	EventID        int64      `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Payload        string     `json:"-"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	LastError      string     `json:"last_error,omitempty"`
+	ResponseCode   int        `json:"response_code,omitempty"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// Заполняются при выборке на отправку
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
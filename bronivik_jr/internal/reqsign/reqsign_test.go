@@ -0,0 +1,101 @@
+package reqsign
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1_700_000_000, 0)
+	v := NewVerifier(time.Minute, nil)
+	v.now = func() time.Time { return now }
+
+	body := []byte(`{"date":"2026-01-01"}`)
+	h, err := SignRequest("secret", "post", "/api/book-device?x=1", body, now)
+	if err != nil {
+		t.Fatalf("SignRequest: %v", err)
+	}
+	if err := v.Verify(ctx, "secret", "POST", "/api/book-device?x=1", BodyHash(body), h); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	// The same request again is a replay
+	if err := v.Verify(ctx, "secret", "POST", "/api/book-device?x=1", BodyHash(body), h); !errors.Is(err, ErrReplay) {
+		t.Fatalf("replay: got %v", err)
+	}
+
+	// Any change to the signed parts breaks the signature
+	h, _ = SignRequest("secret", "POST", "/api/book-device", body, now)
+	cases := []struct {
+		secret, method, path string
+		body                 []byte
+	}{
+		{"other", "POST", "/api/book-device", body},
+		{"secret", "DELETE", "/api/book-device", body},
+		{"secret", "POST", "/api/book-device?x=2", body},
+		{"secret", "POST", "/api/book-device", []byte(`{"date":"2026-01-02"}`)},
+	}
+	for _, tc := range cases {
+		if err := v.Verify(ctx, tc.secret, tc.method, tc.path, BodyHash(tc.body), h); !errors.Is(err, ErrBadSignature) {
+			t.Fatalf("%s %s: got %v", tc.method, tc.path, err)
+		}
+	}
+	// A rejected signature does not burn the nonce
+	if err := v.Verify(ctx, "secret", "POST", "/api/book-device", BodyHash(body), h); err != nil {
+		t.Fatalf("valid after rejected: %v", err)
+	}
+}
+
+func TestVerifyRejectsStaleAndIncomplete(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1_700_000_000, 0)
+	v := NewVerifier(time.Minute, nil)
+	v.now = func() time.Time { return now }
+
+	for _, sent := range []time.Time{now.Add(-2 * time.Minute), now.Add(2 * time.Minute)} {
+		h, _ := SignRequest("secret", "GET", "/api/v1/items", nil, sent)
+		if err := v.Verify(ctx, "secret", "GET", "/api/v1/items", BodyHash(nil), h); !errors.Is(err, ErrTimestamp) {
+			t.Fatalf("skew %v: got %v", sent.Sub(now), err)
+		}
+	}
+
+	h, _ := SignRequest("secret", "GET", "/api/v1/items", nil, now)
+	if err := v.Verify(ctx, "", "GET", "/api/v1/items", BodyHash(nil), h); !errors.Is(err, ErrNoSecret) {
+		t.Fatalf("no secret: got %v", err)
+	}
+	h.Nonce = ""
+	if err := v.Verify(ctx, "secret", "GET", "/api/v1/items", BodyHash(nil), h); !errors.Is(err, ErrMissing) {
+		t.Fatalf("no nonce: got %v", err)
+	}
+	if !(Headers{}).Empty() || h.Empty() {
+		t.Fatalf("Empty mismatch")
+	}
+	if _, err := strconv.Atoi(h.Timestamp); err != nil {
+		t.Fatalf("timestamp must be unix seconds, got %q", h.Timestamp)
+	}
+}
+
+func TestMemoryNonceCacheExpires(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1_700_000_000, 0)
+	c := NewMemoryNonceCache()
+	c.now = func() time.Time { return now }
+
+	if ok, _ := c.Add(ctx, "n1", time.Minute); !ok {
+		t.Fatalf("first add must succeed")
+	}
+	if ok, _ := c.Add(ctx, "n1", time.Minute); ok {
+		t.Fatalf("second add must fail")
+	}
+	now = now.Add(time.Minute)
+	if ok, _ := c.Add(ctx, "n2", time.Minute); !ok || len(c.seen) != 1 {
+		t.Fatalf("expired nonce must be pruned, have %d", len(c.seen))
+	}
+	if ok, _ := c.Add(ctx, "n1", time.Minute); !ok {
+		t.Fatalf("expired nonce may be reused")
+	}
+}
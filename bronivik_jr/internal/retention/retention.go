@@ -0,0 +1,394 @@
+// Package retention applies a retention policy to personal data: per table and
+// per column rules that clear or hash values, or delete whole rows, once the
+// rows reach a given age.
+//
+// Each bot describes which tables and columns rules may touch with a catalog,
+// so table and column names in a policy never reach SQL unchecked. Every run
+// that changes data records what it touched in a log table that is part of
+// the audit export; a dry run only counts the rows.
+//
+// The canonical source is shared/retention; bronivik_jr and bronivik_crm keep
+// identical copies in internal/retention because they are built as separate modules.
+package retention
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Actions a rule can take.
+const (
+	// ActionClear empties the column, as erasing user data does.
+	ActionClear = "clear"
+	// ActionHash replaces the value with a keyed hash, so equal values stay
+	// comparable in statistics while the value itself is gone.
+	ActionHash = "hash"
+	// ActionDelete deletes whole rows.
+	ActionDelete = "delete"
+)
+
+// HashPrefix marks values replaced by ActionHash. Hashes are not personal
+// data and are stored as is, even when field encryption is enabled.
+const HashPrefix = "anon:"
+
+// DefaultLogTable is the table that records what each run touched.
+const DefaultLogTable = "retention_log"
+
+const defaultBatchSize = 500
+
+// ErrNoHashKey is returned by New for a hash rule without Options.HashKey.
+var ErrNoHashKey = errors.New("hash rules require a hash key")
+
+var identRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// DB is the part of *sql.DB the engine uses. Queries are written with "?"
+// placeholders; a wrapper may rewrite them for other dialects.
+type DB interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Rule is one line of the policy, e.g. "clear bookings.phone after 180 days".
+type Rule struct {
+	Table  string
+	Column string // empty for ActionDelete
+	Action string
+	After  time.Duration
+}
+
+func (r Rule) String() string {
+	target := r.Table
+	if r.Column != "" {
+		target += "." + r.Column
+	}
+	return fmt.Sprintf("%s %s after %s", r.Action, target, formatAge(r.After))
+}
+
+// Table describes a table rules may touch.
+type Table struct {
+	Name string
+	// Age is the column or SQL expression a row's age is counted from,
+	// e.g. "COALESCE(end_time, date)" for bookings.
+	Age string
+	// Where is an extra SQL condition; rows that do not match are never touched.
+	Where string
+	// Columns lists the personal data columns rules may clear or hash.
+	Columns map[string]Column
+	// Deletable allows ActionDelete on the table.
+	Deletable bool
+}
+
+// Column describes a personal data column.
+type Column struct {
+	// Derived columns are computed from this one (e.g. a phone blind index)
+	// and are cleared whenever the column is cleared or hashed.
+	Derived []string
+}
+
+// Options configure an Engine.
+type Options struct {
+	// LogTable defaults to DefaultLogTable.
+	LogTable string
+	// HashKey keys the HMAC used by ActionHash and is required by hash rules:
+	// an unkeyed hash of a phone or name is reversed by brute force.
+	HashKey []byte
+	// Decrypt returns the plaintext of a stored value before it is hashed, so
+	// encrypted values hash the same as plaintext ones. Values are hashed as
+	// stored when nil.
+	Decrypt func(string) (string, error)
+	// BatchSize is the number of rows hashed per query, 500 by default.
+	BatchSize int
+	// Now defaults to time.Now.
+	Now func() time.Time
+}
+
+// Result is the outcome of one rule.
+type Result struct {
+	Rule   Rule
+	Cutoff time.Time
+	// Rows is the number of rows changed, or that would be changed in a dry run.
+	Rows int64
+}
+
+// Report is the outcome of a run.
+type Report struct {
+	StartedAt time.Time
+	DryRun    bool
+	Results   []Result
+}
+
+// Total returns the number of rows touched by all rules.
+func (r *Report) Total() int64 {
+	var n int64
+	for _, res := range r.Results {
+		n += res.Rows
+	}
+	return n
+}
+
+// WriteReport prints the report as a table, one line per rule.
+func WriteReport(w io.Writer, r *Report) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TABLE\tCOLUMN\tACTION\tAFTER\tOLDER THAN\tROWS")
+	for _, res := range r.Results {
+		column := res.Rule.Column
+		if column == "" {
+			column = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\n", res.Rule.Table, column, res.Rule.Action,
+			formatAge(res.Rule.After), res.Cutoff.Format("2006-01-02 15:04"), res.Rows)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	verb := "touched"
+	if r.DryRun {
+		verb = "would be touched (dry run)"
+	}
+	_, err := fmt.Fprintf(w, "%d row(s) %s\n", r.Total(), verb)
+	return err
+}
+
+// Engine applies a policy to a database.
+type Engine struct {
+	db     DB
+	tables map[string]Table
+	rules  []Rule
+	opts   Options
+}
+
+// New validates the rules against the catalog and creates an engine.
+func New(db DB, catalog []Table, rules []Rule, opts Options) (*Engine, error) {
+	if opts.LogTable == "" {
+		opts.LogTable = DefaultLogTable
+	}
+	if !identRe.MatchString(opts.LogTable) {
+		return nil, fmt.Errorf("invalid retention log table name %q", opts.LogTable)
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+
+	tables := make(map[string]Table, len(catalog))
+	for _, t := range catalog {
+		tables[t.Name] = t
+	}
+	for i, r := range rules {
+		if err := validateRule(tables, r); err != nil {
+			return nil, fmt.Errorf("retention rule %d (%s): %w", i+1, r, err)
+		}
+		if r.Action == ActionHash && len(opts.HashKey) == 0 {
+			return nil, fmt.Errorf("retention rule %d (%s): %w", i+1, r, ErrNoHashKey)
+		}
+	}
+	return &Engine{db: db, tables: tables, rules: rules, opts: opts}, nil
+}
+
+func validateRule(tables map[string]Table, r Rule) error {
+	t, ok := tables[r.Table]
+	if !ok {
+		return fmt.Errorf("table %q is not covered by the retention policy", r.Table)
+	}
+	if r.After <= 0 {
+		return fmt.Errorf("age must be positive")
+	}
+	switch r.Action {
+	case ActionClear, ActionHash:
+		if _, ok := t.Columns[r.Column]; !ok {
+			return fmt.Errorf("column %q of %s is not a personal data column", r.Column, r.Table)
+		}
+	case ActionDelete:
+		if r.Column != "" {
+			return fmt.Errorf("delete removes whole rows, column must be empty")
+		}
+		if !t.Deletable {
+			return fmt.Errorf("rows of %s cannot be deleted", r.Table)
+		}
+	default:
+		return fmt.Errorf("unknown action %q", r.Action)
+	}
+	return nil
+}
+
+// Rules returns the policy of the engine.
+func (e *Engine) Rules() []Rule {
+	return e.rules
+}
+
+// Run applies the rules in order. With dryRun it only counts the rows each
+// rule would touch. On error the report holds the rules completed so far.
+func (e *Engine) Run(ctx context.Context, dryRun bool) (*Report, error) {
+	report := &Report{StartedAt: e.opts.Now(), DryRun: dryRun}
+	for _, r := range e.rules {
+		res := Result{Rule: r, Cutoff: report.StartedAt.Add(-r.After)}
+		var err error
+		if dryRun {
+			res.Rows, err = e.count(ctx, r, res.Cutoff)
+		} else {
+			res.Rows, err = e.apply(ctx, r, res.Cutoff)
+		}
+		if err != nil {
+			return report, fmt.Errorf("%s: %w", r, err)
+		}
+		report.Results = append(report.Results, res)
+
+		if !dryRun && res.Rows > 0 {
+			if err := e.record(ctx, report.StartedAt, res); err != nil {
+				return report, fmt.Errorf("record %s: %w", r, err)
+			}
+		}
+	}
+	return report, nil
+}
+
+// filter returns the WHERE clause selecting rows the rule still has to touch.
+func (e *Engine) filter(r Rule, cutoff time.Time) (string, []interface{}) {
+	t := e.tables[r.Table]
+	where := "(" + t.Age + ") < ?"
+	args := []interface{}{cutoff}
+	if t.Where != "" {
+		where += " AND (" + t.Where + ")"
+	}
+	switch r.Action {
+	case ActionClear:
+		where += " AND " + r.Column + " IS NOT NULL AND " + r.Column + " <> ''"
+	case ActionHash:
+		where += " AND " + r.Column + " IS NOT NULL AND " + r.Column + " <> '' AND " + r.Column + " NOT LIKE ?"
+		args = append(args, HashPrefix+"%")
+	}
+	return where, args
+}
+
+func (e *Engine) count(ctx context.Context, r Rule, cutoff time.Time) (int64, error) {
+	where, args := e.filter(r, cutoff)
+	rows, err := e.db.QueryContext(ctx, `SELECT COUNT(*) FROM `+r.Table+` WHERE `+where, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	var n int64
+	if rows.Next() {
+		if err := rows.Scan(&n); err != nil {
+			return 0, err
+		}
+	}
+	return n, rows.Err()
+}
+
+func (e *Engine) apply(ctx context.Context, r Rule, cutoff time.Time) (int64, error) {
+	where, args := e.filter(r, cutoff)
+	switch r.Action {
+	case ActionDelete:
+		return e.exec(ctx, `DELETE FROM `+r.Table+` WHERE `+where, args...)
+	case ActionClear:
+		col := e.tables[r.Table].Columns[r.Column]
+		return e.exec(ctx, `UPDATE `+r.Table+` SET `+r.Column+` = ''`+derivedSet(col)+` WHERE `+where, args...)
+	default:
+		return e.hash(ctx, r, where, args)
+	}
+}
+
+func (e *Engine) exec(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	res, err := e.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// hash rewrites values row by row: the hash is computed from the plaintext,
+// which SQL cannot see when the column is encrypted.
+func (e *Engine) hash(ctx context.Context, r Rule, where string, args []interface{}) (int64, error) {
+	col := e.tables[r.Table].Columns[r.Column]
+	update := `UPDATE ` + r.Table + ` SET ` + r.Column + ` = ?` + derivedSet(col) + ` WHERE id = ? AND ` + r.Column + ` = ?`
+	query := `SELECT id, ` + r.Column + ` FROM ` + r.Table + ` WHERE ` + where + ` ORDER BY id LIMIT ?`
+
+	var total int64
+	for {
+		type pending struct {
+			id    int64
+			value string
+		}
+		rows, err := e.db.QueryContext(ctx, query, append(args, e.opts.BatchSize)...)
+		if err != nil {
+			return total, err
+		}
+		var batch []pending
+		for rows.Next() {
+			var p pending
+			if err := rows.Scan(&p.id, &p.value); err != nil {
+				rows.Close()
+				return total, err
+			}
+			batch = append(batch, p)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return total, err
+		}
+
+		var changed int64
+		for _, p := range batch {
+			plain := p.value
+			if e.opts.Decrypt != nil {
+				if plain, err = e.opts.Decrypt(p.value); err != nil {
+					return total, fmt.Errorf("row %d: %w", p.id, err)
+				}
+			}
+			// Rows changed concurrently are skipped and picked up by the next run
+			n, err := e.exec(ctx, update, e.hashValue(plain), p.id, p.value)
+			if err != nil {
+				return total, err
+			}
+			changed += n
+		}
+		total += changed
+		if len(batch) < e.opts.BatchSize || changed == 0 {
+			return total, nil
+		}
+	}
+}
+
+func (e *Engine) hashValue(v string) string {
+	mac := hmac.New(sha256.New, e.opts.HashKey)
+	mac.Write([]byte(v))
+	return HashPrefix + hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+func derivedSet(col Column) string {
+	var b strings.Builder
+	for _, d := range col.Derived {
+		b.WriteString(", " + d + " = ''")
+	}
+	return b.String()
+}
+
+func (e *Engine) record(ctx context.Context, runAt time.Time, res Result) error {
+	_, err := e.db.ExecContext(ctx, `INSERT INTO `+e.opts.LogTable+`
+		(run_at, table_name, column_name, action, cutoff, rows_affected)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		runAt, res.Rule.Table, res.Rule.Column, res.Rule.Action, res.Cutoff, res.Rows)
+	return err
+}
+
+// formatAge prints whole days as "180d" and anything else as a duration.
+func formatAge(d time.Duration) string {
+	const day = 24 * time.Hour
+	if d > 0 && d%day == 0 {
+		return fmt.Sprintf("%dd", d/day)
+	}
+	return d.String()
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"bronivik/internal/domain"
+	"bronivik/internal/models"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	apiKeyTokenPrefix        = "bk_"
+	apiKeyBytes              = 24
+	apiKeyShownPrefix        = len(apiKeyTokenPrefix) + 8
+	apiKeySigningSecretBytes = 32
+)
+
+// APIKeyService выпускает, ротирует и отзывает ключи клиентов API.
+type APIKeyService struct {
+	repo         domain.APIKeyRepository
+	defaultGrace time.Duration
+	logger       *zerolog.Logger
+}
+
+// NewAPIKeyService создает сервис; defaultGrace — сколько старый ключ работает после ротации,
+// если в запросе срок не указан.
+func NewAPIKeyService(repo domain.APIKeyRepository, defaultGrace time.Duration, logger *zerolog.Logger) *APIKeyService {
+	return &APIKeyService{repo: repo, defaultGrace: defaultGrace, logger: logger}
+}
+
+// CreateKey выпускает ключ. Открытый ключ и секрет подписи есть только в результате этого вызова.
+func (s *APIKeyService) CreateKey(
+	ctx context.Context,
+	name string,
+	permissions []string,
+	expiresAt *time.Time,
+	limits models.APIKeyLimits,
+) (*models.APIKey, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, models.ErrInvalidAPIKeyName
+	}
+	perms, err := normalizeAPIKeyPermissions(permissions)
+	if err != nil {
+		return nil, err
+	}
+	// Ключ из базы без разрешений ничего не может: такой ключ выпускать бессмысленно
+	if len(perms) == 0 {
+		return nil, models.ErrNoAPIKeyPermissions
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, models.ErrInvalidAPIKeyExpiry
+	}
+	if err := limits.Validate(); err != nil {
+		return nil, err
+	}
+
+	key, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	key.Name = name
+	key.Permissions = perms
+	key.ExpiresAt = expiresAt
+	key.APIKeyLimits = limits
+	if err := s.repo.CreateAPIKey(ctx, key); err != nil {
+		return nil, err
+	}
+	s.logger.Info().Int64("api_key_id", key.ID).Str("name", key.Name).Strs("permissions", perms).Msg("api key created")
+	return key, nil
+}
+
+// normalizeAPIKeyPermissions проверяет разрешения и убирает дубли.
+func normalizeAPIKeyPermissions(permissions []string) ([]string, error) {
+	known := make(map[string]bool, len(models.APIKeyPermissions))
+	for _, p := range models.APIKeyPermissions {
+		known[p] = true
+	}
+
+	seen := make(map[string]bool, len(permissions))
+	perms := []string{}
+	for _, p := range permissions {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if !known[p] {
+			return nil, fmt.Errorf("%w: %q", models.ErrInvalidAPIKeyPermission, p)
+		}
+		if !seen[p] {
+			seen[p] = true
+			perms = append(perms, p)
+		}
+	}
+	return perms, nil
+}
+
+func newAPIKey() (*models.APIKey, error) {
+	raw := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("generate api key: %w", err)
+	}
+	secret := make([]byte, apiKeySigningSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("generate api key signing secret: %w", err)
+	}
+	token := apiKeyTokenPrefix + hex.EncodeToString(raw)
+	return &models.APIKey{
+		Key:           token,
+		Hash:          models.HashAPIKey(token),
+		Prefix:        token[:apiKeyShownPrefix],
+		SigningSecret: hex.EncodeToString(secret),
+	}, nil
+}
+
+// ListKeys возвращает ключи без секретов подписи: после выпуска их не показываем.
+func (s *APIKeyService) ListKeys(ctx context.Context) ([]*models.APIKey, error) {
+	keys, err := s.repo.GetAPIKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range keys {
+		k.SigningSecret = ""
+	}
+	return keys, nil
+}
+
+// RotateKey выпускает замену ключа id; старый ключ принимается еще grace
+// (defaultGrace, если grace не положителен), чтобы клиенты успели переключиться.
+func (s *APIKeyService) RotateKey(ctx context.Context, id int64, grace time.Duration) (*models.APIKey, error) {
+	if grace <= 0 {
+		grace = s.defaultGrace
+	}
+	key, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	graceUntil := time.Now().Add(grace)
+	if err := s.repo.RotateAPIKey(ctx, id, key, graceUntil); err != nil {
+		return nil, err
+	}
+	s.logger.Info().Int64("api_key_id", id).Int64("new_api_key_id", key.ID).Time("grace_until", graceUntil).Msg("api key rotated")
+	return key, nil
+}
+
+// SetKeyLimits меняет собственные лимиты ключа; нулевые поля возвращают общий лимит.
+func (s *APIKeyService) SetKeyLimits(ctx context.Context, id int64, limits models.APIKeyLimits) error {
+	if err := limits.Validate(); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateAPIKeyLimits(ctx, id, limits); err != nil {
+		return err
+	}
+	s.logger.Info().Int64("api_key_id", id).Float64("rps", limits.RateLimitRPS).Int("burst", limits.RateLimitBurst).
+		Int("daily_quota", limits.DailyQuota).Msg("api key limits updated")
+	return nil
+}
+
+// SetKeyClientCert закрепляет за ключом сертификат клиента mTLS: с ним ключ принимается только
+// вместе с сертификатом, а сам сертификат заменяет ключ. Пустой cn снимает привязку.
+func (s *APIKeyService) SetKeyClientCert(ctx context.Context, id int64, cn string) error {
+	cn = strings.TrimSpace(cn)
+	if err := s.repo.UpdateAPIKeyClientCert(ctx, id, cn); err != nil {
+		return err
+	}
+	s.logger.Info().Int64("api_key_id", id).Str("client_cert_cn", cn).Msg("api key client certificate updated")
+	return nil
+}
+
+func (s *APIKeyService) RevokeKey(ctx context.Context, id int64) error {
+	if err := s.repo.RevokeAPIKey(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info().Int64("api_key_id", id).Msg("api key revoked")
+	return nil
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"bronivik/internal/database"
+	"bronivik/internal/models"
+)
+
+// CreateBookingSeries создает серию бронирований по правилу повторения.
+// Даты, не прошедшие валидацию или занятые, попадают в Conflicts, остальные
+// бронируются в одной транзакции. Если не создано ни одного бронирования,
+// возвращается результат с конфликтами и ErrNotAvailable.
+func (s *BookingService) CreateBookingSeries(
+	ctx context.Context,
+	template *models.Booking,
+	rule models.RecurrenceRule,
+) (*models.SeriesResult, error) {
+	dates, err := rule.Occurrences(template.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	series := &models.BookingSeries{
+		UserID:       template.UserID,
+		UserName:     template.UserName,
+		UserNickname: template.UserNickname,
+		Phone:        template.Phone,
+		ItemID:       template.ItemID,
+		ItemName:     template.ItemName,
+		Frequency:    rule.Frequency,
+		Interval:     rule.Interval,
+		StartDate:    dates[0],
+		UntilDate:    rule.Until,
+		Count:        rule.Count,
+		Status:       models.SeriesStatusActive,
+		Comment:      template.Comment,
+	}
+	if series.Interval == 0 {
+		series.Interval = 1
+	}
+
+	result := &models.SeriesResult{Series: series}
+	var bookings []*models.Booking
+	for _, date := range dates {
+		if err := s.ValidateBookingDate(date); err != nil {
+			result.Conflicts = append(result.Conflicts, models.SeriesConflict{Date: date, Reason: err.Error()})
+			continue
+		}
+		booking := *template
+		booking.ID = 0
+		booking.Date = date
+		bookings = append(bookings, &booking)
+	}
+	if len(bookings) == 0 {
+		return result, database.ErrNotAvailable
+	}
+
+	created, conflicts, err := s.repo.CreateBookingSeriesWithLock(ctx, series, bookings)
+	result.Conflicts = append(result.Conflicts, conflicts...)
+	sortConflicts(result.Conflicts)
+	if err != nil {
+		return result, err
+	}
+	result.Bookings = created
+
+	for _, booking := range created {
+		s.enqueueSync(ctx, booking, "upsert")
+	}
+	s.enqueueScheduleSync(ctx)
+
+	return result, nil
+}
+
+// GetBookingSeries возвращает серию и все ее бронирования.
+func (s *BookingService) GetBookingSeries(ctx context.Context, seriesID int64) (*models.BookingSeries, []*models.Booking, error) {
+	series, err := s.repo.GetBookingSeries(ctx, seriesID)
+	if err != nil {
+		return nil, nil, err
+	}
+	bookings, err := s.repo.GetSeriesBookings(ctx, seriesID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return series, bookings, nil
+}
+
+// ConfirmSeries подтверждает все будущие активные бронирования серии.
+func (s *BookingService) ConfirmSeries(ctx context.Context, seriesID, managerID int64) (*models.SeriesResult, error) {
+	return s.applyToSeries(ctx, seriesID, func(b *models.Booking) error {
+		if b.Status == models.StatusConfirmed {
+			return nil
+		}
+		return s.applyStatus(ctx, b, b.Version, models.StatusConfirmed, managerID)
+	})
+}
+
+// CancelSeries отменяет все будущие активные бронирования серии и саму серию.
+func (s *BookingService) CancelSeries(ctx context.Context, seriesID, managerID int64) (*models.SeriesResult, error) {
+	result, err := s.applyToSeries(ctx, seriesID, func(b *models.Booking) error {
+		return s.applyStatus(ctx, b, b.Version, models.StatusCanceled, managerID)
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if err := s.repo.UpdateBookingSeriesStatus(ctx, seriesID, models.SeriesStatusCanceled); err != nil {
+		return result, err
+	}
+	result.Series.Status = models.SeriesStatusCanceled
+	return result, nil
+}
+
+// ChangeSeriesItem переносит будущие активные бронирования серии на другой аппарат.
+// Каждое бронирование переносится в своей транзакции под блокировкой нового аппарата,
+// занятые даты остаются на старом аппарате.
+func (s *BookingService) ChangeSeriesItem(ctx context.Context, seriesID, newItemID, managerID int64) (*models.SeriesResult, error) {
+	item, err := s.repo.GetItemByID(ctx, newItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.applyToSeries(ctx, seriesID, func(b *models.Booking) error {
+		if b.ItemID == newItemID {
+			return nil
+		}
+		return s.ChangeBookingItem(ctx, b.ID, b.Version, newItemID, managerID)
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if err := s.repo.UpdateBookingSeriesItem(ctx, seriesID, item.ID, item.Name); err != nil {
+		return result, err
+	}
+	result.Series.ItemID = item.ID
+	result.Series.ItemName = item.Name
+	return result, nil
+}
+
+// applyToSeries применяет действие к каждому будущему активному бронированию серии
+// и собирает отчет по датам. Ошибка отдельного бронирования не прерывает обработку.
+func (s *BookingService) applyToSeries(
+	ctx context.Context,
+	seriesID int64,
+	action func(b *models.Booking) error,
+) (*models.SeriesResult, error) {
+	series, bookings, err := s.GetBookingSeries(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	if series.Status == models.SeriesStatusCanceled {
+		return &models.SeriesResult{Series: series}, database.ErrSeriesCanceled
+	}
+
+	today := time.Now().Truncate(24 * time.Hour)
+	result := &models.SeriesResult{Series: series}
+	for _, booking := range bookings {
+		if booking.Date.Before(today) || !isActiveSeriesBooking(booking.Status) {
+			continue
+		}
+
+		if err := action(booking); err != nil {
+			s.logger.Warn().Err(err).Int64("series_id", seriesID).Int64("booking_id", booking.ID).Msg("series occurrence update failed")
+			result.Conflicts = append(result.Conflicts, models.SeriesConflict{
+				Date:      booking.Date,
+				BookingID: booking.ID,
+				Reason:    err.Error(),
+			})
+			continue
+		}
+
+		if updated, err := s.repo.GetBooking(ctx, booking.ID); err == nil {
+			booking = updated
+		}
+		result.Bookings = append(result.Bookings, booking)
+	}
+
+	return result, nil
+}
+
+func isActiveSeriesBooking(status string) bool {
+	switch status {
+	case models.StatusCanceled, models.StatusCompleted, "rejected":
+		return false
+	}
+	return true
+}
+
+func sortConflicts(conflicts []models.SeriesConflict) {
+	sort.SliceStable(conflicts, func(i, j int) bool {
+		return conflicts[i].Date.Before(conflicts[j].Date)
+	})
+}
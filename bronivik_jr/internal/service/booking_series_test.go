@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"bronivik/internal/database"
+	"bronivik/internal/models"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookingSeries(t *testing.T) {
+	logger := zerolog.New(io.Discard)
+	ctx := context.Background()
+	today := time.Now().Truncate(24 * time.Hour)
+
+	t.Run("CreateBookingSeries reports conflicts per date", func(t *testing.T) {
+		repo := new(mockRepo)
+		worker := new(mockWorker)
+		svc := NewBookingService(repo, worker, 30, 0, &logger)
+
+		start := today.AddDate(0, 0, 7)
+		template := &models.Booking{UserID: 1, UserName: "Client", ItemID: 1, ItemName: "Item", Date: start}
+		rule := models.RecurrenceRule{Frequency: models.RecurrenceWeekly, Count: 5}
+
+		// Последняя дата (через 35 дней) не проходит валидацию, вторая занята
+		created := make([]*models.Booking, 3)
+		repo.On("CreateBookingSeriesWithLock", ctx, mock.AnythingOfType("*models.BookingSeries"), mock.Anything).
+			Run(func(args mock.Arguments) {
+				series := args.Get(1).(*models.BookingSeries)
+				series.ID = 7
+				bookings := args.Get(2).([]*models.Booking)
+				copy(created, []*models.Booking{bookings[0], bookings[2], bookings[3]})
+			}).
+			Return(created, []models.SeriesConflict{{Date: start.AddDate(0, 0, 7), Reason: database.ErrNotAvailable.Error()}}, nil).
+			Once()
+		worker.On("EnqueueTask", ctx, "upsert", mock.Anything, "").Return(nil).Times(3)
+		worker.On("EnqueueSyncSchedule", ctx, mock.Anything, mock.Anything).Return(nil).Once()
+
+		result, err := svc.CreateBookingSeries(ctx, template, rule)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), result.Series.ID)
+		assert.Equal(t, 1, result.Series.Interval)
+		assert.Len(t, result.Bookings, 3)
+		require.Len(t, result.Conflicts, 2)
+		assert.Equal(t, start.AddDate(0, 0, 7), result.Conflicts[0].Date)
+		assert.Equal(t, database.ErrNotAvailable.Error(), result.Conflicts[0].Reason)
+		assert.Equal(t, start.AddDate(0, 0, 28), result.Conflicts[1].Date)
+		assert.Equal(t, database.ErrDateTooFar.Error(), result.Conflicts[1].Reason)
+
+		repo.AssertExpectations(t)
+		worker.AssertExpectations(t)
+	})
+
+	t.Run("CreateBookingSeries invalid rule", func(t *testing.T) {
+		svc := NewBookingService(new(mockRepo), nil, 30, 0, &logger)
+		_, err := svc.CreateBookingSeries(ctx, &models.Booking{Date: today}, models.RecurrenceRule{Frequency: "daily", Count: 2})
+		assert.ErrorIs(t, err, models.ErrInvalidRecurrence)
+	})
+
+	t.Run("CancelSeries skips past and inactive occurrences", func(t *testing.T) {
+		repo := new(mockRepo)
+		svc := NewBookingService(repo, nil, 30, 0, &logger)
+
+		series := &models.BookingSeries{ID: 3, Status: models.SeriesStatusActive}
+		bookings := []*models.Booking{
+			{ID: 1, Date: today.AddDate(0, 0, -7), Status: models.StatusConfirmed, Version: 1},
+			{ID: 2, Date: today.AddDate(0, 0, 7), Status: models.StatusConfirmed, Version: 2},
+			{ID: 3, Date: today.AddDate(0, 0, 14), Status: models.StatusCanceled, Version: 1},
+			{ID: 4, Date: today.AddDate(0, 0, 21), Status: models.StatusPending, Version: 3},
+		}
+		repo.On("GetBookingSeries", ctx, int64(3)).Return(series, nil).Once()
+		repo.On("GetSeriesBookings", ctx, int64(3)).Return(bookings, nil).Once()
+		repo.On("UpdateBookingStatusWithVersion", actorIDCtx(100), int64(2), int64(2), models.StatusCanceled).Return(nil).Once()
+		repo.On("UpdateBookingStatusWithVersion", actorIDCtx(100), int64(4), int64(3), models.StatusCanceled).
+			Return(database.ErrConcurrentModification).Once()
+		repo.On("GetBooking", ctx, int64(2)).Return(&models.Booking{ID: 2, Status: models.StatusCanceled}, nil).Twice()
+		repo.On("UpdateBookingSeriesStatus", ctx, int64(3), models.SeriesStatusCanceled).Return(nil).Once()
+
+		result, err := svc.CancelSeries(ctx, 3, 100)
+		require.NoError(t, err)
+		require.Len(t, result.Bookings, 1)
+		assert.Equal(t, int64(2), result.Bookings[0].ID)
+		require.Len(t, result.Conflicts, 1)
+		assert.Equal(t, int64(4), result.Conflicts[0].BookingID)
+		assert.Equal(t, models.SeriesStatusCanceled, result.Series.Status)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("ConfirmSeries on canceled series", func(t *testing.T) {
+		repo := new(mockRepo)
+		svc := NewBookingService(repo, nil, 30, 0, &logger)
+
+		repo.On("GetBookingSeries", ctx, int64(5)).Return(&models.BookingSeries{ID: 5, Status: models.SeriesStatusCanceled}, nil).Once()
+		repo.On("GetSeriesBookings", ctx, int64(5)).Return([]*models.Booking{}, nil).Once()
+
+		_, err := svc.ConfirmSeries(ctx, 5, 100)
+		assert.ErrorIs(t, err, database.ErrSeriesCanceled)
+	})
+}
@@ -7,7 +7,6 @@ import (
 
 	"bronivik/internal/database"
 	"bronivik/internal/domain"
-	"bronivik/internal/events"
 	"bronivik/internal/models"
 
 	"github.com/rs/zerolog"
@@ -15,7 +14,6 @@ import (
 
 type BookingService struct {
 	repo              domain.Repository
-	eventBus          domain.EventPublisher
 	sheetsWorker      domain.SyncWorker
 	maxBookingDays    int
 	minBookingAdvance int // in hours
@@ -24,7 +22,6 @@ type BookingService struct {
 
 func NewBookingService(
 	repo domain.Repository,
-	eventBus domain.EventPublisher,
 	sheetsWorker domain.SyncWorker,
 	maxBookingDays, minBookingAdvance int,
 	logger *zerolog.Logger,
@@ -34,7 +31,6 @@ func NewBookingService(
 	}
 	return &BookingService{
 		repo:              repo,
-		eventBus:          eventBus,
 		sheetsWorker:      sheetsWorker,
 		maxBookingDays:    maxBookingDays,
 		minBookingAdvance: minBookingAdvance,
@@ -72,83 +68,127 @@ func (s *BookingService) CreateBooking(ctx context.Context, booking *models.Book
 	if err := s.ValidateBookingDate(booking.Date); err != nil {
 		return err
 	}
+	if booking.EndTime != nil {
+		if booking.EndTime.Before(booking.Date) {
+			return database.ErrInvalidDateRange
+		}
+		if err := s.ValidateBookingDate(*booking.EndTime); err != nil {
+			return err
+		}
+	}
 
-	// Проверяем доступность
-	available, err := s.repo.CheckAvailability(ctx, booking.ItemID, booking.Date)
-	if err != nil {
-		return err
+	// Проверяем доступность на каждый день бронирования
+	var busy []time.Time
+	for _, date := range booking.CoveredDates() {
+		available, err := s.repo.CheckAvailability(ctx, booking.ItemID, date)
+		if err != nil {
+			return err
+		}
+		if !available {
+			busy = append(busy, date)
+		}
 	}
-	if !available {
-		return database.ErrNotAvailable
+	if len(busy) > 0 {
+		return &database.UnavailableDatesError{Dates: busy}
 	}
 
-	// Создаем бронирование с блокировкой
-	err = s.repo.CreateBookingWithLock(ctx, booking)
+	// Создаем бронирование с блокировкой (повторная проверка всех дней внутри транзакции).
+	// Событие booking_created пишется в outbox в той же транзакции.
+	err := s.repo.CreateBookingWithLock(ctx, booking)
 	if err != nil {
 		return err
 	}
 
-	// Публикуем событие
-	s.publishEvent(events.EventBookingCreated, booking, "system", 0)
-
 	// Ставим задачу на синхронизацию
 	s.enqueueSync(ctx, booking, "upsert")
-	if err := s.sheetsWorker.EnqueueSyncSchedule(ctx, time.Time{}, time.Time{}); err != nil {
-		s.logger.Error().Err(err).Msg("failed to enqueue sync schedule")
+	s.enqueueScheduleSync(ctx)
+
+	return nil
+}
+
+// CreateWaitlistBooking создаёт заявку по предложению из листа ожидания. Единица
+// удерживается предложением, поэтому предварительная проверка занятости не нужна:
+// принятие предложения и создание заявки выполняются в одной транзакции.
+func (s *BookingService) CreateWaitlistBooking(ctx context.Context, entryID int64, booking *models.Booking) error {
+	if err := s.ValidateBookingDate(booking.Date); err != nil {
+		return err
+	}
+
+	if err := s.repo.CreateWaitlistBooking(ctx, entryID, booking); err != nil {
+		return err
 	}
 
+	s.enqueueSync(ctx, booking, "upsert")
+	s.enqueueScheduleSync(ctx)
+
 	return nil
 }
 
 func (s *BookingService) ConfirmBooking(ctx context.Context, bookingID, version, managerID int64) error {
-	return s.updateStatusAndSync(ctx, bookingID, version, models.StatusConfirmed, events.EventBookingConfirmed, "manager", managerID)
+	return s.updateStatusAndSync(ctx, bookingID, version, models.StatusConfirmed, managerID)
 }
 
 func (s *BookingService) RejectBooking(ctx context.Context, bookingID, version, managerID int64) error {
-	return s.updateStatusAndSync(ctx, bookingID, version, models.StatusCanceled, events.EventBookingCanceled, "manager", managerID)
+	return s.updateStatusAndSync(ctx, bookingID, version, models.StatusCanceled, managerID)
 }
 
 func (s *BookingService) CompleteBooking(ctx context.Context, bookingID, version, managerID int64) error {
-	return s.updateStatusAndSync(ctx, bookingID, version, models.StatusCompleted, events.EventBookingCompleted, "manager", managerID)
+	return s.updateStatusAndSync(ctx, bookingID, version, models.StatusCompleted, managerID)
 }
 
 func (s *BookingService) ReopenBooking(ctx context.Context, bookingID, version, managerID int64) error {
-	return s.updateStatusAndSync(ctx, bookingID, version, models.StatusPending, "", "", managerID)
+	return s.updateStatusAndSync(ctx, bookingID, version, models.StatusPending, managerID)
 }
 
 func (s *BookingService) updateStatusAndSync(
 	ctx context.Context,
 	bookingID, version int64,
-	status, eventType, changedBy string,
+	status string,
+	managerID int64,
+) error {
+	current, err := s.repo.GetBooking(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+	return s.applyStatus(ctx, current, version, status, managerID)
+}
+
+// applyStatus проверяет переход для уже загруженной заявки и сохраняет новый статус.
+// Событие о смене статуса пишется в outbox репозиторием.
+func (s *BookingService) applyStatus(
+	ctx context.Context,
+	current *models.Booking,
+	version int64,
+	status string,
 	managerID int64,
 ) error {
-	err := s.repo.UpdateBookingStatusWithVersion(ctx, bookingID, version, status)
+	if err := s.checkTransition(ctx, current, status); err != nil {
+		return err
+	}
+
+	bookingID := current.ID
+	err := s.repo.UpdateBookingStatusWithVersion(withActorID(ctx, managerID), bookingID, version, status)
 	if err != nil {
 		return err
 	}
 
 	booking, err := s.repo.GetBooking(ctx, bookingID)
 	if err == nil {
-		if eventType != "" {
-			s.publishEvent(eventType, booking, changedBy, managerID)
-		}
 		s.enqueueSync(ctx, booking, "update_status")
-		if err := s.sheetsWorker.EnqueueSyncSchedule(ctx, time.Time{}, time.Time{}); err != nil {
-			s.logger.Error().Err(err).Msg("failed to enqueue sync schedule")
-		}
+		s.enqueueScheduleSync(ctx)
 	}
 
 	return nil
 }
 
 func (s *BookingService) ChangeBookingItem(ctx context.Context, bookingID, version, newItemID, managerID int64) error {
-	// Получаем текущую заявку и проверяем доступность нового аппарата
-	_, available, err := s.repo.GetBookingWithAvailability(ctx, bookingID, newItemID)
+	// Доступность нового аппарата проверяется в транзакции обновления
+	current, err := s.repo.GetBooking(ctx, bookingID)
 	if err != nil {
 		return err
 	}
-	if !available {
-		return database.ErrNotAvailable
+	if err := s.checkTransition(ctx, current, models.StatusChanged); err != nil {
+		return err
 	}
 
 	// Находим имя нового аппарата
@@ -167,40 +207,72 @@ func (s *BookingService) ChangeBookingItem(ctx context.Context, bookingID, versi
 		return errors.New("new item not found")
 	}
 
-	err = s.repo.UpdateBookingItemAndStatusWithVersion(ctx, bookingID, version, newItemID, newItemName, models.StatusChanged)
+	err = s.repo.UpdateBookingItemAndStatusWithVersion(
+		withActorID(ctx, managerID), bookingID, version, newItemID, newItemName, models.StatusChanged)
 	if err != nil {
 		return err
 	}
 
 	updatedBooking, err := s.repo.GetBooking(ctx, bookingID)
 	if err == nil {
-		s.publishEvent(events.EventBookingItemChange, updatedBooking, "manager", managerID)
 		s.enqueueSync(ctx, updatedBooking, "upsert")
-		if err := s.sheetsWorker.EnqueueSyncSchedule(ctx, time.Time{}, time.Time{}); err != nil {
-			s.logger.Error().Err(err).Msg("failed to enqueue sync schedule")
-		}
+		s.enqueueScheduleSync(ctx)
 	}
 
 	return nil
 }
 
-func (s *BookingService) RescheduleBooking(ctx context.Context, bookingID, managerID int64) error {
-	err := s.repo.UpdateBookingStatus(ctx, bookingID, "rescheduled")
+// RescheduleBooking переносит заявку на новую дату или период с проверкой версии.
+// Прежние даты сохраняются в истории изменений и передаются в событии booking_rescheduled.
+func (s *BookingService) RescheduleBooking(
+	ctx context.Context,
+	bookingID, version int64,
+	date time.Time,
+	endTime *time.Time,
+	managerID int64,
+) error {
+	if err := s.ValidateBookingDate(date); err != nil {
+		return err
+	}
+	if endTime != nil {
+		if endTime.Before(date) {
+			return database.ErrInvalidDateRange
+		}
+		if err := s.ValidateBookingDate(*endTime); err != nil {
+			return err
+		}
+	}
+
+	previous, err := s.repo.GetBooking(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkTransition(ctx, previous, models.StatusChanged); err != nil {
+		return err
+	}
+
+	err = s.repo.UpdateBookingDatesWithVersion(ctx, bookingID, version, date, endTime, managerID)
 	if err != nil {
 		return err
 	}
 
 	booking, err := s.repo.GetBooking(ctx, bookingID)
 	if err == nil {
-		s.enqueueSync(ctx, booking, "update_status")
-		if err := s.sheetsWorker.EnqueueSyncSchedule(ctx, time.Time{}, time.Time{}); err != nil {
-			s.logger.Error().Err(err).Msg("failed to enqueue sync schedule")
-		}
+		s.enqueueSync(ctx, booking, "upsert")
+		s.enqueueScheduleSync(ctx)
 	}
 
 	return nil
 }
 
+// GetBookingHistory возвращает историю изменений заявки; для несуществующей заявки — ошибку GetBooking.
+func (s *BookingService) GetBookingHistory(ctx context.Context, bookingID int64) ([]*models.BookingHistoryEntry, error) {
+	if _, err := s.repo.GetBooking(ctx, bookingID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetBookingHistory(ctx, bookingID)
+}
+
 func (s *BookingService) GetAvailability(ctx context.Context, itemID int64, startDate time.Time, days int) ([]*models.Availability, error) {
 	return s.repo.GetAvailabilityForPeriod(ctx, itemID, startDate, days)
 }
@@ -217,6 +289,11 @@ func (s *BookingService) GetBookingsByDateRange(ctx context.Context, start, end
 	return s.repo.GetBookingsByDateRange(ctx, start, end)
 }
 
+// ListBookings возвращает заявки по фильтру в порядке возрастания ID.
+func (s *BookingService) ListBookings(ctx context.Context, filter *models.BookingFilter) ([]*models.Booking, error) {
+	return s.repo.ListBookings(ctx, filter)
+}
+
 func (s *BookingService) GetBooking(ctx context.Context, id int64) (*models.Booking, error) {
 	return s.repo.GetBooking(ctx, id)
 }
@@ -225,27 +302,14 @@ func (s *BookingService) GetDailyBookings(ctx context.Context, start, end time.T
 	return s.repo.GetDailyBookings(ctx, start, end)
 }
 
-func (s *BookingService) publishEvent(eventType string, booking *models.Booking, changedBy string, changedByID int64) {
-	if s.eventBus == nil {
-		return
-	}
-
-	payload := events.BookingEventPayload{
-		BookingID:   booking.ID,
-		UserID:      booking.UserID,
-		UserName:    booking.UserName,
-		ItemID:      booking.ItemID,
-		ItemName:    booking.ItemName,
-		Status:      booking.Status,
-		Date:        booking.Date,
-		Comment:     booking.Comment,
-		ChangedBy:   changedBy,
-		ChangedByID: changedByID,
-	}
-
-	if err := s.eventBus.PublishJSON(eventType, payload); err != nil {
-		s.logger.Error().Err(err).Str("event_type", eventType).Int64("booking_id", booking.ID).Msg("publish event error")
+// withActorID подставляет managerID как инициатора изменения, если он не задан в контексте.
+func withActorID(ctx context.Context, managerID int64) context.Context {
+	actor := models.ActorFromContext(ctx)
+	if actor.ID != 0 || managerID == 0 {
+		return ctx
 	}
+	actor.ID = managerID
+	return models.WithActor(ctx, actor)
 }
 
 func (s *BookingService) enqueueSync(ctx context.Context, booking *models.Booking, taskType string) {
@@ -258,7 +322,17 @@ func (s *BookingService) enqueueSync(ctx context.Context, booking *models.Bookin
 		status = booking.Status
 	}
 
-	if err := s.sheetsWorker.EnqueueTask(ctx, taskType, booking.ID, booking, status); err != nil {
+	if err := s.sheetsWorker.EnqueueTask(ctx, taskType, booking.ID, status); err != nil {
 		s.logger.Error().Err(err).Int64("booking_id", booking.ID).Str("task", taskType).Msg("sheets enqueue error")
 	}
 }
+
+func (s *BookingService) enqueueScheduleSync(ctx context.Context) {
+	if s.sheetsWorker == nil {
+		return
+	}
+
+	if err := s.sheetsWorker.EnqueueSyncSchedule(ctx, time.Time{}, time.Time{}); err != nil {
+		s.logger.Error().Err(err).Msg("failed to enqueue sync schedule")
+	}
+}
@@ -6,11 +6,13 @@ import (
 	"testing"
 	"time"
 
+	"bronivik/internal/database"
 	"bronivik/internal/models"
 
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type mockRepo struct {
@@ -30,6 +32,9 @@ func (m *mockRepo) CreateBooking(ctx context.Context, b *models.Booking) error {
 func (m *mockRepo) CreateBookingWithLock(ctx context.Context, b *models.Booking) error {
 	return m.Called(ctx, b).Error(0)
 }
+func (m *mockRepo) CreateWaitlistBooking(ctx context.Context, entryID int64, b *models.Booking) error {
+	return m.Called(ctx, entryID, b).Error(0)
+}
 func (m *mockRepo) UpdateBookingStatus(ctx context.Context, id int64, s string) error {
 	return m.Called(ctx, id, s).Error(0)
 }
@@ -117,6 +122,33 @@ func (m *mockRepo) UpdateUserActivity(ctx context.Context, id int64) error {
 func (m *mockRepo) UpdateUserPhone(ctx context.Context, id int64, p string) error {
 	return m.Called(ctx, id, p).Error(0)
 }
+func (m *mockRepo) GetUserLanguage(ctx context.Context, id int64) (string, string, error) {
+	args := m.Called(ctx, id)
+	return args.String(0), args.String(1), args.Error(2)
+}
+func (m *mockRepo) SetUserLanguage(ctx context.Context, id int64, lang string) error {
+	return m.Called(ctx, id, lang).Error(0)
+}
+func (m *mockRepo) GiveConsent(ctx context.Context, id int64, version string) error {
+	return m.Called(ctx, id, version).Error(0)
+}
+func (m *mockRepo) RevokeConsent(ctx context.Context, id int64) error {
+	return m.Called(ctx, id).Error(0)
+}
+func (m *mockRepo) ExportUserData(ctx context.Context, id int64) (*models.UserDataExport, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserDataExport), args.Error(1)
+}
+func (m *mockRepo) EraseUserData(ctx context.Context, id int64) (*models.ErasureResult, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ErasureResult), args.Error(1)
+}
 func (m *mockRepo) GetDailyBookings(ctx context.Context, s, e time.Time) (map[string][]*models.Booking, error) {
 	args := m.Called(ctx, s, e)
 	if args.Get(0) == nil {
@@ -128,15 +160,32 @@ func (m *mockRepo) GetBookedCount(ctx context.Context, id int64, d time.Time) (i
 	args := m.Called(ctx, id, d)
 	return args.Int(0), args.Error(1)
 }
-func (m *mockRepo) GetBookingWithAvailability(ctx context.Context, id, nid int64) (*models.Booking, bool, error) {
-	args := m.Called(ctx, id, nid)
+func (m *mockRepo) UpdateBookingItemAndStatusWithVersion(ctx context.Context, id, v, iid int64, in, s string) error {
+	return m.Called(ctx, id, v, iid, in, s).Error(0)
+}
+func (m *mockRepo) UpdateBookingDatesWithVersion(
+	ctx context.Context,
+	id, v int64,
+	date time.Time,
+	endTime *time.Time,
+	changedBy int64,
+) error {
+	return m.Called(ctx, id, v, date, endTime, changedBy).Error(0)
+}
+func (m *mockRepo) ListBookings(ctx context.Context, filter *models.BookingFilter) ([]*models.Booking, error) {
+	args := m.Called(ctx, filter)
 	if args.Get(0) == nil {
-		return nil, args.Bool(1), args.Error(2)
+		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.Booking), args.Bool(1), args.Error(2)
+	return args.Get(0).([]*models.Booking), args.Error(1)
 }
-func (m *mockRepo) UpdateBookingItemAndStatusWithVersion(ctx context.Context, id, v, iid int64, in, s string) error {
-	return m.Called(ctx, id, v, iid, in, s).Error(0)
+
+func (m *mockRepo) GetBookingHistory(ctx context.Context, bookingID int64) ([]*models.BookingHistoryEntry, error) {
+	args := m.Called(ctx, bookingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.BookingHistoryEntry), args.Error(1)
 }
 func (m *mockRepo) SetItems(items []*models.Item) { m.Called(items) }
 func (m *mockRepo) GetActiveUsers(ctx context.Context, d int) ([]*models.User, error) {
@@ -160,31 +209,66 @@ func (m *mockRepo) GetUserBookings(ctx context.Context, id int64) ([]*models.Boo
 	}
 	return args.Get(0).([]*models.Booking), args.Error(1)
 }
-
-type mockEventBus struct {
-	mock.Mock
+func (m *mockRepo) CreateBookingSeriesWithLock(
+	ctx context.Context, sr *models.BookingSeries, b []*models.Booking,
+) ([]*models.Booking, []models.SeriesConflict, error) {
+	args := m.Called(ctx, sr, b)
+	var created []*models.Booking
+	if args.Get(0) != nil {
+		created = args.Get(0).([]*models.Booking)
+	}
+	var conflicts []models.SeriesConflict
+	if args.Get(1) != nil {
+		conflicts = args.Get(1).([]models.SeriesConflict)
+	}
+	return created, conflicts, args.Error(2)
+}
+func (m *mockRepo) GetBookingSeries(ctx context.Context, id int64) (*models.BookingSeries, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BookingSeries), args.Error(1)
+}
+func (m *mockRepo) GetSeriesBookings(ctx context.Context, id int64) ([]*models.Booking, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Booking), args.Error(1)
+}
+func (m *mockRepo) UpdateBookingSeriesStatus(ctx context.Context, id int64, s string) error {
+	return m.Called(ctx, id, s).Error(0)
+}
+func (m *mockRepo) UpdateBookingSeriesItem(ctx context.Context, id, iid int64, in string) error {
+	return m.Called(ctx, id, iid, in).Error(0)
 }
-
-func (m *mockEventBus) PublishJSON(et string, p interface{}) error { return m.Called(et, p).Error(0) }
 
 type mockWorker struct {
 	mock.Mock
 }
 
-func (m *mockWorker) EnqueueTask(ctx context.Context, tt string, bid int64, b *models.Booking, s string) error {
-	return m.Called(ctx, tt, bid, b, s).Error(0)
+func (m *mockWorker) EnqueueTask(ctx context.Context, tt string, bid int64, s string) error {
+	return m.Called(ctx, tt, bid, s).Error(0)
 }
 func (m *mockWorker) EnqueueSyncSchedule(ctx context.Context, s, e time.Time) error {
 	return m.Called(ctx, s, e).Error(0)
 }
 
+// actorIDCtx сопоставляет контекст с заданным ID инициатора изменения.
+func actorIDCtx(id int64) interface{} {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		return models.ActorFromContext(ctx).ID == id
+	})
+}
+
 func TestBookingService(t *testing.T) {
 	repo := new(mockRepo)
-	bus := new(mockEventBus)
 	worker := new(mockWorker)
 	logger := zerolog.New(io.Discard)
-	svc := NewBookingService(repo, bus, worker, 30, 2, &logger)
-	ctx := context.Background()
+	svc := NewBookingService(repo, worker, 30, 2, &logger)
+	// Правила менеджера действуют только при явном акторе
+	ctx := models.WithActor(context.Background(), models.Actor{Type: models.ActorManager})
 
 	t.Run("ValidateBookingDate", func(t *testing.T) {
 		now := time.Now()
@@ -208,8 +292,7 @@ func TestBookingService(t *testing.T) {
 
 		repo.On("CheckAvailability", ctx, int64(1), date).Return(true, nil).Once()
 		repo.On("CreateBookingWithLock", ctx, booking).Return(nil).Once()
-		bus.On("PublishJSON", mock.Anything, mock.Anything).Return(nil).Once()
-		worker.On("EnqueueTask", ctx, "upsert", int64(0), booking, "").Return(nil).Once()
+		worker.On("EnqueueTask", ctx, "upsert", int64(0), "").Return(nil).Once()
 		worker.On("EnqueueSyncSchedule", ctx, mock.Anything, mock.Anything).Return(nil).Once()
 
 		err := svc.CreateBooking(ctx, booking)
@@ -217,19 +300,43 @@ func TestBookingService(t *testing.T) {
 		repo.AssertExpectations(t)
 	})
 
+	t.Run("CreateBooking range reports busy dates", func(t *testing.T) {
+		date := time.Now().AddDate(0, 0, 5)
+		end := date.AddDate(0, 0, 2)
+		booking := &models.Booking{ItemID: 1, Date: date, EndTime: &end}
+
+		repo.On("CheckAvailability", ctx, int64(1), date).Return(true, nil).Once()
+		repo.On("CheckAvailability", ctx, int64(1), date.AddDate(0, 0, 1)).Return(false, nil).Once()
+		repo.On("CheckAvailability", ctx, int64(1), end).Return(false, nil).Once()
+
+		err := svc.CreateBooking(ctx, booking)
+		require.ErrorIs(t, err, database.ErrNotAvailable)
+		var datesErr *database.UnavailableDatesError
+		require.ErrorAs(t, err, &datesErr)
+		assert.Equal(t, []time.Time{date.AddDate(0, 0, 1), end}, datesErr.Dates)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("CreateBooking range end before start", func(t *testing.T) {
+		date := time.Now().AddDate(0, 0, 5)
+		end := date.AddDate(0, 0, -1)
+		err := svc.CreateBooking(ctx, &models.Booking{ItemID: 1, Date: date, EndTime: &end})
+		assert.ErrorIs(t, err, database.ErrInvalidDateRange)
+	})
+
 	testStatusUpdate := func(
 		name string,
 		bookingID int64,
 		version int64,
-		status string,
+		from, status string,
 		method func(context.Context, int64, int64, int64) error,
 	) {
 		t.Run(name, func(t *testing.T) {
 			booking := &models.Booking{ID: bookingID, Status: status}
-			repo.On("UpdateBookingStatusWithVersion", ctx, bookingID, version, status).Return(nil).Once()
+			repo.On("GetBooking", ctx, bookingID).Return(&models.Booking{ID: bookingID, Status: from}, nil).Once()
+			repo.On("UpdateBookingStatusWithVersion", actorIDCtx(100), bookingID, version, status).Return(nil).Once()
 			repo.On("GetBooking", ctx, bookingID).Return(booking, nil).Once()
-			bus.On("PublishJSON", mock.Anything, mock.Anything).Return(nil).Once()
-			worker.On("EnqueueTask", ctx, "update_status", bookingID, booking, status).Return(nil).Once()
+			worker.On("EnqueueTask", ctx, "update_status", bookingID, status).Return(nil).Once()
 			worker.On("EnqueueSyncSchedule", ctx, mock.Anything, mock.Anything).Return(nil).Once()
 
 			err := method(ctx, bookingID, version, 100)
@@ -238,22 +345,46 @@ func TestBookingService(t *testing.T) {
 		})
 	}
 
-	testStatusUpdate("ConfirmBooking", 10, 1, models.StatusConfirmed, svc.ConfirmBooking)
-	testStatusUpdate("RejectBooking", 11, 2, models.StatusCanceled, svc.RejectBooking)
-	testStatusUpdate("CompleteBooking", 12, 3, models.StatusCompleted, svc.CompleteBooking)
-	testStatusUpdate("ReopenBooking", 13, 4, models.StatusPending, svc.ReopenBooking)
+	testStatusUpdate("ConfirmBooking", 10, 1, models.StatusPending, models.StatusConfirmed, svc.ConfirmBooking)
+	testStatusUpdate("RejectBooking", 11, 2, models.StatusPending, models.StatusCanceled, svc.RejectBooking)
+	testStatusUpdate("CompleteBooking", 12, 3, models.StatusConfirmed, models.StatusCompleted, svc.CompleteBooking)
+	testStatusUpdate("ReopenBooking", 13, 4, models.StatusConfirmed, models.StatusPending, svc.ReopenBooking)
+
+	t.Run("CompleteBooking canceled", func(t *testing.T) {
+		repo.On("GetBooking", ctx, int64(20)).Return(&models.Booking{ID: 20, Status: models.StatusCanceled}, nil).Once()
+
+		err := svc.CompleteBooking(ctx, 20, 1, 100)
+		assert.ErrorIs(t, err, models.ErrInvalidTransition)
+		repo.AssertNotCalled(t, "UpdateBookingStatusWithVersion", ctx, int64(20), int64(1), models.StatusCompleted)
+	})
+
+	t.Run("ReopenBooking as API client", func(t *testing.T) {
+		apiCtx := models.WithStatusActor(ctx, models.ActorAPI)
+		repo.On("GetBooking", apiCtx, int64(21)).Return(&models.Booking{ID: 21, Status: models.StatusConfirmed}, nil).Once()
+
+		err := svc.ReopenBooking(apiCtx, 21, 1, 0)
+		var trErr *models.TransitionError
+		require.ErrorAs(t, err, &trErr)
+		assert.Equal(t, models.ActorAPI, trErr.Actor)
+	})
+
+	t.Run("ConfirmBooking legacy status", func(t *testing.T) {
+		repo.On("GetBooking", ctx, int64(22)).Return(&models.Booking{ID: 22, Status: "approved"}, nil).Once()
+
+		err := svc.ConfirmBooking(ctx, 22, 1, 100)
+		assert.ErrorIs(t, err, models.ErrUnknownStatus)
+	})
 
 	t.Run("ChangeBookingItem", func(t *testing.T) {
 		oldBooking := &models.Booking{ID: 14, ItemID: 1, ItemName: "Old Item", Status: models.StatusPending}
 		newBooking := &models.Booking{ID: 14, ItemID: 2, ItemName: "New Item", Status: models.StatusChanged}
 		items := []*models.Item{{ID: 2, Name: "New Item"}}
 
-		repo.On("GetBookingWithAvailability", ctx, int64(14), int64(2)).Return(oldBooking, true, nil).Once()
+		repo.On("GetBooking", ctx, int64(14)).Return(oldBooking, nil).Once()
 		repo.On("GetActiveItems", ctx).Return(items, nil).Once()
-		repo.On("UpdateBookingItemAndStatusWithVersion", ctx, int64(14), int64(5), int64(2), "New Item", models.StatusChanged).Return(nil).Once()
+		repo.On("UpdateBookingItemAndStatusWithVersion", actorIDCtx(100), int64(14), int64(5), int64(2), "New Item", models.StatusChanged).Return(nil).Once()
 		repo.On("GetBooking", ctx, int64(14)).Return(newBooking, nil).Once()
-		bus.On("PublishJSON", mock.Anything, mock.Anything).Return(nil).Once()
-		worker.On("EnqueueTask", ctx, "upsert", int64(14), newBooking, "").Return(nil).Once()
+		worker.On("EnqueueTask", ctx, "upsert", int64(14), "").Return(nil).Once()
 		worker.On("EnqueueSyncSchedule", ctx, mock.Anything, mock.Anything).Return(nil).Once()
 
 		err := svc.ChangeBookingItem(ctx, 14, 5, 2, 100)
@@ -262,16 +393,43 @@ func TestBookingService(t *testing.T) {
 	})
 
 	t.Run("RescheduleBooking", func(t *testing.T) {
-		booking := &models.Booking{ID: 15, Status: "rescheduled"}
-
-		repo.On("UpdateBookingStatus", ctx, int64(15), "rescheduled").Return(nil).Once()
-		repo.On("GetBooking", ctx, int64(15)).Return(booking, nil).Once()
-		worker.On("EnqueueTask", ctx, "update_status", int64(15), booking, "rescheduled").Return(nil).Once()
+		repo := new(mockRepo)
+		worker := new(mockWorker)
+		svc := NewBookingService(repo, worker, 30, 0, &logger)
+
+		oldDate := time.Now().AddDate(0, 0, 2).Truncate(24 * time.Hour)
+		newDate := oldDate.AddDate(0, 0, 5)
+		newEnd := newDate.AddDate(0, 0, 2)
+		before := &models.Booking{ID: 15, Date: oldDate, Status: models.StatusConfirmed, Version: 3}
+		after := &models.Booking{ID: 15, Date: newDate, EndTime: &newEnd, Status: models.StatusChanged, Version: 4}
+
+		repo.On("GetBooking", ctx, int64(15)).Return(before, nil).Once()
+		repo.On("UpdateBookingDatesWithVersion", ctx, int64(15), int64(3), newDate, &newEnd, int64(100)).Return(nil).Once()
+		repo.On("GetBooking", ctx, int64(15)).Return(after, nil).Once()
+		worker.On("EnqueueTask", ctx, "upsert", int64(15), "").Return(nil).Once()
 		worker.On("EnqueueSyncSchedule", ctx, mock.Anything, mock.Anything).Return(nil).Once()
 
-		err := svc.RescheduleBooking(ctx, 15, 100)
+		err := svc.RescheduleBooking(ctx, 15, 3, newDate, &newEnd, 100)
 		assert.NoError(t, err)
 		repo.AssertExpectations(t)
+		worker.AssertExpectations(t)
+	})
+
+	t.Run("RescheduleBooking conflict", func(t *testing.T) {
+		date := time.Now().AddDate(0, 0, 3).Truncate(24 * time.Hour)
+		repo.On("GetBooking", ctx, int64(16)).
+			Return(&models.Booking{ID: 16, Date: date.AddDate(0, 0, 1), Status: models.StatusConfirmed}, nil).Once()
+		repo.On("UpdateBookingDatesWithVersion", ctx, int64(16), int64(1), date, (*time.Time)(nil), int64(100)).
+			Return(&database.UnavailableDatesError{Dates: []time.Time{date}}).Once()
+
+		err := svc.RescheduleBooking(ctx, 16, 1, date, nil, 100)
+		assert.ErrorIs(t, err, database.ErrNotAvailable)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("RescheduleBooking past date", func(t *testing.T) {
+		err := svc.RescheduleBooking(ctx, 17, 1, time.Now().AddDate(0, 0, -2), nil, 100)
+		assert.ErrorIs(t, err, database.ErrPastDate)
 	})
 
 	t.Run("GetAvailability", func(t *testing.T) {
@@ -316,6 +474,18 @@ func TestBookingService(t *testing.T) {
 		repo.AssertExpectations(t)
 	})
 
+	t.Run("ListBookings", func(t *testing.T) {
+		filter := &models.BookingFilter{ItemID: 3, AfterID: 10, Limit: 20}
+		bookings := []*models.Booking{{ID: 11}, {ID: 12}}
+
+		repo.On("ListBookings", ctx, filter).Return(bookings, nil).Once()
+
+		result, err := svc.ListBookings(ctx, filter)
+		assert.NoError(t, err)
+		assert.Equal(t, bookings, result)
+		repo.AssertExpectations(t)
+	})
+
 	t.Run("GetBooking", func(t *testing.T) {
 		booking := &models.Booking{ID: 16}
 
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"bronivik/internal/models"
+)
+
+// checkTransition проверяет переход текущего статуса заявки в status.
+func (s *BookingService) checkTransition(ctx context.Context, booking *models.Booking, status string) error {
+	actor := models.StatusActorFromContext(ctx)
+	err := models.CheckStatusTransition(actor, booking.Status, status)
+	if err != nil {
+		s.logger.Warn().Err(err).
+			Int64("booking_id", booking.ID).
+			Str("actor", string(actor)).
+			Msg("booking status transition rejected")
+	}
+	return err
+}
@@ -79,3 +79,52 @@ func (s *UserService) GetUserBookings(ctx context.Context, userID int64) ([]*mod
 func (s *UserService) GetUserByID(ctx context.Context, id int64) (*models.User, error) {
 	return s.repo.GetUserByID(ctx, id)
 }
+
+func (s *UserService) GetUserLanguage(ctx context.Context, telegramID int64) (chosen, telegram string, err error) {
+	return s.repo.GetUserLanguage(ctx, telegramID)
+}
+
+func (s *UserService) SetUserLanguage(ctx context.Context, telegramID int64, lang string) error {
+	return s.repo.SetUserLanguage(ctx, telegramID, lang)
+}
+
+func (s *UserService) GetUserByTelegramID(ctx context.Context, telegramID int64) (*models.User, error) {
+	return s.repo.GetUserByTelegramID(ctx, telegramID)
+}
+
+// GiveConsent фиксирует согласие с политикой обработки персональных данных версии version.
+func (s *UserService) GiveConsent(ctx context.Context, telegramID int64, version string) error {
+	if err := s.repo.GiveConsent(ctx, telegramID, version); err != nil {
+		return err
+	}
+	s.logger.Info().Int64("user_id", telegramID).Str("policy_version", version).Msg("Personal data consent given")
+	return nil
+}
+
+// RevokeConsent отзывает согласие; новые заявки пользователя блокируются, пока он не даст его снова.
+func (s *UserService) RevokeConsent(ctx context.Context, telegramID int64) error {
+	if err := s.repo.RevokeConsent(ctx, telegramID); err != nil {
+		return err
+	}
+	s.logger.Info().Int64("user_id", telegramID).Msg("Personal data consent revoked")
+	return nil
+}
+
+// ExportUserData возвращает все данные, которые бот хранит о пользователе.
+func (s *UserService) ExportUserData(ctx context.Context, telegramID int64) (*models.UserDataExport, error) {
+	return s.repo.ExportUserData(ctx, telegramID)
+}
+
+// EraseUserData удаляет персональные данные пользователя, оставляя обезличенные заявки для статистики.
+func (s *UserService) EraseUserData(ctx context.Context, telegramID int64) (*models.ErasureResult, error) {
+	result, err := s.repo.EraseUserData(ctx, telegramID)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info().
+		Int64("user_id", telegramID).
+		Str("pseudonym", result.Pseudonym).
+		Int("bookings", len(result.BookingIDs)).
+		Msg("User personal data erased")
+	return result, nil
+}
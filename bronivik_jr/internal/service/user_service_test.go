@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
@@ -36,6 +37,11 @@ func (m *MockRepository) CreateBookingWithLock(ctx context.Context, booking *mod
 	return args.Error(0)
 }
 
+func (m *MockRepository) CreateWaitlistBooking(ctx context.Context, entryID int64, booking *models.Booking) error {
+	args := m.Called(ctx, entryID, booking)
+	return args.Error(0)
+}
+
 func (m *MockRepository) UpdateBookingStatus(ctx context.Context, id int64, status string) error {
 	args := m.Called(ctx, id, status)
 	return args.Error(0)
@@ -155,6 +161,42 @@ func (m *MockRepository) UpdateUserPhone(ctx context.Context, telegramID int64,
 	return args.Error(0)
 }
 
+func (m *MockRepository) GetUserLanguage(ctx context.Context, telegramID int64) (chosen, telegram string, err error) {
+	args := m.Called(ctx, telegramID)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockRepository) SetUserLanguage(ctx context.Context, telegramID int64, lang string) error {
+	args := m.Called(ctx, telegramID, lang)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GiveConsent(ctx context.Context, telegramID int64, version string) error {
+	args := m.Called(ctx, telegramID, version)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RevokeConsent(ctx context.Context, telegramID int64) error {
+	args := m.Called(ctx, telegramID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ExportUserData(ctx context.Context, telegramID int64) (*models.UserDataExport, error) {
+	args := m.Called(ctx, telegramID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserDataExport), args.Error(1)
+}
+
+func (m *MockRepository) EraseUserData(ctx context.Context, telegramID int64) (*models.ErasureResult, error) {
+	args := m.Called(ctx, telegramID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ErasureResult), args.Error(1)
+}
+
 func (m *MockRepository) GetDailyBookings(ctx context.Context, start, end time.Time) (map[string][]*models.Booking, error) {
 	args := m.Called(ctx, start, end)
 	if args.Get(0) == nil {
@@ -168,14 +210,6 @@ func (m *MockRepository) GetBookedCount(ctx context.Context, itemID int64, date
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockRepository) GetBookingWithAvailability(ctx context.Context, id, newItemID int64) (*models.Booking, bool, error) {
-	args := m.Called(ctx, id, newItemID)
-	if args.Get(0) == nil {
-		return nil, args.Bool(1), args.Error(2)
-	}
-	return args.Get(0).(*models.Booking), args.Bool(1), args.Error(2)
-}
-
 func (m *MockRepository) UpdateBookingItemAndStatusWithVersion(
 	ctx context.Context,
 	id, version, itemID int64,
@@ -185,6 +219,33 @@ func (m *MockRepository) UpdateBookingItemAndStatusWithVersion(
 	return args.Error(0)
 }
 
+func (m *MockRepository) UpdateBookingDatesWithVersion(
+	ctx context.Context,
+	id, version int64,
+	date time.Time,
+	endTime *time.Time,
+	changedBy int64,
+) error {
+	args := m.Called(ctx, id, version, date, endTime, changedBy)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListBookings(ctx context.Context, filter *models.BookingFilter) ([]*models.Booking, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Booking), args.Error(1)
+}
+
+func (m *MockRepository) GetBookingHistory(ctx context.Context, bookingID int64) ([]*models.BookingHistoryEntry, error) {
+	args := m.Called(ctx, bookingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.BookingHistoryEntry), args.Error(1)
+}
+
 func (m *MockRepository) SetItems(items []*models.Item) {
 	m.Called(items)
 }
@@ -213,6 +274,44 @@ func (m *MockRepository) GetUserBookings(ctx context.Context, userID int64) ([]*
 	return args.Get(0).([]*models.Booking), args.Error(1)
 }
 
+func (m *MockRepository) CreateBookingSeriesWithLock(
+	ctx context.Context,
+	series *models.BookingSeries,
+	bookings []*models.Booking,
+) ([]*models.Booking, []models.SeriesConflict, error) {
+	args := m.Called(ctx, series, bookings)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]*models.Booking), args.Get(1).([]models.SeriesConflict), args.Error(2)
+}
+
+func (m *MockRepository) GetBookingSeries(ctx context.Context, id int64) (*models.BookingSeries, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BookingSeries), args.Error(1)
+}
+
+func (m *MockRepository) GetSeriesBookings(ctx context.Context, seriesID int64) ([]*models.Booking, error) {
+	args := m.Called(ctx, seriesID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Booking), args.Error(1)
+}
+
+func (m *MockRepository) UpdateBookingSeriesStatus(ctx context.Context, id int64, status string) error {
+	args := m.Called(ctx, id, status)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UpdateBookingSeriesItem(ctx context.Context, id, itemID int64, itemName string) error {
+	args := m.Called(ctx, id, itemID, itemName)
+	return args.Error(0)
+}
+
 func TestUserService_IsManager(t *testing.T) {
 	mockRepo := new(MockRepository)
 	logger := zerolog.Nop()
@@ -364,3 +463,37 @@ func TestUserService_GetUserByID(t *testing.T) {
 	assert.Nil(t, result)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestUserService_Consent(t *testing.T) {
+	mockRepo := new(MockRepository)
+	logger := zerolog.Nop()
+	cfg := &config.Config{}
+	s := NewUserService(mockRepo, cfg, &logger)
+
+	mockRepo.On("GiveConsent", mock.Anything, int64(1), models.PrivacyPolicyVersion).Return(nil)
+	mockRepo.On("RevokeConsent", mock.Anything, int64(1)).Return(nil)
+	mockRepo.On("RevokeConsent", mock.Anything, int64(2)).Return(assert.AnError)
+
+	assert.NoError(t, s.GiveConsent(context.Background(), 1, models.PrivacyPolicyVersion))
+	assert.NoError(t, s.RevokeConsent(context.Background(), 1))
+	assert.Error(t, s.RevokeConsent(context.Background(), 2))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_EraseUserData(t *testing.T) {
+	mockRepo := new(MockRepository)
+	logger := zerolog.Nop()
+	cfg := &config.Config{}
+	s := NewUserService(mockRepo, cfg, &logger)
+
+	erased := &models.ErasureResult{TelegramID: 1, PseudonymID: -5, Pseudonym: "deleted-5", BookingIDs: []int64{10, 11}}
+	mockRepo.On("EraseUserData", mock.Anything, int64(1)).Return(erased, nil)
+	mockRepo.On("EraseUserData", mock.Anything, int64(2)).Return(nil, sql.ErrNoRows)
+
+	result, err := s.EraseUserData(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, erased, result)
+	_, err = s.EraseUserData(context.Background(), 2)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"bronivik/internal/database"
+	"bronivik/internal/domain"
+	"bronivik/internal/models"
+
+	"github.com/rs/zerolog"
+)
+
+// WaitlistService управляет листом ожидания: ставит пользователей в очередь на занятые
+// позиции и предлагает освободившиеся единицы следующему в очереди.
+type WaitlistService struct {
+	repo           domain.WaitlistRepository
+	bookingService domain.BookingService
+	notifier       domain.WaitlistNotifier
+	offerTTL       time.Duration
+	checkInterval  time.Duration
+	mu             sync.Mutex
+	logger         *zerolog.Logger
+}
+
+func NewWaitlistService(
+	repo domain.WaitlistRepository,
+	bookingService domain.BookingService,
+	offerTTL time.Duration,
+	logger *zerolog.Logger,
+) *WaitlistService {
+	if offerTTL <= 0 {
+		offerTTL = time.Duration(models.WaitlistOfferTTL) * time.Second
+	}
+	return &WaitlistService{
+		repo:           repo,
+		bookingService: bookingService,
+		offerTTL:       offerTTL,
+		checkInterval:  time.Duration(models.WaitlistCheckInterval) * time.Second,
+		logger:         logger,
+	}
+}
+
+// SetNotifier задает получателя предложений (бот создается позже сервиса).
+func (s *WaitlistService) SetNotifier(notifier domain.WaitlistNotifier) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notifier = notifier
+}
+
+// Start периодически закрывает просроченные предложения и передает место следующему в очереди.
+func (s *WaitlistService) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.processExpiredOffers(ctx, time.Now())
+		}
+	}
+}
+
+func (s *WaitlistService) JoinWaitlist(ctx context.Context, entry *models.WaitlistEntry) error {
+	if err := s.bookingService.ValidateBookingDate(entry.Date); err != nil {
+		return err
+	}
+
+	if err := s.repo.AddWaitlistEntry(ctx, entry); err != nil {
+		return err
+	}
+
+	// Место могло освободиться, пока пользователь решал встать в очередь
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.offerFreeSlots(ctx, entry.ItemID, entry.Date)
+}
+
+// HandleSlotReleased вызывается при отмене заявки и предлагает освободившиеся единицы очереди.
+func (s *WaitlistService) HandleSlotReleased(ctx context.Context, itemID int64, date time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.offerFreeSlots(ctx, itemID, date)
+}
+
+func (s *WaitlistService) AcceptOffer(ctx context.Context, entryID, userID int64) (*models.Booking, error) {
+	entry, err := s.getOwnEntry(ctx, entryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Status != models.WaitlistStatusOffered || entry.IsOfferExpired(time.Now()) {
+		return nil, database.ErrWaitlistOfferExpired
+	}
+
+	booking := &models.Booking{
+		UserID:       entry.UserID,
+		UserName:     entry.UserName,
+		UserNickname: entry.UserNickname,
+		Phone:        entry.Phone,
+		ItemID:       entry.ItemID,
+		ItemName:     entry.ItemName,
+		Date:         entry.Date,
+		Status:       models.StatusPending,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	// Принятие предложения и заявка сохраняются в одной транзакции
+	if err := s.bookingService.CreateWaitlistBooking(ctx, entry.ID, booking); err != nil {
+		switch {
+		case errors.Is(err, database.ErrWaitlistOfferExpired):
+			// Предложение уже закрыто обработчиком истёкших предложений
+		case errors.Is(err, database.ErrNotAvailable):
+			// Место успели занять: возвращаем пользователя в очередь с сохранением позиции
+			if errStatus := s.repo.UpdateWaitlistStatus(ctx, entry.ID, models.WaitlistStatusOffered, models.WaitlistStatusWaiting); errStatus != nil {
+				s.logger.Error().Err(errStatus).Int64("waitlist_id", entry.ID).Msg("waitlist: return entry to queue")
+			}
+		default:
+			// Заявку создать не удалось: закрываем предложение, чтобы оно не держало единицу
+			s.releaseOffer(ctx, entry)
+		}
+		return nil, err
+	}
+
+	s.logger.Info().
+		Int64("waitlist_id", entry.ID).
+		Int64("booking_id", booking.ID).
+		Int64("user_id", userID).
+		Msg("waitlist offer accepted")
+
+	return booking, nil
+}
+
+func (s *WaitlistService) DeclineOffer(ctx context.Context, entryID, userID int64) error {
+	entry, err := s.getOwnEntry(ctx, entryID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateWaitlistStatus(ctx, entry.ID, models.WaitlistStatusOffered, models.WaitlistStatusDeclined); err != nil {
+		if errors.Is(err, database.ErrConcurrentModification) {
+			return database.ErrWaitlistOfferExpired
+		}
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.offerFreeSlots(ctx, entry.ItemID, entry.Date)
+}
+
+// releaseOffer закрывает предложение, которое не удалось довести до заявки, и передает
+// единицу следующему в очереди.
+func (s *WaitlistService) releaseOffer(ctx context.Context, entry *models.WaitlistEntry) {
+	if err := s.repo.UpdateWaitlistStatus(ctx, entry.ID, models.WaitlistStatusOffered, models.WaitlistStatusExpired); err != nil {
+		if !errors.Is(err, database.ErrConcurrentModification) {
+			s.logger.Error().Err(err).Int64("waitlist_id", entry.ID).Msg("waitlist: expire failed offer")
+		}
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.offerFreeSlots(ctx, entry.ItemID, entry.Date); err != nil {
+		s.logger.Error().Err(err).Int64("item_id", entry.ItemID).Str("date", entry.Date.Format("2006-01-02")).Msg("waitlist: offer next")
+	}
+}
+
+func (s *WaitlistService) getOwnEntry(ctx context.Context, entryID, userID int64) (*models.WaitlistEntry, error) {
+	entry, err := s.repo.GetWaitlistEntry(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.UserID != userID {
+		return nil, fmt.Errorf("waitlist entry %d does not belong to user %d", entryID, userID)
+	}
+	return entry, nil
+}
+
+// offerFreeSlots предлагает свободные единицы следующим в очереди. Вызывается под s.mu.
+// Действующие предложения уже учтены в доступности: каждое держит свою единицу.
+func (s *WaitlistService) offerFreeSlots(ctx context.Context, itemID int64, date time.Time) error {
+	today := time.Now().Truncate(24 * time.Hour)
+	if date.Before(today) {
+		return nil
+	}
+
+	availability, err := s.bookingService.GetAvailability(ctx, itemID, date, 1)
+	if err != nil {
+		return fmt.Errorf("get availability: %w", err)
+	}
+	if len(availability) == 0 {
+		return nil
+	}
+
+	free := int(availability[0].Available)
+	for free > 0 {
+		entry, err := s.repo.GetNextWaitlistEntry(ctx, itemID, date)
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			return nil
+		}
+
+		expiresAt := time.Now().Add(s.offerTTL)
+		if err := s.repo.MarkWaitlistOffered(ctx, entry.ID, expiresAt); err != nil {
+			if errors.Is(err, database.ErrNotAvailable) {
+				// Единицу успели забронировать в обход очереди
+				return nil
+			}
+			return err
+		}
+		entry.Status = models.WaitlistStatusOffered
+		entry.OfferExpiresAt = &expiresAt
+
+		if s.notifier != nil {
+			if err := s.notifier.NotifyWaitlistOffer(ctx, entry); err != nil {
+				// Пользователь не узнает о предложении: закрываем его и предлагаем единицу следующему
+				s.logger.Error().Err(err).Int64("waitlist_id", entry.ID).Msg("waitlist: notify offer")
+				if errStatus := s.repo.UpdateWaitlistStatus(ctx, entry.ID, models.WaitlistStatusOffered, models.WaitlistStatusExpired); errStatus != nil {
+					return errStatus
+				}
+				continue
+			}
+		}
+
+		s.logger.Info().
+			Int64("waitlist_id", entry.ID).
+			Int64("user_id", entry.UserID).
+			Int64("item_id", itemID).
+			Str("date", date.Format("2006-01-02")).
+			Time("expires_at", expiresAt).
+			Msg("waitlist offer sent")
+		free--
+	}
+	return nil
+}
+
+func (s *WaitlistService) processExpiredOffers(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.repo.ExpirePastWaitlistEntries(ctx, now.Truncate(24*time.Hour)); err != nil {
+		s.logger.Error().Err(err).Msg("waitlist: expire past entries")
+	}
+
+	expired, err := s.repo.GetExpiredWaitlistOffers(ctx, now)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("waitlist: load expired offers")
+		return
+	}
+
+	type slot struct {
+		itemID int64
+		date   string
+	}
+	released := make(map[slot]time.Time)
+
+	for _, entry := range expired {
+		err := s.repo.UpdateWaitlistStatus(ctx, entry.ID, models.WaitlistStatusOffered, models.WaitlistStatusExpired)
+		if err != nil {
+			// Пользователь мог успеть принять предложение
+			if !errors.Is(err, database.ErrConcurrentModification) {
+				s.logger.Error().Err(err).Int64("waitlist_id", entry.ID).Msg("waitlist: expire offer")
+			}
+			continue
+		}
+		entry.Status = models.WaitlistStatusExpired
+
+		if s.notifier != nil {
+			if err := s.notifier.NotifyWaitlistOfferExpired(ctx, entry); err != nil {
+				s.logger.Error().Err(err).Int64("waitlist_id", entry.ID).Msg("waitlist: notify expired")
+			}
+		}
+		released[slot{itemID: entry.ItemID, date: entry.Date.Format("2006-01-02")}] = entry.Date
+	}
+
+	for key, date := range released {
+		if err := s.offerFreeSlots(ctx, key.itemID, date); err != nil {
+			s.logger.Error().Err(err).Int64("item_id", key.itemID).Str("date", key.date).Msg("waitlist: offer next")
+		}
+	}
+}
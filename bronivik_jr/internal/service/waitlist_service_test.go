@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"bronivik/internal/database"
+	"bronivik/internal/domain"
+	"bronivik/internal/models"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWaitlistRepo struct {
+	mock.Mock
+}
+
+func (m *mockWaitlistRepo) AddWaitlistEntry(ctx context.Context, e *models.WaitlistEntry) error {
+	return m.Called(ctx, e).Error(0)
+}
+func (m *mockWaitlistRepo) GetWaitlistEntry(ctx context.Context, id int64) (*models.WaitlistEntry, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WaitlistEntry), args.Error(1)
+}
+func (m *mockWaitlistRepo) GetNextWaitlistEntry(ctx context.Context, itemID int64, d time.Time) (*models.WaitlistEntry, error) {
+	args := m.Called(ctx, itemID, d)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WaitlistEntry), args.Error(1)
+}
+func (m *mockWaitlistRepo) MarkWaitlistOffered(ctx context.Context, id int64, exp time.Time) error {
+	return m.Called(ctx, id, exp).Error(0)
+}
+func (m *mockWaitlistRepo) UpdateWaitlistStatus(ctx context.Context, id int64, from, to string) error {
+	return m.Called(ctx, id, from, to).Error(0)
+}
+func (m *mockWaitlistRepo) GetExpiredWaitlistOffers(ctx context.Context, now time.Time) ([]*models.WaitlistEntry, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.WaitlistEntry), args.Error(1)
+}
+func (m *mockWaitlistRepo) ExpirePastWaitlistEntries(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type mockBookingService struct {
+	domain.BookingService
+	mock.Mock
+}
+
+func (m *mockBookingService) ValidateBookingDate(d time.Time) error {
+	return m.Called(d).Error(0)
+}
+func (m *mockBookingService) CreateWaitlistBooking(ctx context.Context, entryID int64, b *models.Booking) error {
+	return m.Called(ctx, entryID, b).Error(0)
+}
+func (m *mockBookingService) GetAvailability(ctx context.Context, itemID int64, start time.Time, days int) ([]*models.Availability, error) {
+	args := m.Called(ctx, itemID, start, days)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Availability), args.Error(1)
+}
+
+type mockWaitlistNotifier struct {
+	mock.Mock
+}
+
+func (m *mockWaitlistNotifier) NotifyWaitlistOffer(ctx context.Context, e *models.WaitlistEntry) error {
+	return m.Called(ctx, e).Error(0)
+}
+func (m *mockWaitlistNotifier) NotifyWaitlistOfferExpired(ctx context.Context, e *models.WaitlistEntry) error {
+	return m.Called(ctx, e).Error(0)
+}
+
+func setupWaitlistService() (*WaitlistService, *mockWaitlistRepo, *mockBookingService, *mockWaitlistNotifier) {
+	repo := new(mockWaitlistRepo)
+	bookings := new(mockBookingService)
+	notifier := new(mockWaitlistNotifier)
+	logger := zerolog.New(io.Discard)
+	svc := NewWaitlistService(repo, bookings, time.Hour, &logger)
+	svc.SetNotifier(notifier)
+	return svc, repo, bookings, notifier
+}
+
+func TestWaitlistService_HandleSlotReleased(t *testing.T) {
+	svc, repo, bookings, notifier := setupWaitlistService()
+	ctx := context.Background()
+	date := time.Now().AddDate(0, 0, 2).Truncate(24 * time.Hour)
+	entry := &models.WaitlistEntry{ID: 7, UserID: 42, ChatID: 42, ItemID: 1, Date: date, Status: models.WaitlistStatusWaiting}
+
+	bookings.On("GetAvailability", ctx, int64(1), date, 1).
+		Return([]*models.Availability{{Date: date, ItemID: 1, Available: 1}}, nil).Once()
+	repo.On("GetNextWaitlistEntry", ctx, int64(1), date).Return(entry, nil).Once()
+	repo.On("MarkWaitlistOffered", ctx, int64(7), mock.AnythingOfType("time.Time")).Return(nil).Once()
+	notifier.On("NotifyWaitlistOffer", ctx, entry).Return(nil).Once()
+
+	require.NoError(t, svc.HandleSlotReleased(ctx, 1, date))
+	assert.Equal(t, models.WaitlistStatusOffered, entry.Status)
+	require.NotNil(t, entry.OfferExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *entry.OfferExpiresAt, time.Minute)
+
+	repo.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+}
+
+func TestWaitlistService_HandleSlotReleased_OfferAlreadyOutstanding(t *testing.T) {
+	svc, repo, bookings, notifier := setupWaitlistService()
+	ctx := context.Background()
+	date := time.Now().AddDate(0, 0, 2).Truncate(24 * time.Hour)
+
+	// Единицу держит действующее предложение, доступность уже это учитывает
+	bookings.On("GetAvailability", ctx, int64(1), date, 1).
+		Return([]*models.Availability{{Date: date, ItemID: 1, Available: 0}}, nil).Once()
+
+	require.NoError(t, svc.HandleSlotReleased(ctx, 1, date))
+	repo.AssertNotCalled(t, "GetNextWaitlistEntry", mock.Anything, mock.Anything, mock.Anything)
+	notifier.AssertNotCalled(t, "NotifyWaitlistOffer", mock.Anything, mock.Anything)
+}
+
+func TestWaitlistService_HandleSlotReleased_NotifyFailed(t *testing.T) {
+	svc, repo, bookings, notifier := setupWaitlistService()
+	ctx := context.Background()
+	date := time.Now().AddDate(0, 0, 2).Truncate(24 * time.Hour)
+	first := &models.WaitlistEntry{ID: 7, UserID: 42, ChatID: 42, ItemID: 1, Date: date, Status: models.WaitlistStatusWaiting}
+	second := &models.WaitlistEntry{ID: 8, UserID: 43, ChatID: 43, ItemID: 1, Date: date, Status: models.WaitlistStatusWaiting}
+
+	bookings.On("GetAvailability", ctx, int64(1), date, 1).
+		Return([]*models.Availability{{Date: date, ItemID: 1, Available: 1}}, nil).Once()
+	repo.On("GetNextWaitlistEntry", ctx, int64(1), date).Return(first, nil).Once()
+	repo.On("MarkWaitlistOffered", ctx, int64(7), mock.AnythingOfType("time.Time")).Return(nil).Once()
+	notifier.On("NotifyWaitlistOffer", ctx, first).Return(assert.AnError).Once()
+	repo.On("UpdateWaitlistStatus", ctx, int64(7), models.WaitlistStatusOffered, models.WaitlistStatusExpired).Return(nil).Once()
+	repo.On("GetNextWaitlistEntry", ctx, int64(1), date).Return(second, nil).Once()
+	repo.On("MarkWaitlistOffered", ctx, int64(8), mock.AnythingOfType("time.Time")).Return(nil).Once()
+	notifier.On("NotifyWaitlistOffer", ctx, second).Return(nil).Once()
+
+	require.NoError(t, svc.HandleSlotReleased(ctx, 1, date))
+	repo.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+}
+
+func TestWaitlistService_AcceptOffer(t *testing.T) {
+	ctx := context.Background()
+	date := time.Now().AddDate(0, 0, 2).Truncate(24 * time.Hour)
+	expiresAt := time.Now().Add(30 * time.Minute)
+
+	t.Run("Success", func(t *testing.T) {
+		svc, repo, bookings, _ := setupWaitlistService()
+		entry := &models.WaitlistEntry{
+			ID: 7, UserID: 42, UserName: "User", Phone: "+79990000000", ItemID: 1, ItemName: "Item",
+			Date: date, Status: models.WaitlistStatusOffered, OfferExpiresAt: &expiresAt,
+		}
+
+		repo.On("GetWaitlistEntry", ctx, int64(7)).Return(entry, nil).Once()
+		bookings.On("CreateWaitlistBooking", ctx, int64(7), mock.AnythingOfType("*models.Booking")).Run(func(args mock.Arguments) {
+			args.Get(2).(*models.Booking).ID = 100
+		}).Return(nil).Once()
+
+		booking, err := svc.AcceptOffer(ctx, 7, 42)
+		require.NoError(t, err)
+		assert.Equal(t, int64(100), booking.ID)
+		assert.Equal(t, int64(42), booking.UserID)
+		assert.Equal(t, models.StatusPending, booking.Status)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		svc, repo, bookings, _ := setupWaitlistService()
+		past := time.Now().Add(-time.Minute)
+		entry := &models.WaitlistEntry{ID: 8, UserID: 42, Date: date, Status: models.WaitlistStatusOffered, OfferExpiresAt: &past}
+
+		repo.On("GetWaitlistEntry", ctx, int64(8)).Return(entry, nil).Once()
+
+		_, err := svc.AcceptOffer(ctx, 8, 42)
+		assert.ErrorIs(t, err, database.ErrWaitlistOfferExpired)
+		bookings.AssertNotCalled(t, "CreateWaitlistBooking", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("WrongUser", func(t *testing.T) {
+		svc, repo, _, _ := setupWaitlistService()
+		entry := &models.WaitlistEntry{ID: 9, UserID: 42, Date: date, Status: models.WaitlistStatusOffered, OfferExpiresAt: &expiresAt}
+
+		repo.On("GetWaitlistEntry", ctx, int64(9)).Return(entry, nil).Once()
+
+		_, err := svc.AcceptOffer(ctx, 9, 43)
+		assert.Error(t, err)
+	})
+
+	t.Run("SlotTaken", func(t *testing.T) {
+		svc, repo, bookings, _ := setupWaitlistService()
+		entry := &models.WaitlistEntry{ID: 10, UserID: 42, Date: date, Status: models.WaitlistStatusOffered, OfferExpiresAt: &expiresAt}
+
+		repo.On("GetWaitlistEntry", ctx, int64(10)).Return(entry, nil).Once()
+		bookings.On("CreateWaitlistBooking", ctx, int64(10), mock.Anything).Return(database.ErrNotAvailable).Once()
+		repo.On("UpdateWaitlistStatus", ctx, int64(10), models.WaitlistStatusOffered, models.WaitlistStatusWaiting).Return(nil).Once()
+
+		_, err := svc.AcceptOffer(ctx, 10, 42)
+		assert.ErrorIs(t, err, database.ErrNotAvailable)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("BookingFailed", func(t *testing.T) {
+		svc, repo, bookings, _ := setupWaitlistService()
+		entry := &models.WaitlistEntry{ID: 11, UserID: 42, ItemID: 1, Date: date, Status: models.WaitlistStatusOffered, OfferExpiresAt: &expiresAt}
+
+		repo.On("GetWaitlistEntry", ctx, int64(11)).Return(entry, nil).Once()
+		bookings.On("CreateWaitlistBooking", ctx, int64(11), mock.Anything).Return(assert.AnError).Once()
+		repo.On("UpdateWaitlistStatus", ctx, int64(11), models.WaitlistStatusOffered, models.WaitlistStatusExpired).Return(nil).Once()
+		bookings.On("GetAvailability", ctx, int64(1), date, 1).
+			Return([]*models.Availability{{Date: date, ItemID: 1, Available: 1}}, nil).Once()
+		repo.On("GetNextWaitlistEntry", ctx, int64(1), date).Return(nil, nil).Once()
+
+		_, err := svc.AcceptOffer(ctx, 11, 42)
+		assert.ErrorIs(t, err, assert.AnError)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestWaitlistService_ProcessExpiredOffers(t *testing.T) {
+	svc, repo, bookings, notifier := setupWaitlistService()
+	ctx := context.Background()
+	now := time.Now()
+	date := now.AddDate(0, 0, 2).Truncate(24 * time.Hour)
+	past := now.Add(-time.Minute)
+
+	expired := &models.WaitlistEntry{ID: 1, UserID: 10, ItemID: 1, Date: date, Status: models.WaitlistStatusOffered, OfferExpiresAt: &past}
+	next := &models.WaitlistEntry{ID: 2, UserID: 20, ItemID: 1, Date: date, Status: models.WaitlistStatusWaiting}
+
+	repo.On("ExpirePastWaitlistEntries", ctx, mock.AnythingOfType("time.Time")).Return(int64(0), nil).Once()
+	repo.On("GetExpiredWaitlistOffers", ctx, now).Return([]*models.WaitlistEntry{expired}, nil).Once()
+	repo.On("UpdateWaitlistStatus", ctx, int64(1), models.WaitlistStatusOffered, models.WaitlistStatusExpired).Return(nil).Once()
+	notifier.On("NotifyWaitlistOfferExpired", ctx, expired).Return(nil).Once()
+
+	bookings.On("GetAvailability", ctx, int64(1), date, 1).
+		Return([]*models.Availability{{Date: date, ItemID: 1, Available: 1}}, nil).Once()
+	repo.On("GetNextWaitlistEntry", ctx, int64(1), date).Return(next, nil).Once()
+	repo.On("MarkWaitlistOffered", ctx, int64(2), mock.AnythingOfType("time.Time")).Return(nil).Once()
+	notifier.On("NotifyWaitlistOffer", ctx, next).Return(nil).Once()
+
+	svc.processExpiredOffers(ctx, now)
+
+	repo.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+}
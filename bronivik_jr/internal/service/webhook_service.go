@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"bronivik/internal/domain"
+	"bronivik/internal/events"
+	"bronivik/internal/models"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	webhookSecretBytes        = 32
+	defaultWebhookListLimit   = 50
+	maxWebhookDeliveriesLimit = 500
+)
+
+// WebhookService управляет подписками на вебхуки и повторной отправкой доставок.
+type WebhookService struct {
+	repo   domain.WebhookRepository
+	logger *zerolog.Logger
+}
+
+func NewWebhookService(repo domain.WebhookRepository, logger *zerolog.Logger) *WebhookService {
+	return &WebhookService{repo: repo, logger: logger}
+}
+
+// CreateSubscription проверяет URL и типы событий и создает подписку со случайным секретом.
+// Секрет возвращается только в результате этого вызова.
+func (s *WebhookService) CreateSubscription(
+	ctx context.Context,
+	name, rawURL string,
+	eventTypes []string,
+) (*models.WebhookSubscription, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, models.ErrInvalidWebhookURL
+	}
+
+	types, err := normalizeWebhookEventTypes(eventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("generate webhook secret: %w", err)
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = u.Host
+	}
+	sub := &models.WebhookSubscription{
+		Name:       name,
+		URL:        u.String(),
+		EventTypes: types,
+		Secret:     hex.EncodeToString(secret),
+	}
+	if err := s.repo.CreateWebhookSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	s.logger.Info().Int64("subscription_id", sub.ID).Str("url", sub.URL).Strs("events", types).Msg("webhook subscription created")
+	return sub, nil
+}
+
+// normalizeWebhookEventTypes убирает дубли; пустой список или "*" означает все события.
+func normalizeWebhookEventTypes(eventTypes []string) ([]string, error) {
+	if len(eventTypes) == 0 || (len(eventTypes) == 1 && strings.TrimSpace(eventTypes[0]) == "*") {
+		return append([]string(nil), events.BookingEventTypes...), nil
+	}
+
+	known := make(map[string]bool, len(events.BookingEventTypes))
+	for _, t := range events.BookingEventTypes {
+		known[t] = true
+	}
+
+	seen := make(map[string]bool, len(eventTypes))
+	var types []string
+	for _, t := range eventTypes {
+		t = strings.TrimSpace(t)
+		if !known[t] {
+			return nil, fmt.Errorf("%w: %q", models.ErrInvalidWebhookEventType, t)
+		}
+		if !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+	return types, nil
+}
+
+func (s *WebhookService) ListSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	return s.repo.GetWebhookSubscriptions(ctx, false)
+}
+
+func (s *WebhookService) DeactivateSubscription(ctx context.Context, id int64) error {
+	return s.repo.DeactivateWebhookSubscription(ctx, id)
+}
+
+// ListDeliveries возвращает журнал доставок; status=dead — очередь недоставленных событий.
+func (s *WebhookService) ListDeliveries(ctx context.Context, status string, limit int) ([]*models.WebhookDelivery, error) {
+	switch status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliveryDelivered, models.WebhookDeliveryDead:
+	default:
+		return nil, fmt.Errorf("%w: %q", models.ErrInvalidWebhookStatus, status)
+	}
+	if limit <= 0 {
+		limit = defaultWebhookListLimit
+	}
+	if limit > maxWebhookDeliveriesLimit {
+		limit = maxWebhookDeliveriesLimit
+	}
+	return s.repo.GetWebhookDeliveries(ctx, status, limit)
+}
+
+func (s *WebhookService) Redeliver(ctx context.Context, deliveryID int64) error {
+	if err := s.repo.RedeliverWebhook(ctx, deliveryID); err != nil {
+		return err
+	}
+	s.logger.Info().Int64("delivery_id", deliveryID).Msg("webhook delivery requeued")
+	return nil
+}
+
+func (s *WebhookService) RedeliverDead(ctx context.Context) (int64, error) {
+	n, err := s.repo.RedeliverDeadWebhooks(ctx)
+	if err != nil {
+		return 0, err
+	}
+	s.logger.Info().Int64("count", n).Msg("dead webhook deliveries requeued")
+	return n, nil
+}
@@ -0,0 +1,76 @@
+package worker
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"bronivik/internal/database"
+
+	"github.com/rs/zerolog"
+)
+
+// EncryptionWorker перешифровывает телефоны и имена клиентов основным ключом:
+// открытые значения, записанные до включения шифрования, и значения под
+// ключами, которые выводятся из ротации.
+type EncryptionWorker struct {
+	db        *database.DB
+	interval  time.Duration
+	batchSize int
+	logger    *zerolog.Logger
+}
+
+// NewEncryptionWorker builds a worker with sane defaults.
+func NewEncryptionWorker(db *database.DB, interval time.Duration, batchSize int, logger *zerolog.Logger) *EncryptionWorker {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	if batchSize <= 0 {
+		batchSize = 200
+	}
+	if logger == nil {
+		l := zerolog.New(os.Stdout).With().Timestamp().Logger()
+		logger = &l
+	}
+	return &EncryptionWorker{db: db, interval: interval, batchSize: batchSize, logger: logger}
+}
+
+// Start launches main loop; stops when ctx is done.
+func (w *EncryptionWorker) Start(ctx context.Context) {
+	w.logger.Info().Msg("encryption_worker: started")
+	defer w.logger.Info().Msg("encryption_worker: stopped")
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.RotateAll(ctx); err != nil && ctx.Err() == nil {
+			w.logger.Error().Err(err).Msg("encryption_worker: rotate fields")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RotateAll перешифровывает пачками, пока не останется строк под старыми ключами.
+func (w *EncryptionWorker) RotateAll(ctx context.Context) error {
+	total := 0
+	for ctx.Err() == nil {
+		n, err := w.db.RotateFieldEncryption(ctx, w.batchSize)
+		total += n
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			break
+		}
+	}
+	if total > 0 {
+		w.logger.Info().Int("rows", total).Msg("encryption_worker: fields re-encrypted")
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"bronivik/internal/retention"
+
+	"github.com/rs/zerolog"
+)
+
+// RetentionWorker по расписанию применяет политику хранения персональных данных.
+// В режиме dryRun только пишет в лог, сколько строк затронул бы каждый прогон.
+type RetentionWorker struct {
+	engine   *retention.Engine
+	interval time.Duration
+	dryRun   bool
+	logger   *zerolog.Logger
+}
+
+// NewRetentionWorker builds a worker with sane defaults.
+func NewRetentionWorker(engine *retention.Engine, interval time.Duration, dryRun bool, logger *zerolog.Logger) *RetentionWorker {
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+	if logger == nil {
+		l := zerolog.New(os.Stdout).With().Timestamp().Logger()
+		logger = &l
+	}
+	return &RetentionWorker{engine: engine, interval: interval, dryRun: dryRun, logger: logger}
+}
+
+// Start launches main loop; stops when ctx is done.
+func (w *RetentionWorker) Start(ctx context.Context) {
+	w.logger.Info().Bool("dry_run", w.dryRun).Int("rules", len(w.engine.Rules())).Msg("retention_worker: started")
+	defer w.logger.Info().Msg("retention_worker: stopped")
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			w.logger.Error().Err(err).Msg("retention_worker: apply policy")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce применяет политику один раз и пишет итог по каждому правилу.
+func (w *RetentionWorker) RunOnce(ctx context.Context) error {
+	report, err := w.engine.Run(ctx, w.dryRun)
+	for _, res := range report.Results {
+		if res.Rows == 0 {
+			continue
+		}
+		w.logger.Info().
+			Str("rule", res.Rule.String()).
+			Time("cutoff", res.Cutoff).
+			Int64("rows", res.Rows).
+			Bool("dry_run", w.dryRun).
+			Msg("retention_worker: rule applied")
+	}
+	return err
+}
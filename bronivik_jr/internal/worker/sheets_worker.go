@@ -2,6 +2,7 @@ package worker
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -31,13 +32,13 @@ type SheetTask struct {
 	CreatedAt time.Time
 }
 
-// sheetTaskPayload is persisted in SyncTask.Payload as JSON.
+// sheetTaskPayload is persisted in SyncTask.Payload as JSON. It holds no
+// personal data: upsert tasks reload the booking when they run.
 type sheetTaskPayload struct {
-	BookingID int64           `json:"booking_id"`
-	Booking   *models.Booking `json:"booking,omitempty"`
-	Status    string          `json:"status,omitempty"`
-	StartDate time.Time       `json:"start_date,omitempty"`
-	EndDate   time.Time       `json:"end_date,omitempty"`
+	BookingID int64     `json:"booking_id"`
+	Status    string    `json:"status,omitempty"`
+	StartDate time.Time `json:"start_date,omitempty"`
+	EndDate   time.Time `json:"end_date,omitempty"`
 }
 
 // SheetsWorker consumes sync_queue tasks and applies them to Google Sheets.
@@ -106,22 +107,18 @@ func NewSheetsWorker(
 }
 
 // EnqueueTask persists task to DB and schedules it via redis or in-memory queue.
-func (w *SheetsWorker) EnqueueTask(ctx context.Context, taskType string, bookingID int64, booking *models.Booking, status string) error {
+func (w *SheetsWorker) EnqueueTask(ctx context.Context, taskType string, bookingID int64, status string) error {
 	if taskType == "" {
 		return errors.New("task type is required")
 	}
-	if bookingID == 0 && (booking == nil || booking.ID == 0) {
+	if bookingID == 0 {
 		return errors.New("booking id is required")
 	}
 
 	payload := sheetTaskPayload{
 		BookingID: bookingID,
-		Booking:   booking,
 		Status:    status,
 	}
-	if payload.BookingID == 0 && booking != nil {
-		payload.BookingID = booking.ID
-	}
 
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
@@ -250,10 +247,18 @@ func (w *SheetsWorker) processTask(ctx context.Context, task *models.SyncTask) {
 func (w *SheetsWorker) handleSheetTask(ctx context.Context, taskType string, payload *sheetTaskPayload) error {
 	switch taskType {
 	case TaskUpsert:
-		if payload.Booking == nil {
-			return errors.New("booking payload missing")
+		if payload.BookingID == 0 {
+			return errors.New("booking id missing")
+		}
+		booking, err := w.db.GetBooking(ctx, payload.BookingID)
+		if errors.Is(err, sql.ErrNoRows) {
+			w.logger.Warn().Int64("booking_id", payload.BookingID).Msg("sheets_worker: booking to upsert not found")
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("load booking: %w", err)
 		}
-		return w.sheets.UpsertBooking(ctx, payload.Booking)
+		return w.sheets.UpsertBooking(ctx, booking)
 	case TaskDelete:
 		if payload.BookingID == 0 {
 			return errors.New("booking id missing")
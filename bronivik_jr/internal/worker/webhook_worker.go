@@ -0,0 +1,222 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"bronivik/internal/database"
+	"bronivik/internal/events"
+	"bronivik/internal/models"
+
+	"github.com/rs/zerolog"
+)
+
+// Заголовки исходящих вебхуков.
+const (
+	WebhookHeaderEvent     = "X-Bronivik-Event"
+	WebhookHeaderDelivery  = "X-Bronivik-Delivery"
+	WebhookHeaderTimestamp = "X-Bronivik-Timestamp"
+	WebhookHeaderSignature = "X-Bronivik-Signature"
+)
+
+// webhookBody — тело запроса к подписчику.
+type webhookBody struct {
+	DeliveryID int64           `json:"delivery_id"`
+	EventID    int64           `json:"event_id"`
+	Type       string          `json:"type"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// SignWebhookPayload возвращает подпись "sha256=<hex>" от строки "<timestamp>.<body>".
+// Получатель вычисляет ее тем же секретом и сравнивает с заголовком X-Bronivik-Signature.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookWorker отправляет доставки из webhook_deliveries с экспоненциальными повторами.
+type WebhookWorker struct {
+	db           *database.DB
+	client       *http.Client
+	retryPolicy  RetryPolicy
+	pollInterval time.Duration
+	batchSize    int
+	retention    time.Duration
+	logger       *zerolog.Logger
+}
+
+// NewWebhookWorker builds a worker with sane defaults.
+func NewWebhookWorker(db *database.DB, client *http.Client, retry RetryPolicy, pollInterval time.Duration, logger *zerolog.Logger) *WebhookWorker {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if retry.MaxRetries == 0 {
+		retry.MaxRetries = 8
+	}
+	if retry.InitialDelay == 0 {
+		retry.InitialDelay = 10 * time.Second
+	}
+	if retry.MaxDelay == 0 {
+		retry.MaxDelay = time.Hour
+	}
+	if retry.BackoffFactor == 0 {
+		retry.BackoffFactor = 2
+	}
+	if pollInterval <= 0 {
+		pollInterval = 2 * time.Second
+	}
+	if logger == nil {
+		l := zerolog.New(os.Stdout).With().Timestamp().Logger()
+		logger = &l
+	}
+
+	return &WebhookWorker{
+		db:           db,
+		client:       client,
+		retryPolicy:  retry,
+		pollInterval: pollInterval,
+		batchSize:    20,
+		logger:       logger,
+	}
+}
+
+// SetRetention включает удаление доставленных вебхуков старше retention; 0 хранит их всегда.
+func (w *WebhookWorker) SetRetention(retention time.Duration) {
+	w.retention = retention
+}
+
+// HandleEvent ставит событие в очередь доставки подписчикам; подписывается на EventBus.
+// Ошибка возвращается диспетчеру событий для повтора.
+func (w *WebhookWorker) HandleEvent(ev *events.Event) error {
+	if ev.ID == 0 {
+		// Событие не из outbox: без ID нельзя исключить повторную доставку
+		return nil
+	}
+	if _, err := w.db.EnqueueWebhookDeliveries(context.Background(), ev); err != nil {
+		return fmt.Errorf("enqueue webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// Start launches main loop; stops when ctx is done.
+func (w *WebhookWorker) Start(ctx context.Context) {
+	w.logger.Info().Msg("webhook_worker: started")
+	defer w.logger.Info().Msg("webhook_worker: stopped")
+
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	lastCleanup := time.Time{}
+	for {
+		if err := w.ProcessDue(ctx); err != nil && ctx.Err() == nil {
+			w.logger.Error().Err(err).Msg("webhook_worker: fetch due deliveries")
+		}
+
+		if w.retention > 0 && time.Since(lastCleanup) > time.Hour {
+			lastCleanup = time.Now()
+			if n, err := w.db.DeleteDeliveredWebhooks(ctx, time.Now().Add(-w.retention)); err != nil {
+				w.logger.Error().Err(err).Msg("webhook_worker: delete delivered webhooks")
+			} else if n > 0 {
+				w.logger.Info().Int64("deleted", n).Msg("webhook_worker: delivered webhooks cleaned up")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue отправляет одну пачку доставок, срок которых наступил.
+func (w *WebhookWorker) ProcessDue(ctx context.Context) error {
+	deliveries, err := w.db.GetDueWebhookDeliveries(ctx, time.Now(), w.batchSize)
+	if err != nil {
+		return err
+	}
+	for _, d := range deliveries {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		w.deliver(ctx, d)
+	}
+	return nil
+}
+
+func (w *WebhookWorker) deliver(ctx context.Context, d *models.WebhookDelivery) {
+	code, err := w.send(ctx, d)
+	if err == nil {
+		if uerr := w.db.MarkWebhookDelivered(ctx, d.ID, code); uerr != nil {
+			w.logger.Error().Err(uerr).Int64("delivery_id", d.ID).Msg("webhook_worker: mark delivered")
+		}
+		return
+	}
+
+	attempt := d.Attempts + 1
+	var next *time.Time
+	if attempt < w.retryPolicy.MaxRetries {
+		t := time.Now().Add(w.retryPolicy.NextDelay(attempt))
+		next = &t
+	}
+	w.logger.Warn().Err(err).
+		Int64("delivery_id", d.ID).
+		Int64("subscription_id", d.SubscriptionID).
+		Int("attempt", attempt).
+		Bool("dead", next == nil).
+		Msg("webhook_worker: delivery failed")
+
+	if uerr := w.db.MarkWebhookFailed(ctx, d.ID, code, err.Error(), next); uerr != nil {
+		w.logger.Error().Err(uerr).Int64("delivery_id", d.ID).Msg("webhook_worker: mark failed")
+	}
+}
+
+func (w *WebhookWorker) send(ctx context.Context, d *models.WebhookDelivery) (int, error) {
+	body, err := json.Marshal(webhookBody{
+		DeliveryID: d.ID,
+		EventID:    d.EventID,
+		Type:       d.EventType,
+		CreatedAt:  d.CreatedAt,
+		Data:       json.RawMessage(d.Payload),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("encode body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("build request: %w", err)
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookHeaderEvent, d.EventType)
+	req.Header.Set(WebhookHeaderDelivery, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(WebhookHeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookHeaderSignature, SignWebhookPayload(d.Secret, timestamp, body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"bronivik/internal/events"
+	"bronivik/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookWorkerDeliversSignedRequests(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	var secret string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get(WebhookHeaderTimestamp), 10, 64)
+		assert.Equal(t, SignWebhookPayload(secret, ts, body), r.Header.Get(WebhookHeaderSignature))
+		assert.Equal(t, events.EventBookingConfirmed, r.Header.Get(WebhookHeaderEvent))
+
+		// Первая попытка неудачна
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	sub := &models.WebhookSubscription{Name: "crm", URL: srv.URL, EventTypes: []string{events.EventBookingConfirmed}, Secret: "s3cret"}
+	require.NoError(t, db.CreateWebhookSubscription(ctx, sub))
+	secret = sub.Secret
+
+	w := NewWebhookWorker(db, srv.Client(), RetryPolicy{MaxRetries: 3, InitialDelay: time.Millisecond}, 0, nil)
+	require.NoError(t, w.HandleEvent(&events.Event{ID: 7, Type: events.EventBookingConfirmed, Payload: []byte(`{"booking_id":1}`)}))
+	// Событие без подписки и повторная обработка того же события доставок не добавляют
+	require.NoError(t, w.HandleEvent(&events.Event{ID: 8, Type: events.EventBookingCreated, Payload: []byte(`{}`)}))
+	require.NoError(t, w.HandleEvent(&events.Event{ID: 7, Type: events.EventBookingConfirmed, Payload: []byte(`{"booking_id":1}`)}))
+
+	require.NoError(t, w.ProcessDue(ctx))
+	pending, err := db.GetWebhookDeliveries(ctx, models.WebhookDeliveryPending, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, 1, pending[0].Attempts)
+	assert.Equal(t, http.StatusServiceUnavailable, pending[0].ResponseCode)
+
+	time.Sleep(5 * time.Millisecond)
+	require.NoError(t, w.ProcessDue(ctx))
+	delivered, err := db.GetWebhookDeliveries(ctx, models.WebhookDeliveryDelivered, 10)
+	require.NoError(t, err)
+	require.Len(t, delivered, 1)
+	assert.Equal(t, int64(7), delivered[0].EventID)
+	assert.Equal(t, int32(2), calls.Load())
+
+	// Доставленные вебхуки удаляются по истечении срока хранения
+	n, err := db.DeleteDeliveredWebhooks(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	n, err = db.DeleteDeliveredWebhooks(ctx, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
+
+func TestWebhookWorkerMovesToDeadLetter(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(srv.Close)
+
+	sub := &models.WebhookSubscription{Name: "acc", URL: srv.URL, EventTypes: []string{events.EventBookingCanceled}, Secret: "x"}
+	require.NoError(t, db.CreateWebhookSubscription(ctx, sub))
+
+	w := NewWebhookWorker(db, srv.Client(), RetryPolicy{MaxRetries: 1}, 0, nil)
+	require.NoError(t, w.HandleEvent(&events.Event{ID: 1, Type: events.EventBookingCanceled, Payload: []byte(`{}`)}))
+	require.NoError(t, w.ProcessDue(ctx))
+
+	dead, err := db.GetWebhookDeliveries(ctx, models.WebhookDeliveryDead, 10)
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	assert.Contains(t, dead[0].LastError, "500")
+
+	n, err := db.RedeliverDeadWebhooks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	due, err := db.GetDueWebhookDeliveries(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, 0, due[0].Attempts)
+}
@@ -23,21 +23,9 @@ func TestProcessTaskSuccess(t *testing.T) {
 	sheets := &fakeSheets{}
 	worker := NewSheetsWorker(db, sheets, nil, RetryPolicy{}, nil)
 
-	booking := &models.Booking{
-		ID:        1,
-		UserID:    1,
-		UserName:  "tester",
-		Phone:     "+100",
-		ItemID:    10,
-		ItemName:  "camera",
-		Date:      time.Now(),
-		Status:    "pending",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
 	ctx := context.Background()
-	if err := worker.EnqueueTask(ctx, TaskUpsert, booking.ID, booking, ""); err != nil {
+	booking := createTestBooking(t, db)
+	if err := worker.EnqueueTask(ctx, TaskUpsert, booking.ID, ""); err != nil {
 		t.Fatalf("enqueue: %v", err)
 	}
 
@@ -67,14 +55,9 @@ func TestProcessTaskRetry(t *testing.T) {
 	sheets := &fakeSheets{err: errors.New("boom")}
 	worker := NewSheetsWorker(db, sheets, nil, RetryPolicy{MaxRetries: 3, InitialDelay: time.Second}, nil)
 
-	booking := &models.Booking{
-		ID: 2, UserID: 1, UserName: "tester", Phone: "+100",
-		ItemID: 10, ItemName: "camera", Date: time.Now(),
-		Status: "pending", CreatedAt: time.Now(), UpdatedAt: time.Now(),
-	}
-
 	ctx := context.Background()
-	if err := worker.EnqueueTask(ctx, TaskUpsert, booking.ID, booking, ""); err != nil {
+	booking := createTestBooking(t, db)
+	if err := worker.EnqueueTask(ctx, TaskUpsert, booking.ID, ""); err != nil {
 		t.Fatalf("enqueue: %v", err)
 	}
 
@@ -101,14 +84,9 @@ func TestProcessTaskFail(t *testing.T) {
 	sheets := &fakeSheets{err: errors.New("fatal")}
 	worker := NewSheetsWorker(db, sheets, nil, RetryPolicy{MaxRetries: 1}, nil)
 
-	booking := &models.Booking{
-		ID: 3, UserID: 1, UserName: "tester", Phone: "+100",
-		ItemID: 10, ItemName: "camera", Date: time.Now(),
-		Status: "pending", CreatedAt: time.Now(), UpdatedAt: time.Now(),
-	}
-
 	ctx := context.Background()
-	err := worker.EnqueueTask(ctx, TaskUpsert, booking.ID, booking, "")
+	booking := createTestBooking(t, db)
+	err := worker.EnqueueTask(ctx, TaskUpsert, booking.ID, "")
 	require.NoError(t, err)
 	task, _ := worker.tryLocalQueue()
 	worker.processTask(ctx, &task)
@@ -233,24 +211,35 @@ func TestSheetsWorker_EnqueueTask(t *testing.T) {
 	worker := NewSheetsWorker(db, sheets, nil, RetryPolicy{}, nil)
 
 	ctx := context.Background()
-	booking := &models.Booking{ID: 1, UserName: "test"}
 
 	t.Run("ValidTask", func(t *testing.T) {
-		err := worker.EnqueueTask(ctx, TaskUpsert, 1, booking, "")
+		err := worker.EnqueueTask(ctx, TaskUpsert, 1, "")
 		if err != nil {
 			t.Fatalf("enqueue: %v", err)
 		}
 	})
 
+	t.Run("PayloadHoldsOnlyBookingID", func(t *testing.T) {
+		booking := createTestBooking(t, db)
+		err := worker.EnqueueTask(ctx, TaskUpsert, booking.ID, "")
+		require.NoError(t, err)
+		tasks, err := db.GetPendingSyncTasks(ctx, 10)
+		require.NoError(t, err)
+		last := tasks[len(tasks)-1]
+		require.Equal(t, booking.ID, last.BookingID)
+		require.NotContains(t, last.Payload, booking.Phone)
+		require.NotContains(t, last.Payload, booking.UserName)
+	})
+
 	t.Run("InvalidTaskType", func(t *testing.T) {
-		err := worker.EnqueueTask(ctx, "", 1, booking, "")
+		err := worker.EnqueueTask(ctx, "", 1, "")
 		if err == nil {
 			t.Fatalf("expected error for empty task type")
 		}
 	})
 
 	t.Run("InvalidBookingID", func(t *testing.T) {
-		err := worker.EnqueueTask(ctx, TaskUpsert, 0, nil, "")
+		err := worker.EnqueueTask(ctx, TaskUpsert, 0, "")
 		if err == nil {
 			t.Fatalf("expected error for missing booking id")
 		}
@@ -317,14 +306,27 @@ func TestSheetsWorker_HandleSheetTask(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("Upsert", func(t *testing.T) {
-		booking := &models.Booking{ID: 1, ItemName: "Test"}
-		err := worker.handleSheetTask(ctx, TaskUpsert, &sheetTaskPayload{Booking: booking})
+		booking := createTestBooking(t, db)
+		err := worker.handleSheetTask(ctx, TaskUpsert, &sheetTaskPayload{BookingID: booking.ID})
 		if err != nil {
 			t.Fatalf("handle: %v", err)
 		}
 		if sheets.upsertCalls != 1 {
 			t.Fatalf("expected 1 upsert call, got %d", sheets.upsertCalls)
 		}
+		if sheets.upserted == nil || sheets.upserted.Phone != booking.Phone {
+			t.Fatalf("expected booking reloaded from db, got %+v", sheets.upserted)
+		}
+	})
+
+	t.Run("UpsertDeletedBooking", func(t *testing.T) {
+		err := worker.handleSheetTask(ctx, TaskUpsert, &sheetTaskPayload{BookingID: 999})
+		if err != nil {
+			t.Fatalf("handle: %v", err)
+		}
+		if sheets.upsertCalls != 1 {
+			t.Fatalf("expected no upsert for missing booking, got %d calls", sheets.upsertCalls)
+		}
 	})
 
 	t.Run("Delete", func(t *testing.T) {
@@ -409,8 +411,8 @@ func TestSheetsWorker_StartStop(t *testing.T) {
 	defer cancel()
 
 	// Add a task to the queue before starting
-	booking := &models.Booking{ID: 1, ItemName: "Test"}
-	err := worker.EnqueueTask(ctx, TaskUpsert, 1, booking, "")
+	booking := createTestBooking(t, db)
+	err := worker.EnqueueTask(ctx, TaskUpsert, booking.ID, "")
 	require.NoError(t, err)
 
 	// This should process the task and stop when ctx is done
@@ -431,10 +433,9 @@ func TestSheetsWorker_Redis(t *testing.T) {
 	worker.pollInterval = 10 * time.Millisecond
 
 	ctx := context.Background()
-	booking := &models.Booking{ID: 1, ItemName: "Test"}
 
 	t.Run("PushAndPop", func(t *testing.T) {
-		err := worker.EnqueueTask(ctx, TaskUpsert, 1, booking, "")
+		err := worker.EnqueueTask(ctx, TaskUpsert, 1, "")
 		if err != nil {
 			t.Fatalf("enqueue: %v", err)
 		}
@@ -512,6 +513,7 @@ func TestSheetsWorker_EnqueueSyncSchedule_QueueFull(t *testing.T) {
 
 type fakeSheets struct {
 	err         error
+	upserted    *models.Booking
 	upsertCalls int
 	deleteCalls int
 	statusCalls int
@@ -519,6 +521,7 @@ type fakeSheets struct {
 
 func (f *fakeSheets) UpsertBooking(ctx context.Context, b *models.Booking) error {
 	f.upsertCalls++
+	f.upserted = b
 	return f.err
 }
 
@@ -550,9 +553,26 @@ func newTestDB(t *testing.T) *database.DB {
 		t.Fatalf("new db: %v", err)
 	}
 	t.Cleanup(func() { db.Close() })
+	if err := db.Migrate(context.Background(), ""); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
 	return db
 }
 
+// createTestBooking stores a booking: upsert tasks reload it from the db.
+func createTestBooking(t *testing.T, db *database.DB) *models.Booking {
+	t.Helper()
+	ctx := context.Background()
+	item := &models.Item{Name: "camera", TotalQuantity: 1}
+	require.NoError(t, db.CreateItem(ctx, item))
+	booking := &models.Booking{
+		UserID: 1, UserName: "tester", Phone: "+100",
+		ItemID: item.ID, ItemName: item.Name, Date: time.Now(), Status: "pending",
+	}
+	require.NoError(t, db.CreateBooking(ctx, booking))
+	return booking
+}
+
 func loadTaskStatus(t *testing.T, db *database.DB, id int64) (status string, retryCount int, nextRetry sql.NullTime) {
 	t.Helper()
 	row := db.QueryRowContext(context.Background(), `SELECT status, retry_count, next_retry_at FROM sync_queue WHERE id = ?`, id)
@@ -0,0 +1,37 @@
+-- Migration: Create reminders table (PostgreSQL)
+-- Date: 2026-01-13
+-- Description: Extended reminder system with deduplication and retry support
+
+CREATE TABLE IF NOT EXISTS reminders (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    booking_id BIGINT NOT NULL,
+    reminder_type TEXT NOT NULL,              -- '24h_before', 'day_of_booking', 'custom'
+    scheduled_at TIMESTAMPTZ NOT NULL,        -- planned send time
+    sent_at TIMESTAMPTZ,                      -- actual send time (NULL if not sent)
+    status TEXT NOT NULL DEFAULT 'pending',   -- pending, scheduled, processing, sent, failed, cancelled
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    retry_count BIGINT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now(),
+
+    -- Unique constraint for deduplication
+    UNIQUE(user_id, booking_id, reminder_type)
+);
+
+-- Index for selecting pending reminders
+CREATE INDEX IF NOT EXISTS idx_reminders_pending
+ON reminders(scheduled_at, status, enabled);
+
+-- Index for user lookup
+CREATE INDEX IF NOT EXISTS idx_reminders_user
+ON reminders(user_id);
+
+-- Index for booking lookup
+CREATE INDEX IF NOT EXISTS idx_reminders_booking
+ON reminders(booking_id);
+
+-- Index for cleanup queries
+CREATE INDEX IF NOT EXISTS idx_reminders_cleanup
+ON reminders(status, sent_at);
@@ -0,0 +1,11 @@
+-- Rollback: Drop consent state and the consent log
+-- WARNING: This will delete all consent records
+
+DROP INDEX IF EXISTS idx_consent_log_user;
+DROP TABLE IF EXISTS consent_log;
+
+ALTER TABLE users DROP COLUMN consent_version;
+ALTER TABLE users DROP COLUMN consent_revoked_at;
+ALTER TABLE users DROP COLUMN consent_revoked;
+ALTER TABLE users DROP COLUMN consent_given_at;
+ALTER TABLE users DROP COLUMN consent_given;
@@ -0,0 +1,19 @@
+-- Migration: Add personal data consent
+-- Description: Consent state on users and the consent_log audit trail
+
+ALTER TABLE users ADD COLUMN consent_given BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN consent_given_at DATETIME;
+ALTER TABLE users ADD COLUMN consent_revoked BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN consent_revoked_at DATETIME;
+ALTER TABLE users ADD COLUMN consent_version TEXT NOT NULL DEFAULT '';
+
+-- Every given, revoked and erased consent (part of the audit export)
+CREATE TABLE IF NOT EXISTS consent_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    action TEXT NOT NULL,                     -- given, revoked, erased
+    policy_version TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_consent_log_user ON consent_log(user_id);
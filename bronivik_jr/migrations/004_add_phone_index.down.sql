@@ -0,0 +1,6 @@
+-- Rollback: Drop the phone blind index
+-- Search by phone stops working for encrypted phones
+
+DROP INDEX IF EXISTS idx_bookings_phone_index;
+
+ALTER TABLE bookings DROP COLUMN phone_index;
@@ -0,0 +1,7 @@
+-- Migration: Add phone blind index
+-- Description: Keyed hash of the normalized phone, so bookings stay searchable
+-- by phone when phones are encrypted at rest. Empty until encryption is enabled.
+
+ALTER TABLE bookings ADD COLUMN phone_index TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_bookings_phone_index ON bookings(phone_index);
@@ -0,0 +1,12 @@
+-- Migration: Create retention_log table (PostgreSQL)
+-- Description: What each retention policy run cleared, hashed or deleted (part of the audit export)
+
+CREATE TABLE IF NOT EXISTS retention_log (
+    id BIGSERIAL PRIMARY KEY,
+    run_at TIMESTAMPTZ NOT NULL,
+    table_name TEXT NOT NULL,
+    column_name TEXT NOT NULL DEFAULT '',    -- empty for rules that delete rows
+    action TEXT NOT NULL,                    -- clear, hash, delete
+    cutoff TIMESTAMPTZ NOT NULL,
+    rows_affected BIGINT NOT NULL DEFAULT 0
+);
@@ -0,0 +1,12 @@
+-- Migration: Create retention_log table
+-- Description: What each retention policy run cleared, hashed or deleted (part of the audit export)
+
+CREATE TABLE IF NOT EXISTS retention_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    run_at DATETIME NOT NULL,
+    table_name TEXT NOT NULL,
+    column_name TEXT NOT NULL DEFAULT '',    -- empty for rules that delete rows
+    action TEXT NOT NULL,                    -- clear, hash, delete
+    cutoff DATETIME NOT NULL,
+    rows_affected INTEGER NOT NULL DEFAULT 0
+);
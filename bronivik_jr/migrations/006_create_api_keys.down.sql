@@ -0,0 +1,5 @@
+-- Rollback: Drop api_keys table
+-- WARNING: Every key issued by managers stops working; static keys from
+-- api.auth.api_keys are not affected
+
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,21 @@
+-- Migration: Create api_keys table
+-- Description: API client keys issued by managers. Only the SHA-256 of a key is
+-- stored; the signing secret is encrypted together with personal data.
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    key_prefix TEXT NOT NULL,                 -- shown in lists to tell keys apart
+    key_hash TEXT NOT NULL UNIQUE,
+    signing_secret TEXT NOT NULL DEFAULT '',
+    permissions TEXT NOT NULL DEFAULT '',     -- comma-separated
+    expires_at DATETIME,
+    last_used_at DATETIME,
+    revoked_at DATETIME,
+    replaced_by INTEGER,                      -- key issued by rotation
+    rate_limit_rps REAL NOT NULL DEFAULT 0,   -- 0 means the api.rate_limit default
+    rate_limit_burst INTEGER NOT NULL DEFAULT 0,
+    daily_quota INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,4 @@
+-- Rollback: Drop client certificate binding from API keys
+-- Keys bound to a certificate are accepted by the key alone again
+
+ALTER TABLE api_keys DROP COLUMN client_cert_cn;
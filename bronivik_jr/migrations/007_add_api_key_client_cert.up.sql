@@ -0,0 +1,6 @@
+-- Migration: Add client certificate binding to API keys
+-- Description: CN of the mutual TLS client certificate bound to a key. A bound
+-- key is accepted only together with this certificate, and the certificate alone
+-- authenticates the client. Empty means the key is not bound.
+
+ALTER TABLE api_keys ADD COLUMN client_cert_cn TEXT NOT NULL DEFAULT '';
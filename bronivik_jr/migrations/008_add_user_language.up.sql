@@ -0,0 +1,5 @@
+-- Migration: Add user interface language
+-- Description: Language chosen with /language. Empty means the Telegram client
+-- language is used, falling back to the default locale.
+
+ALTER TABLE user_settings ADD COLUMN language TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,8 @@
+-- Rollback: Drop booking_series table
+-- WARNING: Bookings stay, but lose the link to their series
+
+DROP INDEX IF EXISTS idx_bookings_series_id;
+
+ALTER TABLE bookings DROP COLUMN series_id;
+
+DROP TABLE IF EXISTS booking_series;
@@ -0,0 +1,25 @@
+-- Migration: Create booking_series table (PostgreSQL)
+-- Description: Recurring bookings; every booking created for a series keeps its series_id
+
+CREATE TABLE IF NOT EXISTS booking_series (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    user_name TEXT NOT NULL,
+    user_nickname TEXT,
+    phone TEXT NOT NULL,
+    item_id BIGINT NOT NULL REFERENCES items(id),
+    item_name TEXT NOT NULL,
+    frequency TEXT NOT NULL,                  -- weekly, monthly
+    "interval" BIGINT NOT NULL DEFAULT 1,
+    start_date TIMESTAMPTZ NOT NULL,
+    until_date TIMESTAMPTZ,
+    occurrences BIGINT NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'active',    -- active, canceled
+    comment TEXT,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now()
+);
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS series_id BIGINT REFERENCES booking_series(id);
+
+CREATE INDEX IF NOT EXISTS idx_bookings_series_id ON bookings(series_id);
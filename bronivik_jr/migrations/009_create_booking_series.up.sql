@@ -0,0 +1,26 @@
+-- Migration: Create booking_series table
+-- Description: Recurring bookings; every booking created for a series keeps its series_id
+
+CREATE TABLE IF NOT EXISTS booking_series (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    user_name TEXT NOT NULL,
+    user_nickname TEXT,
+    phone TEXT NOT NULL,
+    item_id INTEGER NOT NULL,
+    item_name TEXT NOT NULL,
+    frequency TEXT NOT NULL,                  -- weekly, monthly
+    interval INTEGER NOT NULL DEFAULT 1,
+    start_date DATETIME NOT NULL,
+    until_date DATETIME,
+    occurrences INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'active',    -- active, canceled
+    comment TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(item_id) REFERENCES items(id)
+);
+
+ALTER TABLE bookings ADD COLUMN series_id INTEGER REFERENCES booking_series(id);
+
+CREATE INDEX IF NOT EXISTS idx_bookings_series_id ON bookings(series_id);
@@ -0,0 +1,5 @@
+-- Rollback: Drop booking_history table
+-- WARNING: This will delete the change history of all bookings
+
+DROP INDEX IF EXISTS idx_booking_history_booking;
+DROP TABLE IF EXISTS booking_history;
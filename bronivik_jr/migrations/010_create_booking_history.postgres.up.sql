@@ -0,0 +1,17 @@
+-- Migration: Create booking_history table (PostgreSQL)
+-- Description: Status, item and date changes of bookings with the actor and reason
+
+CREATE TABLE IF NOT EXISTS booking_history (
+    id BIGSERIAL PRIMARY KEY,
+    booking_id BIGINT NOT NULL REFERENCES bookings(id),
+    field TEXT NOT NULL,                      -- status, item, dates
+    old_value TEXT NOT NULL DEFAULT '',
+    new_value TEXT NOT NULL DEFAULT '',
+    actor_type TEXT NOT NULL,                 -- user, manager, api, system
+    actor_id BIGINT NOT NULL DEFAULT 0,
+    actor_name TEXT NOT NULL DEFAULT '',
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_history_booking ON booking_history(booking_id);
@@ -0,0 +1,6 @@
+-- Rollback: Drop events table
+-- WARNING: Undelivered events are lost; stop the bot and let the dispatcher
+-- drain the outbox first
+
+DROP INDEX IF EXISTS idx_events_pending;
+DROP TABLE IF EXISTS events;
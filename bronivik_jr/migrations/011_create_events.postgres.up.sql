@@ -0,0 +1,17 @@
+-- Migration: Create events table (PostgreSQL)
+-- Description: Transactional outbox of booking events, written in the same
+-- transaction as the booking change and delivered by the event dispatcher
+
+CREATE TABLE IF NOT EXISTS events (
+    id BIGSERIAL PRIMARY KEY,
+    type TEXT NOT NULL,
+    booking_id BIGINT,
+    payload TEXT NOT NULL,
+    attempts BIGINT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ,
+    processed_at TIMESTAMPTZ,                 -- NULL until every subscriber succeeded
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_events_pending ON events(processed_at, next_attempt_at);
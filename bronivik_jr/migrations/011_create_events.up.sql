@@ -0,0 +1,17 @@
+-- Migration: Create events table
+-- Description: Transactional outbox of booking events, written in the same
+-- transaction as the booking change and delivered by the event dispatcher
+
+CREATE TABLE IF NOT EXISTS events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    type TEXT NOT NULL,
+    booking_id INTEGER,
+    payload TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at DATETIME,
+    processed_at DATETIME,                    -- NULL until every subscriber succeeded
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_events_pending ON events(processed_at, next_attempt_at);
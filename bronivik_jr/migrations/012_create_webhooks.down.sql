@@ -0,0 +1,6 @@
+-- Rollback: Drop webhook tables
+-- WARNING: Subscriptions and the delivery log, including dead letters, are deleted
+
+DROP INDEX IF EXISTS idx_webhook_deliveries_status;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
@@ -0,0 +1,5 @@
+-- Rollback: Drop idempotency_keys table
+-- Retries of requests sent before the rollback are executed again
+
+DROP INDEX IF EXISTS idx_idempotency_keys_expires;
+DROP TABLE IF EXISTS idempotency_keys;
@@ -0,0 +1,17 @@
+-- Migration: Create idempotency_keys table (PostgreSQL)
+-- Description: Stored responses of API calls sent with an Idempotency-Key header
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id BIGSERIAL PRIMARY KEY,
+    scope TEXT NOT NULL,                      -- API client the key belongs to
+    key TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,                -- hash of the method, path and body
+    status_code BIGINT NOT NULL DEFAULT 0,    -- 0 while the first request is in progress
+    content_type TEXT NOT NULL DEFAULT '',
+    body BYTEA,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    UNIQUE(scope, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires ON idempotency_keys(expires_at);
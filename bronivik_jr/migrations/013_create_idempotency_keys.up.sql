@@ -0,0 +1,17 @@
+-- Migration: Create idempotency_keys table
+-- Description: Stored responses of API calls sent with an Idempotency-Key header
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    scope TEXT NOT NULL,                      -- API client the key belongs to
+    key TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,                -- hash of the method, path and body
+    status_code INTEGER NOT NULL DEFAULT 0,   -- 0 while the first request is in progress
+    content_type TEXT NOT NULL DEFAULT '',
+    body BLOB,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    expires_at DATETIME NOT NULL,
+    UNIQUE(scope, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires ON idempotency_keys(expires_at);
@@ -0,0 +1,7 @@
+-- Rollback: Drop waitlist table
+-- WARNING: Waiting users and open offers are deleted
+
+DROP INDEX IF EXISTS idx_waitlist_user_id;
+DROP INDEX IF EXISTS idx_waitlist_status_expires;
+DROP INDEX IF EXISTS idx_waitlist_item_date_status;
+DROP TABLE IF EXISTS waitlist;
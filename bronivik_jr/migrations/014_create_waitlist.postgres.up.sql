@@ -0,0 +1,24 @@
+-- Migration: Create waitlist table (PostgreSQL)
+-- Description: Users waiting for a fully booked item; a released unit is
+-- offered to the first entry in line
+
+CREATE TABLE IF NOT EXISTS waitlist (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    chat_id BIGINT NOT NULL,
+    user_name TEXT NOT NULL,
+    user_nickname TEXT,
+    phone TEXT NOT NULL,
+    item_id BIGINT NOT NULL REFERENCES items(id),
+    item_name TEXT NOT NULL,
+    date TIMESTAMPTZ NOT NULL,
+    status TEXT NOT NULL DEFAULT 'waiting',   -- waiting, offered, accepted, declined, expired
+    offer_expires_at TIMESTAMPTZ,
+    booking_id BIGINT,                        -- booking created from an accepted offer
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_waitlist_item_date_status ON waitlist(item_id, date, status);
+CREATE INDEX IF NOT EXISTS idx_waitlist_status_expires ON waitlist(status, offer_expires_at);
+CREATE INDEX IF NOT EXISTS idx_waitlist_user_id ON waitlist(user_id);
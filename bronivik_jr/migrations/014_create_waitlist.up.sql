@@ -0,0 +1,25 @@
+-- Migration: Create waitlist table
+-- Description: Users waiting for a fully booked item; a released unit is
+-- offered to the first entry in line
+
+CREATE TABLE IF NOT EXISTS waitlist (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    chat_id INTEGER NOT NULL,
+    user_name TEXT NOT NULL,
+    user_nickname TEXT,
+    phone TEXT NOT NULL,
+    item_id INTEGER NOT NULL,
+    item_name TEXT NOT NULL,
+    date DATETIME NOT NULL,
+    status TEXT NOT NULL DEFAULT 'waiting',   -- waiting, offered, accepted, declined, expired
+    offer_expires_at DATETIME,
+    booking_id INTEGER,                       -- booking created from an accepted offer
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(item_id) REFERENCES items(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_waitlist_item_date_status ON waitlist(item_id, date, status);
+CREATE INDEX IF NOT EXISTS idx_waitlist_status_expires ON waitlist(status, offer_expires_at);
+CREATE INDEX IF NOT EXISTS idx_waitlist_user_id ON waitlist(user_id);
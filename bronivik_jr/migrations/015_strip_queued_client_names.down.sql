@@ -0,0 +1,5 @@
+-- Rollback: Nothing to restore
+-- The stripped client data is not recoverable. Older code reads the booking
+-- from upsert payloads, so let the Sheets queue drain before rolling back
+
+SELECT 1;
@@ -0,0 +1,13 @@
+-- Migration: Strip client data from queued payloads
+-- Description: Sheets sync tasks used to carry the whole booking and booking
+-- events the client name, all in plaintext. Sync tasks now hold only the booking
+-- ID and events no client name; rewrite the rows written before that.
+
+UPDATE sync_queue SET payload = '{"booking_id":' || booking_id || '}'
+WHERE task_type = 'upsert';
+
+UPDATE events SET payload = (payload::jsonb - 'user_name')::text
+WHERE payload LIKE '%"user_name"%';
+
+UPDATE webhook_deliveries SET payload = (payload::jsonb - 'user_name')::text
+WHERE payload LIKE '%"user_name"%';
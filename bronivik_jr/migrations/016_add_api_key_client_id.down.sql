@@ -0,0 +1,4 @@
+-- Rollback: Drop client identity from API keys
+-- Keys issued by rotation lose the link to the first key of their chain
+
+ALTER TABLE api_keys DROP COLUMN client_id;
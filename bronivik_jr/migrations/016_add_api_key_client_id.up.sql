@@ -0,0 +1,18 @@
+-- Migration: Add client identity to API keys
+-- Description: Rotation issues a new key row, so the key ID changes while the
+-- client stays the same. client_id is the ID of the first key in the rotation
+-- chain and keeps per-client state, such as idempotency keys, across rotations.
+-- NULL means the key is the first in its chain and its own ID is the client ID.
+
+ALTER TABLE api_keys ADD COLUMN client_id BIGINT;
+
+-- Keys already issued by rotation inherit the first key of their chain
+WITH RECURSIVE chain(id, root) AS (
+    SELECT id, id FROM api_keys
+    WHERE id NOT IN (SELECT replaced_by FROM api_keys WHERE replaced_by IS NOT NULL)
+    UNION ALL
+    SELECT k.replaced_by, chain.root FROM chain JOIN api_keys k ON k.id = chain.id
+    WHERE k.replaced_by IS NOT NULL
+)
+UPDATE api_keys SET client_id = (SELECT root FROM chain WHERE chain.id = api_keys.id)
+WHERE id IN (SELECT replaced_by FROM api_keys WHERE replaced_by IS NOT NULL);
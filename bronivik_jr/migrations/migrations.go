@@ -0,0 +1,9 @@
+// Package migrations embeds the versioned SQL migrations of bronivik_jr.
+// Files are named NNN_name.up.sql / NNN_name.down.sql; a NNN_name.postgres.up.sql
+// variant replaces the generic script when the bot runs on PostgreSQL.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,70 @@
+#!/bin/bash
+# Check that the copies of shared packages in the bots match shared/
+# Usage: ./scripts/check-shared.sh [--sync]
+#
+# Each bot is a separate Go module built from its own directory (see the
+# Dockerfiles), so shared packages are copied into internal/<package>. Edit the
+# package in shared/ and run with --sync to update the copies.
+
+set -e
+
+ROOT="$(cd "$(dirname "$0")/.." && pwd)"
+
+# Packages copied into every bot as internal/<package>
+PACKAGES="fieldcrypt i18n migrate reqsign retention tgwebhook"
+BOTS="bronivik_jr bronivik_crm"
+
+# Colors for output
+RED='\033[0;31m'
+GREEN='\033[0;32m'
+NC='\033[0m' # No Color
+
+log_info() {
+    echo -e "${GREEN}[INFO]${NC} $1"
+}
+
+log_error() {
+    echo -e "${RED}[ERROR]${NC} $1"
+}
+
+# Replace the copies with the packages from shared/
+sync_copies() {
+    for pkg in $PACKAGES; do
+        for bot in $BOTS; do
+            rm -rf "$ROOT/$bot/internal/$pkg"
+            cp -R "$ROOT/shared/$pkg" "$ROOT/$bot/internal/$pkg"
+        done
+    done
+    log_info "Shared packages copied into $BOTS"
+}
+
+# Fail when any copy differs from shared/
+check_copies() {
+    local failed=0
+    for pkg in $PACKAGES; do
+        for bot in $BOTS; do
+            if ! diff -r "$ROOT/shared/$pkg" "$ROOT/$bot/internal/$pkg"; then
+                log_error "$bot/internal/$pkg differs from shared/$pkg"
+                failed=1
+            fi
+        done
+    done
+    if [ "$failed" -ne 0 ]; then
+        log_error "Edit shared/ and run $0 --sync"
+        exit 1
+    fi
+    log_info "Shared package copies are up to date"
+}
+
+case "${1:-}" in
+    --sync)
+        sync_copies
+        ;;
+    "")
+        check_copies
+        ;;
+    *)
+        echo "Usage: $0 [--sync]"
+        exit 1
+        ;;
+esac
@@ -27,39 +27,36 @@ log_error() {
     echo -e "${RED}[ERROR]${NC} $1"
 }
 
-# Check if migrate tool is installed
-check_migrate() {
-    if ! command -v migrate &> /dev/null; then
-        log_error "migrate tool is not installed"
-        log_info "Install with: go install -tags 'sqlite3' github.com/golang-migrate/migrate/v4/cmd/migrate@latest"
-        exit 1
-    fi
-}
-
-# Get database path for service
-get_db_path() {
-    case "$1" in
+# Run the migrate command of a service (bronivik_jr/cmd/migrate, bronivik_crm/cmd/migrate)
+run_migrate() {
+    local service="$1"
+    shift
+    local dir config
+    case "$service" in
         jr|bronivik-jr)
-            echo "$BRONIVIK_JR_DB"
+            dir="./bronivik_jr"
+            config="${BRONIVIK_JR_CONFIG:-configs/config.yaml}"
             ;;
         crm|bronivik-crm)
-            echo "$BRONIVIK_CRM_DB"
+            dir="./bronivik_crm"
+            config="${BRONIVIK_CRM_CONFIG:-configs/config.yaml}"
             ;;
         *)
-            log_error "Unknown service: $1. Use 'jr' or 'crm'"
+            log_error "Unknown service: $service. Use 'jr' or 'crm'"
             exit 1
             ;;
     esac
+    (cd "$dir" && go run ./cmd/migrate -config "$config" $DRY_RUN "$@")
 }
 
-# Get migrations path for service
-get_migrations_path() {
+# Get database path for service
+get_db_path() {
     case "$1" in
         jr|bronivik-jr)
-            echo "./bronivik_jr/migrations"
+            echo "$BRONIVIK_JR_DB"
             ;;
         crm|bronivik-crm)
-            echo "./bronivik_crm/migrations"
+            echo "$BRONIVIK_CRM_DB"
             ;;
         *)
             log_error "Unknown service: $1. Use 'jr' or 'crm'"
@@ -89,69 +86,35 @@ backup() {
 # Apply migrations
 migrate_up() {
     local service="$1"
-    local steps="${2:-}"
-    local db_path=$(get_db_path "$service")
-    local migrations_path=$(get_migrations_path "$service")
-    
     log_info "Applying migrations for $service"
-    
-    if [ -n "$steps" ]; then
-        migrate -path "$migrations_path" -database "sqlite3://$db_path" up "$steps"
-    else
-        migrate -path "$migrations_path" -database "sqlite3://$db_path" up
-    fi
-    
-    log_info "Migrations applied successfully"
+    run_migrate "$service" up
 }
 
 # Rollback migrations
 migrate_down() {
     local service="$1"
     local steps="${2:-1}"
-    local db_path=$(get_db_path "$service")
-    local migrations_path=$(get_migrations_path "$service")
-    
     log_warn "Rolling back $steps migration(s) for $service"
-    
-    migrate -path "$migrations_path" -database "sqlite3://$db_path" down "$steps"
-    
-    log_info "Rollback completed"
+    run_migrate "$service" down "$steps"
 }
 
-# Show current version
-migrate_version() {
-    local service="$1"
-    local db_path=$(get_db_path "$service")
-    local migrations_path=$(get_migrations_path "$service")
-    
-    log_info "Current migration version for $service:"
-    migrate -path "$migrations_path" -database "sqlite3://$db_path" version
-}
-
-# Force set version
-migrate_force() {
+# Migrate to an exact version (0 rolls back everything)
+migrate_goto() {
     local service="$1"
     local version="$2"
-    local db_path=$(get_db_path "$service")
-    local migrations_path=$(get_migrations_path "$service")
-    
     if [ -z "$version" ]; then
-        log_error "Version number required for force command"
+        log_error "Version number required for goto command"
         exit 1
     fi
-    
-    log_warn "Forcing version to $version for $service"
-    migrate -path "$migrations_path" -database "sqlite3://$db_path" force "$version"
-    log_info "Version forced successfully"
+    log_warn "Migrating $service to version $version"
+    run_migrate "$service" goto "$version"
 }
 
 # Show migration status
 migrate_status() {
     local service="$1"
-    local migrations_path=$(get_migrations_path "$service")
-    
-    log_info "Available migrations for $service:"
-    ls -la "$migrations_path"/*.sql 2>/dev/null || log_warn "No migrations found"
+    log_info "Migration status for $service:"
+    run_migrate "$service" status
 }
 
 # Apply migrations to all services
@@ -167,9 +130,6 @@ migrate_all() {
                 backup "$service"
                 migrate_up "$service"
                 ;;
-            version)
-                migrate_version "$service"
-                ;;
             status)
                 migrate_status "$service"
                 ;;
@@ -187,13 +147,12 @@ usage() {
 Usage: $0 <command> [service] [args]
 
 Commands:
-    up [service] [steps]     Apply migrations (all if no steps specified)
+    up [service]             Apply pending migrations
     down [service] [steps]   Rollback migrations (1 by default)
-    version [service]        Show current migration version
-    force [service] [ver]    Force set migration version
-    status [service]         List available migrations
+    goto [service] [ver]     Apply or roll back migrations to the version
+    status [service]         Show applied and pending migrations
     backup [service]         Create database backup
-    all [command]            Run command on all services (up, version, status)
+    all [command]            Run command on all services (up, status)
 
 Services:
     jr, bronivik-jr          Bronivik Jr (device booking)
@@ -202,13 +161,17 @@ Services:
 Examples:
     $0 up jr                 Apply all migrations for Bronivik Jr
     $0 down jr 1             Rollback 1 migration for Bronivik Jr
-    $0 version crm           Show migration version for Bronivik CRM
+    $0 status crm            Show migration status for Bronivik CRM
+    DRY_RUN=-dry-run $0 up jr   Print SQL without executing it
     $0 backup jr             Create backup of Bronivik Jr database
     $0 all up                Apply migrations for all services
 
 Environment Variables:
     BRONIVIK_JR_DB           Path to Bronivik Jr database (default: /app/data/bronivik_jr.db)
     BRONIVIK_CRM_DB          Path to Bronivik CRM database (default: /app/data/bronivik_crm.db)
+    BRONIVIK_JR_CONFIG       Bronivik Jr config, relative to bronivik_jr (default: configs/config.yaml)
+    BRONIVIK_CRM_CONFIG      Bronivik CRM config, relative to bronivik_crm (default: configs/config.yaml)
+    DRY_RUN                  Set to -dry-run to print SQL instead of executing it
     BACKUP_DIR               Backup directory (default: /app/backups)
 EOF
     exit 1
@@ -216,8 +179,6 @@ EOF
 
 # Main
 main() {
-    check_migrate
-    
     local command="${1:-}"
     local service="${2:-}"
     local args="${3:-}"
@@ -228,7 +189,7 @@ main() {
                 migrate_all up
             else
                 backup "$service"
-                migrate_up "$service" "$args"
+                migrate_up "$service"
             fi
             ;;
         down)
@@ -239,19 +200,13 @@ main() {
             backup "$service"
             migrate_down "$service" "$args"
             ;;
-        version)
-            if [ "$service" = "all" ] || [ -z "$service" ]; then
-                migrate_all version
-            else
-                migrate_version "$service"
-            fi
-            ;;
-        force)
+        goto)
             if [ -z "$service" ]; then
-                log_error "Service required for force command"
+                log_error "Service required for goto command"
                 exit 1
             fi
-            migrate_force "$service" "$args"
+            backup "$service"
+            migrate_goto "$service" "$args"
             ;;
         status)
             if [ "$service" = "all" ] || [ -z "$service" ]; then
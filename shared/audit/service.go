@@ -31,6 +31,8 @@ func DefaultConfig() *Config {
 }
 
 // DataCleaner interface for cleaning old data.
+// Pass a nil cleaner to keep history and let the retention package
+// anonymize old rows instead of deleting them.
 type DataCleaner interface {
 	// DeleteOldBookings deletes bookings older than duration.
 	DeleteOldBookings(ctx context.Context, olderThan time.Duration) (int64, error)
@@ -0,0 +1,321 @@
+// Package i18n provides message catalogs with plural rules and localized date
+// formatting for the bots.
+//
+// The canonical source is shared/i18n; bronivik_jr and bronivik_crm keep
+// identical copies in internal/i18n because they are built as separate modules.
+//
+// A catalog is a YAML file named after its language (ru.yaml, en.yaml):
+//
+//	dates:
+//	  date: "02.01.2006"          # Go time layouts
+//	  date_time: "02.01.2006 15:04"
+//	  day_month: "02.01"
+//	  months: [Январь, Февраль, ...]
+//	  weekdays: [Пн, Вт, ...]      # Monday first
+//	messages:
+//	  greeting: "Здравствуйте, %s!"
+//	  bookings_count:
+//	    one: "%d заявка"
+//	    few: "%d заявки"
+//	    many: "%d заявок"
+//
+// Messages are fmt templates. Keys missing in a language fall back to the
+// fallback language and then to the key itself.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Plural categories used in catalogs (CLDR names).
+const (
+	PluralOne   = "one"
+	PluralFew   = "few"
+	PluralMany  = "many"
+	PluralOther = "other"
+)
+
+// Bundle holds the catalogs of all supported languages.
+type Bundle struct {
+	fallback  string
+	catalogs  map[string]*catalog
+	languages []string
+}
+
+type catalog struct {
+	Dates    dateFormats        `yaml:"dates"`
+	Messages map[string]message `yaml:"messages"`
+}
+
+type dateFormats struct {
+	Date     string   `yaml:"date"`
+	DateTime string   `yaml:"date_time"`
+	DayMonth string   `yaml:"day_month"`
+	Months   []string `yaml:"months"`
+	Weekdays []string `yaml:"weekdays"`
+}
+
+// message is either a plain template or a set of plural forms.
+type message struct {
+	text  string
+	forms map[string]string
+}
+
+func (m *message) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Decode(&m.text)
+	case yaml.MappingNode:
+		return node.Decode(&m.forms)
+	default:
+		return fmt.Errorf("line %d: message must be a string or a map of plural forms", node.Line)
+	}
+}
+
+// Load reads every <lang>.yaml file in the root of fsys. The fallback language
+// must be present; it is used for unknown languages and missing keys.
+func Load(fsys fs.FS, fallback string) (*Bundle, error) {
+	files, err := fs.Glob(fsys, "*.yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Bundle{fallback: fallback, catalogs: make(map[string]*catalog)}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		var c catalog
+		if err := yaml.Unmarshal(data, &c); err != nil {
+			return nil, fmt.Errorf("i18n: %s: %w", file, err)
+		}
+		if n := len(c.Dates.Months); n != 0 && n != 12 {
+			return nil, fmt.Errorf("i18n: %s: expected 12 months, got %d", file, n)
+		}
+		if n := len(c.Dates.Weekdays); n != 0 && n != 7 {
+			return nil, fmt.Errorf("i18n: %s: expected 7 weekdays, got %d", file, n)
+		}
+		lang := strings.TrimSuffix(path.Base(file), ".yaml")
+		b.catalogs[lang] = &c
+		b.languages = append(b.languages, lang)
+	}
+	if b.catalogs[fallback] == nil {
+		return nil, fmt.Errorf("i18n: fallback language %q has no catalog", fallback)
+	}
+	sort.Strings(b.languages)
+	return b, nil
+}
+
+// Languages returns the supported language codes in alphabetical order.
+func (b *Bundle) Languages() []string {
+	return append([]string(nil), b.languages...)
+}
+
+// Match maps a Telegram language_code such as "en-US" to a supported language,
+// or returns the fallback language.
+func (b *Bundle) Match(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+	if _, ok := b.catalogs[code]; ok {
+		return code
+	}
+	return b.fallback
+}
+
+// Supported reports whether lang has its own catalog.
+func (b *Bundle) Supported(lang string) bool {
+	_, ok := b.catalogs[lang]
+	return ok
+}
+
+// Localizer returns a localizer for the closest supported language.
+func (b *Bundle) Localizer(lang string) *Localizer {
+	lang = b.Match(lang)
+	return &Localizer{bundle: b, lang: lang, catalog: b.catalogs[lang]}
+}
+
+// Matches reports whether text is the translation of key in any language. It
+// is used to recognize reply keyboard buttons whatever language they were shown in.
+func (b *Bundle) Matches(key, text string) bool {
+	for _, c := range b.catalogs {
+		if m, ok := c.Messages[key]; ok && m.forms == nil && m.text == text {
+			return true
+		}
+	}
+	return false
+}
+
+// Missing returns keys of the fallback catalog that lang does not translate.
+func (b *Bundle) Missing(lang string) []string {
+	c := b.catalogs[lang]
+	var missing []string
+	for key := range b.catalogs[b.fallback].Messages {
+		if c == nil {
+			missing = append(missing, key)
+			continue
+		}
+		if _, ok := c.Messages[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// Localizer formats messages and dates in one language.
+type Localizer struct {
+	bundle  *Bundle
+	lang    string
+	catalog *catalog
+}
+
+// Lang returns the language code of the localizer.
+func (l *Localizer) Lang() string {
+	return l.lang
+}
+
+// T returns the message for key formatted with args.
+func (l *Localizer) T(key string, args ...interface{}) string {
+	m, ok := l.lookup(key)
+	if !ok {
+		return key
+	}
+	text := m.text
+	if m.forms != nil {
+		text = m.form(PluralOther)
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// N returns the plural form of key for n. Without args the template is
+// formatted with n itself.
+func (l *Localizer) N(key string, n int, args ...interface{}) string {
+	m, ok := l.lookup(key)
+	if !ok {
+		return key
+	}
+	text := m.text
+	if m.forms != nil {
+		text = m.form(PluralCategory(l.lang, n))
+	}
+	if len(args) == 0 {
+		args = []interface{}{n}
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+func (l *Localizer) lookup(key string) (message, bool) {
+	if m, ok := l.catalog.Messages[key]; ok {
+		return m, true
+	}
+	m, ok := l.bundle.catalogs[l.bundle.fallback].Messages[key]
+	return m, ok
+}
+
+func (m message) form(category string) string {
+	for _, c := range []string{category, PluralOther, PluralMany} {
+		if text, ok := m.forms[c]; ok {
+			return text
+		}
+	}
+	for _, text := range m.forms {
+		return text
+	}
+	return ""
+}
+
+// PluralCategory returns the CLDR plural category of an integer n in lang.
+func PluralCategory(lang string, n int) string {
+	if n < 0 {
+		n = -n
+	}
+	switch lang {
+	case "ru", "uk", "be":
+		mod10, mod100 := n%10, n%100
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return PluralOne
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return PluralFew
+		default:
+			return PluralMany
+		}
+	default:
+		if n == 1 {
+			return PluralOne
+		}
+		return PluralOther
+	}
+}
+
+// Date formats a calendar date, e.g. 05.01.2026 or Jan 5, 2026.
+func (l *Localizer) Date(t time.Time) string {
+	return t.Format(orDefault(l.catalog.Dates.Date, "2006-01-02"))
+}
+
+// DateTime formats a date with time of day.
+func (l *Localizer) DateTime(t time.Time) string {
+	return t.Format(orDefault(l.catalog.Dates.DateTime, "2006-01-02 15:04"))
+}
+
+// DayMonth formats a date without the year.
+func (l *Localizer) DayMonth(t time.Time) string {
+	return t.Format(orDefault(l.catalog.Dates.DayMonth, "01-02"))
+}
+
+// Month returns the standalone month name.
+func (l *Localizer) Month(m time.Month) string {
+	if len(l.catalog.Dates.Months) == 12 && m >= time.January && m <= time.December {
+		return l.catalog.Dates.Months[m-1]
+	}
+	return m.String()
+}
+
+// MonthYear formats a month heading such as "Январь 2026".
+func (l *Localizer) MonthYear(t time.Time) string {
+	return l.Month(t.Month()) + " " + strconv.Itoa(t.Year())
+}
+
+// Weekday returns the short weekday name.
+func (l *Localizer) Weekday(d time.Weekday) string {
+	if len(l.catalog.Dates.Weekdays) == 7 {
+		// Catalog weeks start on Monday.
+		return l.catalog.Dates.Weekdays[(int(d)+6)%7]
+	}
+	return d.String()[:3]
+}
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+type ctxKey struct{}
+
+// WithLocalizer stores the localizer of the current user in ctx.
+func WithLocalizer(ctx context.Context, l *Localizer) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns the localizer stored in ctx or nil.
+func FromContext(ctx context.Context) *Localizer {
+	l, _ := ctx.Value(ctxKey{}).(*Localizer)
+	return l
+}
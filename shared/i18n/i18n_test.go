@@ -0,0 +1,115 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBundle(t *testing.T) *Bundle {
+	t.Helper()
+	b, err := Load(fstest.MapFS{
+		"ru.yaml": {Data: []byte(`
+dates:
+  date: "02.01.2006"
+  day_month: "02.01"
+  months: [Январь, Февраль, Март, Апрель, Май, Июнь, Июль, Август, Сентябрь, Октябрь, Ноябрь, Декабрь]
+  weekdays: [Пн, Вт, Ср, Чт, Пт, Сб, Вс]
+messages:
+  hello: "Здравствуйте, %s!"
+  btn_cancel: "❌ Отмена"
+  only_ru: "только по-русски"
+  bookings:
+    one: "%d заявка"
+    few: "%d заявки"
+    many: "%d заявок"
+`)},
+		"en.yaml": {Data: []byte(`
+dates:
+  date: "Jan 2, 2006"
+messages:
+  hello: "Hello, %s!"
+  btn_cancel: "❌ Cancel"
+  bookings:
+    one: "%d booking"
+    other: "%d bookings"
+`)},
+		"README.md": {Data: []byte(`not a catalog`)},
+	}, "ru")
+	require.NoError(t, err)
+	return b
+}
+
+func TestLocalizer(t *testing.T) {
+	b := testBundle(t)
+	assert.Equal(t, []string{"en", "ru"}, b.Languages())
+
+	ru, en := b.Localizer("ru"), b.Localizer("en-US")
+	assert.Equal(t, "en", en.Lang())
+	assert.Equal(t, "ru", b.Localizer("de").Lang())
+	assert.Equal(t, "ru", b.Localizer("").Lang())
+
+	assert.Equal(t, "Здравствуйте, Анна!", ru.T("hello", "Анна"))
+	assert.Equal(t, "Hello, Ann!", en.T("hello", "Ann"))
+	assert.Equal(t, "только по-русски", en.T("only_ru"), "missing keys fall back to the default language")
+	assert.Equal(t, "unknown_key", en.T("unknown_key"))
+
+	assert.True(t, b.Matches("btn_cancel", "❌ Cancel"))
+	assert.True(t, b.Matches("btn_cancel", "❌ Отмена"))
+	assert.False(t, b.Matches("btn_cancel", "Cancel"))
+
+	assert.Equal(t, []string{"only_ru"}, b.Missing("en"))
+}
+
+func TestPlural(t *testing.T) {
+	b := testBundle(t)
+	ru, en := b.Localizer("ru"), b.Localizer("en")
+
+	for n, want := range map[int]string{
+		1: "1 заявка", 2: "2 заявки", 5: "5 заявок", 11: "11 заявок",
+		12: "12 заявок", 21: "21 заявка", 22: "22 заявки", 111: "111 заявок",
+	} {
+		assert.Equal(t, want, ru.N("bookings", n), n)
+	}
+	assert.Equal(t, "1 booking", en.N("bookings", 1))
+	assert.Equal(t, "0 bookings", en.N("bookings", 0))
+	assert.Equal(t, PluralFew, PluralCategory("ru", 3))
+	assert.Equal(t, PluralOther, PluralCategory("en", 3))
+}
+
+func TestDates(t *testing.T) {
+	b := testBundle(t)
+	ru, en := b.Localizer("ru"), b.Localizer("en")
+	day := time.Date(2026, time.January, 5, 9, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, "05.01.2026", ru.Date(day))
+	assert.Equal(t, "Jan 5, 2026", en.Date(day))
+	assert.Equal(t, "05.01", ru.DayMonth(day))
+	assert.Equal(t, "Январь 2026", ru.MonthYear(day))
+	assert.Equal(t, "January 2026", en.MonthYear(day))
+	assert.Equal(t, "Пн", ru.Weekday(time.Monday))
+	assert.Equal(t, "Вс", ru.Weekday(time.Sunday))
+	assert.Equal(t, "Sun", en.Weekday(time.Sunday))
+}
+
+func TestLoadErrors(t *testing.T) {
+	_, err := Load(fstest.MapFS{"en.yaml": {Data: []byte(`messages: {}`)}}, "ru")
+	assert.Error(t, err, "fallback catalog is required")
+
+	_, err = Load(fstest.MapFS{"ru.yaml": {Data: []byte("dates:\n  months: [Январь]\n")}}, "ru")
+	assert.Error(t, err)
+
+	_, err = Load(fstest.MapFS{"ru.yaml": {Data: []byte("messages:\n  key: [a, b]\n")}}, "ru")
+	assert.Error(t, err)
+}
+
+func TestContext(t *testing.T) {
+	b := testBundle(t)
+	assert.Nil(t, FromContext(context.Background()))
+	ctx := WithLocalizer(context.Background(), b.Localizer("en"))
+	assert.Equal(t, "en", FromContext(ctx).Lang())
+}
@@ -0,0 +1,161 @@
+package retention
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const day = 24 * time.Hour
+
+var testNow = time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+var testCatalog = []Table{
+	{
+		Name: "bookings",
+		Age:  "date",
+		Columns: map[string]Column{
+			"name":  {},
+			"phone": {Derived: []string{"phone_index"}},
+		},
+		Deletable: true,
+	},
+	{Name: "users", Age: "last_seen", Columns: map[string]Column{"phone": {}}},
+}
+
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(`
+		CREATE TABLE bookings (id INTEGER PRIMARY KEY, name TEXT NOT NULL, phone TEXT,
+			phone_index TEXT NOT NULL DEFAULT '', date DATETIME NOT NULL);
+		CREATE TABLE users (id INTEGER PRIMARY KEY, phone TEXT, last_seen DATETIME NOT NULL);
+		CREATE TABLE retention_log (id INTEGER PRIMARY KEY, run_at DATETIME, table_name TEXT,
+			column_name TEXT, action TEXT, cutoff DATETIME, rows_affected INTEGER);`)
+	require.NoError(t, err)
+
+	for i, age := range []int{400, 200, 10} {
+		_, err = db.Exec(`INSERT INTO bookings (id, name, phone, phone_index, date) VALUES (?, ?, ?, 'idx', ?)`,
+			i+1, "Client", fmt.Sprintf("799900000%02d", i), testNow.Add(-time.Duration(age)*day))
+		require.NoError(t, err)
+	}
+	return db
+}
+
+func newTestEngine(t *testing.T, db *sql.DB, rules []Rule, opts Options) *Engine {
+	t.Helper()
+	opts.Now = func() time.Time { return testNow }
+	e, err := New(db, testCatalog, rules, opts)
+	require.NoError(t, err)
+	return e
+}
+
+func TestNewValidatesRules(t *testing.T) {
+	db := newTestDB(t)
+	cases := map[string]Rule{
+		"unknown table":     {Table: "payments", Column: "phone", Action: ActionClear, After: day},
+		"unknown column":    {Table: "bookings", Column: "status", Action: ActionClear, After: day},
+		"unknown action":    {Table: "bookings", Column: "phone", Action: "encrypt", After: day},
+		"no age":            {Table: "bookings", Column: "phone", Action: ActionClear},
+		"delete column":     {Table: "bookings", Column: "phone", Action: ActionDelete, After: day},
+		"delete not listed": {Table: "users", Action: ActionDelete, After: day},
+	}
+	for name, rule := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := New(db, testCatalog, []Rule{rule}, Options{})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRunDryRunChangesNothing(t *testing.T) {
+	db := newTestDB(t)
+	e := newTestEngine(t, db, []Rule{
+		{Table: "bookings", Column: "phone", Action: ActionClear, After: 180 * day},
+		{Table: "bookings", Action: ActionDelete, After: 365 * day},
+	}, Options{})
+
+	report, err := e.Run(context.Background(), true)
+	require.NoError(t, err)
+	require.Len(t, report.Results, 2)
+	assert.Equal(t, int64(2), report.Results[0].Rows)
+	assert.Equal(t, int64(1), report.Results[1].Rows)
+	assert.Equal(t, testNow.Add(-180*day), report.Results[0].Cutoff)
+
+	var phones, logged int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM bookings WHERE phone <> ''`).Scan(&phones))
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM retention_log`).Scan(&logged))
+	assert.Equal(t, 3, phones)
+	assert.Zero(t, logged)
+
+	var out bytes.Buffer
+	require.NoError(t, WriteReport(&out, report))
+	assert.Contains(t, out.String(), "bookings  phone")
+	assert.Contains(t, out.String(), "3 row(s) would be touched (dry run)")
+}
+
+func TestHashRequiresKey(t *testing.T) {
+	_, err := New(newTestDB(t), testCatalog, []Rule{
+		{Table: "bookings", Column: "name", Action: ActionHash, After: 180 * day},
+	}, Options{})
+	assert.ErrorIs(t, err, ErrNoHashKey)
+}
+
+func TestRunAppliesRules(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	e := newTestEngine(t, db, []Rule{
+		{Table: "bookings", Column: "phone", Action: ActionClear, After: 180 * day},
+		{Table: "bookings", Column: "name", Action: ActionHash, After: 180 * day},
+		{Table: "bookings", Action: ActionDelete, After: 365 * day},
+	}, Options{HashKey: []byte("secret"), BatchSize: 1, Decrypt: func(v string) (string, error) {
+		return strings.ToUpper(v), nil
+	}})
+
+	report, err := e.Run(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), report.Total())
+
+	var ids []int64
+	rows, err := db.Query(`SELECT id FROM bookings ORDER BY id`)
+	require.NoError(t, err)
+	for rows.Next() {
+		var id int64
+		require.NoError(t, rows.Scan(&id))
+		ids = append(ids, id)
+	}
+	require.NoError(t, rows.Close())
+	assert.Equal(t, []int64{2, 3}, ids, "the booking older than a year is deleted")
+
+	var name, phone, index string
+	require.NoError(t, db.QueryRow(`SELECT name, phone, phone_index FROM bookings WHERE id = 2`).Scan(&name, &phone, &index))
+	assert.Empty(t, phone)
+	assert.Empty(t, index, "derived columns are cleared with the phone")
+	assert.Equal(t, e.hashValue("CLIENT"), name, "the hash is computed from the decrypted value")
+	assert.True(t, strings.HasPrefix(name, HashPrefix))
+
+	require.NoError(t, db.QueryRow(`SELECT name, phone FROM bookings WHERE id = 3`).Scan(&name, &phone))
+	assert.Equal(t, "Client", name)
+	assert.NotEmpty(t, phone)
+
+	var logged int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM retention_log`).Scan(&logged))
+	assert.Equal(t, 3, logged)
+
+	// The second run finds nothing left to do
+	report, err = e.Run(ctx, false)
+	require.NoError(t, err)
+	assert.Zero(t, report.Total())
+}
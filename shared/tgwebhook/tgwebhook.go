@@ -0,0 +1,167 @@
+// Package tgwebhook receives Telegram updates pushed to a webhook instead of
+// long polling.
+//
+// The canonical source is shared/tgwebhook; bronivik_jr and bronivik_crm keep
+// identical copies in internal/tgwebhook because they are built as separate modules.
+package tgwebhook
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// SecretHeader carries the secret_token passed to setWebhook.
+	SecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+	// maxBodyBytes limits a single update; Telegram updates are far smaller.
+	maxBodyBytes = 1 << 20
+	bufferSize   = 100
+)
+
+// Config describes where Telegram pushes updates. When CertFile and KeyFile
+// are empty the listener serves plain HTTP behind a TLS-terminating proxy.
+type Config struct {
+	URL      string
+	Secret   string
+	Listen   string
+	CertFile string
+	KeyFile  string
+}
+
+// API is the part of tgbotapi.BotAPI needed to register the webhook.
+// tgbotapi.WebhookConfig has no secret_token field, so setWebhook is called directly.
+type API interface {
+	MakeRequest(endpoint string, params tgbotapi.Params) (*tgbotapi.APIResponse, error)
+}
+
+// Receiver accepts updates on the path of the webhook URL and queues them for the bot.
+type Receiver struct {
+	cfg     Config
+	api     API
+	path    string
+	updates chan tgbotapi.Update
+	logger  *zerolog.Logger
+}
+
+// New creates a receiver for cfg.URL. Updates are served on the path of that
+// URL at cfg.Listen.
+func New(cfg Config, api API, logger *zerolog.Logger) (*Receiver, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid telegram webhook url %q", cfg.URL)
+	}
+	if cfg.Secret == "" {
+		return nil, errors.New("telegram webhook secret is required")
+	}
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+	return &Receiver{
+		cfg:     cfg,
+		api:     api,
+		path:    path,
+		updates: make(chan tgbotapi.Update, bufferSize),
+		logger:  logger,
+	}, nil
+}
+
+// Start listens for updates, registers the webhook with Telegram and returns
+// the channel updates are delivered to. The server stops when ctx is canceled.
+//
+// The listener is bound before setWebhook so no update is pushed to a closed
+// port. The webhook is not deleted on shutdown: during a rolling deploy the
+// next instance may already have registered it.
+func (w *Receiver) Start(ctx context.Context) (tgbotapi.UpdatesChannel, error) {
+	ln, err := net.Listen("tcp", w.cfg.Listen)
+	if err != nil {
+		return nil, fmt.Errorf("listen telegram webhook: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(w.path, w)
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		var serveErr error
+		if w.cfg.CertFile != "" {
+			serveErr = srv.ServeTLS(ln, w.cfg.CertFile, w.cfg.KeyFile)
+		} else {
+			serveErr = srv.Serve(ln)
+		}
+		if serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
+			w.logger.Error().Err(serveErr).Msg("Telegram webhook server stopped")
+		}
+	}()
+
+	params := tgbotapi.Params{
+		"url":          w.cfg.URL,
+		"secret_token": w.cfg.Secret,
+	}
+	if _, err := w.api.MakeRequest("setWebhook", params); err != nil {
+		_ = srv.Close()
+		return nil, fmt.Errorf("set telegram webhook: %w", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			_ = srv.Close()
+		}
+	}()
+
+	w.logger.Info().
+		Str("addr", ln.Addr().String()).
+		Str("path", w.path).
+		Bool("tls", w.cfg.CertFile != "").
+		Msg("Telegram webhook receiver started")
+	return w.updates, nil
+}
+
+// ServeHTTP validates the secret token and queues the update for processing.
+func (w *Receiver) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		rw.Header().Set("Allow", http.MethodPost)
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.URL.Path != w.path {
+		http.NotFound(rw, r)
+		return
+	}
+	token := r.Header.Get(SecretHeader)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(w.cfg.Secret)) != 1 {
+		w.logger.Warn().Str("remote_addr", r.RemoteAddr).Msg("Telegram webhook request with invalid secret token")
+		http.Error(rw, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var update tgbotapi.Update
+	if err := json.NewDecoder(http.MaxBytesReader(rw, r.Body, maxBodyBytes)).Decode(&update); err != nil {
+		http.Error(rw, "invalid update", http.StatusBadRequest)
+		return
+	}
+
+	// Block while the queue is full; Telegram retries if the request fails.
+	select {
+	case w.updates <- update:
+		rw.WriteHeader(http.StatusOK)
+	case <-r.Context().Done():
+		http.Error(rw, "busy", http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,115 @@
+package tgwebhook
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAPI struct {
+	endpoint string
+	params   tgbotapi.Params
+}
+
+func (f *fakeAPI) MakeRequest(endpoint string, params tgbotapi.Params) (*tgbotapi.APIResponse, error) {
+	f.endpoint, f.params = endpoint, params
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+func newTestReceiver(t *testing.T, api API) *Receiver {
+	t.Helper()
+	logger := zerolog.New(io.Discard)
+	receiver, err := New(Config{
+		URL:    "https://bot.example.com/telegram/hook",
+		Secret: "secret-token",
+		Listen: "127.0.0.1:0",
+	}, api, &logger)
+	require.NoError(t, err)
+	return receiver
+}
+
+func TestReceiverServeHTTP(t *testing.T) {
+	receiver := newTestReceiver(t, &fakeAPI{})
+	body := `{"update_id": 7, "message": {"message_id": 1, "text": "/start", "chat": {"id": 42}}}`
+
+	post := func(path, secret, payload string) int {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(payload))
+		if secret != "" {
+			req.Header.Set(SecretHeader, secret)
+		}
+		rec := httptest.NewRecorder()
+		receiver.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, post("/telegram/hook", "", body))
+	assert.Equal(t, http.StatusUnauthorized, post("/telegram/hook", "wrong", body))
+	assert.Equal(t, http.StatusNotFound, post("/other", "secret-token", body))
+	assert.Equal(t, http.StatusBadRequest, post("/telegram/hook", "secret-token", "{"))
+
+	rec := httptest.NewRecorder()
+	receiver.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/telegram/hook", http.NoBody))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	assert.Equal(t, http.StatusOK, post("/telegram/hook", "secret-token", body))
+	select {
+	case update := <-receiver.updates:
+		assert.Equal(t, 7, update.UpdateID)
+		assert.Equal(t, "/start", update.Message.Text)
+	default:
+		t.Fatal("update was not queued")
+	}
+}
+
+func TestReceiverStart(t *testing.T) {
+	api := &fakeAPI{}
+	receiver := newTestReceiver(t, api)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Reserve a free port first to know the server address.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	receiver.cfg.Listen = ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	updates, err := receiver.Start(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "setWebhook", api.endpoint)
+	assert.Equal(t, "https://bot.example.com/telegram/hook", api.params["url"])
+	assert.Equal(t, "secret-token", api.params["secret_token"])
+
+	req, err := http.NewRequest(http.MethodPost, "http://"+receiver.cfg.Listen+"/telegram/hook",
+		strings.NewReader(`{"update_id": 9}`))
+	require.NoError(t, err)
+	req.Header.Set(SecretHeader, "secret-token")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	select {
+	case update := <-updates:
+		assert.Equal(t, 9, update.UpdateID)
+	case <-time.After(time.Second):
+		t.Fatal("update was not delivered")
+	}
+}
+
+func TestNewValidatesConfig(t *testing.T) {
+	logger := zerolog.New(io.Discard)
+	_, err := New(Config{URL: "https://bot.example.com/hook"}, &fakeAPI{}, &logger)
+	assert.Error(t, err)
+	_, err = New(Config{URL: "/hook", Secret: "secret-token"}, &fakeAPI{}, &logger)
+	assert.Error(t, err)
+}